@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -21,6 +22,8 @@ import (
 	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
 	bValidator "github.com/x-xyz/goapi/base/validator"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	"github.com/x-xyz/goapi/domain/external_listing"
 	"github.com/x-xyz/goapi/domain/order"
 	mmiddleware "github.com/x-xyz/goapi/middleware"
 	"github.com/x-xyz/goapi/service/chain"
@@ -28,10 +31,13 @@ import (
 	chainlink_service "github.com/x-xyz/goapi/service/chainlink"
 	"github.com/x-xyz/goapi/service/coingecko"
 	"github.com/x-xyz/goapi/service/ens"
+	"github.com/x-xyz/goapi/service/envelope"
 	"github.com/x-xyz/goapi/service/hyype"
+	"github.com/x-xyz/goapi/service/marketplace"
 	"github.com/x-xyz/goapi/service/opensea"
 	"github.com/x-xyz/goapi/service/pinata"
 	"github.com/x-xyz/goapi/service/query"
+	"github.com/x-xyz/goapi/service/ratelimit"
 	"github.com/x-xyz/goapi/service/redis"
 	account_delivery "github.com/x-xyz/goapi/stores/account/delivery/http"
 	account_repository "github.com/x-xyz/goapi/stores/account/repository"
@@ -39,6 +45,14 @@ import (
 	airdrop_delivery "github.com/x-xyz/goapi/stores/airdrop/delivery/http"
 	airdrop_repository "github.com/x-xyz/goapi/stores/airdrop/repository"
 	airdrop_usecase "github.com/x-xyz/goapi/stores/airdrop/usecase"
+	alert_delivery "github.com/x-xyz/goapi/stores/alert/delivery/http"
+	alert_notifier "github.com/x-xyz/goapi/stores/alert/notifier"
+	alert_repository "github.com/x-xyz/goapi/stores/alert/repository"
+	alert_usecase "github.com/x-xyz/goapi/stores/alert/usecase"
+	apikey_delivery "github.com/x-xyz/goapi/stores/apikey/delivery/http"
+	apikey_middleware "github.com/x-xyz/goapi/stores/apikey/delivery/http/middleware"
+	apikey_repository "github.com/x-xyz/goapi/stores/apikey/repository"
+	apikey_usecase "github.com/x-xyz/goapi/stores/apikey/usecase"
 	auth_delivery "github.com/x-xyz/goapi/stores/auth/delivery/http"
 	auth_middleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 	auth_usecase "github.com/x-xyz/goapi/stores/auth/usecase"
@@ -50,18 +64,29 @@ import (
 	coll_promotion_delivery "github.com/x-xyz/goapi/stores/collection_promotion/delivery/http"
 	coll_promotion_repository "github.com/x-xyz/goapi/stores/collection_promotion/repository"
 	coll_promotion_usecase "github.com/x-xyz/goapi/stores/collection_promotion/usecase"
+	comment_delivery "github.com/x-xyz/goapi/stores/comment/delivery/http"
+	comment_repository "github.com/x-xyz/goapi/stores/comment/repository"
+	comment_usecase "github.com/x-xyz/goapi/stores/comment/usecase"
 	ens_delivery "github.com/x-xyz/goapi/stores/ens/delivery/http"
 	erc1155Repository "github.com/x-xyz/goapi/stores/erc1155/repository"
 	external_listing_delivery "github.com/x-xyz/goapi/stores/external_listing/delivery/http"
 	external_listing_repository "github.com/x-xyz/goapi/stores/external_listing/repository"
 	external_listing_usecase "github.com/x-xyz/goapi/stores/external_listing/usecase"
 	file_usecase "github.com/x-xyz/goapi/stores/file/usecase"
+	graphql_delivery "github.com/x-xyz/goapi/stores/graphql/delivery/http"
+	graphql_usecase "github.com/x-xyz/goapi/stores/graphql/usecase"
 	hc_delivery "github.com/x-xyz/goapi/stores/healthcheck/delivery/http"
 	hc_repo "github.com/x-xyz/goapi/stores/healthcheck/repository"
 	hc_usecase "github.com/x-xyz/goapi/stores/healthcheck/usecase"
 	ip_delivery "github.com/x-xyz/goapi/stores/ip/delivery/http"
 	ip_repository "github.com/x-xyz/goapi/stores/ip/repository"
 	ip_usecase "github.com/x-xyz/goapi/stores/ip/usecase"
+	lazymint_delivery "github.com/x-xyz/goapi/stores/lazymint/delivery/http"
+	lazymint_repository "github.com/x-xyz/goapi/stores/lazymint/repository"
+	lazymint_usecase "github.com/x-xyz/goapi/stores/lazymint/usecase"
+	moderation_delivery "github.com/x-xyz/goapi/stores/moderation/delivery/http"
+	moderation_repository "github.com/x-xyz/goapi/stores/moderation/repository"
+	moderation_usecase "github.com/x-xyz/goapi/stores/moderation/usecase"
 	moderator_delivery "github.com/x-xyz/goapi/stores/moderator/delivery/http"
 	moderator_repository "github.com/x-xyz/goapi/stores/moderator/repository"
 	moderator_usecase "github.com/x-xyz/goapi/stores/moderator/usecase"
@@ -74,8 +99,11 @@ import (
 	promotion_usecase "github.com/x-xyz/goapi/stores/promotion/usecase"
 	relationship_repository "github.com/x-xyz/goapi/stores/relationship/repository"
 	relationship_usecase "github.com/x-xyz/goapi/stores/relationship/usecase"
+	royalty_usecase "github.com/x-xyz/goapi/stores/royalty/usecase"
 	search_delivery "github.com/x-xyz/goapi/stores/search/delivery/http"
 	search_usecase "github.com/x-xyz/goapi/stores/search/usecase"
+	spam_delivery "github.com/x-xyz/goapi/stores/spam/delivery/http"
+	spam_usecase "github.com/x-xyz/goapi/stores/spam/usecase"
 	statistic_delivery "github.com/x-xyz/goapi/stores/statistic/delivery/http"
 	statistics_repository "github.com/x-xyz/goapi/stores/statistic/repository"
 	statistics_usecase "github.com/x-xyz/goapi/stores/statistic/usecase"
@@ -88,6 +116,8 @@ import (
 	vex_delivery "github.com/x-xyz/goapi/stores/vex/delivery/http"
 	vex_repository "github.com/x-xyz/goapi/stores/vex/repository"
 	vex_usecase "github.com/x-xyz/goapi/stores/vex/usecase"
+	webresource_repository "github.com/x-xyz/goapi/stores/web_resource/repository"
+	webresource_usecase "github.com/x-xyz/goapi/stores/web_resource/usecase"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 
@@ -117,6 +147,11 @@ func init() {
 //	@in							header
 //	@name						Authorization
 //	@description				retrive token from #/auth/post_auth_sign and apply with `bearer {token}`
+//
+//	@securityDefinitions.apikey	PartnerApiKey
+//	@in							header
+//	@name						X-API-Key
+//	@description				partner api key, requests without it are rate limited by ip
 func main() {
 	// init echo
 	e := echo.New()
@@ -170,12 +205,31 @@ func main() {
 	exchanges := viper.Sub("exchanges")
 	keys := exchanges.AllSettings()
 	exchangeCfgs := make(map[domain.ChainId]order.ExchangeCfg)
+	lazyMintContracts := make(map[domain.ChainId]domain.Address)
 	for k := range keys {
 		chainId := domain.ChainId(exchanges.GetInt(fmt.Sprintf("%s.chainId", k)))
 		exchangeAddr := exchanges.GetString(fmt.Sprintf("%s.exchange", k))
+		if lazyMintAddr := exchanges.GetString(fmt.Sprintf("%s.lazyMint", k)); lazyMintAddr != "" {
+			lazyMintContracts[chainId] = domain.Address(lazyMintAddr).ToLower()
+		}
+		fee := order.FeeCfg{
+			ProtocolFeeBps:    exchanges.GetInt64(fmt.Sprintf("%s.fee.protocolBps", k)),
+			FeeDistShareBps:   exchanges.GetInt64(fmt.Sprintf("%s.fee.distShareBps", k)),
+			Recipient:         domain.Address(exchanges.GetString(fmt.Sprintf("%s.fee.recipient", k))).ToLower(),
+			DonationRecipient: domain.Address(exchanges.GetString(fmt.Sprintf("%s.fee.donationRecipient", k))).ToLower(),
+		}
+		// fee quotes would silently drop the protocol fee if it defaults to 0, so they are disabled instead
+		if !exchanges.IsSet(fmt.Sprintf("%s.fee.protocolBps", k)) {
+			context.WithField("exchange", k).Warn("fee.protocolBps not configured, fee quotes disabled")
+			fee.Unset = true
+		}
+		if err := fee.Validate(); err != nil {
+			panic(fmt.Sprintf("exchanges.%s.fee: %s", k, err))
+		}
 		exchangeCfgs[chainId] = order.ExchangeCfg{
 			Address:    domain.Address(exchangeAddr).ToLower(),
 			Strategies: make(map[domain.Address]order.Strategy),
+			Fee:        fee,
 		}
 		strategies := exchanges.GetStringMapString(fmt.Sprintf("%s.strategies", k))
 		for addr, name := range strategies {
@@ -190,12 +244,16 @@ func main() {
 	keys = networks.AllSettings()
 	rpcs := make(map[int32]string)
 	archiveRpcs := make(map[int32]string)
+	royaltyEngines := make(map[domain.ChainId]domain.Address)
 	for k := range keys {
 		chainId := networks.GetInt32(fmt.Sprintf("%s.chainId", k))
 		rpcUrl := networks.GetString(fmt.Sprintf("%s.rpcUrl", k))
 		rpcs[chainId] = rpcUrl
 		archiveRpcUrl := networks.GetString(fmt.Sprintf("%s.archiveRpcUrl", k))
 		archiveRpcs[chainId] = archiveRpcUrl
+		if royaltyEngine := viper.GetString(fmt.Sprintf("contract.%s.royaltyEngine", k)); royaltyEngine != "" {
+			royaltyEngines[domain.ChainId(chainId)] = domain.Address(royaltyEngine).ToLower()
+		}
 	}
 	chainService, err := chain.NewClient(context, &chain.ClientCfg{
 		RpcUrls:        rpcs,
@@ -207,6 +265,9 @@ func main() {
 	erc721Service := contract.NewErc721(chainService)
 	erc1155Service := contract.NewErc1155(chainService)
 	erc1271Service := contract.NewErc1271(chainService)
+	erc2981Service := contract.NewErc2981(chainService)
+	royaltyEngineService := contract.NewRoyaltyEngine(chainService)
+	ownershipService := contract.NewOwnership(chainService)
 	chainlinkService := chainlink_service.New(chainService)
 	coinGecko := coingecko.NewClient(&coingecko.ClientCfg{
 		HttpClient: http.Client{},
@@ -219,13 +280,36 @@ func main() {
 		Timeout:    httpTimeout,
 		Apikey:     openseaApiKey,
 	})
+	// reads metadata of nft avatars
+	ipfsGateway := viper.GetString("ens.ipfsGateway")
+	webResource := webresource_usecase.NewWebResourceUseCase(&webresource_usecase.WebResourceUseCaseCfg{
+		HttpReader:    webresource_repository.NewHttpReaderRepo(http.Client{}, httpTimeout, nil),
+		IpfsReader:    webresource_repository.NewIpfsGatewayReaderRepo(http.Client{}, ipfsGateway, httpTimeout),
+		DataUriReader: webresource_repository.NewDataUriReaderRepo(),
+		ArUriReader:   webresource_repository.NewArReaderRepo(http.Client{}, httpTimeout, nil),
+	})
 	// ens on ethereum
-	ensService := ens.New(rpcs[1], redisCache)
+	ensService := ens.New(rpcs[1], redisCache, webResource, ipfsGateway)
+
+	// master keys encrypting data keys of unlockable contents, retired keys are kept to read contents not rotated yet.
+	// viper lowercases map keys, so key ids are lowercased as well.
+	// e.g. unlockable.masterKeys: {k1: <base64 of 32 random bytes>}, unlockable.currentKeyId: k1
+	// adding and revealing encrypted contents are disabled if no master key is configured.
+	var unlockableKeyring *envelope.Keyring
+	if unlockableMasterKeys := viper.GetStringMapString("unlockable.masterKeys"); len(unlockableMasterKeys) > 0 {
+		unlockableKeyring, err = envelope.NewFromBase64(unlockableMasterKeys, strings.ToLower(viper.GetString("unlockable.currentKeyId")))
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		context.Warn("unlockable.masterKeys not configured, unlockable content encryption disabled")
+	}
 
 	// construct repository, usecase and delivery
 	hcRepo := hc_repo.New(mongoClient, redisCache)
 	nftitemRepo := token_repository.NewNftItem(q, redisCache)
 	unlockableRepo := token_repository.NewUnlockable(q)
+	unlockableLogRepo := token_repository.NewUnlockableAccessLog(q)
 	collectionRepo := collection_repository.NewCollection(q)
 	registrationRepo := collection_repository.NewRegistration(q)
 	accountRepo := account_repository.New(q, redisCache)
@@ -244,19 +328,40 @@ func main() {
 	tradingVolumeRepo := collection_repository.NewTradingVolumeRepo(q)
 	activityRepo := account_repository.NewActivityHistoryRepo(q)
 	vexRepo := vex_repository.NewVexFeeDistributionHistoryRepo(q)
+	if err := account_repository.EnsureFolderSlugIndex(context, q); err != nil {
+		panic(err)
+	}
 	folderRepo := account_repository.NewFolderRepo(q)
 	folderRelationRepo := account_repository.NewFolderNftRelationshipRepo(q)
 	floorPriceHistoryRepo := collection_repository.NewFloorPriceHistoryRepo(q)
+	holderStatsRepo := collection_repository.NewHolderStatsRepo(q)
 	promotionRepo := promotion_repository.NewPromotion(q)
 	collPromotionRepo := coll_promotion_repository.NewCollPromotion(q)
 	listingRecordRepo := airdrop_repository.NewListingRecordRepo(q)
+	airdropRoundRepo := airdrop_repository.NewAirdropRoundRepo(q)
+	rewardDistributionRepo := coll_promotion_repository.NewRewardDistribution(q)
+	rewardLedgerRepo := coll_promotion_repository.NewRewardLedger(q)
 	orderItemRepo := order_repository.NewOrderItemRepo(q)
 	orderRepo := order_repository.NewOrderRepo(q)
 	orderNonceRepo := account_repository.NewOrderNonceRepo(q)
+	linkedWalletRepo := account_repository.NewLinkedWalletRepo(q)
 	externalListingRepo := external_listing_repository.NewExternalListingRepo(q)
 	statisticRepo := statistics_repository.New(q)
 	ipRepo := ip_repository.New(q)
 	twelvefoldRepo := twelvefold_repository.NewTwelvefoldRepo(q)
+	apikeyRepo := apikey_repository.New(q)
+	if err := moderation_repository.EnsureReportIndex(context, q); err != nil {
+		panic(err)
+	}
+	reportRepo := moderation_repository.NewReportRepo(q)
+	auditLogRepo := moderation_repository.NewAuditLogRepo(q)
+	voucherRepo := lazymint_repository.NewVoucherRepo(q)
+	alertRuleRepo := alert_repository.NewRuleRepo(q)
+	commentRepo := comment_repository.NewCommentRepo(q)
+	if err := comment_repository.EnsureReactionIndex(context, q); err != nil {
+		panic(err)
+	}
+	commentReactionRepo := comment_repository.NewReactionRepo(q)
 
 	chainlink := chainlink_usecase.New(chainlinkService, paytokenRepo)
 	priceFormatter := pricefomatter.NewPriceFormatter(&pricefomatter.PriceFormatterCfg{
@@ -273,18 +378,37 @@ func main() {
 		ListingRecordRepo: listingRecordRepo,
 		OpenseaDataRepo:   openseaDataRepo,
 	})
+	search := search_usecase.New(&search_usecase.SearchUseCaseCfg{
+		Mongo:           q,
+		AccountRepo:     accountRepo,
+		CollectionRepo:  collectionRepo,
+		NftitemRepo:     nftitemRepo,
+		ENS:             ensService,
+		RebuildInterval: viper.GetDuration("search.rebuildInterval"),
+	})
+	royalty := royalty_usecase.New(&royalty_usecase.UseCaseCfg{
+		Erc2981:        erc2981Service,
+		RoyaltyEngine:  royaltyEngineService,
+		RoyaltyEngines: royaltyEngines,
+		CollectionRepo: collectionRepo,
+		Redis:          redisCache,
+	})
 	token := token_usecase.New(&token_usecase.TokenUseCaseCfg{
 		LikeRepo:           likeRepo,
 		NftitemRepo:        nftitemRepo,
 		CollectionRepo:     collectionRepo,
 		UnlockableRepo:     unlockableRepo,
+		UnlockableKeyring:  unlockableKeyring,
+		UnlockableLogRepo:  unlockableLogRepo,
 		FileUC:             file,
 		IpfsUri:            viper.GetString("ipfsUri"),
 		ActivityRepo:       activityRepo,
 		FolderRelationRepo: folderRelationRepo,
 		Erc1155HoldingRepo: erc1155HoldingRepo,
 		OrderItemRepo:      orderItemRepo,
-		Redis:              redisCache,
+		SearchIndexer:      search,
+		RoyaltyUC:          royalty,
+		VoucherRepo:        voucherRepo,
 	})
 	collection := collection_usecase.NewCollection(&collection_usecase.CollectionUseCaseCfg{
 		CollectionRepo:        collectionRepo,
@@ -304,6 +428,9 @@ func main() {
 		LikeRepo:              likeRepo,
 		PromotedCollectionsUC: collPromotionUsecase,
 		TokenUC:               token,
+		SearchIndexer:         search,
+		OwnershipContract:     ownershipService,
+		HolderStatsRepo:       holderStatsRepo,
 	})
 	follow := relationship_usecase.NewFollow(followRepo)
 	like := relationship_usecase.NewLike(likeRepo, nftitemRepo)
@@ -323,14 +450,56 @@ func main() {
 		CollectionUC:            collection,
 		ActivityRepo:            activityRepo,
 		FolderUC:                folderUsecase,
+		SearchIndexer:           search,
+		ENS:                     ensService,
+		CollectionLikeUC:        collectionLike,
+		FolderRelationRepo:      folderRelationRepo,
+		Redis:                   redisCache,
+		LinkedWalletRepo:        linkedWalletRepo,
+		Erc1271:                 erc1271Service,
+		LinkWalletMsg:           viper.GetString("auth.linkWalletMsg"),
 	})
 	auth := auth_usecase.New(viper.GetString("auth.jwtSecret"), account)
-	search := search_usecase.New(q)
 	airdrop := airdrop_usecase.NewAirdropUseCase(airdropRepo)
 	proof := airdrop_usecase.NewProofUseCase(proofRepo)
+	listingRecord := airdrop_usecase.NewListingRecordUseCaseImpl(&airdrop_usecase.ListingRecordUseCaseCfg{
+		TokenUC:           token,
+		CollectionUC:      collection,
+		ListingRecordRepo: listingRecordRepo,
+	})
+	listingReward := coll_promotion_usecase.NewListingReward(&coll_promotion_usecase.ListingRewardUseCaseCfg{
+		CollPromotionUC:   collPromotionUsecase,
+		ListingRecordUC:   listingRecord,
+		ListingRecordRepo: listingRecordRepo,
+		DistributionRepo:  rewardDistributionRepo,
+		LedgerRepo:        rewardLedgerRepo,
+		SnapshotInterval:  viper.GetDuration("listingReward.snapshotInterval"),
+	})
+	airdropBuilder := airdrop_usecase.NewAirdropBuilderUseCase(&airdrop_usecase.AirdropBuilderUseCaseCfg{
+		AirdropRepo:     airdropRepo,
+		ProofRepo:       proofRepo,
+		RoundRepo:       airdropRoundRepo,
+		CollPromotionUC: collPromotionUsecase,
+		ListingRewardUC: listingReward,
+	})
 	tradingVolume := collection_usecase.NewTradingVolumeUseCase(tradingVolumeRepo, chainlink)
 	vex := vex_usecase.NewVexFeeDistrubutionHistoryUseCase(vexRepo)
 	orderNonce := account_usecase.NewOrderNonceUseCase(orderNonceRepo)
+	// token rules are evaluated on new orders too, the rest by the tracker
+	alertNotifier := alert_notifier.NewLogNotifier()
+	if alertWebhookUrl := viper.GetString("alert.webhookUrl"); alertWebhookUrl != "" {
+		alertNotifier = alert_notifier.NewWebhookNotifier(&alert_notifier.WebhookNotifierCfg{
+			HttpClient: http.Client{},
+			Timeout:    10 * time.Second,
+			Url:        alertWebhookUrl,
+		})
+	}
+	alert := alert_usecase.New(&alert_usecase.AlertUseCaseCfg{
+		Repo:           alertRuleRepo,
+		CollectionRepo: collectionRepo,
+		OrderItemRepo:  orderItemRepo,
+		Notifier:       alertNotifier,
+	})
 	order := order_usecase.New(&order_usecase.OrderUseCaseCfg{
 		ExchangeCfgs:        exchangeCfgs,
 		OrderRepo:           orderRepo,
@@ -344,32 +513,139 @@ func main() {
 		TokenUC:             token,
 		Erc1271:             erc1271Service,
 		ActivityHistoryRepo: activityRepo,
+		RoyaltyUC:           royalty,
+		ExternalListingRepo: externalListingRepo,
+		AlertUC:             alert,
 	})
-	externalListingUsecase := external_listing_usecase.New(openseaClient, externalListingRepo, priceFormatter)
+	// external marketplaces share rate limits across instances
+	marketplaceLimiter := ratelimit.New(redisCache)
+	marketplaceThrottle := func(source string) marketplace.ThrottleCfg {
+		return marketplace.ThrottleCfg{
+			Limiter:      marketplaceLimiter,
+			Requests:     viper.GetInt(fmt.Sprintf("marketplaces.%s.rateLimit", source)),
+			Window:       viper.GetDuration(fmt.Sprintf("marketplaces.%s.rateWindow", source)),
+			BackoffStart: viper.GetDuration(fmt.Sprintf("marketplaces.%s.backoffStart", source)),
+			BackoffLimit: viper.GetDuration(fmt.Sprintf("marketplaces.%s.backoffLimit", source)),
+		}
+	}
+	marketplaces := []external_listing.Marketplace{
+		marketplace.NewOpensea(&marketplace.OpenseaCfg{
+			Client:         openseaClient,
+			PriceFormatter: priceFormatter,
+			Throttle:       marketplaceThrottle("opensea"),
+		}),
+	}
+	if viper.GetBool("marketplaces.looksrare.enabled") {
+		marketplaces = append(marketplaces, marketplace.NewLooksRare(&marketplace.LooksRareCfg{
+			HttpClient:     http.Client{},
+			Timeout:        httpTimeout,
+			Apikey:         viper.GetString("marketplaces.looksrare.apikey"),
+			PriceFormatter: priceFormatter,
+			Throttle:       marketplaceThrottle("looksrare"),
+		}))
+	}
+	if viper.GetBool("marketplaces.x2y2.enabled") {
+		marketplaces = append(marketplaces, marketplace.NewX2Y2(&marketplace.X2Y2Cfg{
+			HttpClient:     http.Client{},
+			Timeout:        httpTimeout,
+			Apikey:         viper.GetString("marketplaces.x2y2.apikey"),
+			PriceFormatter: priceFormatter,
+			Throttle:       marketplaceThrottle("x2y2"),
+		}))
+	}
+	externalListingUsecase := external_listing_usecase.New(marketplaces, externalListingRepo)
 	statisticUsecase := statistics_usecase.New(statisticRepo)
 	ipUseCase := ip_usecase.New(ipRepo, nftitemRepo)
 	twelvefoldUseCase := twelvefold_usecase.NewTwelvefoldUseCase(twelvefoldRepo)
+	graphqlUseCase := graphql_usecase.New(&graphql_usecase.GraphqlUseCaseCfg{
+		AccountUC:      account,
+		FolderUC:       folderUsecase,
+		CollectionUC:   collection,
+		TokenUC:        token,
+		OrderUC:        order,
+		AccountRepo:    accountRepo,
+		CollectionRepo: collectionRepo,
+		NftitemRepo:    nftitemRepo,
+		ActivityRepo:   activityRepo,
+		RelationRepo:   folderRelationRepo,
+		MaxDepth:       viper.GetInt("graphql.maxDepth"),
+		MaxCost:        viper.GetInt("graphql.maxCost"),
+	})
+	apikeyUseCase := apikey_usecase.New(&apikey_usecase.ApiKeyUseCaseCfg{
+		Repo:              apikeyRepo,
+		Redis:             redisCache,
+		DefaultRateLimit:  viper.GetInt("apikey.defaultRateLimit"),
+		DefaultDailyQuota: viper.GetInt("apikey.defaultDailyQuota"),
+	})
+
+	moderation := moderation_usecase.New(&moderation_usecase.UseCaseCfg{
+		ReportRepo:   reportRepo,
+		AuditLogRepo: auditLogRepo,
+		AccountUC:    account,
+		CollectionUC: collection,
+		TokenUC:      token,
+	})
+
+	lazymint := lazymint_usecase.New(&lazymint_usecase.UseCaseCfg{
+		Contracts:      lazyMintContracts,
+		VoucherRepo:    voucherRepo,
+		NftitemRepo:    nftitemRepo,
+		PaytokenRepo:   paytokenRepo,
+		PriceFormatter: priceFormatter,
+		Erc1271:        erc1271Service,
+	})
+
+	comment := comment_usecase.New(&comment_usecase.CommentUseCaseCfg{
+		Repo:               commentRepo,
+		ReactionRepo:       commentReactionRepo,
+		AuditLogRepo:       auditLogRepo,
+		AccountRepo:        accountRepo,
+		CollectionRepo:     collectionRepo,
+		NftitemRepo:        nftitemRepo,
+		Erc1155HoldingRepo: erc1155HoldingRepo,
+		Limiter:            ratelimit.New(redisCache),
+		PostRateLimit:      viper.GetInt("comment.postRateLimit"),
+		PostRateWindow:     viper.GetDuration("comment.postRateWindow"),
+	})
+
+	// moderators only override spam classification, signals are found by the spam classifier of the nft indexer
+	spamUseCase := spam_usecase.New(&spam_usecase.UseCaseCfg{
+		CollectionRepo:      collectionRepo,
+		RegistrationRepo:    registrationRepo,
+		NftitemRepo:         nftitemRepo,
+		ActivityHistoryRepo: activityRepo,
+	})
+
+	rateLimitMiddleware := apikey_middleware.New(apikeyUseCase, ratelimit.New(redisCache), viper.GetInt("apikey.ipRateLimit"))
+	e.Use(rateLimitMiddleware.RateLimit())
 
 	adminAddresses := viper.GetStringSlice("admin.addresses")
 	auth_middleware := auth_middleware.New(auth, moderator, adminAddresses)
 
 	hc_delivery.New(e, hc)
 	auth_delivery.New(e, auth, viper.GetString("auth.signatureMsg"))
-	account_delivery.New(e, account, like, folderUsecase, collection, auth_middleware, orderNonce)
-	token_delivery.New(e, token, like, account, folderUsecase, order, auth_middleware, hyypeClient)
-	collection_delivery.New(e, account, collection, auth_middleware, collectionLike, tradingVolume)
+	account_delivery.New(e, account, like, folderUsecase, collection, auth_middleware, orderNonce, moderation)
+	token_delivery.New(e, token, like, account, folderUsecase, order, auth_middleware, hyypeClient, moderation)
+	collection_delivery.New(e, account, collection, auth_middleware, collectionLike, tradingVolume, moderation)
 	moderator_delivery.New(e, moderator, account, auth_middleware)
 	search_delivery.New(e, search)
-	airdrop_delivery.New(e, airdrop, proof)
+	airdrop_delivery.New(e, airdrop, proof, airdropBuilder, auth_middleware)
 	vex_delivery.New(e, vex)
 	promotion_delivery.New(e, promotionUsecase)
-	coll_promotion_delivery.New(e, collPromotionUsecase, collection)
+	coll_promotion_delivery.New(e, collPromotionUsecase, collection, listingReward, auth_middleware)
 	coin_delivery.New(e, coinGecko)
 	external_listing_delivery.New(e, externalListingUsecase, collection, cacheDuration)
 	statistic_delivery.New(e, statisticUsecase)
 	ip_delivery.New(e, ipUseCase, account, auth_middleware)
 	ens_delivery.New(e, ensService)
 	twelvefold_delivery.New(e, twelvefoldUseCase)
+	graphql_delivery.New(e, graphqlUseCase, auth_middleware)
+	apikey_delivery.New(e, apikeyUseCase, auth_middleware)
+	moderation_delivery.New(e, moderation, auth_middleware)
+	lazymint_delivery.New(e, lazymint, auth_middleware)
+	alert_delivery.New(e, alert, auth_middleware)
+	comment_delivery.New(e, comment, auth_middleware)
+	spam_delivery.New(e, spamUseCase, auth_middleware)
 
 	e.GET("/check", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]interface{}{
@@ -379,6 +655,34 @@ func main() {
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// encrypts unlockable contents added before encryption, it's a no-op once all contents are encrypted
+	if unlockableKeyring != nil {
+		go func() {
+			if count, err := token.EncryptPlaintextUnlockables(context); err != nil {
+				context.WithField("err", err).Error("token.EncryptPlaintextUnlockables failed")
+			} else if count > 0 {
+				context.WithField("count", count).Info("encrypted plaintext unlockable contents")
+			}
+		}()
+	}
+
+	searchIndexSyncer := search_usecase.NewIndexSyncer(&search_usecase.IndexSyncerCfg{
+		Search:   search,
+		Interval: viper.GetDuration("search.syncInterval"),
+	})
+	searchIndexSyncer.Start(context)
+
+	// snapshot times differ between instances in random mode, only one instance should enable it
+	if viper.GetBool("listingReward.enabled") {
+		rewardScheduler := coll_promotion_usecase.NewRewardScheduler(&coll_promotion_usecase.RewardSchedulerCfg{
+			ListingReward: listingReward,
+			Mode:          collection_promotion.SnapshotMode(viper.GetString("listingReward.snapshotMode")),
+			Interval:      viper.GetDuration("listingReward.snapshotInterval"),
+			Period:        viper.GetDuration("listingReward.distributionPeriod"),
+		})
+		rewardScheduler.Start(context)
+	}
+
 	go func() {
 		if err := e.Start(viper.GetString("server.address")); err != nil && err != http.ErrServerClosed {
 			log.Log().WithField("err", err).Error("shutting down the server")
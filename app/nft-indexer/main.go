@@ -19,6 +19,7 @@ import (
 	"github.com/x-xyz/goapi/domain/nftitem"
 	mmiddleware "github.com/x-xyz/goapi/middleware"
 	"github.com/x-xyz/goapi/service/chain"
+	"github.com/x-xyz/goapi/service/chain/contract"
 	chainlink_service "github.com/x-xyz/goapi/service/chainlink"
 	"github.com/x-xyz/goapi/service/opensea"
 	"github.com/x-xyz/goapi/service/query"
@@ -34,8 +35,12 @@ import (
 	openseadata_usecase "github.com/x-xyz/goapi/stores/openseadata/usecase"
 	order_repository "github.com/x-xyz/goapi/stores/order/repository"
 	paytoken_repository "github.com/x-xyz/goapi/stores/paytoken/repository"
+	reconciliation_repository "github.com/x-xyz/goapi/stores/reconciliation/repository"
+	reconciliation_usecase "github.com/x-xyz/goapi/stores/reconciliation/usecase"
+	spam_usecase "github.com/x-xyz/goapi/stores/spam/usecase"
 	token_repository "github.com/x-xyz/goapi/stores/token/repository"
 	token_usecase "github.com/x-xyz/goapi/stores/token/usecase"
+	trackerstate_repository "github.com/x-xyz/goapi/stores/tracker_state/repository/mongo"
 	webresource_repository "github.com/x-xyz/goapi/stores/web_resource/repository"
 	webresource_usecase "github.com/x-xyz/goapi/stores/web_resource/usecase"
 )
@@ -83,6 +88,13 @@ func main() {
 	osEventIndexerBackoffLimitD := viper.GetDuration("openseaEventIndexer.backoffLimitDuration")
 	osEventIndexerApikey := viper.GetString("openseaEventIndexer.apikey")
 	thumborUrl := viper.GetString("thumbor.url")
+	spamClassifierEnable := viper.GetBool("spamClassifier.enable")
+	spamClassifierInterval := viper.GetDuration("spamClassifier.interval")
+	reconcilerEnable := viper.GetBool("reconciler.enable")
+	reconcilerInterval := viper.GetDuration("reconciler.interval")
+	reconcilerSampleSize := viper.GetInt("reconciler.sampleSize")
+	holderStatsEnable := viper.GetBool("holderStats.enable")
+	holderStatsInterval := viper.GetDuration("holderStats.interval")
 
 	ctx.WithFields(log.Fields{
 		"ipfs.api":              ipfsApiUrl,
@@ -154,12 +166,17 @@ func main() {
 	}
 	nftitemRepo := token_repository.NewNftItem(q, nil)
 	collectionRepo := collection_reposiroty.NewCollection(q)
+	registrationRepo := collection_reposiroty.NewRegistration(q)
 	erc1155HoldingRepo := erc1155_repository.NewHoldingRepo(q)
 	openseaDataRepo := openseadata_repository.NewOpenseaDataRepo(q)
 	floorPriceHistoryRepo := collection_reposiroty.NewFloorPriceHistoryRepo(q)
+	holderStatsRepo := collection_reposiroty.NewHolderStatsRepo(q)
 	paytokenRepo := paytoken_repository.NewPayTokenRepo(q)
 	orderItemRepo := order_repository.NewOrderItemRepo(q)
 	apecoinStakingRepo := apecoinstakingRepo.New(q)
+	activityHistoryRepo := account_repository.NewActivityHistoryRepo(q)
+	driftRepo := reconciliation_repository.NewDriftRepo(q)
+	trackerStateRepo := trackerstate_repository.NewTrackerStateMongoRepo(q)
 
 	// usecases
 	webResourceUseCase := webresource_usecase.NewWebResourceUseCase(&webresource_usecase.WebResourceUseCaseCfg{
@@ -171,7 +188,8 @@ func main() {
 	})
 	chainlink := chainlink_usecase.New(chainlinkService, paytokenRepo)
 	tokenUseCase := token_usecase.New(&token_usecase.TokenUseCaseCfg{
-		NftitemRepo: nftitemRepo,
+		NftitemRepo:   nftitemRepo,
+		OrderItemRepo: orderItemRepo,
 	})
 	collectionUseCase := collection_usecase.NewCollection(&collection_usecase.CollectionUseCaseCfg{
 		CollectionRepo:        collectionRepo,
@@ -180,11 +198,29 @@ func main() {
 		ChainlinkUC:           chainlink,
 		FloorPriceHistoryRepo: floorPriceHistoryRepo,
 		OrderItemRepo:         orderItemRepo,
+		ActivityHistoryRepo:   activityHistoryRepo,
+		HolderStatsRepo:       holderStatsRepo,
 	})
 	openseaDataUseCase := openseadata_usecase.NewOpenseaUseCase(openseaDataRepo)
-	activityHistoryRepo := account_repository.NewActivityHistoryRepo(q)
 	activityHistoryUseCase := account_usecase.NewActivityHistoryUsecase(activityHistoryRepo)
 	apecoinStakingUseCase := apecoinstakingUseCase.New(apecoinStakingRepo)
+	spamUseCase := spam_usecase.New(&spam_usecase.UseCaseCfg{
+		CollectionRepo:      collectionRepo,
+		RegistrationRepo:    registrationRepo,
+		NftitemRepo:         nftitemRepo,
+		ActivityHistoryRepo: activityHistoryRepo,
+		WebResourceUC:       webResourceUseCase,
+		Erc721Service:       contract.NewErc721(chainService),
+	})
+	reconciliationUseCase := reconciliation_usecase.New(&reconciliation_usecase.UseCaseCfg{
+		NftitemRepo:        nftitemRepo,
+		Erc1155HoldingRepo: erc1155HoldingRepo,
+		DriftRepo:          driftRepo,
+		TrackerStateRepo:   trackerStateRepo,
+		Erc721Service:      contract.NewErc721(chainService),
+		Erc1155Service:     contract.NewErc1155(chainService),
+		TokenUseCase:       tokenUseCase,
+	})
 
 	indexerStates := []nftitem.IndexerState{
 		nftitem.IndexerStateHasTokenURI,
@@ -224,6 +260,28 @@ func main() {
 		SetInterval(indexerStatInterval)
 	statUpdater.Start(ctx)
 
+	spamClassifier := nft_indexer.
+		NewSpamClassifier(spamUseCase, collectionUseCase, registrationRepo, errCh).
+		SetInterval(spamClassifierInterval)
+	if spamClassifierEnable {
+		spamClassifier.Start(ctx)
+	}
+
+	holderStatsUpdater := nft_indexer.
+		NewHolderStatsUpdater(collectionUseCase, errCh).
+		SetInterval(holderStatsInterval)
+	if holderStatsEnable {
+		holderStatsUpdater.Start(ctx)
+	}
+
+	reconciler := nft_indexer.
+		NewReconciler(reconciliationUseCase, collectionUseCase, errCh).
+		SetInterval(reconcilerInterval).
+		SetSampleSize(reconcilerSampleSize)
+	if reconcilerEnable {
+		reconciler.Start(ctx)
+	}
+
 	osIndexer := nft_indexer.NewOpenseaDataIndexer(&nft_indexer.OpenseaDataIndexerCfg{
 		Collection:    collectionUseCase,
 		OpenseaData:   openseaDataUseCase,
@@ -263,6 +321,12 @@ func main() {
 	}
 
 	statUpdater.Wait()
+	if holderStatsEnable {
+		holderStatsUpdater.Wait()
+	}
+	if reconcilerEnable {
+		reconciler.Wait()
+	}
 	if osIndexerEnable {
 		osIndexer.Wait()
 	}
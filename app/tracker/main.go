@@ -30,6 +30,9 @@ import (
 	"github.com/x-xyz/goapi/service/query"
 	accountRepo "github.com/x-xyz/goapi/stores/account/repository"
 	accountUsecase "github.com/x-xyz/goapi/stores/account/usecase"
+	alertNotifier "github.com/x-xyz/goapi/stores/alert/notifier"
+	alertRepo "github.com/x-xyz/goapi/stores/alert/repository"
+	alertUseCase "github.com/x-xyz/goapi/stores/alert/usecase"
 
 	cRepo "github.com/x-xyz/goapi/stores/chain/repository"
 	cUseCase "github.com/x-xyz/goapi/stores/chain/usecase"
@@ -44,9 +47,12 @@ import (
 	erc1155UseCase "github.com/x-xyz/goapi/stores/erc1155/usecase"
 	e7UseCase "github.com/x-xyz/goapi/stores/erc721/usecase"
 	exchangeUseCase "github.com/x-xyz/goapi/stores/exchange/usecase"
+	lazymintRepo "github.com/x-xyz/goapi/stores/lazymint/repository"
+	nftdetectorUseCase "github.com/x-xyz/goapi/stores/nftdetector/usecase"
 	order_repo "github.com/x-xyz/goapi/stores/order/repository"
 	ptRepo "github.com/x-xyz/goapi/stores/paytoken/repository"
 	punkUseCase "github.com/x-xyz/goapi/stores/punk/usecase"
+	spamUseCase "github.com/x-xyz/goapi/stores/spam/usecase"
 
 	"github.com/x-xyz/goapi/stores/token/repository"
 	tokenUseCase "github.com/x-xyz/goapi/stores/token/usecase"
@@ -78,6 +84,7 @@ func main() {
 
 	ctxTimeout := viper.GetDuration("context.timeout")
 	checkNewContractInterval := viper.GetDuration("tracker.checkNewContractInterval")
+	discoveryEnabled := viper.GetBool("tracker.discovery.enabled")
 	followDistance := viper.GetUint64("tracker.followDistance")
 	activeNetwork := viper.GetString("activeNetwork")
 	networkInfo := viper.Sub(fmt.Sprintf("networks.%s", activeNetwork))
@@ -99,6 +106,7 @@ func main() {
 	apecoinStakingContract := "0x5954ab967bc958940b7eb73ee84797dc8a2afbb9"
 	royaltyEngineContrct := contractInfo.GetString("royaltyEngine")
 	priceUpdaterInterval := viper.GetDuration("priceUpdater.interval")
+	alertWebhookUrl := viper.GetString("alert.webhookUrl")
 
 	ctx.WithFields(log.Fields{
 		"network":          activeNetwork,
@@ -163,12 +171,15 @@ func main() {
 	orderItemRepo := order_repo.NewOrderItemRepo(q)
 	orderNonceRepo := accountRepo.NewOrderNonceRepo(q)
 	apecoinStakingRepo := apecoinstakingRepo.New(q)
+	voucherRepo := lazymintRepo.NewVoucherRepo(q)
+	registrationRepo := colRepo.NewRegistration(q)
 
 	// usecases
 	tokenUC := tokenUseCase.New(&tokenUseCase.TokenUseCaseCfg{
 		NftitemRepo:    nftitemRepo,
 		CollectionRepo: collectionRepo,
 		OrderItemRepo:  orderItemRepo,
+		VoucherRepo:    voucherRepo,
 	})
 	chainlinkUC := chainlinkUseCase.New(chainlinkService, paytokenRepo)
 	tradingVolumeUC := colUseCase.NewTradingVolumeUseCase(tradingVolumeRepo, chainlinkUC)
@@ -228,6 +239,7 @@ func main() {
 		ActivityHistoryRepo:       activityHistoryRepo,
 		FolderUsecase:             folderUsecase,
 		OrderUseCase:              order,
+		VoucherRepo:               voucherRepo,
 	})
 	erc1155UC := erc1155UseCase.NewErc1155UseCase(erc1155ContractRepo)
 	erc1155EventUseCase := erc1155UseCase.NewErc1155EventUseCase(&erc1155UseCase.Erc1155EventUseCaseCfg{
@@ -245,11 +257,42 @@ func main() {
 		CollectionRepo: collectionRepo,
 	})
 	apecoinStakingUC := apecoinstakingUseCase.New(apecoinStakingRepo)
+	nftDetectorUC := nftdetectorUseCase.NewNFTDetectorUseCase(&nftdetectorUseCase.NFTDetectorCfg{
+		Erc721:          erc721Repo,
+		Erc721Service:   serviceContract.NewErc721(chainService),
+		Erc1155:         erc1155ContractRepo,
+		Erc1155Service:  serviceContract.NewErc1155(chainService),
+		MetadataService: serviceContract.NewNftMetadata(chainService),
+		Registration:    registrationRepo,
+		// images and transfers of discovered contracts are checked by the spam classifier of the nft indexer
+		Spam: spamUseCase.New(&spamUseCase.UseCaseCfg{
+			CollectionRepo:      collectionRepo,
+			RegistrationRepo:    registrationRepo,
+			NftitemRepo:         nftitemRepo,
+			ActivityHistoryRepo: activityHistoryRepo,
+		}),
+	})
+
+	notifier := alertNotifier.NewLogNotifier()
+	if alertWebhookUrl != "" {
+		notifier = alertNotifier.NewWebhookNotifier(&alertNotifier.WebhookNotifierCfg{
+			HttpClient: http.Client{},
+			Timeout:    10 * time.Second,
+			Url:        alertWebhookUrl,
+		})
+	}
+	alertUC := alertUseCase.New(&alertUseCase.AlertUseCaseCfg{
+		Repo:           alertRepo.NewRuleRepo(q),
+		CollectionRepo: collectionRepo,
+		OrderItemRepo:  orderItemRepo,
+		Notifier:       notifier,
+	})
 
 	// handlers
 	exchangeHandler := tracker.NewExchangeEventHandler(&tracker.ExchangeEventHandlerCfg{
 		ChainId:         chainId,
 		ExchangeUseCase: exchangeUC,
+		Alert:           alertUC,
 	})
 	erc721Handler := tracker.NewErc721EventHandler(&tracker.Erc721EventHandlerCfg{
 		ChainId:            chainId,
@@ -277,6 +320,10 @@ func main() {
 		ApecoinStakingContract: serviceContract.NewApecoinStaking(chainService, int32(chainId), common.HexToAddress(apecoinStakingContract)),
 		TokenUC:                tokenUC,
 	})
+	nftDetectHandler := tracker.NewNFTDetectHandler(&tracker.NFTDetectHandlerCfg{
+		ChainId:          chainId,
+		NFTDetectUseCase: nftDetectorUC,
+	})
 
 	currentBlockGetter := tracker.NewCurrentBlockGetter(&tracker.CurrentBlockGetterCfg{
 		Client: wsClient,
@@ -353,6 +400,33 @@ func main() {
 		trackers = append(trackers, apecoinStakingTracker)
 	}
 
+	// discovery tracker watches transfers of all addresses for new nft contracts,
+	// they are tracked by the new contract check below once accepted by moderators
+	if discoveryEnabled {
+		discoveryTracker, err := tracker.NewEventTracker(&tracker.EventTrackerCfg{
+			ChainId:             chainId,
+			BlockTime:           blockTime,
+			CurrentBlockGetter:  currentBlockGetter,
+			Mongo:               q,
+			WsClient:            _clientProvider.consume(ctx),
+			RpcClient:           throttledClient,
+			ClientWithArchive:   archiveEthClient,
+			TrackerStateUseCase: tsUseCase,
+			TrackerTag:          "discovery",
+			ShouldDecodeSender:  false,
+			FollowDistance:      followDistance,
+			BlockUseCase:        blockUseCase,
+			ContractAddress:     common.Address{},
+			EventHandl:          nftDetectHandler,
+			ErrorCh:             errCh,
+			SkipMissingBlock:    true,
+		})
+		if err != nil {
+			ctx.WithField("err", err).Panic("new discovery tracker failed")
+		}
+		trackers = append(trackers, discoveryTracker)
+	}
+
 	// token trackers
 	trackingTokens := make(map[domain.Address]struct{})
 	tokens, err := erc721UseCase.FindAll(ctx, contract.WithChainId(domain.ChainId(chainId)), contract.WithIsAppropriate(true))
@@ -450,6 +524,7 @@ func main() {
 		Order:          order,
 		Interval:       priceUpdaterInterval,
 		PriceFormatter: priceFormatter,
+		Alert:          alertUC,
 		ErrorCh:        errCh,
 	})
 	metadataRefreshingIndexer := nft_indexer.NewMetadataUpdater(&nft_indexer.MetadataUpdaterCfg{
@@ -0,0 +1,51 @@
+package abi
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var ERC2981ABI abi.ABI
+
+func init() {
+	_abi, err := abi.JSON(strings.NewReader(erc2981ABIJson))
+	if err != nil {
+		panic("Failed to parse ABI")
+	}
+	ERC2981ABI = _abi
+}
+
+var erc2981ABIJson = `
+[
+  {
+    "inputs": [
+      {
+        "internalType": "uint256",
+        "name": "tokenId",
+        "type": "uint256"
+      },
+      {
+        "internalType": "uint256",
+        "name": "salePrice",
+        "type": "uint256"
+      }
+    ],
+    "name": "royaltyInfo",
+    "outputs": [
+      {
+        "internalType": "address",
+        "name": "receiver",
+        "type": "address"
+      },
+      {
+        "internalType": "uint256",
+        "name": "royaltyAmount",
+        "type": "uint256"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]
+`
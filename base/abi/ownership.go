@@ -0,0 +1,60 @@
+package abi
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// OwnershipABI has owner() of Ownable and hasRole() of AccessControl
+var OwnershipABI abi.ABI
+
+func init() {
+	_abi, err := abi.JSON(strings.NewReader(ownershipABIJson))
+	if err != nil {
+		panic("Failed to parse ABI")
+	}
+	OwnershipABI = _abi
+}
+
+var ownershipABIJson = `
+[
+  {
+    "inputs": [],
+    "name": "owner",
+    "outputs": [
+      {
+        "internalType": "address",
+        "name": "",
+        "type": "address"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "bytes32",
+        "name": "role",
+        "type": "bytes32"
+      },
+      {
+        "internalType": "address",
+        "name": "account",
+        "type": "address"
+      }
+    ],
+    "name": "hasRole",
+    "outputs": [
+      {
+        "internalType": "bool",
+        "name": "",
+        "type": "bool"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]
+`
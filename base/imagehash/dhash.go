@@ -0,0 +1,87 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+)
+
+const (
+	width  = 9
+	height = 8
+)
+
+// DHash is the difference hash of an image, it compares brightness of adjacent pixels of the image
+// scaled down to 9x8, so resized or re-encoded copies of an image have close hashes
+func DHash(img image.Image) uint64 {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+
+	var gray [height][width]float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			gray[y][x] = averageLuma(img, cell(bounds, x, y))
+		}
+	}
+
+	hash := uint64(0)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// DHashBytes decodes a png, jpeg or gif image and returns its difference hash
+func DHashBytes(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	return DHash(img), nil
+}
+
+// Distance is the number of different bits of two hashes, images are similar if it's small
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// cell returns the area of the image scaled to the pixel (x, y), it's at least 1x1
+func cell(bounds image.Rectangle, x, y int) image.Rectangle {
+	dx, dy := bounds.Dx(), bounds.Dy()
+	x0 := bounds.Min.X + x*dx/width
+	y0 := bounds.Min.Y + y*dy/height
+	x1 := bounds.Min.X + (x+1)*dx/width
+	y1 := bounds.Min.Y + (y+1)*dy/height
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+	return image.Rect(x0, y0, x1, y1).Intersect(bounds)
+}
+
+func averageLuma(img image.Image, r image.Rectangle) float64 {
+	sum, n := 0.0, 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(cr) + 0.587*float64(cg) + 0.114*float64(cb)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
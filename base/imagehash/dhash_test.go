@@ -0,0 +1,48 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func gradient(w, h int, reversed bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / w)
+			if reversed {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, uint8(y * 255 / h), v, 255})
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	req := require.New(t)
+
+	small := DHash(gradient(90, 80, false))
+	large := DHash(gradient(450, 400, false))
+	reversed := DHash(gradient(90, 80, true))
+
+	req.LessOrEqual(Distance(small, large), 2)
+	req.Greater(Distance(small, reversed), 32)
+
+	buf := &bytes.Buffer{}
+	req.NoError(png.Encode(buf, gradient(90, 80, false)))
+	decoded, err := DHashBytes(buf.Bytes())
+	req.NoError(err)
+	req.Equal(small, decoded)
+
+	_, err = DHashBytes([]byte("not an image"))
+	req.Error(err)
+
+	req.Equal(uint64(0), DHash(image.NewRGBA(image.Rect(0, 0, 0, 0))))
+	req.NotPanics(func() { DHash(gradient(3, 2, false)) })
+}
@@ -0,0 +1,91 @@
+package nft_indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/collection"
+)
+
+// HolderStatsUpdater precomputes holder distribution, concentration and churn of collections
+type HolderStatsUpdater struct {
+	collection collection.Usecase
+	// minInterval between every rounds
+	interval  time.Duration
+	errorCh   chan error
+	stoppedCh chan interface{}
+}
+
+func NewHolderStatsUpdater(collectionUsecase collection.Usecase, errCh chan error) *HolderStatsUpdater {
+	return &HolderStatsUpdater{
+		collection: collectionUsecase,
+		errorCh:    errCh,
+		stoppedCh:  make(chan interface{}),
+	}
+}
+
+func (im *HolderStatsUpdater) SetInterval(interval time.Duration) *HolderStatsUpdater {
+	im.interval = interval
+	return im
+}
+
+func (im *HolderStatsUpdater) Start(ctx ctx.Ctx) {
+	go im.loop(ctx)
+}
+
+func (im *HolderStatsUpdater) loop(ctx ctx.Ctx) {
+	errAndStop := func(err error) {
+		im.errorCh <- err
+		close(im.stoppedCh)
+	}
+
+	nextTick := time.Second * 0
+	limit := int32(100)
+	offset := int32(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(im.stoppedCh)
+			return
+		case <-time.After(nextTick):
+			cols, err := im.collection.FindAll(ctx, collection.WithPagination(offset, limit))
+			if err != nil {
+				ctx.WithFields(log.Fields{
+					"offset": offset,
+					"limit":  limit,
+					"err":    err,
+				}).Error("im.collection.FindAll failed")
+				errAndStop(err)
+				return
+			}
+
+			ctx.Info(fmt.Sprintf("holder stats update progress: %d", offset))
+
+			for _, col := range cols.Items {
+				// a failed collection is updated again in the next round
+				if err := im.collection.RefreshHolderStats(ctx, col.ToId()); err != nil {
+					ctx.WithFields(log.Fields{
+						"chainId": col.ChainId,
+						"address": col.Erc721Address,
+						"err":     err,
+					}).Error("im.collection.RefreshHolderStats failed")
+				}
+			}
+
+			if len(cols.Items) < int(limit) {
+				nextTick = im.interval
+				offset = 0
+			} else {
+				nextTick = time.Second * 0
+				offset += limit
+			}
+		}
+	}
+}
+
+func (im *HolderStatsUpdater) Wait() {
+	<-im.stoppedCh
+}
@@ -412,7 +412,7 @@ func (i *NftIndexer) storeAttributes(ctx bCtx.Ctx, item *nftitem.NftItem) error
 		return i.increaseRetryCount(ctx, item)
 	}
 	patchable := &nftitem.PatchableNftItem{
-		Attributes:        attrs,
+		Attributes:        nftitem.NormalizeAttributes(attrs),
 		IndexerState:      (*nftitem.IndexerState)(ptr.String(nftitem.IndexerStateFetchingAnimation)),
 		IndexerRetryCount: ptr.Int32(0),
 	}
@@ -0,0 +1,126 @@
+package nft_indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/metrics"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/reconciliation"
+)
+
+// Reconciler compares stored owners and balances of collections with the chain, and fixes those
+// left wrong by missed logs
+type Reconciler struct {
+	reconciliation reconciliation.UseCase
+	collection     collection.Usecase
+	// sampleSize of tokens checked in every collection, all tokens are checked if it's not positive
+	sampleSize int
+	// minInterval between every rounds
+	interval  time.Duration
+	errorCh   chan error
+	stoppedCh chan interface{}
+}
+
+func NewReconciler(reconciliationUseCase reconciliation.UseCase, collectionUsecase collection.Usecase, errCh chan error) *Reconciler {
+	metOnce.Do(func() {
+		met = metrics.New("indexer")
+	})
+	return &Reconciler{
+		reconciliation: reconciliationUseCase,
+		collection:     collectionUsecase,
+		errorCh:        errCh,
+		stoppedCh:      make(chan interface{}),
+	}
+}
+
+func (im *Reconciler) SetInterval(interval time.Duration) *Reconciler {
+	im.interval = interval
+	return im
+}
+
+func (im *Reconciler) SetSampleSize(sampleSize int) *Reconciler {
+	im.sampleSize = sampleSize
+	return im
+}
+
+func (im *Reconciler) Start(ctx ctx.Ctx) {
+	go im.loop(ctx)
+}
+
+func (im *Reconciler) loop(ctx ctx.Ctx) {
+	errAndStop := func(err error) {
+		im.errorCh <- err
+		close(im.stoppedCh)
+	}
+
+	nextTick := time.Second * 0
+	limit := int32(100)
+	offset := int32(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(im.stoppedCh)
+			return
+		case <-time.After(nextTick):
+			cols, err := im.collection.FindAll(ctx, collection.WithPagination(offset, limit))
+			if err != nil {
+				ctx.WithFields(log.Fields{
+					"offset": offset,
+					"limit":  limit,
+					"err":    err,
+				}).Error("im.collection.FindAll failed")
+				errAndStop(err)
+				return
+			}
+
+			ctx.Info(fmt.Sprintf("reconcile progress: %d", offset))
+
+			for _, col := range cols.Items {
+				// a failed collection is reconciled again in the next round
+				report, err := im.reconciliation.Reconcile(ctx, col.ChainId, col.Erc721Address, col.TokenType, im.sampleSize)
+				if err != nil {
+					ctx.WithFields(log.Fields{
+						"chainId": col.ChainId,
+						"address": col.Erc721Address,
+						"err":     err,
+					}).Error("im.reconciliation.Reconcile failed")
+					continue
+				}
+
+				tags := []string{"chainId", fmt.Sprint(report.ChainId), "contract", report.ContractAddress.ToLowerStr()}
+				met.BumpSum("reconcile.checked", float64(report.Checked), tags...)
+				met.BumpSum("reconcile.drifted", float64(report.Drifted), tags...)
+				met.BumpSum("reconcile.fixed", float64(report.Fixed), tags...)
+				met.BumpSum("reconcile.skipped", float64(report.Skipped), tags...)
+				if report.Checked > 0 {
+					met.BumpAvg("reconcile.driftRate", float64(report.Drifted)/float64(report.Checked), tags...)
+				}
+				if report.Drifted > 0 {
+					ctx.WithFields(log.Fields{
+						"chainId": report.ChainId,
+						"address": report.ContractAddress,
+						"checked": report.Checked,
+						"drifted": report.Drifted,
+						"fixed":   report.Fixed,
+					}).Warn("drift found")
+				}
+			}
+
+			if len(cols.Items) < int(limit) {
+				nextTick = im.interval
+				offset = 0
+			} else {
+				nextTick = time.Second * 0
+				offset += limit
+			}
+		}
+	}
+}
+
+func (im *Reconciler) Wait() {
+	<-im.stoppedCh
+}
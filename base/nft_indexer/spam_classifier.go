@@ -0,0 +1,123 @@
+package nft_indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/spam"
+)
+
+// SpamClassifier refreshes spam scores of collections and of contracts found by contract discovery
+type SpamClassifier struct {
+	spam         spam.UseCase
+	collection   collection.Usecase
+	registration collection.RegistrationRepo
+	// minInterval between every rounds
+	interval  time.Duration
+	errorCh   chan error
+	stoppedCh chan interface{}
+}
+
+func NewSpamClassifier(spamUseCase spam.UseCase, collectionUsecase collection.Usecase, registrationRepo collection.RegistrationRepo, errCh chan error) *SpamClassifier {
+	return &SpamClassifier{
+		spam:         spamUseCase,
+		collection:   collectionUsecase,
+		registration: registrationRepo,
+		errorCh:      errCh,
+		stoppedCh:    make(chan interface{}),
+	}
+}
+
+func (im *SpamClassifier) SetInterval(interval time.Duration) *SpamClassifier {
+	im.interval = interval
+	return im
+}
+
+func (im *SpamClassifier) Start(ctx ctx.Ctx) {
+	go im.loop(ctx)
+}
+
+func (im *SpamClassifier) loop(ctx ctx.Ctx) {
+	errAndStop := func(err error) {
+		im.errorCh <- err
+		close(im.stoppedCh)
+	}
+
+	nextTick := time.Second * 0
+	limit := int32(100)
+	offset := int32(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(im.stoppedCh)
+			return
+		case <-time.After(nextTick):
+			cols, err := im.collection.FindAll(ctx, collection.WithPagination(offset, limit))
+			if err != nil {
+				ctx.WithFields(log.Fields{
+					"offset": offset,
+					"limit":  limit,
+					"err":    err,
+				}).Error("im.collection.FindAll failed")
+				errAndStop(err)
+				return
+			}
+
+			ctx.Info(fmt.Sprintf("spam classify progress: %d", offset))
+
+			ids := []collection.CollectionId{}
+			for _, col := range cols.Items {
+				ids = append(ids, col.ToId())
+			}
+
+			// discovered contracts are classified along with the last page of collections
+			if len(cols.Items) < int(limit) && im.registration != nil {
+				regs, err := im.registration.FindAll(ctx)
+				if err != nil {
+					ctx.WithField("err", err).Error("im.registration.FindAll failed")
+					errAndStop(err)
+					return
+				}
+				for _, reg := range regs {
+					if reg.Discovered && reg.State == collection.RegistrationStatePending {
+						ids = append(ids, collection.CollectionId{ChainId: reg.ChainId, Address: reg.Erc721Address})
+					}
+				}
+			}
+
+			for _, id := range ids {
+				// a failed contract is classified again in the next round
+				if res, err := im.spam.Refresh(ctx, id.ChainId, id.Address); err != nil {
+					ctx.WithFields(log.Fields{
+						"chainId": id.ChainId,
+						"address": id.Address,
+						"err":     err,
+					}).Error("im.spam.Refresh failed")
+				} else if spam.IsSpam(res.Score) {
+					ctx.WithFields(log.Fields{
+						"chainId": id.ChainId,
+						"address": id.Address,
+						"score":   res.Score,
+						"signals": res.Signals,
+					}).Info("classified as spam")
+				}
+			}
+
+			if len(cols.Items) < int(limit) {
+				nextTick = im.interval
+				offset = 0
+			} else {
+				nextTick = time.Second * 0
+				offset += limit
+			}
+		}
+	}
+}
+
+func (im *SpamClassifier) Wait() {
+	<-im.stoppedCh
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/x-xyz/goapi/base/metrics"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/chain"
+	chainservice "github.com/x-xyz/goapi/service/chain"
 	"github.com/x-xyz/goapi/service/query"
 )
 
@@ -596,25 +597,5 @@ func (f *EventTracker) headerByNumberWithRetry(ctx bCtx.Ctx, number uint64, retr
 }
 
 func getDeployedBlock(ctx bCtx.Ctx, c domain.EthClientRepo, addr common.Address) (uint64, error) {
-	blk, err := c.BlockNumber(ctx)
-	if err != nil {
-		return 0, err
-	}
-	l := blk
-	s := blk
-	for l > 0 {
-		step := l / 2
-		mid := s - step - 1
-		b, err := c.CodeAt(ctx, addr, new(big.Int).SetUint64(mid))
-		if err != nil {
-			return 0, err
-		}
-		if len(b) > 0 {
-			s = mid
-			l -= step + 1
-		} else {
-			l = step
-		}
-	}
-	return s, nil
+	return chainservice.GetDeployedBlock(ctx, c, addr)
 }
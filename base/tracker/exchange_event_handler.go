@@ -4,8 +4,11 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/x-xyz/goapi/base/abi"
 	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
 	"github.com/x-xyz/goapi/domain/exchange"
+	"github.com/x-xyz/goapi/domain/nftitem"
 )
 
 var (
@@ -18,17 +21,21 @@ var (
 type ExchangeEventHandlerCfg struct {
 	ChainId         int64
 	ExchangeUseCase exchange.UseCase
+	// Alert is optional, token alert rules are evaluated after trades if set
+	Alert alert.UseCase
 }
 
 type ExchangeEventHandler struct {
 	chainId    int64
 	exchangeUC exchange.UseCase
+	alert      alert.UseCase
 }
 
 func NewExchangeEventHandler(cfg *ExchangeEventHandlerCfg) EventHandler {
 	return &ExchangeEventHandler{
 		chainId:    cfg.ChainId,
 		exchangeUC: cfg.ExchangeUseCase,
+		alert:      cfg.Alert,
 	}
 }
 
@@ -74,6 +81,7 @@ func (h *ExchangeEventHandler) ProcessEvents(ctx bCtx.Ctx, logs []logWithBlockTi
 				ctx.WithField("err", err).Error("exchangeUC.TakerAsk failed")
 				return err
 			}
+			h.evaluateAlerts(ctx, e.Fulfillment)
 		case takerBidSig:
 			e, err := toTakerBidEvent(&log)
 			if err != nil {
@@ -85,6 +93,7 @@ func (h *ExchangeEventHandler) ProcessEvents(ctx bCtx.Ctx, logs []logWithBlockTi
 				ctx.WithField("err", err).Error("exchangeUC.TakerBid failed")
 				return err
 			}
+			h.evaluateAlerts(ctx, e.Fulfillment)
 		default:
 			ctx.WithField("signature", log.Topics[0]).Warn("unrecognized signature, skipping")
 		}
@@ -92,6 +101,24 @@ func (h *ExchangeEventHandler) ProcessEvents(ctx bCtx.Ctx, logs []logWithBlockTi
 	return nil
 }
 
+// evaluateAlerts is best effort, a failed evaluation is retried by the PriceUpdater
+func (h *ExchangeEventHandler) evaluateAlerts(ctx bCtx.Ctx, f exchange.Fulfillment) {
+	if h.alert == nil {
+		return
+	}
+	id := nftitem.Id{
+		ChainId:         domain.ChainId(h.chainId),
+		ContractAddress: f.Collection.ToLower(),
+		TokenId:         domain.TokenId(f.TokenId.String()),
+	}
+	if err := h.alert.EvaluateToken(ctx, id); err != nil {
+		ctx.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Warn("alert.EvaluateToken failed")
+	}
+}
+
 func toCancelAllOrdersEvent(log *logWithBlockTime) (*exchange.CancelAllOrdersEvent, error) {
 	l, err := abi.ToCancelAllOrdersLog(&log.Log)
 	if err != nil {
@@ -123,7 +123,7 @@ func (m *ManifoldEventHandler) processRoyaltyOverride(c ctx.Ctx, evt *manifold.R
 
 	updatedPayload := collection.UpdatePayload{
 		FeeRecipient: recipients[0],
-		Royalty:      royalty,
+		Royalty:      &royalty,
 	}
 	err = m.CollectionRepo.Update(c, coll.ToId(), updatedPayload)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/x-xyz/goapi/base/log"
 	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/order"
 	"github.com/x-xyz/goapi/domain/token"
@@ -20,7 +21,9 @@ type PriceUpdaterCfg struct {
 	Order          order.UseCase
 	Interval       time.Duration
 	PriceFormatter pricefomatter.PriceFormatter
-	ErrorCh        chan<- error
+	// Alert is optional, alert rules are evaluated after prices of a collection are updated if set
+	Alert   alert.UseCase
+	ErrorCh chan<- error
 }
 
 type PriceUpdater struct {
@@ -30,6 +33,7 @@ type PriceUpdater struct {
 	order          order.UseCase
 	interval       time.Duration
 	priceFormatter pricefomatter.PriceFormatter
+	alert          alert.UseCase
 	errorCh        chan<- error
 	stoppedCh      chan interface{}
 }
@@ -42,6 +46,7 @@ func NewPriceUpdater(cfg *PriceUpdaterCfg) *PriceUpdater {
 		order:          cfg.Order,
 		interval:       cfg.Interval,
 		priceFormatter: cfg.PriceFormatter,
+		alert:          cfg.Alert,
 		errorCh:        cfg.ErrorCh,
 		stoppedCh:      make(chan interface{}),
 	}
@@ -113,6 +118,15 @@ func (u *PriceUpdater) updatePricesForCollection(ctx bCtx.Ctx, col *collection.C
 			"err": err,
 		}).Error("updatePricesForListings failed")
 	}
+
+	if u.alert != nil {
+		if err := u.alert.EvaluateCollection(ctx, id); err != nil {
+			ctx.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("alert.EvaluateCollection failed")
+		}
+	}
 	return nil
 
 	// TODO: add auction, bids
@@ -58,9 +58,10 @@ func (a *Account) ToInfo() *Info {
 }
 
 type SimpleAccount struct {
-	Address   domain.Address `json:"address"`
-	Alias     string         `json:"alias"`
-	ImageHash string         `json:"imageHash"`
+	Address   domain.Address     `json:"address"`
+	Alias     string             `json:"alias"`
+	ImageHash string             `json:"imageHash"`
+	Ens       *domain.EnsProfile `json:"ens,omitempty"`
 }
 
 // Info is account struct returns to client which contains public info and aggreates data from other usecases
@@ -80,6 +81,8 @@ type Info struct {
 	Twitter     string         `json:"twitter"`
 	Instagram   string         `json:"instagram"`
 	Discord     string         `json:"discord"`
+	// Ens is the forward verified ens name of the address
+	Ens *domain.EnsProfile `json:"ens,omitempty"`
 }
 
 func (i *Info) Sanitized() *Info {
@@ -90,6 +93,7 @@ func (i *Info) Sanitized() *Info {
 		ImageHash:   i.ImageHash,
 		CreatedAtMs: i.CreatedAtMs,
 		IsModerator: i.IsModerator,
+		Ens:         i.Ens,
 	}
 }
 
@@ -160,9 +164,25 @@ type Usecase interface {
 	Unban(c ctx.Ctx, address domain.Address) error
 
 	GetActivities(c ctx.Ctx, address domain.Address, opts ...FindActivityHistoryOptions) (*ActivityResult, error)
+	// GetFeed returns activities of accounts followed and collections liked by the account
+	GetFeed(c ctx.Ctx, address domain.Address, params FeedParams) (*ActivityResult, error)
 
 	GetAccountStat(c ctx.Ctx, address domain.Address) (*AccountStat, error)
 	GetAccountCollectionHoldings(c ctx.Ctx, address domain.Address) (*AccountCollectionHoldings, error)
+
+	// LinkWallet links wallet to primary with a signature of the link message signed by wallet,
+	// the nonce of primary is used and reset
+	LinkWallet(c ctx.Ctx, primary, wallet domain.Address, chainId domain.ChainId, signature string) error
+	// UnlinkWallet is requested by either the primary account or the linked wallet
+	UnlinkWallet(c ctx.Ctx, requester, wallet domain.Address) error
+	// GetLinkedWallets returns the wallet group of address, which can be either the primary or a linked wallet
+	GetLinkedWallets(c ctx.Ctx, address domain.Address) (*LinkedWallets, error)
+	// GetLinkedActivities returns activities of all wallets linked with address
+	GetLinkedActivities(c ctx.Ctx, address domain.Address, opts ...FindActivityHistoryOptions) (*ActivityResult, error)
+	// GetLinkedCollectionHoldings sums collection holdings of all wallets linked with address
+	GetLinkedCollectionHoldings(c ctx.Ctx, address domain.Address) (*AccountCollectionHoldings, error)
+	// GetLinkedAccountStat sums stats of all wallets linked with address, collections held by several wallets are counted once
+	GetLinkedAccountStat(c ctx.Ctx, address domain.Address) (*AccountStat, error)
 }
 
 // Repo is account repo
@@ -38,4 +38,12 @@ type Activity struct {
 type ActivityResult struct {
 	Activities []*Activity `json:"activities"`
 	Count      int         `json:"count"`
+	NextCursor string      `json:"nextCursor"`
+}
+
+// FeedParams pages a feed by cursor, all activity types of the feed are included if Types is empty
+type FeedParams struct {
+	Types  []ActivityHistoryType `query:"types"`
+	Cursor string                `query:"cursor"`
+	Limit  int                   `query:"limit"`
 }
@@ -7,7 +7,9 @@ import (
 
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/service/keyset"
 	"github.com/x-xyz/goapi/service/opensea"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ActivityHistoryType string
@@ -54,6 +56,7 @@ const (
 )
 
 type ActivityHistory struct {
+	ObjectId        primitive.ObjectID  `json:"-" bson:"_id,omitempty"`
 	ChainId         domain.ChainId      `json:"chainId" bson:"chainId"`
 	ContractAddress domain.Address      `json:"contractAddress" bson:"contractAddress"`
 	TokenId         domain.TokenId      `json:"tokenId" bson:"tokenId"`
@@ -131,12 +134,16 @@ type findActivityHistoryOptions struct {
 	Offset   *int
 	Limit    *int
 	Account  *domain.Address
+	Accounts []domain.Address
 	ChainId  *domain.ChainId
 	Contract *domain.Address
 	TokenId  *domain.TokenId
 	Types    []ActivityHistoryType
 	TimeGTE  *time.Time
 	Source   *SourceType
+	Feed     *ActivityFeed
+	// Cursor enables keyset pagination, empty for the first page. Offset is ignored if Cursor is set
+	Cursor *string
 }
 
 type FindActivityHistoryOptions func(*findActivityHistoryOptions) error
@@ -166,6 +173,17 @@ func ActivityHistoryWithAccount(account domain.Address) FindActivityHistoryOptio
 	}
 }
 
+// ActivityHistoryWithAccounts matches activities of any of the accounts, it's ignored if ActivityHistoryWithAccount is set
+func ActivityHistoryWithAccounts(accounts []domain.Address) FindActivityHistoryOptions {
+	return func(opts *findActivityHistoryOptions) error {
+		opts.Accounts = nil
+		for _, a := range accounts {
+			opts.Accounts = append(opts.Accounts, a.ToLower())
+		}
+		return nil
+	}
+}
+
 func ActivityHistoryWithChainId(chainId domain.ChainId) FindActivityHistoryOptions {
 	return func(opts *findActivityHistoryOptions) error {
 		opts.ChainId = &chainId
@@ -218,6 +236,64 @@ func ActivityHistoryWithSource(source SourceType) FindActivityHistoryOptions {
 	}
 }
 
+// ActivityFeed matches activities of any of the accounts or in any of the collections
+type ActivityFeed struct {
+	Accounts    []domain.Address
+	Collections []CollectionId
+}
+
+func ActivityHistoryWithFeed(accounts []domain.Address, collections []CollectionId) FindActivityHistoryOptions {
+	return func(opts *findActivityHistoryOptions) error {
+		feed := &ActivityFeed{}
+		for _, a := range accounts {
+			feed.Accounts = append(feed.Accounts, a.ToLower())
+		}
+		for _, c := range collections {
+			feed.Collections = append(feed.Collections, CollectionId{ChainId: c.ChainId, Address: c.Address.ToLower()})
+		}
+		opts.Feed = feed
+		return nil
+	}
+}
+
+func ActivityHistoryWithCursor(cursor string) FindActivityHistoryOptions {
+	return func(opts *findActivityHistoryOptions) error {
+		opts.Cursor = &cursor
+		return nil
+	}
+}
+
+// ActivityHistorySorts returns the order of found activities, activities in the same block are ordered by _id
+func ActivityHistorySorts() []string {
+	return []string{"-time", "-_id"}
+}
+
+// NextActivityHistoryCursor returns the cursor of the page after activities found with opts,
+// empty if cursor paging is not enabled or activities is the last page
+func NextActivityHistoryCursor(activities []ActivityHistory, optFns ...FindActivityHistoryOptions) (string, error) {
+	opts, err := GetFindActivityHistoryOptions(optFns...)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Cursor == nil || opts.Limit == nil || *opts.Limit <= 0 || len(activities) < *opts.Limit {
+		return "", nil
+	}
+
+	return keyset.Encode(ActivityHistorySorts(), activities[len(activities)-1])
+}
+
+// ShouldCountActivityHistories returns false for pages after the first of cursor paging, their total is
+// already known from the first page and counting them scans the whole filter again
+func ShouldCountActivityHistories(optFns ...FindActivityHistoryOptions) (bool, error) {
+	opts, err := GetFindActivityHistoryOptions(optFns...)
+	if err != nil {
+		return false, err
+	}
+
+	return opts.Cursor == nil || *opts.Cursor == "", nil
+}
+
 type ActivityHistoryRepo interface {
 	Insert(ctx.Ctx, *ActivityHistory) error
 	FindActivities(c ctx.Ctx, opts ...FindActivityHistoryOptions) ([]ActivityHistory, error)
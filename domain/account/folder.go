@@ -1,6 +1,8 @@
 package account
 
 import (
+	"errors"
+	"regexp"
 	"time"
 
 	"github.com/x-xyz/goapi/base/ctx"
@@ -8,6 +10,45 @@ import (
 	"github.com/x-xyz/goapi/domain/nftitem"
 )
 
+const (
+	MaxFolderDescriptionLength = 1000
+	MaxFolderCaptionLength     = 280
+	MaxFolderCollaborators     = 20
+)
+
+var (
+	ErrBuiltInFolder = errors.New("built-in folder is uneditable")
+	ErrInvalidSlug   = errors.New("invalid slug")
+	ErrSlugTaken     = errors.New("slug is taken")
+	ErrInvalidFolder = errors.New("invalid folder settings")
+	// ErrNotFolderEditor is returned if the account is neither the owner nor a collaborator of the folder
+	ErrNotFolderEditor = errors.New("not an editor of the folder")
+)
+
+// slugPattern is lowercase words joined by dashes, e.g. "my-apes"
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func IsValidSlug(slug string) bool {
+	return len(slug) >= 3 && len(slug) <= 64 && slugPattern.MatchString(slug)
+}
+
+// CoverLayout is how the gallery cover is composed of the folder items
+type CoverLayout string
+
+const (
+	CoverLayoutSingle CoverLayout = "single"
+	CoverLayoutGrid   CoverLayout = "grid"
+	CoverLayoutMosaic CoverLayout = "mosaic"
+)
+
+func (l CoverLayout) IsValid() bool {
+	switch l {
+	case CoverLayoutSingle, CoverLayoutGrid, CoverLayoutMosaic:
+		return true
+	}
+	return false
+}
+
 type Folder struct {
 	Id                    string         `json:"id" bson:"id"`
 	Name                  string         `json:"name" bson:"name"`
@@ -22,6 +63,39 @@ type Folder struct {
 	NftCount              int            `json:"nftCount" bson:"nftCount"`
 	CollectionCount       int            `json:"collectionCount" bson:"collectionCount"`
 	Cover                 nftitem.Id     `json:"cover,omitempty" bson:"cover,omitempty"`
+	// gallery settings, slug is unique per owner
+	Description string      `json:"description" bson:"description"`
+	Slug        string      `json:"slug,omitempty" bson:"slug,omitempty"`
+	CoverLayout CoverLayout `json:"coverLayout,omitempty" bson:"coverLayout,omitempty"`
+	ViewCount   int64       `json:"viewCount" bson:"viewCount"`
+	// Collaborators can add nfts they own to the folder
+	Collaborators []domain.Address `json:"collaborators" bson:"collaborators"`
+
+	// PreviousTotalValueInUsd is the total value at yesterday floors, nil if the stat was refreshed before it's kept
+	PreviousTotalValueInUsd *float64 `json:"-" bson:"previousTotalValueInUsd,omitempty"`
+}
+
+// Portfolio is the value of nfts held by accounts at collection floors, aggregated from their built-in folders
+type Portfolio struct {
+	// FloorPriceInUsd is the lowest collection floor of the nfts
+	FloorPriceInUsd       float64 `json:"floorPriceInUsd"`
+	TotalValueInUsd       float64 `json:"totalValueInUsd"`
+	TotalValueMovement    float64 `json:"totalValueMovement"`
+	InstantLiquidityInUsd float64 `json:"instantLiquidityInUsd"`
+	NftCount              int     `json:"nftCount"`
+}
+
+// CanEdit reports whether the address is the owner or a collaborator of the folder
+func (f *Folder) CanEdit(address domain.Address) bool {
+	if f.Owner.Equals(address) {
+		return true
+	}
+	for _, collaborator := range f.Collaborators {
+		if collaborator.Equals(address) {
+			return true
+		}
+	}
+	return false
 }
 
 type FolderUpdater struct {
@@ -36,15 +110,43 @@ type FolderUpdater struct {
 	// udpate by usecase
 	CollectionCount *int `json:"-" bson:"collectionCount"`
 	// update by usecase, use first of nfts
-	Cover *nftitem.Id `json:"cover" bson:"cover"`
+	Cover         *nftitem.Id       `json:"cover" bson:"cover"`
+	Description   *string           `json:"description" bson:"description"`
+	Slug          *string           `json:"slug" bson:"slug"`
+	CoverLayout   *CoverLayout      `json:"coverLayout" bson:"coverLayout"`
+	Collaborators *[]domain.Address `json:"collaborators" bson:"collaborators"`
+
+	// update by usecase
+	PreviousTotalValueInUsd *float64 `json:"-" bson:"previousTotalValueInUsd"`
+}
+
+// FolderItem is an nft of a folder with its gallery caption
+type FolderItem struct {
+	nftitem.NftitemWith1155Balance
+	Caption string `json:"caption,omitempty"`
+	// AddedBy is the collaborator who added the nft, empty if added by the owner
+	AddedBy domain.Address `json:"addedBy,omitempty"`
+}
+
+// Holder returns the account holding the nft, owner is the folder owner
+func (i *FolderItem) Holder(owner domain.Address) domain.Address {
+	if i.AddedBy != "" {
+		return i.AddedBy
+	}
+	return owner
 }
 
 type GetFoldersOptions struct {
-	Owner     *domain.Address
+	Owner *domain.Address
+	// Owners matches folders of any of the addresses, e.g. all wallets linked to an account
+	Owners    []domain.Address
 	IsBuiltIn *bool
 	IsPrivate *bool
-	Offset    *int32
-	Limit     *int32
+	Slug      *string
+	// Collaborator matches folders shared with the address
+	Collaborator *domain.Address
+	Offset       *int32
+	Limit        *int32
 }
 
 func ParseGetFoldersOptionFunc(opts ...GetFoldersOptionsFunc) (GetFoldersOptions, error) {
@@ -68,6 +170,15 @@ func WithOwner(owner domain.Address) GetFoldersOptionsFunc {
 	}
 }
 
+func WithOwners(owners []domain.Address) GetFoldersOptionsFunc {
+	return func(gfo *GetFoldersOptions) error {
+		for _, owner := range owners {
+			gfo.Owners = append(gfo.Owners, owner.ToLower())
+		}
+		return nil
+	}
+}
+
 func WithBuiltIn(isBuiltIn bool) GetFoldersOptionsFunc {
 	return func(gfo *GetFoldersOptions) error {
 		gfo.IsBuiltIn = &isBuiltIn
@@ -82,6 +193,21 @@ func WithPrivate(isPrivate bool) GetFoldersOptionsFunc {
 	}
 }
 
+func WithSlug(slug string) GetFoldersOptionsFunc {
+	return func(gfo *GetFoldersOptions) error {
+		gfo.Slug = &slug
+		return nil
+	}
+}
+
+func WithCollaborator(collaborator domain.Address) GetFoldersOptionsFunc {
+	return func(gfo *GetFoldersOptions) error {
+		collaborator = collaborator.ToLower()
+		gfo.Collaborator = &collaborator
+		return nil
+	}
+}
+
 func WithPagination(offset int32, limit int32) GetFoldersOptionsFunc {
 	return func(options *GetFoldersOptions) error {
 		options.Offset = &offset
@@ -96,6 +222,7 @@ type FolderRepo interface {
 	GetFolders(ctx ctx.Ctx, opts ...GetFoldersOptionsFunc) ([]*Folder, error)
 	Update(ctx ctx.Ctx, Id string, updater *FolderUpdater) error
 	Delete(ctx ctx.Ctx, Id string) error
+	IncreaseViewCount(ctx ctx.Ctx, Id string, count int) (int64, error)
 }
 
 type FolderUseCase interface {
@@ -105,10 +232,27 @@ type FolderUseCase interface {
 	GetFolder(ctx ctx.Ctx, folderId string) (*Folder, error)
 	GetFolders(ctx ctx.Ctx, opts ...GetFoldersOptionsFunc) ([]*Folder, error)
 	GetNFTsInFolder(ctx ctx.Ctx, folderId string) ([]*nftitem.NftitemWith1155Balance, error)
+	// GetFolderItems returns nfts of the folder in gallery order with captions
+	GetFolderItems(ctx ctx.Ctx, folderId string) ([]*FolderItem, error)
 	RefreshStat(ctx.Ctx, string) error
 	RefreshCount(ctx ctx.Ctx, folderId string) error
 	Delete(c ctx.Ctx, folderId string) error
 	MarkNftPrivate(c ctx.Ctx, owner domain.Address, marks []nftitem.Id, unmarks []nftitem.Id) error
 	DeleteRelationFromAllFolders(c ctx.Ctx, owner domain.Address, nftitemId nftitem.Id) error
 	AddNftToPublicFolder(c ctx.Ctx, owner domain.Address, nftitemId nftitem.Id) error
+
+	// GetGallery returns the folder of owner by slug
+	GetGallery(c ctx.Ctx, owner domain.Address, slug string) (*Folder, error)
+	IncreaseViewCount(c ctx.Ctx, folderId string) (int64, error)
+	// UpdateGallery updates description, slug, cover layout and collaborators of the folder
+	UpdateGallery(c ctx.Ctx, folderId string, updater *FolderUpdater) error
+	// Reorder moves items to the front in the given order, the rest keep their order after them
+	Reorder(c ctx.Ctx, folderId string, items []nftitem.Id) error
+	// SetCaption sets the caption of an item, by the owner or the collaborator who added it
+	SetCaption(c ctx.Ctx, folderId string, editor domain.Address, item nftitem.Id, caption string) error
+	// AddItems adds nfts owned by editor to the folder, editor is the owner or a collaborator
+	AddItems(c ctx.Ctx, folderId string, editor domain.Address, items []nftitem.Id) error
+
+	// GetPortfolio sums the portfolio of owners, pass all wallets linked with an account for its aggregated portfolio
+	GetPortfolio(c ctx.Ctx, owners []domain.Address) (*Portfolio, error)
 }
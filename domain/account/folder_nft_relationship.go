@@ -12,6 +12,17 @@ type FolderNftRelationship struct {
 	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
 	TokenId         domain.TokenId `json:"tokenId" bson:"tokenId"`
 	Index           int            `json:"index" bson:"index"`
+	Caption         string         `json:"caption,omitempty" bson:"caption,omitempty"`
+	// AddedBy is the collaborator who added the nft, empty if added by the folder owner
+	AddedBy domain.Address `json:"addedBy,omitempty" bson:"addedBy,omitempty"`
+}
+
+// Holder returns the account holding the nft, owner is the folder owner
+func (r *FolderNftRelationship) Holder(owner domain.Address) domain.Address {
+	if r.AddedBy != "" {
+		return r.AddedBy
+	}
+	return owner
 }
 
 func (r *FolderNftRelationship) ToNftItemId() *nftitem.Id {
@@ -28,6 +39,7 @@ type RelationsQueryOptions struct {
 	Limit     *int
 	FolderIds *[]string
 	NftitemId *nftitem.Id
+	AddedBys  *[]domain.Address
 }
 
 type RelationsQueryOptionsFunc func(*RelationsQueryOptions) error
@@ -79,6 +91,14 @@ func WithNftitemId(id nftitem.Id) RelationsQueryOptionsFunc {
 	}
 }
 
+// WithAddedBys selects relations added by any of the collaborators
+func WithAddedBys(addedBys []domain.Address) RelationsQueryOptionsFunc {
+	return func(options *RelationsQueryOptions) error {
+		options.AddedBys = &addedBys
+		return nil
+	}
+}
+
 type FolderNftRelationshipRepo interface {
 	Insert(ctx ctx.Ctx, relation *FolderNftRelationship) error
 	GetAllRelations(ctx ctx.Ctx, opts ...RelationsQueryOptionsFunc) ([]*FolderNftRelationship, error)
@@ -89,4 +109,12 @@ type FolderNftRelationshipRepo interface {
 	AddNftitemsToFolder(ctx ctx.Ctx, items []nftitem.Id, folderId string) error
 	// MoveNftitems will move or create nft from src folder to destination folder
 	MoveNftitems(ctx ctx.Ctx, items []nftitem.Id, fromFolderId, toFolderId string) error
+	// ReplaceRelations replaces relations of the folder in a transaction, indexes follow the order of relations
+	ReplaceRelations(ctx ctx.Ctx, folderId string, relations []*FolderNftRelationship) error
+	// AppendRelations inserts relations after the last one of the folder, indexes follow the order of relations
+	AppendRelations(ctx ctx.Ctx, folderId string, relations []*FolderNftRelationship) error
+	// UpdateIndexes sets indexes of the relations to the order of items, relations of other nfts are left untouched
+	UpdateIndexes(ctx ctx.Ctx, folderId string, items []nftitem.Id) error
+	// UpdateCaption returns domain.ErrNotFound if the nft isn't in the folder
+	UpdateCaption(ctx ctx.Ctx, folderId string, item nftitem.Id, caption string) error
 }
@@ -0,0 +1,67 @@
+package account
+
+import (
+	"errors"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+const (
+	// MaxLinkedWallets is the max number of wallets linked to a primary account
+	MaxLinkedWallets = 10
+	// DefaultLinkWalletMsg is signed by the wallet being linked, formatted with the primary address and its nonce
+	DefaultLinkWalletMsg = "Link this wallet to account %s\n\nNonce: %s"
+)
+
+var (
+	ErrCannotLinkSelf       = errors.New("cannot link account to itself")
+	ErrWalletAlreadyLinked  = errors.New("wallet already linked")
+	ErrWalletNotLinked      = errors.New("wallet not linked")
+	ErrPrimaryIsLinked      = errors.New("account is linked to another account")
+	ErrTooManyLinkedWallets = errors.New("too many linked wallets")
+)
+
+// LinkedWallet is a wallet proven to belong to the user of the primary account
+type LinkedWallet struct {
+	// Address is the linked wallet, a wallet can only be linked to one primary account
+	Address  domain.Address `json:"address" bson:"_id"`
+	Primary  domain.Address `json:"primary" bson:"primary"`
+	ChainId  domain.ChainId `json:"chainId" bson:"chainId"`
+	LinkedAt time.Time      `json:"linkedAt" bson:"linkedAt"`
+}
+
+// LinkedWallets is the group of wallets of an account
+type LinkedWallets struct {
+	Primary domain.Address  `json:"primary"`
+	Wallets []*LinkedWallet `json:"wallets"`
+}
+
+// Addresses returns the primary address followed by the linked wallets
+func (w *LinkedWallets) Addresses() []domain.Address {
+	res := []domain.Address{w.Primary}
+	for _, wallet := range w.Wallets {
+		res = append(res, wallet.Address)
+	}
+	return res
+}
+
+// Contains reports whether address is the primary or one of the linked wallets
+func (w *LinkedWallets) Contains(address domain.Address) bool {
+	for _, a := range w.Addresses() {
+		if a.Equals(address) {
+			return true
+		}
+	}
+	return false
+}
+
+type LinkedWalletRepo interface {
+	// Get returns the link of the wallet, domain.ErrNotFound if the wallet isn't linked
+	Get(c ctx.Ctx, address domain.Address) (*LinkedWallet, error)
+	FindByPrimary(c ctx.Ctx, primary domain.Address) ([]*LinkedWallet, error)
+	// Insert returns ErrWalletAlreadyLinked if the wallet is linked
+	Insert(c ctx.Ctx, wallet *LinkedWallet) error
+	Remove(c ctx.Ctx, address domain.Address) error
+}
@@ -3,12 +3,10 @@
 package mocks
 
 import (
+	mock "github.com/stretchr/testify/mock"
 	ctx "github.com/x-xyz/goapi/base/ctx"
-	account "github.com/x-xyz/goapi/domain/account"
-
 	domain "github.com/x-xyz/goapi/domain"
-
-	mock "github.com/stretchr/testify/mock"
+	account "github.com/x-xyz/goapi/domain/account"
 )
 
 // Usecase is an autogenerated mock type for the Usecase type
@@ -187,6 +185,29 @@ func (_m *Usecase) GetActivities(c ctx.Ctx, address domain.Address, opts ...acco
 	return r0, r1
 }
 
+// GetFeed provides a mock function with given fields: c, address, params
+func (_m *Usecase) GetFeed(c ctx.Ctx, address domain.Address, params account.FeedParams) (*account.ActivityResult, error) {
+	ret := _m.Called(c, address, params)
+
+	var r0 *account.ActivityResult
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, account.FeedParams) *account.ActivityResult); ok {
+		r0 = rf(c, address, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.ActivityResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address, account.FeedParams) error); ok {
+		r1 = rf(c, address, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFollowers provides a mock function with given fields: c, address
 func (_m *Usecase) GetFollowers(c ctx.Ctx, address domain.Address) ([]*account.Info, error) {
 	ret := _m.Called(c, address)
@@ -233,16 +254,16 @@ func (_m *Usecase) GetFollowings(c ctx.Ctx, address domain.Address) ([]*account.
 	return r0, r1
 }
 
-// GetNotificationSettings provides a mock function with given fields: c, address
-func (_m *Usecase) GetNotificationSettings(c ctx.Ctx, address domain.Address) (*account.NotificationSettings, error) {
+// GetLinkedAccountStat provides a mock function with given fields: c, address
+func (_m *Usecase) GetLinkedAccountStat(c ctx.Ctx, address domain.Address) (*account.AccountStat, error) {
 	ret := _m.Called(c, address)
 
-	var r0 *account.NotificationSettings
-	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) *account.NotificationSettings); ok {
+	var r0 *account.AccountStat
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) *account.AccountStat); ok {
 		r0 = rf(c, address)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*account.NotificationSettings)
+			r0 = ret.Get(0).(*account.AccountStat)
 		}
 	}
 
@@ -256,8 +277,8 @@ func (_m *Usecase) GetNotificationSettings(c ctx.Ctx, address domain.Address) (*
 	return r0, r1
 }
 
-// GetPendingOffers provides a mock function with given fields: c, address, opts
-func (_m *Usecase) GetPendingOffers(c ctx.Ctx, address domain.Address, opts ...account.FindPendingOfferOptions) (*account.PendingOfferResult, error) {
+// GetLinkedActivities provides a mock function with given fields: c, address, opts
+func (_m *Usecase) GetLinkedActivities(c ctx.Ctx, address domain.Address, opts ...account.FindActivityHistoryOptions) (*account.ActivityResult, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -267,17 +288,17 @@ func (_m *Usecase) GetPendingOffers(c ctx.Ctx, address domain.Address, opts ...a
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *account.PendingOfferResult
-	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, ...account.FindPendingOfferOptions) *account.PendingOfferResult); ok {
+	var r0 *account.ActivityResult
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, ...account.FindActivityHistoryOptions) *account.ActivityResult); ok {
 		r0 = rf(c, address, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*account.PendingOfferResult)
+			r0 = ret.Get(0).(*account.ActivityResult)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address, ...account.FindPendingOfferOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address, ...account.FindActivityHistoryOptions) error); ok {
 		r1 = rf(c, address, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -286,6 +307,75 @@ func (_m *Usecase) GetPendingOffers(c ctx.Ctx, address domain.Address, opts ...a
 	return r0, r1
 }
 
+// GetLinkedCollectionHoldings provides a mock function with given fields: c, address
+func (_m *Usecase) GetLinkedCollectionHoldings(c ctx.Ctx, address domain.Address) (*account.AccountCollectionHoldings, error) {
+	ret := _m.Called(c, address)
+
+	var r0 *account.AccountCollectionHoldings
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) *account.AccountCollectionHoldings); ok {
+		r0 = rf(c, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.AccountCollectionHoldings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address) error); ok {
+		r1 = rf(c, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLinkedWallets provides a mock function with given fields: c, address
+func (_m *Usecase) GetLinkedWallets(c ctx.Ctx, address domain.Address) (*account.LinkedWallets, error) {
+	ret := _m.Called(c, address)
+
+	var r0 *account.LinkedWallets
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) *account.LinkedWallets); ok {
+		r0 = rf(c, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.LinkedWallets)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address) error); ok {
+		r1 = rf(c, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNotificationSettings provides a mock function with given fields: c, address
+func (_m *Usecase) GetNotificationSettings(c ctx.Ctx, address domain.Address) (*account.NotificationSettings, error) {
+	ret := _m.Called(c, address)
+
+	var r0 *account.NotificationSettings
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) *account.NotificationSettings); ok {
+		r0 = rf(c, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*account.NotificationSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address) error); ok {
+		r1 = rf(c, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsFollowing provides a mock function with given fields: c, address, toAddress
 func (_m *Usecase) IsFollowing(c ctx.Ctx, address domain.Address, toAddress domain.Address) (bool, error) {
 	ret := _m.Called(c, address, toAddress)
@@ -307,6 +397,20 @@ func (_m *Usecase) IsFollowing(c ctx.Ctx, address domain.Address, toAddress doma
 	return r0, r1
 }
 
+// LinkWallet provides a mock function with given fields: c, primary, wallet, chainId, signature
+func (_m *Usecase) LinkWallet(c ctx.Ctx, primary domain.Address, wallet domain.Address, chainId domain.ChainId, signature string) error {
+	ret := _m.Called(c, primary, wallet, chainId, signature)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, domain.Address, domain.ChainId, string) error); ok {
+		r0 = rf(c, primary, wallet, chainId, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Unban provides a mock function with given fields: c, address
 func (_m *Usecase) Unban(c ctx.Ctx, address domain.Address) error {
 	ret := _m.Called(c, address)
@@ -335,6 +439,20 @@ func (_m *Usecase) Unfollow(c ctx.Ctx, address domain.Address, toAddress domain.
 	return r0
 }
 
+// UnlinkWallet provides a mock function with given fields: c, requester, wallet
+func (_m *Usecase) UnlinkWallet(c ctx.Ctx, requester domain.Address, wallet domain.Address) error {
+	ret := _m.Called(c, requester, wallet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, domain.Address) error); ok {
+		r0 = rf(c, requester, wallet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: c, address, updater
 func (_m *Usecase) Update(c ctx.Ctx, address domain.Address, updater *account.Updater) (*account.Info, error) {
 	ret := _m.Called(c, address, updater)
@@ -423,41 +541,6 @@ func (_m *Usecase) UpsertNotificationSettings(c ctx.Ctx, settings *account.Notif
 	return r0, r1
 }
 
-// UseOrderNonce provides a mock function with given fields: c, address
-func (_m *Usecase) UseOrderNonce(c ctx.Ctx, address domain.Address) (string, error) {
-	ret := _m.Called(c, address)
-
-	var r0 string
-	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address) string); ok {
-		r0 = rf(c, address)
-	} else {
-		r0 = ret.Get(0).(string)
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.Address) error); ok {
-		r1 = rf(c, address)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// UseOrderNonceIfNeeded provides a mock function with given fields: c, address, nonce
-func (_m *Usecase) UseOrderNonceIfNeeded(c ctx.Ctx, address domain.Address, nonce string) error {
-	ret := _m.Called(c, address, nonce)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.Address, string) error); ok {
-		r0 = rf(c, address, nonce)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // ValidateSignature provides a mock function with given fields: c, address, signature
 func (_m *Usecase) ValidateSignature(c ctx.Ctx, address domain.Address, signature string) error {
 	ret := _m.Called(c, address, signature)
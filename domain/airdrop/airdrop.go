@@ -25,11 +25,13 @@ type Airdrop struct {
 }
 
 type AirdropFindAllOptions struct {
-	SortBy        *string         `bson:"-"`
-	SortDir       *domain.SortDir `bson:"-"`
-	Offset        *int32          `bson:"-"`
-	Limit         *int32          `bson:"-"`
-	DeadlineAfter *time.Time      `bson:"-"`
+	SortBy          *string         `bson:"-"`
+	SortDir         *domain.SortDir `bson:"-"`
+	Offset          *int32          `bson:"-"`
+	Limit           *int32          `bson:"-"`
+	DeadlineAfter   *time.Time      `bson:"-"`
+	ChainId         *domain.ChainId `bson:"-"`
+	ContractAddress *domain.Address `bson:"-"`
 }
 
 type AirdropFindAllOptionsFunc func(*AirdropFindAllOptions) error
@@ -69,6 +71,20 @@ func AirdropWithDeadlineAfter(deadline time.Time) AirdropFindAllOptionsFunc {
 	}
 }
 
+func AirdropWithChainId(chainId domain.ChainId) AirdropFindAllOptionsFunc {
+	return func(options *AirdropFindAllOptions) error {
+		options.ChainId = &chainId
+		return nil
+	}
+}
+
+func AirdropWithContractAddress(address domain.Address) AirdropFindAllOptionsFunc {
+	return func(options *AirdropFindAllOptions) error {
+		options.ContractAddress = address.ToLowerPtr()
+		return nil
+	}
+}
+
 type AirdropRepo interface {
 	FindAll(ctx.Ctx, ...AirdropFindAllOptionsFunc) ([]Airdrop, error)
 	Create(ctx.Ctx, *Airdrop) error
@@ -7,12 +7,17 @@ import (
 	"github.com/x-xyz/goapi/domain"
 )
 
+// ListingRecord counts listings of an owner at a snapshot. Count follows the legacy airdrop rule, listings within 25%
+// of opensea floor price, Weight and WeightedCount are the sum of collection_promotion.ListingWeight and the number of
+// listings weighted for listing reward rounds, Weight is nil if snapshotted before listings were weighted
 type ListingRecord struct {
 	Owner           domain.Address `bson:"owner"`
 	ChainId         domain.ChainId `bson:"chainId"`
 	ContractAddress domain.Address `bson:"contractAddress"`
 	Count           int            `bson:"count"`
 	SubCount        int            `bson:"subcount"`
+	Weight          *float64       `bson:"weight,omitempty"`
+	WeightedCount   int            `bson:"weightedCount"`
 	SnapshotTime    *time.Time     `bson:"snapshotTime"`
 	UpdatedAt       *time.Time     `bson:"updatedAt"`
 }
@@ -12,6 +12,8 @@ type Proof struct {
 	Round           int            `json:"round" bson:"round"`
 	Amount          string         `json:"amount" bson:"amount"`
 	Proof           []string       `json:"proof" bson:"proof"`
+	// Index is the position of the leaf, only meaningful if the leaf encoding includes index
+	Index int `json:"index" bson:"index"`
 }
 
 type ProofFindAllOptions struct {
@@ -22,6 +24,7 @@ type ProofFindAllOptions struct {
 	ChainId         *domain.ChainId `bson:"chainId"`
 	ContractAddress *domain.Address `bson:"contractAddress"`
 	Claimer         *domain.Address `bson:"claimer"`
+	Round           *int            `bson:"round"`
 }
 
 type ProofFindAllOptionsFunc func(*ProofFindAllOptions) error
@@ -73,9 +76,19 @@ func ProofWithClaimer(claimer domain.Address) ProofFindAllOptionsFunc {
 	}
 }
 
+func ProofWithRound(round int) ProofFindAllOptionsFunc {
+	return func(options *ProofFindAllOptions) error {
+		options.Round = &round
+		return nil
+	}
+}
+
 type ProofRepo interface {
 	FindAll(ctx.Ctx, ...ProofFindAllOptionsFunc) ([]Proof, error)
 	Create(ctx.Ctx, *Proof) error
+	// BulkUpsert replaces proofs by chainId, contractAddress, claimer and round
+	BulkUpsert(ctx.Ctx, []Proof) error
+	RemoveAll(ctx.Ctx, ...ProofFindAllOptionsFunc) error
 }
 
 type ProofUseCase interface {
@@ -0,0 +1,292 @@
+package airdrop
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/validator"
+	"github.com/x-xyz/goapi/domain"
+)
+
+var (
+	ErrRoundExists     = errors.New("airdrop round already exists")
+	ErrNoAllocation    = errors.New("no allocation")
+	ErrInvalidEncoding = errors.New("invalid leaf encoding")
+)
+
+type LeafField string
+
+const (
+	// LeafFieldIndex is the position of the claimer in the tree, encoded as uint256
+	LeafFieldIndex LeafField = "index"
+	// LeafFieldClaimer is encoded as address
+	LeafFieldClaimer LeafField = "claimer"
+	// LeafFieldAmount is encoded as uint256
+	LeafFieldAmount LeafField = "amount"
+	// LeafFieldRound is encoded as uint256
+	LeafFieldRound LeafField = "round"
+)
+
+// LeafEncoding describes how the claim contract hashes a leaf
+type LeafEncoding struct {
+	Fields []LeafField `json:"fields" bson:"fields"`
+	// Packed uses abi.encodePacked, otherwise abi.encode
+	Packed bool `json:"packed" bson:"packed"`
+	// DoubleHash hashes the encoded leaf twice, as OpenZeppelin's StandardMerkleTree does
+	DoubleHash bool `json:"doubleHash" bson:"doubleHash"`
+}
+
+// DefaultLeafEncoding is keccak256(abi.encodePacked(claimer, amount))
+var DefaultLeafEncoding = LeafEncoding{
+	Fields: []LeafField{LeafFieldClaimer, LeafFieldAmount},
+	Packed: true,
+}
+
+func (e LeafEncoding) Validate() error {
+	if len(e.Fields) == 0 {
+		return ErrInvalidEncoding
+	}
+
+	seen := map[LeafField]bool{}
+	for _, f := range e.Fields {
+		switch f {
+		case LeafFieldIndex, LeafFieldClaimer, LeafFieldAmount, LeafFieldRound:
+		default:
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidEncoding, f)
+		}
+		if seen[f] {
+			return fmt.Errorf("%w: duplicated field %s", ErrInvalidEncoding, f)
+		}
+		seen[f] = true
+	}
+
+	if !seen[LeafFieldClaimer] || !seen[LeafFieldAmount] {
+		return fmt.Errorf("%w: claimer and amount are required", ErrInvalidEncoding)
+	}
+
+	return nil
+}
+
+// Leaf returns the hashed leaf of a claim
+func (e LeafEncoding) Leaf(index int, round int, claimer domain.Address, amount *big.Int) ([]byte, error) {
+	if amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, domain.ErrInvalidNumberFormat
+	}
+
+	encoded := []byte{}
+	for _, f := range e.Fields {
+		switch f {
+		case LeafFieldIndex:
+			encoded = append(encoded, math.U256Bytes(big.NewInt(int64(index)))...)
+		case LeafFieldClaimer:
+			address := common.HexToAddress(string(claimer)).Bytes()
+			if !e.Packed {
+				address = common.LeftPadBytes(address, 32)
+			}
+			encoded = append(encoded, address...)
+		case LeafFieldAmount:
+			encoded = append(encoded, math.U256Bytes(new(big.Int).Set(amount))...)
+		case LeafFieldRound:
+			encoded = append(encoded, math.U256Bytes(big.NewInt(int64(round)))...)
+		default:
+			return nil, ErrInvalidEncoding
+		}
+	}
+
+	leaf := crypto.Keccak256(encoded)
+	if e.DoubleHash {
+		leaf = crypto.Keccak256(leaf)
+	}
+	return leaf, nil
+}
+
+type AllocationSource string
+
+const (
+	// AllocationSourceListingRecords rewards listings in snapshots with the flat reward of promoted collections
+	AllocationSourceListingRecords AllocationSource = "listingRecords"
+	// AllocationSourcePromotion splits the RewardPerDistribution of the promotion by listing multipliers
+	AllocationSourcePromotion AllocationSource = "promotion"
+	// AllocationSourceCsv is an uploaded csv of `claimer,amount` rows
+	AllocationSourceCsv AllocationSource = "csv"
+	// AllocationSourceRewardLedger is the ledger of a listing reward distribution
+	AllocationSourceRewardLedger AllocationSource = "rewardLedger"
+)
+
+type Allocation struct {
+	Claimer domain.Address `json:"claimer"`
+	// Amount is in wei
+	Amount string `json:"amount"`
+}
+
+// ParseAllocations reads `claimer,amount` rows, a header row is skipped if presents
+func ParseAllocations(r io.Reader) ([]Allocation, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	res := []Allocation{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+		}
+
+		claimer, amount := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if line == 1 && !validator.IsValidAddress(claimer) {
+			// header
+			continue
+		}
+
+		if !validator.IsValidAddress(claimer) {
+			return nil, fmt.Errorf("%w: invalid claimer at line %d", domain.ErrBadParamInput, line)
+		}
+		if _, ok := new(big.Int).SetString(amount, 10); !ok {
+			return nil, fmt.Errorf("%w: invalid amount at line %d", domain.ErrBadParamInput, line)
+		}
+
+		res = append(res, Allocation{Claimer: domain.Address(claimer).ToLower(), Amount: amount})
+	}
+
+	return res, nil
+}
+
+type RoundStatus string
+
+const (
+	// RoundStatusBuilding is set before proofs are written, a round left building is rebuilt by the next build
+	RoundStatusBuilding RoundStatus = "building"
+	RoundStatusReady    RoundStatus = "ready"
+)
+
+// AirdropRound is a version of merkle tree of an airdrop, proofs of the round are stored with the same round number.
+// AirdropTypeOnce has a single round 0, AirdropTypeRound starts from round 1.
+type AirdropRound struct {
+	ChainId         domain.ChainId   `json:"chainId" bson:"chainId"`
+	ContractAddress domain.Address   `json:"contractAddress" bson:"contractAddress"`
+	Round           int              `json:"round" bson:"round"`
+	Root            string           `json:"root" bson:"root"`
+	Encoding        LeafEncoding     `json:"encoding" bson:"encoding"`
+	Source          AllocationSource `json:"source" bson:"source"`
+	ClaimerCount    int              `json:"claimerCount" bson:"claimerCount"`
+	TotalAmount     string           `json:"totalAmount" bson:"totalAmount"`
+	Status          RoundStatus      `json:"status" bson:"status"`
+	CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
+}
+
+type AirdropRoundFindAllOptions struct {
+	SortBy          *string         `bson:"-"`
+	SortDir         *domain.SortDir `bson:"-"`
+	Offset          *int32          `bson:"-"`
+	Limit           *int32          `bson:"-"`
+	ChainId         *domain.ChainId `bson:"chainId"`
+	ContractAddress *domain.Address `bson:"contractAddress"`
+	Round           *int            `bson:"round"`
+	Status          *RoundStatus    `bson:"status"`
+}
+
+type AirdropRoundFindAllOptionsFunc func(*AirdropRoundFindAllOptions) error
+
+func GetAirdropRoundFindAllOptions(opts ...AirdropRoundFindAllOptionsFunc) (AirdropRoundFindAllOptions, error) {
+	res := AirdropRoundFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func AirdropRoundWithSort(sortby string, sortdir domain.SortDir) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func AirdropRoundWithPagination(offset int32, limit int32) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func AirdropRoundWithChainId(chainId domain.ChainId) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.ChainId = &chainId
+		return nil
+	}
+}
+
+func AirdropRoundWithContractAddress(address domain.Address) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.ContractAddress = address.ToLowerPtr()
+		return nil
+	}
+}
+
+func AirdropRoundWithRound(round int) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.Round = &round
+		return nil
+	}
+}
+
+func AirdropRoundWithStatus(status RoundStatus) AirdropRoundFindAllOptionsFunc {
+	return func(options *AirdropRoundFindAllOptions) error {
+		options.Status = &status
+		return nil
+	}
+}
+
+type BuildParams struct {
+	ChainId         domain.ChainId   `json:"chainId"`
+	ContractAddress domain.Address   `json:"contractAddress"`
+	Source          AllocationSource `json:"source"`
+	// Begin and End are the snapshot window of listingRecords and promotion sources
+	Begin *time.Time `json:"begin"`
+	End   *time.Time `json:"end"`
+	// Allocations are required by csv source
+	Allocations []Allocation `json:"allocations"`
+	// DistributionId is required by rewardLedger source
+	DistributionId string `json:"distributionId"`
+	// Encoding uses DefaultLeafEncoding if not set
+	Encoding *LeafEncoding `json:"encoding"`
+}
+
+type VerifyParams struct {
+	ChainId         domain.ChainId `json:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress"`
+	Round           int            `json:"round"`
+	Index           int            `json:"index"`
+	Claimer         domain.Address `json:"claimer"`
+	Amount          string         `json:"amount"`
+	Proof           []string       `json:"proof"`
+}
+
+type AirdropRoundRepo interface {
+	FindAll(ctx.Ctx, ...AirdropRoundFindAllOptionsFunc) ([]AirdropRound, error)
+	// Upsert replaces the round by chainId, contractAddress and round
+	Upsert(ctx.Ctx, *AirdropRound) error
+}
+
+type AirdropBuilderUseCase interface {
+	// Build allocates rewards, builds merkle tree of the next round and writes proofs
+	Build(ctx.Ctx, BuildParams) (*AirdropRound, error)
+	// Verify checks the claim against the root of the round
+	Verify(ctx.Ctx, VerifyParams) (bool, error)
+	FindRounds(ctx.Ctx, ...AirdropRoundFindAllOptionsFunc) ([]AirdropRound, error)
+}
@@ -0,0 +1,62 @@
+package airdrop
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/x-xyz/goapi/domain"
+)
+
+func TestParseAllocations(t *testing.T) {
+	res, err := ParseAllocations(strings.NewReader("claimer,amount\n0xAbc0000000000000000000000000000000000001, 100\n0xabc0000000000000000000000000000000000002,200\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Allocation{
+		{Claimer: "0xabc0000000000000000000000000000000000001", Amount: "100"},
+		{Claimer: "0xabc0000000000000000000000000000000000002", Amount: "200"},
+	}, res)
+
+	_, err = ParseAllocations(strings.NewReader("0xabc0000000000000000000000000000000000001,1.5\n"))
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+	_, err = ParseAllocations(strings.NewReader("0xabc0000000000000000000000000000000000001,1\nbad,1\n"))
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestLeafEncodingValidate(t *testing.T) {
+	assert.NoError(t, DefaultLeafEncoding.Validate())
+	assert.ErrorIs(t, LeafEncoding{}.Validate(), ErrInvalidEncoding)
+	assert.ErrorIs(t, LeafEncoding{Fields: []LeafField{LeafFieldClaimer}}.Validate(), ErrInvalidEncoding)
+	assert.ErrorIs(t, LeafEncoding{Fields: []LeafField{LeafFieldClaimer, LeafFieldAmount, LeafFieldAmount}}.Validate(), ErrInvalidEncoding)
+	assert.ErrorIs(t, LeafEncoding{Fields: []LeafField{LeafFieldClaimer, LeafFieldAmount, "foo"}}.Validate(), ErrInvalidEncoding)
+}
+
+func TestLeaf(t *testing.T) {
+	claimer := domain.Address("0xabc0000000000000000000000000000000000001")
+	amount := big.NewInt(100)
+	address := common.HexToAddress(string(claimer)).Bytes()
+
+	// keccak256(abi.encodePacked(claimer, amount))
+	leaf, err := DefaultLeafEncoding.Leaf(3, 1, claimer, amount)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256(address, math.U256Bytes(big.NewInt(100))), leaf)
+
+	// keccak256(bytes.concat(keccak256(abi.encode(index, claimer, amount))))
+	leaf, err = LeafEncoding{
+		Fields:     []LeafField{LeafFieldIndex, LeafFieldClaimer, LeafFieldAmount},
+		DoubleHash: true,
+	}.Leaf(3, 1, claimer, amount)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256(crypto.Keccak256(
+		math.U256Bytes(big.NewInt(3)),
+		common.LeftPadBytes(address, 32),
+		math.U256Bytes(big.NewInt(100)),
+	)), leaf)
+
+	_, err = DefaultLeafEncoding.Leaf(0, 0, claimer, big.NewInt(-1))
+	assert.ErrorIs(t, err, domain.ErrInvalidNumberFormat)
+}
@@ -0,0 +1,277 @@
+package alert
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+const (
+	// DefaultCooldown is applied to rules created without a cooldown
+	DefaultCooldown = time.Hour
+	// MinCooldown is the shortest interval between two notifications of a rule
+	MinCooldown = time.Minute
+	// MaxRulesPerOwner is the number of rules an account can create
+	MaxRulesPerOwner = 100
+)
+
+var (
+	ErrTooManyRules = errors.New("too many alert rules")
+	// ErrStateConflict is returned if the state is updated by another evaluator since it's read
+	ErrStateConflict = errors.New("alert rule state changed")
+)
+
+type RuleType string
+
+const (
+	// RuleTypeCollectionFloorBelow fires when the collection floor crosses below Threshold
+	RuleTypeCollectionFloorBelow RuleType = "collectionFloorBelow"
+	// RuleTypeTraitFloorChange fires when the floor of a trait changes by Threshold in ratio, any change if 0
+	RuleTypeTraitFloorChange RuleType = "traitFloorChange"
+	// RuleTypeTokenListed fires on a new listing of the token, priced at or below Threshold if set
+	RuleTypeTokenListed RuleType = "tokenListed"
+	// RuleTypeTokenPriceChange fires when the lowest listing price changes by Threshold in ratio, any change if 0
+	RuleTypeTokenPriceChange RuleType = "tokenPriceChange"
+	// RuleTypeOfferReceived fires on a new best offer of the token above Threshold
+	RuleTypeOfferReceived RuleType = "offerReceived"
+)
+
+func (t RuleType) IsValid() bool {
+	switch t {
+	case RuleTypeCollectionFloorBelow, RuleTypeTraitFloorChange, RuleTypeTokenListed, RuleTypeTokenPriceChange, RuleTypeOfferReceived:
+		return true
+	}
+	return false
+}
+
+// IsTokenRule reports whether the rule watches a single token
+func (t RuleType) IsTokenRule() bool {
+	return t == RuleTypeTokenListed || t == RuleTypeTokenPriceChange || t == RuleTypeOfferReceived
+}
+
+// Rule is an alert rule of an account, prices are in native token
+type Rule struct {
+	Id              string         `json:"id" bson:"id"`
+	Owner           domain.Address `json:"owner" bson:"owner"`
+	Type            RuleType       `json:"type" bson:"type"`
+	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
+	// TokenId is required by token rules
+	TokenId domain.TokenId `json:"tokenId,omitempty" bson:"tokenId,omitempty"`
+	// TraitName and TraitValue are required by trait rules
+	TraitName  string `json:"traitName,omitempty" bson:"traitName,omitempty"`
+	TraitValue string `json:"traitValue,omitempty" bson:"traitValue,omitempty"`
+
+	Threshold       float64 `json:"threshold" bson:"threshold"`
+	CooldownSeconds int64   `json:"cooldownSeconds" bson:"cooldownSeconds"`
+	IsEnabled       bool    `json:"isEnabled" bson:"isEnabled"`
+
+	State     RuleState `json:"state" bson:"state"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+func (r *Rule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownSeconds) * time.Second
+}
+
+func (r *Rule) ToCollectionId() collection.CollectionId {
+	return collection.CollectionId{ChainId: r.ChainId, Address: r.ContractAddress}
+}
+
+func (r *Rule) ToNftItemId() nftitem.Id {
+	return nftitem.Id{ChainId: r.ChainId, ContractAddress: r.ContractAddress, TokenId: r.TokenId}
+}
+
+// RuleState is what the rule saw on last evaluation, Version is bumped on every change
+type RuleState struct {
+	Version int64 `json:"-" bson:"version"`
+	// Value is the last observed price, 0 if there was none
+	Value float64 `json:"value" bson:"value"`
+	// Key identifies the last notified listing or offer, so it's notified once
+	Key string `json:"-" bson:"key"`
+	// IsMet is true if the condition of a crossing rule held on last evaluation
+	IsMet       bool       `json:"isMet" bson:"isMet"`
+	TriggeredAt *time.Time `json:"triggeredAt,omitempty" bson:"triggeredAt,omitempty"`
+}
+
+// Observation is the current state of what a rule watches
+type Observation struct {
+	// Exists is false if there is no floor, listing or offer
+	Exists bool
+	Value  float64
+	// Key is the order item hash of the listing or offer
+	Key string
+}
+
+// Evaluate returns the next state of the rule and whether to notify. The state is unchanged if
+// the rule would fire within its cooldown, so that it fires once the cooldown ends.
+func (r *Rule) Evaluate(obs Observation, now time.Time) (RuleState, bool) {
+	next := r.State
+	fire := false
+
+	switch r.Type {
+	case RuleTypeCollectionFloorBelow:
+		met := obs.Exists && obs.Value < r.Threshold
+		fire = met && !r.State.IsMet
+		next.IsMet = met
+	case RuleTypeTokenListed:
+		met := obs.Exists && (r.Threshold == 0 || obs.Value <= r.Threshold)
+		fire = met && obs.Key != r.State.Key
+		if fire {
+			next.Key = obs.Key
+		}
+	case RuleTypeOfferReceived:
+		met := obs.Exists && obs.Value > r.Threshold
+		fire = met && obs.Key != r.State.Key
+		if fire {
+			next.Key = obs.Key
+		}
+	case RuleTypeTokenPriceChange, RuleTypeTraitFloorChange:
+		// the first observation is the baseline
+		fire = obs.Exists && r.State.Value != 0 && obs.Value != r.State.Value &&
+			math.Abs(obs.Value-r.State.Value)/r.State.Value >= r.Threshold
+	}
+
+	next.Value = 0
+	if obs.Exists {
+		next.Value = obs.Value
+	}
+	if isChangeRule := r.Type == RuleTypeTokenPriceChange || r.Type == RuleTypeTraitFloorChange; isChangeRule && obs.Exists && r.State.Value != 0 && !fire {
+		// change rules keep the baseline until they fire, so slow drifts are caught as well
+		next.Value = r.State.Value
+	}
+
+	if fire {
+		if r.State.TriggeredAt != nil && now.Sub(*r.State.TriggeredAt) < r.Cooldown() {
+			return r.State, false
+		}
+		next.TriggeredAt = &now
+	}
+
+	if next.Value != r.State.Value || next.Key != r.State.Key || next.IsMet != r.State.IsMet || fire {
+		next.Version = r.State.Version + 1
+	}
+	return next, fire
+}
+
+// Notification is delivered to the owner of a fired rule
+type Notification struct {
+	RuleId          string         `json:"ruleId"`
+	Owner           domain.Address `json:"owner"`
+	Type            RuleType       `json:"type"`
+	ChainId         domain.ChainId `json:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress"`
+	TokenId         domain.TokenId `json:"tokenId,omitempty"`
+	TraitName       string         `json:"traitName,omitempty"`
+	TraitValue      string         `json:"traitValue,omitempty"`
+	Threshold       float64        `json:"threshold"`
+	// PreviousValue is the price seen before, Value is the price which fired the rule
+	PreviousValue float64   `json:"previousValue"`
+	Value         float64   `json:"value"`
+	Time          time.Time `json:"time"`
+}
+
+// Notifier delivers notifications, e.g. by email, push or webhook
+type Notifier interface {
+	Notify(c ctx.Ctx, n *Notification) error
+}
+
+// Updater updates a rule, nil fields are left unchanged and a cooldown of 0 resets it to DefaultCooldown
+type Updater struct {
+	Threshold       *float64 `json:"threshold" bson:"threshold"`
+	CooldownSeconds *int64   `json:"cooldownSeconds" bson:"cooldownSeconds"`
+	IsEnabled       *bool    `json:"isEnabled" bson:"isEnabled"`
+}
+
+type FindAllOptions struct {
+	Owner           *domain.Address
+	IsEnabled       *bool
+	ChainId         *domain.ChainId
+	ContractAddress *domain.Address
+	TokenId         *domain.TokenId
+	Types           []RuleType
+}
+
+type FindAllOptionsFunc func(*FindAllOptions) error
+
+func GetFindAllOptions(opts ...FindAllOptionsFunc) (FindAllOptions, error) {
+	res := FindAllOptions{}
+
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func WithOwner(owner domain.Address) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		owner = owner.ToLower()
+		options.Owner = &owner
+		return nil
+	}
+}
+
+func WithIsEnabled(isEnabled bool) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.IsEnabled = &isEnabled
+		return nil
+	}
+}
+
+func WithCollection(id collection.CollectionId) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		address := id.Address.ToLower()
+		options.ChainId = &id.ChainId
+		options.ContractAddress = &address
+		return nil
+	}
+}
+
+func WithToken(id nftitem.Id) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		address := id.ContractAddress.ToLower()
+		options.ChainId = &id.ChainId
+		options.ContractAddress = &address
+		options.TokenId = &id.TokenId
+		return nil
+	}
+}
+
+func WithTypes(types ...RuleType) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Types = types
+		return nil
+	}
+}
+
+type Repo interface {
+	Insert(c ctx.Ctx, rule *Rule) error
+	FindAll(c ctx.Ctx, opts ...FindAllOptionsFunc) ([]*Rule, error)
+	FindOne(c ctx.Ctx, id string) (*Rule, error)
+	Update(c ctx.Ctx, id string, updater *Updater) error
+	// UpdateState replaces the state if it's still at version, returns ErrStateConflict otherwise
+	UpdateState(c ctx.Ctx, id string, version int64, state RuleState) error
+	Delete(c ctx.Ctx, id string) error
+}
+
+type UseCase interface {
+	Create(c ctx.Ctx, owner domain.Address, rule *Rule) (*Rule, error)
+	FindAll(c ctx.Ctx, owner domain.Address) ([]*Rule, error)
+	// FindOne returns domain.ErrNotFound if the rule isn't owned by owner
+	FindOne(c ctx.Ctx, owner domain.Address, id string) (*Rule, error)
+	Update(c ctx.Ctx, owner domain.Address, id string, updater *Updater) (*Rule, error)
+	Delete(c ctx.Ctx, owner domain.Address, id string) error
+
+	// EvaluateCollection evaluates enabled rules of the collection and its tokens
+	EvaluateCollection(c ctx.Ctx, id collection.CollectionId) error
+	// EvaluateToken evaluates enabled token rules of the token
+	EvaluateToken(c ctx.Ctx, id nftitem.Id) error
+}
@@ -0,0 +1,87 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateFloorCrossing(t *testing.T) {
+	now := time.Now()
+	r := Rule{Type: RuleTypeCollectionFloorBelow, Threshold: 1, CooldownSeconds: 60}
+
+	next, fire := r.Evaluate(Observation{Exists: true, Value: 1.5}, now)
+	assert.False(t, fire)
+	assert.Equal(t, int64(1), next.Version)
+
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 0.9}, now)
+	assert.True(t, fire)
+	assert.True(t, next.IsMet)
+
+	// stays below, fired already
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 0.8}, now)
+	assert.False(t, fire)
+
+	// back above and below again within cooldown
+	r.State = next
+	next, _ = r.Evaluate(Observation{Exists: true, Value: 1.2}, now)
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 0.7}, now.Add(time.Second))
+	assert.False(t, fire)
+	assert.Equal(t, r.State, next)
+
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 0.7}, now.Add(time.Minute))
+	assert.True(t, fire)
+}
+
+func TestEvaluateListingAndOffer(t *testing.T) {
+	now := time.Now()
+	r := Rule{Type: RuleTypeTokenListed, Threshold: 2}
+
+	_, fire := r.Evaluate(Observation{Exists: true, Value: 3, Key: "a"}, now)
+	assert.False(t, fire)
+
+	next, fire := r.Evaluate(Observation{Exists: true, Value: 2, Key: "a"}, now)
+	assert.True(t, fire)
+	assert.Equal(t, "a", next.Key)
+
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 2, Key: "a"}, now)
+	assert.False(t, fire)
+	assert.Equal(t, r.State, next)
+
+	o := Rule{Type: RuleTypeOfferReceived, Threshold: 1}
+	_, fire = o.Evaluate(Observation{Exists: true, Value: 1, Key: "b"}, now)
+	assert.False(t, fire)
+	_, fire = o.Evaluate(Observation{Exists: true, Value: 1.1, Key: "b"}, now)
+	assert.True(t, fire)
+}
+
+func TestEvaluatePriceChange(t *testing.T) {
+	now := time.Now()
+	r := Rule{Type: RuleTypeTokenPriceChange, Threshold: 0.1}
+
+	next, fire := r.Evaluate(Observation{Exists: true, Value: 10}, now)
+	assert.False(t, fire)
+	assert.Equal(t, float64(10), next.Value)
+
+	// small drifts keep the baseline
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 10.5}, now)
+	assert.False(t, fire)
+	assert.Equal(t, float64(10), next.Value)
+
+	r.State = next
+	next, fire = r.Evaluate(Observation{Exists: true, Value: 11}, now)
+	assert.True(t, fire)
+	assert.Equal(t, float64(11), next.Value)
+
+	// delisted resets the baseline
+	r.State = next
+	next, fire = r.Evaluate(Observation{}, now)
+	assert.False(t, fire)
+	assert.Equal(t, float64(0), next.Value)
+}
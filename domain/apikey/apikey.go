@@ -0,0 +1,162 @@
+package apikey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+// Header is the request header carrying the api key
+const Header = "X-API-Key"
+
+// RateWindow is the window of ApiKey.RateLimit
+const RateWindow = time.Minute
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrRevoked    = errors.New("api key revoked")
+	ErrNoScope    = errors.New("api key has no required scope")
+)
+
+type Scope string
+
+const (
+	// ScopeRead grants access to read only requests, graphql queries included
+	ScopeRead Scope = "read"
+	// ScopeWrite grants access to requests which mutate states
+	ScopeWrite Scope = "write"
+)
+
+func (s Scope) IsValid() bool {
+	return s == ScopeRead || s == ScopeWrite
+}
+
+type ApiKey struct {
+	Id string `json:"id" bson:"id"`
+	// Prefix is the first few characters of the raw key, for the owner to tell keys apart
+	Prefix string `json:"prefix" bson:"prefix"`
+	// Hash is the sha256 of the raw key, raw key is only returned once when issued
+	Hash  string         `json:"-" bson:"hash"`
+	Owner domain.Address `json:"owner" bson:"owner"`
+	Name  string         `json:"name" bson:"name"`
+
+	Scopes []Scope `json:"scopes" bson:"scopes"`
+	// RateLimit is the max requests per minute
+	RateLimit int `json:"rateLimit" bson:"rateLimit"`
+	// DailyQuota is the max requests in a UTC day, 0 for unlimited
+	DailyQuota int `json:"dailyQuota" bson:"dailyQuota"`
+
+	IsRevoked bool       `json:"isRevoked" bson:"isRevoked"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" bson:"createdAt"`
+}
+
+func (k *ApiKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuedKey is returned on issuing, the only time the raw key is visible
+type IssuedKey struct {
+	*ApiKey
+	Key string `json:"key"`
+}
+
+// Updater updates a key, nil fields are left unchanged
+type Updater struct {
+	Scopes     []Scope `json:"scopes" bson:"scopes"`
+	RateLimit  *int    `json:"rateLimit" bson:"rateLimit"`
+	DailyQuota *int    `json:"dailyQuota" bson:"dailyQuota"`
+	// update by usecase
+	IsRevoked *bool `json:"-" bson:"isRevoked"`
+	// update by usecase
+	RevokedAt *time.Time `json:"-" bson:"revokedAt"`
+}
+
+// Usage is the request count of a key in a UTC day
+type Usage struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// Hash returns the stored form of a raw key
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type FindAllOptions struct {
+	Owner     *domain.Address
+	IsRevoked *bool
+	Offset    *int32
+	Limit     *int32
+}
+
+type FindAllOptionsFunc func(*FindAllOptions) error
+
+func GetFindAllOptions(opts ...FindAllOptionsFunc) (FindAllOptions, error) {
+	res := FindAllOptions{}
+
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func WithOwner(owner domain.Address) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		owner = owner.ToLower()
+		options.Owner = &owner
+		return nil
+	}
+}
+
+func WithIsRevoked(isRevoked bool) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.IsRevoked = &isRevoked
+		return nil
+	}
+}
+
+func WithPagination(offset int32, limit int32) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+type Repo interface {
+	Insert(ctx ctx.Ctx, key *ApiKey) error
+	FindAll(ctx ctx.Ctx, opts ...FindAllOptionsFunc) ([]*ApiKey, error)
+	FindOne(ctx ctx.Ctx, id string) (*ApiKey, error)
+	FindOneByHash(ctx ctx.Ctx, hash string) (*ApiKey, error)
+	Update(ctx ctx.Ctx, id string, updater *Updater) error
+}
+
+type UseCase interface {
+	// Issue creates a key for owner, the returned raw key is not stored
+	Issue(ctx ctx.Ctx, owner domain.Address, name string, scopes []Scope) (*IssuedKey, error)
+	FindAll(ctx ctx.Ctx, opts ...FindAllOptionsFunc) ([]*ApiKey, error)
+	FindOne(ctx ctx.Ctx, id string) (*ApiKey, error)
+	Revoke(ctx ctx.Ctx, id string) error
+	UpdateLimits(ctx ctx.Ctx, id string, updater *Updater) (*ApiKey, error)
+
+	// Authenticate resolves a raw key, returns ErrInvalidKey or ErrRevoked if it's not usable
+	Authenticate(ctx ctx.Ctx, key string) (*ApiKey, error)
+	// IncrUsage bumps today's counter of the key and returns the count after increment
+	IncrUsage(ctx ctx.Ctx, id string) (int64, error)
+	// GetUsage returns the counters of recent days, latest first
+	GetUsage(ctx ctx.Ctx, id string, days int) ([]Usage, error)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/spam"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CollectionId struct {
@@ -16,9 +18,10 @@ type CollectionId struct {
 }
 
 type Collection struct {
-	ChainId       domain.ChainId   `json:"chainId" bson:"chainId"`
-	Erc721Address domain.Address   `json:"erc721Address" bson:"erc721Address"`
-	TokenType     domain.TokenType `json:"tokenType" bson:"tokenType"`
+	ObjectId      primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ChainId       domain.ChainId     `json:"chainId" bson:"chainId"`
+	Erc721Address domain.Address     `json:"erc721Address" bson:"erc721Address"`
+	TokenType     domain.TokenType   `json:"tokenType" bson:"tokenType"`
 	// contract register
 	Owner           domain.Address `json:"owner" bson:"owner"`
 	Email           string         `json:"email" bson:"email"`
@@ -55,6 +58,7 @@ type Collection struct {
 	// not available for nested attributes
 	// this field will be updated by indexer
 	Attributes                map[string]map[string]int64 `json:"attributes" bson:"attributes"`
+	AttributeRanges           map[string]AttributeRange   `json:"attributeRanges" bson:"attributeRanges"`
 	AttributesHash            string                      `json:"-" bson:"attributesHash"`
 	ShouldCalculateOpenrarity bool                        `json:"-" bson:"shouldCalculateOpenrarity"`
 	// num of owners, will be updated by indexer
@@ -83,6 +87,10 @@ type Collection struct {
 	EditableAccounts        []domain.Address              `json:"editableAccounts" bson:"editableAccounts"`
 	LastOpenseaEventIndexAt time.Time                     `json:"-" bson:"lastOpenseaEventIndexAt"`
 	TraitFloorPrice         map[string]map[string]float64 `json:"traitFloorPrice" bson:"traitFloorPrice"`
+	// spam likelihood from 0 to 1, will be updated by spam classifier
+	SpamScore    float64       `json:"spamScore" bson:"spamScore"`
+	SpamSignals  []spam.Signal `json:"spamSignals" bson:"spamSignals"`
+	SpamOverride spam.Override `json:"spamOverride" bson:"spamOverride"`
 }
 
 func (c *Collection) ToId() CollectionId {
@@ -139,16 +147,23 @@ type UpdatePayload struct {
 	InstagramHandle string         `json:"-" bson:"instagramHandle"`
 	MediumHandle    string         `json:"-" bson:"mediumHandle"`
 	Telegram        string         `json:"-" bson:"telegram"`
-	Royalty         float64        `json:"-" bson:"royalty"`
 	FeeRecipient    string         `json:"-" bson:"feeRecipient"`
 	Status          bool           `json:"-" bson:"status"`
 	// use pointer to prevent be ignored when making bson
-	IsAppropriate *bool `json:"-" bson:"isAppropriate"`
-	IsVerified    bool  `json:"-" bson:"isVerified"`
+	Royalty       *float64 `json:"-" bson:"royalty"`
+	IsAppropriate *bool    `json:"-" bson:"isAppropriate"`
+	IsVerified    bool     `json:"-" bson:"isVerified"`
+	// nil is ignored and empty slice clears the accounts
+	EditableAccounts []domain.Address `json:"-" bson:"editableAccounts"`
+	// spam score will be updated by spam classifier, and override by moderators
+	SpamScore    *float64      `json:"-" bson:"spamScore"`
+	SpamSignals  []spam.Signal `json:"-" bson:"spamSignals"`
+	SpamOverride spam.Override `json:"-" bson:"spamOverride"`
 	// supply and attributes will be updated by indexer
-	Supply         int64                       `json:"supply" bson:"supply"`
-	Attributes     map[string]map[string]int64 `json:"attributes" bson:"attributes"`
-	AttributesHash string                      `json:"-" bson:"attributesHash,omitempty"`
+	Supply          int64                       `json:"supply" bson:"supply"`
+	Attributes      map[string]map[string]int64 `json:"attributes" bson:"attributes"`
+	AttributeRanges map[string]AttributeRange   `json:"attributeRanges" bson:"attributeRanges"`
+	AttributesHash  string                      `json:"-" bson:"attributesHash,omitempty"`
 	// num of owners, will be updated by indexer
 	NumOwners         int64    `bson:"numOwners,omitempty"`
 	NumOwnersMovement *float64 `bson:"numOwnersMovement"`
@@ -173,6 +188,21 @@ type UpdatePayload struct {
 	TraitFloorPrice map[string]map[string]float64 `bson:"traitFloorPrice,omitempty"`
 }
 
+// AttributeRange is the min and max value of a number trait in a collection
+type AttributeRange struct {
+	Min float64 `json:"min" bson:"min"`
+	Max float64 `json:"max" bson:"max"`
+}
+
+func (r *AttributeRange) Add(v float64) {
+	if v < r.Min {
+		r.Min = v
+	}
+	if v > r.Max {
+		r.Max = v
+	}
+}
+
 type UpdateInfoPayload struct {
 	Email           *string  `json:"email" bson:"email"`
 	ColectionName   *string  `json:"collectionName" bson:"collectionName"`
@@ -232,8 +262,9 @@ type CollectionWithStatByAccount struct {
 }
 
 type ActivityResult struct {
-	Items []account.ActivityHistory `json:"items"`
-	Count int                       `json:"count"`
+	Items      []account.ActivityHistory `json:"items"`
+	Count      int                       `json:"count"`
+	NextCursor string                    `json:"nextCursor"`
 }
 
 type GlobalOfferStatRow struct {
@@ -269,6 +300,40 @@ type findAllOptions struct {
 	LikedBy          *domain.Address
 	ListedBy         *domain.Address
 	OfferedBy        *domain.Address
+	IsVerified       *bool
+	// IsSpam matches collections with spam score above the threshold
+	IsSpam *bool
+	// CollectionName matches the name case-insensitively
+	CollectionName *string
+	// Cursor enables keyset pagination, empty for the first page. Offset is ignored if Cursor is set
+	Cursor *string
+}
+
+// SortFields returns sort fields of the find, `_id` by default
+func (o findAllOptions) SortFields() []string {
+	if o.SortBy == nil || o.SortDir == nil {
+		return []string{"_id"}
+	}
+
+	sorts := []string{}
+	sortBy := *o.SortBy
+
+	// to keep empty data has lowest order by any way
+	switch sortBy {
+	case "floorPrice":
+		fallthrough
+	case "usdFloorPrice":
+		sorts = append(sorts, "-hasFloorPrice")
+	case "lastSoldAt":
+		sorts = append(sorts, "-hasBeenSold")
+	case "lastListedAt":
+		sorts = append(sorts, "-hasBeenListed")
+	}
+
+	if *o.SortDir == domain.SortDirDesc {
+		sortBy = "-" + sortBy
+	}
+	return append(sorts, sortBy)
 }
 
 type FindAllOptions func(*findAllOptions) error
@@ -329,6 +394,27 @@ func WithIsAppropriate(isAppropriate bool) FindAllOptions {
 	}
 }
 
+func WithIsVerified(isVerified bool) FindAllOptions {
+	return func(options *findAllOptions) error {
+		options.IsVerified = &isVerified
+		return nil
+	}
+}
+
+func WithIsSpam(isSpam bool) FindAllOptions {
+	return func(options *findAllOptions) error {
+		options.IsSpam = &isSpam
+		return nil
+	}
+}
+
+func WithCollectionName(name string) FindAllOptions {
+	return func(options *findAllOptions) error {
+		options.CollectionName = &name
+		return nil
+	}
+}
+
 func WithIsInternal(isInternal bool) FindAllOptions {
 	return func(options *findAllOptions) error {
 		options.IsInternal = &isInternal
@@ -414,9 +500,18 @@ func WithOfferedBy(address domain.Address) FindAllOptions {
 	}
 }
 
+func WithCursor(cursor string) FindAllOptions {
+	return func(options *findAllOptions) error {
+		options.Cursor = &cursor
+		return nil
+	}
+}
+
 type Repo interface {
 	FindAll(c ctx.Ctx, opts ...FindAllOptions) ([]*Collection, error)
 	Count(c ctx.Ctx, opts ...FindAllOptions) (int, error)
+	// NextCursor returns the cursor of the page after last, which is the last collection found with opts
+	NextCursor(c ctx.Ctx, last CollectionId, opts ...FindAllOptions) (string, error)
 	FindOne(c ctx.Ctx, id CollectionId) (*Collection, error)
 	Create(c ctx.Ctx, value CreatePayload) error
 	Upsert(c ctx.Ctx, value CreatePayload) error
@@ -444,12 +539,19 @@ type Usecase interface {
 	UpdateSaleStat(c ctx.Ctx, id CollectionId, priceInNative, priceInUsd float64, blkTime time.Time) error
 	UpdateLastListedAt(c ctx.Ctx, id CollectionId, blkTime time.Time) error
 	UpdateInfo(c ctx.Ctx, id CollectionId, info UpdateInfoPayload) error
+	// VerifyOwnership checks the account owns the collection contract on chain
+	VerifyOwnership(c ctx.Ctx, id CollectionId, account domain.Address) (*OwnershipProof, error)
+	UpdateRoyalty(c ctx.Ctx, id CollectionId, account domain.Address, payload RoyaltyPayload) error
+	UpdateEditableAccounts(c ctx.Ctx, id CollectionId, account domain.Address, accounts []domain.Address) error
 	UpdateLastOpenseaEventIndexAt(c ctx.Ctx, id CollectionId, t time.Time) error
 	UpdateTraitFloorPrice(c ctx.Ctx, id CollectionId, traitName, traitValue string, price float64) error
 	UpdateOpenseaFloorPrice(c ctx.Ctx, id CollectionId, price float64) error
 	GetCollectionStatByAccount(c ctx.Ctx, id CollectionId, account domain.Address) (*CollectionWithStatByAccount, error)
 	GetActivities(c ctx.Ctx, id CollectionId, optFns ...account.FindActivityHistoryOptions) (*ActivityResult, error)
 	GetGlobalOfferStats(c ctx.Ctx, id CollectionId) (*GlobalOfferStatResult, error)
+	GetHolderStats(c ctx.Ctx, id CollectionId) (*HolderStats, error)
+	// RefreshHolderStats recomputes holder stats from current holdings and transfer history
+	RefreshHolderStats(c ctx.Ctx, id CollectionId) error
 }
 
 func ToCollectionKey(chainId domain.ChainId, address domain.Address) string {
@@ -465,7 +567,9 @@ func FromCollectionKey(key string) (domain.ChainId, domain.Address, error) {
 	return domain.ChainId(chainId), domain.Address(parts[1]), nil
 }
 
+// SearchResult is a page of search, Count is 0 on pages after the first one when paging by cursor
 type SearchResult struct {
-	Items []*CollectionWithHoldingCount `json:"items"`
-	Count int                           `json:"count"`
+	Items      []*CollectionWithHoldingCount `json:"items"`
+	Count      int                           `json:"count"`
+	NextCursor string                        `json:"nextCursor"`
 }
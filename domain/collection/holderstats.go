@@ -0,0 +1,84 @@
+package collection
+
+import (
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+// HolderBucket is a range of numbers of items held by an account
+type HolderBucket string
+
+const (
+	HolderBucketOne       HolderBucket = "1"
+	HolderBucketTwoToFive HolderBucket = "2-5"
+	HolderBucketSixTo20   HolderBucket = "6-20"
+	HolderBucketOver20    HolderBucket = "21+"
+)
+
+// HolderBuckets are in ascending order
+var HolderBuckets = []HolderBucket{HolderBucketOne, HolderBucketTwoToFive, HolderBucketSixTo20, HolderBucketOver20}
+
+func ToHolderBucket(items int64) HolderBucket {
+	switch {
+	case items <= 1:
+		return HolderBucketOne
+	case items <= 5:
+		return HolderBucketTwoToFive
+	case items <= 20:
+		return HolderBucketSixTo20
+	default:
+		return HolderBucketOver20
+	}
+}
+
+type HolderDistribution struct {
+	Bucket  HolderBucket `json:"bucket" bson:"bucket"`
+	Holders int64        `json:"holders" bson:"holders"`
+	Items   int64        `json:"items" bson:"items"`
+}
+
+type TopHolder struct {
+	Address domain.Address `json:"address" bson:"address"`
+	Items   int64          `json:"items" bson:"items"`
+	// Share of all held items, ie: 2.5% = 0.025
+	Share float64 `json:"share" bson:"share"`
+}
+
+// HolderChurn is the accounts starting and stopping to hold any item within the period
+type HolderChurn struct {
+	Period   PeriodType `json:"period" bson:"period"`
+	New      int64      `json:"new" bson:"new"`
+	Departed int64      `json:"departed" bson:"departed"`
+}
+
+// HolderStats is precomputed from nftitems or erc1155 holdings and transfer history
+type HolderStats struct {
+	ChainId      domain.ChainId       `json:"chainId" bson:"chainId"`
+	Address      domain.Address       `json:"address" bson:"address"`
+	NumHolders   int64                `json:"numHolders" bson:"numHolders"`
+	NumItems     int64                `json:"numItems" bson:"numItems"`
+	Distribution []HolderDistribution `json:"distribution" bson:"distribution"`
+	TopHolders   []TopHolder          `json:"topHolders" bson:"topHolders"`
+	// Concentration is the gini coefficient of items held by holders, 0 if evenly held and close to 1 if held by a few
+	Concentration float64 `json:"concentration" bson:"concentration"`
+	// AvgHoldingDays is the average days since the holder received the item, items without transfer history are excluded
+	AvgHoldingDays float64       `json:"avgHoldingDays" bson:"avgHoldingDays"`
+	Churn          []HolderChurn `json:"churn" bson:"churn"`
+	UpdatedAt      time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (s *HolderStats) ToId() HolderStatsId {
+	return HolderStatsId{ChainId: s.ChainId, Address: s.Address}
+}
+
+type HolderStatsId struct {
+	ChainId domain.ChainId `json:"chainId" bson:"chainId"`
+	Address domain.Address `json:"address" bson:"address"`
+}
+
+type HolderStatsRepo interface {
+	FindOne(ctx.Ctx, HolderStatsId) (*HolderStats, error)
+	Upsert(ctx.Ctx, HolderStats) error
+}
@@ -0,0 +1,51 @@
+package collection
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-xyz/goapi/domain"
+)
+
+var (
+	ErrNotCollectionOwner = errors.New("not collection owner")
+	ErrInvalidRoyalty     = errors.New("invalid royalty")
+)
+
+// OwnershipMethod is how the ownership of a collection contract is proven
+type OwnershipMethod string
+
+const (
+	// OwnershipMethodOwnable is owner() of Ownable
+	OwnershipMethodOwnable OwnershipMethod = "ownable"
+	// OwnershipMethodAccessControl is DEFAULT_ADMIN_ROLE of AccessControl
+	OwnershipMethodAccessControl OwnershipMethod = "accessControl"
+	// OwnershipMethodDeployer is the account deploying the contract,
+	// the account is proven by the signed message of the request
+	OwnershipMethodDeployer OwnershipMethod = "deployer"
+)
+
+type OwnershipProof struct {
+	ChainId    domain.ChainId  `json:"chainId"`
+	Address    domain.Address  `json:"address"`
+	Account    domain.Address  `json:"account"`
+	Method     OwnershipMethod `json:"method"`
+	VerifiedAt time.Time       `json:"verifiedAt"`
+}
+
+// RoyaltyPayload is the royalty in percentage, ie: 2.5% = 2.5
+type RoyaltyPayload struct {
+	Royalty      float64        `json:"royalty"`
+	FeeRecipient domain.Address `json:"feeRecipient"`
+}
+
+func (p RoyaltyPayload) Validate() error {
+	if p.Royalty < 0 || p.Royalty > 100 {
+		return ErrInvalidRoyalty
+	}
+	if p.Royalty > 0 && !common.IsHexAddress(p.FeeRecipient.ToLowerStr()) {
+		return domain.ErrInvalidAddress
+	}
+	return nil
+}
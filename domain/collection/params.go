@@ -30,6 +30,10 @@ type SearchParams struct {
 	LikedBy   *domain.Address `query:"likedBy"`
 	ListedBy  *domain.Address `query:"listedBy"`
 	OfferedBy *domain.Address `query:"offeredBy"`
+	// if cursor != nil, result is paged by cursor instead of offset, empty cursor for the first page
+	Cursor *string `query:"cursor"`
+	// spam collections are hidden unless IncludeSpam is true
+	IncludeSpam *bool `query:"includeSpam"`
 }
 
 type SearchSortOption = string
@@ -37,6 +37,11 @@ type Registration struct {
 	Royalty         float64           `json:"royalty" bson:"royalty"`
 	FeeRecipient    string            `json:"feeRecipient" bson:"feeRecipient"`
 	State           RegistrationState `json:"-" bson:"state"`
+	Symbol          string            `json:"symbol" bson:"symbol"`
+	// Discovered is true if the registration is created by contract discovery instead of the owner
+	Discovered bool `json:"discovered" bson:"discovered"`
+	// SpamScore is the spam likelihood from 0 to 1 of a discovered contract
+	SpamScore float64 `json:"spamScore" bson:"spamScore"`
 }
 
 type UpdateRegistration struct {
@@ -48,4 +53,7 @@ type RegistrationRepo interface {
 	FindOne(c ctx.Ctx, id CollectionId) (*Registration, error)
 	Create(c ctx.Ctx, value Registration) error
 	Patch(c ctx.Ctx, id CollectionId, value UpdateRegistration) error
+	// PatchSpamScore updates the spam score without touching the state of the registration
+	PatchSpamScore(c ctx.Ctx, id CollectionId, score float64) error
+	Remove(c ctx.Ctx, id CollectionId) error
 }
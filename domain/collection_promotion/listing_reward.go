@@ -0,0 +1,206 @@
+package collection_promotion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+var ErrDistributionNotClosed = errors.New("distribution period not closed")
+
+// FloorBand is the max relative distance to floor price of a qualifying listing
+const FloorBand = 0.25
+
+// ListingWeight is 1 for a listing at floor price and decreases linearly to 0 at FloorBand away from it, listings
+// out of the band or of a collection without floor price don't qualify
+func ListingWeight(price float64, floor float64) float64 {
+	if floor <= 0 || price <= 0 {
+		return 0
+	}
+	distance := math.Abs(price-floor) / floor
+	if distance >= FloorBand {
+		return 0
+	}
+	return 1 - distance/FloorBand
+}
+
+type SnapshotMode string
+
+const (
+	// SnapshotModeFixed snapshots at the beginning of every interval
+	SnapshotModeFixed SnapshotMode = "fixed"
+	// SnapshotModeRandom snapshots at a random time of every interval, so listing right before a known snapshot time
+	// doesn't pay off
+	SnapshotModeRandom SnapshotMode = "random"
+)
+
+func (m SnapshotMode) IsValid() bool {
+	return m == SnapshotModeFixed || m == SnapshotModeRandom
+}
+
+// RewardDistribution is the header of the ledger of a distribution period. RewardPerDistribution of the promotion is
+// split equally to every snapshot slot of the period, and each snapshot shares its slot by listing weights. Slots
+// without snapshot are left unallocated.
+type RewardDistribution struct {
+	Id              string          `json:"id" bson:"id"`
+	PromotionId     string          `json:"promotionId" bson:"promotionId"`
+	Begin           time.Time       `json:"begin" bson:"begin"`
+	End             time.Time       `json:"end" bson:"end"`
+	SnapshotSlots   int             `json:"snapshotSlots" bson:"snapshotSlots"`
+	SnapshotTimes   []time.Time     `json:"snapshotTimes" bson:"snapshotTimes"`
+	TotalReward     string          `json:"totalReward" bson:"totalReward"`
+	AllocatedReward string          `json:"allocatedReward" bson:"allocatedReward"`
+	OwnerCount      int             `json:"ownerCount" bson:"ownerCount"`
+	Collections     []CollPromotion `json:"collections" bson:"collections"`
+	CreatedAt       time.Time       `json:"createdAt" bson:"createdAt"`
+}
+
+func DistributionId(promotionId string, begin time.Time) string {
+	return fmt.Sprintf("%s-%d", promotionId, begin.Unix())
+}
+
+// RewardLedgerEntry is the reward of an owner in a distribution
+type RewardLedgerEntry struct {
+	DistributionId string         `json:"distributionId" bson:"distributionId"`
+	PromotionId    string         `json:"promotionId" bson:"promotionId"`
+	Owner          domain.Address `json:"owner" bson:"owner"`
+	// Amount is in wei
+	Amount string `json:"amount" bson:"amount"`
+	// Weight is the sum of listing weights times collection multipliers in all snapshots
+	Weight float64 `json:"weight" bson:"weight"`
+	// Listings is the count of qualifying listings in all snapshots by collection
+	Listings  map[domain.Address]int `json:"listings" bson:"listings"`
+	CreatedAt time.Time              `json:"createdAt" bson:"createdAt"`
+}
+
+type RewardDistributionFindAllOptions struct {
+	SortBy      *string         `bson:"-"`
+	SortDir     *domain.SortDir `bson:"-"`
+	Offset      *int32          `bson:"-"`
+	Limit       *int32          `bson:"-"`
+	Id          *string         `bson:"id"`
+	PromotionId *string         `bson:"promotionId"`
+}
+
+type RewardDistributionFindAllOptionsFunc func(*RewardDistributionFindAllOptions) error
+
+func GetRewardDistributionFindAllOptions(opts ...RewardDistributionFindAllOptionsFunc) (RewardDistributionFindAllOptions, error) {
+	res := RewardDistributionFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func RewardDistributionWithSort(sortby string, sortdir domain.SortDir) RewardDistributionFindAllOptionsFunc {
+	return func(options *RewardDistributionFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func RewardDistributionWithPagination(offset int32, limit int32) RewardDistributionFindAllOptionsFunc {
+	return func(options *RewardDistributionFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func RewardDistributionWithId(id string) RewardDistributionFindAllOptionsFunc {
+	return func(options *RewardDistributionFindAllOptions) error {
+		options.Id = &id
+		return nil
+	}
+}
+
+func RewardDistributionWithPromotionId(promotionId string) RewardDistributionFindAllOptionsFunc {
+	return func(options *RewardDistributionFindAllOptions) error {
+		options.PromotionId = &promotionId
+		return nil
+	}
+}
+
+type RewardLedgerFindAllOptions struct {
+	SortBy         *string         `bson:"-"`
+	SortDir        *domain.SortDir `bson:"-"`
+	Offset         *int32          `bson:"-"`
+	Limit          *int32          `bson:"-"`
+	DistributionId *string         `bson:"distributionId"`
+	Owner          *domain.Address `bson:"owner"`
+}
+
+type RewardLedgerFindAllOptionsFunc func(*RewardLedgerFindAllOptions) error
+
+func GetRewardLedgerFindAllOptions(opts ...RewardLedgerFindAllOptionsFunc) (RewardLedgerFindAllOptions, error) {
+	res := RewardLedgerFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func RewardLedgerWithSort(sortby string, sortdir domain.SortDir) RewardLedgerFindAllOptionsFunc {
+	return func(options *RewardLedgerFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func RewardLedgerWithPagination(offset int32, limit int32) RewardLedgerFindAllOptionsFunc {
+	return func(options *RewardLedgerFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func RewardLedgerWithDistributionId(id string) RewardLedgerFindAllOptionsFunc {
+	return func(options *RewardLedgerFindAllOptions) error {
+		options.DistributionId = &id
+		return nil
+	}
+}
+
+func RewardLedgerWithOwner(owner domain.Address) RewardLedgerFindAllOptionsFunc {
+	return func(options *RewardLedgerFindAllOptions) error {
+		options.Owner = owner.ToLowerPtr()
+		return nil
+	}
+}
+
+type RewardDistributionRepo interface {
+	FindAll(ctx.Ctx, ...RewardDistributionFindAllOptionsFunc) ([]RewardDistribution, error)
+	// Upsert replaces the distribution by id
+	Upsert(ctx.Ctx, *RewardDistribution) error
+	// Remove removes the distribution by id, it's not an error if it doesn't exist
+	Remove(c ctx.Ctx, id string) error
+}
+
+type RewardLedgerRepo interface {
+	FindAll(ctx.Ctx, ...RewardLedgerFindAllOptionsFunc) ([]RewardLedgerEntry, error)
+	// BulkUpsert replaces entries by distributionId and owner
+	BulkUpsert(ctx.Ctx, []RewardLedgerEntry) error
+	// RemoveAll removes entries of the distribution
+	RemoveAll(c ctx.Ctx, distributionId string) error
+}
+
+type ListingRewardUseCase interface {
+	// Snapshot records qualifying listings of promoted collections at the time
+	Snapshot(c ctx.Ctx, ts time.Time) error
+	// Distribute allocates rewards of the closed period by snapshots in it and writes the ledger, distributing the
+	// same period again rewrites it
+	Distribute(c ctx.Ctx, begin time.Time, end time.Time) (*RewardDistribution, error)
+	FindDistributions(ctx.Ctx, ...RewardDistributionFindAllOptionsFunc) ([]RewardDistribution, error)
+	FindLedger(ctx.Ctx, ...RewardLedgerFindAllOptionsFunc) ([]RewardLedgerEntry, error)
+}
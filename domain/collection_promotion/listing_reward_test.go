@@ -0,0 +1,18 @@
+package collection_promotion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListingWeight(t *testing.T) {
+	assert.Equal(t, 1.0, ListingWeight(10, 10))
+	assert.InDelta(t, 0.6, ListingWeight(11, 10), 1e-9)
+	assert.InDelta(t, 0.6, ListingWeight(9, 10), 1e-9)
+	assert.InDelta(t, 0.2, ListingWeight(12, 10), 1e-9)
+	assert.Equal(t, 0.0, ListingWeight(12.5, 10))
+	assert.Equal(t, 0.0, ListingWeight(7, 10))
+	// no floor price
+	assert.Equal(t, 0.0, ListingWeight(10, 0))
+}
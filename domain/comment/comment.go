@@ -0,0 +1,200 @@
+package comment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+)
+
+const (
+	MaxBodyLength = 2000
+	// EditWindow is how long the author can edit a comment after posting
+	EditWindow = 15 * time.Minute
+	// DeleteWindow is how long the author can delete a comment after posting, moderators can remove at any time
+	DeleteWindow = 24 * time.Hour
+)
+
+var (
+	ErrInvalidBody        = errors.New("invalid comment body")
+	ErrInvalidParent      = errors.New("invalid parent comment")
+	ErrInvalidReaction    = errors.New("invalid reaction")
+	ErrNotAuthor          = errors.New("not the author of the comment")
+	ErrEditWindowPassed   = errors.New("comment can't be edited anymore")
+	ErrDeleteWindowPassed = errors.New("comment can't be deleted anymore")
+	ErrRateLimited        = errors.New("posting too fast")
+	ErrBanned             = errors.New("account is banned")
+	ErrReactionExists     = errors.New("reaction already exists")
+)
+
+type Reaction string
+
+const (
+	ReactionLike   Reaction = "like"
+	ReactionLove   Reaction = "love"
+	ReactionLaugh  Reaction = "laugh"
+	ReactionFire   Reaction = "fire"
+	ReactionRocket Reaction = "rocket"
+)
+
+func (r Reaction) IsValid() bool {
+	switch r {
+	case ReactionLike, ReactionLove, ReactionLaugh, ReactionFire, ReactionRocket:
+		return true
+	}
+	return false
+}
+
+// Thread is the discussion of a token, or of a collection if TokenId is empty
+type Thread struct {
+	ChainId         domain.ChainId `json:"chainId" bson:"chainId" param:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress" param:"contract"`
+	TokenId         domain.TokenId `json:"tokenId,omitempty" bson:"tokenId" param:"tokenId"`
+}
+
+func (t Thread) Normalize() Thread {
+	t.ContractAddress = t.ContractAddress.ToLower()
+	return t
+}
+
+func (t Thread) ToCollectionId() collection.CollectionId {
+	return collection.CollectionId{ChainId: t.ChainId, Address: t.ContractAddress}
+}
+
+type Comment struct {
+	Id     string `json:"id" bson:"id"`
+	Thread Thread `json:"thread" bson:"thread"`
+	// ParentId is set on replies, replies are one level deep
+	ParentId   string             `json:"parentId,omitempty" bson:"parentId"`
+	Author     domain.Address     `json:"author" bson:"author"`
+	Body       string             `json:"body" bson:"body"`
+	ReplyCount int64              `json:"replyCount" bson:"replyCount"`
+	Reactions  map[Reaction]int64 `json:"reactions" bson:"reactions"`
+	// IsDeleted is set by the author and IsRemoved by moderators, the body of both is cleared
+	IsDeleted bool       `json:"isDeleted" bson:"isDeleted"`
+	IsRemoved bool       `json:"isRemoved" bson:"isRemoved"`
+	EditedAt  *time.Time `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" bson:"createdAt"`
+}
+
+func (c *Comment) IsVisible() bool {
+	return !c.IsDeleted && !c.IsRemoved
+}
+
+type CommentWithDetail struct {
+	*Comment
+	AuthorAccount *account.SimpleAccount `json:"authorAccount,omitempty"`
+	// Holds is the number of tokens of the collection the author holds now
+	Holds int64 `json:"holds"`
+	// MyReactions are the reactions of the signed in account
+	MyReactions []Reaction `json:"myReactions,omitempty"`
+}
+
+type SearchResult struct {
+	Items []*CommentWithDetail `json:"items"`
+	Count int                  `json:"count"`
+}
+
+// Updater updates a comment, nil fields are left unchanged
+type Updater struct {
+	Body      *string    `bson:"body"`
+	EditedAt  *time.Time `bson:"editedAt"`
+	IsDeleted *bool      `bson:"isDeleted"`
+	IsRemoved *bool      `bson:"isRemoved"`
+}
+
+type ReactionRecord struct {
+	CommentId string         `json:"commentId" bson:"commentId"`
+	Account   domain.Address `json:"account" bson:"account"`
+	Reaction  Reaction       `json:"reaction" bson:"reaction"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+}
+
+type FindAllOptions struct {
+	Thread   *Thread
+	ParentId *string
+	// IsListed excludes deleted and removed comments, unless they have replies to keep threads readable
+	IsListed *bool
+	Offset   *int32
+	Limit    *int32
+}
+
+type FindAllOptionsFunc func(*FindAllOptions) error
+
+func GetFindAllOptions(opts ...FindAllOptionsFunc) (FindAllOptions, error) {
+	res := FindAllOptions{}
+
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func WithThread(thread Thread) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		thread = thread.Normalize()
+		options.Thread = &thread
+		return nil
+	}
+}
+
+// WithParentId finds replies of the comment, or top level comments if parentId is empty
+func WithParentId(parentId string) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.ParentId = &parentId
+		return nil
+	}
+}
+
+func WithIsListed(isListed bool) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.IsListed = &isListed
+		return nil
+	}
+}
+
+func WithPagination(offset int32, limit int32) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+type Repo interface {
+	Insert(c ctx.Ctx, comment *Comment) error
+	FindOne(c ctx.Ctx, id string) (*Comment, error)
+	FindAll(c ctx.Ctx, opts ...FindAllOptionsFunc) ([]*Comment, error)
+	Count(c ctx.Ctx, opts ...FindAllOptionsFunc) (int, error)
+	Update(c ctx.Ctx, id string, updater *Updater) error
+	IncreaseReplyCount(c ctx.Ctx, id string, count int) error
+	// IncreaseReactionCount returns the comment after increment
+	IncreaseReactionCount(c ctx.Ctx, id string, reaction Reaction, count int) (*Comment, error)
+}
+
+type ReactionRepo interface {
+	// Insert returns ErrReactionExists if the account reacted already
+	Insert(c ctx.Ctx, record *ReactionRecord) error
+	// Delete returns domain.ErrNotFound if the account didn't react
+	Delete(c ctx.Ctx, commentId string, account domain.Address, reaction Reaction) error
+	FindAll(c ctx.Ctx, account domain.Address, commentIds []string) ([]*ReactionRecord, error)
+}
+
+type UseCase interface {
+	// Find returns top level comments of the thread if parentId is empty, or replies of the comment otherwise,
+	// viewer is optional and is used to resolve MyReactions
+	Find(c ctx.Ctx, thread Thread, parentId string, viewer *domain.Address, offset, limit int32) (*SearchResult, error)
+	Post(c ctx.Ctx, author domain.Address, thread Thread, parentId, body string) (*CommentWithDetail, error)
+	Edit(c ctx.Ctx, author domain.Address, thread Thread, id, body string) (*Comment, error)
+	Delete(c ctx.Ctx, author domain.Address, thread Thread, id string) error
+	// Remove hides the comment by a moderator and writes an audit log
+	Remove(c ctx.Ctx, moderator domain.Address, thread Thread, id, reason string) error
+	React(c ctx.Ctx, address domain.Address, thread Thread, id string, reaction Reaction) (*Comment, error)
+	Unreact(c ctx.Ctx, address domain.Address, thread Thread, id string, reaction Reaction) (*Comment, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+// EnsProfile is the ens name of an address with its text records
+type EnsProfile struct {
+	Name string `json:"name"`
+	// Avatar is the http url resolved from avatar text record
+	Avatar  string `json:"avatar,omitempty"`
+	Twitter string `json:"twitter,omitempty"`
+	Url     string `json:"url,omitempty"`
+}
@@ -69,4 +69,7 @@ type HoldingRepo interface {
 	Delete(c ctx.Ctx, id HoldingId) error
 	Increment(c ctx.Ctx, id HoldingId, value int64) (*Holding, error)
 	CountUniqueOwner(c ctx.Ctx, chainId domain.ChainId, address domain.Address) (int64, error)
+	// SumBalanceByOwners sums balances of the collection held by each of owners in one aggregation, owners holding none
+	// are omitted
+	SumBalanceByOwners(c ctx.Ctx, chainId domain.ChainId, address domain.Address, owners []domain.Address) (map[domain.Address]int64, error)
 }
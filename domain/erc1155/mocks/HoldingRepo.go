@@ -3,12 +3,10 @@
 package mocks
 
 import (
+	mock "github.com/stretchr/testify/mock"
 	ctx "github.com/x-xyz/goapi/base/ctx"
 	domain "github.com/x-xyz/goapi/domain"
-
 	erc1155 "github.com/x-xyz/goapi/domain/erc1155"
-
-	mock "github.com/stretchr/testify/mock"
 )
 
 // HoldingRepo is an autogenerated mock type for the HoldingRepo type
@@ -141,6 +139,29 @@ func (_m *HoldingRepo) Increment(c ctx.Ctx, id erc1155.HoldingId, value int64) (
 	return r0, r1
 }
 
+// SumBalanceByOwners provides a mock function with given fields: c, chainId, address, owners
+func (_m *HoldingRepo) SumBalanceByOwners(c ctx.Ctx, chainId domain.ChainId, address domain.Address, owners []domain.Address) (map[domain.Address]int64, error) {
+	ret := _m.Called(c, chainId, address, owners)
+
+	var r0 map[domain.Address]int64
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.ChainId, domain.Address, []domain.Address) map[domain.Address]int64); ok {
+		r0 = rf(c, chainId, address, owners)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[domain.Address]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.ChainId, domain.Address, []domain.Address) error); ok {
+		r1 = rf(c, chainId, address, owners)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewHoldingRepo interface {
 	mock.TestingT
 	Cleanup(func())
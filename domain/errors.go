@@ -22,6 +22,7 @@ var (
 	ErrInvalidOrderNonce           = errors.New("invalid order nonce")
 	ErrInvalidOrderSideForStrategy = errors.New("invalid order side for strategy")
 	ErrInvalidCurrency             = errors.New("invalid currency")
+	ErrInvalidCursor               = errors.New("invalid cursor")
 
 	// request error
 	ErrInvalidAddress   = errors.New("Invalid address")
@@ -1,12 +1,25 @@
 package external_listing
 
 import (
+	"errors"
 	"time"
 
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
 )
 
+var ErrUnsupportedSource = errors.New("unsupported marketplace source")
+
+type Source string
+
+const (
+	SourceOpensea   Source = "opensea"
+	SourceLooksRare Source = "looksrare"
+	SourceX2Y2      Source = "x2y2"
+)
+
+// ExternalListing is a listing on another marketplace, Price and PriceInUsd are display prices in the native token
+// and in USD, PriceInNative is Price as a number for sorting
 type ExternalListing struct {
 	Owner           domain.Address `json:"owner" bson:"owner"`
 	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
@@ -17,9 +30,10 @@ type ExternalListing struct {
 	PaymentToken    domain.Address `json:"paymentToken" bson:"paymentToken"`
 	Price           string         `json:"price" bson:"price"`
 	PriceInUsd      string         `json:"priceInUsd" bson:"priceInUSD"`
+	PriceInNative   float64        `json:"priceInNative" bson:"priceInNative"`
 	StartTime       time.Time      `json:"startTime" bson:"startTime"`
 	Deadline        time.Time      `json:"deadline" bson:"deadline"`
-	Source          string         `json:"source" bson:"source"`
+	Source          Source         `json:"source" bson:"source"`
 	UpdatedTime     time.Time      `json:"updatedTime" bson:"updatedTime"`
 }
 
@@ -47,6 +61,7 @@ func GetFindAllOptions(opts ...FindAllOptionsFunc) (FindAllOptions, error) {
 type RemoveAllOptions struct {
 	Owner   *domain.Address `bson:"owner"`
 	ChainId *domain.ChainId `bson:"chainId"`
+	Source  *Source         `bson:"source"`
 }
 
 type RemoveAllOptionsFunc func(*RemoveAllOptions) error
@@ -69,6 +84,16 @@ func WithExternalListing(owner domain.Address, chainId domain.ChainId) RemoveAll
 	}
 }
 
+// WithExternalListingSource removes listings of the owner on a single marketplace
+func WithExternalListingSource(owner domain.Address, chainId domain.ChainId, source Source) RemoveAllOptionsFunc {
+	return func(options *RemoveAllOptions) error {
+		options.Owner = &owner
+		options.ChainId = &chainId
+		options.Source = &source
+		return nil
+	}
+}
+
 func WithOwner(owner domain.Address) FindAllOptionsFunc {
 	return func(options *FindAllOptions) error {
 		options.Owner = &owner
@@ -83,13 +108,46 @@ func WithChainId(chainId domain.ChainId) FindAllOptionsFunc {
 	}
 }
 
+func WithContractAddress(address domain.Address) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.ContractAddress = address.ToLowerPtr()
+		return nil
+	}
+}
+
+func WithSort(sortBy string, sortDir domain.SortDir) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.SortBy = &sortBy
+		options.SortDir = &sortDir
+		return nil
+	}
+}
+
+func WithPagination(offset int32, limit int32) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+// WithActiveAt finds listings started and not expired at the time with quantity left
+func WithActiveAt(at time.Time) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.ActiveAt = &at
+		return nil
+	}
+}
+
 type FindAllOptions struct {
-	SortBy  *string
-	SortDir *domain.SortDir
-	Offset  *int32
-	Limit   *int32
-	ChainId *domain.ChainId `bson:"chainId"`
-	Owner   *domain.Address
+	SortBy          *string         `bson:"-"`
+	SortDir         *domain.SortDir `bson:"-"`
+	Offset          *int32          `bson:"-"`
+	Limit           *int32          `bson:"-"`
+	ChainId         *domain.ChainId `bson:"chainId"`
+	Owner           *domain.Address
+	ContractAddress *domain.Address `bson:"contractAddress"`
+	ActiveAt        *time.Time      `bson:"-"`
 }
 
 type ExternalListingRepo interface {
@@ -98,9 +156,46 @@ type ExternalListingRepo interface {
 	RemoveAll(c ctx.Ctx, opts ...RemoveAllOptionsFunc) error
 }
 
+// Marketplace is a source of external listings, one implementation per marketplace
+type Marketplace interface {
+	Source() Source
+	// GetListings returns the active listings of the account normalized into ExternalListing
+	GetListings(c ctx.Ctx, account domain.Address, chainId domain.ChainId) ([]ExternalListing, error)
+	// GetFloor returns the cheapest active listing of the collection on the marketplace, domain.ErrNotFound if the
+	// collection isn't listed
+	GetFloor(c ctx.Ctx, chainId domain.ChainId, collection domain.Address) (*SourceFloor, error)
+}
+
+// SourceFloor is the cheapest active listing of a collection on a marketplace, TokenId is empty if the marketplace
+// only reports the floor price
+type SourceFloor struct {
+	Source        Source         `json:"source"`
+	TokenId       domain.TokenId `json:"tokenId"`
+	PriceInNative float64        `json:"priceInNative"`
+	PriceInUsd    float64        `json:"priceInUsd"`
+}
+
+// Floor is the cross-marketplace floor of a collection, the cheapest of all sources
+type Floor struct {
+	ChainId       domain.ChainId `json:"chainId"`
+	Collection    domain.Address `json:"collection"`
+	Source        Source         `json:"source"`
+	TokenId       domain.TokenId `json:"tokenId"`
+	PriceInNative float64        `json:"priceInNative"`
+	PriceInUsd    float64        `json:"priceInUsd"`
+	Sources       []SourceFloor  `json:"sources"`
+}
+
 type ExternalListingUseCase interface {
 	GetListings(c ctx.Ctx, account domain.Address, chainId domain.ChainId) ([]ExternalListing, error)
-	FetchOpenseaListings(c ctx.Ctx, account domain.Address, chainId domain.ChainId) ([]ExternalListing, error)
+	// FetchListings fetches listings of the account from all marketplaces keyed by source, failed marketplaces are
+	// left out unless all of them fail
+	FetchListings(c ctx.Ctx, account domain.Address, chainId domain.ChainId) (map[Source][]ExternalListing, error)
+	// ReplaceListings replaces the stored listings of the account on the marketplace with listings
+	ReplaceListings(c ctx.Ctx, account domain.Address, chainId domain.ChainId, source Source, listings []ExternalListing) error
+	// GetFloor fetches the floor of the collection from all marketplaces, failed marketplaces are left out unless
+	// all of them fail
+	GetFloor(c ctx.Ctx, chainId domain.ChainId, collection domain.Address) (*Floor, error)
 	BulkUpsert(ctx.Ctx, []ExternalListing) error
 	DeleteListing(ctx.Ctx, ExternalListingId) error
 }
@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"errors"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+var (
+	ErrQueryTooDeep   = errors.New("query exceeds max depth")
+	ErrQueryTooCostly = errors.New("query exceeds max cost")
+	ErrUnauthorized   = errors.New("unauthorized")
+)
+
+// Request is the standard graphql request over http
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Usecase interface {
+	// Do executes the request, viewer is the authenticated account or nil for anonymous requests
+	Do(c ctx.Ctx, viewer *domain.Address, req Request) *gql.Result
+}
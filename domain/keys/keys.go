@@ -13,8 +13,10 @@ const (
 	PfxNonce = "nonce"
 	// PfxPagingService is used for prefixing paging data
 	PfxPagingService = "pagingService"
-	// PfxSearchV2Paging is used for prefixing searchV2 paging
-	PfxSearchV2Paging = "searchV2Paging"
+	// PfxRateLimit is used for prefixing rate limit windows
+	PfxRateLimit = "rateLimit"
+	// PfxApiKeyUsage is used for prefixing api key usage counters
+	PfxApiKeyUsage = "apiKeyUsage"
 )
 
 // MD5 hashes the data with md5
@@ -0,0 +1,246 @@
+package lazymint
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+// MaxRoyalty is the max royalty in basis points
+const MaxRoyalty = 10000
+
+var (
+	ErrUnsupportedContract = errors.New("lazy minting is not supported by the contract")
+	ErrVoucherExpired      = errors.New("mint voucher expired")
+	ErrVoucherExists       = errors.New("token is minted or has an active mint voucher")
+	ErrInvalidVoucher      = errors.New("invalid mint voucher")
+)
+
+const (
+	PrimaryType      = "MintVoucher"
+	Eip712DomainName = "EIP712Domain"
+)
+
+var VoucherTypes = apitypes.Types{
+	"MintVoucher": {
+		{Name: "creator", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "tokenURI", Type: "string"},
+		{Name: "royalty", Type: "uint256"},
+		{Name: "price", Type: "uint256"},
+		{Name: "currency", Type: "address"},
+		{Name: "expiry", Type: "uint256"},
+	},
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+}
+
+func GetDomainSeperator(chainId domain.ChainId, contract domain.Address) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              "XLazyMint",
+		Version:           "1",
+		ChainId:           math.NewHexOrDecimal256(int64(chainId)),
+		VerifyingContract: contract.ToLowerStr(),
+	}
+}
+
+type VoucherStatus string
+
+const (
+	VoucherStatusActive  VoucherStatus = "active"
+	VoucherStatusMinted  VoucherStatus = "minted"
+	VoucherStatusExpired VoucherStatus = "expired"
+)
+
+// Voucher is a mint voucher signed by the creator, anyone paying the price before expiry can redeem it to mint
+// the token on the lazy mint contract
+type Voucher struct {
+	// Id is the hex of the eip712 hash signed by the creator
+	Id              string         `json:"id" bson:"id"`
+	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
+	TokenId         domain.TokenId `json:"tokenId" bson:"tokenId"`
+	Creator         domain.Address `json:"creator" bson:"creator"`
+	TokenUri        string         `json:"tokenUri" bson:"tokenUri"`
+	// Royalty is in basis points
+	Royalty  int32          `json:"royalty" bson:"royalty"`
+	Price    string         `json:"price" bson:"price"`
+	Currency domain.Address `json:"currency" bson:"currency"`
+	// Expiry is the unix timestamp in seconds
+	Expiry     int64         `json:"expiry" bson:"expiry"`
+	Signature  string        `json:"signature" bson:"signature"`
+	Status     VoucherStatus `json:"status" bson:"status"`
+	MintTxHash domain.TxHash `json:"mintTxHash,omitempty" bson:"mintTxHash,omitempty"`
+	MintedAt   *time.Time    `json:"mintedAt,omitempty" bson:"mintedAt,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+func (v *Voucher) ToNftItemId() nftitem.Id {
+	return nftitem.Id{ChainId: v.ChainId, ContractAddress: v.ContractAddress, TokenId: v.TokenId}
+}
+
+func (v *Voucher) ExpiresAt() time.Time {
+	return time.Unix(v.Expiry, 0)
+}
+
+// IsRedeemable returns true if the voucher is active and not expired
+func (v *Voucher) IsRedeemable(now time.Time) bool {
+	return v.Status == VoucherStatusActive && v.ExpiresAt().After(now)
+}
+
+func (v *Voucher) ToMessage() apitypes.TypedDataMessage {
+	return apitypes.TypedDataMessage{
+		"creator":  v.Creator.ToLowerStr(),
+		"tokenId":  v.TokenId.String(),
+		"tokenURI": v.TokenUri,
+		"royalty":  fmt.Sprint(v.Royalty),
+		"price":    v.Price,
+		"currency": v.Currency.ToLowerStr(),
+		"expiry":   fmt.Sprint(v.Expiry),
+	}
+}
+
+// Hash returns the eip712 hash of the voucher to be signed
+func (v *Voucher) Hash() ([]byte, error) {
+	typedData := apitypes.TypedData{
+		Types:       VoucherTypes,
+		PrimaryType: PrimaryType,
+		Domain:      GetDomainSeperator(v.ChainId, v.ContractAddress),
+		Message:     v.ToMessage(),
+	}
+
+	domainSeperator, err := typedData.HashStruct(Eip712DomainName, typedData.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	dataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, err
+	}
+	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeperator), string(dataHash)))
+	return crypto.Keccak256(rawData), nil
+}
+
+// Validate checks fields of the voucher, the signature and the currency are not verified
+func (v *Voucher) Validate() error {
+	if v.ChainId == 0 || v.ContractAddress == "" || v.Creator == "" || v.TokenUri == "" {
+		return ErrInvalidVoucher
+	}
+	if _, ok := new(big.Int).SetString(v.TokenId.String(), 10); !ok {
+		return ErrInvalidVoucher
+	}
+	if v.Royalty < 0 || v.Royalty > MaxRoyalty {
+		return ErrInvalidVoucher
+	}
+	if price, ok := new(big.Int).SetString(v.Price, 10); !ok || price.Sign() <= 0 {
+		return ErrInvalidVoucher
+	}
+	if _, err := hexutil.Decode(v.Signature); err != nil {
+		return ErrInvalidVoucher
+	}
+	return nil
+}
+
+type FindAllOptions struct {
+	SortBy          *string         `bson:"-"`
+	SortDir         *domain.SortDir `bson:"-"`
+	Offset          *int32          `bson:"-"`
+	Limit           *int32          `bson:"-"`
+	Id              *string         `bson:"id"`
+	ChainId         *domain.ChainId `bson:"chainId"`
+	ContractAddress *domain.Address `bson:"contractAddress"`
+	TokenId         *domain.TokenId `bson:"tokenId"`
+	Creator         *domain.Address `bson:"creator"`
+	Status          *VoucherStatus  `bson:"status"`
+}
+
+type FindAllOptionsFunc func(*FindAllOptions) error
+
+func GetFindAllOptions(opts ...FindAllOptionsFunc) (FindAllOptions, error) {
+	res := FindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func WithSort(sortby string, sortdir domain.SortDir) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func WithPagination(offset int32, limit int32) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func WithId(id string) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Id = &id
+		return nil
+	}
+}
+
+func WithToken(id nftitem.Id) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.ChainId = &id.ChainId
+		options.ContractAddress = id.ContractAddress.ToLowerPtr()
+		options.TokenId = &id.TokenId
+		return nil
+	}
+}
+
+func WithCreator(creator domain.Address) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Creator = creator.ToLowerPtr()
+		return nil
+	}
+}
+
+func WithStatus(status VoucherStatus) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Status = &status
+		return nil
+	}
+}
+
+// VoucherUpdater marks the voucher minted or expired
+type VoucherUpdater struct {
+	Status     *VoucherStatus `bson:"status"`
+	MintTxHash *domain.TxHash `bson:"mintTxHash"`
+	MintedAt   *time.Time     `bson:"mintedAt"`
+}
+
+type VoucherRepo interface {
+	FindAll(c ctx.Ctx, opts ...FindAllOptionsFunc) ([]Voucher, error)
+	Insert(c ctx.Ctx, voucher *Voucher) error
+	Update(c ctx.Ctx, id string, updater VoucherUpdater) error
+}
+
+type UseCase interface {
+	// Create verifies and stores the voucher, the token is listed as not minted yet
+	Create(c ctx.Ctx, voucher Voucher) (*Voucher, error)
+	FindAll(c ctx.Ctx, opts ...FindAllOptionsFunc) ([]Voucher, error)
+	FindOne(c ctx.Ctx, id string) (*Voucher, error)
+}
@@ -0,0 +1,327 @@
+package moderation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+var (
+	ErrReportExists    = errors.New("report already exists")
+	ErrReportResolved  = errors.New("report already resolved")
+	ErrInvalidTarget   = errors.New("invalid report target")
+	ErrInvalidCategory = errors.New("invalid report category")
+)
+
+type TargetType string
+
+const (
+	TargetTypeToken      TargetType = "token"
+	TargetTypeCollection TargetType = "collection"
+	TargetTypeAccount    TargetType = "account"
+	TargetTypeComment    TargetType = "comment"
+)
+
+// Target is a token, a collection, an account or a comment, fields not belonging to the type are empty
+type Target struct {
+	Type            TargetType     `json:"type" bson:"type"`
+	ChainId         domain.ChainId `json:"chainId,omitempty" bson:"chainId,omitempty"`
+	ContractAddress domain.Address `json:"contractAddress,omitempty" bson:"contractAddress,omitempty"`
+	TokenId         domain.TokenId `json:"tokenId,omitempty" bson:"tokenId,omitempty"`
+	Address         domain.Address `json:"address,omitempty" bson:"address,omitempty"`
+	CommentId       string         `json:"commentId,omitempty" bson:"commentId,omitempty"`
+}
+
+// Normalize validates the target, drops fields not belonging to the type and lowers addresses
+func (t Target) Normalize() (Target, error) {
+	switch t.Type {
+	case TargetTypeToken:
+		if t.ChainId == 0 || t.ContractAddress == "" || t.TokenId == "" {
+			return t, ErrInvalidTarget
+		}
+		return Target{Type: t.Type, ChainId: t.ChainId, ContractAddress: t.ContractAddress.ToLower(), TokenId: t.TokenId}, nil
+	case TargetTypeCollection:
+		if t.ChainId == 0 || t.ContractAddress == "" {
+			return t, ErrInvalidTarget
+		}
+		return Target{Type: t.Type, ChainId: t.ChainId, ContractAddress: t.ContractAddress.ToLower()}, nil
+	case TargetTypeAccount:
+		if t.Address == "" {
+			return t, ErrInvalidTarget
+		}
+		return Target{Type: t.Type, Address: t.Address.ToLower()}, nil
+	case TargetTypeComment:
+		// the thread is kept so the comment can be located, TokenId is empty on collection threads
+		if t.ChainId == 0 || t.ContractAddress == "" || t.CommentId == "" {
+			return t, ErrInvalidTarget
+		}
+		return Target{Type: t.Type, ChainId: t.ChainId, ContractAddress: t.ContractAddress.ToLower(), TokenId: t.TokenId, CommentId: t.CommentId}, nil
+	default:
+		return t, ErrInvalidTarget
+	}
+}
+
+type Category string
+
+const (
+	CategorySpam          Category = "spam"
+	CategoryScam          Category = "scam"
+	CategoryCopyright     Category = "copyright"
+	CategoryInappropriate Category = "inappropriate"
+	CategoryImpersonation Category = "impersonation"
+	CategoryOther         Category = "other"
+)
+
+func (c Category) IsValid() bool {
+	switch c {
+	case CategorySpam, CategoryScam, CategoryCopyright, CategoryInappropriate, CategoryImpersonation, CategoryOther:
+		return true
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+type Report struct {
+	Id       string         `json:"id" bson:"id"`
+	Target   Target         `json:"target" bson:"target"`
+	Reporter domain.Address `json:"reporter" bson:"reporter"`
+	Category Category       `json:"category" bson:"category"`
+	Comment  string         `json:"comment" bson:"comment"`
+	Status   ReportStatus   `json:"status" bson:"status"`
+	// ResolvedBy, ResolvedAt and Resolution are set when the report is triaged
+	ResolvedBy *domain.Address `json:"resolvedBy,omitempty" bson:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time      `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+	Resolution string          `json:"resolution,omitempty" bson:"resolution,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt" bson:"createdAt"`
+}
+
+type ReportParams struct {
+	Target   Target   `json:"target"`
+	Category Category `json:"category"`
+	Comment  string   `json:"comment"`
+}
+
+// ReportUpdater resolves reports
+type ReportUpdater struct {
+	Status     *ReportStatus   `bson:"status"`
+	ResolvedBy *domain.Address `bson:"resolvedBy"`
+	ResolvedAt *time.Time      `bson:"resolvedAt"`
+	Resolution *string         `bson:"resolution"`
+}
+
+type AuditAction string
+
+const (
+	AuditActionBan   AuditAction = "ban"
+	AuditActionUnban AuditAction = "unban"
+	// AuditActionRemoveComment is taken by the comment usecase, comments can't be banned
+	AuditActionRemoveComment AuditAction = "removeComment"
+)
+
+// AuditLog is an immutable record of a moderation action
+type AuditLog struct {
+	Id     string         `json:"id" bson:"id"`
+	Action AuditAction    `json:"action" bson:"action"`
+	Actor  domain.Address `json:"actor" bson:"actor"`
+	Target Target         `json:"target" bson:"target"`
+	Reason string         `json:"reason" bson:"reason"`
+	// ReportId is set if the action is taken by triaging a report
+	ReportId  string    `json:"reportId,omitempty" bson:"reportId,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+type TriageParams struct {
+	// Status is either actioned or dismissed
+	Status ReportStatus `json:"status"`
+	// Ban bans the target when actioned
+	Ban        bool   `json:"ban"`
+	Resolution string `json:"resolution"`
+}
+
+type ReportFindAllOptions struct {
+	SortBy   *string         `bson:"-"`
+	SortDir  *domain.SortDir `bson:"-"`
+	Offset   *int32          `bson:"-"`
+	Limit    *int32          `bson:"-"`
+	Id       *string         `bson:"id"`
+	Status   *ReportStatus   `bson:"status"`
+	Category *Category       `bson:"category"`
+	Reporter *domain.Address `bson:"reporter"`
+	// Target matches reports of the exact target
+	Target *Target `bson:"-"`
+	// TargetType matches reports of all targets of the type
+	TargetType *TargetType `bson:"target.type"`
+}
+
+type ReportFindAllOptionsFunc func(*ReportFindAllOptions) error
+
+func GetReportFindAllOptions(opts ...ReportFindAllOptionsFunc) (ReportFindAllOptions, error) {
+	res := ReportFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func ReportWithSort(sortby string, sortdir domain.SortDir) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func ReportWithPagination(offset int32, limit int32) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func ReportWithId(id string) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.Id = &id
+		return nil
+	}
+}
+
+func ReportWithStatus(status ReportStatus) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.Status = &status
+		return nil
+	}
+}
+
+func ReportWithCategory(category Category) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.Category = &category
+		return nil
+	}
+}
+
+func ReportWithReporter(reporter domain.Address) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.Reporter = reporter.ToLowerPtr()
+		return nil
+	}
+}
+
+func ReportWithTarget(target Target) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		normalized, err := target.Normalize()
+		if err != nil {
+			return err
+		}
+		options.Target = &normalized
+		return nil
+	}
+}
+
+func ReportWithTargetType(typ TargetType) ReportFindAllOptionsFunc {
+	return func(options *ReportFindAllOptions) error {
+		options.TargetType = &typ
+		return nil
+	}
+}
+
+type AuditLogFindAllOptions struct {
+	SortBy  *string         `bson:"-"`
+	SortDir *domain.SortDir `bson:"-"`
+	Offset  *int32          `bson:"-"`
+	Limit   *int32          `bson:"-"`
+	Action  *AuditAction    `bson:"action"`
+	Actor   *domain.Address `bson:"actor"`
+	// Target matches logs of the exact target
+	Target *Target `bson:"-"`
+}
+
+type AuditLogFindAllOptionsFunc func(*AuditLogFindAllOptions) error
+
+func GetAuditLogFindAllOptions(opts ...AuditLogFindAllOptionsFunc) (AuditLogFindAllOptions, error) {
+	res := AuditLogFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func AuditLogWithSort(sortby string, sortdir domain.SortDir) AuditLogFindAllOptionsFunc {
+	return func(options *AuditLogFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func AuditLogWithPagination(offset int32, limit int32) AuditLogFindAllOptionsFunc {
+	return func(options *AuditLogFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func AuditLogWithAction(action AuditAction) AuditLogFindAllOptionsFunc {
+	return func(options *AuditLogFindAllOptions) error {
+		options.Action = &action
+		return nil
+	}
+}
+
+func AuditLogWithActor(actor domain.Address) AuditLogFindAllOptionsFunc {
+	return func(options *AuditLogFindAllOptions) error {
+		options.Actor = actor.ToLowerPtr()
+		return nil
+	}
+}
+
+func AuditLogWithTarget(target Target) AuditLogFindAllOptionsFunc {
+	return func(options *AuditLogFindAllOptions) error {
+		normalized, err := target.Normalize()
+		if err != nil {
+			return err
+		}
+		options.Target = &normalized
+		return nil
+	}
+}
+
+type ReportRepo interface {
+	FindAll(ctx.Ctx, ...ReportFindAllOptionsFunc) ([]Report, error)
+	Count(ctx.Ctx, ...ReportFindAllOptionsFunc) (int, error)
+	Insert(ctx.Ctx, *Report) error
+	// Update updates reports matching the options
+	Update(ctx.Ctx, ReportUpdater, ...ReportFindAllOptionsFunc) error
+}
+
+// AuditLogRepo has no update or delete, audit logs are immutable
+type AuditLogRepo interface {
+	FindAll(ctx.Ctx, ...AuditLogFindAllOptionsFunc) ([]AuditLog, error)
+	Insert(ctx.Ctx, *AuditLog) error
+}
+
+type UseCase interface {
+	Report(c ctx.Ctx, reporter domain.Address, params ReportParams) (*Report, error)
+	FindReports(ctx.Ctx, ...ReportFindAllOptionsFunc) ([]Report, int, error)
+	// Triage resolves the open report, actioning it resolves all open reports of the same target
+	Triage(c ctx.Ctx, moderator domain.Address, id string, params TriageParams) (*Report, error)
+	// Ban bans the target and writes an audit log
+	Ban(c ctx.Ctx, actor domain.Address, target Target, reason string) error
+	// Unban unbans the target and writes an audit log
+	Unban(c ctx.Ctx, actor domain.Address, target Target, reason string) error
+	FindAuditLogs(ctx.Ctx, ...AuditLogFindAllOptionsFunc) ([]AuditLog, error)
+}
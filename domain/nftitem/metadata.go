@@ -1,9 +1,38 @@
 package nftitem
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AttributeValueType string
+
+const (
+	AttributeValueTypeString AttributeValueType = "string"
+	AttributeValueTypeNumber AttributeValueType = "number"
+	AttributeValueTypeDate   AttributeValueType = "date"
+)
+
+// display types of opensea metadata standard
+const (
+	DisplayTypeNumber          = "number"
+	DisplayTypeBoostNumber     = "boost_number"
+	DisplayTypeBoostPercentage = "boost_percentage"
+	DisplayTypeDate            = "date"
+)
+
 type Attribute struct {
 	TraitType   string `json:"trait_type" bson:"trait_type"`
 	Value       string `json:"value" bson:"value"`
 	DisplayType string `json:"display_type,omitempty" bson:"display_type,omitempty"`
+
+	// typed value, filled by Normalize
+	ValueType   AttributeValueType `json:"valueType,omitempty" bson:"valueType,omitempty"`
+	NumberValue *float64           `json:"numberValue,omitempty" bson:"numberValue,omitempty"`
+	DateValue   *time.Time         `json:"dateValue,omitempty" bson:"dateValue,omitempty"`
+	StringValue string             `json:"stringValue,omitempty" bson:"stringValue,omitempty"`
 }
 
 type RawAttribute struct {
@@ -14,6 +43,110 @@ type RawAttribute struct {
 
 type Attributes = []Attribute
 
+// IsNumericDisplayType returns true if the display type is shown as a number
+func IsNumericDisplayType(displayType string) bool {
+	switch displayType {
+	case DisplayTypeNumber, DisplayTypeBoostNumber, DisplayTypeBoostPercentage:
+		return true
+	}
+	return false
+}
+
+// Normalize returns the attribute with typed value parsed from the JSON-encoded `Value`.
+// Date is parsed from unix timestamp (in seconds or milliseconds) or RFC3339 string for `date` display type,
+// number is parsed for numeric display types and JSON numbers, string otherwise.
+func (a Attribute) Normalize() Attribute {
+	a.ValueType = AttributeValueTypeString
+	a.NumberValue = nil
+	a.DateValue = nil
+	a.StringValue = ""
+
+	var (
+		raw interface{}
+		num *float64
+		str string
+	)
+
+	// values written by custom parsers are not JSON-encoded
+	if err := json.Unmarshal([]byte(a.Value), &raw); err != nil {
+		raw = a.Value
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		num = &v
+		str = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		str = v
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && (IsNumericDisplayType(a.DisplayType) || a.DisplayType == DisplayTypeDate) {
+			num = &f
+		}
+	case bool:
+		str = strconv.FormatBool(v)
+	default:
+		str = a.Value
+	}
+
+	if a.DisplayType == DisplayTypeDate {
+		if t, ok := parseDate(num, str); ok {
+			a.ValueType = AttributeValueTypeDate
+			a.DateValue = &t
+			return a
+		}
+	}
+
+	if num != nil {
+		a.ValueType = AttributeValueTypeNumber
+		a.NumberValue = num
+		return a
+	}
+
+	a.StringValue = str
+	return a
+}
+
+// IsNormalized returns true if all attributes are normalized
+func IsNormalized(attrs Attributes) bool {
+	for _, attr := range attrs {
+		if attr.ValueType == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func NormalizeAttributes(attrs Attributes) Attributes {
+	if attrs == nil {
+		return nil
+	}
+
+	res := make(Attributes, 0, len(attrs))
+	for _, attr := range attrs {
+		res = append(res, attr.Normalize())
+	}
+	return res
+}
+
+// unix timestamps larger than this are considered in milliseconds
+const maxUnixSeconds = 1e11
+
+func parseDate(num *float64, str string) (time.Time, bool) {
+	if num != nil {
+		if *num > maxUnixSeconds {
+			return time.UnixMilli(int64(*num)).UTC(), true
+		}
+		return time.Unix(int64(*num), 0).UTC(), true
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(str)); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
 type PropertyDetail struct {
 	Name  string      `json:"name"`
 	Value interface{} `json:"value"`
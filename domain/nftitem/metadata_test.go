@@ -0,0 +1,81 @@
+package nftitem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x-xyz/goapi/base/ptr"
+)
+
+func TestAttributeNormalize(t *testing.T) {
+	date := time.Date(2022, 4, 30, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		attr Attribute
+		want Attribute
+	}{
+		{
+			name: "json string",
+			attr: Attribute{TraitType: "Fur", Value: `"Blue"`},
+			want: Attribute{TraitType: "Fur", Value: `"Blue"`, ValueType: AttributeValueTypeString, StringValue: "Blue"},
+		},
+		{
+			name: "raw string",
+			attr: Attribute{TraitType: "Staked", Value: "Yes"},
+			want: Attribute{TraitType: "Staked", Value: "Yes", ValueType: AttributeValueTypeString, StringValue: "Yes"},
+		},
+		{
+			name: "numeric string without display type",
+			attr: Attribute{TraitType: "Edition", Value: `"10"`},
+			want: Attribute{TraitType: "Edition", Value: `"10"`, ValueType: AttributeValueTypeString, StringValue: "10"},
+		},
+		{
+			name: "json number",
+			attr: Attribute{TraitType: "Level", Value: "15"},
+			want: Attribute{TraitType: "Level", Value: "15", ValueType: AttributeValueTypeNumber, NumberValue: ptr.Float64(15)},
+		},
+		{
+			name: "boost percentage in string",
+			attr: Attribute{TraitType: "Stamina", Value: `"2.5"`, DisplayType: DisplayTypeBoostPercentage},
+			want: Attribute{TraitType: "Stamina", Value: `"2.5"`, DisplayType: DisplayTypeBoostPercentage, ValueType: AttributeValueTypeNumber, NumberValue: ptr.Float64(2.5)},
+		},
+		{
+			name: "date in seconds",
+			attr: Attribute{TraitType: "Birthday", Value: "1651276800", DisplayType: DisplayTypeDate},
+			want: Attribute{TraitType: "Birthday", Value: "1651276800", DisplayType: DisplayTypeDate, ValueType: AttributeValueTypeDate, DateValue: &date},
+		},
+		{
+			name: "date in milliseconds",
+			attr: Attribute{TraitType: "Birthday", Value: "1651276800000", DisplayType: DisplayTypeDate},
+			want: Attribute{TraitType: "Birthday", Value: "1651276800000", DisplayType: DisplayTypeDate, ValueType: AttributeValueTypeDate, DateValue: &date},
+		},
+		{
+			name: "date in string",
+			attr: Attribute{TraitType: "Birthday", Value: `"2022-04-30"`, DisplayType: DisplayTypeDate},
+			want: Attribute{TraitType: "Birthday", Value: `"2022-04-30"`, DisplayType: DisplayTypeDate, ValueType: AttributeValueTypeDate, DateValue: &date},
+		},
+		{
+			name: "invalid date",
+			attr: Attribute{TraitType: "Birthday", Value: `"someday"`, DisplayType: DisplayTypeDate},
+			want: Attribute{TraitType: "Birthday", Value: `"someday"`, DisplayType: DisplayTypeDate, ValueType: AttributeValueTypeString, StringValue: "someday"},
+		},
+		{
+			name: "bool",
+			attr: Attribute{TraitType: "Rare", Value: "true"},
+			want: Attribute{TraitType: "Rare", Value: "true", ValueType: AttributeValueTypeString, StringValue: "true"},
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, c.attr.Normalize(), c.name)
+	}
+}
+
+func TestIsNormalized(t *testing.T) {
+	attrs := Attributes{{TraitType: "Level", Value: "1"}}
+	assert.False(t, IsNormalized(attrs))
+	assert.True(t, IsNormalized(NormalizeAttributes(attrs)))
+	assert.True(t, IsNormalized(nil))
+}
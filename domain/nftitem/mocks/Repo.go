@@ -3,11 +3,9 @@
 package mocks
 
 import (
+	mock "github.com/stretchr/testify/mock"
 	ctx "github.com/x-xyz/goapi/base/ctx"
 	domain "github.com/x-xyz/goapi/domain"
-
-	mock "github.com/stretchr/testify/mock"
-
 	nftitem "github.com/x-xyz/goapi/domain/nftitem"
 )
 
@@ -16,6 +14,20 @@ type Repo struct {
 	mock.Mock
 }
 
+// BulkPatch provides a mock function with given fields: c, patches
+func (_m *Repo) BulkPatch(c ctx.Ctx, patches []nftitem.PatchOp) error {
+	ret := _m.Called(c, patches)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, []nftitem.PatchOp) error); ok {
+		r0 = rf(c, patches)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Count provides a mock function with given fields: c, opts
 func (_m *Repo) Count(c ctx.Ctx, opts ...nftitem.FindAllOptionsFunc) (int, error) {
 	_va := make([]interface{}, len(opts))
@@ -44,6 +56,29 @@ func (_m *Repo) Count(c ctx.Ctx, opts ...nftitem.FindAllOptionsFunc) (int, error
 	return r0, r1
 }
 
+// CountByOwners provides a mock function with given fields: c, chainId, contract, owners
+func (_m *Repo) CountByOwners(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, owners []domain.Address) (map[domain.Address]int64, error) {
+	ret := _m.Called(c, chainId, contract, owners)
+
+	var r0 map[domain.Address]int64
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.ChainId, domain.Address, []domain.Address) map[domain.Address]int64); ok {
+		r0 = rf(c, chainId, contract, owners)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[domain.Address]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, domain.ChainId, domain.Address, []domain.Address) error); ok {
+		r1 = rf(c, chainId, contract, owners)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: _a0, _a1
 func (_m *Repo) Create(_a0 ctx.Ctx, _a1 *nftitem.NftItem) error {
 	ret := _m.Called(_a0, _a1)
@@ -181,6 +216,34 @@ func (_m *Repo) IncreaseViewCount(c ctx.Ctx, id nftitem.Id, count int) (int32, e
 	return r0, r1
 }
 
+// NextCursor provides a mock function with given fields: c, last, opts
+func (_m *Repo) NextCursor(c ctx.Ctx, last nftitem.Id, opts ...nftitem.FindAllOptionsFunc) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, c, last)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, nftitem.Id, ...nftitem.FindAllOptionsFunc) string); ok {
+		r0 = rf(c, last, opts...)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ctx.Ctx, nftitem.Id, ...nftitem.FindAllOptionsFunc) error); ok {
+		r1 = rf(c, last, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Patch provides a mock function with given fields: c, id, value
 func (_m *Repo) Patch(c ctx.Ctx, id nftitem.Id, value nftitem.PatchableNftItem) error {
 	ret := _m.Called(c, id, value)
@@ -195,13 +258,13 @@ func (_m *Repo) Patch(c ctx.Ctx, id nftitem.Id, value nftitem.PatchableNftItem)
 	return r0
 }
 
-// RefreshActiveListings provides a mock function with given fields: c, id
-func (_m *Repo) RefreshActiveListings(c ctx.Ctx, id nftitem.Id) error {
-	ret := _m.Called(c, id)
+// PatchTokens provides a mock function with given fields: c, chainId, contract, tokenIds, value
+func (_m *Repo) PatchTokens(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, tokenIds []domain.TokenId, value nftitem.PatchableNftItem) error {
+	ret := _m.Called(c, chainId, contract, tokenIds, value)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(ctx.Ctx, nftitem.Id) error); ok {
-		r0 = rf(c, id)
+	if rf, ok := ret.Get(0).(func(ctx.Ctx, domain.ChainId, domain.Address, []domain.TokenId, nftitem.PatchableNftItem) error); ok {
+		r0 = rf(c, chainId, contract, tokenIds, value)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -108,6 +108,7 @@ type NftItem struct {
 	HasActiveListings         bool               `json:"-" bson:"hasActiveListings"`
 	OpenrarityRank            int                `json:"openrarityRank" bson:"openrarityRank"`
 	OpenrarityScore           float64            `json:"openrarityScore" bson:"openrarityScore"`
+	SpamScore                 float64            `json:"spamScore" bson:"spamScore"`
 
 	// ListingEndsAt is calculated from orders, take the last end listing
 	ListingEndsAt         *time.Time       `json:"listingEndsAt,omitempty" bson:"listingEndsAt"`
@@ -119,6 +120,9 @@ type NftItem struct {
 	OfferStartsAt         *time.Time       `json:"offerStartsAt,omitempty" bson:"offerStartsAt"`
 	InstantLiquidityInUsd float64          `json:"instantLiquidityInUsd" bson:"instantLiquidityInUsd"`
 	HasOrder              bool             `json:"-" bson:"hasOrder"`
+
+	// LazyMintVoucherId is set if the token is not minted yet and listed by the mint voucher
+	LazyMintVoucherId string `json:"lazyMintVoucherId,omitempty" bson:"lazyMintVoucherId,omitempty"`
 }
 
 type PatchableNftItem struct {
@@ -155,6 +159,7 @@ type PatchableNftItem struct {
 	HasActiveListings         *bool               `json:"-" bson:"hasActiveListings"`
 	OpenrarityRank            *int                `json:"openrarityRank" bson:"openrarityRank"`
 	OpenrarityScore           *float64            `json:"openrarityScore" bson:"openrarityScore"`
+	SpamScore                 *float64            `json:"spamScore" bson:"spamScore"`
 
 	// ListingEndsAt is calculated from orders, take the last end listing
 	ListingEndsAt         *time.Time       `json:"listingEndsAt,omitempty" bson:"listingEndsAt"`
@@ -166,6 +171,9 @@ type PatchableNftItem struct {
 	OfferStartsAt         *time.Time       `json:"offerStartsAt,omitempty" bson:"offerStartsAt"`
 	InstantLiquidityInUsd *float64         `json:"instantLiquidityInUsd" bson:"instantLiquidityInUsd"`
 	HasOrder              *bool            `json:"-" bson:"hasOrder"`
+
+	// LazyMintVoucherId is cleared once the token is minted
+	LazyMintVoucherId *string `json:"-" bson:"lazyMintVoucherId"`
 }
 
 func (i *NftItem) ToId() *Id {
@@ -213,10 +221,25 @@ type NftitemWith1155Balance struct {
 	Balance int `json:"balance"`
 }
 
-// AttributeFilter is used to filter nftitem list
+// AttributeFilter is used to filter nftitem list, an item matches if its trait
+// equals to one of Values, or its typed value is in the given range (inclusive)
 type AttributeFilter struct {
 	Name   string   `query:"name"`
 	Values []string `query:"values"`
+	// Min and Max filter number traits, including boost number and boost percentage
+	Min *float64 `query:"min"`
+	Max *float64 `query:"max"`
+	// After and Before filter date traits
+	After  *time.Time `query:"after"`
+	Before *time.Time `query:"before"`
+}
+
+func (f AttributeFilter) HasNumberRange() bool {
+	return f.Min != nil || f.Max != nil
+}
+
+func (f AttributeFilter) HasDateRange() bool {
+	return f.After != nil || f.Before != nil
 }
 
 type FindAllOptions struct {
@@ -251,6 +274,40 @@ type FindAllOptions struct {
 	BidOwner            *domain.Address
 	ObjectIdLT          *primitive.ObjectID
 	HasOrder            *bool
+	// IsSpam matches items with spam score above the threshold
+	IsSpam *bool
+	// Cursor enables keyset pagination, empty for the first page. Offset is ignored if Cursor is set
+	Cursor *string
+}
+
+// SortFields returns sort fields of the find, `-_id` by default
+func (o FindAllOptions) SortFields() []string {
+	if o.Sorts != nil {
+		return *o.Sorts
+	}
+
+	if o.SortBy == nil || o.SortDir == nil {
+		// default sort by -createdAt
+		// but there are some items without `createdAt` in dev db
+		return []string{"-_id"}
+	}
+
+	sorts := []string{}
+	sortBy := *o.SortBy
+	switch sortBy {
+	case "listedAt":
+		fallthrough
+	case "priceInUSD":
+		sorts = append(sorts, "-hasActiveListings")
+	}
+
+	if *o.SortDir == domain.SortDirDesc {
+		sortBy = "-" + sortBy
+	}
+	if sortBy == "_id" || sortBy == "-_id" {
+		return append(sorts, sortBy)
+	}
+	return append(sorts, sortBy, "-_id")
 }
 
 type FindAllOptionsFunc func(*FindAllOptions) error
@@ -504,11 +561,40 @@ func WithHasOrder(hasOrder bool) FindAllOptionsFunc {
 	}
 }
 
+func WithIsSpam(isSpam bool) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.IsSpam = &isSpam
+		return nil
+	}
+}
+
+func WithCursor(cursor string) FindAllOptionsFunc {
+	return func(options *FindAllOptions) error {
+		options.Cursor = &cursor
+		return nil
+	}
+}
+
+// PatchOp patches the nftitem of Id with Value
+type PatchOp struct {
+	Id    Id
+	Value PatchableNftItem
+}
+
 type Repo interface {
 	FindAll(c ctx.Ctx, opts ...FindAllOptionsFunc) ([]*NftItem, error)
 	Count(c ctx.Ctx, opts ...FindAllOptionsFunc) (int, error)
+	// CountByOwners counts tokens of the collection held by each of owners in one aggregation, owners holding none are
+	// omitted
+	CountByOwners(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, owners []domain.Address) (map[domain.Address]int64, error)
+	// NextCursor returns the cursor of the page after last, which is the last item found with opts
+	NextCursor(c ctx.Ctx, last Id, opts ...FindAllOptionsFunc) (string, error)
 	FindOne(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, tokenId domain.TokenId) (*NftItem, error)
 	Patch(c ctx.Ctx, id Id, value PatchableNftItem) error
+	// BulkPatch patches nftitems in one bulk write, nftitems not found are skipped
+	BulkPatch(c ctx.Ctx, patches []PatchOp) error
+	// PatchTokens patches tokens of a collection with the same value in one update, tokens not found are skipped
+	PatchTokens(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, tokenIds []domain.TokenId, value PatchableNftItem) error
 	IncreaseViewCount(c ctx.Ctx, id Id, count int) (int32, error)
 	IncreaseLikeCount(c ctx.Ctx, id Id, count int) (int32, error)
 	//	@todo	remember set IsAppropriate to true as default value
@@ -5,4 +5,5 @@ import "github.com/x-xyz/goapi/domain"
 type ExchangeCfg struct {
 	Address    domain.Address
 	Strategies map[domain.Address]Strategy
+	Fee        FeeCfg
 }
@@ -0,0 +1,102 @@
+package order
+
+import (
+	"errors"
+
+	"github.com/x-xyz/goapi/domain"
+)
+
+// BurnAddress receives the burnt share of protocol fee
+const BurnAddress = domain.Address("0x000000000000000000000000000000000000dead")
+
+// BpsBase is 100% in basis points, MinPercentageToAsk is in basis points as well
+const BpsBase = 10000
+
+type FeeCfg struct {
+	// ProtocolFeeBps is the protocol fee of the sale price in basis points
+	ProtocolFeeBps int64
+	// FeeDistShareBps is the share of the protocol fee burnt or donated by the FeeDistType of the order, in basis
+	// points of the protocol fee. The rest goes to the Recipient.
+	FeeDistShareBps   int64
+	Recipient         domain.Address
+	DonationRecipient domain.Address
+	// Unset is true if the protocol fee of the exchange isn't configured, fee quotes are disabled instead of
+	// dropping the protocol fee
+	Unset bool
+}
+
+var (
+	ErrInvalidFeeCfg = errors.New("invalid fee config")
+	// ErrFeeNotConfigured is returned by fee quotes of an exchange without fee config
+	ErrFeeNotConfigured = errors.New("fee is not configured")
+)
+
+// Validate checks basis points are within 0 and BpsBase
+func (c FeeCfg) Validate() error {
+	if c.ProtocolFeeBps < 0 || c.ProtocolFeeBps > BpsBase || c.FeeDistShareBps < 0 || c.FeeDistShareBps > BpsBase {
+		return ErrInvalidFeeCfg
+	}
+	return nil
+}
+
+type FeeType string
+
+const (
+	FeeTypeProtocol FeeType = "protocol"
+	FeeTypeBurn     FeeType = "burn"
+	FeeTypeDonate   FeeType = "donate"
+	FeeTypeRoyalty  FeeType = "royalty"
+)
+
+type FeeLine struct {
+	Type      FeeType        `json:"type"`
+	Recipient domain.Address `json:"recipient"`
+	Amount    string         `json:"amount"`
+	// DisplayAmount is in the unit of the payment token
+	DisplayAmount string  `json:"displayAmount"`
+	AmountInUsd   float64 `json:"amountInUsd"`
+}
+
+type QuoteWarning string
+
+const (
+	QuoteWarningBelowMinPercentageToAsk QuoteWarning = "belowMinPercentageToAsk"
+	QuoteWarningStrategyForbidsSide     QuoteWarning = "strategyForbidsSide"
+	QuoteWarningUnsupportedStrategy     QuoteWarning = "unsupportedStrategy"
+	QuoteWarningRoyaltyUnavailable      QuoteWarning = "royaltyUnavailable"
+)
+
+type ItemQuote struct {
+	ItemIdx    int            `json:"itemIdx"`
+	Collection domain.Address `json:"collection"`
+	TokenId    domain.TokenId `json:"tokenId"`
+	Price      string         `json:"price"`
+	Fees       []FeeLine      `json:"fees"`
+	// NetProceeds is what the seller receives after all fees
+	NetProceeds        string         `json:"netProceeds"`
+	DisplayNetProceeds string         `json:"displayNetProceeds"`
+	NetProceedsInUsd   float64        `json:"netProceedsInUsd"`
+	Warnings           []QuoteWarning `json:"warnings"`
+}
+
+// FeeQuote is the fee breakdown of a draft order, warnings don't prevent the order from being made
+type FeeQuote struct {
+	ChainId  domain.ChainId `json:"chainId"`
+	IsAsk    bool           `json:"isAsk"`
+	Strategy Strategy       `json:"strategy"`
+	Currency domain.Address `json:"currency"`
+	Items    []ItemQuote    `json:"items"`
+	Warnings []QuoteWarning `json:"warnings"`
+}
+
+// IsSideAllowed returns false if the strategy can't be used by the side, collection offers are bids only and
+// private sales are asks only
+func (s Strategy) IsSideAllowed(isAsk bool) bool {
+	switch s {
+	case StrategyCollectionOffer:
+		return !isAsk
+	case StrategyPrivateSale:
+		return isAsk
+	}
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/royalty"
 )
 
 type Item struct {
@@ -15,6 +16,9 @@ type Item struct {
 	TokenId    domain.TokenId `json:"tokenId" bson:"tokenID"`
 	Amount     string         `json:"amount" bson:"amount"`
 	Price      string         `json:"price" bson:"price"`
+
+	// Royalty is resolved in responses and not stored
+	Royalty *royalty.Quote `json:"royalty,omitempty" bson:"-"`
 }
 
 func (i *Item) LowerCase() {
@@ -143,6 +147,7 @@ type OrderItemFindAllOptions struct {
 	OrderHash     *domain.OrderHash
 	OrderItemHash *domain.OrderHash
 	NftitemId     *nftitem.Id
+	NftitemIds    *[]nftitem.Id
 	Signer        *domain.Address
 	NonceLT       *string
 	IsValid       *bool
@@ -195,6 +200,13 @@ func WithNftItemId(nftitemId nftitem.Id) OrderItemFindAllOptionsFunc {
 	}
 }
 
+func WithNftItemIds(ids []nftitem.Id) OrderItemFindAllOptionsFunc {
+	return func(options *OrderItemFindAllOptions) error {
+		options.NftitemIds = &ids
+		return nil
+	}
+}
+
 func WithIsValid(isValid bool) OrderItemFindAllOptionsFunc {
 	return func(options *OrderItemFindAllOptions) error {
 		options.IsValid = &isValid
@@ -409,4 +421,8 @@ type UseCase interface {
 	CancelOrderItemByOrderItemHash(ctx ctx.Ctx, chainId domain.ChainId, orderItemHash domain.OrderHash, logCancelActivity bool, lMeta *domain.LogMeta) error
 	CancelOrderItemByNonce(ctx ctx.Ctx, chainId domain.ChainId, signer domain.Address, nonce *big.Int, lMeta *domain.LogMeta) error
 	RefreshOrders(ctx ctx.Ctx, nftitemId nftitem.Id) error
+	// QuoteFees returns the fee breakdown and net proceeds of a draft order, the order isn't signed or stored
+	QuoteFees(ctx ctx.Ctx, draft Order) (*FeeQuote, error)
+	// QuoteSweep returns the cheapest listed items of a collection across native orders and external listings
+	QuoteSweep(ctx ctx.Ctx, params SweepParams) (*SweepQuote, error)
 }
@@ -0,0 +1,76 @@
+package order
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+// MaxSweepCount is the max number of items in a sweep
+const MaxSweepCount = 100
+
+type SweepSource string
+
+const (
+	SweepSourceNative   SweepSource = "native"
+	SweepSourceExternal SweepSource = "external"
+)
+
+// SweepParams selects the cheapest listed items of a collection, either Count or Budget is required. If both are set
+// the sweep stops at whichever is reached first.
+type SweepParams struct {
+	ChainId    domain.ChainId `json:"chainId"`
+	Collection domain.Address `json:"collection"`
+	Count      int            `json:"count"`
+	// Budget is the max total price in the native token, e.g. "1.5" for 1.5 ETH
+	Budget     string                    `json:"budget"`
+	Attributes []nftitem.AttributeFilter `json:"attributes"`
+	// Buyer's own listings are excluded if set
+	Buyer domain.Address `json:"-"`
+}
+
+type SweepItem struct {
+	Source        SweepSource    `json:"source"`
+	ChainId       domain.ChainId `json:"chainId"`
+	Collection    domain.Address `json:"collection"`
+	TokenId       domain.TokenId `json:"tokenId"`
+	Seller        domain.Address `json:"seller"`
+	Currency      domain.Address `json:"currency"`
+	DisplayPrice  string         `json:"displayPrice"`
+	PriceInNative float64        `json:"priceInNative"`
+	PriceInUsd    float64        `json:"priceInUsd"`
+	// OrderItem is the order item to fill if source is native
+	OrderItem *OrderItem `json:"orderItem,omitempty"`
+	// ExternalListing is the listing to fill if source is external
+	ExternalListing *external_listing.ExternalListing `json:"externalListing,omitempty"`
+}
+
+// SweepQuote is the cheapest fillable set of items sorted by price, it may have less items than requested if the
+// collection doesn't have enough listings
+type SweepQuote struct {
+	ChainId       domain.ChainId `json:"chainId"`
+	Collection    domain.Address `json:"collection"`
+	Items         []SweepItem    `json:"items"`
+	TotalInNative string         `json:"totalInNative"`
+	TotalInUsd    float64        `json:"totalInUsd"`
+}
+
+func (p SweepParams) Validate() error {
+	if p.ChainId == 0 || p.Collection == "" {
+		return domain.ErrBadParamInput
+	}
+	if p.Count < 0 || p.Count > MaxSweepCount {
+		return domain.ErrBadParamInput
+	}
+	if p.Count == 0 && p.Budget == "" {
+		return domain.ErrBadParamInput
+	}
+	if p.Budget != "" {
+		budget, err := decimal.NewFromString(p.Budget)
+		if err != nil || budget.Sign() <= 0 {
+			return domain.ErrInvalidNumberFormat
+		}
+	}
+	return nil
+}
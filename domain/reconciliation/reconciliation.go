@@ -0,0 +1,52 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+type Field string
+
+const (
+	// FieldOwner is the owner of an erc721 token
+	FieldOwner Field = "owner"
+	// FieldBalance is the balance of an erc1155 token held by an account
+	FieldBalance Field = "balance"
+)
+
+// Drift is an audit row of a stored value which differs from the chain
+type Drift struct {
+	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
+	TokenId         domain.TokenId `json:"tokenId" bson:"tokenId"`
+	Field           Field          `json:"field" bson:"field"`
+	// Holder is the account of the balance, it's empty for owners
+	Holder     domain.Address `json:"holder,omitempty" bson:"holder,omitempty"`
+	Stored     string         `json:"stored" bson:"stored"`
+	Actual     string         `json:"actual" bson:"actual"`
+	Fixed      bool           `json:"fixed" bson:"fixed"`
+	DetectedAt time.Time      `json:"detectedAt" bson:"detectedAt"`
+}
+
+// Report sums up a reconciliation of a collection
+type Report struct {
+	ChainId         domain.ChainId `json:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress"`
+	Checked         int            `json:"checked"`
+	Drifted         int            `json:"drifted"`
+	Fixed           int            `json:"fixed"`
+	// Skipped is the number of tokens or holdings not compared as the tracker was processing events
+	Skipped int `json:"skipped"`
+}
+
+type DriftRepo interface {
+	Insert(c ctx.Ctx, drift Drift) error
+}
+
+type UseCase interface {
+	// Reconcile compares stored owners or balances of the collection with the chain and fixes drifts.
+	// sampleSize tokens or holdings are checked, all of them are checked if sampleSize isn't positive
+	Reconcile(c ctx.Ctx, chainId domain.ChainId, address domain.Address, tokenType domain.TokenType, sampleSize int) (*Report, error)
+}
@@ -0,0 +1,85 @@
+package royalty
+
+import (
+	"math/big"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+// RatePrecision is the sale price rates are resolved at, a rate of RatePrecision is 100%
+var RatePrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+type Source string
+
+const (
+	SourceErc2981       Source = "erc2981"
+	SourceRoyaltyEngine Source = "royaltyEngine"
+	SourceCollection    Source = "collection"
+	// SourceNone is resolved if the token has no royalty
+	SourceNone Source = "none"
+)
+
+type Recipient struct {
+	Address domain.Address `json:"address"`
+	// Rate is the amount received if the token is sold at RatePrecision
+	Rate string `json:"rate"`
+}
+
+// Royalty is the resolved royalty of a token, independent of the sale price
+type Royalty struct {
+	ChainId         domain.ChainId `json:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress"`
+	TokenId         domain.TokenId `json:"tokenId"`
+	Source          Source         `json:"source"`
+	Recipients      []Recipient    `json:"recipients"`
+}
+
+type Payout struct {
+	Address domain.Address `json:"address"`
+	Amount  string         `json:"amount"`
+}
+
+// Quote is the royalty of a sale, amounts are in the smallest unit of the currency
+type Quote struct {
+	Source       Source   `json:"source"`
+	Price        string   `json:"price"`
+	Recipients   []Payout `json:"recipients"`
+	TotalRoyalty string   `json:"totalRoyalty"`
+	// NetProceeds is what the seller receives before marketplace fees
+	NetProceeds string `json:"netProceeds"`
+}
+
+// Quote returns royalty amounts of the sale price, the total never exceeds the price
+func (r *Royalty) Quote(price *big.Int) *Quote {
+	total := new(big.Int)
+	payouts := []Payout{}
+	for _, recipient := range r.Recipients {
+		rate, ok := new(big.Int).SetString(recipient.Rate, 10)
+		if !ok {
+			continue
+		}
+		amount := new(big.Int).Mul(price, rate)
+		amount.Div(amount, RatePrecision)
+		if remaining := new(big.Int).Sub(price, total); amount.Cmp(remaining) > 0 {
+			amount = remaining
+		}
+		total.Add(total, amount)
+		payouts = append(payouts, Payout{Address: recipient.Address, Amount: amount.String()})
+	}
+	return &Quote{
+		Source:       r.Source,
+		Price:        price.String(),
+		Recipients:   payouts,
+		TotalRoyalty: total.String(),
+		NetProceeds:  new(big.Int).Sub(price, total).String(),
+	}
+}
+
+type UseCase interface {
+	// Resolve tries erc2981 royaltyInfo, the royalty engine and the registered collection royalty in order, results
+	// are cached per token
+	Resolve(c ctx.Ctx, id nftitem.Id) (*Royalty, error)
+	Quote(c ctx.Ctx, id nftitem.Id, price *big.Int) (*Quote, error)
+}
@@ -0,0 +1,48 @@
+package royalty
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	r := Royalty{
+		Source: SourceRoyaltyEngine,
+		Recipients: []Recipient{
+			{Address: "0x1", Rate: "25000000000000000"},
+			{Address: "0x2", Rate: "10000000000000000"},
+		},
+	}
+
+	q := r.Quote(big.NewInt(1000))
+	assert.Equal(t, SourceRoyaltyEngine, q.Source)
+	assert.Equal(t, []Payout{{Address: "0x1", Amount: "25"}, {Address: "0x2", Amount: "10"}}, q.Recipients)
+	assert.Equal(t, "35", q.TotalRoyalty)
+	assert.Equal(t, "965", q.NetProceeds)
+}
+
+func TestQuoteCapped(t *testing.T) {
+	r := Royalty{
+		Source: SourceErc2981,
+		Recipients: []Recipient{
+			{Address: "0x1", Rate: "900000000000000000"},
+			{Address: "0x2", Rate: "900000000000000000"},
+		},
+	}
+
+	q := r.Quote(big.NewInt(100))
+	assert.Equal(t, []Payout{{Address: "0x1", Amount: "90"}, {Address: "0x2", Amount: "10"}}, q.Recipients)
+	assert.Equal(t, "100", q.TotalRoyalty)
+	assert.Equal(t, "0", q.NetProceeds)
+}
+
+func TestQuoteNone(t *testing.T) {
+	r := Royalty{Source: SourceNone}
+
+	q := r.Quote(big.NewInt(100))
+	assert.Empty(t, q.Recipients)
+	assert.Equal(t, "0", q.TotalRoyalty)
+	assert.Equal(t, "100", q.NetProceeds)
+}
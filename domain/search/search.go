@@ -12,6 +12,10 @@ type Result struct {
 	Accounts    []*account.SimpleAccount `json:"accounts,omitempty"`
 	Collections []*collection.Collection `json:"collections,omitempty"`
 	Tokens      []*nftitem.SimpleNftItem `json:"tokens,omitempty"`
+	// Count is the number of all matched documents regardless of pagination
+	Count int `json:"count"`
+	// Facets is value counts of matched documents, keyed by facet name
+	Facets map[string]map[string]int `json:"facets,omitempty"`
 }
 
 const (
@@ -20,9 +24,94 @@ const (
 	Token      = "token"
 )
 
+// facet names
+const (
+	FacetKind       = "kind"
+	FacetChainId    = "chainId"
+	FacetCollection = "collection"
+	FacetCategory   = "category"
+	FacetVerified   = "verified"
+)
+
+type SearchOptions struct {
+	Filter      []string
+	Collections []domain.Address
+	ChainId     *domain.ChainId
+	Category    *string
+	Offset      int
+	Limit       *int
+}
+
+type SearchOptionsFunc func(*SearchOptions) error
+
+func GetSearchOptions(opts ...SearchOptionsFunc) (SearchOptions, error) {
+	res := SearchOptions{}
+
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return SearchOptions{}, err
+		}
+	}
+
+	return res, nil
+}
+
+// WithFilter limits the kinds (account, collection, token) to search
+func WithFilter(filter []string) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		options.Filter = filter
+		return nil
+	}
+}
+
+func WithCollections(collections []domain.Address) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		options.Collections = collections
+		return nil
+	}
+}
+
+func WithChainId(chainId domain.ChainId) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		options.ChainId = &chainId
+		return nil
+	}
+}
+
+func WithCategory(category string) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		options.Category = &category
+		return nil
+	}
+}
+
+func WithPagination(offset, limit int) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		if offset < 0 || limit <= 0 {
+			return domain.ErrBadParamInput
+		}
+		options.Offset = offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+// Indexer keeps the search index up to date, it is called when a document changes
+type Indexer interface {
+	IndexAccount(c ctx.Ctx, address domain.Address) error
+	IndexCollection(c ctx.Ctx, id collection.CollectionId) error
+	IndexToken(c ctx.Ctx, id nftitem.Id) error
+}
+
 type Usecase interface {
-	Search(c ctx.Ctx, keyword string, filter []string, collections []domain.Address) (*Result, error)
-	SearchAccounts(c ctx.Ctx, keyword string) (*Result, error)
-	SearchCollections(c ctx.Ctx, keyword string, collections []domain.Address) (*Result, error)
-	SearchTokens(c ctx.Ctx, keyword string, collections []domain.Address) (*Result, error)
+	Indexer
+
+	// Sync indexes documents changed since last sync, the whole index is rebuilt periodically
+	Sync(c ctx.Ctx) error
+
+	// Search searches all kinds, each kind is paged individually
+	Search(c ctx.Ctx, keyword string, opts ...SearchOptionsFunc) (*Result, error)
+	SearchAccounts(c ctx.Ctx, keyword string, opts ...SearchOptionsFunc) (*Result, error)
+	SearchCollections(c ctx.Ctx, keyword string, opts ...SearchOptionsFunc) (*Result, error)
+	SearchTokens(c ctx.Ctx, keyword string, opts ...SearchOptionsFunc) (*Result, error)
 }
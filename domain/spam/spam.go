@@ -0,0 +1,101 @@
+package spam
+
+import (
+	"errors"
+	"math"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+)
+
+type Signal string
+
+const (
+	// SignalMassAirdrop is set if a single transaction sends tokens to many accounts which didn't ask for them
+	SignalMassAirdrop Signal = "massAirdrop"
+	// SignalCopycatName is set if the name equals to a verified collection on the same chain
+	SignalCopycatName Signal = "copycatName"
+	// SignalCopycatImage is set if the hosted image looks like the logo of a verified collection
+	SignalCopycatImage Signal = "copycatImage"
+	// SignalSuspiciousUrl is set if the metadata is served from a raw ip, a url shortener or a bait domain
+	SignalSuspiciousUrl Signal = "suspiciousUrl"
+	// SignalNoHolderTransfers is set if none of the airdropped holders has ever transferred a token
+	SignalNoHolderTransfers Signal = "noHolderTransfers"
+	// SignalTransferBlocked is set if the holder of a token fails to transfer it
+	SignalTransferBlocked Signal = "transferBlocked"
+	// SignalBaitName is set if the name links to a site or promises a claim, common in airdropped scam contracts.
+	// it takes another signal to hide the contract
+	SignalBaitName Signal = "baitName"
+)
+
+// Weights of signals, the score is the sum of weights capped at 1
+var Weights = map[Signal]float64{
+	SignalMassAirdrop:       0.4,
+	SignalCopycatName:       0.4,
+	SignalCopycatImage:      0.4,
+	SignalSuspiciousUrl:     0.3,
+	SignalNoHolderTransfers: 0.2,
+	SignalTransferBlocked:   0.6,
+	SignalBaitName:          0.3,
+}
+
+var ErrInvalidOverride = errors.New("invalid spam override")
+
+// Override is set by moderators to correct the classifier, the classifier decides if it's empty or OverrideNone
+type Override string
+
+const (
+	OverrideNone    Override = "none"
+	OverrideSpam    Override = "spam"
+	OverrideNotSpam Override = "notSpam"
+)
+
+func (o Override) IsValid() bool {
+	return o == OverrideNone || o == OverrideSpam || o == OverrideNotSpam
+}
+
+// Threshold is the score from which collections and tokens are hidden by default
+const Threshold = 0.5
+
+type Result struct {
+	Score   float64  `json:"score"`
+	Signals []Signal `json:"signals"`
+}
+
+func Score(signals []Signal) float64 {
+	score := 0.0
+	for _, s := range signals {
+		score += Weights[s]
+	}
+	return math.Min(score, 1)
+}
+
+// ScoreOf scores signals of a collection or its tokens, verified collections are never spam unless moderators
+// override it
+func ScoreOf(signals []Signal, isVerified bool, override Override) float64 {
+	switch override {
+	case OverrideSpam:
+		return 1
+	case OverrideNotSpam:
+		return 0
+	}
+	if isVerified {
+		return 0
+	}
+	return Score(signals)
+}
+
+func IsSpam(score float64) bool {
+	return score >= Threshold
+}
+
+type UseCase interface {
+	// Classify evaluates the signals of the contract without storing the result
+	Classify(c ctx.Ctx, chainId domain.ChainId, address domain.Address) (*Result, error)
+	// Refresh classifies the contract and stores spam scores to the collection, or to the pending registration if
+	// the contract isn't registered, and its tokens
+	Refresh(c ctx.Ctx, chainId domain.ChainId, address domain.Address) (*Result, error)
+	// SetOverride stores the override of moderators to the collection and rescores it and its tokens with the stored
+	// signals
+	SetOverride(c ctx.Ctx, chainId domain.ChainId, address domain.Address, override Override) (*Result, error)
+}
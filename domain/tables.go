@@ -42,4 +42,18 @@ const (
 	TableIpListings                Table = "ipListings"
 	TableApeStakings               Table = "apecoinStakings"
 	TableTwelvefold                Table = "twelvefold"
+	TableApiKeys                   Table = "apiKeys"
+	TableAirdropRounds             Table = "airdropRounds"
+	TableRewardDistributions       Table = "rewardDistributions"
+	TableRewardLedger              Table = "rewardLedger"
+	TableReports                   Table = "reports"
+	TableAuditLogs                 Table = "auditLogs"
+	TableUnlockableAccessLogs      Table = "unlockableAccessLogs"
+	TableMintVouchers              Table = "mintVouchers"
+	TableReconciliationDrifts      Table = "reconciliationDrifts"
+	TableCollectionHolderStats     Table = "collectionHolderStats"
+	TableAlertRules                Table = "alertRules"
+	TableComments                  Table = "comments"
+	TableCommentReactions          Table = "commentReactions"
+	TableLinkedWallets             Table = "linkedWallets"
 )
@@ -36,8 +36,9 @@ type SearchParams struct {
 	BidOwner              *domain.Address   `query:"bidOwner"`
 	IncludeOrders         *bool             `query:"includeOrders"`
 	IncludeInactiveOrders *bool             `query:"includeInactiveOrders"`
-	// if cursor != nil, search result will store in cache
-	// if cursor == nil, search result will bypass cache
+	// spam tokens are hidden unless IncludeSpam is true
+	IncludeSpam *bool `query:"includeSpam"`
+	// if cursor != nil, search result is paged by cursor instead of offset, empty cursor for the first page
 	Cursor *string `query:"cursor"`
 	// Size will be ignored if Cursor == nil
 	Size *int `query:"size"`
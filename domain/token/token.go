@@ -1,7 +1,6 @@
 package token
 
 import (
-	"encoding/json"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +10,8 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/royalty"
+	"github.com/x-xyz/goapi/domain/unlockable"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -62,24 +63,11 @@ type SearchOptions struct {
 	IncludeOrders         *bool                     `json:"IncludeOrders"`
 	IncludeInactiveOrders *bool                     `json:"IncludeInactiveOrders"`
 	HasOrder              *bool                     `json:"HasOrder"`
+	IsSpam                *bool                     `json:"IsSpam"`
 	Cursor                *string                   `json:"-"`
 	Size                  *int                      `json:"-"`
 }
 
-func OptionsToKey(opts SearchOptions) string {
-	key, _ := json.Marshal(opts)
-	return string(key)
-}
-
-func ParseKeyToOptions(key string) (*SearchOptions, error) {
-	opts := SearchOptions{}
-	err := json.Unmarshal([]byte(key), &opts)
-	if err != nil {
-		return nil, err
-	}
-	return &opts, nil
-}
-
 type SearchOptionsFunc func(*SearchOptions) error
 
 func GetSearchOptions(opts ...SearchOptionsFunc) (SearchOptions, error) {
@@ -349,6 +337,7 @@ func WithHasOrder(hasOrder bool) SearchOptionsFunc {
 	}
 }
 
+// SearchResult is a page of search, Count is 0 on pages after the first one when paging by cursor
 type SearchResult struct {
 	Items      []*TokenWithDetail `json:"items"`
 	Count      int                `json:"count"`
@@ -356,8 +345,9 @@ type SearchResult struct {
 }
 
 type ActivityResult struct {
-	Items []account.ActivityHistory `json:"items"`
-	Count int                       `json:"count"`
+	Items      []account.ActivityHistory `json:"items"`
+	Count      int                       `json:"count"`
+	NextCursor string                    `json:"nextCursor"`
 }
 
 type TokenWithDetail struct {
@@ -373,6 +363,8 @@ type TokenWithDetail struct {
 	InactiveListings []*order.OrderItem `json:"inactiveListings,omitempty"`
 	Offers           []*order.OrderItem `json:"offers,omitempty"`
 	ActiveListing    *order.OrderItem   `json:"activeListing,omitempty"`
+	// inject in get token, order items above are quoted with it
+	Royalty *royalty.Royalty `json:"royalty,omitempty"`
 }
 
 type UploadPayload struct {
@@ -405,10 +397,21 @@ type Usecase interface {
 	Search(c ctx.Ctx, opts ...SearchOptionsFunc) (*SearchResult, error)
 	SearchV2(c ctx.Ctx, opts ...SearchOptionsFunc) (*SearchResult, error)
 	FindOne(c ctx.Ctx, id nftitem.Id) (*TokenWithDetail, error)
-	GetActivities(c ctx.Ctx, id nftitem.Id, offet, limit int) (*ActivityResult, error)
+	GetActivities(c ctx.Ctx, id nftitem.Id, opts ...account.FindActivityHistoryOptions) (*ActivityResult, error)
 	GetPriceHistories(c ctx.Ctx, id nftitem.Id, period domain.TimePeriod) ([]PriceHistory, error)
-	AddUnlockableContent(c ctx.Ctx, id nftitem.Id, content string) error
-	GetUnlockableContent(c ctx.Ctx, id nftitem.Id) (string, error)
+	// AddUnlockableContent encrypts and stores the content, the creator must own the token
+	AddUnlockableContent(c ctx.Ctx, id nftitem.Id, creator domain.Address, content string) error
+	HasUnlockableContent(c ctx.Ctx, id nftitem.Id) (bool, error)
+	// RevealUnlockableContent decrypts the content for the current owner and logs the access
+	RevealUnlockableContent(c ctx.Ctx, id nftitem.Id, viewer domain.Address) (string, error)
+	// GetUnlockableAccessLogs returns reveals of the content, only the creator can view them, contents added before
+	// creators were recorded fall back to the creator of the token
+	GetUnlockableAccessLogs(c ctx.Ctx, id nftitem.Id, requester domain.Address, opts ...unlockable.AccessLogFindAllOptionsFunc) ([]unlockable.AccessLog, error)
+	// RotateUnlockableKeys wraps data keys by the current master key and encrypts plaintext contents, returns the
+	// number of updated contents
+	RotateUnlockableKeys(c ctx.Ctx) (int, error)
+	// EncryptPlaintextUnlockables encrypts contents added before encryption, returns the number of encrypted contents
+	EncryptPlaintextUnlockables(c ctx.Ctx) (int, error)
 	BanNftItem(c ctx.Ctx, id nftitem.Id) error
 	UnbanNftItem(c ctx.Ctx, id nftitem.Id) error
 	Upload(c ctx.Ctx, account domain.Address, payload UploadPayload) (*UploadResult, error)
@@ -447,3 +450,10 @@ func FromTokenKey(key string) (domain.ChainId, domain.Address, domain.TokenId, e
 	tokenId := parts[2]
 	return domain.ChainId(chainId), domain.Address(parts[1]), domain.TokenId(tokenId), nil
 }
+
+func WithIsSpam(isSpam bool) SearchOptionsFunc {
+	return func(options *SearchOptions) error {
+		options.IsSpam = &isSpam
+		return nil
+	}
+}
@@ -1,24 +1,139 @@
 package unlockable
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
 )
 
+// MaxAccessLogLimit is the max number of access logs returned at once
+const MaxAccessLogLimit = 100
+
+var (
+	ErrNotOwner           = errors.New("not owner of the token")
+	ErrNotCreator         = errors.New("not creator of the unlockable content")
+	ErrEncryptionDisabled = errors.New("unlockable content encryption is not configured")
+)
+
 type UnlockableId struct {
 	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
 	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
 	TokenId         domain.TokenId `json:"tokenId" bson:"tokenID"`
 }
 
+// Aad is the additional authenticated data binding the encrypted content to the token
+func (id UnlockableId) Aad() []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s", id.ChainId, id.ContractAddress.ToLowerStr(), id.TokenId))
+}
+
 type Unlockable struct {
 	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
 	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
 	TokenId         domain.TokenId `json:"tokenId" bson:"tokenID"`
-	Content         string         `json:"content" bson:"content"`
+	// Content is the plaintext of content added before encryption, it's cleared once the content is encrypted
+	Content string         `json:"-" bson:"content,omitempty"`
+	Creator domain.Address `json:"creator,omitempty" bson:"creator,omitempty"`
+	// KeyId is the id of the master key wrapping the data key, WrappedKey and Ciphertext are prefixed by nonces
+	KeyId      string    `json:"-" bson:"keyId,omitempty"`
+	WrappedKey []byte    `json:"-" bson:"wrappedKey,omitempty"`
+	Ciphertext []byte    `json:"-" bson:"ciphertext,omitempty"`
+	CreatedAt  time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+}
+
+func (u *Unlockable) ToId() UnlockableId {
+	return UnlockableId{ChainId: u.ChainId, ContractAddress: u.ContractAddress, TokenId: u.TokenId}
+}
+
+func (u *Unlockable) IsEncrypted() bool {
+	return u.KeyId != ""
+}
+
+// Updater replaces the content by the encrypted one
+type Updater struct {
+	Content    *string `bson:"content"`
+	KeyId      *string `bson:"keyId"`
+	WrappedKey []byte  `bson:"wrappedKey"`
+	Ciphertext []byte  `bson:"ciphertext"`
+}
+
+// AccessLog is a record of revealing an unlockable content
+type AccessLog struct {
+	ChainId         domain.ChainId `json:"chainId" bson:"chainId"`
+	ContractAddress domain.Address `json:"contractAddress" bson:"contractAddress"`
+	TokenId         domain.TokenId `json:"tokenId" bson:"tokenID"`
+	Viewer          domain.Address `json:"viewer" bson:"viewer"`
+	CreatedAt       time.Time      `json:"createdAt" bson:"createdAt"`
+}
+
+type AccessLogFindAllOptions struct {
+	SortBy          *string         `bson:"-"`
+	SortDir         *domain.SortDir `bson:"-"`
+	Offset          *int32          `bson:"-"`
+	Limit           *int32          `bson:"-"`
+	ChainId         *domain.ChainId `bson:"chainId"`
+	ContractAddress *domain.Address `bson:"contractAddress"`
+	TokenId         *domain.TokenId `bson:"tokenID"`
+	Viewer          *domain.Address `bson:"viewer"`
+}
+
+type AccessLogFindAllOptionsFunc func(*AccessLogFindAllOptions) error
+
+func GetAccessLogFindAllOptions(opts ...AccessLogFindAllOptionsFunc) (AccessLogFindAllOptions, error) {
+	res := AccessLogFindAllOptions{}
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func AccessLogWithSort(sortby string, sortdir domain.SortDir) AccessLogFindAllOptionsFunc {
+	return func(options *AccessLogFindAllOptions) error {
+		options.SortBy = &sortby
+		options.SortDir = &sortdir
+		return nil
+	}
+}
+
+func AccessLogWithPagination(offset int32, limit int32) AccessLogFindAllOptionsFunc {
+	return func(options *AccessLogFindAllOptions) error {
+		options.Offset = &offset
+		options.Limit = &limit
+		return nil
+	}
+}
+
+func AccessLogWithToken(id UnlockableId) AccessLogFindAllOptionsFunc {
+	return func(options *AccessLogFindAllOptions) error {
+		options.ChainId = &id.ChainId
+		options.ContractAddress = id.ContractAddress.ToLowerPtr()
+		options.TokenId = &id.TokenId
+		return nil
+	}
+}
+
+func AccessLogWithViewer(viewer domain.Address) AccessLogFindAllOptionsFunc {
+	return func(options *AccessLogFindAllOptions) error {
+		options.Viewer = viewer.ToLowerPtr()
+		return nil
+	}
 }
 
 type Repo interface {
 	FindOne(c ctx.Ctx, id UnlockableId) (*Unlockable, error)
 	Create(c ctx.Ctx, val Unlockable) error
+	// FindAllNotSealedBy returns contents not encrypted by the master key, including plaintext ones
+	FindAllNotSealedBy(c ctx.Ctx, keyId string, limit int) ([]Unlockable, error)
+	// FindAllPlaintext returns contents added before encryption
+	FindAllPlaintext(c ctx.Ctx, limit int) ([]Unlockable, error)
+	Update(c ctx.Ctx, id UnlockableId, updater Updater) error
+}
+
+type AccessLogRepo interface {
+	FindAll(c ctx.Ctx, opts ...AccessLogFindAllOptionsFunc) ([]AccessLog, error)
+	Insert(c ctx.Ctx, log *AccessLog) error
 }
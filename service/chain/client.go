@@ -7,7 +7,9 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	bCtx "github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/log"
 )
@@ -19,13 +21,36 @@ type ClientCfg struct {
 	ArchiveRpcUrls map[int32]string
 }
 
+// CallRequest is a contract call sent in a batch
+type CallRequest struct {
+	To     common.Address
+	Abi    abi.ABI
+	Method string
+	Params []interface{}
+}
+
+// CallResult is the unpacked outputs of a call in a batch, Err is set if the call reverts
+type CallResult struct {
+	Outputs []interface{}
+	Err     error
+}
+
 type Client interface {
 	Call(bCtx.Ctx, int32, common.Address, *big.Int, abi.ABI, string, ...interface{}) ([]interface{}, error)
+	// CallFrom is Call sent by the given account at the latest block, for methods depending on msg.sender
+	CallFrom(bCtx.Ctx, int32, common.Address, common.Address, abi.ABI, string, ...interface{}) ([]interface{}, error)
+	// BatchCall sends calls at the block, or at the latest block if it's nil, in a single json-rpc batch,
+	// failed calls don't fail the others. the block should be recent as the calls aren't sent to an archive rpc
+	BatchCall(bCtx.Ctx, int32, *big.Int, []CallRequest) ([]CallResult, error)
+	// Deployer returns the account deploying the contract, it requires an archive rpc of the chain
+	Deployer(bCtx.Ctx, int32, common.Address) (common.Address, error)
 }
 
 type clientImpl struct {
 	clients        map[int32]*ethclient.Client
 	archiveClients map[int32]*ethclient.Client
+	// rpcClients are the underlying clients of clients
+	rpcClients map[int32]*rpc.Client
 }
 
 func NewClient(ctx bCtx.Ctx, cfg *ClientCfg) (Client, error) {
@@ -33,8 +58,9 @@ func NewClient(ctx bCtx.Ctx, cfg *ClientCfg) (Client, error) {
 		anyerr error
 	)
 	clients := make(map[int32]*ethclient.Client)
+	rpcClients := make(map[int32]*rpc.Client)
 	for chainId, url := range cfg.RpcUrls {
-		client, err := ethclient.DialContext(ctx, url)
+		rpcClient, err := rpc.DialContext(ctx, url)
 		if err != nil {
 			anyerr = err
 			ctx.WithFields(log.Fields{
@@ -45,7 +71,8 @@ func NewClient(ctx bCtx.Ctx, cfg *ClientCfg) (Client, error) {
 			// soft warning, still let the server start
 			continue
 		}
-		clients[chainId] = client
+		clients[chainId] = ethclient.NewClient(rpcClient)
+		rpcClients[chainId] = rpcClient
 	}
 	archiveClients := make(map[int32]*ethclient.Client)
 	for chainId, url := range cfg.ArchiveRpcUrls {
@@ -65,6 +92,7 @@ func NewClient(ctx bCtx.Ctx, cfg *ClientCfg) (Client, error) {
 	return &clientImpl{
 		clients:        clients,
 		archiveClients: archiveClients,
+		rpcClients:     rpcClients,
 	}, anyerr
 }
 
@@ -81,7 +109,18 @@ func (c *clientImpl) Call(ctx bCtx.Ctx, chainId int32, addr common.Address, blk
 	if !ok {
 		return nil, ErrUnsupportedChain
 	}
+	return c.call(ctx, client, common.Address{}, addr, blk, _abi, method, params...)
+}
+
+func (c *clientImpl) CallFrom(ctx bCtx.Ctx, chainId int32, from common.Address, addr common.Address, _abi abi.ABI, method string, params ...interface{}) ([]interface{}, error) {
+	client, ok := c.clients[chainId]
+	if !ok {
+		return nil, ErrUnsupportedChain
+	}
+	return c.call(ctx, client, from, addr, nil, _abi, method, params...)
+}
 
+func (c *clientImpl) call(ctx bCtx.Ctx, client *ethclient.Client, from common.Address, addr common.Address, blk *big.Int, _abi abi.ABI, method string, params ...interface{}) ([]interface{}, error) {
 	data, err := _abi.Pack(method, params...)
 	if err != nil {
 		ctx.WithFields(log.Fields{
@@ -92,6 +131,7 @@ func (c *clientImpl) Call(ctx bCtx.Ctx, chainId int32, addr common.Address, blk
 		return nil, err
 	}
 	msg := ethereum.CallMsg{
+		From: from,
 		To:   &addr,
 		Data: data,
 	}
@@ -107,3 +147,60 @@ func (c *clientImpl) Call(ctx bCtx.Ctx, chainId int32, addr common.Address, blk
 	}
 	return unpacked, nil
 }
+
+func (c *clientImpl) Deployer(ctx bCtx.Ctx, chainId int32, addr common.Address) (common.Address, error) {
+	client, ok := c.archiveClients[chainId]
+	if !ok {
+		return common.Address{}, ErrUnsupportedChain
+	}
+	return GetDeployer(ctx, client, addr)
+}
+
+func (c *clientImpl) BatchCall(ctx bCtx.Ctx, chainId int32, blk *big.Int, reqs []CallRequest) ([]CallResult, error) {
+	client, ok := c.rpcClients[chainId]
+	if !ok {
+		return nil, ErrUnsupportedChain
+	}
+
+	blkArg := "latest"
+	if blk != nil {
+		blkArg = hexutil.EncodeBig(blk)
+	}
+
+	outputs := make([]hexutil.Bytes, len(reqs))
+	elems := make([]rpc.BatchElem, len(reqs))
+	for i, req := range reqs {
+		data, err := req.Abi.Pack(req.Method, req.Params...)
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"method": req.Method,
+				"params": req.Params,
+				"err":    err,
+			}).Error("abi.Pack failed")
+			return nil, err
+		}
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{
+				map[string]interface{}{"to": req.To, "data": hexutil.Bytes(data)},
+				blkArg,
+			},
+			Result: &outputs[i],
+		}
+	}
+
+	if err := client.BatchCallContext(ctx, elems); err != nil {
+		ctx.WithField("err", err).Error("client.BatchCallContext failed")
+		return nil, err
+	}
+
+	res := make([]CallResult, len(reqs))
+	for i, elem := range elems {
+		if elem.Error != nil {
+			res[i].Err = elem.Error
+			continue
+		}
+		res[i].Outputs, res[i].Err = reqs[i].Abi.Unpack(reqs[i].Method, outputs[i])
+	}
+	return res, nil
+}
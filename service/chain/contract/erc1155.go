@@ -1,6 +1,8 @@
 package contract
 
 import (
+	"math/big"
+
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	baseabi "github.com/x-xyz/goapi/base/abi"
@@ -12,6 +14,11 @@ type Erc1155Contract interface {
 	Supports1155Interface(ctx bCtx.Ctx, chainId int32, addr string) (bool, error)
 }
 
+type Erc1155BalanceContract interface {
+	// BalancesOf returns balances of owners[i] on tokenIds[i] at the block in a batch
+	BalancesOf(ctx bCtx.Ctx, chainId int32, addr string, blk *big.Int, owners []string, tokenIds []*big.Int) ([]*big.Int, error)
+}
+
 type Erc1155 struct {
 	chainService       chain.Client
 	abi                ethabi.ABI
@@ -36,3 +43,22 @@ func (e *Erc1155) Supports1155Interface(ctx bCtx.Ctx, chainId int32, addr string
 	}
 	return unpacked[0].(bool), nil
 }
+
+func (e *Erc1155) BalancesOf(ctx bCtx.Ctx, chainId int32, addr string, blk *big.Int, owners []string, tokenIds []*big.Int) ([]*big.Int, error) {
+	reqs := make([]chain.CallRequest, len(owners))
+	for i := range owners {
+		reqs[i] = chain.CallRequest{To: common.HexToAddress(addr), Abi: e.abi, Method: "balanceOf", Params: []interface{}{common.HexToAddress(owners[i]), tokenIds[i]}}
+	}
+	results, err := e.chainService.BatchCall(ctx, chainId, blk, reqs)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(results))
+	for i, res := range results {
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		balances[i] = res.Outputs[0].(*big.Int)
+	}
+	return balances, nil
+}
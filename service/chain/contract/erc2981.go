@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"math/big"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	baseabi "github.com/x-xyz/goapi/base/abi"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/service/chain"
+)
+
+type Erc2981Contract interface {
+	RoyaltyInfo(ctx bCtx.Ctx, chainId int32, addr string, tokenId *big.Int, salePrice *big.Int) (string, *big.Int, error)
+}
+
+type Erc2981 struct {
+	chainService chain.Client
+	abi          ethabi.ABI
+}
+
+func NewErc2981(chainService chain.Client) Erc2981Contract {
+	return &Erc2981{
+		abi:          baseabi.ERC2981ABI,
+		chainService: chainService,
+	}
+}
+
+func (e *Erc2981) RoyaltyInfo(ctx bCtx.Ctx, chainId int32, addr string, tokenId *big.Int, salePrice *big.Int) (string, *big.Int, error) {
+	method := "royaltyInfo"
+	unpacked, err := e.chainService.Call(ctx, chainId, common.HexToAddress(addr), nil, e.abi, method, tokenId, salePrice)
+	if err != nil {
+		return "", nil, err
+	}
+	return unpacked[0].(common.Address).String(), unpacked[1].(*big.Int), nil
+}
@@ -2,6 +2,7 @@ package contract
 
 import (
 	"math/big"
+	"strings"
 
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,6 +15,19 @@ type Erc721Contract interface {
 	Supports721Interface(ctx bCtx.Ctx, chainId int32, addr string) (bool, error)
 }
 
+// transferProbe is the recipient of simulated transfers
+var transferProbe = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+type Erc721TransferContract interface {
+	// CanTransfer simulates transferFrom of the token by its owner, it's false if the transfer reverts
+	CanTransfer(ctx bCtx.Ctx, chainId int32, addr string, owner string, tokenId *big.Int) (bool, error)
+}
+
+type Erc721OwnerContract interface {
+	// OwnersOf returns owners of tokens at the block in a batch, the owner is empty if ownerOf reverts, e.g. the token is burned
+	OwnersOf(ctx bCtx.Ctx, chainId int32, addr string, blk *big.Int, tokenIds []*big.Int) ([]string, error)
+}
+
 type Erc721 struct {
 	chainService      chain.Client
 	abi               ethabi.ABI
@@ -47,3 +61,38 @@ func (e *Erc721) OwnerOf(ctx bCtx.Ctx, chainId int32, addr string, tokenId *big.
 	}
 	return unpacked[0].(common.Address).String(), nil
 }
+
+func (e *Erc721) OwnersOf(ctx bCtx.Ctx, chainId int32, addr string, blk *big.Int, tokenIds []*big.Int) ([]string, error) {
+	reqs := make([]chain.CallRequest, len(tokenIds))
+	for i, tokenId := range tokenIds {
+		reqs[i] = chain.CallRequest{To: common.HexToAddress(addr), Abi: e.abi, Method: "ownerOf", Params: []interface{}{tokenId}}
+	}
+	results, err := e.chainService.BatchCall(ctx, chainId, blk, reqs)
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]string, len(results))
+	for i, res := range results {
+		if res.Err != nil {
+			if strings.Contains(res.Err.Error(), "execution reverted") {
+				continue
+			}
+			return nil, res.Err
+		}
+		owners[i] = res.Outputs[0].(common.Address).String()
+	}
+	return owners, nil
+}
+
+func (e *Erc721) CanTransfer(ctx bCtx.Ctx, chainId int32, addr string, owner string, tokenId *big.Int) (bool, error) {
+	method := "transferFrom"
+	from := common.HexToAddress(owner)
+	_, err := e.chainService.CallFrom(ctx, chainId, from, common.HexToAddress(addr), e.abi, method, from, transferProbe, tokenId)
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
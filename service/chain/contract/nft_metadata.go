@@ -0,0 +1,46 @@
+package contract
+
+import (
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	baseabi "github.com/x-xyz/goapi/base/abi"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/service/chain"
+)
+
+// NftMetadataContract reads name() and symbol() of the metadata extension,
+// they are optional for erc1155 contracts so callers should expect reverts
+type NftMetadataContract interface {
+	Name(ctx bCtx.Ctx, chainId int32, addr string) (string, error)
+	Symbol(ctx bCtx.Ctx, chainId int32, addr string) (string, error)
+}
+
+type NftMetadata struct {
+	chainService chain.Client
+	abi          ethabi.ABI
+}
+
+func NewNftMetadata(chainService chain.Client) NftMetadataContract {
+	return &NftMetadata{
+		abi:          baseabi.ERC721TokenABI,
+		chainService: chainService,
+	}
+}
+
+func (m *NftMetadata) Name(ctx bCtx.Ctx, chainId int32, addr string) (string, error) {
+	method := "name"
+	unpacked, err := m.chainService.Call(ctx, chainId, common.HexToAddress(addr), nil, m.abi, method)
+	if err != nil {
+		return "", err
+	}
+	return unpacked[0].(string), nil
+}
+
+func (m *NftMetadata) Symbol(ctx bCtx.Ctx, chainId int32, addr string) (string, error) {
+	method := "symbol"
+	unpacked, err := m.chainService.Call(ctx, chainId, common.HexToAddress(addr), nil, m.abi, method)
+	if err != nil {
+		return "", err
+	}
+	return unpacked[0].(string), nil
+}
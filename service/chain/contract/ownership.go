@@ -0,0 +1,59 @@
+package contract
+
+import (
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	baseabi "github.com/x-xyz/goapi/base/abi"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/service/chain"
+)
+
+// DefaultAdminRole is DEFAULT_ADMIN_ROLE of AccessControl
+var DefaultAdminRole = [32]byte{}
+
+type OwnershipContract interface {
+	// Owner calls owner() of Ownable
+	Owner(ctx bCtx.Ctx, chainId int32, addr string) (string, error)
+	// HasRole calls hasRole() of AccessControl
+	HasRole(ctx bCtx.Ctx, chainId int32, addr string, role [32]byte, account string) (bool, error)
+	// Deployer returns the account sending the contract creation transaction
+	Deployer(ctx bCtx.Ctx, chainId int32, addr string) (string, error)
+}
+
+type Ownership struct {
+	chainService chain.Client
+	abi          ethabi.ABI
+}
+
+func NewOwnership(chainService chain.Client) OwnershipContract {
+	return &Ownership{
+		abi:          baseabi.OwnershipABI,
+		chainService: chainService,
+	}
+}
+
+func (o *Ownership) Owner(ctx bCtx.Ctx, chainId int32, addr string) (string, error) {
+	method := "owner"
+	unpacked, err := o.chainService.Call(ctx, chainId, common.HexToAddress(addr), nil, o.abi, method)
+	if err != nil {
+		return "", err
+	}
+	return unpacked[0].(common.Address).String(), nil
+}
+
+func (o *Ownership) HasRole(ctx bCtx.Ctx, chainId int32, addr string, role [32]byte, account string) (bool, error) {
+	method := "hasRole"
+	unpacked, err := o.chainService.Call(ctx, chainId, common.HexToAddress(addr), nil, o.abi, method, role, common.HexToAddress(account))
+	if err != nil {
+		return false, err
+	}
+	return unpacked[0].(bool), nil
+}
+
+func (o *Ownership) Deployer(ctx bCtx.Ctx, chainId int32, addr string) (string, error) {
+	deployer, err := o.chainService.Deployer(ctx, chainId, common.HexToAddress(addr))
+	if err != nil {
+		return "", err
+	}
+	return deployer.String(), nil
+}
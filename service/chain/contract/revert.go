@@ -0,0 +1,24 @@
+package contract
+
+import "strings"
+
+var revertErrors = []string{
+	"execution reverted",
+	// the contract doesn't exist or doesn't implement the method
+	"abi: attempting to unmarshall an empty string while arguments are expected",
+	"invalid opcode: INVALID",
+	"invalid jump destination",
+}
+
+// IsReverted returns true if the call is rejected by the contract, other errors like rpc failures are worth retrying
+func IsReverted(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, msg := range revertErrors {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+)
+
+// ErrDeployerNotFound is returned if the contract isn't deployed by a transaction directly, e.g. by a factory
+var ErrDeployerNotFound = errors.New("deployer not found")
+
+// GetDeployedBlock binary searches the first block having code at addr, c should be an archive client
+func GetDeployedBlock(ctx bCtx.Ctx, c domain.EthClientRepo, addr common.Address) (uint64, error) {
+	blk, err := c.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	l := blk
+	s := blk
+	for l > 0 {
+		step := l / 2
+		mid := s - step - 1
+		b, err := c.CodeAt(ctx, addr, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if len(b) > 0 {
+			s = mid
+			l -= step + 1
+		} else {
+			l = step
+		}
+	}
+	return s, nil
+}
+
+// GetDeployer returns the sender of the transaction creating the contract at addr
+func GetDeployer(ctx bCtx.Ctx, c domain.EthClientRepo, addr common.Address) (common.Address, error) {
+	blkNum, err := GetDeployedBlock(ctx, c, addr)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"addr": addr,
+			"err":  err,
+		}).Error("GetDeployedBlock failed")
+		return common.Address{}, err
+	}
+	blk, err := c.BlockByNumber(ctx, new(big.Int).SetUint64(blkNum))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"blk": blkNum,
+			"err": err,
+		}).Error("BlockByNumber failed")
+		return common.Address{}, err
+	}
+	for _, tx := range blk.Transactions() {
+		// only contract creations have no recipient
+		if tx.To() != nil {
+			continue
+		}
+		receipt, err := c.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"tx":  tx.Hash(),
+				"err": err,
+			}).Error("TransactionReceipt failed")
+			return common.Address{}, err
+		}
+		if receipt.ContractAddress != addr {
+			continue
+		}
+		return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	}
+	return common.Address{}, ErrDeployerNotFound
+}
@@ -0,0 +1,52 @@
+package ens
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// registryAddress is ENS registry on ethereum
+	registryAddress = common.HexToAddress("0x00000000000C2E074eC69A0bFb2993bA3cD8D8cE")
+	// reverseRecordsAddress is ENS ReverseRecords on ethereum, it forward verifies reverse records on chain
+	reverseRecordsAddress = common.HexToAddress("0x3671aE578E63FdF66ad4F3E12CC0c0d71Ac7510C")
+	// multicallAddress is Multicall3 which is deployed at the same address on most chains
+	multicallAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+)
+
+const (
+	registryABIJson       = `[{"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+	resolverABIJson       = `[{"inputs":[{"name":"node","type":"bytes32"},{"name":"key","type":"string"}],"name":"text","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+	reverseRecordsABIJson = `[{"inputs":[{"name":"addresses","type":"address[]"}],"name":"getNames","outputs":[{"name":"r","type":"string[]"}],"stateMutability":"view","type":"function"}]`
+	multicallABIJson      = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+	tokenABIJson          = `[{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"id","type":"uint256"}],"name":"uri","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+)
+
+var (
+	registryABI       = mustParseABI(registryABIJson)
+	resolverABI       = mustParseABI(resolverABIJson)
+	reverseRecordsABI = mustParseABI(reverseRecordsABIJson)
+	multicallABI      = mustParseABI(multicallABIJson)
+	tokenABI          = mustParseABI(tokenABIJson)
+)
+
+func mustParseABI(s string) abi.ABI {
+	res, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+type call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type callResult struct {
+	Success    bool
+	ReturnData []byte
+}
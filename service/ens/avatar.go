@@ -0,0 +1,216 @@
+package ens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/keys"
+)
+
+var (
+	ErrUnsupportedAvatar = errors.New("unsupported avatar uri")
+	// ErrAvatarNotOwned is returned if the nft of the avatar isn't owned by the address of the name (ENSIP-12)
+	ErrAvatarNotOwned = errors.New("avatar nft not owned")
+)
+
+type nftStandard string
+
+const (
+	nftStandardErc721  nftStandard = "erc721"
+	nftStandardErc1155 nftStandard = "erc1155"
+)
+
+// nftAvatar is an avatar uri pointing to a nft, e.g. eip155:1/erc721:0xb47e3cd837ddf8e4c57f05d70ab865de6e193bbb/0
+type nftAvatar struct {
+	chainId  string
+	standard nftStandard
+	contract common.Address
+	tokenId  *big.Int
+}
+
+func parseNftAvatar(uri string) (*nftAvatar, error) {
+	// eip155:<chainId>/<standard>:<contract>/<tokenId>
+	lower := strings.ToLower(uri)
+	if !strings.HasPrefix(lower, "eip155:") {
+		return nil, ErrUnsupportedAvatar
+	}
+	parts := strings.Split(strings.TrimPrefix(lower, "eip155:"), "/")
+	if len(parts) != 3 {
+		return nil, ErrUnsupportedAvatar
+	}
+
+	asset := strings.SplitN(parts[1], ":", 2)
+	if len(asset) != 2 || !common.IsHexAddress(asset[1]) {
+		return nil, ErrUnsupportedAvatar
+	}
+
+	standard := nftStandard(asset[0])
+	if standard != nftStandardErc721 && standard != nftStandardErc1155 {
+		return nil, ErrUnsupportedAvatar
+	}
+
+	tokenId, ok := new(big.Int).SetString(parts[2], 10)
+	if !ok || tokenId.Sign() < 0 {
+		return nil, ErrUnsupportedAvatar
+	}
+
+	return &nftAvatar{
+		chainId:  parts[0],
+		standard: standard,
+		contract: common.HexToAddress(asset[1]),
+		tokenId:  tokenId,
+	}, nil
+}
+
+// metadataUri fills the {id} placeholder of erc1155 uri with the hex token id
+func (a *nftAvatar) metadataUri(uri string) string {
+	if a.standard != nftStandardErc1155 {
+		return uri
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", a.tokenId))
+}
+
+// gatewayUrl returns a browser loadable url of http, ipfs and data uris, empty if not supported
+func gatewayUrl(uri string, ipfsGateway string) string {
+	switch {
+	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"), strings.HasPrefix(uri, "data:"):
+		return uri
+	case strings.HasPrefix(uri, "ipfs://"):
+		cid := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return strings.TrimSuffix(ipfsGateway, "/") + "/" + cid
+	default:
+		return ""
+	}
+}
+
+// resolveAvatar returns the image url of avatar text record of a name resolved to owner, http, ipfs and data uris are
+// used directly and nft uris on ethereum are resolved by token metadata if the nft is owned by owner
+func (im *impl) resolveAvatar(c ctx.Ctx, uri string, owner common.Address) (string, error) {
+	if url := gatewayUrl(uri, im.ipfsGateway); url != "" {
+		return url, nil
+	}
+
+	avatar, err := parseNftAvatar(uri)
+	if err != nil {
+		return "", err
+	}
+	if avatar.chainId != "1" || im.webResource == nil {
+		return "", ErrUnsupportedAvatar
+	}
+
+	if owned, err := im.ownsNft(c, avatar, owner); err != nil {
+		c.WithFields(log.Fields{
+			"uri":   uri,
+			"owner": owner,
+			"err":   err,
+		}).Warn("ownsNft failed")
+		return "", err
+	} else if !owned {
+		return "", ErrAvatarNotOwned
+	}
+
+	// the image of a token rarely changes while the owner does, only the image is cached
+	res := ""
+	if err := im.cache.GetByFunc(c, keys.RedisKey("avatar-image", strings.ToLower(uri)), &res, func() (interface{}, error) {
+		image, err := im.nftImage(c, avatar)
+		if err != nil {
+			return nil, err
+		}
+		return &image, nil
+	}); err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// ownsNft checks ownerOf of erc721 and balanceOf of erc1155
+func (im *impl) ownsNft(c ctx.Ctx, avatar *nftAvatar, owner common.Address) (bool, error) {
+	if owner == (common.Address{}) {
+		return false, nil
+	}
+
+	if avatar.standard == nftStandardErc1155 {
+		data, err := tokenABI.Pack("balanceOf", owner, avatar.tokenId)
+		if err != nil {
+			return false, err
+		}
+		out, err := im.call(c, avatar.contract, data)
+		if err != nil {
+			return false, err
+		}
+		unpacked, err := tokenABI.Unpack("balanceOf", out)
+		if err != nil {
+			return false, err
+		}
+		return unpacked[0].(*big.Int).Sign() > 0, nil
+	}
+
+	data, err := tokenABI.Pack("ownerOf", avatar.tokenId)
+	if err != nil {
+		return false, err
+	}
+	out, err := im.call(c, avatar.contract, data)
+	if err != nil {
+		return false, err
+	}
+	unpacked, err := tokenABI.Unpack("ownerOf", out)
+	if err != nil {
+		return false, err
+	}
+	return unpacked[0].(common.Address) == owner, nil
+}
+
+// nftImage returns the image url in token metadata
+func (im *impl) nftImage(c ctx.Ctx, avatar *nftAvatar) (string, error) {
+	method := "tokenURI"
+	if avatar.standard == nftStandardErc1155 {
+		method = "uri"
+	}
+	data, err := tokenABI.Pack(method, avatar.tokenId)
+	if err != nil {
+		return "", err
+	}
+	out, err := im.call(c, avatar.contract, data)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"contract": avatar.contract,
+			"tokenId":  avatar.tokenId,
+			"err":      err,
+		}).Warn("call token uri failed")
+		return "", err
+	}
+	unpacked, err := tokenABI.Unpack(method, out)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := im.webResource.GetJson(c, avatar.metadataUri(unpacked[0].(string)))
+	if err != nil {
+		return "", err
+	}
+
+	metadata := struct {
+		Image     string `json:"image"`
+		ImageUrl  string `json:"image_url"`
+		ImageData string `json:"image_data"`
+	}{}
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return "", err
+	}
+
+	for _, image := range []string{metadata.Image, metadata.ImageUrl} {
+		if url := gatewayUrl(image, im.ipfsGateway); url != "" {
+			return url, nil
+		}
+	}
+	if metadata.ImageData != "" {
+		return "data:image/svg+xml;utf8," + metadata.ImageData, nil
+	}
+	return "", ErrUnsupportedAvatar
+}
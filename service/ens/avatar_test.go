@@ -0,0 +1,60 @@
+package ens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNftAvatar(t *testing.T) {
+	avatar, err := parseNftAvatar("eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1024")
+	assert.NoError(t, err)
+	assert.Equal(t, &nftAvatar{
+		chainId:  "1",
+		standard: nftStandardErc721,
+		contract: common.HexToAddress("0xb47e3cd837ddf8e4c57f05d70ab865de6e193bbb"),
+		tokenId:  big.NewInt(1024),
+	}, avatar)
+
+	avatar, err = parseNftAvatar("eip155:1/erc1155:0x495f947276749ce646f68ac8c248420045cb7b5e/10")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/000000000000000000000000000000000000000000000000000000000000000a.json", avatar.metadataUri("https://api.example.com/{id}.json"))
+
+	for _, uri := range []string{
+		"https://example.com/avatar.png",
+		"eip155:1/erc20:0x495f947276749ce646f68ac8c248420045cb7b5e/10",
+		"eip155:1/erc721:0x1234/10",
+		"eip155:1/erc721:0x495f947276749ce646f68ac8c248420045cb7b5e/abc",
+		"eip155:1/erc721:0x495f947276749ce646f68ac8c248420045cb7b5e",
+	} {
+		_, err := parseNftAvatar(uri)
+		assert.ErrorIs(t, err, ErrUnsupportedAvatar, uri)
+	}
+}
+
+func TestGatewayUrl(t *testing.T) {
+	gateway := "https://ipfs.io/ipfs/"
+	assert.Equal(t, "https://example.com/a.png", gatewayUrl("https://example.com/a.png", gateway))
+	assert.Equal(t, "data:image/png;base64,AAAA", gatewayUrl("data:image/png;base64,AAAA", gateway))
+	assert.Equal(t, "https://ipfs.io/ipfs/QmHash/a.png", gatewayUrl("ipfs://QmHash/a.png", gateway))
+	assert.Equal(t, "https://ipfs.io/ipfs/QmHash", gatewayUrl("ipfs://ipfs/QmHash", gateway))
+	assert.Equal(t, "", gatewayUrl("eip155:1/erc721:0x495f947276749ce646f68ac8c248420045cb7b5e/1", gateway))
+}
+
+func TestMulticallPack(t *testing.T) {
+	data, err := registryABI.Pack("resolver", [32]byte{})
+	assert.NoError(t, err)
+	_, err = multicallABI.Pack("aggregate3", []call{{Target: registryAddress, AllowFailure: true, CallData: data}})
+	assert.NoError(t, err)
+}
+
+func TestOwnershipPack(t *testing.T) {
+	_, err := tokenABI.Pack("ownerOf", big.NewInt(1))
+	assert.NoError(t, err)
+	_, err = tokenABI.Pack("balanceOf", common.HexToAddress("0x020cA66C30beC2c4Fe3861a94E4DB4A498A35872"), big.NewInt(1))
+	assert.NoError(t, err)
+	_, err = resolverABI.Pack("addr", [32]byte{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,294 @@
+package ens
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	goens "github.com/wealdtech/go-ens/v3"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/service/cache"
+)
+
+const (
+	textAvatar  = "avatar"
+	textTwitter = "com.twitter"
+	textUrl     = "url"
+)
+
+var textKeys = []string{textAvatar, textTwitter, textUrl}
+
+// avatarTimeout bounds resolving an nft avatar, which calls the chain and fetches token metadata
+const avatarTimeout = 5 * time.Second
+
+func (im *impl) call(c ctx.Ctx, to common.Address, data []byte) ([]byte, error) {
+	return im.client.CallContract(c, ethereum.CallMsg{To: &to, Data: data}, nil)
+}
+
+// multicall aggregates calls in one eth_call, failed calls get nil return data
+func (im *impl) multicall(c ctx.Ctx, calls []call) ([][]byte, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	data, err := multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+	out, err := im.call(c, multicallAddress, data)
+	if err != nil {
+		c.WithField("err", err).Error("multicall failed")
+		return nil, err
+	}
+	unpacked, err := multicallABI.Unpack("aggregate3", out)
+	if err != nil {
+		return nil, err
+	}
+	results := *abi.ConvertType(unpacked[0], new([]callResult)).(*[]callResult)
+
+	res := make([][]byte, len(results))
+	for i, r := range results {
+		if r.Success {
+			res[i] = r.ReturnData
+		}
+	}
+	return res, nil
+}
+
+func (im *impl) ReverseResolveBatch(c ctx.Ctx, addresses []domain.Address) (map[domain.Address]string, error) {
+	res := map[domain.Address]string{}
+
+	// cached names, an empty name is cached as well
+	misses := []domain.Address{}
+	seen := map[domain.Address]bool{}
+	for _, address := range addresses {
+		address = address.ToLower()
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		name := ""
+		if err := im.cache.Get(c, keys.RedisKey("verified-name", address.ToLowerStr()), &name); err == cache.ErrNotFound {
+			misses = append(misses, address)
+		} else if err != nil {
+			c.WithField("err", err).Warn("cache.Get failed")
+			misses = append(misses, address)
+		} else if name != "" {
+			res[address] = name
+		}
+	}
+
+	if len(misses) == 0 {
+		return res, nil
+	}
+
+	accounts := make([]common.Address, len(misses))
+	for i, address := range misses {
+		accounts[i] = common.HexToAddress(address.ToLowerStr())
+	}
+	data, err := reverseRecordsABI.Pack("getNames", accounts)
+	if err != nil {
+		return nil, err
+	}
+	out, err := im.call(c, reverseRecordsAddress, data)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"count": len(misses),
+			"err":   err,
+		}).Error("reverseRecords.getNames failed")
+		return nil, err
+	}
+	unpacked, err := reverseRecordsABI.Unpack("getNames", out)
+	if err != nil {
+		return nil, err
+	}
+
+	// names not forward verified are returned as empty string
+	names := unpacked[0].([]string)
+	for i, address := range misses {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		if err := im.cache.Set(c, keys.RedisKey("verified-name", address.ToLowerStr()), &name); err != nil {
+			c.WithField("err", err).Warn("cache.Set failed")
+		}
+		if name != "" {
+			res[address] = name
+		}
+	}
+
+	return res, nil
+}
+
+func (im *impl) GetProfile(c ctx.Ctx, name string) (*domain.EnsProfile, error) {
+	profiles, err := im.GetProfiles(c, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	return profiles[strings.ToLower(name)], nil
+}
+
+func (im *impl) GetProfiles(c ctx.Ctx, names []string) (map[string]*domain.EnsProfile, error) {
+	res := map[string]*domain.EnsProfile{}
+
+	misses := []string{}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == "" || res[name] != nil {
+			continue
+		}
+
+		profile := domain.EnsProfile{}
+		if err := im.cache.Get(c, keys.RedisKey("profile", name), &profile); err == nil {
+			res[name] = &profile
+			continue
+		} else if err != cache.ErrNotFound {
+			c.WithField("err", err).Warn("cache.Get failed")
+		}
+		res[name] = &domain.EnsProfile{Name: name}
+		misses = append(misses, name)
+	}
+
+	if len(misses) == 0 {
+		return res, nil
+	}
+
+	nodes := make([][32]byte, len(misses))
+	resolverCalls := make([]call, len(misses))
+	for i, name := range misses {
+		node, err := goens.NameHash(name)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"name": name,
+				"err":  err,
+			}).Warn("goens.NameHash failed")
+			return nil, domain.ErrBadParamInput
+		}
+		nodes[i] = node
+		data, err := registryABI.Pack("resolver", node)
+		if err != nil {
+			return nil, err
+		}
+		resolverCalls[i] = call{Target: registryAddress, AllowFailure: true, CallData: data}
+	}
+
+	resolverOuts, err := im.multicall(c, resolverCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	// text records and the address of names with resolver, the address is the owner of nft avatars
+	recordCalls := []call{}
+	recordNames := []string{}
+	recordKeys := []string{}
+	for i, out := range resolverOuts {
+		if len(out) == 0 {
+			continue
+		}
+		unpacked, err := registryABI.Unpack("resolver", out)
+		if err != nil {
+			continue
+		}
+		resolver := unpacked[0].(common.Address)
+		if resolver == (common.Address{}) {
+			continue
+		}
+		for _, key := range textKeys {
+			data, err := resolverABI.Pack("text", nodes[i], key)
+			if err != nil {
+				return nil, err
+			}
+			recordCalls = append(recordCalls, call{Target: resolver, AllowFailure: true, CallData: data})
+			recordNames = append(recordNames, misses[i])
+			recordKeys = append(recordKeys, key)
+		}
+		data, err := resolverABI.Pack("addr", nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		recordCalls = append(recordCalls, call{Target: resolver, AllowFailure: true, CallData: data})
+		recordNames = append(recordNames, misses[i])
+		recordKeys = append(recordKeys, "")
+	}
+
+	recordOuts, err := im.multicall(c, recordCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string]common.Address{}
+	for i, out := range recordOuts {
+		if len(out) == 0 {
+			continue
+		}
+		if recordKeys[i] == "" {
+			if unpacked, err := resolverABI.Unpack("addr", out); err == nil {
+				owners[recordNames[i]] = unpacked[0].(common.Address)
+			}
+			continue
+		}
+		unpacked, err := resolverABI.Unpack("text", out)
+		if err != nil {
+			continue
+		}
+		value := unpacked[0].(string)
+		profile := res[recordNames[i]]
+		switch recordKeys[i] {
+		case textAvatar:
+			profile.Avatar = value
+		case textTwitter:
+			profile.Twitter = value
+		case textUrl:
+			profile.Url = value
+		}
+	}
+
+	// avatars are resolved in parallel and bounded by avatarTimeout, so a slow metadata host doesn't hold the request
+	cacheables := make([]bool, len(misses))
+	wg := sync.WaitGroup{}
+	for i, name := range misses {
+		cacheables[i] = true
+		if res[name].Avatar == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			avatarCtx, cancel := ctx.WithTimeout(c, avatarTimeout)
+			defer cancel()
+
+			profile := res[name]
+			avatar, err := im.resolveAvatar(avatarCtx, profile.Avatar, owners[name])
+			if err != nil {
+				c.WithFields(log.Fields{
+					"name":   name,
+					"avatar": profile.Avatar,
+					"err":    err,
+				}).Warn("resolveAvatar failed")
+				// retry next time unless the avatar is never resolvable
+				cacheables[i] = err == ErrUnsupportedAvatar || err == ErrAvatarNotOwned
+			}
+			profile.Avatar = avatar
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range misses {
+		if !cacheables[i] {
+			continue
+		}
+		if err := im.cache.Set(c, keys.RedisKey("profile", name), res[name]); err != nil {
+			c.WithField("err", err).Warn("cache.Set failed")
+		}
+	}
+
+	return res, nil
+}
@@ -8,4 +8,10 @@ import (
 type ENS interface {
 	Resolve(ctx ctx.Ctx, name string) (domain.Address, error)
 	ReverseResolve(ctx ctx.Ctx, address domain.Address) (string, error)
+	// ReverseResolveBatch returns forward verified names of addresses in lower case, addresses without a verified name
+	// are omitted
+	ReverseResolveBatch(ctx ctx.Ctx, addresses []domain.Address) (map[domain.Address]string, error)
+	GetProfile(ctx ctx.Ctx, name string) (*domain.EnsProfile, error)
+	// GetProfiles returns profiles by names, text records of all names are fetched in one request
+	GetProfiles(ctx ctx.Ctx, names []string) (map[string]*domain.EnsProfile, error)
 }
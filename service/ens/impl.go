@@ -20,11 +20,14 @@ import (
 )
 
 type impl struct {
-	client *ethclient.Client
-	cache  cache.Service
+	client      *ethclient.Client
+	cache       cache.Service
+	webResource domain.WebResourceUseCase
+	ipfsGateway string
 }
 
-func New(rpc string, redis redis.Service) ENS {
+// New creates ENS service on ethereum, webResource is optional and nft avatars are not resolved if nil
+func New(rpc string, redis redis.Service, webResource domain.WebResourceUseCase, ipfsGateway string) ENS {
 	client, err := ethclient.Dial(rpc)
 	if err != nil {
 		panic(err)
@@ -43,6 +46,8 @@ func New(rpc string, redis redis.Service) ENS {
 				Cache: redisCache.NewRedis(redis),
 			}),
 		}),
+		webResource,
+		ipfsGateway,
 	}
 }
 
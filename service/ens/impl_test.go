@@ -30,7 +30,7 @@ func (s *ensSuite) SetupSuite() {
 		Src: redisCachePool,
 	})
 
-	s.im = New("rpc_url", redisCache).(*impl)
+	s.im = New("rpc_url", redisCache, nil, "https://ipfs.io/ipfs").(*impl)
 }
 
 func TestSuite(t *testing.T) {
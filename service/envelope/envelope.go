@@ -0,0 +1,157 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+const keySize = 32
+
+var (
+	ErrInvalidKey     = errors.New("master key must be 32 bytes")
+	ErrUnknownKey     = errors.New("unknown master key")
+	ErrNoCurrentKey   = errors.New("current master key not found")
+	ErrMalformedInput = errors.New("malformed ciphertext")
+)
+
+// Sealed is a plaintext encrypted by a data key, and the data key wrapped by a master key
+type Sealed struct {
+	// KeyId is the id of the master key wrapping the data key
+	KeyId      string
+	WrappedKey []byte
+	Ciphertext []byte
+}
+
+/*
+Keyring does envelope encryption with AES-256-GCM:
+  - every Seal generates a random data key to encrypt the plaintext
+  - the data key is wrapped by the current master key
+  - nonces are prepended to the wrapped key and the ciphertext
+
+Master keys are kept by id so data sealed by a retired key can still be opened, Rewrap moves a data key to the
+current master key without touching the ciphertext.
+*/
+type Keyring struct {
+	keys    map[string]cipher.AEAD
+	current string
+}
+
+// New creates a keyring of master keys by id, current is the id of the key used for sealing
+func New(keys map[string][]byte, current string) (*Keyring, error) {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		aeads[id] = aead
+	}
+	if _, ok := aeads[current]; !ok {
+		return nil, ErrNoCurrentKey
+	}
+	return &Keyring{keys: aeads, current: current}, nil
+}
+
+// NewFromBase64 creates a keyring of base64 encoded master keys by id
+func NewFromBase64(keys map[string]string, current string) (*Keyring, error) {
+	raw := make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		raw[id] = b
+	}
+	return New(raw, current)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrMalformedInput
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, aad)
+}
+
+// CurrentKeyId returns the id of the master key used for sealing
+func (k *Keyring) CurrentKeyId() string {
+	return k.current
+}
+
+// Seal encrypts plaintext with a new data key, aad binds the ciphertext to its owner and must be given to Open
+func (k *Keyring) Seal(plaintext, aad []byte) (*Sealed, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(aead, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := seal(k.keys[k.current], dataKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealed{KeyId: k.current, WrappedKey: wrappedKey, Ciphertext: ciphertext}, nil
+}
+
+func (k *Keyring) unwrap(s *Sealed, aad []byte) ([]byte, error) {
+	master, ok := k.keys[s.KeyId]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return open(master, s.WrappedKey, aad)
+}
+
+// Open decrypts the sealed plaintext
+func (k *Keyring) Open(s *Sealed, aad []byte) ([]byte, error) {
+	dataKey, err := k.unwrap(s, aad)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, s.Ciphertext, aad)
+}
+
+// Rewrap wraps the data key by the current master key, the ciphertext is kept as is
+func (k *Keyring) Rewrap(s *Sealed, aad []byte) (*Sealed, error) {
+	dataKey, err := k.unwrap(s, aad)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := seal(k.keys[k.current], dataKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealed{KeyId: k.current, WrappedKey: wrappedKey, Ciphertext: s.Ciphertext}, nil
+}
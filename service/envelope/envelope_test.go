@@ -0,0 +1,87 @@
+package envelope
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keyOf(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestSealOpen(t *testing.T) {
+	assert := assert.New(t)
+
+	k, err := New(map[string][]byte{"k1": keyOf(1)}, "k1")
+	assert.Nil(err)
+
+	s, err := k.Seal([]byte("secret"), []byte("1:0xa:1"))
+	assert.Nil(err)
+	assert.Equal("k1", s.KeyId)
+	assert.False(bytes.Contains(s.Ciphertext, []byte("secret")))
+
+	plaintext, err := k.Open(s, []byte("1:0xa:1"))
+	assert.Nil(err)
+	assert.Equal("secret", string(plaintext))
+
+	// bound to aad
+	_, err = k.Open(s, []byte("1:0xa:2"))
+	assert.NotNil(err)
+
+	// data keys are per seal
+	s2, err := k.Seal([]byte("secret"), []byte("1:0xa:1"))
+	assert.Nil(err)
+	assert.NotEqual(s.WrappedKey, s2.WrappedKey)
+	assert.NotEqual(s.Ciphertext, s2.Ciphertext)
+}
+
+func TestRewrap(t *testing.T) {
+	assert := assert.New(t)
+	aad := []byte("aad")
+
+	old, err := New(map[string][]byte{"k1": keyOf(1)}, "k1")
+	assert.Nil(err)
+	s, err := old.Seal([]byte("secret"), aad)
+	assert.Nil(err)
+
+	rotated, err := New(map[string][]byte{"k1": keyOf(1), "k2": keyOf(2)}, "k2")
+	assert.Nil(err)
+
+	// retired keys still open
+	plaintext, err := rotated.Open(s, aad)
+	assert.Nil(err)
+	assert.Equal("secret", string(plaintext))
+
+	rewrapped, err := rotated.Rewrap(s, aad)
+	assert.Nil(err)
+	assert.Equal("k2", rewrapped.KeyId)
+	assert.Equal(s.Ciphertext, rewrapped.Ciphertext)
+
+	retired, err := New(map[string][]byte{"k2": keyOf(2)}, "k2")
+	assert.Nil(err)
+	plaintext, err = retired.Open(rewrapped, aad)
+	assert.Nil(err)
+	assert.Equal("secret", string(plaintext))
+
+	_, err = retired.Open(s, aad)
+	assert.Equal(ErrUnknownKey, err)
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := New(map[string][]byte{"k1": []byte("short")}, "k1")
+	assert.Equal(ErrInvalidKey, err)
+
+	_, err = New(map[string][]byte{"k1": keyOf(1)}, "k2")
+	assert.Equal(ErrNoCurrentKey, err)
+
+	_, err = NewFromBase64(map[string]string{"k1": "not base64!"}, "k1")
+	assert.Equal(ErrInvalidKey, err)
+
+	k, err := NewFromBase64(map[string]string{"k1": "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="}, "k1")
+	assert.Nil(err)
+	assert.Equal("k1", k.CurrentKeyId())
+}
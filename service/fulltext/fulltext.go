@@ -0,0 +1,145 @@
+package fulltext
+
+/*
+	Description:
+		Package `fulltext` provides an embedded in-memory inverted index.
+		Documents are tokenized into lower-cased terms per weighted field,
+		queries support exact, prefix and fuzzy (levenshtein) term matching,
+		and results are ranked by tf-idf multiplied by a per-document boost.
+*/
+
+import "fmt"
+
+var (
+	// ErrInvalidPagination is returned when offset or limit is negative
+	ErrInvalidPagination = fmt.Errorf("invalid pagination")
+)
+
+// FacetKind is the built-in facet of document kind
+const FacetKind = "kind"
+
+// Field is a piece of searchable text of a document
+type Field struct {
+	Name string
+	Text string
+	// Weight scales the contribution of terms found in this field, 1 if zero
+	Weight float64
+}
+
+// Document is the unit to be indexed
+type Document struct {
+	// Id must be unique across all kinds
+	Id     string
+	Kind   string
+	Fields []Field
+	// Boost multiplies the relevance score by (1 + Boost)
+	Boost float64
+	// Facets are exact-match attributes used for filtering and facet counting, a facet may have multiple values
+	Facets map[string][]string
+}
+
+// Hit is a matched document
+type Hit struct {
+	Id    string  `json:"id"`
+	Kind  string  `json:"kind"`
+	Score float64 `json:"score"`
+}
+
+// Result of a search
+type Result struct {
+	Hits   []Hit                     `json:"hits"`
+	Total  int                       `json:"total"`
+	Facets map[string]map[string]int `json:"facets,omitempty"`
+}
+
+type Index interface {
+	// Upsert adds the document into index, or replaces the existing one with the same id
+	Upsert(doc Document)
+
+	// Remove removes a document from index, it is no-op if the id does not exist
+	Remove(id string)
+
+	// Search returns matched documents ranked by relevance
+	Search(text string, opts ...SearchOptions) (*Result, error)
+
+	// Size returns the number of indexed documents
+	Size() int
+}
+
+type searchOptions struct {
+	Kinds       []string
+	Filters     map[string][]string
+	FacetFields []string
+	Offset      int
+	Limit       int
+	Prefix      bool
+	Fuzzy       bool
+}
+
+type SearchOptions func(*searchOptions) error
+
+func GetSearchOptions(opts ...SearchOptions) (searchOptions, error) {
+	res := searchOptions{
+		Filters: map[string][]string{},
+		Limit:   10,
+		Prefix:  true,
+		Fuzzy:   true,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return searchOptions{}, err
+		}
+	}
+
+	return res, nil
+}
+
+// WithKinds limits results to documents of the given kinds
+func WithKinds(kinds ...string) SearchOptions {
+	return func(options *searchOptions) error {
+		options.Kinds = append(options.Kinds, kinds...)
+		return nil
+	}
+}
+
+// WithFilter limits results to documents whose facet `name` equals one of `values`
+func WithFilter(name string, values ...string) SearchOptions {
+	return func(options *searchOptions) error {
+		options.Filters[name] = append(options.Filters[name], values...)
+		return nil
+	}
+}
+
+// WithFacets requests value counts of the given facets over all matched documents
+func WithFacets(names ...string) SearchOptions {
+	return func(options *searchOptions) error {
+		options.FacetFields = append(options.FacetFields, names...)
+		return nil
+	}
+}
+
+func WithPagination(offset, limit int) SearchOptions {
+	return func(options *searchOptions) error {
+		if offset < 0 || limit < 0 {
+			return ErrInvalidPagination
+		}
+		options.Offset = offset
+		options.Limit = limit
+		return nil
+	}
+}
+
+func WithPrefix(prefix bool) SearchOptions {
+	return func(options *searchOptions) error {
+		options.Prefix = prefix
+		return nil
+	}
+}
+
+func WithFuzzy(fuzzy bool) SearchOptions {
+	return func(options *searchOptions) error {
+		options.Fuzzy = fuzzy
+		return nil
+	}
+}
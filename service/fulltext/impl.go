@@ -0,0 +1,403 @@
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxTermLength      = 64
+	maxPrefixExpansion = 256
+	minFuzzyLength     = 4
+	twoEditsLength     = 8
+)
+
+type docEntry struct {
+	id     string
+	kind   string
+	boost  float64
+	facets map[string][]string
+	terms  map[string]float64
+}
+
+type impl struct {
+	mu       sync.RWMutex
+	docs     map[string]*docEntry
+	postings map[string]map[string]float64
+
+	// sorted terms and terms grouped by rune length, rebuilt lazily for prefix and fuzzy lookups
+	dirty bool
+	terms []string
+	byLen map[int][]string
+}
+
+func New() Index {
+	return &impl{
+		docs:     map[string]*docEntry{},
+		postings: map[string]map[string]float64{},
+		byLen:    map[int][]string{},
+	}
+}
+
+// Tokenize splits text into lower-cased terms of letters and numbers
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	res := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) > maxTermLength {
+			f = string([]rune(f)[:maxTermLength])
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+func (im *impl) Upsert(doc Document) {
+	entry := &docEntry{
+		id:     doc.Id,
+		kind:   doc.Kind,
+		boost:  doc.Boost,
+		facets: doc.Facets,
+		terms:  map[string]float64{},
+	}
+
+	for _, field := range doc.Fields {
+		terms := Tokenize(field.Text)
+		if len(terms) == 0 {
+			continue
+		}
+		weight := field.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		// shorter fields are more relevant for the same term
+		norm := weight / math.Sqrt(float64(len(terms)))
+		tf := map[string]int{}
+		for _, t := range terms {
+			tf[t]++
+		}
+		for t, cnt := range tf {
+			if w := norm * (1 + math.Log(float64(cnt))); w > entry.terms[t] {
+				entry.terms[t] = w
+			}
+		}
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.remove(doc.Id)
+	im.docs[doc.Id] = entry
+	for t, w := range entry.terms {
+		posting, ok := im.postings[t]
+		if !ok {
+			posting = map[string]float64{}
+			im.postings[t] = posting
+			im.dirty = true
+		}
+		posting[doc.Id] = w
+	}
+}
+
+func (im *impl) Remove(id string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.remove(id)
+}
+
+// remove must be called with write lock held
+func (im *impl) remove(id string) {
+	entry, ok := im.docs[id]
+	if !ok {
+		return
+	}
+	for t := range entry.terms {
+		posting := im.postings[t]
+		delete(posting, id)
+		if len(posting) == 0 {
+			delete(im.postings, t)
+			im.dirty = true
+		}
+	}
+	delete(im.docs, id)
+}
+
+func (im *impl) Size() int {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	return len(im.docs)
+}
+
+func (im *impl) Search(text string, opts ...SearchOptions) (*Result, error) {
+	options, err := GetSearchOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Hits: []Hit{}}
+	if len(options.FacetFields) > 0 {
+		res.Facets = map[string]map[string]int{}
+		for _, f := range options.FacetFields {
+			res.Facets[f] = map[string]int{}
+		}
+	}
+
+	queryTerms := dedup(Tokenize(text))
+	if len(queryTerms) == 0 {
+		return res, nil
+	}
+
+	im.refreshTerms()
+
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	// every query term must be matched by some term of the document
+	var scores map[string]float64
+	for i, qt := range queryTerms {
+		termScores := map[string]float64{}
+		for term, factor := range im.expand(qt, options) {
+			posting := im.postings[term]
+			if len(posting) == 0 {
+				continue
+			}
+			idf := math.Log(1 + float64(len(im.docs))/float64(len(posting)))
+			for id, w := range posting {
+				if s := w * idf * factor; s > termScores[id] {
+					termScores[id] = s
+				}
+			}
+		}
+
+		if i == 0 {
+			scores = termScores
+		} else {
+			for id, s := range scores {
+				if ts, ok := termScores[id]; ok {
+					scores[id] = s + ts
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+
+		if len(scores) == 0 {
+			return res, nil
+		}
+	}
+
+	for id, score := range scores {
+		doc := im.docs[id]
+		if !matchFilters(doc, options) {
+			continue
+		}
+		res.Hits = append(res.Hits, Hit{Id: id, Kind: doc.kind, Score: score * (1 + doc.boost)})
+		for _, f := range options.FacetFields {
+			for _, v := range doc.facet(f) {
+				res.Facets[f][v]++
+			}
+		}
+	}
+
+	sort.Slice(res.Hits, func(i, j int) bool {
+		if res.Hits[i].Score != res.Hits[j].Score {
+			return res.Hits[i].Score > res.Hits[j].Score
+		}
+		return res.Hits[i].Id < res.Hits[j].Id
+	})
+
+	res.Total = len(res.Hits)
+	res.Hits = paginate(res.Hits, options.Offset, options.Limit)
+
+	return res, nil
+}
+
+// expand returns index terms matching the query term with their score factors
+// must be called with read lock held
+func (im *impl) expand(qt string, options searchOptions) map[string]float64 {
+	res := map[string]float64{}
+
+	if _, ok := im.postings[qt]; ok {
+		res[qt] = 1
+	}
+
+	qLen := utf8.RuneCountInString(qt)
+
+	if options.Prefix {
+		start := sort.SearchStrings(im.terms, qt)
+		for i, n := start, 0; i < len(im.terms) && n < maxPrefixExpansion; i++ {
+			t := im.terms[i]
+			if !strings.HasPrefix(t, qt) {
+				break
+			}
+			if t == qt {
+				continue
+			}
+			res[t] = math.Max(res[t], 0.5+0.4*float64(qLen)/float64(utf8.RuneCountInString(t)))
+			n++
+		}
+	}
+
+	if options.Fuzzy && qLen >= minFuzzyLength {
+		maxEdits := 1
+		if qLen >= twoEditsLength {
+			maxEdits = 2
+		}
+		qr := []rune(qt)
+		for l := qLen - maxEdits; l <= qLen+maxEdits; l++ {
+			for _, t := range im.byLen[l] {
+				if t == qt {
+					continue
+				}
+				if d := levenshtein(qr, []rune(t), maxEdits); d <= maxEdits {
+					res[t] = math.Max(res[t], 0.5/float64(d))
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func (im *impl) refreshTerms() {
+	im.mu.RLock()
+	dirty := im.dirty
+	im.mu.RUnlock()
+	if !dirty {
+		return
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	if !im.dirty {
+		return
+	}
+
+	im.terms = make([]string, 0, len(im.postings))
+	im.byLen = map[int][]string{}
+	for t := range im.postings {
+		im.terms = append(im.terms, t)
+		l := utf8.RuneCountInString(t)
+		im.byLen[l] = append(im.byLen[l], t)
+	}
+	sort.Strings(im.terms)
+	im.dirty = false
+}
+
+func (d *docEntry) facet(name string) []string {
+	if name == FacetKind {
+		return []string{d.kind}
+	}
+	return d.facets[name]
+}
+
+func matchFilters(doc *docEntry, options searchOptions) bool {
+	if len(options.Kinds) > 0 && !contains(options.Kinds, doc.kind) {
+		return false
+	}
+	for name, values := range options.Filters {
+		if len(values) == 0 {
+			continue
+		}
+		if !containsAny(values, doc.facet(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func paginate(hits []Hit, offset, limit int) []Hit {
+	if offset >= len(hits) {
+		return []Hit{}
+	}
+	end := len(hits)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return hits[offset:end]
+}
+
+// levenshtein returns the edit distance of a and b, or max+1 once it is known to exceed max
+func levenshtein(a, b []rune, max int) int {
+	if abs(len(a)-len(b)) > max {
+		return max + 1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func dedup(terms []string) []string {
+	seen := map[string]struct{}{}
+	res := []string{}
+	for _, t := range terms {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+	return res
+}
+
+func contains(values []string, v string) bool {
+	for _, val := range values {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values []string, targets []string) bool {
+	for _, t := range targets {
+		if contains(values, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func min(vals ...int) int {
+	res := vals[0]
+	for _, v := range vals[1:] {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,157 @@
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testsuite struct {
+	suite.Suite
+	im *impl
+}
+
+func (ts *testsuite) SetupTest() {
+	ts.im = New().(*impl)
+	ts.im.Upsert(Document{
+		Id:     "collection:1:0xbc4c",
+		Kind:   "collection",
+		Fields: []Field{{Name: "name", Text: "Bored Ape Yacht Club", Weight: 3}},
+		Boost:  2,
+		Facets: map[string][]string{"chainId": {"1"}},
+	})
+	ts.im.Upsert(Document{
+		Id:     "collection:1:0x60e4",
+		Kind:   "collection",
+		Fields: []Field{{Name: "name", Text: "Mutant Ape Yacht Club", Weight: 3}},
+		Facets: map[string][]string{"chainId": {"1"}},
+	})
+	ts.im.Upsert(Document{
+		Id:   "token:1:0xbc4c:1",
+		Kind: "token",
+		Fields: []Field{
+			{Name: "name", Text: "Bored Ape #1", Weight: 3},
+			{Name: "traits", Text: "Golden Brown, Bayc T Red"},
+		},
+		Facets: map[string][]string{"chainId": {"1"}, "collection": {"0xbc4c"}, "trait": {"Fur:Golden Brown", "Clothes:Bayc T Red"}},
+	})
+	ts.im.Upsert(Document{
+		Id:     "account:0xabc",
+		Kind:   "account",
+		Fields: []Field{{Name: "alias", Text: "apeholder"}, {Name: "ens", Text: "apeholder.eth"}},
+		Facets: map[string][]string{},
+	})
+}
+
+func Test(t *testing.T) {
+	suite.Run(t, new(testsuite))
+}
+
+func (ts *testsuite) TestTokenize() {
+	ts.Equal([]string{"bored", "ape", "1"}, Tokenize("Bored Ape #1"))
+	ts.Equal([]string{"apeholder", "eth"}, Tokenize("ApeHolder.eth"))
+	ts.Empty(Tokenize(" #- "))
+}
+
+func (ts *testsuite) TestExactMatchRankedByBoost() {
+	res, err := ts.im.Search("yacht club", WithKinds("collection"))
+	ts.NoError(err)
+	ts.Equal(2, res.Total)
+	ts.Equal("collection:1:0xbc4c", res.Hits[0].Id)
+	ts.Equal("collection:1:0x60e4", res.Hits[1].Id)
+}
+
+func (ts *testsuite) TestPrefix() {
+	res, err := ts.im.Search("mut")
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+	ts.Equal("collection:1:0x60e4", res.Hits[0].Id)
+
+	res, err = ts.im.Search("mut", WithPrefix(false), WithFuzzy(false))
+	ts.NoError(err)
+	ts.Equal(0, res.Total)
+}
+
+func (ts *testsuite) TestFuzzy() {
+	res, err := ts.im.Search("mutnat")
+	ts.NoError(err)
+	ts.Equal(0, res.Total, "two edits is not allowed for short terms")
+
+	res, err = ts.im.Search("mutamt")
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+	ts.Equal("collection:1:0x60e4", res.Hits[0].Id)
+
+	res, err = ts.im.Search("bord", WithFuzzy(false))
+	ts.NoError(err)
+	ts.Equal(0, res.Total)
+}
+
+func (ts *testsuite) TestAllTermsRequired() {
+	res, err := ts.im.Search("golden ape")
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+	ts.Equal("token:1:0xbc4c:1", res.Hits[0].Id)
+}
+
+func (ts *testsuite) TestFiltersAndFacets() {
+	res, err := ts.im.Search("ape", WithFacets(FacetKind, "collection"))
+	ts.NoError(err)
+	ts.Equal(4, res.Total)
+	ts.Equal(map[string]int{"collection": 2, "token": 1, "account": 1}, res.Facets[FacetKind])
+	ts.Equal(map[string]int{"0xbc4c": 1}, res.Facets["collection"])
+
+	res, err = ts.im.Search("ape", WithFacets("trait"), WithFilter("trait", "Fur:Golden Brown", "Fur:Black"))
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+	ts.Equal(map[string]int{"Fur:Golden Brown": 1, "Clothes:Bayc T Red": 1}, res.Facets["trait"])
+
+	res, err = ts.im.Search("ape", WithFilter("collection", "0xbc4c"))
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+	ts.Equal("token", res.Hits[0].Kind)
+}
+
+func (ts *testsuite) TestPagination() {
+	res, err := ts.im.Search("ape", WithPagination(1, 2))
+	ts.NoError(err)
+	ts.Equal(4, res.Total)
+	ts.Len(res.Hits, 2)
+
+	res, err = ts.im.Search("ape", WithPagination(10, 2))
+	ts.NoError(err)
+	ts.Equal(4, res.Total)
+	ts.Len(res.Hits, 0)
+
+	_, err = ts.im.Search("ape", WithPagination(-1, 2))
+	ts.Equal(ErrInvalidPagination, err)
+}
+
+func (ts *testsuite) TestUpsertAndRemove() {
+	ts.im.Upsert(Document{
+		Id:     "collection:1:0x60e4",
+		Kind:   "collection",
+		Fields: []Field{{Name: "name", Text: "Mutant Hound Collars", Weight: 3}},
+	})
+	res, err := ts.im.Search("yacht")
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+
+	res, err = ts.im.Search("hound")
+	ts.NoError(err)
+	ts.Equal(1, res.Total)
+
+	ts.im.Remove("collection:1:0x60e4")
+	ts.im.Remove("not-exist")
+	res, err = ts.im.Search("hound")
+	ts.NoError(err)
+	ts.Equal(0, res.Total)
+	ts.Equal(3, ts.im.Size())
+}
+
+func (ts *testsuite) TestLevenshtein() {
+	ts.Equal(0, levenshtein([]rune("ape"), []rune("ape"), 1))
+	ts.Equal(1, levenshtein([]rune("ape"), []rune("apes"), 1))
+	ts.Equal(1, levenshtein([]rune("kitten"), []rune("sitten"), 1))
+	ts.Equal(2, levenshtein([]rune("abcd"), []rune("badc"), 1))
+}
@@ -0,0 +1,157 @@
+package keyset
+
+/*
+	Description:
+		Package `keyset` implements cursor based pagination on mongo.
+		A cursor encodes the sort keys and their values of the last document in a page,
+		the next page is queried by documents sorted after those values instead of skipping by offset.
+
+	Cursor structure:
+		{"s": <sorts>, "v": <values of sort fields>} bson marshaled, base64 url encoded
+
+	Sorts are in the same format as query.Mongo, i.e. "field" for ascending and "-field" for descending.
+	`_id` is appended to sorts as the tie breaker so that every document has a unique position.
+*/
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/x-xyz/goapi/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const idField = "_id"
+
+type cursor struct {
+	Sorts  []string `bson:"s"`
+	Values bson.A   `bson:"v"`
+}
+
+// Sorts returns sorts with `-_id` appended if `_id` is not sorted yet
+func Sorts(sorts []string) []string {
+	for _, s := range sorts {
+		if field, _ := parseSort(s); field == idField {
+			return sorts
+		}
+	}
+
+	res := make([]string, 0, len(sorts)+1)
+	res = append(res, sorts...)
+	return append(res, "-"+idField)
+}
+
+// Encode returns the cursor pointing right after doc, doc should be marshalable by bson and contain `_id`.
+// Pass the stored document as bson.Raw if the model has non-pointer sort fields, otherwise fields missing
+// in the database are encoded as zero values while mongo sorts them as null
+func Encode(sorts []string, doc interface{}) (string, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	values := bson.A{}
+	for _, s := range sorts {
+		field, _ := parseSort(s)
+		val, err := bson.Raw(raw).LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			// missing field is sorted as null
+			values = append(values, nil)
+			continue
+		}
+
+		var v interface{}
+		if err := val.Unmarshal(&v); err != nil {
+			return "", err
+		}
+		values = append(values, v)
+	}
+
+	data, err := bson.Marshal(cursor{Sorts: sorts, Values: values})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Query returns the query of documents sorted after cursor, domain.ErrInvalidCursor is returned
+// if the cursor is malformed or it was encoded with different sorts
+func Query(encoded string, sorts []string) (bson.M, error) {
+	c, err := decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Sorts) != len(sorts) || len(c.Values) != len(sorts) {
+		return nil, domain.ErrInvalidCursor
+	}
+	for i, s := range sorts {
+		if c.Sorts[i] != s {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+
+	// (f0 > v0) or (f0 = v0 and f1 > v1) or ...
+	branches := bson.A{}
+	for i, s := range sorts {
+		field, asc := parseSort(s)
+
+		for _, after := range afterConds(field, c.Values[i], asc) {
+			branch := bson.M{}
+			for j := 0; j < i; j++ {
+				prev, _ := parseSort(sorts[j])
+				branch[prev] = c.Values[j]
+			}
+			for k, v := range after {
+				branch[k] = v
+			}
+			branches = append(branches, branch)
+		}
+	}
+
+	if len(branches) == 0 {
+		// the cursor points to the end
+		return bson.M{idField: bson.M{"$exists": false}}, nil
+	}
+
+	return bson.M{"$or": branches}, nil
+}
+
+func decode(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	c := &cursor{}
+	if err := bson.Unmarshal(data, c); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// afterConds returns conditions of values sorted after v, null is the smallest value in mongo
+func afterConds(field string, v interface{}, asc bool) []bson.M {
+	switch {
+	case asc && v == nil:
+		return []bson.M{{field: bson.M{"$ne": nil}}}
+	case asc:
+		return []bson.M{{field: bson.M{"$gt": v}}}
+	case v == nil:
+		return nil
+	default:
+		return []bson.M{
+			{field: bson.M{"$lt": v}},
+			{field: nil},
+		}
+	}
+}
+
+func parseSort(s string) (string, bool) {
+	if strings.HasPrefix(s, "-") {
+		return s[1:], false
+	}
+	return s, true
+}
@@ -0,0 +1,117 @@
+package keyset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type doc struct {
+	ObjectId primitive.ObjectID `bson:"_id"`
+	Price    *float64           `bson:"price"`
+	Listed   bool               `bson:"listed"`
+	Time     time.Time          `bson:"time"`
+}
+
+type KeysetSuite struct {
+	suite.Suite
+}
+
+func TestKeysetSuite(t *testing.T) {
+	suite.Run(t, new(KeysetSuite))
+}
+
+func (s *KeysetSuite) TestSorts() {
+	s.Equal([]string{"-price", "-_id"}, Sorts([]string{"-price"}))
+	s.Equal([]string{"_id"}, Sorts([]string{"_id"}))
+	s.Equal([]string{"-_id"}, Sorts(nil))
+}
+
+func (s *KeysetSuite) TestQuery() {
+	id := primitive.NewObjectID()
+	price := 1.5
+	sorts := []string{"-listed", "price", "-_id"}
+
+	cursor, err := Encode(sorts, doc{ObjectId: id, Price: &price, Listed: true})
+	s.NoError(err)
+
+	q, err := Query(cursor, sorts)
+	s.NoError(err)
+	s.Equal(bson.M{"$or": bson.A{
+		bson.M{"listed": bson.M{"$lt": true}},
+		bson.M{"listed": nil},
+		bson.M{"listed": true, "price": bson.M{"$gt": 1.5}},
+		bson.M{"listed": true, "price": 1.5, "_id": bson.M{"$lt": id}},
+		bson.M{"listed": true, "price": 1.5, "_id": nil},
+	}}, q)
+}
+
+func (s *KeysetSuite) TestQueryNull() {
+	id := primitive.NewObjectID()
+
+	cursor, err := Encode([]string{"price", "-_id"}, doc{ObjectId: id})
+	s.NoError(err)
+	q, err := Query(cursor, []string{"price", "-_id"})
+	s.NoError(err)
+	s.Equal(bson.M{"$or": bson.A{
+		bson.M{"price": bson.M{"$ne": nil}},
+		bson.M{"price": nil, "_id": bson.M{"$lt": id}},
+		bson.M{"price": nil, "_id": nil},
+	}}, q)
+
+	// nothing sorts before null in descending order
+	cursor, err = Encode([]string{"-price", "_id"}, doc{ObjectId: id})
+	s.NoError(err)
+	q, err = Query(cursor, []string{"-price", "_id"})
+	s.NoError(err)
+	s.Equal(bson.M{"$or": bson.A{
+		bson.M{"price": nil, "_id": bson.M{"$gt": id}},
+	}}, q)
+}
+
+func (s *KeysetSuite) TestQueryTime() {
+	id := primitive.NewObjectID()
+	now := time.Now()
+
+	cursor, err := Encode([]string{"-time", "-_id"}, doc{ObjectId: id, Time: now})
+	s.NoError(err)
+	q, err := Query(cursor, []string{"-time", "-_id"})
+	s.NoError(err)
+	s.Equal(bson.M{"time": bson.M{"$lt": primitive.NewDateTimeFromTime(now)}}, q["$or"].(bson.A)[0])
+}
+
+func (s *KeysetSuite) TestInvalidCursor() {
+	cursor, err := Encode([]string{"-_id"}, doc{ObjectId: primitive.NewObjectID()})
+	s.NoError(err)
+
+	_, err = Query(cursor, []string{"_id"})
+	s.Equal(domain.ErrInvalidCursor, err)
+
+	_, err = Query("not a cursor", []string{"-_id"})
+	s.Equal(domain.ErrInvalidCursor, err)
+
+	_, err = Query("", []string{"-_id"})
+	s.Equal(domain.ErrInvalidCursor, err)
+}
+
+func (s *KeysetSuite) TestEncodeRaw() {
+	id := primitive.NewObjectID()
+	sorts := []string{"-listed", "-_id"}
+
+	// the model encodes missing `listed` as false while mongo sorts it as null
+	data, err := bson.Marshal(bson.M{"_id": id})
+	s.NoError(err)
+
+	cursor, err := Encode(sorts, bson.Raw(data))
+	s.NoError(err)
+	q, err := Query(cursor, sorts)
+	s.NoError(err)
+	s.Equal(bson.M{"$or": bson.A{
+		bson.M{"listed": nil, "_id": bson.M{"$lt": id}},
+		bson.M{"listed": nil, "_id": nil},
+	}}, q)
+}
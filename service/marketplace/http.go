@@ -0,0 +1,67 @@
+package marketplace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+)
+
+type httpClient struct {
+	client  http.Client
+	timeout time.Duration
+	// headers are set on every request, e.g. api key
+	headers map[string]string
+}
+
+// getJson gets url and unmarshals the body into resp, rate limited and server errors are ErrRetryable
+func (c *httpClient) getJson(ctx bCtx.Ctx, url string, resp interface{}) error {
+	ctx, cancel := bCtx.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"url": url,
+			"err": err,
+		}).Error("NewRequestWithContext failed")
+		return err
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"url": url,
+			"err": err,
+		}).Error("client.Do failed")
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: status %d", ErrRetryable, res.StatusCode)
+	} else if res.StatusCode != http.StatusOK {
+		ctx.WithFields(log.Fields{
+			"url":        url,
+			"statusCode": res.StatusCode,
+		}).Error("resp.StatusCode != 200")
+		return ErrStatusCodeNotOk
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"url": url,
+			"err": err,
+		}).Error("failed to read body")
+		return err
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		ctx.WithField("err", err).Error("json.Unmarshal failed")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package marketplace
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+)
+
+const looksRareApi = "https://api.looksrare.org/api/v1"
+
+type LooksRareCfg struct {
+	HttpClient http.Client
+	Timeout    time.Duration
+	Apikey     string
+	// BaseUrl is the v1 api if empty
+	BaseUrl        string
+	PriceFormatter pricefomatter.PriceFormatter
+	Throttle       ThrottleCfg
+}
+
+type looksRareOrder struct {
+	Hash              string         `json:"hash"`
+	CollectionAddress domain.Address `json:"collectionAddress"`
+	TokenId           domain.TokenId `json:"tokenId"`
+	Signer            domain.Address `json:"signer"`
+	Amount            int64          `json:"amount"`
+	Price             string         `json:"price"`
+	CurrencyAddress   domain.Address `json:"currencyAddress"`
+	// StartTime and EndTime are unix timestamps
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+}
+
+type looksRareOrdersResp struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    []looksRareOrder `json:"data"`
+}
+
+type looksRareMarketplace struct {
+	http           *httpClient
+	baseUrl        string
+	priceFormatter pricefomatter.PriceFormatter
+	throttle       *throttle
+}
+
+func NewLooksRare(cfg *LooksRareCfg) external_listing.Marketplace {
+	baseUrl := cfg.BaseUrl
+	if baseUrl == "" {
+		baseUrl = looksRareApi
+	}
+	headers := map[string]string{}
+	if cfg.Apikey != "" {
+		headers["X-Looks-Api-Key"] = cfg.Apikey
+	}
+	return &looksRareMarketplace{
+		http:           &httpClient{client: cfg.HttpClient, timeout: cfg.Timeout, headers: headers},
+		baseUrl:        baseUrl,
+		priceFormatter: cfg.PriceFormatter,
+		throttle:       newThrottle(external_listing.SourceLooksRare, cfg.Throttle),
+	}
+}
+
+func (m *looksRareMarketplace) Source() external_listing.Source {
+	return external_listing.SourceLooksRare
+}
+
+// GetListings returns valid asks of the account, orders are paged by the hash of the last order
+func (m *looksRareMarketplace) GetListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) ([]external_listing.ExternalListing, error) {
+	res := []external_listing.ExternalListing{}
+	now := time.Now()
+	cursor := ""
+	for page := 0; page < pageLimit; page++ {
+		params := url.Values{}
+		params.Add("isOrderAsk", "true")
+		params.Add("signer", account.ToLowerStr())
+		params.Add("status[]", "VALID")
+		params.Add("pagination[first]", "150")
+		if cursor != "" {
+			params.Add("pagination[cursor]", cursor)
+		}
+		url := fmt.Sprintf("%s/orders?%s", m.baseUrl, params.Encode())
+
+		resp := looksRareOrdersResp{}
+		if err := m.throttle.do(ctx, func() error {
+			return m.http.getJson(ctx, url, &resp)
+		}); err != nil {
+			ctx.WithField("err", err).Error("looksrare get orders failed")
+			return nil, err
+		}
+
+		for _, o := range resp.Data {
+			price, _ := new(big.Int).SetString(o.Price, 10)
+			if listing, ok := normalize(ctx, m.priceFormatter, listingParams{
+				source:      external_listing.SourceLooksRare,
+				owner:       account,
+				chainId:     chainId,
+				collection:  o.CollectionAddress,
+				tokenId:     o.TokenId,
+				quantity:    o.Amount,
+				currency:    o.CurrencyAddress,
+				price:       price,
+				startTime:   time.Unix(o.StartTime, 0),
+				deadline:    time.Unix(o.EndTime, 0),
+				updatedTime: now,
+			}); ok {
+				res = append(res, *listing)
+			}
+		}
+
+		if len(resp.Data) == 0 {
+			break
+		}
+		cursor = resp.Data[len(resp.Data)-1].Hash
+	}
+	return res, nil
+}
+
+// GetFloor returns the cheapest valid ask of the collection
+func (m *looksRareMarketplace) GetFloor(ctx bCtx.Ctx, chainId domain.ChainId, collection domain.Address) (*external_listing.SourceFloor, error) {
+	params := url.Values{}
+	params.Add("isOrderAsk", "true")
+	params.Add("collection", collection.ToLowerStr())
+	params.Add("status[]", "VALID")
+	params.Add("sort", "PRICE_ASC")
+	params.Add("pagination[first]", "1")
+	url := fmt.Sprintf("%s/orders?%s", m.baseUrl, params.Encode())
+
+	resp := looksRareOrdersResp{}
+	if err := m.throttle.do(ctx, func() error {
+		return m.http.getJson(ctx, url, &resp)
+	}); err != nil {
+		ctx.WithField("err", err).Error("looksrare get orders failed")
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	o := resp.Data[0]
+	price, _ := new(big.Int).SetString(o.Price, 10)
+	return toFloor(ctx, m.priceFormatter, external_listing.SourceLooksRare, chainId, o.TokenId, o.CurrencyAddress, price)
+}
@@ -0,0 +1,86 @@
+package marketplace
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+)
+
+// pageLimit is the max number of pages fetched of an account
+const pageLimit = 20
+
+type listingParams struct {
+	source     external_listing.Source
+	owner      domain.Address
+	chainId    domain.ChainId
+	collection domain.Address
+	tokenId    domain.TokenId
+	quantity   int64
+	currency   domain.Address
+	// price is in the smallest unit of the currency
+	price       *big.Int
+	startTime   time.Time
+	deadline    time.Time
+	updatedTime time.Time
+	// taker is the reserved buyer of private listings, stored as Minter of ExternalListing
+	taker domain.Address
+}
+
+// normalize prices the listing in the native token and USD, the listing is skipped if the currency isn't supported
+func normalize(ctx bCtx.Ctx, priceFormatter pricefomatter.PriceFormatter, p listingParams) (*external_listing.ExternalListing, bool) {
+	if p.price == nil || p.price.Sign() <= 0 || p.quantity <= 0 {
+		return nil, false
+	}
+	_, priceInUsd, priceInNative, err := priceFormatter.GetPrices(ctx, p.chainId, p.currency, p.price)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"source":   p.source,
+			"currency": p.currency,
+			"err":      err,
+		}).Warn("priceFormatter.GetPrices failed")
+		return nil, false
+	}
+	return &external_listing.ExternalListing{
+		Owner:           p.owner.ToLower(),
+		ChainId:         p.chainId,
+		Minter:          p.taker.ToLower(),
+		ContractAddress: p.collection.ToLower(),
+		TokenId:         p.tokenId,
+		Quantity:        p.quantity,
+		PaymentToken:    p.currency.ToLower(),
+		Price:           fmt.Sprintf("%f", priceInNative),
+		PriceInUsd:      fmt.Sprintf("%f", priceInUsd),
+		StartTime:       p.startTime,
+		Deadline:        p.deadline,
+		Source:          p.source,
+		UpdatedTime:     p.updatedTime,
+	}, true
+}
+
+// toFloor prices the cheapest listing of a collection in the native token and USD
+func toFloor(ctx bCtx.Ctx, priceFormatter pricefomatter.PriceFormatter, source external_listing.Source, chainId domain.ChainId, tokenId domain.TokenId, currency domain.Address, price *big.Int) (*external_listing.SourceFloor, error) {
+	if price == nil || price.Sign() <= 0 {
+		return nil, domain.ErrNotFound
+	}
+	_, priceInUsd, priceInNative, err := priceFormatter.GetPrices(ctx, chainId, currency, price)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"source":   source,
+			"currency": currency,
+			"err":      err,
+		}).Error("priceFormatter.GetPrices failed")
+		return nil, err
+	}
+	return &external_listing.SourceFloor{
+		Source:        source,
+		TokenId:       tokenId,
+		PriceInNative: priceInNative,
+		PriceInUsd:    priceInUsd,
+	}, nil
+}
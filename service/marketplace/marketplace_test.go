@@ -0,0 +1,241 @@
+package marketplace
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter/mocks"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	"github.com/x-xyz/goapi/service/opensea"
+	"github.com/x-xyz/goapi/service/ratelimit"
+	mRatelimit "github.com/x-xyz/goapi/service/ratelimit/mocks"
+)
+
+var (
+	mockAccount    = domain.Address("0x4444444444444444444444444444444444444444")
+	mockCollection = domain.Address("0x3333333333333333333333333333333333333333")
+	mockWeth       = domain.Address("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	mockChainId    = domain.ChainId(1)
+	fastThrottle   = ThrottleCfg{BackoffStart: time.Millisecond, BackoffLimit: time.Millisecond}
+)
+
+// mockPriceFormatter prices 1 wei of any token as 1 wei of the native token, and 1 native token as 1000 USD
+func mockPriceFormatter() *pricefomatter.PriceFormatter {
+	f := &pricefomatter.PriceFormatter{}
+	f.On("GetPrices", mock.Anything, mockChainId, mock.Anything, mock.Anything).Return(
+		func(_ bCtx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) decimal.Decimal {
+			return decimal.NewFromBigInt(value, -18)
+		},
+		func(_ bCtx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) float64 {
+			return decimal.NewFromBigInt(value, -18).Mul(decimal.NewFromInt(1000)).InexactFloat64()
+		},
+		func(_ bCtx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) float64 {
+			return decimal.NewFromBigInt(value, -18).InexactFloat64()
+		},
+		nil,
+	)
+	return f
+}
+
+func TestLooksRare(t *testing.T) {
+	req := require.New(t)
+	now := time.Now()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		req.Equal("/orders", r.URL.Path)
+		req.Equal("true", r.URL.Query().Get("isOrderAsk"))
+		req.Equal(mockAccount.ToLowerStr(), r.URL.Query().Get("signer"))
+		req.Equal("key", r.Header.Get("X-Looks-Api-Key"))
+		if r.URL.Query().Get("pagination[cursor]") == "0xhash" {
+			fmt.Fprint(w, `{"success":true,"data":[]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"success":true,"data":[{"hash":"0xhash","collectionAddress":"%s","tokenId":"7","signer":"%s","amount":1,"price":"1500000000000000000","currencyAddress":"%s","startTime":%d,"endTime":%d}]}`,
+			mockCollection, mockAccount, mockWeth, now.Add(-time.Hour).Unix(), now.Add(time.Hour).Unix())
+	}))
+	defer server.Close()
+
+	m := NewLooksRare(&LooksRareCfg{
+		HttpClient:     http.Client{},
+		Timeout:        time.Second,
+		Apikey:         "key",
+		BaseUrl:        server.URL,
+		PriceFormatter: mockPriceFormatter(),
+		Throttle:       fastThrottle,
+	})
+	req.Equal(external_listing.SourceLooksRare, m.Source())
+
+	listings, err := m.GetListings(bCtx.Background(), mockAccount, mockChainId)
+	req.NoError(err)
+	req.Equal(2, calls)
+	req.Len(listings, 1)
+	req.Equal(mockCollection, listings[0].ContractAddress)
+	req.Equal(domain.TokenId("7"), listings[0].TokenId)
+	req.Equal(mockWeth, listings[0].PaymentToken)
+	req.Equal("1.500000", listings[0].Price)
+	req.Equal("1500.000000", listings[0].PriceInUsd)
+	req.Equal(external_listing.SourceLooksRare, listings[0].Source)
+}
+
+func TestX2Y2BacksOffWhenRateLimited(t *testing.T) {
+	req := require.New(t)
+	now := time.Now()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		req.Equal("key", r.Header.Get("X-API-KEY"))
+		req.Equal("open", r.URL.Query().Get("status"))
+		fmt.Fprintf(w, `{"success":true,"next":"","data":[{"id":1,"maker":"%s","price":"2000000000000000000","currency":"%s","created_at":%d,"end_at":%d,"token":{"contract":"%s","token_id":"8"}}]}`,
+			mockAccount, domain.EmptyAddress, now.Add(-time.Hour).Unix(), now.Add(time.Hour).Unix(), mockCollection)
+	}))
+	defer server.Close()
+
+	m := NewX2Y2(&X2Y2Cfg{
+		HttpClient:     http.Client{},
+		Timeout:        time.Second,
+		Apikey:         "key",
+		BaseUrl:        server.URL,
+		PriceFormatter: mockPriceFormatter(),
+		Throttle:       fastThrottle,
+	})
+
+	listings, err := m.GetListings(bCtx.Background(), mockAccount, mockChainId)
+	req.NoError(err)
+	req.Equal(2, calls)
+	req.Len(listings, 1)
+	req.Equal(int64(1), listings[0].Quantity)
+	req.Equal("2.000000", listings[0].Price)
+	req.Equal(external_listing.SourceX2Y2, listings[0].Source)
+}
+
+func TestX2Y2GivesUpAfterMaxRetries(t *testing.T) {
+	req := require.New(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	throttle := fastThrottle
+	throttle.MaxRetries = 2
+	m := NewX2Y2(&X2Y2Cfg{
+		HttpClient:     http.Client{},
+		Timeout:        time.Second,
+		BaseUrl:        server.URL,
+		PriceFormatter: mockPriceFormatter(),
+		Throttle:       throttle,
+	})
+
+	_, err := m.GetListings(bCtx.Background(), mockAccount, mockChainId)
+	req.ErrorIs(err, ErrRetryable)
+	req.Equal(3, calls)
+}
+
+func TestOpensea(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Equal("/assets", r.URL.Path)
+		fmt.Fprintf(w, `{"next":"","assets":[{"token_id":"9","asset_contract":{"address":"%s"},"seaport_sell_orders":[{
+			"created_date":"2022-01-01T00:00:00","closing_date":"2099-01-01T00:00:00","current_price":"3000000000000000000.000000",
+			"protocol_data":{"parameters":{"offer":[{"itemType":2,"token":"%s","startAmount":"1","endAmount":"1"}],
+			"consideration":[{"itemType":0,"token":"%s"}]}}}]}]}`, mockCollection, mockCollection, domain.EmptyAddress)
+	}))
+	defer server.Close()
+
+	m := NewOpensea(&OpenseaCfg{
+		Client:         opensea.NewClient(&opensea.ClientCfg{HttpClient: http.Client{}, Timeout: time.Second, BaseUrl: server.URL}),
+		PriceFormatter: mockPriceFormatter(),
+		Throttle:       fastThrottle,
+	})
+
+	listings, err := m.GetListings(bCtx.Background(), mockAccount, mockChainId)
+	req.NoError(err)
+	req.Len(listings, 1)
+	req.Equal(domain.TokenId("9"), listings[0].TokenId)
+	req.Equal("3.000000", listings[0].Price)
+	req.Equal(external_listing.SourceOpensea, listings[0].Source)
+}
+
+func TestFloor(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders":
+			if r.URL.Query().Get("isOrderAsk") == "true" {
+				req.Equal(mockCollection.ToLowerStr(), r.URL.Query().Get("collection"))
+				req.Equal("PRICE_ASC", r.URL.Query().Get("sort"))
+				fmt.Fprintf(w, `{"success":true,"data":[{"hash":"0xhash","collectionAddress":"%s","tokenId":"7","amount":1,"price":"1500000000000000000","currencyAddress":"%s"}]}`,
+					mockCollection, mockWeth)
+				return
+			}
+			req.Equal(mockCollection.ToLowerStr(), r.URL.Query().Get("contract"))
+			req.Equal("price", r.URL.Query().Get("sort"))
+			req.Equal("asc", r.URL.Query().Get("direction"))
+			fmt.Fprint(w, `{"success":true,"next":"","data":[]}`)
+		case "/asset_contract/" + mockCollection.ToLowerStr():
+			fmt.Fprint(w, `{"collection":{"slug":"mock"}}`)
+		case "/collection/mock":
+			fmt.Fprint(w, `{"collection":{"stats":{"floor_price":2.5}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	looksRare := NewLooksRare(&LooksRareCfg{HttpClient: http.Client{}, Timeout: time.Second, BaseUrl: server.URL, PriceFormatter: mockPriceFormatter(), Throttle: fastThrottle})
+	floor, err := looksRare.GetFloor(bCtx.Background(), mockChainId, mockCollection)
+	req.NoError(err)
+	req.Equal(&external_listing.SourceFloor{Source: external_listing.SourceLooksRare, TokenId: "7", PriceInNative: 1.5, PriceInUsd: 1500}, floor)
+
+	x2y2 := NewX2Y2(&X2Y2Cfg{HttpClient: http.Client{}, Timeout: time.Second, BaseUrl: server.URL, PriceFormatter: mockPriceFormatter(), Throttle: fastThrottle})
+	_, err = x2y2.GetFloor(bCtx.Background(), mockChainId, mockCollection)
+	req.ErrorIs(err, domain.ErrNotFound)
+
+	os := NewOpensea(&OpenseaCfg{
+		Client:         opensea.NewClient(&opensea.ClientCfg{HttpClient: http.Client{}, Timeout: time.Second, BaseUrl: server.URL}),
+		PriceFormatter: mockPriceFormatter(),
+		Throttle:       fastThrottle,
+	})
+	floor, err = os.GetFloor(bCtx.Background(), mockChainId, mockCollection)
+	req.NoError(err)
+	req.Equal(&external_listing.SourceFloor{Source: external_listing.SourceOpensea, PriceInNative: 2.5, PriceInUsd: 2500}, floor)
+}
+
+func TestThrottleWaitsForRateLimit(t *testing.T) {
+	req := require.New(t)
+
+	key := fmt.Sprintf("marketplace:%s", external_listing.SourceLooksRare)
+	limiter := &mRatelimit.Limiter{}
+	limiter.On("Allow", mock.Anything, key, 1, time.Second).Return(&ratelimit.Result{Allowed: false, Limit: 1, RetryAfter: time.Millisecond}, nil).Twice()
+	limiter.On("Allow", mock.Anything, key, 1, time.Second).Return(&ratelimit.Result{Allowed: true, Limit: 1}, nil).Once()
+	throttle := newThrottle(external_listing.SourceLooksRare, ThrottleCfg{Limiter: limiter, Requests: 1, Window: time.Second})
+
+	calls := 0
+	err := throttle.do(bCtx.Background(), func() error {
+		calls++
+		return nil
+	})
+	req.NoError(err)
+	req.Equal(1, calls)
+	limiter.AssertExpectations(t)
+}
@@ -0,0 +1,228 @@
+package marketplace
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	"github.com/x-xyz/goapi/service/opensea"
+)
+
+const openseaTimeLayout = "2006-01-02T15:04:05"
+
+// seaport item types of the offer, listings offering tokens are skipped
+const (
+	seaportItemTypeNative = 0
+	seaportItemTypeErc20  = 1
+)
+
+type OpenseaCfg struct {
+	Client         opensea.Client
+	PriceFormatter pricefomatter.PriceFormatter
+	Throttle       ThrottleCfg
+}
+
+type openseaMarketplace struct {
+	client         opensea.Client
+	priceFormatter pricefomatter.PriceFormatter
+	throttle       *throttle
+}
+
+func NewOpensea(cfg *OpenseaCfg) external_listing.Marketplace {
+	return &openseaMarketplace{
+		client:         cfg.Client,
+		priceFormatter: cfg.PriceFormatter,
+		throttle:       newThrottle(external_listing.SourceOpensea, cfg.Throttle),
+	}
+}
+
+func (m *openseaMarketplace) Source() external_listing.Source {
+	return external_listing.SourceOpensea
+}
+
+// GetListings returns legacy and seaport listings in ETH, listings in other tokens are skipped
+func (m *openseaMarketplace) GetListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) ([]external_listing.ExternalListing, error) {
+	res := []external_listing.ExternalListing{}
+	now := time.Now()
+	cursor := ""
+	for page := 0; page < pageLimit; page++ {
+		var data *opensea.AssetsResp
+		err := m.throttle.do(ctx, func() error {
+			var err error
+			data, err = m.client.GetAssetByOwner(ctx, account, cursor)
+			// opensea client doesn't tell rate limited from other failures
+			if errors.Is(err, opensea.ErrStatusCodeNotOk) {
+				return ErrRetryable
+			}
+			return err
+		})
+		if err != nil {
+			ctx.WithField("err", err).Error("GetAssetByOwner failed")
+			return nil, err
+		}
+
+		for _, asset := range data.Assets {
+			for _, sellOrder := range asset.SellOrders {
+				listing, err := m.fromSellOrder(ctx, account, chainId, sellOrder, now)
+				if err != nil {
+					return nil, err
+				} else if listing != nil {
+					res = append(res, *listing)
+				}
+			}
+			for _, sellOrder := range asset.SeaportSellOrders {
+				listing, err := m.fromSeaportSellOrder(ctx, account, chainId, asset, sellOrder, now)
+				if err != nil {
+					return nil, err
+				} else if listing != nil {
+					res = append(res, *listing)
+				}
+			}
+		}
+
+		cursor = data.Next
+		if cursor == "" {
+			break
+		}
+	}
+	return res, nil
+}
+
+// GetFloor returns the floor price in ETH of the collection stats, opensea doesn't tell the floor token
+func (m *openseaMarketplace) GetFloor(ctx bCtx.Ctx, chainId domain.ChainId, collection domain.Address) (*external_listing.SourceFloor, error) {
+	var contract *opensea.AssetContractResp
+	if err := m.throttle.do(ctx, func() error {
+		var err error
+		contract, err = m.client.GetAssetContractByAddress(ctx, collection.ToLowerStr())
+		if errors.Is(err, opensea.ErrStatusCodeNotOk) {
+			return ErrRetryable
+		}
+		return err
+	}); err != nil {
+		ctx.WithField("err", err).Error("GetAssetContractByAddress failed")
+		return nil, err
+	}
+	if contract.Collection.Slug == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	var data *opensea.CollectionResp
+	if err := m.throttle.do(ctx, func() error {
+		var err error
+		data, err = m.client.GetCollectionBySlug(ctx, contract.Collection.Slug)
+		if errors.Is(err, opensea.ErrStatusCodeNotOk) {
+			return ErrRetryable
+		}
+		return err
+	}); err != nil {
+		ctx.WithField("err", err).Error("GetCollectionBySlug failed")
+		return nil, err
+	}
+	price := decimal.NewFromFloat(data.Collection.Stats.FloorPrice).Shift(18).BigInt()
+	return toFloor(ctx, m.priceFormatter, external_listing.SourceOpensea, chainId, "", domain.EmptyAddress, price)
+}
+
+func (m *openseaMarketplace) fromSellOrder(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId, sellOrder opensea.SellOrder, now time.Time) (*external_listing.ExternalListing, error) {
+	if !sellOrder.PaymentTokenContract.Address.Equals(domain.EmptyAddress) {
+		return nil, nil
+	}
+	quantity, err := strconv.ParseInt(sellOrder.Quantity, 10, 64)
+	if err != nil {
+		ctx.WithField("err", err).Error("quantity ParseInt failed")
+		return nil, err
+	}
+	price, err := decimal.NewFromString(sellOrder.CurrentPrice)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":          err,
+			"currentPrice": sellOrder.CurrentPrice,
+		}).Error("currentPrice NewFromString failed")
+		return nil, err
+	}
+	startTime, err := time.Parse(time.RFC3339, sellOrder.StartTime)
+	if err != nil {
+		ctx.WithField("err", err).Error("startTime time parse failed")
+		return nil, err
+	}
+	deadline, err := time.Parse(openseaTimeLayout, sellOrder.Deadline)
+	if err != nil {
+		ctx.WithField("err", err).Error("deadline time parse failed")
+		return nil, err
+	}
+	listing, _ := normalize(ctx, m.priceFormatter, listingParams{
+		source:      external_listing.SourceOpensea,
+		owner:       account,
+		chainId:     chainId,
+		collection:  sellOrder.Metadata.Asset.Address,
+		tokenId:     sellOrder.Metadata.Asset.TokenId,
+		quantity:    quantity,
+		currency:    domain.EmptyAddress,
+		price:       price.BigInt(),
+		startTime:   startTime,
+		deadline:    deadline,
+		updatedTime: now,
+		taker:       sellOrder.Taker.Address,
+	})
+	return listing, nil
+}
+
+func (m *openseaMarketplace) fromSeaportSellOrder(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId, asset opensea.Asset, sellOrder opensea.SeaportSellOrder, now time.Time) (*external_listing.ExternalListing, error) {
+	params := sellOrder.ProtocolData.Parameters
+	for _, consideration := range params.Consideration {
+		if !consideration.ContractAddress.Equals(domain.EmptyAddress) {
+			return nil, nil
+		}
+	}
+	if len(params.Offer) != 1 {
+		return nil, nil
+	}
+	offer := params.Offer[0]
+	if offer.ItemType == seaportItemTypeNative || offer.ItemType == seaportItemTypeErc20 || !offer.ContractAddress.Equals(asset.AssetContract.Address) {
+		return nil, nil
+	}
+
+	quantity, err := strconv.ParseInt(offer.EndAmount, 10, 64)
+	if err != nil {
+		ctx.WithField("err", err).Error("quantity ParseInt failed")
+		return nil, err
+	}
+	price, err := decimal.NewFromString(sellOrder.CurrentPrice)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":          err,
+			"currentPrice": sellOrder.CurrentPrice,
+		}).Error("currentPrice NewFromString failed")
+		return nil, err
+	}
+	startTime, err := time.Parse(openseaTimeLayout, sellOrder.StartTime)
+	if err != nil {
+		ctx.WithField("err", err).Error("startTime time parse failed")
+		return nil, err
+	}
+	deadline, err := time.Parse(openseaTimeLayout, sellOrder.Deadline)
+	if err != nil {
+		ctx.WithField("err", err).Error("deadline time parse failed")
+		return nil, err
+	}
+	listing, _ := normalize(ctx, m.priceFormatter, listingParams{
+		source:      external_listing.SourceOpensea,
+		owner:       account,
+		chainId:     chainId,
+		collection:  asset.AssetContract.Address,
+		tokenId:     asset.TokenId,
+		quantity:    quantity,
+		currency:    domain.EmptyAddress,
+		price:       price.BigInt(),
+		startTime:   startTime,
+		deadline:    deadline,
+		updatedTime: now,
+		taker:       sellOrder.Taker.Address,
+	})
+	return listing, nil
+}
@@ -0,0 +1,121 @@
+package marketplace
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/x-xyz/goapi/base/backoff"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	"github.com/x-xyz/goapi/service/ratelimit"
+)
+
+var (
+	ErrStatusCodeNotOk = errors.New("http.status != 200")
+	// ErrRetryable is returned by requests worth retrying, e.g. rate limited or server errors
+	ErrRetryable = errors.New("retryable marketplace error")
+)
+
+const (
+	defaultBackoffStart = time.Second
+	defaultBackoffLimit = 30 * time.Second
+	defaultMaxRetries   = 3
+)
+
+// ThrottleCfg limits requests to a marketplace, it's shared by all instances if Limiter is set
+type ThrottleCfg struct {
+	// Limiter is optional, requests aren't rate limited if nil
+	Limiter ratelimit.Limiter
+	// Requests is the max number of requests in Window
+	Requests int
+	Window   time.Duration
+	// BackoffStart and BackoffLimit are the exponential backoff of retryable errors, defaultBackoffStart and
+	// defaultBackoffLimit if zero
+	BackoffStart time.Duration
+	BackoffLimit time.Duration
+	// MaxRetries is defaultMaxRetries if zero
+	MaxRetries int
+}
+
+type throttle struct {
+	source       external_listing.Source
+	limiter      ratelimit.Limiter
+	requests     int
+	window       time.Duration
+	backoffStart time.Duration
+	backoffLimit time.Duration
+	maxRetries   int
+}
+
+func newThrottle(source external_listing.Source, cfg ThrottleCfg) *throttle {
+	t := &throttle{
+		source:       source,
+		limiter:      cfg.Limiter,
+		requests:     cfg.Requests,
+		window:       cfg.Window,
+		backoffStart: cfg.BackoffStart,
+		backoffLimit: cfg.BackoffLimit,
+		maxRetries:   cfg.MaxRetries,
+	}
+	if t.backoffStart == 0 {
+		t.backoffStart = defaultBackoffStart
+	}
+	if t.backoffLimit == 0 {
+		t.backoffLimit = defaultBackoffLimit
+	}
+	if t.maxRetries == 0 {
+		t.maxRetries = defaultMaxRetries
+	}
+	return t
+}
+
+// wait blocks until the rate limit of the source allows a request
+func (t *throttle) wait(c ctx.Ctx) error {
+	if t.limiter == nil || t.requests <= 0 || t.window <= 0 {
+		return nil
+	}
+	for {
+		res, err := t.limiter.Allow(c, fmt.Sprintf("marketplace:%s", t.source), t.requests, t.window)
+		if err != nil {
+			// fail open, the marketplace backs off by itself
+			c.WithFields(log.Fields{
+				"source": t.source,
+				"err":    err,
+			}).Warn("limiter.Allow failed")
+			return nil
+		}
+		if res.Allowed {
+			return nil
+		}
+		select {
+		case <-c.Done():
+			return c.Err()
+		case <-time.After(res.RetryAfter):
+		}
+	}
+}
+
+// do runs fn within the rate limit, and retries it with exponential backoff if it returns ErrRetryable
+func (t *throttle) do(c ctx.Ctx, fn func() error) error {
+	b := backoff.NewExponential(t.backoffStart, t.backoffLimit)
+	for retries := 0; ; retries++ {
+		if err := t.wait(c); err != nil {
+			return err
+		}
+		err := fn()
+		if !errors.Is(err, ErrRetryable) || retries >= t.maxRetries {
+			return err
+		}
+		c.WithFields(log.Fields{
+			"source":  t.source,
+			"retries": retries,
+			"backoff": b.NextDuration,
+			"err":     err,
+		}).Warn("marketplace request failed, backing off")
+		if err := b.Backoff(c); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package marketplace
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+)
+
+const x2y2Api = "https://api.x2y2.org/v1"
+
+type X2Y2Cfg struct {
+	HttpClient http.Client
+	Timeout    time.Duration
+	Apikey     string
+	// BaseUrl is the v1 api if empty
+	BaseUrl        string
+	PriceFormatter pricefomatter.PriceFormatter
+	Throttle       ThrottleCfg
+}
+
+type x2y2Order struct {
+	Id       int64          `json:"id"`
+	Maker    domain.Address `json:"maker"`
+	Price    string         `json:"price"`
+	Currency domain.Address `json:"currency"`
+	Amount   int64          `json:"amount"`
+	// CreatedAt and EndAt are unix timestamps
+	CreatedAt int64 `json:"created_at"`
+	EndAt     int64 `json:"end_at"`
+	Token     struct {
+		Contract domain.Address `json:"contract"`
+		TokenId  domain.TokenId `json:"token_id"`
+	} `json:"token"`
+}
+
+type x2y2OrdersResp struct {
+	Success bool        `json:"success"`
+	Next    string      `json:"next"`
+	Data    []x2y2Order `json:"data"`
+}
+
+type x2y2Marketplace struct {
+	http           *httpClient
+	baseUrl        string
+	priceFormatter pricefomatter.PriceFormatter
+	throttle       *throttle
+}
+
+func NewX2Y2(cfg *X2Y2Cfg) external_listing.Marketplace {
+	baseUrl := cfg.BaseUrl
+	if baseUrl == "" {
+		baseUrl = x2y2Api
+	}
+	return &x2y2Marketplace{
+		http:           &httpClient{client: cfg.HttpClient, timeout: cfg.Timeout, headers: map[string]string{"X-API-KEY": cfg.Apikey}},
+		baseUrl:        baseUrl,
+		priceFormatter: cfg.PriceFormatter,
+		throttle:       newThrottle(external_listing.SourceX2Y2, cfg.Throttle),
+	}
+}
+
+func (m *x2y2Marketplace) Source() external_listing.Source {
+	return external_listing.SourceX2Y2
+}
+
+// GetListings returns open sell orders of the account, erc721 orders have no amount and count as 1
+func (m *x2y2Marketplace) GetListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) ([]external_listing.ExternalListing, error) {
+	res := []external_listing.ExternalListing{}
+	now := time.Now()
+	cursor := ""
+	for page := 0; page < pageLimit; page++ {
+		params := url.Values{}
+		params.Add("maker", account.ToLowerStr())
+		params.Add("status", "open")
+		if cursor != "" {
+			params.Add("cursor", cursor)
+		}
+		url := fmt.Sprintf("%s/orders?%s", m.baseUrl, params.Encode())
+
+		resp := x2y2OrdersResp{}
+		if err := m.throttle.do(ctx, func() error {
+			return m.http.getJson(ctx, url, &resp)
+		}); err != nil {
+			ctx.WithField("err", err).Error("x2y2 get orders failed")
+			return nil, err
+		}
+
+		for _, o := range resp.Data {
+			price, _ := new(big.Int).SetString(o.Price, 10)
+			quantity := o.Amount
+			if quantity == 0 {
+				quantity = 1
+			}
+			if listing, ok := normalize(ctx, m.priceFormatter, listingParams{
+				source:      external_listing.SourceX2Y2,
+				owner:       account,
+				chainId:     chainId,
+				collection:  o.Token.Contract,
+				tokenId:     o.Token.TokenId,
+				quantity:    quantity,
+				currency:    o.Currency,
+				price:       price,
+				startTime:   time.Unix(o.CreatedAt, 0),
+				deadline:    time.Unix(o.EndAt, 0),
+				updatedTime: now,
+			}); ok {
+				res = append(res, *listing)
+			}
+		}
+
+		cursor = resp.Next
+		if cursor == "" {
+			break
+		}
+	}
+	return res, nil
+}
+
+// GetFloor returns the cheapest open sell order of the collection
+func (m *x2y2Marketplace) GetFloor(ctx bCtx.Ctx, chainId domain.ChainId, collection domain.Address) (*external_listing.SourceFloor, error) {
+	params := url.Values{}
+	params.Add("contract", collection.ToLowerStr())
+	params.Add("status", "open")
+	params.Add("sort", "price")
+	params.Add("direction", "asc")
+	params.Add("limit", "1")
+	url := fmt.Sprintf("%s/orders?%s", m.baseUrl, params.Encode())
+
+	resp := x2y2OrdersResp{}
+	if err := m.throttle.do(ctx, func() error {
+		return m.http.getJson(ctx, url, &resp)
+	}); err != nil {
+		ctx.WithField("err", err).Error("x2y2 get orders failed")
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	o := resp.Data[0]
+	price, _ := new(big.Int).SetString(o.Price, 10)
+	return toFloor(ctx, m.priceFormatter, external_listing.SourceX2Y2, chainId, o.Token.TokenId, o.Currency, price)
+}
@@ -0,0 +1,91 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const leafSize = 32
+
+var (
+	ErrNoLeaves    = errors.New("no leaves")
+	ErrOutOfRange  = errors.New("leaf index out of range")
+	ErrInvalidLeaf = errors.New("leaf must be 32 bytes")
+)
+
+// Tree is a keccak256 merkle tree with sorted pair hashing, which is what
+// OpenZeppelin's MerkleProof.verify expects, so proofs need no position bits.
+// An odd node at the end of a layer is promoted to the next layer as is.
+type Tree struct {
+	// layers[0] are the leaves, the last layer holds the root only
+	layers [][][]byte
+}
+
+// New builds a tree from hashed leaves, the order of leaves is kept
+func New(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrNoLeaves
+	}
+
+	for _, leaf := range leaves {
+		if len(leaf) != leafSize {
+			return nil, ErrInvalidLeaf
+		}
+	}
+
+	layers := [][][]byte{leaves}
+	for layer := leaves; len(layer) > 1; {
+		next := make([][]byte, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+
+	return &Tree{layers: layers}, nil
+}
+
+func (t *Tree) Root() []byte {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Proof returns the sibling hashes from the leaf at index up to the root
+func (t *Tree) Proof(index int) ([][]byte, error) {
+	if index < 0 || index >= len(t.layers[0]) {
+		return nil, ErrOutOfRange
+	}
+
+	proof := [][]byte{}
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := index ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// Verify checks the leaf is in the tree of root
+func Verify(root []byte, leaf []byte, proof [][]byte) bool {
+	hash := leaf
+	for _, sibling := range proof {
+		hash = hashPair(hash, sibling)
+	}
+	return bytes.Equal(hash, root)
+}
+
+func hashPair(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256(a, b)
+}
@@ -0,0 +1,82 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type MerkleSuite struct {
+	suite.Suite
+}
+
+func TestMerkleSuite(t *testing.T) {
+	suite.Run(t, new(MerkleSuite))
+}
+
+func leaves(n int) [][]byte {
+	res := make([][]byte, n)
+	for i := range res {
+		res[i] = crypto.Keccak256([]byte{byte(i)})
+	}
+	return res
+}
+
+func (s *MerkleSuite) TestSingleLeaf() {
+	l := leaves(1)
+	tree, err := New(l)
+	s.NoError(err)
+	s.Equal(l[0], tree.Root())
+
+	proof, err := tree.Proof(0)
+	s.NoError(err)
+	s.Empty(proof)
+	s.True(Verify(tree.Root(), l[0], proof))
+}
+
+func (s *MerkleSuite) TestTwoLeaves() {
+	l := leaves(2)
+	tree, err := New(l)
+	s.NoError(err)
+	// pair is sorted before hashing, so the order of leaves doesn't matter
+	reversed, err := New([][]byte{l[1], l[0]})
+	s.NoError(err)
+	s.Equal(tree.Root(), reversed.Root())
+}
+
+func (s *MerkleSuite) TestProofs() {
+	for _, n := range []int{2, 3, 5, 8, 13} {
+		l := leaves(n)
+		tree, err := New(l)
+		s.NoError(err)
+
+		for i := range l {
+			proof, err := tree.Proof(i)
+			s.NoError(err)
+			s.True(Verify(tree.Root(), l[i], proof), "n=%d i=%d", n, i)
+			s.False(Verify(tree.Root(), crypto.Keccak256([]byte("other")), proof), "n=%d i=%d", n, i)
+		}
+	}
+}
+
+func (s *MerkleSuite) TestKnownRoot() {
+	// hashPair(hashPair(l0, l1), l2) with sorted pairs
+	l := leaves(3)
+	tree, err := New(l)
+	s.NoError(err)
+	s.Equal(hexutil.Encode(hashPair(hashPair(l[0], l[1]), l[2])), hexutil.Encode(tree.Root()))
+}
+
+func (s *MerkleSuite) TestErrors() {
+	_, err := New(nil)
+	s.ErrorIs(err, ErrNoLeaves)
+
+	_, err = New([][]byte{{1, 2}})
+	s.ErrorIs(err, ErrInvalidLeaf)
+
+	tree, _ := New(leaves(2))
+	_, err = tree.Proof(2)
+	s.ErrorIs(err, ErrOutOfRange)
+}
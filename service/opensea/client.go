@@ -85,6 +85,8 @@ type ClientCfg struct {
 	HttpClient http.Client
 	Timeout    time.Duration
 	Apikey     string
+	// BaseUrl is the v1 api if empty
+	BaseUrl string
 }
 
 type AssetContractResp struct {
@@ -20,10 +20,15 @@ const (
 )
 
 func NewClient(cfg *ClientCfg) Client {
+	baseUrl := cfg.BaseUrl
+	if baseUrl == "" {
+		baseUrl = v1Api
+	}
 	return &client{
 		client:  cfg.HttpClient,
 		timeout: cfg.Timeout,
 		apikey:  cfg.Apikey,
+		baseUrl: baseUrl,
 	}
 }
 
@@ -31,10 +36,11 @@ type client struct {
 	client  http.Client
 	timeout time.Duration
 	apikey  string
+	baseUrl string
 }
 
 func (c *client) GetAssetContractByAddress(ctx bCtx.Ctx, addr string) (*AssetContractResp, error) {
-	url := fmt.Sprintf("%s/asset_contract/%s", v1Api, addr)
+	url := fmt.Sprintf("%s/asset_contract/%s", c.baseUrl, addr)
 	data, err := c.get(ctx, url)
 	if err != nil {
 		ctx.WithFields(log.Fields{
@@ -52,7 +58,7 @@ func (c *client) GetAssetContractByAddress(ctx bCtx.Ctx, addr string) (*AssetCon
 }
 
 func (c *client) GetCollectionBySlug(ctx bCtx.Ctx, slug string) (*CollectionResp, error) {
-	url := fmt.Sprintf("%s/collection/%s", v1Api, slug)
+	url := fmt.Sprintf("%s/collection/%s", c.baseUrl, slug)
 	data, err := c.get(ctx, url)
 	if err != nil {
 		ctx.WithFields(log.Fields{
@@ -114,7 +120,7 @@ func (c *client) GetEvent(ctx bCtx.Ctx, opts ...GetEventOptionsFunc) (*EventResp
 		return nil, err
 	}
 
-	base, err := url.Parse(fmt.Sprintf("%s/events", v1Api))
+	base, err := url.Parse(fmt.Sprintf("%s/events", c.baseUrl))
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +169,7 @@ func (c *client) GetEvent(ctx bCtx.Ctx, opts ...GetEventOptionsFunc) (*EventResp
 }
 
 func (c *client) GetAsset(ctx bCtx.Ctx, collectionSlug string, tokenId string) (*AssetsResp, error) {
-	base, err := url.Parse(fmt.Sprintf("%s/assets", v1Api))
+	base, err := url.Parse(fmt.Sprintf("%s/assets", c.baseUrl))
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +204,7 @@ func (c *client) GetAsset(ctx bCtx.Ctx, collectionSlug string, tokenId string) (
 }
 
 func (c *client) GetAssetByOwner(ctx bCtx.Ctx, owner domain.Address, cursor string) (*AssetsResp, error) {
-	base, err := url.Parse(fmt.Sprintf("%s/assets", v1Api))
+	base, err := url.Parse(fmt.Sprintf("%s/assets", c.baseUrl))
 	if err != nil {
 		return nil, err
 	}
@@ -288,6 +288,21 @@ func (im *impl) RemoveAll(context ctx.Ctx, table domain.Table, selector interfac
 	return res.DeletedCount, nil
 }
 
+func (im *impl) CreateIndex(context ctx.Ctx, table domain.Table, index mongo.IndexModel) error {
+	client := im.getClient(context)
+
+	context = ctx.WithValues(context, map[string]interface{}{
+		"table": table,
+		"keys":  index.Keys,
+	})
+
+	if _, err := client.Database(client.DbName).Collection(string(table)).Indexes().CreateOne(context, index); err != nil {
+		im.logerr(context, "CreateIndex: CreateOne failed", err)
+		return err
+	}
+	return nil
+}
+
 func initPatchOp() *patchOp {
 	return &patchOp{}
 }
@@ -315,13 +330,17 @@ func (im *impl) Patch(context ctx.Ctx, table domain.Table, selector, update inte
 	updater := bson.M{"$set": update}
 	if o.patchMany {
 		updateRes, err = client.Database(client.DbName).Collection(string(table)).UpdateMany(context, selector, updater)
-		if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateKey
+		} else if err != nil {
 			im.logerr(context, "Patch: UpdateMany failed", err)
 			return err
 		}
 	} else {
 		updateRes, err = client.Database(client.DbName).Collection(string(table)).UpdateOne(context, selector, updater)
-		if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateKey
+		} else if err != nil {
 			im.logerr(context, "Patch: UpdateOne failed", err)
 			return err
 		}
@@ -439,6 +458,35 @@ func (im *impl) BulkUpsert(context ctx.Ctx, table domain.Table, upsertOps []Upse
 	return res.MatchedCount, res.ModifiedCount, nil
 }
 
+func (im *impl) BulkPatch(context ctx.Ctx, table domain.Table, patchOps []UpsertOp, ops ...PatchOp) (matchedCnt int64, modifiedCnt int64, err error) {
+	if len(patchOps) == 0 {
+		return 0, 0, fmt.Errorf("length of `pairs` equals 0")
+	}
+
+	o := initPatchOp()
+	for _, opt := range ops {
+		opt(o)
+	}
+
+	client := im.getClient(context)
+	bulkWriteOpts := options.BulkWrite().SetOrdered(false)
+	models := make([]mongo.WriteModel, 0, len(patchOps))
+	for _, op := range patchOps {
+		updater := bson.M{"$set": op.Updater}
+		if o.patchMany {
+			models = append(models, mongo.NewUpdateManyModel().SetFilter(op.Selector).SetUpdate(updater))
+		} else {
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(op.Selector).SetUpdate(updater))
+		}
+	}
+	res, err := client.Database(client.DbName).Collection(string(table)).BulkWrite(context, models, bulkWriteOpts)
+	if err != nil {
+		im.logerr(context, "BulkPatch: BulkWrite failed", err)
+		return 0, 0, err
+	}
+	return res.MatchedCount, res.ModifiedCount, nil
+}
+
 // Iter wraps mongo's cursor struct
 type Iter struct {
 	cursor *mongo.Cursor
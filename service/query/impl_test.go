@@ -705,6 +705,49 @@ func (q *querySuite) TestBulkUpsert() {
 	q.Error(err)
 }
 
+func (q *querySuite) TestBulkPatch() {
+	type Dummy struct {
+		Dummy  string `json:"dummy" bson:"dummy"`
+		Group  string `json:"group" bson:"group"`
+		Update string `json:"updatekey" bson:"updatekey"`
+	}
+
+	client := q.im.getClient(mockCTX)
+	collection := client.Database(dbName).Collection(string(mockTable))
+
+	for _, d := range []Dummy{{"bulk-patch-1", "a", ""}, {"bulk-patch-2", "a", ""}, {"bulk-patch-3", "b", ""}} {
+		q.Require().NoError(q.im.Insert(mockCTX, mockTable, d))
+	}
+
+	// patch one entry per op, non-existing entries aren't inserted
+	matched, _, err := q.im.BulkPatch(mockCTX, mockTable, []UpsertOp{
+		{Selector: bson.M{"dummy": "bulk-patch-1"}, Updater: bson.M{"updatekey": "one"}},
+		{Selector: bson.M{"dummy": "bulk-patch-0"}, Updater: bson.M{"updatekey": "none"}},
+	})
+	q.Require().NoError(err)
+	q.Equal(int64(1), matched)
+
+	ans := Dummy{}
+	q.NoError(collection.FindOne(mockCTX, bson.M{"dummy": "bulk-patch-1"}).Decode(&ans))
+	q.Equal(Dummy{"bulk-patch-1", "a", "one"}, ans)
+	n, err := collection.CountDocuments(mockCTX, bson.M{"dummy": "bulk-patch-0"})
+	q.NoError(err)
+	q.Equal(int64(0), n)
+
+	// patch all entries selected by each op
+	matched, _, err = q.im.BulkPatch(mockCTX, mockTable, []UpsertOp{
+		{Selector: bson.M{"group": "a"}, Updater: bson.M{"updatekey": "group-a"}},
+		{Selector: bson.M{"group": "b"}, Updater: bson.M{"updatekey": "group-b"}},
+	}, WithPatchMany(true))
+	q.Require().NoError(err)
+	q.Equal(int64(3), matched)
+	q.NoError(collection.FindOne(mockCTX, bson.M{"dummy": "bulk-patch-2"}).Decode(&ans))
+	q.Equal(Dummy{"bulk-patch-2", "a", "group-a"}, ans)
+
+	_, _, err = q.im.BulkPatch(mockCTX, mockTable, nil)
+	q.Error(err)
+}
+
 func (q *querySuite) testPipe() {
 	type Dummy struct {
 		PrimaryKey string `bson:"primaryKey" json:"primaryKey"`
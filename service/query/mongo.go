@@ -12,9 +12,11 @@ package query
 */
 
 import (
+	"errors"
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/domain"
@@ -31,6 +33,11 @@ var (
 	ErrCollScan = fmt.Errorf("COLLSCAN is not allowed")
 )
 
+// IsNotFound checks if err is a not found error of either the db or a repo
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, domain.ErrNotFound)
+}
+
 type patchOp struct {
 	patchMany bool
 }
@@ -138,5 +145,13 @@ type Mongo interface {
 	// Note that upsert operations are executed in parallel, as well as in a non-deterministic order.
 	BulkUpsert(context ctx.Ctx, table domain.Table, BulkOps []UpsertOp) (matchedCnt int64, modifiedCnt int64, err error)
 
+	// BulkPatch sets fields of `Updater` to entries selected by `Selector` in one bulk write without upsert,
+	// set WithPatchMany(true) to patch all entries selected by each op
+	BulkPatch(context ctx.Ctx, table domain.Table, BulkOps []UpsertOp, ops ...PatchOp) (matchedCnt int64, modifiedCnt int64, err error)
+
 	RunWithTransaction(context ctx.Ctx, run func(ctx.Ctx) error) error
+
+	// CreateIndex creates the index on the table, it's a no-op if the same index exists.
+	// Writes violating a unique index return ErrDuplicateKey
+	CreateIndex(context ctx.Ctx, table domain.Table, index mongo.IndexModel) error
 }
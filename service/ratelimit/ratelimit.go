@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/service/redis"
+)
+
+var ErrBadReply = errors.New("bad rate limit reply")
+
+/*
+Sliding window log on a sorted set:
+  - key:
+    "rateLimit:<key>"
+  - member:
+    "<nowNs>-<random>", unique per request
+  - score:
+    request time in millisecond
+
+Entries out of window are trimmed on each hit, a hit is only recorded when allowed,
+so rejected requests don't extend the window.
+*/
+var slidingWindow = redis.NewScript(1, `
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+if count >= limit then
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	local retryAfter = window
+	if oldest[2] then
+		retryAfter = tonumber(oldest[2]) + window - now
+	end
+	return {0, 0, retryAfter}
+end
+
+redis.call('ZADD', key, now, member)
+redis.call('PEXPIRE', key, window)
+return {1, limit - count - 1, 0}
+`)
+
+// Result is the outcome of a hit
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time until a slot is freed, only set when not allowed
+	RetryAfter time.Duration
+}
+
+// Limiter counts hits of keys in sliding windows
+type Limiter interface {
+	// Allow records a hit of key if there are less than limit hits in the last window
+	Allow(c ctx.Ctx, key string, limit int, window time.Duration) (*Result, error)
+}
+
+type impl struct {
+	redis redis.Service
+	now   func() time.Time
+}
+
+func New(redis redis.Service) Limiter {
+	return &impl{redis: redis, now: time.Now}
+}
+
+func (im *impl) Allow(c ctx.Ctx, key string, limit int, window time.Duration) (*Result, error) {
+	now := im.now()
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), rand.Int63())
+
+	reply, err := redis.Ints(im.redis.ScriptDo(c, slidingWindow,
+		keys.RedisKey(keys.PfxRateLimit, key),
+		now.UnixNano()/int64(time.Millisecond),
+		window.Milliseconds(),
+		limit,
+		member,
+	))
+	if err != nil {
+		c.WithField("err", err).Error("redis.ScriptDo failed")
+		return nil, err
+	}
+
+	if len(reply) != 3 {
+		c.WithField("reply", reply).Error("unexpected rate limit reply")
+		return nil, ErrBadReply
+	}
+
+	return &Result{
+		Allowed:    reply[0] == 1,
+		Limit:      limit,
+		Remaining:  reply[1],
+		RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	mockRedis "github.com/x-xyz/goapi/service/redis/mocks"
+)
+
+var (
+	mockCtx = ctx.Background()
+	mockNow = time.Unix(1600000000, 0)
+)
+
+type testsuite struct {
+	suite.Suite
+	im    *impl
+	redis *mockRedis.Service
+}
+
+func (ts *testsuite) SetupTest() {
+	ts.redis = &mockRedis.Service{}
+	ts.im = New(ts.redis).(*impl)
+	ts.im.now = func() time.Time { return mockNow }
+}
+
+func Test(t *testing.T) {
+	suite.Run(t, new(testsuite))
+}
+
+func (ts *testsuite) TestAllowed() {
+	ts.redis.On("ScriptDo", mockCtx, slidingWindow, "rateLimit:ip:1.2.3.4", int64(1600000000000), int64(60000), 10, mock.Anything).
+		Return([]interface{}{int64(1), int64(9), int64(0)}, nil).Once()
+
+	res, err := ts.im.Allow(mockCtx, "ip:1.2.3.4", 10, time.Minute)
+	ts.NoError(err)
+	ts.Equal(&Result{Allowed: true, Limit: 10, Remaining: 9}, res)
+}
+
+func (ts *testsuite) TestRejected() {
+	ts.redis.On("ScriptDo", mockCtx, slidingWindow, "rateLimit:ip:1.2.3.4", int64(1600000000000), int64(60000), 10, mock.Anything).
+		Return([]interface{}{int64(0), int64(0), int64(1500)}, nil).Once()
+
+	res, err := ts.im.Allow(mockCtx, "ip:1.2.3.4", 10, time.Minute)
+	ts.NoError(err)
+	ts.Equal(&Result{Allowed: false, Limit: 10, RetryAfter: 1500 * time.Millisecond}, res)
+}
+
+func (ts *testsuite) TestBadReply() {
+	ts.redis.On("ScriptDo", mockCtx, slidingWindow, "rateLimit:ip:1.2.3.4", int64(1600000000000), int64(60000), 10, mock.Anything).
+		Return([]interface{}{int64(1)}, nil).Once()
+
+	_, err := ts.im.Allow(mockCtx, "ip:1.2.3.4", 10, time.Minute)
+	ts.ErrorIs(err, ErrBadReply)
+}
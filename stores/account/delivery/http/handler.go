@@ -1,6 +1,8 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -11,7 +13,9 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/like"
+	"github.com/x-xyz/goapi/domain/moderation"
 	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/spam"
 	"github.com/x-xyz/goapi/middleware"
 	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 )
@@ -22,16 +26,18 @@ type handler struct {
 	fu         account.FolderUseCase
 	collection collection.Usecase
 	orderNonce account.OrderNonceUseCase
+	moderation moderation.UseCase
 }
 
 // New will initialize the healthcheck/
-func New(e *echo.Echo, au account.Usecase, like like.Usecase, fu account.FolderUseCase, collection collection.Usecase, authMiddleware *authMiddleware.AuthMiddleware, orderNonce account.OrderNonceUseCase) {
+func New(e *echo.Echo, au account.Usecase, like like.Usecase, fu account.FolderUseCase, collection collection.Usecase, authMiddleware *authMiddleware.AuthMiddleware, orderNonce account.OrderNonceUseCase, moderation moderation.UseCase) {
 	h := &handler{
 		au:         au,
 		like:       like,
 		fu:         fu,
 		collection: collection,
 		orderNonce: orderNonce,
+		moderation: moderation,
 	}
 	g := e.Group("/account")
 	g.GET("/:account", h.getAccount, middleware.IsValidAddress("account"))
@@ -50,17 +56,28 @@ func New(e *echo.Echo, au account.Usecase, like like.Usecase, fu account.FolderU
 	g.GET("/:account/folder/:folderId/nfts", h.getNFTsInFolder, middleware.IsValidAddress("account"), authMiddleware.OptionalAuth())
 	g.PUT("/:account/folder/:folderId", h.updateFolder, middleware.IsValidAddress("account"), authMiddleware.Auth())
 	g.DELETE("/:account/folder/:folderId", h.deleteFolder, middleware.IsValidAddress("account"), authMiddleware.Auth())
+	g.PUT("/:account/folder/:folderId/gallery", h.updateGallery, middleware.IsValidAddress("account"), authMiddleware.Auth())
+	g.PUT("/:account/folder/:folderId/order", h.reorderFolder, middleware.IsValidAddress("account"), authMiddleware.Auth())
+	g.PUT("/:account/folder/:folderId/caption", h.setFolderCaption, middleware.IsValidAddress("account"), authMiddleware.Auth())
+	g.POST("/:account/folder/:folderId/nfts", h.addNFTsToFolder, middleware.IsValidAddress("account"), authMiddleware.Auth())
+	g.GET("/:account/gallery/:slug", h.getGallery, middleware.IsValidAddress("account"), authMiddleware.OptionalAuth())
+	g.GET("/:account/shared-folders", h.getSharedFolders, middleware.IsValidAddress("account"), authMiddleware.OptionalAuth())
 	g.GET("/:account/collection/:chainId/:contract", h.getCollectionStatByAccount)
 	g.GET("/:account/collection-summary", h.getCollectionSummary)
+	g.GET("/:account/portfolio", h.getPortfolio, middleware.IsValidAddress("account"))
 	g.GET("/:account/orderNonce/:chainId", h.useOrderNonce, authMiddleware.Auth())
+	g.GET("/:account/linked-wallets", h.getLinkedWallets, middleware.IsValidAddress("account"))
 
 	// self
 	g.PATCH("", h.updateAccount, authMiddleware.Auth())
 	g.PATCH("/banner", h.updateBanner, authMiddleware.Auth())
 	g.PATCH("/avatar", h.updateAvatar, authMiddleware.Auth())
 	g.POST("/nonce", h.generateNonce, authMiddleware.Auth())
+	g.GET("/feed", h.getFeed, authMiddleware.Auth())
 	g.GET("/settings/notification", h.getNotifSettings, authMiddleware.Auth())
 	g.PUT("/settings/notification", h.updateNotifSettings, authMiddleware.Auth())
+	g.POST("/linked-wallets", h.linkWallet, authMiddleware.Auth())
+	g.DELETE("/linked-wallets/:wallet", h.unlinkWallet, authMiddleware.Auth(), middleware.IsValidAddress("wallet"))
 
 	// admin
 	g.POST("/ban", h.ban, authMiddleware.Auth(), authMiddleware.IsModerator())
@@ -199,6 +216,36 @@ func (h *handler) generateNonce(c echo.Context) error {
 	return delivery.MakeJsonResp(c, http.StatusOK, nonce)
 }
 
+// GetFeed
+//
+//	@Description	This api returns activities of followed accounts and liked collections, newest first.
+//	@Tags			activities
+//	@Accept			json
+//	@Produce		json
+//	@Param			types	query		[]string	false	"activity types"
+//	@Param			limit	query		int			false	"paging size, default 20, max 100"
+//	@Param			cursor	query		string		false	"paging cursor, empty for the first page"
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	account.ActivityResult
+//	@Failure		400
+//	@Failure		500
+//	@Router			/account/feed [get]
+func (h *handler) getFeed(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	p := account.FeedParams{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.au.GetFeed(ctx, address, p)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
 func (h *handler) getNotifSettings(c echo.Context) error {
 	ctx := c.Get("ctx").(ctx.Ctx)
 	address := c.Get("address").(domain.Address)
@@ -320,6 +367,7 @@ func (h *handler) ban(c echo.Context) error {
 	type payload struct {
 		Addresses []domain.Address `json:"addresses"`
 		Signature string           `json:"signature"`
+		Reason    string           `json:"reason"`
 	}
 
 	p := &payload{}
@@ -339,11 +387,12 @@ func (h *handler) ban(c echo.Context) error {
 
 	resp := &response{}
 
-	for _, address := range p.Addresses {
-		if err := h.au.Ban(ctx, address); err != nil {
-			resp.Fails = append(resp.Fails, address)
+	for _, target := range p.Addresses {
+		t := moderation.Target{Type: moderation.TargetTypeAccount, Address: target}
+		if err := h.moderation.Ban(ctx, address, t, p.Reason); err != nil {
+			resp.Fails = append(resp.Fails, target)
 		} else {
-			resp.Successes = append(resp.Successes, address)
+			resp.Successes = append(resp.Successes, target)
 		}
 	}
 
@@ -358,6 +407,7 @@ func (h *handler) unban(c echo.Context) error {
 	type payload struct {
 		Addresses []domain.Address `json:"addresses"`
 		Signature string           `json:"signature"`
+		Reason    string           `json:"reason"`
 	}
 
 	p := &payload{}
@@ -377,11 +427,12 @@ func (h *handler) unban(c echo.Context) error {
 
 	resp := &response{}
 
-	for _, address := range p.Addresses {
-		if err := h.au.Unban(ctx, address); err != nil {
-			resp.Fails = append(resp.Fails, address)
+	for _, target := range p.Addresses {
+		t := moderation.Target{Type: moderation.TargetTypeAccount, Address: target}
+		if err := h.moderation.Unban(ctx, address, t, p.Reason); err != nil {
+			resp.Fails = append(resp.Fails, target)
 		} else {
-			resp.Successes = append(resp.Successes, address)
+			resp.Successes = append(resp.Successes, target)
 		}
 	}
 
@@ -397,6 +448,8 @@ func (h *handler) unban(c echo.Context) error {
 //	@Param			account	path		string	true	"account address"	example(0x020ca66c30bec2c4fe3861a94e4db4a498a35872)
 //	@Param			limit	query		int		false	"paging size"
 //	@Param			offset	query		int		false	"paging offset"
+//	@Param			cursor	query		string	false	"paging cursor, empty for the first page. offset is ignored if given"
+//	@Param			linked	query		bool	false	"include activities of all linked wallets"
 //	@Success		200		{object}	account.ActivityResult
 //	@Failure		400
 //	@Failure		404
@@ -413,6 +466,8 @@ func (h *handler) getActivities(c echo.Context) error {
 		Contract *domain.Address               `query:"contract"`
 		TokenId  *domain.TokenId               `query:"tokenId"`
 		Types    []account.ActivityHistoryType `query:"types"`
+		Cursor   *string                       `query:"cursor"`
+		Linked   bool                          `query:"linked"`
 	}
 
 	p := &params{}
@@ -438,7 +493,18 @@ func (h *handler) getActivities(c echo.Context) error {
 		opts = append(opts, account.ActivityHistoryWithTypes(p.Types...))
 	}
 
-	if res, err := h.au.GetActivities(ctx, p.Address, opts...); err != nil {
+	if p.Cursor != nil {
+		opts = append(opts, account.ActivityHistoryWithCursor(*p.Cursor))
+	}
+
+	getActivities := h.au.GetActivities
+	if p.Linked {
+		getActivities = h.au.GetLinkedActivities
+	}
+
+	if res, err := getActivities(ctx, p.Address, opts...); err == domain.ErrInvalidCursor {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
@@ -450,6 +516,7 @@ func (h *handler) getStat(c echo.Context) error {
 
 	type params struct {
 		Address domain.Address `param:"account"`
+		Linked  bool           `query:"linked"`
 	}
 
 	p := &params{}
@@ -458,7 +525,12 @@ func (h *handler) getStat(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
 	}
 
-	if res, err := h.au.GetAccountStat(ctx, p.Address); err != nil {
+	getStat := h.au.GetAccountStat
+	if p.Linked {
+		getStat = h.au.GetLinkedAccountStat
+	}
+
+	if res, err := getStat(ctx, p.Address); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
@@ -470,6 +542,7 @@ func (h *handler) getFolders(c echo.Context) error {
 
 	type params struct {
 		Account domain.Address `param:"account"`
+		Linked  bool           `query:"linked"`
 	}
 
 	p := &params{}
@@ -501,6 +574,23 @@ func (h *handler) getFolders(c echo.Context) error {
 		isAuthed = true
 	}
 
+	if p.Linked {
+		wallets, err := h.au.GetLinkedWallets(ctx, p.Account)
+		if err != nil {
+			return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+		}
+
+		if len(wallets.Wallets) > 0 {
+			folders, err = h.fu.GetFolders(ctx, account.WithOwners(wallets.Addresses()))
+			if err != nil {
+				return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+			}
+		}
+
+		// private folders of linked wallets are visible to any wallet of the group
+		isAuthed = ad != nil && wallets.Contains(ad.(domain.Address))
+	}
+
 	res := []*account.Folder{}
 	if isAuthed {
 		res = folders
@@ -537,22 +627,26 @@ func (h *handler) getFolder(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusBadRequest, "folder not belongs to this account")
 	}
 
-	if folder.IsPrivate {
-		ad := c.Get("address")
-		if ad == nil || !ad.(domain.Address).Equals(p.Account) {
-			return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
-		}
+	if folder.IsPrivate && !canView(c, folder) {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
 	}
 
 	return delivery.MakeJsonResp(c, http.StatusOK, folder)
 }
 
+// canView reports whether the signed in account is the owner or a collaborator of the folder
+func canView(c echo.Context, folder *account.Folder) bool {
+	ad, ok := c.Get("address").(domain.Address)
+	return ok && folder.CanEdit(ad)
+}
+
 func (h *handler) getNFTsInFolder(c echo.Context) error {
 	ctx := c.Get("ctx").(ctx.Ctx)
 
 	type params struct {
-		FolderId string         `param:"folderId"`
-		Account  domain.Address `param:"account"`
+		FolderId    string         `param:"folderId"`
+		Account     domain.Address `param:"account"`
+		IncludeSpam bool           `query:"includeSpam"`
 	}
 
 	p := params{}
@@ -569,19 +663,35 @@ func (h *handler) getNFTsInFolder(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusBadRequest, "folder not belongs to this account")
 	}
 
-	res, err := h.fu.GetNFTsInFolder(ctx, p.FolderId)
+	if folder.IsPrivate && !canView(c, folder) {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	res, err := h.getFolderItems(ctx, p.FolderId, p.IncludeSpam)
 	if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
-	if folder.IsPrivate {
-		ad := c.Get("address")
-		if ad == nil || !ad.(domain.Address).Equals(p.Account) {
-			return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+func (h *handler) getFolderItems(ctx ctx.Ctx, folderId string, includeSpam bool) ([]*account.FolderItem, error) {
+	res, err := h.fu.GetFolderItems(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !includeSpam {
+		nfts := []*account.FolderItem{}
+		for _, nft := range res {
+			if !spam.IsSpam(nft.SpamScore) {
+				nfts = append(nfts, nft)
+			}
 		}
+		res = nfts
 	}
 
-	return delivery.MakeJsonResp(c, http.StatusOK, res)
+	return res, nil
 }
 
 func (h *handler) createFolder(c echo.Context) error {
@@ -716,6 +826,250 @@ func (h *handler) deleteFolder(c echo.Context) error {
 	return delivery.MakeJsonResp(c, http.StatusOK, "")
 }
 
+func folderErrStatus(err error) int {
+	switch {
+	case errors.Is(err, account.ErrInvalidSlug), errors.Is(err, account.ErrInvalidFolder):
+		return http.StatusBadRequest
+	case errors.Is(err, account.ErrBuiltInFolder), errors.Is(err, account.ErrNotFolderEditor):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, account.ErrSlugTaken):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// getOwnedFolder returns the folder if it belongs to account and the signed-in editor is the owner with a valid signature
+func (h *handler) getOwnedFolder(ctx ctx.Ctx, folderId string, owner, editor domain.Address, signature string) (*account.Folder, error) {
+	folder, err := h.fu.GetFolder(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !folder.Owner.Equals(owner) || !folder.Owner.Equals(editor) {
+		return nil, account.ErrNotFolderEditor
+	}
+
+	if err := h.au.ValidateSignature(ctx, folder.Owner, signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", account.ErrNotFolderEditor, err)
+	}
+
+	return folder, nil
+}
+
+func (h *handler) updateGallery(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	editor := c.Get("address").(domain.Address)
+
+	type payload struct {
+		FolderId      string               `param:"folderId"`
+		Account       domain.Address       `param:"account"`
+		Signature     string               `json:"signature"`
+		Description   *string              `json:"description"`
+		Slug          *string              `json:"slug"`
+		CoverLayout   *account.CoverLayout `json:"coverLayout"`
+		Collaborators *[]domain.Address    `json:"collaborators"`
+	}
+
+	p := payload{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if _, err := h.getOwnedFolder(ctx, p.FolderId, p.Account, editor, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	patchable := account.FolderUpdater{
+		Description:   p.Description,
+		Slug:          p.Slug,
+		CoverLayout:   p.CoverLayout,
+		Collaborators: p.Collaborators,
+	}
+
+	if err := h.fu.UpdateGallery(ctx, p.FolderId, &patchable); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
+func (h *handler) reorderFolder(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	editor := c.Get("address").(domain.Address)
+
+	type payload struct {
+		FolderId  string         `param:"folderId"`
+		Account   domain.Address `param:"account"`
+		Signature string         `json:"signature"`
+		Nfts      []nftitem.Id   `json:"nfts"`
+	}
+
+	p := payload{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if _, err := h.getOwnedFolder(ctx, p.FolderId, p.Account, editor, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	if err := h.fu.Reorder(ctx, p.FolderId, p.Nfts); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
+func (h *handler) setFolderCaption(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	editor := c.Get("address").(domain.Address)
+
+	type payload struct {
+		FolderId  string         `param:"folderId"`
+		Account   domain.Address `param:"account"`
+		Signature string         `json:"signature"`
+		Nft       nftitem.Id     `json:"nft"`
+		Caption   string         `json:"caption"`
+	}
+
+	p := payload{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	folder, err := h.fu.GetFolder(ctx, p.FolderId)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	if !folder.Owner.Equals(p.Account) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "folder not belongs to this account")
+	}
+
+	// collaborators sign for themselves
+	if err := h.au.ValidateSignature(ctx, editor, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	if err := h.fu.SetCaption(ctx, p.FolderId, editor, p.Nft, p.Caption); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
+func (h *handler) addNFTsToFolder(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	editor := c.Get("address").(domain.Address)
+
+	type payload struct {
+		FolderId  string         `param:"folderId"`
+		Account   domain.Address `param:"account"`
+		Signature string         `json:"signature"`
+		Nfts      []nftitem.Id   `json:"nfts"`
+	}
+
+	p := payload{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	folder, err := h.fu.GetFolder(ctx, p.FolderId)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	if !folder.Owner.Equals(p.Account) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "folder not belongs to this account")
+	}
+
+	if err := h.au.ValidateSignature(ctx, editor, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	if err := h.fu.AddItems(ctx, p.FolderId, editor, p.Nfts); err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
+func (h *handler) getGallery(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Account     domain.Address `param:"account"`
+		Slug        string         `param:"slug"`
+		IncludeSpam bool           `query:"includeSpam"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	folder, err := h.fu.GetGallery(ctx, p.Account, p.Slug)
+	if err != nil {
+		return delivery.MakeJsonResp(c, folderErrStatus(err), err)
+	}
+
+	if folder.IsPrivate && !canView(c, folder) {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, "private gallery")
+	}
+
+	items, err := h.getFolderItems(ctx, folder.Id, p.IncludeSpam)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	// views of the owner and collaborators aren't counted
+	if !canView(c, folder) {
+		if count, err := h.fu.IncreaseViewCount(ctx, folder.Id); err == nil {
+			folder.ViewCount = count
+		}
+	}
+
+	type response struct {
+		*account.Folder
+		Nfts []*account.FolderItem `json:"nfts"`
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, response{Folder: folder, Nfts: items})
+}
+
+func (h *handler) getSharedFolders(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Account domain.Address `param:"account"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	folders, err := h.fu.GetFolders(ctx, account.WithCollaborator(p.Account))
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	res := []*account.Folder{}
+	for _, f := range folders {
+		if !f.IsPrivate || canView(c, f) {
+			res = append(res, f)
+		}
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
 func (h *handler) getCollectionStatByAccount(c echo.Context) error {
 	ctx := c.Get("ctx").(ctx.Ctx)
 	p := struct {
@@ -745,23 +1099,32 @@ func (h *handler) getCollectionSummary(c echo.Context) error {
 	ctx := c.Get("ctx").(ctx.Ctx)
 
 	p := struct {
-		Account domain.Address `param:"account"`
+		Account     domain.Address `param:"account"`
+		IncludeSpam bool           `query:"includeSpam"`
+		Linked      bool           `query:"linked"`
 	}{}
 
 	if err := c.Bind(&p); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
 	}
 
-	folders, err := h.fu.GetFolders(
-		ctx,
-		account.WithOwner(p.Account.ToLower()),
-		account.WithBuiltIn(true),
-	)
+	owners := []domain.Address{p.Account}
+	getHoldings := h.au.GetAccountCollectionHoldings
+	if p.Linked {
+		wallets, err := h.au.GetLinkedWallets(ctx, p.Account)
+		if err != nil {
+			return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+		}
+		owners = wallets.Addresses()
+		getHoldings = h.au.GetLinkedCollectionHoldings
+	}
+
+	portfolio, err := h.fu.GetPortfolio(ctx, owners)
 	if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
-	userCollections, err := h.au.GetAccountCollectionHoldings(ctx, p.Account.ToLower())
+	userCollections, err := getHoldings(ctx, p.Account.ToLower())
 	if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
@@ -771,51 +1134,204 @@ func (h *handler) getCollectionSummary(c echo.Context) error {
 		addresses = append(addresses, cId.Address)
 	}
 
-	collectionsRes, err := h.collection.FindAll(ctx, collection.WithAddresses(addresses), collection.WithPagination(0, 1))
-	if err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	}
-
-	totalValue := float64(0)
-	nftCount := 0
-	previousValue := float64(0)
-	instantLiquidityValue := float64(0)
-	for _, f := range folders {
-		totalValue += f.TotalValueInUsd
-		nftCount += f.NftCount
-		previousValue += f.TotalValueInUsd / (1 + f.TotalValueMovement)
-		instantLiquidityValue += f.InstantLiquidityInUsd
+	collectionOpts := []collection.FindAllOptions{collection.WithAddresses(addresses), collection.WithPagination(0, 1)}
+	if !p.IncludeSpam {
+		collectionOpts = append(collectionOpts, collection.WithIsSpam(false))
 	}
 
-	totalValueChange := float64(0)
-	if previousValue > 0 {
-		totalValueChange = (totalValue - previousValue) / previousValue
+	collectionsRes, err := h.collection.FindAll(ctx, collectionOpts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
 	instantLiquidityRatio := float64(0)
-	if totalValue > 0 {
-		instantLiquidityRatio = instantLiquidityValue / totalValue
+	if portfolio.TotalValueInUsd > 0 {
+		instantLiquidityRatio = portfolio.InstantLiquidityInUsd / portfolio.TotalValueInUsd
 	}
 
 	res := struct {
 		TotalCollectionValue       float64 `json:"totalCollectionValue"`
+		FloorPriceInUsd            float64 `json:"floorPriceInUsd"`
 		NftCount                   int     `json:"nftCount"`
 		CollectionCount            int     `json:"collectionCount"`
 		TotalCollectionValueChange float64 `json:"totalCollectionValueChange"`
 		InstantLiquidityValue      float64 `json:"instantLiquidityValue"`
 		InstantLiquidityRatio      float64 `json:"instantLiquidityRatio"`
 	}{
-		TotalCollectionValue:       totalValue,
-		NftCount:                   nftCount,
+		TotalCollectionValue:       portfolio.TotalValueInUsd,
+		FloorPriceInUsd:            portfolio.FloorPriceInUsd,
+		NftCount:                   portfolio.NftCount,
 		CollectionCount:            collectionsRes.Count,
-		TotalCollectionValueChange: totalValueChange,
-		InstantLiquidityValue:      instantLiquidityValue,
+		TotalCollectionValueChange: portfolio.TotalValueMovement,
+		InstantLiquidityValue:      portfolio.InstantLiquidityInUsd,
 		InstantLiquidityRatio:      instantLiquidityRatio,
 	}
 
 	return delivery.MakeJsonResp(c, http.StatusOK, res)
 }
 
+// GetPortfolio
+//
+//	@Description	This api returns the value of nfts held by the account at collection floors.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//	@Param			account	path		string	true	"account address"
+//	@Param			linked	query		bool	false	"aggregate the portfolio of all linked wallets"
+//	@Success		200		{object}	account.Portfolio
+//	@Failure		400
+//	@Failure		500
+//	@Router			/account/{account}/portfolio [get]
+func (h *handler) getPortfolio(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Account domain.Address `param:"account"`
+		Linked  bool           `query:"linked"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	owners := []domain.Address{p.Account}
+	if p.Linked {
+		wallets, err := h.au.GetLinkedWallets(ctx, p.Account)
+		if err != nil {
+			return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+		}
+		owners = wallets.Addresses()
+	}
+
+	portfolio, err := h.fu.GetPortfolio(ctx, owners)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, portfolio)
+}
+
+func linkedWalletErrStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, account.ErrCannotLinkSelf), errors.Is(err, account.ErrTooManyLinkedWallets):
+		return http.StatusBadRequest
+	case errors.Is(err, account.ErrInvalidNonce), errors.Is(err, account.ErrInvalidSignature):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, account.ErrWalletNotLinked):
+		return http.StatusNotFound
+	case errors.Is(err, account.ErrWalletAlreadyLinked), errors.Is(err, account.ErrPrimaryIsLinked):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// GetLinkedWallets
+//
+//	@Description	This api returns the primary account and wallets linked to it, the account can be any wallet of the group.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//	@Param			account	path		string	true	"account address"
+//	@Success		200		{object}	account.LinkedWallets
+//	@Failure		500
+//	@Router			/account/{account}/linked-wallets [get]
+func (h *handler) getLinkedWallets(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Account domain.Address `param:"account"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	wallets, err := h.au.GetLinkedWallets(ctx, p.Account)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, wallets)
+}
+
+// LinkWallet
+//
+//	@Description	Link a wallet to the signed in account. The wallet signs the link message formatted with the account address and the nonce from #/account/post_account_nonce,
+//	@Description	chainId is used to validate ERC-1271 signatures of contract wallets.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			body	body	object{address=string,chainId=int,signature=string}	true	"wallet to link"
+//	@Success		200
+//	@Failure		400
+//	@Failure		405
+//	@Failure		409
+//	@Failure		500
+//	@Router			/account/linked-wallets [post]
+func (h *handler) linkWallet(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	type payload struct {
+		Address   domain.Address `json:"address"`
+		ChainId   domain.ChainId `json:"chainId"`
+		Signature string         `json:"signature"`
+	}
+
+	p := payload{ChainId: 1}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.au.LinkWallet(ctx, address, p.Address, p.ChainId, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, linkedWalletErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
+// UnlinkWallet
+//
+//	@Description	Unlink a wallet, requested by either the primary account or the linked wallet itself.
+//	@Tags			account
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			wallet	path	string						true	"linked wallet address"
+//	@Param			body	body	object{signature=string}	true	"signature of the signed in account"
+//	@Success		200
+//	@Failure		404
+//	@Failure		405
+//	@Failure		500
+//	@Router			/account/linked-wallets/{wallet} [delete]
+func (h *handler) unlinkWallet(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	type payload struct {
+		Wallet    domain.Address `param:"wallet"`
+		Signature string         `json:"signature"`
+	}
+
+	p := payload{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.au.ValidateSignature(ctx, address, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	if err := h.au.UnlinkWallet(ctx, address, p.Wallet); err != nil {
+		return delivery.MakeJsonResp(c, linkedWalletErrStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, "")
+}
+
 // userOrderNonce
 //
 //	@Summary		Get next valid nonce for account
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+)
+
+var (
+	folderOwner    = domain.Address("0x0000000000000000000000000000000000000001")
+	folderStranger = domain.Address("0x0000000000000000000000000000000000000002")
+)
+
+type FolderHandlerSuite struct {
+	suite.Suite
+	e      *echo.Echo
+	au     *mAccount.Usecase
+	fu     *mAccount.FolderUseCase
+	folder *account.Folder
+}
+
+func TestFolderHandlerSuite(t *testing.T) {
+	suite.Run(t, new(FolderHandlerSuite))
+}
+
+func (s *FolderHandlerSuite) SetupTest() {
+	s.au = &mAccount.Usecase{}
+	s.fu = &mAccount.FolderUseCase{}
+	s.folder = &account.Folder{Id: "f", Owner: folderOwner}
+
+	h := &handler{au: s.au, fu: s.fu}
+
+	s.e = echo.New()
+	s.e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("ctx", ctx.Background())
+			c.Set("address", domain.Address(c.Request().Header.Get("X-Address")))
+			return next(c)
+		}
+	})
+	s.e.PUT("/account/:account/folder/:folderId/gallery", h.updateGallery)
+	s.e.PUT("/account/:account/folder/:folderId/order", h.reorderFolder)
+}
+
+func (s *FolderHandlerSuite) TearDownTest() {
+	s.au.AssertExpectations(s.T())
+	s.fu.AssertExpectations(s.T())
+}
+
+func (s *FolderHandlerSuite) do(path string, signer domain.Address, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Address", string(signer))
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *FolderHandlerSuite) mockFolder() {
+	s.fu.On("GetFolder", mock.Anything, "f").Return(s.folder, nil).Once()
+}
+
+func (s *FolderHandlerSuite) TestUpdateGallery() {
+	path := "/account/" + string(folderOwner) + "/folder/f/gallery"
+	body := `{"signature":"sig","description":"hi"}`
+
+	// signed in as someone else
+	s.mockFolder()
+	rec := s.do(path, folderStranger, body)
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	// owner path with someone else's folder
+	s.mockFolder()
+	rec = s.do("/account/"+string(folderStranger)+"/folder/f/gallery", folderStranger, body)
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	s.mockFolder()
+	s.au.On("ValidateSignature", mock.Anything, folderOwner, "sig").Return(account.ErrInvalidSignature).Once()
+	rec = s.do(path, folderOwner, body)
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	s.mockFolder()
+	s.au.On("ValidateSignature", mock.Anything, folderOwner, "sig").Return(nil).Once()
+	s.fu.On("UpdateGallery", mock.Anything, "f", mock.MatchedBy(func(updater *account.FolderUpdater) bool {
+		return *updater.Description == "hi"
+	})).Return(nil).Once()
+	rec = s.do(path, folderOwner, body)
+	s.Equal(http.StatusOK, rec.Code)
+}
+
+func (s *FolderHandlerSuite) TestReorderFolder() {
+	path := "/account/" + string(folderOwner) + "/folder/f/order"
+	body := `{"signature":"sig","nfts":[]}`
+
+	s.fu.On("GetFolder", mock.Anything, "f").Return(nil, domain.ErrNotFound).Once()
+	rec := s.do(path, folderOwner, body)
+	s.Equal(http.StatusNotFound, rec.Code)
+
+	s.mockFolder()
+	rec = s.do(path, folderStranger, body)
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	s.mockFolder()
+	s.au.On("ValidateSignature", mock.Anything, folderOwner, "sig").Return(account.ErrInvalidNonce).Once()
+	rec = s.do(path, folderOwner, body)
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	s.mockFolder()
+	s.au.On("ValidateSignature", mock.Anything, folderOwner, "sig").Return(nil).Once()
+	s.fu.On("Reorder", mock.Anything, "f", mock.Anything).Return(nil).Once()
+	rec = s.do(path, folderOwner, body)
+	s.Equal(http.StatusOK, rec.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/service/keyset"
 	"github.com/x-xyz/goapi/service/query"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -24,6 +25,11 @@ func makeFindQuery(optFns ...account.FindActivityHistoryOptions) (bson.M, error)
 			bson.M{"account": *opts.Account},
 			bson.M{"to": opts.Account},
 		}
+	} else if len(opts.Accounts) > 0 {
+		qry["$or"] = bson.A{
+			bson.M{"account": bson.M{"$in": opts.Accounts}},
+			bson.M{"to": bson.M{"$in": opts.Accounts}},
+		}
 	}
 
 	if opts.ChainId != nil {
@@ -52,6 +58,38 @@ func makeFindQuery(optFns ...account.FindActivityHistoryOptions) (bson.M, error)
 		qry["source"] = *opts.Source
 	}
 
+	if opts.Feed != nil {
+		feed := bson.A{}
+		if len(opts.Feed.Accounts) > 0 {
+			feed = append(feed,
+				bson.M{"account": bson.M{"$in": opts.Feed.Accounts}},
+				bson.M{"to": bson.M{"$in": opts.Feed.Accounts}},
+			)
+		}
+		// collections are grouped by chain, a branch per collection makes the query too large to plan
+		chainIds := []domain.ChainId{}
+		byChain := map[domain.ChainId][]domain.Address{}
+		for _, c := range opts.Feed.Collections {
+			if _, ok := byChain[c.ChainId]; !ok {
+				chainIds = append(chainIds, c.ChainId)
+			}
+			byChain[c.ChainId] = append(byChain[c.ChainId], c.Address)
+		}
+		for _, chainId := range chainIds {
+			feed = append(feed, bson.M{"chainId": chainId, "contractAddress": bson.M{"$in": byChain[chainId]}})
+		}
+		if len(feed) == 0 {
+			// nothing is followed
+			feed = append(feed, bson.M{"_id": bson.M{"$exists": false}})
+		}
+		if or, ok := qry["$or"]; ok {
+			delete(qry, "$or")
+			qry["$and"] = bson.A{bson.M{"$or": or}, bson.M{"$or": feed}}
+		} else {
+			qry["$or"] = feed
+		}
+	}
+
 	return qry, nil
 }
 
@@ -98,9 +136,26 @@ func (r *activityHistoryRepo) FindActivities(c ctx.Ctx, optFns ...account.FindAc
 		limit = *opts.Limit
 	}
 
+	sorts := account.ActivityHistorySorts()
+
+	if opts.Cursor != nil {
+		offset = 0
+		if *opts.Cursor != "" {
+			after, err := keyset.Query(*opts.Cursor, sorts)
+			if err != nil {
+				c.WithFields(log.Fields{
+					"err":    err,
+					"cursor": *opts.Cursor,
+				}).Warn("keyset.Query failed")
+				return nil, err
+			}
+			qry = bson.M{"$and": bson.A{qry, after}}
+		}
+	}
+
 	res := []account.ActivityHistory{}
 
-	err = r.q.Search(c, domain.TableActivityHistories, offset, limit, "-time", qry, &res)
+	err = r.q.SearchNSorts(c, domain.TableActivityHistories, offset, limit, sorts, qry, &res)
 
 	if err == query.ErrNotFound {
 		return nil, domain.ErrNotFound
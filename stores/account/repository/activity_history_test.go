@@ -10,6 +10,7 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/service/query"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type activityHistorySuite struct {
@@ -88,6 +89,12 @@ func (s *activityHistorySuite) TestFind() {
 
 		output, err := s.im.FindActivities(ctx, c.query...)
 		s.Nil(err)
+
+		// don't care objectId
+		for i := range output {
+			output[i].ObjectId = primitive.NilObjectID
+		}
+
 		s.ElementsMatch(c.want, output)
 	}
 }
@@ -122,6 +129,8 @@ func (s *activityHistorySuite) TestUpsert() {
 		"sourceEventId": "123",
 	}, &result)
 	s.Nil(err)
+	// don't care objectId
+	result.ObjectId = primitive.NilObjectID
 	s.Equal(activity, result)
 
 	err = s.im.UpsertBySourceEventId(ctx, activity.Source, activity.SourceEventId, activity.Type, &activity)
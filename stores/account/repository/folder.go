@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/database/mongoclient"
 	"github.com/x-xyz/goapi/base/log"
@@ -8,19 +10,36 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/service/query"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type folderReopImpl struct {
 	query query.Mongo
 }
 
+// EnsureFolderSlugIndex creates the unique index of (owner, slug) which backs slug uniqueness of galleries,
+// folders without slug are excluded
+func EnsureFolderSlugIndex(ctx ctx.Ctx, q query.Mongo) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "owner", Value: 1}, {Key: "slug", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"slug": bson.M{"$gt": ""}}),
+	}
+	return q.CreateIndex(ctx, domain.TableFolders, index)
+}
+
+// NewFolderRepo requires the index created by EnsureFolderSlugIndex, Insert and Update return account.ErrSlugTaken on duplicated slugs
 func NewFolderRepo(query query.Mongo) account.FolderRepo {
 	return &folderReopImpl{query}
 }
 
 func (im *folderReopImpl) Insert(ctx ctx.Ctx, f *account.Folder) error {
 	f.Owner = f.Owner.ToLower()
-	if err := im.query.Insert(ctx, domain.TableFolders, f); err != nil {
+	if err := im.query.Insert(ctx, domain.TableFolders, f); errors.Is(err, query.ErrDuplicateKey) {
+		return account.ErrSlugTaken
+	} else if err != nil {
 		ctx.WithFields(log.Fields{
 			"folder": *f,
 			"err":    err,
@@ -70,6 +89,10 @@ func (im *folderReopImpl) GetFolders(ctx ctx.Ctx, opts ...account.GetFoldersOpti
 		q["owner"] = opt.Owner.ToLowerStr()
 	}
 
+	if len(opt.Owners) > 0 {
+		q["owner"] = bson.M{"$in": opt.Owners}
+	}
+
 	if opt.IsBuiltIn != nil {
 		q["isBuiltIn"] = *opt.IsBuiltIn
 	}
@@ -78,6 +101,14 @@ func (im *folderReopImpl) GetFolders(ctx ctx.Ctx, opts ...account.GetFoldersOpti
 		q["isPrivate"] = *opt.IsPrivate
 	}
 
+	if opt.Slug != nil {
+		q["slug"] = *opt.Slug
+	}
+
+	if opt.Collaborator != nil {
+		q["collaborators"] = *opt.Collaborator
+	}
+
 	if len(q) == 0 {
 		q["_id"] = bson.M{"$exists": true}
 	}
@@ -103,7 +134,9 @@ func (im *folderReopImpl) Update(ctx ctx.Ctx, Id string, patchable *account.Fold
 		})
 		return err
 	}
-	if err := im.query.Patch(ctx, domain.TableFolders, q, updateBson); err != nil {
+	if err := im.query.Patch(ctx, domain.TableFolders, q, updateBson); errors.Is(err, query.ErrDuplicateKey) {
+		return account.ErrSlugTaken
+	} else if err != nil {
 		ctx.WithFields(log.Fields{
 			"id":  Id,
 			"err": err,
@@ -125,3 +158,15 @@ func (im *folderReopImpl) Delete(ctx ctx.Ctx, Id string) error {
 
 	return nil
 }
+
+func (im *folderReopImpl) IncreaseViewCount(ctx ctx.Ctx, Id string, count int) (int64, error) {
+	res := account.Folder{}
+	if err := im.query.Increment(ctx, domain.TableFolders, bson.M{"id": Id}, &res, "viewCount", count); err != nil {
+		ctx.WithFields(log.Fields{
+			"id":  Id,
+			"err": err,
+		}).Error("increment folder view count failed")
+		return 0, err
+	}
+	return res.ViewCount, nil
+}
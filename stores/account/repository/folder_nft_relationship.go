@@ -45,6 +45,10 @@ func makeRelationFindQuery(opts account.RelationsQueryOptions) bson.M {
 		query["tokenId"] = opts.NftitemId.TokenId
 	}
 
+	if opts.AddedBys != nil {
+		query["addedBy"] = bson.M{"$in": *opts.AddedBys}
+	}
+
 	return query
 }
 
@@ -150,6 +154,118 @@ func (im *folderNftRelationshipImpl) AddNftitemsToFolder(ctx ctx.Ctx, items []nf
 	return nil
 }
 
+func (im *folderNftRelationshipImpl) ReplaceRelations(c ctx.Ctx, folderId string, relations []*account.FolderNftRelationship) error {
+	return im.query.RunWithTransaction(c, func(c ctx.Ctx) error {
+		if err := im.DeleteAllRelationsByFolderID(c, folderId); err != nil {
+			return err
+		}
+		return im.upsertRelations(c, folderId, relations, 0)
+	})
+}
+
+func (im *folderNftRelationshipImpl) AppendRelations(ctx ctx.Ctx, folderId string, relations []*account.FolderNftRelationship) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	lastRelation := []account.FolderNftRelationship{}
+	if err := im.query.Search(ctx, domain.TableFolderNftRelationships, 0, 1, "-index", bson.M{"folderId": folderId}, &lastRelation); err != nil {
+		ctx.WithFields(log.Fields{
+			"folderId": folderId,
+			"err":      err,
+		}).Error("failed to Search")
+		return err
+	}
+	firstIndex := 0
+	if len(lastRelation) > 0 {
+		firstIndex = lastRelation[0].Index + 1
+	}
+
+	return im.upsertRelations(ctx, folderId, relations, firstIndex)
+}
+
+// upsertRelations upserts relations of the folder with indexes starting from firstIndex
+func (im *folderNftRelationshipImpl) upsertRelations(ctx ctx.Ctx, folderId string, relations []*account.FolderNftRelationship, firstIndex int) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	ops := []query.UpsertOp{}
+	for i, r := range relations {
+		r.FolderId = folderId
+		r.Index = firstIndex + i
+		ops = append(ops, query.UpsertOp{
+			Selector: bson.M{
+				"chainId":         r.ChainId,
+				"contractAddress": r.ContractAddress,
+				"tokenId":         r.TokenId,
+				"folderId":        folderId,
+			},
+			Updater: r,
+		})
+	}
+
+	if _, _, err := im.query.BulkUpsert(ctx, domain.TableFolderNftRelationships, ops); err != nil {
+		ctx.WithFields(log.Fields{
+			"folderId": folderId,
+			"err":      err,
+		}).Error("failed to BulkUpsert")
+		return err
+	}
+
+	return nil
+}
+
+func (im *folderNftRelationshipImpl) UpdateIndexes(ctx ctx.Ctx, folderId string, items []nftitem.Id) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ops := []query.UpsertOp{}
+	for i, it := range items {
+		ops = append(ops, query.UpsertOp{
+			Selector: bson.M{
+				"chainId":         it.ChainId,
+				"contractAddress": it.ContractAddress,
+				"tokenId":         it.TokenId,
+				"folderId":        folderId,
+			},
+			Updater: bson.M{"index": i},
+		})
+	}
+
+	if _, _, err := im.query.BulkPatch(ctx, domain.TableFolderNftRelationships, ops); err != nil {
+		ctx.WithFields(log.Fields{
+			"folderId": folderId,
+			"err":      err,
+		}).Error("failed to BulkPatch")
+		return err
+	}
+
+	return nil
+}
+
+func (im *folderNftRelationshipImpl) UpdateCaption(ctx ctx.Ctx, folderId string, item nftitem.Id, caption string) error {
+	q := bson.M{
+		"chainId":         item.ChainId,
+		"contractAddress": item.ContractAddress,
+		"tokenId":         item.TokenId,
+		"folderId":        folderId,
+	}
+	if err := im.query.Patch(ctx, domain.TableFolderNftRelationships, q, bson.M{"caption": caption}); err == query.ErrNotFound {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"folderId": folderId,
+			"item":     item,
+			"err":      err,
+		}).Error("failed to Patch")
+		return err
+	}
+
+	return nil
+}
+
 func (im *folderNftRelationshipImpl) MoveNftitems(ctx ctx.Ctx, items []nftitem.Id, fromFolderId, toFolderId string) error {
 	// return if no item need to be moved
 	if len(items) == 0 {
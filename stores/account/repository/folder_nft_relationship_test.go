@@ -530,3 +530,82 @@ func (s *relationSuite) TestMoveNftitems() {
 	s.Nil(err)
 	s.Equal(3, len(folderRelations2))
 }
+
+func (s *relationSuite) TestAppendRelations() {
+	ctx := ctx.Background()
+
+	mockFolderId := "mockFolderId"
+	existing := account.FolderNftRelationship{
+		FolderId:        mockFolderId,
+		ChainId:         1,
+		ContractAddress: "0x123",
+		TokenId:         "1",
+		Index:           3,
+		Caption:         "kept",
+	}
+	err := s.query.Insert(ctx, domain.TableFolderNftRelationships, &existing)
+	s.Nil(err)
+
+	err = s.im.AppendRelations(ctx, mockFolderId, []*account.FolderNftRelationship{
+		{ChainId: 1, ContractAddress: "0x123", TokenId: "2", AddedBy: "0x456"},
+		{ChainId: 1, ContractAddress: "0x123", TokenId: "3"},
+	})
+	s.Nil(err)
+
+	relations, err := s.im.GetAllRelations(ctx, account.WithFolderId(mockFolderId))
+	s.Nil(err)
+	s.Equal(3, len(relations))
+	s.Equal(existing, *relations[0])
+	s.Equal(domain.TokenId("2"), relations[1].TokenId)
+	s.Equal(4, relations[1].Index)
+	s.Equal(domain.Address("0x456"), relations[1].AddedBy)
+	s.Equal(domain.TokenId("3"), relations[2].TokenId)
+	s.Equal(5, relations[2].Index)
+}
+
+func (s *relationSuite) TestUpdateIndexesAndCaption() {
+	ctx := ctx.Background()
+
+	mockFolderId := "mockFolderId"
+	mockRelations := []account.FolderNftRelationship{
+		{
+			FolderId:        mockFolderId,
+			ChainId:         1,
+			ContractAddress: "0x123",
+			TokenId:         "1",
+			Index:           0,
+			Caption:         "kept",
+		},
+		{
+			FolderId:        mockFolderId,
+			ChainId:         1,
+			ContractAddress: "0x123",
+			TokenId:         "2",
+			Index:           1,
+		},
+	}
+	for _, r := range mockRelations {
+		err := s.query.Insert(ctx, domain.TableFolderNftRelationships, &r)
+		s.Nil(err)
+	}
+
+	err := s.im.UpdateIndexes(ctx, mockFolderId, []nftitem.Id{
+		*mockRelations[1].ToNftItemId(),
+		*mockRelations[0].ToNftItemId(),
+	})
+	s.Nil(err)
+
+	err = s.im.UpdateCaption(ctx, mockFolderId, *mockRelations[1].ToNftItemId(), "new")
+	s.Nil(err)
+
+	err = s.im.UpdateCaption(ctx, mockFolderId, nftitem.Id{ChainId: 1, ContractAddress: "0x123", TokenId: "9"}, "new")
+	s.Equal(domain.ErrNotFound, err)
+
+	relations, err := s.im.GetAllRelations(ctx, account.WithFolderId(mockFolderId))
+	s.Nil(err)
+	s.Equal(2, len(relations))
+	s.Equal(domain.TokenId("2"), relations[0].TokenId)
+	s.Equal("new", relations[0].Caption)
+	s.Equal(domain.TokenId("1"), relations[1].TokenId)
+	s.Equal("kept", relations[1].Caption)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+type linkedWalletRepo struct {
+	query query.Mongo
+}
+
+// NewLinkedWalletRepo keys links by wallet address so a wallet can't be linked twice
+func NewLinkedWalletRepo(query query.Mongo) account.LinkedWalletRepo {
+	return &linkedWalletRepo{query}
+}
+
+func (im *linkedWalletRepo) Get(c ctx.Ctx, address domain.Address) (*account.LinkedWallet, error) {
+	res := &account.LinkedWallet{}
+	err := im.query.FindOne(c, domain.TableLinkedWallets, bson.M{"_id": address.ToLower()}, res)
+	if errors.Is(err, query.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("failed to query.FindOne")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *linkedWalletRepo) FindByPrimary(c ctx.Ctx, primary domain.Address) ([]*account.LinkedWallet, error) {
+	res := []*account.LinkedWallet{}
+	err := im.query.Search(c, domain.TableLinkedWallets, 0, 0, "linkedAt", bson.M{"primary": primary.ToLower()}, &res)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"primary": primary,
+		}).Error("failed to query.Search")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *linkedWalletRepo) Insert(c ctx.Ctx, wallet *account.LinkedWallet) error {
+	wallet.Address = wallet.Address.ToLower()
+	wallet.Primary = wallet.Primary.ToLower()
+
+	err := im.query.Insert(c, domain.TableLinkedWallets, wallet)
+	if errors.Is(err, query.ErrDuplicateKey) {
+		return account.ErrWalletAlreadyLinked
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"wallet": wallet,
+		}).Error("failed to query.Insert")
+		return err
+	}
+	return nil
+}
+
+func (im *linkedWalletRepo) Remove(c ctx.Ctx, address domain.Address) error {
+	err := im.query.Remove(c, domain.TableLinkedWallets, bson.M{"_id": address.ToLower()})
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("failed to query.Remove")
+		return err
+	}
+	return nil
+}
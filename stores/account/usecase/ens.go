@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+)
+
+// ensProfiles returns ens profiles of addresses in lower case by batch requests, failure is ignored as ens is only
+// an enrichment
+func (im *impl) ensProfiles(c ctx.Ctx, addresses []domain.Address) map[domain.Address]*domain.EnsProfile {
+	res := map[domain.Address]*domain.EnsProfile{}
+	if im.ens == nil || len(addresses) == 0 {
+		return res
+	}
+
+	names, err := im.ens.ReverseResolveBatch(c, addresses)
+	if err != nil {
+		c.WithField("err", err).Warn("ens.ReverseResolveBatch failed")
+		return res
+	}
+	if len(names) == 0 {
+		return res
+	}
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, name)
+	}
+	profiles, err := im.ens.GetProfiles(c, values)
+	if err != nil {
+		c.WithField("err", err).Warn("ens.GetProfiles failed")
+		profiles = map[string]*domain.EnsProfile{}
+	}
+
+	for address, name := range names {
+		if profile, ok := profiles[name]; ok {
+			res[address] = profile
+		} else {
+			res[address] = &domain.EnsProfile{Name: name}
+		}
+	}
+	return res
+}
+
+func (im *impl) enrichInfos(c ctx.Ctx, infos []*account.Info) {
+	addresses := []domain.Address{}
+	for _, info := range infos {
+		if info != nil {
+			addresses = append(addresses, info.Address)
+		}
+	}
+
+	profiles := im.ensProfiles(c, addresses)
+	for _, info := range infos {
+		if info != nil {
+			info.Ens = profiles[info.Address.ToLower()]
+		}
+	}
+}
+
+func (im *impl) enrichSimpleAccounts(c ctx.Ctx, accounts []*account.SimpleAccount) {
+	addresses := []domain.Address{}
+	for _, a := range accounts {
+		addresses = append(addresses, a.Address)
+	}
+
+	profiles := im.ensProfiles(c, addresses)
+	for _, a := range accounts {
+		a.Ens = profiles[a.Address.ToLower()]
+	}
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/service/keyset"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+	// feeds are merged on read from at most maxFeedSources followed accounts and liked collections
+	maxFeedSources = 1000
+	// maxFeedRounds bounds the pages read to fill a feed page when activities are hidden
+	maxFeedRounds = 5
+)
+
+func (im *impl) GetFeed(c ctx.Ctx, address domain.Address, params account.FeedParams) (*account.ActivityResult, error) {
+	if params.Limit <= 0 {
+		params.Limit = defaultFeedLimit
+	} else if params.Limit > maxFeedLimit {
+		params.Limit = maxFeedLimit
+	}
+	address = address.ToLower()
+
+	types := []string{}
+	for _, t := range params.Types {
+		types = append(types, string(t))
+	}
+	key := keys.RedisKey(address.ToLowerStr(), strings.Join(types, ","), params.Cursor, fmt.Sprint(params.Limit))
+
+	res := &account.ActivityResult{}
+	if err := im.feedCache.GetByFunc(c, key, res, func() (interface{}, error) {
+		return im.getFeed(c, address, params)
+	}); err != nil {
+		c.WithFields(log.Fields{
+			"address": address,
+			"params":  params,
+			"err":     err,
+		}).Error("feedCache.GetByFunc failed")
+		return nil, err
+	}
+	return res, nil
+}
+
+// getFeed pages activities by cursor only, Count of the result is not computed
+func (im *impl) getFeed(c ctx.Ctx, address domain.Address, params account.FeedParams) (*account.ActivityResult, error) {
+	types := feedTypes(params.Types)
+	if len(types) == 0 {
+		return &account.ActivityResult{}, nil
+	}
+
+	followings, err := im.follow.GetFollowings(c, address)
+	if err != nil {
+		c.WithField("err", err).Error("follow.GetFollowings failed")
+		return nil, err
+	}
+	if len(followings) > maxFeedSources {
+		followings = followings[:maxFeedSources]
+	}
+
+	collections := []account.CollectionId{}
+	if im.collectionLike != nil {
+		likeds, err := im.collectionLike.GetLikeds(c, address)
+		if err != nil {
+			c.WithField("err", err).Error("collectionLike.GetLikeds failed")
+			return nil, err
+		}
+		for _, l := range likeds {
+			collections = append(collections, account.CollectionId{ChainId: l.ChainId, Address: l.ContractAddress})
+		}
+		if len(collections) > maxFeedSources {
+			collections = collections[:maxFeedSources]
+		}
+	}
+
+	if len(followings) == 0 && len(collections) == 0 {
+		return &account.ActivityResult{}, nil
+	}
+
+	visible, nextCursor, err := im.findVisibleFeed(c, address, params,
+		account.ActivityHistoryWithFeed(followings, collections),
+		account.ActivityHistoryWithTypes(types...),
+		account.ActivityHistoryWithSource(account.SourceX),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &account.ActivityResult{Activities: im.toActivities(c, visible), NextCursor: nextCursor}, nil
+}
+
+// findVisibleFeed pages activities from params.Cursor until params.Limit visible activities are found or
+// maxFeedRounds pages are read, hidden activities are dropped after the query
+func (im *impl) findVisibleFeed(c ctx.Ctx, viewer domain.Address, params account.FeedParams, feedOpts ...account.FindActivityHistoryOptions) ([]account.ActivityHistory, string, error) {
+	visible := []account.ActivityHistory{}
+	hiddenByAccount := map[domain.Address]map[nftitem.Id]struct{}{}
+	cursor := params.Cursor
+	for round := 1; ; round++ {
+		optFns := append(append([]account.FindActivityHistoryOptions{}, feedOpts...),
+			account.ActivityHistoryWithPagination(0, params.Limit),
+			account.ActivityHistoryWithCursor(cursor),
+		)
+		activities, err := im.activityRepo.FindActivities(c, optFns...)
+		if err != nil {
+			c.WithField("err", err).Error("activityRepo.FindActivities failed")
+			return nil, "", err
+		}
+
+		filtered, err := im.filterHiddenActivities(c, viewer, activities, hiddenByAccount)
+		if err != nil {
+			return nil, "", err
+		}
+		if need := params.Limit - len(visible); len(filtered) >= need {
+			visible = append(visible, filtered[:need]...)
+			// the page ends at its last activity, hidden activities after it are skipped by the next page
+			nextCursor, err := keyset.Encode(account.ActivityHistorySorts(), visible[len(visible)-1])
+			if err != nil {
+				c.WithField("err", err).Error("keyset.Encode failed")
+				return nil, "", err
+			}
+			return visible, nextCursor, nil
+		}
+		visible = append(visible, filtered...)
+
+		// cursor points to the last found activity, including the hidden ones
+		if cursor, err = account.NextActivityHistoryCursor(activities, optFns...); err != nil {
+			c.WithField("err", err).Error("account.NextActivityHistoryCursor failed")
+			return nil, "", err
+		}
+		if cursor == "" || round >= maxFeedRounds {
+			return visible, cursor, nil
+		}
+	}
+}
+
+// feedTypes returns the requested types shown on accounts, or all of them if none is requested
+func feedTypes(requested []account.ActivityHistoryType) []account.ActivityHistoryType {
+	if len(requested) == 0 {
+		return accountActivityTypes
+	}
+	types := []account.ActivityHistoryType{}
+	for _, t := range requested {
+		for _, allowed := range accountActivityTypes {
+			if t == allowed {
+				types = append(types, t)
+				break
+			}
+		}
+	}
+	return types
+}
+
+// filterHiddenActivities drops activities on tokens in private folders of the viewer or of the accounts involved,
+// hidden tokens of accounts are kept in hiddenByAccount for the following pages
+func (im *impl) filterHiddenActivities(c ctx.Ctx, viewer domain.Address, activities []account.ActivityHistory, hiddenByAccount map[domain.Address]map[nftitem.Id]struct{}) ([]account.ActivityHistory, error) {
+	if im.folder == nil || im.folderRelation == nil {
+		return activities, nil
+	}
+
+	isHidden := func(a domain.Address, id nftitem.Id) (bool, error) {
+		a = a.ToLower()
+		if a.IsEmpty() || a == domain.EmptyAddress {
+			return false, nil
+		}
+		hidden, ok := hiddenByAccount[a]
+		if !ok {
+			var err error
+			if hidden, err = im.getHiddenTokens(c, a); err != nil {
+				return false, err
+			}
+			hiddenByAccount[a] = hidden
+		}
+		_, ok = hidden[id]
+		return ok, nil
+	}
+
+	res := []account.ActivityHistory{}
+	for _, act := range activities {
+		id := nftitem.Id{ChainId: act.ChainId, ContractAddress: act.ContractAddress.ToLower(), TokenId: act.TokenId}
+		hidden := false
+		for _, a := range []domain.Address{viewer, act.Account, act.To} {
+			ok, err := isHidden(a, id)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				hidden = true
+				break
+			}
+		}
+		if !hidden {
+			res = append(res, act)
+		}
+	}
+	return res, nil
+}
+
+func (im *impl) getHiddenTokens(c ctx.Ctx, owner domain.Address) (map[nftitem.Id]struct{}, error) {
+	folders, err := im.folder.GetFolders(c, account.WithOwner(owner), account.WithPrivate(true))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"owner": owner,
+			"err":   err,
+		}).Error("folder.GetFolders failed")
+		return nil, err
+	}
+
+	hidden := map[nftitem.Id]struct{}{}
+	if len(folders) == 0 {
+		return hidden, nil
+	}
+	folderIds := []string{}
+	for _, f := range folders {
+		folderIds = append(folderIds, f.Id)
+	}
+	relations, err := im.folderRelation.GetAllRelations(c, account.WithFolderIds(folderIds))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"owner": owner,
+			"err":   err,
+		}).Error("folderRelation.GetAllRelations failed")
+		return nil, err
+	}
+	for _, r := range relations {
+		id := *r.ToNftItemId()
+		id.ContractAddress = id.ContractAddress.ToLower()
+		hidden[id] = struct{}{}
+	}
+	return hidden, nil
+}
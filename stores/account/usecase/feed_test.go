@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ownedBy matches the owner option of GetFolders
+func ownedBy(owner domain.Address) interface{} {
+	return mock.MatchedBy(func(opt account.GetFoldersOptionsFunc) bool {
+		o, err := account.ParseGetFoldersOptionFunc(opt)
+		return err == nil && o.Owner != nil && *o.Owner == owner
+	})
+}
+
+func TestFeedTypes(t *testing.T) {
+	require.Equal(t, accountActivityTypes, feedTypes(nil))
+	require.Equal(t,
+		[]account.ActivityHistoryType{account.ActivityHistoryTypeSold},
+		feedTypes([]account.ActivityHistoryType{account.ActivityHistoryTypeSold, "unknown"}),
+	)
+	require.Empty(t, feedTypes([]account.ActivityHistoryType{"unknown"}))
+}
+
+func TestFilterHiddenActivities(t *testing.T) {
+	viewer := domain.Address("0x0000000000000000000000000000000000000001")
+	alice := domain.Address("0x0000000000000000000000000000000000000002")
+	bob := domain.Address("0x0000000000000000000000000000000000000003")
+	contract := domain.Address("0x00000000000000000000000000000000000000aa")
+
+	folderUC := &mAccount.FolderUseCase{}
+	// folders of each account are read once
+	for _, a := range []domain.Address{viewer, alice} {
+		folderUC.On("GetFolders", mock.Anything, ownedBy(a), mock.AnythingOfType("account.GetFoldersOptionsFunc")).Return(nil, nil).Once()
+	}
+	folderUC.On("GetFolders", mock.Anything, ownedBy(bob), mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{{Id: "bob-private", Owner: bob, IsPrivate: true}}, nil).Once()
+	folderRelation := &mAccount.FolderNftRelationshipRepo{}
+	folderRelation.On("GetAllRelations", mock.Anything, mock.MatchedBy(func(opt account.RelationsQueryOptionsFunc) bool {
+		o, err := account.ParseRelationsQueryOptionFunc(opt)
+		return err == nil && o.FolderIds != nil && len(*o.FolderIds) == 1 && (*o.FolderIds)[0] == "bob-private"
+	})).Return([]*account.FolderNftRelationship{
+		{FolderId: "bob-private", ChainId: 1, ContractAddress: "0x00000000000000000000000000000000000000AA", TokenId: "2"},
+	}, nil).Once()
+	im := &impl{
+		folder:         folderUC,
+		folderRelation: folderRelation,
+	}
+
+	activities := []account.ActivityHistory{
+		{ChainId: 1, ContractAddress: contract, TokenId: "1", Account: alice},
+		{ChainId: 1, ContractAddress: contract, TokenId: "2", Account: alice},
+		{ChainId: 1, ContractAddress: contract, TokenId: "2", Account: alice, To: bob},
+		{ChainId: 1, ContractAddress: contract, TokenId: "1", Account: alice, To: bob},
+	}
+
+	res, err := im.filterHiddenActivities(ctx.Background(), viewer, activities, map[domain.Address]map[nftitem.Id]struct{}{})
+	require.NoError(t, err)
+	require.Equal(t, []account.ActivityHistory{activities[0], activities[1], activities[3]}, res)
+	folderUC.AssertExpectations(t)
+	folderRelation.AssertExpectations(t)
+}
+
+func TestFindVisibleFeed(t *testing.T) {
+	viewer := domain.Address("0x0000000000000000000000000000000000000001")
+	alice := domain.Address("0x0000000000000000000000000000000000000002")
+	contract := domain.Address("0x00000000000000000000000000000000000000aa")
+	now := time.Now().Truncate(time.Second)
+
+	activityOf := func(tokenId domain.TokenId, age time.Duration) account.ActivityHistory {
+		return account.ActivityHistory{ObjectId: primitive.NewObjectID(), ChainId: 1, ContractAddress: contract, TokenId: tokenId, Account: alice, Time: now.Add(-age)}
+	}
+	// token 2 is in a private folder of alice
+	activities := []account.ActivityHistory{
+		activityOf("2", time.Minute),
+		activityOf("1", 2*time.Minute),
+		activityOf("2", 3*time.Minute),
+		activityOf("3", 4*time.Minute),
+		activityOf("4", 5*time.Minute),
+	}
+
+	cursors := []string{}
+	activityRepo := &mAccount.ActivityHistoryRepo{}
+	for _, page := range [][]account.ActivityHistory{activities[0:2], activities[2:4], activities[4:]} {
+		activityRepo.On("FindActivities", mock.Anything,
+			mock.AnythingOfType("account.FindActivityHistoryOptions"),
+			mock.AnythingOfType("account.FindActivityHistoryOptions")).
+			Return(page, nil).Once().Run(func(args mock.Arguments) {
+			opts, err := account.GetFindActivityHistoryOptions(args.Get(1).(account.FindActivityHistoryOptions), args.Get(2).(account.FindActivityHistoryOptions))
+			require.NoError(t, err)
+			cursors = append(cursors, *opts.Cursor)
+		})
+	}
+
+	// folders are read once a request however many pages are read
+	folderUC := &mAccount.FolderUseCase{}
+	folderUC.On("GetFolders", mock.Anything, ownedBy(viewer), mock.AnythingOfType("account.GetFoldersOptionsFunc")).Return(nil, nil).Twice()
+	folderUC.On("GetFolders", mock.Anything, ownedBy(alice), mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{{Id: "alice-private", Owner: alice, IsPrivate: true}}, nil).Twice()
+	folderRelation := &mAccount.FolderNftRelationshipRepo{}
+	folderRelation.On("GetAllRelations", mock.Anything, mock.AnythingOfType("account.RelationsQueryOptionsFunc")).Return([]*account.FolderNftRelationship{
+		{FolderId: "alice-private", ChainId: 1, ContractAddress: contract, TokenId: "2"},
+	}, nil).Twice()
+
+	im := &impl{
+		activityRepo:   activityRepo,
+		folder:         folderUC,
+		folderRelation: folderRelation,
+	}
+
+	// the first page of 2 has a hidden activity, so the next page is read to fill it
+	visible, next, err := im.findVisibleFeed(ctx.Background(), viewer, account.FeedParams{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []account.ActivityHistory{activities[1], activities[3]}, visible)
+	require.Len(t, cursors, 2)
+	require.Equal(t, "", cursors[0])
+	require.NotEmpty(t, cursors[1])
+
+	// the next page starts after the last shown activity
+	visible, next, err = im.findVisibleFeed(ctx.Background(), viewer, account.FeedParams{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	require.Len(t, cursors, 3)
+	require.NotEqual(t, cursors[1], cursors[2])
+	require.Equal(t, []account.ActivityHistory{activities[4]}, visible)
+	require.Equal(t, "", next)
+	activityRepo.AssertExpectations(t)
+	folderUC.AssertExpectations(t)
+	folderRelation.AssertExpectations(t)
+}
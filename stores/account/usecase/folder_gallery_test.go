@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/ptr"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+var (
+	galleryOwner        = domain.Address("0x0000000000000000000000000000000000000001")
+	galleryCollaborator = domain.Address("0x0000000000000000000000000000000000000002")
+	galleryStranger     = domain.Address("0x0000000000000000000000000000000000000003")
+)
+
+type FolderGallerySuite struct {
+	suite.Suite
+	ctx          ctx.Ctx
+	folder       *account.Folder
+	folderRepo   *mAccount.FolderRepo
+	relationRepo *mAccount.FolderNftRelationshipRepo
+	im           *folderUsecaseImpl
+}
+
+func TestFolderGallerySuite(t *testing.T) {
+	suite.Run(t, new(FolderGallerySuite))
+}
+
+func (s *FolderGallerySuite) SetupTest() {
+	s.ctx = ctx.Background()
+	s.folder = &account.Folder{Id: "f", Owner: galleryOwner, Collaborators: []domain.Address{galleryCollaborator}}
+	s.folderRepo = &mAccount.FolderRepo{}
+	s.relationRepo = &mAccount.FolderNftRelationshipRepo{}
+	s.im = &folderUsecaseImpl{folderRepo: s.folderRepo, relationRepo: s.relationRepo}
+}
+
+func (s *FolderGallerySuite) TearDownTest() {
+	s.folderRepo.AssertExpectations(s.T())
+	s.relationRepo.AssertExpectations(s.T())
+}
+
+func (s *FolderGallerySuite) mockFolder() {
+	s.folderRepo.On("Get", mock.Anything, s.folder.Id).Return(s.folder, nil).Once()
+}
+
+// mockRelations mocks relations of the folder, selected by nftitem too if withItem is set
+func (s *FolderGallerySuite) mockRelations(withItem bool, relations ...*account.FolderNftRelationship) {
+	args := []interface{}{mock.Anything, mock.AnythingOfType("account.RelationsQueryOptionsFunc")}
+	if withItem {
+		args = append(args, mock.AnythingOfType("account.RelationsQueryOptionsFunc"))
+	}
+	s.relationRepo.On("GetAllRelations", args...).Return(relations, nil).Once()
+}
+
+func galleryRelation(tokenId string, addedBy domain.Address) *account.FolderNftRelationship {
+	return &account.FolderNftRelationship{
+		FolderId:        "f",
+		ChainId:         1,
+		ContractAddress: "0x00000000000000000000000000000000000000aa",
+		TokenId:         domain.TokenId(tokenId),
+		AddedBy:         addedBy,
+	}
+}
+
+func (s *FolderGallerySuite) TestReorder() {
+	r1, r2, r3 := galleryRelation("1", ""), galleryRelation("2", ""), galleryRelation("3", "")
+
+	s.mockFolder()
+	s.mockRelations(false, r1, r2, r3)
+	// unknown items are ignored and the rest keep their order
+	s.relationRepo.On("UpdateIndexes", mock.Anything, "f", []nftitem.Id{*r3.ToNftItemId(), *r1.ToNftItemId(), *r2.ToNftItemId()}).
+		Return(nil).Once()
+	s.mockRelations(false, r3, r1, r2)
+	s.folderRepo.On("Update", mock.Anything, "f", mock.MatchedBy(func(updater *account.FolderUpdater) bool {
+		return *updater.NftCount == 3 && updater.Cover.TokenId == "3"
+	})).Return(nil).Once()
+	s.NoError(s.im.Reorder(s.ctx, "f", []nftitem.Id{*r3.ToNftItemId(), {ChainId: 1, TokenId: "9"}}))
+
+	s.folder.IsBuiltIn = true
+	s.mockFolder()
+	s.ErrorIs(s.im.Reorder(s.ctx, "f", nil), account.ErrBuiltInFolder)
+}
+
+func (s *FolderGallerySuite) TestSetCaption() {
+	ownerRelation, collaboratorRelation := galleryRelation("1", ""), galleryRelation("2", galleryCollaborator)
+	ownerItem, collaboratorItem := *ownerRelation.ToNftItemId(), *collaboratorRelation.ToNftItemId()
+
+	s.mockFolder()
+	s.mockRelations(true, collaboratorRelation)
+	s.relationRepo.On("UpdateCaption", mock.Anything, "f", collaboratorItem, "by owner").Return(nil).Once()
+	s.NoError(s.im.SetCaption(s.ctx, "f", galleryOwner, collaboratorItem, "by owner"))
+
+	s.mockFolder()
+	s.mockRelations(true, collaboratorRelation)
+	s.relationRepo.On("UpdateCaption", mock.Anything, "f", collaboratorItem, "by collaborator").Return(nil).Once()
+	s.NoError(s.im.SetCaption(s.ctx, "f", galleryCollaborator, collaboratorItem, "by collaborator"))
+
+	// collaborators caption only nfts they added
+	s.mockFolder()
+	s.mockRelations(true, ownerRelation)
+	s.ErrorIs(s.im.SetCaption(s.ctx, "f", galleryCollaborator, ownerItem, "nope"), account.ErrNotFolderEditor)
+
+	s.mockFolder()
+	s.ErrorIs(s.im.SetCaption(s.ctx, "f", galleryStranger, ownerItem, "nope"), account.ErrNotFolderEditor)
+
+	s.mockFolder()
+	s.mockRelations(true)
+	s.ErrorIs(s.im.SetCaption(s.ctx, "f", galleryOwner, nftitem.Id{ChainId: 1, TokenId: "9"}, "nope"), domain.ErrNotFound)
+
+	s.mockFolder()
+	long := strings.Repeat("a", account.MaxFolderCaptionLength+1)
+	s.ErrorIs(s.im.SetCaption(s.ctx, "f", galleryOwner, ownerItem, long), account.ErrInvalidFolder)
+}
+
+func (s *FolderGallerySuite) TestUpdateGallerySlugTaken() {
+	slug := "my-gallery"
+
+	s.mockFolder()
+	s.folderRepo.On("GetFolders", mock.Anything,
+		mock.AnythingOfType("account.GetFoldersOptionsFunc"),
+		mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{{Id: "g", Owner: galleryOwner, Slug: slug}}, nil).Once()
+	s.ErrorIs(s.im.UpdateGallery(s.ctx, "f", &account.FolderUpdater{Slug: &slug}), account.ErrSlugTaken)
+
+	// the slug taken meanwhile is rejected by the unique index
+	s.mockFolder()
+	s.folderRepo.On("GetFolders", mock.Anything,
+		mock.AnythingOfType("account.GetFoldersOptionsFunc"),
+		mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{}, nil).Once()
+	s.folderRepo.On("Update", mock.Anything, "f", mock.MatchedBy(func(updater *account.FolderUpdater) bool {
+		return *updater.Slug == slug
+	})).Return(account.ErrSlugTaken).Once()
+	s.ErrorIs(s.im.UpdateGallery(s.ctx, "f", &account.FolderUpdater{Slug: &slug}), account.ErrSlugTaken)
+}
+
+func (s *FolderGallerySuite) TestGetPortfolio() {
+	owners := []domain.Address{galleryOwner, galleryCollaborator}
+
+	s.folderRepo.On("GetFolders", mock.Anything,
+		mock.MatchedBy(func(opt account.GetFoldersOptionsFunc) bool {
+			o, err := account.ParseGetFoldersOptionFunc(opt)
+			return err == nil && len(o.Owners) == 2
+		}),
+		mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{
+			{Owner: galleryOwner, FloorPriceInUsd: 300, TotalValueInUsd: 600, TotalValueMovement: 0.2, InstantLiquidityInUsd: 100, NftCount: 2},
+			{Owner: galleryOwner, NftCount: 1},
+			{Owner: galleryCollaborator, FloorPriceInUsd: 200, TotalValueInUsd: 400, TotalValueMovement: -0.2, InstantLiquidityInUsd: 50, NftCount: 2},
+			// the value dropped to 0, the previous value is kept by the stat
+			{Owner: galleryCollaborator, TotalValueMovement: -1, PreviousTotalValueInUsd: ptr.Float64(1000), NftCount: 1},
+			// refreshed before the previous value is kept, it can't be derived
+			{Owner: galleryCollaborator, TotalValueMovement: -1, NftCount: 1},
+		}, nil).Once()
+
+	portfolio, err := s.im.GetPortfolio(s.ctx, owners)
+	s.NoError(err)
+	s.Equal(float64(200), portfolio.FloorPriceInUsd)
+	s.Equal(float64(1000), portfolio.TotalValueInUsd)
+	s.Equal(float64(150), portfolio.InstantLiquidityInUsd)
+	s.Equal(7, portfolio.NftCount)
+	// previous values are 500, 500 and 1000
+	s.InDelta(-0.5, portfolio.TotalValueMovement, 1e-9)
+}
@@ -3,12 +3,14 @@ package usecase
 import (
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/viney-shih/goroutines"
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/base/ptr"
+	"github.com/x-xyz/goapi/base/validator"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/collection"
@@ -84,68 +86,44 @@ func (im *folderUsecaseImpl) Update(ctx ctx.Ctx, Id string, f *account.FolderUpd
 		return err
 	}
 
-	allNftsFromOwner, err := im.nftRepo.FindAll(ctx, nftitem.WithOwner(folder.Owner.ToLower()))
+	allNftMap, err := im.getOwnedNftMap(ctx, folder.Owner)
 	if err != nil {
-		ctx.WithFields(log.Fields{
-			"owner": folder.Owner,
-			"err":   err,
-		}).Error("failed to nftRepo.FindAll")
 		return err
 	}
 
-	allHoldingsFromOwner, err := im.erc1155HoldingRepo.FindAll(ctx, erc1155.WithOwner(folder.Owner.ToLower()))
+	existing, err := im.getRelationMap(ctx, Id)
 	if err != nil {
-		ctx.WithFields(log.Fields{
-			"owner": folder.Owner,
-			"err":   err,
-		}).Error("failed to erc1155HoldingRepo.FindAll")
 		return err
 	}
 
-	allNftMap := map[string]struct{}{}
-	for _, nft := range allNftsFromOwner {
-		allNftMap[nft.ToId().ToString()] = struct{}{}
-	}
-	for _, holding := range allHoldingsFromOwner {
-		nftId := nftitem.Id{
-			ChainId:         holding.ChainId,
-			ContractAddress: holding.Address.ToLower(),
-			TokenId:         holding.TokenId,
-		}
-		allNftMap[nftId.ToString()] = struct{}{}
-	}
-
-	items := []nftitem.Id{}
+	// captions and nfts added by collaborators are kept, the order follows itemsInput
+	relations := []*account.FolderNftRelationship{}
+	seen := map[string]struct{}{}
 	for _, it := range itemsInput {
-		if _, ok := allNftMap[it.ToString()]; ok {
-			items = append(items, it)
+		key := it.ToString()
+		if _, ok := seen[key]; ok {
+			continue
 		}
+		relation, inFolder := existing[key]
+		_, owned := allNftMap[key]
+		if !owned && !(inFolder && relation.AddedBy != "") {
+			continue
+		}
+		if !inFolder {
+			relation = &account.FolderNftRelationship{
+				ChainId:         it.ChainId,
+				ContractAddress: it.ContractAddress,
+				TokenId:         it.TokenId,
+			}
+		}
+		seen[key] = struct{}{}
+		relations = append(relations, relation)
 	}
 
-	if err := im.relationRepo.DeleteAllRelationsByFolderID(ctx, Id); err != nil {
-		ctx.WithFields(log.Fields{
-			"folderId": Id,
-			"err":      err,
-		}).Error("failed to DeleteAllRelationsByFolderID")
+	if err := im.replaceRelations(ctx, Id, relations, f); err != nil {
 		return err
 	}
 
-	if len(items) > 0 {
-		if err := im.relationRepo.AddNftitemsToFolder(ctx, items, Id); err != nil {
-			ctx.WithFields(log.Fields{
-				"folderId": Id,
-				"err":      err,
-			}).Error("failed to AddNftitemsToFolder")
-			return err
-		}
-
-		f.NftCount = ptr.Int(len(items))
-		f.Cover = &items[0]
-	} else {
-		f.NftCount = ptr.Int(0)
-		f.Cover = &nftitem.Id{}
-	}
-
 	if err := im.folderRepo.Update(ctx, Id, f); err != nil {
 		ctx.WithFields(log.Fields{
 			"folder": *f,
@@ -154,10 +132,16 @@ func (im *folderUsecaseImpl) Update(ctx ctx.Ctx, Id string, f *account.FolderUpd
 		return err
 	}
 
-	err = im.workerPool.ScheduleWithTimeout(3*time.Second, func() {
-		if err := im.RefreshStat(ctx, Id); err != nil {
+	im.scheduleRefreshStat(ctx, Id)
+
+	return nil
+}
+
+func (im *folderUsecaseImpl) scheduleRefreshStat(ctx ctx.Ctx, folderId string) {
+	err := im.workerPool.ScheduleWithTimeout(3*time.Second, func() {
+		if err := im.RefreshStat(ctx, folderId); err != nil {
 			ctx.WithFields(log.Fields{
-				"folderId": Id,
+				"folderId": folderId,
 				"err":      err,
 			}).Error("failed to RefreshStat")
 		}
@@ -165,11 +149,62 @@ func (im *folderUsecaseImpl) Update(ctx ctx.Ctx, Id string, f *account.FolderUpd
 	if err != nil {
 		ctx.WithFields(log.Fields{
 			"err":      err,
-			"folderId": Id,
+			"folderId": folderId,
 		}).Error("failed to ScheduleWithTimeout")
 	}
+}
 
-	return nil
+// getOwnedNftMap returns erc721 and erc1155 nfts owned by owner, keyed by nftitem.Id.ToString()
+func (im *folderUsecaseImpl) getOwnedNftMap(ctx ctx.Ctx, owner domain.Address) (map[string]struct{}, error) {
+	allNftsFromOwner, err := im.nftRepo.FindAll(ctx, nftitem.WithOwner(owner.ToLower()))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"owner": owner,
+			"err":   err,
+		}).Error("failed to nftRepo.FindAll")
+		return nil, err
+	}
+
+	allHoldingsFromOwner, err := im.erc1155HoldingRepo.FindAll(ctx, erc1155.WithOwner(owner.ToLower()))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"owner": owner,
+			"err":   err,
+		}).Error("failed to erc1155HoldingRepo.FindAll")
+		return nil, err
+	}
+
+	allNftMap := map[string]struct{}{}
+	for _, nft := range allNftsFromOwner {
+		allNftMap[nft.ToId().ToString()] = struct{}{}
+	}
+	for _, holding := range allHoldingsFromOwner {
+		nftId := nftitem.Id{
+			ChainId:         holding.ChainId,
+			ContractAddress: holding.Address.ToLower(),
+			TokenId:         holding.TokenId,
+		}
+		allNftMap[nftId.ToString()] = struct{}{}
+	}
+	return allNftMap, nil
+}
+
+// getRelationMap returns relations of the folder keyed by nftitem.Id.ToString()
+func (im *folderUsecaseImpl) getRelationMap(ctx ctx.Ctx, folderId string) (map[string]*account.FolderNftRelationship, error) {
+	relations, err := im.relationRepo.GetAllRelations(ctx, account.WithFolderId(folderId))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.GetAllRelations")
+		return nil, err
+	}
+
+	res := map[string]*account.FolderNftRelationship{}
+	for _, r := range relations {
+		res[r.ToNftItemId().ToString()] = r
+	}
+	return res, nil
 }
 
 func (im *folderUsecaseImpl) GetFolder(ctx ctx.Ctx, folderId string) (*account.Folder, error) {
@@ -197,6 +232,19 @@ func (im *folderUsecaseImpl) GetFolders(ctx ctx.Ctx, opts ...account.GetFoldersO
 }
 
 func (im *folderUsecaseImpl) GetNFTsInFolder(ctx ctx.Ctx, folderId string) ([]*nftitem.NftitemWith1155Balance, error) {
+	items, err := im.GetFolderItems(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*nftitem.NftitemWith1155Balance, len(items))
+	for i, item := range items {
+		res[i] = &item.NftitemWith1155Balance
+	}
+	return res, nil
+}
+
+func (im *folderUsecaseImpl) GetFolderItems(ctx ctx.Ctx, folderId string) ([]*account.FolderItem, error) {
 	folder, err := im.folderRepo.Get(ctx, folderId)
 	if err != nil {
 		ctx.WithFields(log.Fields{
@@ -216,11 +264,11 @@ func (im *folderUsecaseImpl) GetNFTsInFolder(ctx ctx.Ctx, folderId string) ([]*n
 	}
 
 	if len(relations) == 0 {
-		return []*nftitem.NftitemWith1155Balance{}, nil
+		return []*account.FolderItem{}, nil
 	}
 
 	errCh := make(chan error, len(relations))
-	nfts := make([]*nftitem.NftitemWith1155Balance, len(relations))
+	nfts := make([]*account.FolderItem, len(relations))
 	for ind := range relations {
 		go func(ind int) {
 			relation := relations[ind]
@@ -251,14 +299,18 @@ func (im *folderUsecaseImpl) GetNFTsInFolder(ctx ctx.Ctx, folderId string) ([]*n
 				return
 			}
 
-			nfts[ind] = &nftitem.NftitemWith1155Balance{NftItem: *item}
+			nfts[ind] = &account.FolderItem{
+				NftitemWith1155Balance: nftitem.NftitemWith1155Balance{NftItem: *item},
+				Caption:                relation.Caption,
+				AddedBy:                relation.AddedBy,
+			}
 
 			if item.TokenType == 1155 {
 				holdingId := erc1155.HoldingId{
 					ChainId: relation.ChainId,
 					Address: relation.ContractAddress.ToLower(),
 					TokenId: relation.TokenId,
-					Owner:   folder.Owner.ToLower(),
+					Owner:   relation.Holder(folder.Owner).ToLower(),
 				}
 				holding, err := im.erc1155HoldingRepo.FindOne(ctx, holdingId)
 				if err != nil {
@@ -285,7 +337,7 @@ func (im *folderUsecaseImpl) GetNFTsInFolder(ctx ctx.Ctx, folderId string) ([]*n
 	}
 
 	// filter out nil nft
-	res := []*nftitem.NftitemWith1155Balance{}
+	res := []*account.FolderItem{}
 	for _, nft := range nfts {
 		if nft != nil {
 			res = append(res, nft)
@@ -305,12 +357,12 @@ func (im *folderUsecaseImpl) RefreshStat(ctx ctx.Ctx, folderId string) error {
 		return err
 	}
 
-	nfts, err := im.GetNFTsInFolder(ctx, folderId)
+	nfts, err := im.GetFolderItems(ctx, folderId)
 	if err != nil {
 		ctx.WithFields(log.Fields{
 			"folderId": folderId,
 			"err":      err,
-		}).Error("GetNFTsInFolder failed")
+		}).Error("GetFolderItems failed")
 		return err
 	}
 
@@ -334,26 +386,31 @@ func (im *folderUsecaseImpl) RefreshStat(ctx ctx.Ctx, folderId string) error {
 	for _, nft := range nfts {
 		balance := 1
 		if nft.TokenType == 1155 {
+			holder := nft.Holder(folder.Owner).ToLower()
 			holdingId := erc1155.HoldingId{
 				ChainId: nft.ChainId,
 				Address: nft.ContractAddress.ToLower(),
 				TokenId: nft.TokenId,
-				Owner:   folder.Owner.ToLower(),
+				Owner:   holder,
 			}
 			holding, err := im.erc1155HoldingRepo.FindOne(ctx, holdingId)
 			if err == domain.ErrNotFound {
 				ctx.WithFields(log.Fields{
 					"folderId":  folderId,
-					"owner":     folder.Owner.ToLower(),
+					"owner":     holder,
 					"holdingId": holdingId,
 				}).Info("erc1155HoldingRepo.FindOne not found")
 
 				// delete relations if not found
-				err := im.DeleteRelationFromAllFolders(ctx, folder.Owner.ToLower(), *nft.ToId())
+				if nft.AddedBy != "" {
+					err = im.relationRepo.DeleteAll(ctx, account.WithFolderId(folderId), account.WithNftitemId(*nft.ToId()))
+				} else {
+					err = im.DeleteRelationFromAllFolders(ctx, holder, *nft.ToId())
+				}
 				if err != nil {
 					ctx.WithFields(log.Fields{
 						"err": err,
-					}).Error("failed to DeleteRelationFromAllFolders")
+					}).Error("failed to delete relations")
 				}
 				nftcount -= 1
 				continue
@@ -420,12 +477,13 @@ func (im *folderUsecaseImpl) RefreshStat(ctx ctx.Ctx, folderId string) error {
 	}
 
 	updater := &account.FolderUpdater{
-		FloorPriceInUsd:       &floorPriceInUsd,
-		TotalValueInUsd:       &totalValueInUsd,
-		InstantLiquidityInUsd: &instantLiquidityInUsd,
-		TotalValueMovement:    &totalValueMovement,
-		NftCount:              ptr.Int(nftcount),
-		CollectionCount:       ptr.Int(len(floors)),
+		FloorPriceInUsd:         &floorPriceInUsd,
+		TotalValueInUsd:         &totalValueInUsd,
+		InstantLiquidityInUsd:   &instantLiquidityInUsd,
+		TotalValueMovement:      &totalValueMovement,
+		PreviousTotalValueInUsd: &previousTotalValueInUsd,
+		NftCount:                ptr.Int(nftcount),
+		CollectionCount:         ptr.Int(len(floors)),
 	}
 	if err := im.folderRepo.Update(ctx, folderId, updater); err != nil {
 		ctx.WithFields(log.Fields{
@@ -845,3 +903,414 @@ func (im *folderUsecaseImpl) AddNftToPublicFolder(ctx ctx.Ctx, owner domain.Addr
 
 	return nil
 }
+
+func (im *folderUsecaseImpl) GetGallery(c ctx.Ctx, owner domain.Address, slug string) (*account.Folder, error) {
+	folders, err := im.folderRepo.GetFolders(c, account.WithOwner(owner.ToLower()), account.WithSlug(slug))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":   err,
+			"owner": owner,
+			"slug":  slug,
+		}).Error("failed to folderRepo.GetFolders")
+		return nil, err
+	}
+
+	if len(folders) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return folders[0], nil
+}
+
+func (im *folderUsecaseImpl) IncreaseViewCount(c ctx.Ctx, folderId string) (int64, error) {
+	count, err := im.folderRepo.IncreaseViewCount(c, folderId, 1)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to folderRepo.IncreaseViewCount")
+		return 0, err
+	}
+	return count, nil
+}
+
+// getEditableFolder returns the folder if it's not built-in
+func (im *folderUsecaseImpl) getEditableFolder(c ctx.Ctx, folderId string) (*account.Folder, error) {
+	folder, err := im.folderRepo.Get(c, folderId)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to folderRepo.Get")
+		return nil, err
+	}
+
+	if folder.IsBuiltIn {
+		return nil, account.ErrBuiltInFolder
+	}
+
+	return folder, nil
+}
+
+func (im *folderUsecaseImpl) UpdateGallery(c ctx.Ctx, folderId string, updater *account.FolderUpdater) error {
+	folder, err := im.getEditableFolder(c, folderId)
+	if err != nil {
+		return err
+	}
+
+	// only gallery settings are updated here, stats and nfts are maintained by the usecase
+	patchable := &account.FolderUpdater{
+		Description: updater.Description,
+		Slug:        updater.Slug,
+		CoverLayout: updater.CoverLayout,
+	}
+
+	if patchable.Description != nil && utf8.RuneCountInString(*patchable.Description) > account.MaxFolderDescriptionLength {
+		return account.ErrInvalidFolder
+	}
+
+	if patchable.CoverLayout != nil && !patchable.CoverLayout.IsValid() {
+		return account.ErrInvalidFolder
+	}
+
+	if patchable.Slug != nil && *patchable.Slug != "" && *patchable.Slug != folder.Slug {
+		if !account.IsValidSlug(*patchable.Slug) {
+			return account.ErrInvalidSlug
+		}
+		// fails fast before touching relations, concurrent updates are rejected by folderRepo.Update
+		if _, err := im.GetGallery(c, folder.Owner, *patchable.Slug); err == nil {
+			return account.ErrSlugTaken
+		} else if err != domain.ErrNotFound {
+			return err
+		}
+	}
+
+	var removedCollaborators map[domain.Address]struct{}
+	if updater.Collaborators != nil {
+		collaborators := []domain.Address{}
+		seen := map[domain.Address]struct{}{}
+		for _, collaborator := range *updater.Collaborators {
+			collaborator = collaborator.ToLower()
+			if !validator.IsValidAddress(string(collaborator)) || collaborator.Equals(folder.Owner) {
+				return account.ErrInvalidFolder
+			}
+			if _, ok := seen[collaborator]; !ok {
+				seen[collaborator] = struct{}{}
+				collaborators = append(collaborators, collaborator)
+			}
+		}
+		if len(collaborators) > account.MaxFolderCollaborators {
+			return account.ErrInvalidFolder
+		}
+		patchable.Collaborators = &collaborators
+
+		removedCollaborators = map[domain.Address]struct{}{}
+		for _, collaborator := range folder.Collaborators {
+			if _, ok := seen[collaborator.ToLower()]; !ok {
+				removedCollaborators[collaborator.ToLower()] = struct{}{}
+			}
+		}
+	}
+
+	if len(removedCollaborators) > 0 {
+		// nfts of removed collaborators leave with them
+		removed := []domain.Address{}
+		for collaborator := range removedCollaborators {
+			removed = append(removed, collaborator)
+		}
+		if err := im.relationRepo.DeleteAll(c, account.WithFolderId(folderId), account.WithAddedBys(removed)); err != nil {
+			c.WithFields(log.Fields{
+				"err":      err,
+				"folderId": folderId,
+			}).Error("failed to relationRepo.DeleteAll")
+			return err
+		}
+
+		if err := im.fillCountAndCover(c, folderId, patchable); err != nil {
+			return err
+		}
+	}
+
+	if err := im.folderRepo.Update(c, folderId, patchable); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to folderRepo.Update")
+		return err
+	}
+
+	if patchable.NftCount != nil {
+		im.scheduleRefreshStat(c, folderId)
+	}
+
+	return nil
+}
+
+// replaceRelations replaces relations of the folder and sets count and cover to patchable
+func (im *folderUsecaseImpl) replaceRelations(c ctx.Ctx, folderId string, relations []*account.FolderNftRelationship, patchable *account.FolderUpdater) error {
+	if err := im.relationRepo.ReplaceRelations(c, folderId, relations); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.ReplaceRelations")
+		return err
+	}
+
+	patchable.NftCount = ptr.Int(len(relations))
+	patchable.Cover = &nftitem.Id{}
+	if len(relations) > 0 {
+		patchable.Cover = relations[0].ToNftItemId()
+	}
+	return nil
+}
+
+// fillCountAndCover sets count and cover of the folder to patchable from its stored relations
+func (im *folderUsecaseImpl) fillCountAndCover(c ctx.Ctx, folderId string, patchable *account.FolderUpdater) error {
+	relations, err := im.relationRepo.GetAllRelations(c, account.WithFolderId(folderId))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.GetAllRelations")
+		return err
+	}
+
+	patchable.NftCount = ptr.Int(len(relations))
+	patchable.Cover = &nftitem.Id{}
+	if len(relations) > 0 {
+		patchable.Cover = relations[0].ToNftItemId()
+	}
+	return nil
+}
+
+func (im *folderUsecaseImpl) Reorder(c ctx.Ctx, folderId string, items []nftitem.Id) error {
+	if _, err := im.getEditableFolder(c, folderId); err != nil {
+		return err
+	}
+
+	relations, err := im.relationRepo.GetAllRelations(c, account.WithFolderId(folderId))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.GetAllRelations")
+		return err
+	}
+
+	relationMap := map[string]*account.FolderNftRelationship{}
+	for _, r := range relations {
+		relationMap[r.ToNftItemId().ToString()] = r
+	}
+
+	ordered := []nftitem.Id{}
+	for _, it := range items {
+		if r, ok := relationMap[it.ToString()]; ok {
+			ordered = append(ordered, *r.ToNftItemId())
+			delete(relationMap, it.ToString())
+		}
+	}
+	for _, r := range relations {
+		if _, ok := relationMap[r.ToNftItemId().ToString()]; ok {
+			ordered = append(ordered, *r.ToNftItemId())
+		}
+	}
+
+	// only indexes are updated, so that captions or nfts changed meanwhile aren't overwritten
+	if err := im.relationRepo.UpdateIndexes(c, folderId, ordered); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.UpdateIndexes")
+		return err
+	}
+
+	patchable := &account.FolderUpdater{}
+	if err := im.fillCountAndCover(c, folderId, patchable); err != nil {
+		return err
+	}
+
+	if err := im.folderRepo.Update(c, folderId, patchable); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to folderRepo.Update")
+		return err
+	}
+
+	return nil
+}
+
+func (im *folderUsecaseImpl) SetCaption(c ctx.Ctx, folderId string, editor domain.Address, item nftitem.Id, caption string) error {
+	folder, err := im.getEditableFolder(c, folderId)
+	if err != nil {
+		return err
+	}
+
+	if !folder.CanEdit(editor) {
+		return account.ErrNotFolderEditor
+	}
+
+	if utf8.RuneCountInString(caption) > account.MaxFolderCaptionLength {
+		return account.ErrInvalidFolder
+	}
+
+	relations, err := im.relationRepo.GetAllRelations(c, account.WithFolderId(folderId), account.WithNftitemId(item))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+			"item":     item,
+		}).Error("failed to relationRepo.GetAllRelations")
+		return err
+	}
+
+	if len(relations) == 0 {
+		return domain.ErrNotFound
+	}
+
+	// collaborators caption only nfts they added
+	if !folder.Owner.Equals(editor) && !relations[0].AddedBy.Equals(editor) {
+		return account.ErrNotFolderEditor
+	}
+
+	if err := im.relationRepo.UpdateCaption(c, folderId, item, caption); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+			"item":     item,
+		}).Error("failed to relationRepo.UpdateCaption")
+		return err
+	}
+
+	return nil
+}
+
+func (im *folderUsecaseImpl) AddItems(c ctx.Ctx, folderId string, editor domain.Address, items []nftitem.Id) error {
+	folder, err := im.getEditableFolder(c, folderId)
+	if err != nil {
+		return err
+	}
+
+	if !folder.CanEdit(editor) {
+		return account.ErrNotFolderEditor
+	}
+
+	owned, err := im.getOwnedNftMap(c, editor)
+	if err != nil {
+		return err
+	}
+
+	relations, err := im.relationRepo.GetAllRelations(c, account.WithFolderId(folderId))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.GetAllRelations")
+		return err
+	}
+
+	inFolder := map[string]struct{}{}
+	for _, r := range relations {
+		inFolder[r.ToNftItemId().ToString()] = struct{}{}
+	}
+
+	addedBy := domain.Address("")
+	if !folder.Owner.Equals(editor) {
+		addedBy = editor.ToLower()
+	}
+
+	added := []*account.FolderNftRelationship{}
+	for _, it := range items {
+		key := it.ToString()
+		if _, ok := owned[key]; !ok {
+			continue
+		}
+		if _, ok := inFolder[key]; ok {
+			continue
+		}
+		inFolder[key] = struct{}{}
+		added = append(added, &account.FolderNftRelationship{
+			ChainId:         it.ChainId,
+			ContractAddress: it.ContractAddress,
+			TokenId:         it.TokenId,
+			AddedBy:         addedBy,
+		})
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	// only new relations are written, so that relations changed meanwhile aren't overwritten
+	if err := im.relationRepo.AppendRelations(c, folderId, added); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to relationRepo.AppendRelations")
+		return err
+	}
+
+	patchable := &account.FolderUpdater{}
+	if err := im.fillCountAndCover(c, folderId, patchable); err != nil {
+		return err
+	}
+
+	if err := im.folderRepo.Update(c, folderId, patchable); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"folderId": folderId,
+		}).Error("failed to folderRepo.Update")
+		return err
+	}
+
+	im.scheduleRefreshStat(c, folderId)
+
+	return nil
+}
+
+func (im *folderUsecaseImpl) GetPortfolio(c ctx.Ctx, owners []domain.Address) (*account.Portfolio, error) {
+	lowered := make([]domain.Address, 0, len(owners))
+	for _, owner := range owners {
+		lowered = append(lowered, owner.ToLower())
+	}
+
+	// every nft of an owner is in one of its built-in folders
+	folders, err := im.folderRepo.GetFolders(c, account.WithOwners(lowered), account.WithBuiltIn(true))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"owners": owners,
+		}).Error("failed to folderRepo.GetFolders")
+		return nil, err
+	}
+
+	res := &account.Portfolio{}
+	previousTotalValueInUsd := float64(0)
+	for _, f := range folders {
+		if f.FloorPriceInUsd > 0 && (res.FloorPriceInUsd == 0 || f.FloorPriceInUsd < res.FloorPriceInUsd) {
+			res.FloorPriceInUsd = f.FloorPriceInUsd
+		}
+		res.TotalValueInUsd += f.TotalValueInUsd
+		previousTotalValueInUsd += previousTotalValue(f)
+		res.InstantLiquidityInUsd += f.InstantLiquidityInUsd
+		res.NftCount += f.NftCount
+	}
+
+	if previousTotalValueInUsd > 0 {
+		res.TotalValueMovement = (res.TotalValueInUsd - previousTotalValueInUsd) / previousTotalValueInUsd
+	}
+
+	return res, nil
+}
+
+// previousTotalValue returns the total value of the folder at yesterday floors. It's derived from the movement for
+// folders refreshed before it's kept, which is impossible if the value dropped to 0, so the folder is skipped
+func previousTotalValue(f *account.Folder) float64 {
+	if f.PreviousTotalValueInUsd != nil {
+		return *f.PreviousTotalValueInUsd
+	}
+	if 1+f.TotalValueMovement == 0 {
+		return 0
+	}
+	return f.TotalValueInUsd / (1 + f.TotalValueMovement)
+}
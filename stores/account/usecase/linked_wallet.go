@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/ethereum"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/validator"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+)
+
+func (im *impl) LinkWallet(c ctx.Ctx, primary, wallet domain.Address, chainId domain.ChainId, signature string) error {
+	primary = primary.ToLower()
+	wallet = wallet.ToLower()
+	c = ctx.WithValues(c, map[string]interface{}{
+		"primary": primary,
+		"wallet":  wallet,
+	})
+
+	if !validator.IsValidAddress(string(wallet)) {
+		return domain.ErrInvalidAddress
+	}
+	if primary.Equals(wallet) {
+		return account.ErrCannotLinkSelf
+	}
+
+	// groups are one level deep, a linked wallet can't be a primary account and vice versa
+	if _, err := im.linkedWallet.Get(c, primary); err == nil {
+		return account.ErrPrimaryIsLinked
+	} else if err != domain.ErrNotFound {
+		return err
+	}
+
+	if linked, err := im.linkedWallet.FindByPrimary(c, wallet); err != nil {
+		return err
+	} else if len(linked) > 0 {
+		return account.ErrWalletAlreadyLinked
+	}
+
+	linked, err := im.linkedWallet.FindByPrimary(c, primary)
+	if err != nil {
+		return err
+	}
+	if len(linked) >= account.MaxLinkedWallets {
+		return account.ErrTooManyLinkedWallets
+	}
+
+	a, err := im.repo.Get(c, primary)
+	if err != nil {
+		c.WithField("err", err).Error("get address failed")
+		return err
+	}
+	if a.Nonce == invalidNonce {
+		return account.ErrInvalidNonce
+	}
+
+	// reset nonce after validated the signature, so the link message can't be replayed
+	defer im.repo.Update(c, primary, &account.Updater{
+		Nonce: invalidNonce,
+	})
+
+	msg := []byte(fmt.Sprintf(im.linkWalletMsg, primary, strconv.Itoa(int(a.Nonce))))
+	if err := im.verifyWalletSignature(c, wallet, chainId, msg, signature); err != nil {
+		return err
+	}
+
+	if err := im.linkedWallet.Insert(c, &account.LinkedWallet{
+		Address:  wallet,
+		Primary:  primary,
+		ChainId:  chainId,
+		LinkedAt: time.Now(),
+	}); err != nil {
+		c.WithField("err", err).Error("linkedWallet.Insert failed")
+		return err
+	}
+
+	return nil
+}
+
+// verifyWalletSignature accepts personal signatures of EOAs and ERC-1271 signatures of contract wallets
+func (im *impl) verifyWalletSignature(c ctx.Ctx, wallet domain.Address, chainId domain.ChainId, msg []byte, signature string) error {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return account.ErrInvalidSignature
+	}
+
+	valid, err := ethereum.ValidateMsgSignature(msg, signature, wallet.ToLowerStr())
+	if err == nil && valid {
+		return nil
+	}
+	c.WithFields(log.Fields{
+		"err":   err,
+		"valid": valid,
+	}).Warn("validating eoa signature failed")
+
+	if im.erc1271 != nil {
+		valid, err = im.erc1271.IsValidSignature(c, int32(chainId), wallet.ToLowerStr(), common.BytesToHash(accounts.TextHash(msg)), sig)
+		if err == nil && valid {
+			return nil
+		}
+		c.WithFields(log.Fields{
+			"err":   err,
+			"valid": valid,
+		}).Warn("validating eip1271 signature failed")
+	}
+
+	return account.ErrInvalidSignature
+}
+
+func (im *impl) UnlinkWallet(c ctx.Ctx, requester, wallet domain.Address) error {
+	c = ctx.WithValues(c, map[string]interface{}{
+		"requester": requester,
+		"wallet":    wallet,
+	})
+
+	link, err := im.linkedWallet.Get(c, wallet)
+	if err == domain.ErrNotFound {
+		return account.ErrWalletNotLinked
+	} else if err != nil {
+		return err
+	}
+
+	if !link.Primary.Equals(requester) && !link.Address.Equals(requester) {
+		return account.ErrWalletNotLinked
+	}
+
+	if err := im.linkedWallet.Remove(c, wallet); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		c.WithField("err", err).Error("linkedWallet.Remove failed")
+		return err
+	}
+
+	return nil
+}
+
+func (im *impl) GetLinkedWallets(c ctx.Ctx, address domain.Address) (*account.LinkedWallets, error) {
+	primary := address.ToLower()
+	if link, err := im.linkedWallet.Get(c, primary); err == nil {
+		primary = link.Primary
+	} else if err != domain.ErrNotFound {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("linkedWallet.Get failed")
+		return nil, err
+	}
+
+	wallets, err := im.linkedWallet.FindByPrimary(c, primary)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"primary": primary,
+		}).Error("linkedWallet.FindByPrimary failed")
+		return nil, err
+	}
+
+	return &account.LinkedWallets{Primary: primary, Wallets: wallets}, nil
+}
+
+func (im *impl) GetLinkedActivities(c ctx.Ctx, address domain.Address, optFns ...account.FindActivityHistoryOptions) (*account.ActivityResult, error) {
+	wallets, err := im.GetLinkedWallets(c, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return im.findActivities(c, account.ActivityHistoryWithAccounts(wallets.Addresses()), optFns...)
+}
+
+func (im *impl) GetLinkedCollectionHoldings(c ctx.Ctx, address domain.Address) (*account.AccountCollectionHoldings, error) {
+	wallets, err := im.GetLinkedWallets(c, address)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &account.AccountCollectionHoldings{
+		Collections:               make(map[account.CollectionId]int32),
+		CollectionsHoldingBalance: make(map[account.CollectionId]int32),
+	}
+
+	for _, a := range wallets.Addresses() {
+		holdings, err := im.GetAccountCollectionHoldings(c, a)
+		if err != nil {
+			return nil, err
+		}
+		for id, count := range holdings.Collections {
+			res.Collections[id] += count
+		}
+		for id, balance := range holdings.CollectionsHoldingBalance {
+			res.CollectionsHoldingBalance[id] += balance
+		}
+	}
+
+	return res, nil
+}
+
+func (im *impl) GetLinkedAccountStat(c ctx.Ctx, address domain.Address) (*account.AccountStat, error) {
+	wallets, err := im.GetLinkedWallets(c, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return im.accountStat(c, wallets.Addresses())
+}
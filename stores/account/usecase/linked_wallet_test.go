@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	mLike "github.com/x-xyz/goapi/domain/like/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+)
+
+type LinkedWalletSuite struct {
+	suite.Suite
+	ctx          ctx.Ctx
+	repo         *mAccount.Repo
+	linkedWallet *mAccount.LinkedWalletRepo
+	erc1271      *mContract.Erc1271Contract
+	like         *mLike.Usecase
+	nftitem      *mNftitem.Repo
+	collection   *mCollection.Usecase
+	im           *impl
+
+	primary   domain.Address
+	walletKey *ecdsa.PrivateKey
+	wallet    domain.Address
+	otherKey  *ecdsa.PrivateKey
+	other     domain.Address
+}
+
+func TestLinkedWalletSuite(t *testing.T) {
+	suite.Run(t, new(LinkedWalletSuite))
+}
+
+func (s *LinkedWalletSuite) SetupTest() {
+	s.ctx = ctx.Background()
+	s.repo = &mAccount.Repo{}
+	s.linkedWallet = &mAccount.LinkedWalletRepo{}
+	s.erc1271 = &mContract.Erc1271Contract{}
+	s.like = &mLike.Usecase{}
+	s.nftitem = &mNftitem.Repo{}
+	s.collection = &mCollection.Usecase{}
+	s.im = &impl{
+		repo:          s.repo,
+		linkedWallet:  s.linkedWallet,
+		erc1271:       s.erc1271,
+		like:          s.like,
+		nftitem:       s.nftitem,
+		collection:    s.collection,
+		linkWalletMsg: account.DefaultLinkWalletMsg,
+	}
+
+	_, s.primary = s.newWallet()
+	s.walletKey, s.wallet = s.newWallet()
+	s.otherKey, s.other = s.newWallet()
+}
+
+func (s *LinkedWalletSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.linkedWallet.AssertExpectations(s.T())
+	s.erc1271.AssertExpectations(s.T())
+	s.like.AssertExpectations(s.T())
+	s.nftitem.AssertExpectations(s.T())
+	s.collection.AssertExpectations(s.T())
+}
+
+func (s *LinkedWalletSuite) newWallet() (*ecdsa.PrivateKey, domain.Address) {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	return key, domain.Address(crypto.PubkeyToAddress(key.PublicKey).Hex()).ToLower()
+}
+
+func (s *LinkedWalletSuite) signLinkMsg(key *ecdsa.PrivateKey, primary domain.Address, nonce int32) string {
+	msg := fmt.Sprintf(account.DefaultLinkWalletMsg, primary, fmt.Sprint(nonce))
+	sig, err := crypto.Sign(accounts.TextHash([]byte(msg)), key)
+	s.Require().NoError(err)
+	return hexutil.Encode(sig)
+}
+
+// mockLinkable mocks wallet being linkable to primary, the nonce of primary is reset after read
+func (s *LinkedWalletSuite) mockLinkable(primary, wallet domain.Address, nonce int32) {
+	s.linkedWallet.On("Get", mock.Anything, primary).Return(nil, domain.ErrNotFound).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, wallet).Return([]*account.LinkedWallet{}, nil).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, primary).Return([]*account.LinkedWallet{}, nil).Once()
+	s.repo.On("Get", mock.Anything, primary).Return(&account.Account{Address: primary, Nonce: nonce}, nil).Once()
+	if nonce != invalidNonce {
+		s.repo.On("Update", mock.Anything, primary, &account.Updater{Nonce: invalidNonce}).Return(nil).Once()
+	}
+}
+
+func (s *LinkedWalletSuite) mockInsert(primary, wallet domain.Address) {
+	s.linkedWallet.On("Insert", mock.Anything, mock.MatchedBy(func(w *account.LinkedWallet) bool {
+		return w.Address == wallet && w.Primary == primary && w.ChainId == 1
+	})).Return(nil).Once()
+}
+
+func (s *LinkedWalletSuite) mockErc1271(wallet domain.Address, valid bool) {
+	s.erc1271.On("IsValidSignature", mock.Anything, int32(1), wallet.ToLowerStr(), mock.Anything, mock.Anything).
+		Return(valid, nil).Once()
+}
+
+func (s *LinkedWalletSuite) TestLinkWallet() {
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.primary, s.primary, 1, ""), account.ErrCannotLinkSelf)
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.primary, "0x1234", 1, ""), domain.ErrInvalidAddress)
+
+	// signed by another wallet, the nonce is consumed anyway
+	s.mockLinkable(s.primary, s.wallet, 42)
+	s.mockErc1271(s.wallet, false)
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.primary, s.wallet, 1, s.signLinkMsg(s.otherKey, s.primary, 42)), account.ErrInvalidSignature)
+
+	s.mockLinkable(s.primary, s.wallet, invalidNonce)
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.primary, s.wallet, 1, s.signLinkMsg(s.walletKey, s.primary, 42)), account.ErrInvalidNonce)
+
+	s.mockLinkable(s.primary, s.wallet, 43)
+	s.mockInsert(s.primary, s.wallet)
+	s.NoError(s.im.LinkWallet(s.ctx, s.primary, s.wallet, 1, s.signLinkMsg(s.walletKey, s.primary, 43)))
+}
+
+func (s *LinkedWalletSuite) TestLinkContractWallet() {
+	contractWallet := domain.Address("0x00000000000000000000000000000000000000CC")
+
+	s.mockLinkable(s.primary, contractWallet.ToLower(), 44)
+	s.mockErc1271(contractWallet, true)
+	s.mockInsert(s.primary, contractWallet.ToLower())
+	s.NoError(s.im.LinkWallet(s.ctx, s.primary, contractWallet, 1, "0x1234"))
+}
+
+func (s *LinkedWalletSuite) TestLinkWalletGroupRules() {
+	// groups are one level deep
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(&account.LinkedWallet{Address: s.wallet, Primary: s.primary}, nil).Once()
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.wallet, s.other, 1, ""), account.ErrPrimaryIsLinked)
+
+	s.linkedWallet.On("Get", mock.Anything, s.other).Return(nil, domain.ErrNotFound).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.primary).
+		Return([]*account.LinkedWallet{{Address: s.wallet, Primary: s.primary}}, nil).Once()
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.other, s.primary, 1, ""), account.ErrWalletAlreadyLinked)
+
+	linked := []*account.LinkedWallet{}
+	for i := 0; i < account.MaxLinkedWallets; i++ {
+		linked = append(linked, &account.LinkedWallet{Primary: s.primary})
+	}
+	s.linkedWallet.On("Get", mock.Anything, s.primary).Return(nil, domain.ErrNotFound).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.other).Return([]*account.LinkedWallet{}, nil).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.primary).Return(linked, nil).Once()
+	s.ErrorIs(s.im.LinkWallet(s.ctx, s.primary, s.other, 1, ""), account.ErrTooManyLinkedWallets)
+}
+
+func (s *LinkedWalletSuite) TestGetLinkedWallets() {
+	link := &account.LinkedWallet{Address: s.wallet, Primary: s.primary}
+
+	s.linkedWallet.On("Get", mock.Anything, s.primary).Return(nil, domain.ErrNotFound).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.primary).Return([]*account.LinkedWallet{link}, nil).Once()
+	wallets, err := s.im.GetLinkedWallets(s.ctx, s.primary)
+	s.NoError(err)
+	s.Equal([]domain.Address{s.primary, s.wallet}, wallets.Addresses())
+
+	// the group is resolved from a linked wallet too
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(link, nil).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.primary).Return([]*account.LinkedWallet{link}, nil).Once()
+	wallets, err = s.im.GetLinkedWallets(s.ctx, s.wallet)
+	s.NoError(err)
+	s.Equal([]domain.Address{s.primary, s.wallet}, wallets.Addresses())
+}
+
+func (s *LinkedWalletSuite) TestUnlinkWallet() {
+	link := &account.LinkedWallet{Address: s.wallet, Primary: s.primary}
+
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(link, nil).Once()
+	s.ErrorIs(s.im.UnlinkWallet(s.ctx, s.other, s.wallet), account.ErrWalletNotLinked)
+
+	s.linkedWallet.On("Get", mock.Anything, s.other).Return(nil, domain.ErrNotFound).Once()
+	s.ErrorIs(s.im.UnlinkWallet(s.ctx, s.primary, s.other), account.ErrWalletNotLinked)
+
+	// by the linked wallet itself
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(link, nil).Once()
+	s.linkedWallet.On("Remove", mock.Anything, s.wallet).Return(nil).Once()
+	s.NoError(s.im.UnlinkWallet(s.ctx, s.wallet, s.wallet))
+
+	// by the primary account
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(link, nil).Once()
+	s.linkedWallet.On("Remove", mock.Anything, s.wallet).Return(nil).Once()
+	s.NoError(s.im.UnlinkWallet(s.ctx, s.primary, s.wallet))
+}
+
+func (s *LinkedWalletSuite) mockStat(owner domain.Address, liked int, items []*nftitem.NftItem, created []*collection.CollectionWithHoldingCount) {
+	s.like.On("GetLikedCount", mock.Anything, owner).Return(liked, nil).Once()
+	s.nftitem.On("FindAll", mock.Anything, mock.MatchedBy(func(opt nftitem.FindAllOptionsFunc) bool {
+		o, err := nftitem.GetFindAllOptions(opt)
+		return err == nil && o.Owner != nil && *o.Owner == owner
+	})).Return(items, nil).Once()
+	s.collection.On("FindAll", mock.Anything, mock.AnythingOfType("collection.FindAllOptions")).
+		Return(&collection.SearchResult{Items: created, Count: len(created)}, nil).Once()
+}
+
+func (s *LinkedWalletSuite) TestGetLinkedAccountStat() {
+	colA := domain.Address("0x00000000000000000000000000000000000000aa")
+	colB := domain.Address("0x00000000000000000000000000000000000000bb")
+	created := &collection.CollectionWithHoldingCount{Collection: collection.Collection{ChainId: 1, Erc721Address: colB}}
+
+	s.linkedWallet.On("Get", mock.Anything, s.wallet).Return(&account.LinkedWallet{Address: s.wallet, Primary: s.primary}, nil).Once()
+	s.linkedWallet.On("FindByPrimary", mock.Anything, s.primary).
+		Return([]*account.LinkedWallet{{Address: s.wallet, Primary: s.primary}}, nil).Once()
+	s.mockStat(s.primary, 2, []*nftitem.NftItem{
+		{ChainId: 1, ContractAddress: colA, TokenId: "1"},
+		{ChainId: 1, ContractAddress: colA, TokenId: "2"},
+	}, nil)
+	s.mockStat(s.wallet, 1, []*nftitem.NftItem{
+		{ChainId: 1, ContractAddress: colA, TokenId: "3"},
+		{ChainId: 1, ContractAddress: colB, TokenId: "1"},
+	}, []*collection.CollectionWithHoldingCount{created})
+	s.nftitem.On("Count", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return(5, nil).Once()
+
+	stat, err := s.im.GetLinkedAccountStat(s.ctx, s.wallet)
+	s.NoError(err)
+	s.Equal(&account.AccountStat{
+		Single:             4,
+		Favorite:           3,
+		Collections:        2,
+		CreatedNfts:        5,
+		CreatedCollections: 1,
+	}, stat)
+}
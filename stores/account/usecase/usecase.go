@@ -21,8 +21,16 @@ import (
 	"github.com/x-xyz/goapi/domain/like"
 	"github.com/x-xyz/goapi/domain/moderator"
 	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/search"
 	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/service/cache"
+	compoundcache "github.com/x-xyz/goapi/service/cache/compoundCache"
+	"github.com/x-xyz/goapi/service/cache/provider/primitive"
+	redisCache "github.com/x-xyz/goapi/service/cache/provider/redis"
+	"github.com/x-xyz/goapi/service/chain/contract"
+	"github.com/x-xyz/goapi/service/ens"
 	"github.com/x-xyz/goapi/service/pinata"
+	"github.com/x-xyz/goapi/service/redis"
 )
 
 const (
@@ -43,6 +51,20 @@ type AccountUseCaseCfg struct {
 	CollectionUC            collection.Usecase
 	ActivityRepo            account.ActivityHistoryRepo
 	FolderUC                account.FolderUseCase
+	SearchIndexer           search.Indexer
+	LinkedWalletRepo        account.LinkedWalletRepo
+	// ENS is optional, accounts are not enriched with ens profiles if nil
+	ENS ens.ENS
+	// CollectionLikeUC is optional, feeds include liked collections only if set
+	CollectionLikeUC like.CollectionLikeUsecase
+	// FolderRelationRepo is optional, tokens in private folders are not hidden from feeds if nil
+	FolderRelationRepo account.FolderNftRelationshipRepo
+	// Redis is optional, feeds are cached in memory only if nil
+	Redis redis.Service
+	// Erc1271 is optional, contract wallets can't be linked if nil
+	Erc1271 contract.Erc1271Contract
+	// LinkWalletMsg is optional, account.DefaultLinkWalletMsg is used if empty
+	LinkWalletMsg string
 }
 
 type impl struct {
@@ -58,10 +80,41 @@ type impl struct {
 	signatureMsg string
 	collection   collection.Usecase
 	folder       account.FolderUseCase
+	searchIdx    search.Indexer
+	ens          ens.ENS
+
+	collectionLike like.CollectionLikeUsecase
+	folderRelation account.FolderNftRelationshipRepo
+	feedCache      cache.Service
+
+	linkedWallet  account.LinkedWalletRepo
+	erc1271       contract.Erc1271Contract
+	linkWalletMsg string
 }
 
 // New creates account usecase
 func New(cfg *AccountUseCaseCfg) account.Usecase {
+	caches := []cache.Service{
+		cache.New(cache.ServiceConfig{
+			Ttl:   10 * time.Second,
+			Pfx:   "feed",
+			Cache: primitive.NewPrimitive("feed", 1024),
+		}),
+	}
+	if cfg.Redis != nil {
+		// feeds of hot users are served from redis until new activities are likely
+		caches = append(caches, cache.New(cache.ServiceConfig{
+			Ttl:   30 * time.Second,
+			Pfx:   "feed",
+			Cache: redisCache.NewRedis(cfg.Redis),
+		}))
+	}
+
+	linkWalletMsg := cfg.LinkWalletMsg
+	if linkWalletMsg == "" {
+		linkWalletMsg = account.DefaultLinkWalletMsg
+	}
+
 	return &impl{
 		repo:         cfg.Repo,
 		nsRepo:       cfg.NotificationSettingRepo,
@@ -75,6 +128,16 @@ func New(cfg *AccountUseCaseCfg) account.Usecase {
 		collection:   cfg.CollectionUC,
 		activityRepo: cfg.ActivityRepo,
 		folder:       cfg.FolderUC,
+		searchIdx:    cfg.SearchIndexer,
+		ens:          cfg.ENS,
+
+		collectionLike: cfg.CollectionLikeUC,
+		folderRelation: cfg.FolderRelationRepo,
+		feedCache:      compoundcache.NewCompoundCache(caches),
+
+		linkedWallet:  cfg.LinkedWalletRepo,
+		erc1271:       cfg.Erc1271,
+		linkWalletMsg: linkWalletMsg,
 	}
 }
 
@@ -87,7 +150,12 @@ func (im *impl) Get(c ctx.Ctx, address domain.Address) (*account.Info, error) {
 		}).Error("get address error")
 		return nil, err
 	}
-	return im.accountToInfo(c, a)
+	info, err := im.accountToInfo(c, a)
+	if err != nil {
+		return nil, err
+	}
+	im.enrichInfos(c, []*account.Info{info})
+	return info, nil
 }
 
 func (im *impl) Update(c ctx.Ctx, address domain.Address, a *account.Updater) (*account.Info, error) {
@@ -104,6 +172,7 @@ func (im *impl) Update(c ctx.Ctx, address domain.Address, a *account.Updater) (*
 		c.WithField("err", err).Error("repo.Update failed")
 		return nil, err
 	}
+	im.indexAccount(c, address)
 	return im.Get(c, address)
 }
 
@@ -345,6 +414,7 @@ func (im *impl) getAccounts(c ctx.Ctx, addresses []domain.Address) ([]*account.I
 		infos[idx] = ret.Value().(*account.Info)
 		idx++
 	}
+	im.enrichInfos(c, infos)
 	return infos, nil
 }
 
@@ -396,6 +466,7 @@ func (im *impl) Ban(c ctx.Ctx, address domain.Address) error {
 		c.WithField("err", err).WithField("address", address).Error("repo.Update failed")
 		return err
 	}
+	im.indexAccount(c, address)
 	return nil
 }
 
@@ -404,29 +475,38 @@ func (im *impl) Unban(c ctx.Ctx, address domain.Address) error {
 		c.WithField("err", err).WithField("address", address).Error("repo.Update failed")
 		return err
 	}
+	im.indexAccount(c, address)
 	return nil
 }
 
+// accountActivityTypes are the activity types shown on accounts
+var accountActivityTypes = []account.ActivityHistoryType{
+	account.ActivityHistoryTypeCreateOffer,
+	account.ActivityHistoryTypeCancelOffer,
+	account.ActivityHistoryTypeList,
+	account.ActivityHistoryTypeCancelListing,
+	account.ActivityHistoryTypePlaceBid,
+	account.ActivityHistoryTypeBuy,
+	account.ActivityHistoryTypeSold,
+	account.ActivityHistoryTypeTransfer,
+	account.ActivityHistoryTypeMint,
+	account.ActivityHistoryTypeSale,
+}
+
 func (im *impl) GetActivities(c ctx.Ctx, address domain.Address, optFns ...account.FindActivityHistoryOptions) (*account.ActivityResult, error) {
+	return im.findActivities(c, account.ActivityHistoryWithAccount(address), optFns...)
+}
+
+// findActivities finds account activities of the accounts matched by accountOpt
+func (im *impl) findActivities(c ctx.Ctx, accountOpt account.FindActivityHistoryOptions, optFns ...account.FindActivityHistoryOptions) (*account.ActivityResult, error) {
 	activityOpts := append(
 		[]account.FindActivityHistoryOptions{
-			account.ActivityHistoryWithTypes(
-				account.ActivityHistoryTypeCreateOffer,
-				account.ActivityHistoryTypeCancelOffer,
-				account.ActivityHistoryTypeList,
-				account.ActivityHistoryTypeCancelListing,
-				account.ActivityHistoryTypePlaceBid,
-				account.ActivityHistoryTypeBuy,
-				account.ActivityHistoryTypeSold,
-				account.ActivityHistoryTypeTransfer,
-				account.ActivityHistoryTypeMint,
-				account.ActivityHistoryTypeSale,
-			),
+			account.ActivityHistoryWithTypes(accountActivityTypes...),
 		},
 		optFns...,
 	)
 
-	activityOpts = append(activityOpts, account.ActivityHistoryWithAccount(address))
+	activityOpts = append(activityOpts, accountOpt)
 
 	res := &account.ActivityResult{}
 
@@ -436,12 +516,35 @@ func (im *impl) GetActivities(c ctx.Ctx, address domain.Address, optFns ...accou
 		return nil, err
 	}
 
-	count, err := im.activityRepo.CountActivities(c, activityOpts...)
+	shouldCount, err := account.ShouldCountActivityHistories(activityOpts...)
 	if err != nil {
-		c.WithField("err", err).Error("activityRepo.CountActivities")
+		c.WithField("err", err).Error("account.ShouldCountActivityHistories failed")
 		return nil, err
 	}
 
+	if shouldCount {
+		res.Count, err = im.activityRepo.CountActivities(c, activityOpts...)
+		if err != nil {
+			c.WithField("err", err).Error("activityRepo.CountActivities")
+			return nil, err
+		}
+	}
+
+	res.Activities = im.toActivities(c, activities)
+
+	// cursor points to the last found activity, including the ones skipped above
+	res.NextCursor, err = account.NextActivityHistoryCursor(activities, activityOpts...)
+	if err != nil {
+		c.WithField("err", err).Error("account.NextActivityHistoryCursor failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// toActivities enriches activity histories with tokens and accounts, histories of unknown tokens or types are skipped
+func (im *impl) toActivities(c ctx.Ctx, activities []account.ActivityHistory) []*account.Activity {
+	var res []*account.Activity
 	for _, act := range activities {
 		token, err := im.getSimpleToken(c, act.ChainId, act.ContractAddress, act.TokenId)
 		if err != nil {
@@ -463,61 +566,65 @@ func (im *impl) GetActivities(c ctx.Ctx, address domain.Address, optFns ...accou
 		if a.Type == account.ActivityTypeTransfer || a.Type == account.ActivityTypeMint || a.Type == account.ActivityTypeSale2 {
 			a.To = im.getSimpleAccount(c, act.To)
 		}
-		res.Activities = append(res.Activities, a)
+		res = append(res, a)
 	}
 
-	res.Count = count
-
-	return res, nil
+	simpleAccounts := []*account.SimpleAccount{}
+	for _, a := range res {
+		simpleAccounts = append(simpleAccounts, &a.Owner)
+		if a.To.Address != "" {
+			simpleAccounts = append(simpleAccounts, &a.To)
+		}
+	}
+	im.enrichSimpleAccounts(c, simpleAccounts)
+	return res
 }
 
 func (im *impl) GetAccountStat(c ctx.Ctx, address domain.Address) (*account.AccountStat, error) {
+	return im.accountStat(c, []domain.Address{address})
+}
+
+// accountStat sums stats of addresses, collections held by more than one of them are counted once
+func (im *impl) accountStat(c ctx.Ctx, addresses []domain.Address) (*account.AccountStat, error) {
 	res := &account.AccountStat{}
+	countedCollection := map[string]bool{}
 
-	if count, err := im.like.GetLikedCount(c, address); err != nil {
-		c.WithField("err", err).Error("like.GetLikedCount failed")
-		return nil, err
-	} else {
-		res.Favorite = int32(count)
-	}
+	for _, address := range addresses {
+		if count, err := im.like.GetLikedCount(c, address); err != nil {
+			c.WithField("err", err).Error("like.GetLikedCount failed")
+			return nil, err
+		} else {
+			res.Favorite += int32(count)
+		}
 
-	if items, err := im.nftitem.FindAll(c, nftitem.WithOwner(address)); err != nil {
-		c.WithField("err", err).Error("nftitem.FindAll failed")
-		return nil, err
-	} else {
-		countOfNfts := int32(0)
-		countOfCollections := int32(0)
-		countedCollection := map[string]bool{}
-
-		for _, item := range items {
-			countOfNfts += 1
-			collection := strconv.Itoa(int(item.ChainId)) + ":" + string(item.ContractAddress)
-			if _, ok := countedCollection[collection]; !ok {
-				countedCollection[collection] = true
-				countOfCollections += 1
+		if items, err := im.nftitem.FindAll(c, nftitem.WithOwner(address)); err != nil {
+			c.WithField("err", err).Error("nftitem.FindAll failed")
+			return nil, err
+		} else {
+			for _, item := range items {
+				res.Single += 1
+				collection := strconv.Itoa(int(item.ChainId)) + ":" + string(item.ContractAddress)
+				if _, ok := countedCollection[collection]; !ok {
+					countedCollection[collection] = true
+					res.Collections += 1
+				}
 			}
 		}
 
-		res.Single = countOfNfts
-		res.Collections = countOfCollections
-	}
-
-	if cols, err := im.collection.FindAll(c, collection.WithOwner(address)); err != nil {
-		c.WithField("err", err).Error("collection.FindAll failed")
-		return nil, err
-	} else {
-		countOfNfts := int32(0)
-
-		for _, col := range cols.Items {
-			if cnt, err := im.nftitem.Count(c, nftitem.WithChainId(col.ChainId), nftitem.WithContractAddresses([]domain.Address{col.Erc721Address})); err != nil {
-				c.WithFields(log.Fields{"err": err, "collection": col}).Error("nftitem.Count failed")
-			} else {
-				countOfNfts += int32(cnt)
+		if cols, err := im.collection.FindAll(c, collection.WithOwner(address)); err != nil {
+			c.WithField("err", err).Error("collection.FindAll failed")
+			return nil, err
+		} else {
+			for _, col := range cols.Items {
+				if cnt, err := im.nftitem.Count(c, nftitem.WithChainId(col.ChainId), nftitem.WithContractAddresses([]domain.Address{col.Erc721Address})); err != nil {
+					c.WithFields(log.Fields{"err": err, "collection": col}).Error("nftitem.Count failed")
+				} else {
+					res.CreatedNfts += int32(cnt)
+				}
 			}
-		}
 
-		res.CreatedNfts = countOfNfts
-		res.CreatedCollections = int32(cols.Count)
+			res.CreatedCollections += int32(cols.Count)
+		}
 	}
 
 	return res, nil
@@ -606,3 +713,16 @@ func (im *impl) accountToInfo(c ctx.Ctx, a *account.Account) (*account.Info, err
 
 	return info.Sanitized(), nil
 }
+
+// indexAccount refreshes the account in search index, failure is ignored as index will be synced later
+func (im *impl) indexAccount(c ctx.Ctx, address domain.Address) {
+	if im.searchIdx == nil {
+		return
+	}
+	if err := im.searchIdx.IndexAccount(c, address); err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Warn("searchIdx.IndexAccount failed")
+	}
+}
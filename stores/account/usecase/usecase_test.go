@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+)
+
+func TestGetActivitiesCount(t *testing.T) {
+	address := domain.Address("0x0000000000000000000000000000000000000001")
+
+	cases := []struct {
+		name      string
+		opts      []account.FindActivityHistoryOptions
+		wantCount int
+	}{
+		{"offset paging", []account.FindActivityHistoryOptions{account.ActivityHistoryWithPagination(0, 10)}, 42},
+		{"first cursor page", []account.FindActivityHistoryOptions{account.ActivityHistoryWithCursor("")}, 42},
+		{"next cursor page", []account.FindActivityHistoryOptions{account.ActivityHistoryWithCursor("next")}, 0},
+	}
+
+	for _, tc := range cases {
+		activityRepo := &mAccount.ActivityHistoryRepo{}
+		activityRepo.On("FindActivities", mock.Anything,
+			mock.AnythingOfType("account.FindActivityHistoryOptions"),
+			mock.AnythingOfType("account.FindActivityHistoryOptions"),
+			mock.AnythingOfType("account.FindActivityHistoryOptions")).Return(nil, nil).Once()
+		if tc.wantCount > 0 {
+			activityRepo.On("CountActivities", mock.Anything,
+				mock.AnythingOfType("account.FindActivityHistoryOptions"),
+				mock.AnythingOfType("account.FindActivityHistoryOptions"),
+				mock.AnythingOfType("account.FindActivityHistoryOptions")).Return(tc.wantCount, nil).Once()
+		}
+		im := &impl{activityRepo: activityRepo}
+
+		res, err := im.GetActivities(ctx.Background(), address, tc.opts...)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.wantCount, res.Count, tc.name)
+		activityRepo.AssertExpectations(t)
+	}
+}
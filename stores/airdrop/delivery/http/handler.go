@@ -1,24 +1,33 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	bCtx "github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/delivery"
 	"github.com/x-xyz/goapi/domain"
 	dAirdrop "github.com/x-xyz/goapi/domain/airdrop"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 )
 
 type handler struct {
 	airdrop dAirdrop.AirdropUseCase
 	proof   dAirdrop.ProofUseCase
+	builder dAirdrop.AirdropBuilderUseCase
 }
 
-func New(e *echo.Echo, _airdrop dAirdrop.AirdropUseCase, _proof dAirdrop.ProofUseCase) {
-	h := &handler{_airdrop, _proof}
+func New(e *echo.Echo, _airdrop dAirdrop.AirdropUseCase, _proof dAirdrop.ProofUseCase, _builder dAirdrop.AirdropBuilderUseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{_airdrop, _proof, _builder}
 	e.GET("/airdrops", h.getAirdrops)
+	e.GET("/airdrops/rounds", h.getRounds)
+	e.POST("/airdrops/rounds", h.buildRound, authMiddleware.Auth(), authMiddleware.IsAdmin())
 	e.GET("/proofs", h.getProofs)
+	e.POST("/proofs/verify", h.verifyProof)
 }
 
 func (h *handler) getAirdrops(_ctx echo.Context) error {
@@ -60,6 +69,7 @@ func (h *handler) getProofs(_ctx echo.Context) error {
 		ChainId         *domain.ChainId `query:"chainId"`
 		ContractAddress *domain.Address `query:"contractAddress"`
 		Claimer         *domain.Address `query:"claimer"`
+		Round           *int            `query:"round"`
 	}
 
 	p := &params{}
@@ -82,6 +92,9 @@ func (h *handler) getProofs(_ctx echo.Context) error {
 	if p.Claimer != nil {
 		opts = append(opts, dAirdrop.ProofWithClaimer(*p.Claimer))
 	}
+	if p.Round != nil {
+		opts = append(opts, dAirdrop.ProofWithRound(*p.Round))
+	}
 
 	res, err := h.proof.FindAll(ctx, opts...)
 	if err != nil {
@@ -90,3 +103,144 @@ func (h *handler) getProofs(_ctx echo.Context) error {
 		return delivery.MakeJsonResp(_ctx, http.StatusOK, res)
 	}
 }
+
+func (h *handler) getRounds(_ctx echo.Context) error {
+	ctx := _ctx.Get("ctx").(bCtx.Ctx)
+	type params struct {
+		Offset          int32           `query:"offset"`
+		Limit           int32           `query:"limit"`
+		ChainId         *domain.ChainId `query:"chainId"`
+		ContractAddress *domain.Address `query:"contractAddress"`
+	}
+
+	p := &params{}
+	if err := _ctx.Bind(p); err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, "invalid params")
+	}
+
+	opts := []dAirdrop.AirdropRoundFindAllOptionsFunc{
+		dAirdrop.AirdropRoundWithPagination(p.Offset, p.Limit),
+		dAirdrop.AirdropRoundWithStatus(dAirdrop.RoundStatusReady),
+	}
+	if p.ChainId != nil {
+		opts = append(opts, dAirdrop.AirdropRoundWithChainId(*p.ChainId))
+	}
+	if p.ContractAddress != nil {
+		opts = append(opts, dAirdrop.AirdropRoundWithContractAddress(*p.ContractAddress))
+	}
+
+	res, err := h.builder.FindRounds(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusInternalServerError, err)
+	} else {
+		return delivery.MakeJsonResp(_ctx, http.StatusOK, res)
+	}
+}
+
+// buildRound accepts BuildParams in json, or a multipart form with the csv in `allocations` file
+// and the other params in form fields
+func (h *handler) buildRound(_ctx echo.Context) error {
+	ctx := _ctx.Get("ctx").(bCtx.Ctx)
+
+	p := dAirdrop.BuildParams{}
+	if strings.HasPrefix(_ctx.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if err := bindBuildForm(_ctx, &p); err != nil {
+			return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, err)
+		}
+	} else if err := _ctx.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, "invalid params")
+	}
+
+	res, err := h.builder.Build(ctx, p)
+	if errors.Is(err, domain.ErrBadParamInput) ||
+		errors.Is(err, domain.ErrInvalidNumberFormat) ||
+		errors.Is(err, dAirdrop.ErrInvalidEncoding) ||
+		errors.Is(err, dAirdrop.ErrNoAllocation) {
+		return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, err)
+	} else if errors.Is(err, dAirdrop.ErrRoundExists) {
+		return delivery.MakeJsonResp(_ctx, http.StatusConflict, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(_ctx, http.StatusCreated, res)
+}
+
+func bindBuildForm(_ctx echo.Context, p *dAirdrop.BuildParams) error {
+	type form struct {
+		ChainId         domain.ChainId            `form:"chainId"`
+		ContractAddress domain.Address            `form:"contractAddress"`
+		Source          dAirdrop.AllocationSource `form:"source"`
+		// json encoded LeafEncoding
+		Encoding string `form:"encoding"`
+	}
+
+	f := &form{}
+	if err := _ctx.Bind(f); err != nil {
+		return domain.ErrBadParamInput
+	}
+	p.ChainId = f.ChainId
+	p.ContractAddress = f.ContractAddress
+	p.Source = f.Source
+	if f.Encoding != "" {
+		p.Encoding = &dAirdrop.LeafEncoding{}
+		if err := json.Unmarshal([]byte(f.Encoding), p.Encoding); err != nil {
+			return domain.ErrBadParamInput
+		}
+	}
+
+	var err error
+	if p.Begin, err = formTime(_ctx, "begin"); err != nil {
+		return err
+	}
+	if p.End, err = formTime(_ctx, "end"); err != nil {
+		return err
+	}
+
+	file, err := _ctx.FormFile("allocations")
+	if err == http.ErrMissingFile {
+		return nil
+	} else if err != nil {
+		return domain.ErrBadParamInput
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	p.Allocations, err = dAirdrop.ParseAllocations(src)
+	return err
+}
+
+// formTime parses RFC3339 form value, returns nil if it's empty
+func formTime(_ctx echo.Context, name string) (*time.Time, error) {
+	v := _ctx.FormValue(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, domain.ErrBadParamInput
+	}
+	return &t, nil
+}
+
+func (h *handler) verifyProof(_ctx echo.Context) error {
+	ctx := _ctx.Get("ctx").(bCtx.Ctx)
+
+	p := dAirdrop.VerifyParams{}
+	if err := _ctx.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, "invalid params")
+	}
+
+	ok, err := h.builder.Verify(ctx, p)
+	if errors.Is(err, domain.ErrBadParamInput) || errors.Is(err, domain.ErrInvalidNumberFormat) {
+		return delivery.MakeJsonResp(_ctx, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(_ctx, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(_ctx, http.StatusOK, map[string]bool{"valid": ok})
+}
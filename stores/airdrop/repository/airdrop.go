@@ -44,6 +44,12 @@ func (r *airdropRepoImpl) FindAll(ctx bCtx.Ctx, optFns ...airdrop.AirdropFindAll
 	if opts.DeadlineAfter != nil {
 		query["deadline"] = bson.M{"$gt": opts.DeadlineAfter}
 	}
+	if opts.ChainId != nil {
+		query["chainId"] = *opts.ChainId
+	}
+	if opts.ContractAddress != nil {
+		query["contractAddress"] = *opts.ContractAddress
+	}
 
 	airdrops := []airdrop.Airdrop{}
 	if err := r.q.Search(ctx, domain.TableAirdrops, offset, limit, sort, query, &airdrops); err != nil {
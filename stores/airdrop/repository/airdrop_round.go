@@ -0,0 +1,72 @@
+package repository
+
+import (
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/airdrop"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type airdropRoundRepoImpl struct {
+	q query.Mongo
+}
+
+func NewAirdropRoundRepo(q query.Mongo) airdrop.AirdropRoundRepo {
+	return &airdropRoundRepoImpl{q: q}
+}
+
+func (r *airdropRoundRepoImpl) FindAll(ctx bCtx.Ctx, optFns ...airdrop.AirdropRoundFindAllOptionsFunc) ([]airdrop.AirdropRound, error) {
+	opts, err := airdrop.GetAirdropRoundFindAllOptions(optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("airdrop.GetAirdropRoundFindAllOptions failed")
+		return nil, err
+	}
+
+	var (
+		offset int    = 0
+		limit  int    = 0
+		sort   string = "-round"
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+	if opts.SortBy != nil && opts.SortDir != nil {
+		sort = *opts.SortBy
+		if *opts.SortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+
+	query, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return nil, err
+	}
+
+	rounds := []airdrop.AirdropRound{}
+	if err := r.q.Search(ctx, domain.TableAirdropRounds, offset, limit, sort, query, &rounds); err != nil {
+		ctx.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return rounds, nil
+}
+
+func (r *airdropRoundRepoImpl) Upsert(ctx bCtx.Ctx, a *airdrop.AirdropRound) error {
+	copy := *a
+	copy.ContractAddress = a.ContractAddress.ToLower()
+	selector := bson.M{
+		"chainId":         copy.ChainId,
+		"contractAddress": copy.ContractAddress,
+		"round":           copy.Round,
+	}
+	if err := r.q.Upsert(ctx, domain.TableAirdropRounds, selector, &copy); err != nil {
+		ctx.WithField("err", err).Error("q.Upsert failed")
+		return err
+	}
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/airdrop"
 	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
+const proofBulkSize = 1000
+
 type proofRepoImpl struct {
 	q query.Mongo
 }
@@ -63,6 +66,7 @@ func (r *proofRepoImpl) Create(ctx bCtx.Ctx, a *airdrop.Proof) error {
 		Round:           a.Round,
 		Amount:          a.Amount,
 		Proof:           a.Proof,
+		Index:           a.Index,
 	}
 	if err := r.q.Insert(ctx, domain.TableProofs, copy); err != nil {
 		ctx.WithField("err", err).Error("q.Insert failed")
@@ -70,3 +74,58 @@ func (r *proofRepoImpl) Create(ctx bCtx.Ctx, a *airdrop.Proof) error {
 	}
 	return nil
 }
+
+func (r *proofRepoImpl) BulkUpsert(ctx bCtx.Ctx, proofs []airdrop.Proof) error {
+	for begin := 0; begin < len(proofs); begin += proofBulkSize {
+		end := begin + proofBulkSize
+		if end > len(proofs) {
+			end = len(proofs)
+		}
+
+		ops := make([]query.UpsertOp, 0, end-begin)
+		for _, p := range proofs[begin:end] {
+			p.ContractAddress = p.ContractAddress.ToLower()
+			p.Claimer = p.Claimer.ToLower()
+			ops = append(ops, query.UpsertOp{
+				Selector: bson.M{
+					"chainId":         p.ChainId,
+					"contractAddress": p.ContractAddress,
+					"claimer":         p.Claimer,
+					"round":           p.Round,
+				},
+				Updater: p,
+			})
+		}
+
+		if _, _, err := r.q.BulkUpsert(ctx, domain.TableProofs, ops); err != nil {
+			ctx.WithField("err", err).Error("q.BulkUpsert failed")
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *proofRepoImpl) RemoveAll(ctx bCtx.Ctx, optFns ...airdrop.ProofFindAllOptionsFunc) error {
+	opts, err := airdrop.GetProofFindAllOptions(optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("proof.GetProofFindAllOptions failed")
+		return err
+	}
+
+	query, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return err
+	}
+
+	// never wipe the whole table
+	if len(query) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	if _, err := r.q.RemoveAll(ctx, domain.TableProofs, query); err != nil {
+		ctx.WithField("err", err).Error("q.RemoveAll failed")
+		return err
+	}
+	return nil
+}
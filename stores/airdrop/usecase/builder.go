@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/airdrop"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	"github.com/x-xyz/goapi/service/merkle"
+)
+
+type AirdropBuilderUseCaseCfg struct {
+	AirdropRepo     airdrop.AirdropRepo
+	ProofRepo       airdrop.ProofRepo
+	RoundRepo       airdrop.AirdropRoundRepo
+	CollPromotionUC collection_promotion.CollPromotionUsecase
+	ListingRewardUC collection_promotion.ListingRewardUseCase
+}
+
+type airdropBuilderUseCaseImpl struct {
+	airdropRepo     airdrop.AirdropRepo
+	proofRepo       airdrop.ProofRepo
+	roundRepo       airdrop.AirdropRoundRepo
+	collPromotionUC collection_promotion.CollPromotionUsecase
+	listingRewardUC collection_promotion.ListingRewardUseCase
+}
+
+func NewAirdropBuilderUseCase(cfg *AirdropBuilderUseCaseCfg) airdrop.AirdropBuilderUseCase {
+	return &airdropBuilderUseCaseImpl{
+		airdropRepo:     cfg.AirdropRepo,
+		proofRepo:       cfg.ProofRepo,
+		roundRepo:       cfg.RoundRepo,
+		collPromotionUC: cfg.CollPromotionUC,
+		listingRewardUC: cfg.ListingRewardUC,
+	}
+}
+
+type claim struct {
+	claimer domain.Address
+	amount  *big.Int
+}
+
+func (u *airdropBuilderUseCaseImpl) Build(ctx bCtx.Ctx, params airdrop.BuildParams) (*airdrop.AirdropRound, error) {
+	encoding := airdrop.DefaultLeafEncoding
+	if params.Encoding != nil {
+		encoding = *params.Encoding
+	}
+	if err := encoding.Validate(); err != nil {
+		return nil, err
+	}
+
+	airdrops, err := u.airdropRepo.FindAll(ctx,
+		airdrop.AirdropWithChainId(params.ChainId),
+		airdrop.AirdropWithContractAddress(params.ContractAddress),
+	)
+	if err != nil {
+		ctx.WithField("err", err).Error("airdropRepo.FindAll failed")
+		return nil, err
+	} else if len(airdrops) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	drop := airdrops[0]
+
+	allocations, err := u.allocate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, total, err := mergeAllocations(allocations)
+	if err != nil {
+		return nil, err
+	} else if len(claims) == 0 {
+		return nil, airdrop.ErrNoAllocation
+	}
+
+	round, err := u.nextRound(ctx, drop)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([][]byte, len(claims))
+	for i, c := range claims {
+		if leaves[i], err = encoding.Leaf(i, round, c.claimer, c.amount); err != nil {
+			ctx.WithFields(log.Fields{
+				"err":     err,
+				"claimer": c.claimer,
+			}).Error("encoding.Leaf failed")
+			return nil, err
+		}
+	}
+
+	tree, err := merkle.New(leaves)
+	if err != nil {
+		ctx.WithField("err", err).Error("merkle.New failed")
+		return nil, err
+	}
+
+	proofs := make([]airdrop.Proof, len(claims))
+	for i, c := range claims {
+		hashes, err := tree.Proof(i)
+		if err != nil {
+			ctx.WithField("err", err).Error("tree.Proof failed")
+			return nil, err
+		}
+		proof := make([]string, len(hashes))
+		for j, h := range hashes {
+			proof[j] = hexutil.Encode(h)
+		}
+		proofs[i] = airdrop.Proof{
+			ChainId:         drop.ChainId,
+			ContractAddress: drop.ContractAddress,
+			Claimer:         c.claimer,
+			Round:           round,
+			Index:           i,
+			Amount:          c.amount.String(),
+			Proof:           proof,
+		}
+	}
+
+	res := &airdrop.AirdropRound{
+		ChainId:         drop.ChainId,
+		ContractAddress: drop.ContractAddress.ToLower(),
+		Round:           round,
+		Root:            hexutil.Encode(tree.Root()),
+		Encoding:        encoding,
+		Source:          params.Source,
+		ClaimerCount:    len(claims),
+		TotalAmount:     total.String(),
+		Status:          airdrop.RoundStatusBuilding,
+		CreatedAt:       time.Now(),
+	}
+	if err := u.roundRepo.Upsert(ctx, res); err != nil {
+		ctx.WithField("err", err).Error("roundRepo.Upsert failed")
+		return nil, err
+	}
+
+	// clean up leftovers of a failed build of the same round
+	if err := u.proofRepo.RemoveAll(ctx,
+		airdrop.ProofWithChainId(drop.ChainId),
+		airdrop.ProofWithContractAddress(drop.ContractAddress),
+		airdrop.ProofWithRound(round),
+	); err != nil {
+		ctx.WithField("err", err).Error("proofRepo.RemoveAll failed")
+		return nil, err
+	}
+
+	if err := u.proofRepo.BulkUpsert(ctx, proofs); err != nil {
+		ctx.WithField("err", err).Error("proofRepo.BulkUpsert failed")
+		return nil, err
+	}
+
+	res.Status = airdrop.RoundStatusReady
+	if err := u.roundRepo.Upsert(ctx, res); err != nil {
+		ctx.WithField("err", err).Error("roundRepo.Upsert failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *airdropBuilderUseCaseImpl) allocate(ctx bCtx.Ctx, params airdrop.BuildParams) ([]airdrop.Allocation, error) {
+	var (
+		distribution *collection_promotion.ListingRewardDistribution
+		err          error
+	)
+
+	switch params.Source {
+	case airdrop.AllocationSourceCsv:
+		return params.Allocations, nil
+	case airdrop.AllocationSourceRewardLedger:
+		return u.allocateRewardLedger(ctx, params.DistributionId)
+	case airdrop.AllocationSourceListingRecords:
+		if params.Begin == nil || params.End == nil {
+			return nil, domain.ErrBadParamInput
+		}
+		distribution, err = u.collPromotionUC.CalculateListingRewardsFlat(ctx, *params.Begin, *params.End)
+	case airdrop.AllocationSourcePromotion:
+		if params.Begin == nil || params.End == nil {
+			return nil, domain.ErrBadParamInput
+		}
+		distribution, err = u.collPromotionUC.CalculateListingRewardsFixedTotal(ctx, *params.Begin, *params.End)
+	default:
+		return nil, domain.ErrBadParamInput
+	}
+
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":    err,
+			"source": params.Source,
+			"begin":  params.Begin,
+			"end":    params.End,
+		}).Error("calculate listing rewards failed")
+		return nil, err
+	}
+
+	res := make([]airdrop.Allocation, 0, len(distribution.Rewards))
+	for claimer, amount := range distribution.Rewards {
+		res = append(res, airdrop.Allocation{Claimer: claimer, Amount: amount})
+	}
+	return res, nil
+}
+
+func (u *airdropBuilderUseCaseImpl) allocateRewardLedger(ctx bCtx.Ctx, distributionId string) ([]airdrop.Allocation, error) {
+	if distributionId == "" {
+		return nil, domain.ErrBadParamInput
+	}
+
+	// the ledger is complete once the distribution is recorded
+	distributions, err := u.listingRewardUC.FindDistributions(ctx, collection_promotion.RewardDistributionWithId(distributionId))
+	if err != nil {
+		ctx.WithField("err", err).Error("listingRewardUC.FindDistributions failed")
+		return nil, err
+	} else if len(distributions) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	entries, err := u.listingRewardUC.FindLedger(ctx, collection_promotion.RewardLedgerWithDistributionId(distributionId))
+	if err != nil {
+		ctx.WithField("err", err).Error("listingRewardUC.FindLedger failed")
+		return nil, err
+	}
+
+	res := make([]airdrop.Allocation, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, airdrop.Allocation{Claimer: e.Owner, Amount: e.Amount})
+	}
+	return res, nil
+}
+
+// mergeAllocations sums amounts of the same claimer, drops zero amounts and sorts by claimer, so rebuilding the
+// same allocations always gets the same tree
+func mergeAllocations(allocations []airdrop.Allocation) ([]claim, *big.Int, error) {
+	amounts := map[domain.Address]*big.Int{}
+	for _, a := range allocations {
+		amount, ok := new(big.Int).SetString(a.Amount, 10)
+		if !ok || amount.Sign() < 0 {
+			return nil, nil, domain.ErrInvalidNumberFormat
+		}
+		claimer := a.Claimer.ToLower()
+		if amounts[claimer] == nil {
+			amounts[claimer] = new(big.Int)
+		}
+		amounts[claimer].Add(amounts[claimer], amount)
+	}
+
+	total := new(big.Int)
+	claims := []claim{}
+	for claimer, amount := range amounts {
+		if amount.Sign() == 0 {
+			continue
+		}
+		total.Add(total, amount)
+		claims = append(claims, claim{claimer, amount})
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].claimer < claims[j].claimer
+	})
+
+	return claims, total, nil
+}
+
+// nextRound returns the round to build. A round left building by a failed build is rebuilt, otherwise it's 0 for
+// AirdropTypeOnce and the round after the latest for AirdropTypeRound. Proofs built before rounds were recorded are
+// taken into account as well.
+func (u *airdropBuilderUseCaseImpl) nextRound(ctx bCtx.Ctx, drop airdrop.Airdrop) (int, error) {
+	rounds, err := u.roundRepo.FindAll(ctx,
+		airdrop.AirdropRoundWithChainId(drop.ChainId),
+		airdrop.AirdropRoundWithContractAddress(drop.ContractAddress),
+		airdrop.AirdropRoundWithSort("round", domain.SortDirDesc),
+		airdrop.AirdropRoundWithPagination(0, 1),
+	)
+	if err != nil {
+		ctx.WithField("err", err).Error("roundRepo.FindAll failed")
+		return 0, err
+	}
+
+	if len(rounds) > 0 {
+		latest := rounds[0]
+		if latest.Status == airdrop.RoundStatusBuilding {
+			return latest.Round, nil
+		}
+		if drop.Type == airdrop.AirdropTypeOnce {
+			return 0, airdrop.ErrRoundExists
+		}
+		return latest.Round + 1, nil
+	}
+
+	proofs, err := u.proofRepo.FindAll(ctx,
+		airdrop.ProofWithChainId(drop.ChainId),
+		airdrop.ProofWithContractAddress(drop.ContractAddress),
+		airdrop.ProofWithSort("round", domain.SortDirDesc),
+		airdrop.ProofWithPagination(0, 1),
+	)
+	if err != nil {
+		ctx.WithField("err", err).Error("proofRepo.FindAll failed")
+		return 0, err
+	}
+
+	if drop.Type == airdrop.AirdropTypeOnce {
+		if len(proofs) > 0 {
+			return 0, airdrop.ErrRoundExists
+		}
+		return 0, nil
+	}
+
+	if len(proofs) > 0 {
+		return proofs[0].Round + 1, nil
+	}
+	return 1, nil
+}
+
+func (u *airdropBuilderUseCaseImpl) Verify(ctx bCtx.Ctx, params airdrop.VerifyParams) (bool, error) {
+	rounds, err := u.roundRepo.FindAll(ctx,
+		airdrop.AirdropRoundWithChainId(params.ChainId),
+		airdrop.AirdropRoundWithContractAddress(params.ContractAddress),
+		airdrop.AirdropRoundWithRound(params.Round),
+		airdrop.AirdropRoundWithStatus(airdrop.RoundStatusReady),
+	)
+	if err != nil {
+		ctx.WithField("err", err).Error("roundRepo.FindAll failed")
+		return false, err
+	} else if len(rounds) == 0 {
+		return false, domain.ErrNotFound
+	}
+	round := rounds[0]
+
+	amount, ok := new(big.Int).SetString(params.Amount, 10)
+	if !ok {
+		return false, domain.ErrInvalidNumberFormat
+	}
+
+	proof := make([][]byte, len(params.Proof))
+	for i, p := range params.Proof {
+		if proof[i], err = hexutil.Decode(p); err != nil {
+			return false, domain.ErrBadParamInput
+		}
+	}
+
+	root, err := hexutil.Decode(round.Root)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"round": round,
+		}).Error("hexutil.Decode failed")
+		return false, err
+	}
+
+	leaf, err := round.Encoding.Leaf(params.Index, round.Round, params.Claimer.ToLower(), amount)
+	if err != nil {
+		return false, err
+	}
+
+	return merkle.Verify(root, leaf, proof), nil
+}
+
+func (u *airdropBuilderUseCaseImpl) FindRounds(ctx bCtx.Ctx, optFns ...airdrop.AirdropRoundFindAllOptionsFunc) ([]airdrop.AirdropRound, error) {
+	rounds, err := u.roundRepo.FindAll(ctx, optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("roundRepo.FindAll failed")
+		return nil, err
+	}
+	return rounds, nil
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/airdrop"
+	mAirdrop "github.com/x-xyz/goapi/domain/airdrop/mocks"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	mCollPromotion "github.com/x-xyz/goapi/domain/collection_promotion/mocks"
+)
+
+type BuilderSuite struct {
+	suite.Suite
+	ctx         bCtx.Ctx
+	airdropRepo *mAirdrop.AirdropRepo
+	proofRepo   *mAirdrop.ProofRepo
+	roundRepo   *mAirdrop.AirdropRoundRepo
+	rewardUC    *mCollPromotion.ListingRewardUseCase
+	im          airdrop.AirdropBuilderUseCase
+}
+
+func TestBuilderSuite(t *testing.T) {
+	suite.Run(t, new(BuilderSuite))
+}
+
+func (s *BuilderSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.airdropRepo = &mAirdrop.AirdropRepo{}
+	s.proofRepo = &mAirdrop.ProofRepo{}
+	s.roundRepo = &mAirdrop.AirdropRoundRepo{}
+	s.rewardUC = &mCollPromotion.ListingRewardUseCase{}
+	s.im = NewAirdropBuilderUseCase(&AirdropBuilderUseCaseCfg{
+		AirdropRepo:     s.airdropRepo,
+		ProofRepo:       s.proofRepo,
+		RoundRepo:       s.roundRepo,
+		ListingRewardUC: s.rewardUC,
+	})
+}
+
+func (s *BuilderSuite) TearDownTest() {
+	s.airdropRepo.AssertExpectations(s.T())
+	s.proofRepo.AssertExpectations(s.T())
+	s.roundRepo.AssertExpectations(s.T())
+	s.rewardUC.AssertExpectations(s.T())
+}
+
+func (s *BuilderSuite) mockAirdrop(typ airdrop.AirdropType) {
+	s.airdropRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("airdrop.AirdropFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.AirdropFindAllOptionsFunc")).
+		Return([]airdrop.Airdrop{{ChainId: 1, ContractAddress: "0xdrop", Type: typ}}, nil).Once()
+}
+
+// mockRounds mocks the rounds found by chain, contract and 2 more options, i.e. the latest round or a round to verify
+func (s *BuilderSuite) mockRounds(rounds []airdrop.AirdropRound) *mock.Call {
+	return s.roundRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("airdrop.AirdropRoundFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.AirdropRoundFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.AirdropRoundFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.AirdropRoundFindAllOptionsFunc")).
+		Return(rounds, nil).Once()
+}
+
+func (s *BuilderSuite) mockLatestProofs(proofs []airdrop.Proof) {
+	s.proofRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc")).
+		Return(proofs, nil).Once()
+}
+
+// mockStore mocks storing a round, the stored proofs are returned
+func (s *BuilderSuite) mockStore() *[]airdrop.Proof {
+	proofs := []airdrop.Proof{}
+	// building, then ready
+	s.roundRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*airdrop.AirdropRound")).Return(nil).Twice()
+	s.proofRepo.On("RemoveAll", mock.Anything,
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc"),
+		mock.AnythingOfType("airdrop.ProofFindAllOptionsFunc")).
+		Return(nil).Once()
+	s.proofRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]airdrop.Proof")).
+		Run(func(args mock.Arguments) { proofs = args.Get(1).([]airdrop.Proof) }).
+		Return(nil).Once()
+	return &proofs
+}
+
+var allocations = []airdrop.Allocation{
+	{Claimer: "0x0000000000000000000000000000000000000003", Amount: "300"},
+	{Claimer: "0x0000000000000000000000000000000000000001", Amount: "100"},
+	{Claimer: "0x0000000000000000000000000000000000000002", Amount: "200"},
+	{Claimer: "0x0000000000000000000000000000000000000001", Amount: "50"},
+	{Claimer: "0x0000000000000000000000000000000000000004", Amount: "0"},
+}
+
+func (s *BuilderSuite) build() (*airdrop.AirdropRound, error) {
+	return s.im.Build(s.ctx, airdrop.BuildParams{
+		ChainId:         1,
+		ContractAddress: "0xdrop",
+		Source:          airdrop.AllocationSourceCsv,
+		Allocations:     allocations,
+		Encoding: &airdrop.LeafEncoding{
+			Fields: []airdrop.LeafField{airdrop.LeafFieldIndex, airdrop.LeafFieldClaimer, airdrop.LeafFieldAmount, airdrop.LeafFieldRound},
+		},
+	})
+}
+
+func (s *BuilderSuite) TestBuildAndVerify() {
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.mockRounds(nil)
+	s.mockLatestProofs(nil)
+	proofs := s.mockStore()
+
+	round, err := s.build()
+	s.NoError(err)
+	s.Equal(1, round.Round)
+	s.Equal(3, round.ClaimerCount)
+	s.Equal("650", round.TotalAmount)
+	s.Equal(airdrop.RoundStatusReady, round.Status)
+	s.Len(*proofs, 3)
+
+	s.mockRounds([]airdrop.AirdropRound{*round}).Times(len(*proofs) + 1)
+	for _, p := range *proofs {
+		ok, err := s.im.Verify(s.ctx, airdrop.VerifyParams{
+			ChainId:         1,
+			ContractAddress: "0xdrop",
+			Round:           p.Round,
+			Index:           p.Index,
+			Claimer:         p.Claimer,
+			Amount:          p.Amount,
+			Proof:           p.Proof,
+		})
+		s.NoError(err)
+		s.True(ok, p.Claimer)
+	}
+
+	// duplicated claimer is merged
+	p := (*proofs)[0]
+	s.Equal("150", p.Amount)
+
+	ok, err := s.im.Verify(s.ctx, airdrop.VerifyParams{
+		ChainId:         1,
+		ContractAddress: "0xdrop",
+		Round:           p.Round,
+		Index:           p.Index,
+		Claimer:         p.Claimer,
+		Amount:          "151",
+		Proof:           p.Proof,
+	})
+	s.NoError(err)
+	s.False(ok)
+
+	s.mockRounds(nil)
+	_, err = s.im.Verify(s.ctx, airdrop.VerifyParams{ChainId: 1, ContractAddress: "0xdrop", Round: 2, Amount: "1"})
+	s.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (s *BuilderSuite) TestRounds() {
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.mockRounds([]airdrop.AirdropRound{{Round: 1, Status: airdrop.RoundStatusReady}})
+	s.mockStore()
+
+	round, err := s.build()
+	s.NoError(err)
+	s.Equal(2, round.Round)
+
+	// a round left building is rebuilt
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.mockRounds([]airdrop.AirdropRound{{Round: 2, Status: airdrop.RoundStatusBuilding}})
+	s.mockStore()
+
+	round, err = s.build()
+	s.NoError(err)
+	s.Equal(2, round.Round)
+}
+
+func (s *BuilderSuite) TestOnce() {
+	s.mockAirdrop(airdrop.AirdropTypeOnce)
+	s.mockRounds(nil)
+	s.mockLatestProofs(nil)
+	s.mockStore()
+
+	round, err := s.build()
+	s.NoError(err)
+	s.Equal(0, round.Round)
+
+	s.mockAirdrop(airdrop.AirdropTypeOnce)
+	s.mockRounds([]airdrop.AirdropRound{*round})
+
+	_, err = s.build()
+	s.ErrorIs(err, airdrop.ErrRoundExists)
+}
+
+func (s *BuilderSuite) TestLegacyProofs() {
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.mockRounds(nil)
+	s.mockLatestProofs([]airdrop.Proof{{ChainId: 1, ContractAddress: "0xdrop", Round: 5}})
+	s.mockStore()
+
+	round, err := s.build()
+	s.NoError(err)
+	s.Equal(6, round.Round)
+}
+
+func (s *BuilderSuite) TestNoAllocation() {
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	_, err := s.im.Build(s.ctx, airdrop.BuildParams{
+		ChainId:         1,
+		ContractAddress: "0xdrop",
+		Source:          airdrop.AllocationSourceCsv,
+	})
+	s.ErrorIs(err, airdrop.ErrNoAllocation)
+
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	_, err = s.im.Build(s.ctx, airdrop.BuildParams{
+		ChainId:         1,
+		ContractAddress: "0xdrop",
+		Source:          airdrop.AllocationSourcePromotion,
+	})
+	s.ErrorIs(err, domain.ErrBadParamInput)
+}
+
+func (s *BuilderSuite) TestRewardLedger() {
+	params := airdrop.BuildParams{
+		ChainId:         1,
+		ContractAddress: "0xdrop",
+		Source:          airdrop.AllocationSourceRewardLedger,
+		DistributionId:  "promo-1",
+	}
+
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.rewardUC.On("FindDistributions", mock.Anything, mock.AnythingOfType("collection_promotion.RewardDistributionFindAllOptionsFunc")).
+		Return(nil, nil).Once()
+
+	_, err := s.im.Build(s.ctx, params)
+	s.ErrorIs(err, domain.ErrNotFound)
+
+	s.mockAirdrop(airdrop.AirdropTypeRound)
+	s.rewardUC.On("FindDistributions", mock.Anything, mock.AnythingOfType("collection_promotion.RewardDistributionFindAllOptionsFunc")).
+		Return([]collection_promotion.RewardDistribution{{Id: "promo-1"}}, nil).Once()
+	s.rewardUC.On("FindLedger", mock.Anything, mock.AnythingOfType("collection_promotion.RewardLedgerFindAllOptionsFunc")).
+		Return([]collection_promotion.RewardLedgerEntry{
+			{DistributionId: "promo-1", Owner: "0x0000000000000000000000000000000000000001", Amount: "83"},
+			{DistributionId: "promo-1", Owner: "0x0000000000000000000000000000000000000002", Amount: "116"},
+		}, nil).Once()
+	s.mockRounds(nil)
+	s.mockLatestProofs(nil)
+	s.mockStore()
+
+	round, err := s.im.Build(s.ctx, params)
+	s.NoError(err)
+	s.Equal(2, round.ClaimerCount)
+	s.Equal("199", round.TotalAmount)
+	s.Equal(airdrop.AllocationSourceRewardLedger, round.Source)
+}
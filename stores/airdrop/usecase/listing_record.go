@@ -5,9 +5,11 @@ import (
 
 	bCtx "github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/ptr"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/airdrop"
 	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
 	"github.com/x-xyz/goapi/domain/token"
@@ -65,14 +67,24 @@ func (u *listingRecordUseCaseImpl) SnapshotCollectionListings(ctx bCtx.Ctx, chai
 	}
 
 	accountListings := make(map[domain.Address]map[int]int)
+	accountWeights := make(map[domain.Address]float64)
+	accountWeightedCounts := make(map[domain.Address]int)
 	for _, token := range tokens.Items {
-		if !u.isEligible(ctx, col, token) {
+		eligible := u.isEligible(ctx, col, token)
+		weight := u.listingWeight(col, token)
+		if !eligible && weight <= 0 {
 			continue
 		}
 		if accountListings[token.Owner] == nil {
 			accountListings[token.Owner] = make(map[int]int)
 		}
-		accountListings[token.Owner][int(CountTypeMain)]++
+		if eligible {
+			accountListings[token.Owner][int(CountTypeMain)]++
+		}
+		if weight > 0 {
+			accountWeights[token.Owner] += weight
+			accountWeightedCounts[token.Owner]++
+		}
 	}
 	now := time.Now()
 	for account, count := range accountListings {
@@ -82,6 +94,8 @@ func (u *listingRecordUseCaseImpl) SnapshotCollectionListings(ctx bCtx.Ctx, chai
 			ContractAddress: address,
 			Count:           count[int(CountTypeMain)],
 			SubCount:        count[int(CountTypeSub)],
+			Weight:          ptr.Float64(accountWeights[account]),
+			WeightedCount:   accountWeightedCounts[account],
 			SnapshotTime:    &snapshotTime,
 			UpdatedAt:       &now,
 		}
@@ -96,14 +110,28 @@ func (u *listingRecordUseCaseImpl) SnapshotCollectionListings(ctx bCtx.Ctx, chai
 	return nil
 }
 
-func (u *listingRecordUseCaseImpl) isEligible(ctx bCtx.Ctx, collection *collection.Collection, token *token.TokenWithDetail) bool {
-	// no or not public listing
+func isPublicListing(token *token.TokenWithDetail) bool {
 	if token.ActiveListing == nil || token.ActiveListing.Strategy != order.StrategyFixedPrice {
 		return false
 	}
-	if token.ActiveListing.Marketplace != order.MarketplaceApecoin.String() {
+	return token.ActiveListing.Marketplace == order.MarketplaceApecoin.String()
+}
+
+// isEligible is the rule of legacy airdrop snapshots
+func (u *listingRecordUseCaseImpl) isEligible(ctx bCtx.Ctx, collection *collection.Collection, token *token.TokenWithDetail) bool {
+	// no or not public listing
+	if !isPublicListing(token) {
 		return false
 	}
 
 	return token.ActiveListing.PriceInNative >= collection.OpenseaFloorPriceInNative*0.75 && token.ActiveListing.PriceInNative <= collection.OpenseaFloorPriceInNative*1.25
 }
+
+// listingWeight weights public listings by how close the price is to floor for listing reward rounds, 0 if not eligible
+func (u *listingRecordUseCaseImpl) listingWeight(collection *collection.Collection, token *token.TokenWithDetail) float64 {
+	if !isPublicListing(token) {
+		return 0
+	}
+
+	return collection_promotion.ListingWeight(token.ActiveListing.PriceInNative, collection.FloorPriceInNative)
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+type handler struct {
+	alert alert.UseCase
+}
+
+func New(e *echo.Echo, alert alert.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{alert}
+
+	g := e.Group("/alerts", authMiddleware.Auth())
+
+	g.GET("", h.list)
+
+	g.POST("", h.create)
+
+	g.GET("/:id", h.get)
+
+	g.PUT("/:id", h.update)
+
+	g.DELETE("/:id", h.delete)
+}
+
+// list godoc
+//
+//	@Summary		List alert rules
+//	@Description	List alert rules of the signed in account
+//	@Tags			alerts
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Success		200	{object}	[]alert.Rule
+//	@Failure		500
+//	@Router			/alerts [get]
+func (h *handler) list(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	res, err := h.alert.FindAll(ctx, address)
+	if err != nil {
+		ctx.WithField("err", err).Error("alert.FindAll failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// create godoc
+//
+//	@Summary		Create alert rule
+//	@Description	Create alert rule. Prices are in native token. Cooldown defaults to an hour.
+//	@Tags			alerts
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	body		http.create.params	true	"params"
+//	@Success		201		{object}	alert.Rule
+//	@Failure		400
+//	@Failure		500
+//	@Router			/alerts [post]
+func (h *handler) create(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		Type            alert.RuleType `json:"type"`
+		ChainId         domain.ChainId `json:"chainId"`
+		ContractAddress domain.Address `json:"contractAddress"`
+		TokenId         domain.TokenId `json:"tokenId"`
+		TraitName       string         `json:"traitName"`
+		TraitValue      string         `json:"traitValue"`
+		Threshold       float64        `json:"threshold"`
+		CooldownSeconds int64          `json:"cooldownSeconds"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.alert.Create(ctx, address, &alert.Rule{
+		Type:            p.Type,
+		ChainId:         p.ChainId,
+		ContractAddress: p.ContractAddress,
+		TokenId:         p.TokenId,
+		TraitName:       p.TraitName,
+		TraitValue:      p.TraitValue,
+		Threshold:       p.Threshold,
+		CooldownSeconds: p.CooldownSeconds,
+	})
+	if errors.Is(err, domain.ErrBadParamInput) || errors.Is(err, alert.ErrTooManyRules) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("alert.Create failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusCreated, res)
+}
+
+// get godoc
+//
+//	@Summary		Get alert rule
+//	@Description	Get alert rule of the signed in account
+//	@Tags			alerts
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"rule id"
+//	@Success		200	{object}	alert.Rule
+//	@Failure		404
+//	@Failure		500
+//	@Router			/alerts/{id} [get]
+func (h *handler) get(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	res, err := h.alert.FindOne(ctx, address, c.Param("id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// update godoc
+//
+//	@Summary		Update alert rule
+//	@Description	Update threshold, cooldown or enable/disable alert rule. Omitted fields are unchanged.
+//	@Tags			alerts
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"rule id"
+//	@Param			params	body		alert.Updater	true	"params"
+//	@Success		200		{object}	alert.Rule
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/alerts/{id} [put]
+func (h *handler) update(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	updater := &alert.Updater{}
+	if err := c.Bind(updater); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.alert.Update(ctx, address, c.Param("id"), updater)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// delete godoc
+//
+//	@Summary		Delete alert rule
+//	@Description	Delete alert rule of the signed in account
+//	@Tags			alerts
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path	string	true	"rule id"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/alerts/{id} [delete]
+func (h *handler) delete(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	err := h.alert.Delete(ctx, address, c.Param("id"))
+	if errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, nil)
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/alert"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier writes notifications to the log, for environments without a delivery channel
+func NewLogNotifier() alert.Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(c ctx.Ctx, notification *alert.Notification) error {
+	c.WithFields(log.Fields{
+		"notification": notification,
+	}).Info("alert fired")
+	return nil
+}
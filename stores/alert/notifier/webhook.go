@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/alert"
+)
+
+type WebhookNotifierCfg struct {
+	HttpClient http.Client
+	Timeout    time.Duration
+	// Url receives notifications as json in POST requests
+	Url string
+}
+
+type webhookNotifier struct {
+	client  http.Client
+	timeout time.Duration
+	url     string
+}
+
+func NewWebhookNotifier(cfg *WebhookNotifierCfg) alert.Notifier {
+	return &webhookNotifier{
+		client:  cfg.HttpClient,
+		timeout: cfg.Timeout,
+		url:     cfg.Url,
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx bCtx.Ctx, notification *alert.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		ctx.WithField("err", err).Error("json.Marshal failed")
+		return err
+	}
+
+	ctx, cancel := bCtx.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"url": n.url,
+			"err": err,
+		}).Error("NewRequestWithContext failed")
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"url": n.url,
+			"err": err,
+		}).Error("client.Do failed")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		ctx.WithFields(log.Fields{
+			"url":    n.url,
+			"status": resp.StatusCode,
+		}).Error("unexpected status")
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
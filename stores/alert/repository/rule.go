@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type impl struct {
+	query query.Mongo
+}
+
+func NewRuleRepo(query query.Mongo) alert.Repo {
+	return &impl{query}
+}
+
+func (im *impl) Insert(ctx ctx.Ctx, rule *alert.Rule) error {
+	rule.Owner = rule.Owner.ToLower()
+	rule.ContractAddress = rule.ContractAddress.ToLower()
+
+	err := im.query.Insert(ctx, domain.TableAlertRules, rule)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  rule.Id,
+		}).Error("failed to query.Insert")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) FindAll(ctx ctx.Ctx, options ...alert.FindAllOptionsFunc) ([]*alert.Rule, error) {
+	opts, err := alert.GetFindAllOptions(options...)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+		}).Error("failed to alert.GetFindAllOptions")
+		return nil, err
+	}
+
+	query := bson.M{}
+
+	if opts.Owner != nil {
+		query["owner"] = *opts.Owner
+	}
+
+	if opts.IsEnabled != nil {
+		query["isEnabled"] = *opts.IsEnabled
+	}
+
+	if opts.ChainId != nil {
+		query["chainId"] = *opts.ChainId
+	}
+
+	if opts.ContractAddress != nil {
+		query["contractAddress"] = *opts.ContractAddress
+	}
+
+	if opts.TokenId != nil {
+		query["tokenId"] = *opts.TokenId
+	}
+
+	if len(opts.Types) > 0 {
+		query["type"] = bson.M{"$in": opts.Types}
+	}
+
+	res := []*alert.Rule{}
+	err = im.query.Search(ctx, domain.TableAlertRules, 0, 0, "-createdAt", query, &res)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"query": query,
+		}).Error("failed to query.Search")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *impl) FindOne(ctx ctx.Ctx, id string) (*alert.Rule, error) {
+	res := alert.Rule{}
+	err := im.query.FindOne(ctx, domain.TableAlertRules, bson.M{"id": id}, &res)
+	if errors.Is(err, query.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.FindOne")
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (im *impl) Update(ctx ctx.Ctx, id string, updater *alert.Updater) error {
+	updateBson, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"updater": *updater,
+		}).Error("failed to mongoclient.MakeBsonM")
+		return err
+	}
+
+	err = im.query.Patch(ctx, domain.TableAlertRules, bson.M{"id": id}, updateBson)
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.Patch")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) UpdateState(ctx ctx.Ctx, id string, version int64, state alert.RuleState) error {
+	selector := bson.M{"id": id, "state.version": version}
+	err := im.query.Patch(ctx, domain.TableAlertRules, selector, bson.M{"state": state})
+	if errors.Is(err, query.ErrNotFound) {
+		return alert.ErrStateConflict
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":      err,
+			"selector": selector,
+		}).Error("failed to query.Patch")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) Delete(ctx ctx.Ctx, id string) error {
+	err := im.query.Remove(ctx, domain.TableAlertRules, bson.M{"id": id})
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.Remove")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/validator"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+)
+
+type AlertUseCaseCfg struct {
+	Repo           alert.Repo
+	CollectionRepo collection.Repo
+	OrderItemRepo  order.OrderItemRepo
+	// Notifier is only required by evaluators
+	Notifier alert.Notifier
+}
+
+type impl struct {
+	repo           alert.Repo
+	collectionRepo collection.Repo
+	orderItemRepo  order.OrderItemRepo
+	notifier       alert.Notifier
+	now            func() time.Time
+}
+
+func New(cfg *AlertUseCaseCfg) alert.UseCase {
+	return &impl{
+		repo:           cfg.Repo,
+		collectionRepo: cfg.CollectionRepo,
+		orderItemRepo:  cfg.OrderItemRepo,
+		notifier:       cfg.Notifier,
+		now:            time.Now,
+	}
+}
+
+func (im *impl) Create(c ctx.Ctx, owner domain.Address, rule *alert.Rule) (*alert.Rule, error) {
+	if err := validate(rule); err != nil {
+		c.WithFields(log.Fields{
+			"err":  err,
+			"rule": rule,
+		}).Warn("invalid rule")
+		return nil, err
+	}
+
+	if _, err := im.collectionRepo.FindOne(c, rule.ToCollectionId()); errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrBadParamInput
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  rule.ToCollectionId(),
+		}).Error("collectionRepo.FindOne failed")
+		return nil, err
+	}
+
+	rules, err := im.repo.FindAll(c, alert.WithOwner(owner))
+	if err != nil {
+		c.WithField("err", err).Error("repo.FindAll failed")
+		return nil, err
+	}
+	if len(rules) >= alert.MaxRulesPerOwner {
+		return nil, alert.ErrTooManyRules
+	}
+
+	res := &alert.Rule{
+		Id:              uuid.NewString(),
+		Owner:           owner.ToLower(),
+		Type:            rule.Type,
+		ChainId:         rule.ChainId,
+		ContractAddress: rule.ContractAddress.ToLower(),
+		TraitName:       rule.TraitName,
+		TraitValue:      rule.TraitValue,
+		Threshold:       rule.Threshold,
+		CooldownSeconds: rule.CooldownSeconds,
+		IsEnabled:       true,
+		CreatedAt:       im.now(),
+	}
+	if rule.Type.IsTokenRule() {
+		res.TokenId = rule.TokenId
+	}
+	if res.CooldownSeconds == 0 {
+		res.CooldownSeconds = int64(alert.DefaultCooldown / time.Second)
+	}
+
+	if err := im.repo.Insert(c, res); err != nil {
+		c.WithField("err", err).Error("repo.Insert failed")
+		return nil, err
+	}
+	return res, nil
+}
+
+func validate(rule *alert.Rule) error {
+	if !rule.Type.IsValid() || rule.ChainId == 0 || !validator.IsValidAddress(string(rule.ContractAddress)) {
+		return domain.ErrBadParamInput
+	}
+	if rule.Type.IsTokenRule() && rule.TokenId == "" {
+		return domain.ErrBadParamInput
+	}
+	if rule.Type == alert.RuleTypeTraitFloorChange && (rule.TraitName == "" || rule.TraitValue == "") {
+		return domain.ErrBadParamInput
+	}
+	// crossing a floor of 0 never happens, offers above 0 are any offers which is allowed
+	if rule.Threshold < 0 || rule.Type == alert.RuleTypeCollectionFloorBelow && rule.Threshold == 0 {
+		return domain.ErrBadParamInput
+	}
+	if rule.CooldownSeconds != 0 && rule.Cooldown() < alert.MinCooldown {
+		return domain.ErrBadParamInput
+	}
+	return nil
+}
+
+func (im *impl) FindAll(c ctx.Ctx, owner domain.Address) ([]*alert.Rule, error) {
+	return im.repo.FindAll(c, alert.WithOwner(owner))
+}
+
+func (im *impl) FindOne(c ctx.Ctx, owner domain.Address, id string) (*alert.Rule, error) {
+	rule, err := im.repo.FindOne(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Owner != owner.ToLower() {
+		return nil, domain.ErrNotFound
+	}
+	return rule, nil
+}
+
+func (im *impl) Update(c ctx.Ctx, owner domain.Address, id string, updater *alert.Updater) (*alert.Rule, error) {
+	rule, err := im.FindOne(c, owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updater.Threshold != nil {
+		rule.Threshold = *updater.Threshold
+	}
+	if updater.CooldownSeconds != nil {
+		// a cooldown of 0 is reset to the default as in Create, rules always have a cooldown
+		if *updater.CooldownSeconds == 0 {
+			cooldown := int64(alert.DefaultCooldown / time.Second)
+			updater.CooldownSeconds = &cooldown
+		}
+		rule.CooldownSeconds = *updater.CooldownSeconds
+	}
+	if err := validate(rule); err != nil {
+		return nil, err
+	}
+
+	if err := im.repo.Update(c, id, updater); err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.Update failed")
+		return nil, err
+	}
+
+	if updater.Threshold != nil {
+		// what's seen so far was evaluated against the previous threshold
+		state := alert.RuleState{Version: rule.State.Version + 1, TriggeredAt: rule.State.TriggeredAt}
+		if err := im.repo.UpdateState(c, id, rule.State.Version, state); err != nil && err != alert.ErrStateConflict {
+			c.WithFields(log.Fields{
+				"err": err,
+				"id":  id,
+			}).Error("repo.UpdateState failed")
+			return nil, err
+		}
+	}
+
+	return im.repo.FindOne(c, id)
+}
+
+func (im *impl) Delete(c ctx.Ctx, owner domain.Address, id string) error {
+	if _, err := im.FindOne(c, owner, id); err != nil {
+		return err
+	}
+	return im.repo.Delete(c, id)
+}
+
+func (im *impl) EvaluateCollection(c ctx.Ctx, id collection.CollectionId) error {
+	rules, err := im.repo.FindAll(c, alert.WithCollection(id), alert.WithIsEnabled(true))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.FindAll failed")
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var col *collection.Collection
+	// collection offers are shared by all tokens of the collection
+	var collectionOffers []*order.OrderItem
+	tokens := map[nftitem.Id]*tokenObservation{}
+	for _, rule := range rules {
+		obs := alert.Observation{}
+		if rule.Type.IsTokenRule() {
+			tokenId := rule.ToNftItemId()
+			if _, ok := tokens[tokenId]; !ok {
+				if collectionOffers == nil {
+					if collectionOffers, err = im.findCollectionOffers(c, id); err != nil {
+						return err
+					}
+				}
+				if tokens[tokenId], err = im.observeToken(c, tokenId, collectionOffers); err != nil {
+					return err
+				}
+			}
+			obs = tokens[tokenId].of(rule.Type)
+		} else {
+			if col == nil {
+				if col, err = im.collectionRepo.FindOne(c, id); err != nil {
+					c.WithFields(log.Fields{
+						"err": err,
+						"id":  id,
+					}).Error("collectionRepo.FindOne failed")
+					return err
+				}
+			}
+			obs = observeCollection(col, rule)
+		}
+
+		if err := im.apply(c, rule, obs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *impl) EvaluateToken(c ctx.Ctx, id nftitem.Id) error {
+	rules, err := im.repo.FindAll(c,
+		alert.WithToken(id),
+		alert.WithIsEnabled(true),
+		alert.WithTypes(alert.RuleTypeTokenListed, alert.RuleTypeTokenPriceChange, alert.RuleTypeOfferReceived),
+	)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.FindAll failed")
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	collectionOffers, err := im.findCollectionOffers(c, collection.CollectionId{ChainId: id.ChainId, Address: id.ContractAddress})
+	if err != nil {
+		return err
+	}
+	token, err := im.observeToken(c, id, collectionOffers)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := im.apply(c, rule, token.of(rule.Type)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply stores the next state of the rule and notifies the owner if it fires. Evaluators racing on the
+// same rule are deduplicated by the state version, only the one which stores the state notifies.
+func (im *impl) apply(c ctx.Ctx, rule *alert.Rule, obs alert.Observation) error {
+	next, fire := rule.Evaluate(obs, im.now())
+	if next.Version == rule.State.Version {
+		return nil
+	}
+
+	if err := im.repo.UpdateState(c, rule.Id, rule.State.Version, next); err == alert.ErrStateConflict {
+		c.WithField("id", rule.Id).Info("rule evaluated by others")
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  rule.Id,
+		}).Error("repo.UpdateState failed")
+		return err
+	}
+
+	if !fire {
+		return nil
+	}
+
+	notification := &alert.Notification{
+		RuleId:          rule.Id,
+		Owner:           rule.Owner,
+		Type:            rule.Type,
+		ChainId:         rule.ChainId,
+		ContractAddress: rule.ContractAddress,
+		TokenId:         rule.TokenId,
+		TraitName:       rule.TraitName,
+		TraitValue:      rule.TraitValue,
+		Threshold:       rule.Threshold,
+		PreviousValue:   rule.State.Value,
+		Value:           obs.Value,
+		Time:            *next.TriggeredAt,
+	}
+	if err := im.notifier.Notify(c, notification); err != nil {
+		// the state is stored already, a failed delivery isn't retried to avoid duplicates
+		c.WithFields(log.Fields{
+			"err":          err,
+			"notification": notification,
+		}).Error("notifier.Notify failed")
+	}
+	return nil
+}
+
+func observeCollection(col *collection.Collection, rule *alert.Rule) alert.Observation {
+	if rule.Type == alert.RuleTypeTraitFloorChange {
+		price, ok := col.TraitFloorPrice[rule.TraitName][rule.TraitValue]
+		return alert.Observation{Exists: ok && price > 0, Value: price}
+	}
+	return alert.Observation{Exists: col.HasFloorPrice, Value: col.FloorPriceInNative}
+}
+
+type tokenObservation struct {
+	// listing is the lowest active listing, offer is the highest offer including collection offers
+	listing alert.Observation
+	offer   alert.Observation
+}
+
+func (o *tokenObservation) of(t alert.RuleType) alert.Observation {
+	if t == alert.RuleTypeOfferReceived {
+		return o.offer
+	}
+	return o.listing
+}
+
+// observeToken observes orders of the token, collectionOffers are the active collection offers of its collection
+func (im *impl) observeToken(c ctx.Ctx, id nftitem.Id, collectionOffers []*order.OrderItem) (*tokenObservation, error) {
+	now := im.now()
+	items, err := im.orderItemRepo.FindAll(c,
+		order.WithNftItemId(id),
+		order.WithIsValid(true),
+		order.WithIsUsed(false),
+		order.WithStartTimeLT(now),
+		order.WithEndTimeGT(now),
+	)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("orderItemRepo.FindAll failed")
+		return nil, err
+	}
+
+	res := &tokenObservation{}
+	for _, item := range append(items, collectionOffers...) {
+		if item.IsAsk {
+			if item.Strategy == order.StrategyFixedPrice && (!res.listing.Exists || item.PriceInNative < res.listing.Value) {
+				res.listing = alert.Observation{Exists: true, Value: item.PriceInNative, Key: string(item.OrderItemHash)}
+			}
+		} else if !res.offer.Exists || item.PriceInNative > res.offer.Value {
+			res.offer = alert.Observation{Exists: true, Value: item.PriceInNative, Key: string(item.OrderItemHash)}
+		}
+	}
+	return res, nil
+}
+
+// findCollectionOffers finds active collection offers of the collection, never nil if succeeded
+func (im *impl) findCollectionOffers(c ctx.Ctx, id collection.CollectionId) ([]*order.OrderItem, error) {
+	now := im.now()
+	res, err := im.orderItemRepo.FindAll(c,
+		order.WithChainId(id.ChainId),
+		order.WithContractAddress(id.Address),
+		order.WithIsValid(true),
+		order.WithIsUsed(false),
+		order.WithStartTimeLT(now),
+		order.WithEndTimeGT(now),
+		order.WithStrategy(order.StrategyCollectionOffer),
+	)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("orderItemRepo.FindAll failed")
+		return nil, err
+	}
+	if res == nil {
+		res = []*order.OrderItem{}
+	}
+	return res, nil
+}
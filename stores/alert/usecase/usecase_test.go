@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/alert"
+	mAlert "github.com/x-xyz/goapi/domain/alert/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	"github.com/x-xyz/goapi/domain/order"
+	mOrder "github.com/x-xyz/goapi/domain/order/mocks"
+)
+
+var (
+	contractAddress = domain.Address("0x1111111111111111111111111111111111111111")
+	alice           = domain.Address("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+)
+
+type AlertSuite struct {
+	suite.Suite
+	ctx            bCtx.Ctx
+	now            time.Time
+	repo           *mAlert.Repo
+	collectionRepo *mCollection.Repo
+	orderItemRepo  *mOrder.OrderItemRepo
+	notifier       *mAlert.Notifier
+	im             *impl
+	// states are the states stored by UpdateState, notified are notifications sent
+	states   map[string]alert.RuleState
+	notified []*alert.Notification
+}
+
+func TestAlertSuite(t *testing.T) {
+	suite.Run(t, new(AlertSuite))
+}
+
+func (s *AlertSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.now = time.Now()
+	s.repo = &mAlert.Repo{}
+	s.collectionRepo = &mCollection.Repo{}
+	s.orderItemRepo = &mOrder.OrderItemRepo{}
+	s.notifier = &mAlert.Notifier{}
+	s.im = &impl{
+		repo:           s.repo,
+		collectionRepo: s.collectionRepo,
+		orderItemRepo:  s.orderItemRepo,
+		notifier:       s.notifier,
+		now:            func() time.Time { return s.now },
+	}
+	s.states = map[string]alert.RuleState{}
+	s.notified = []*alert.Notification{}
+}
+
+func (s *AlertSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.collectionRepo.AssertExpectations(s.T())
+	s.orderItemRepo.AssertExpectations(s.T())
+	s.notifier.AssertExpectations(s.T())
+}
+
+// mockRules mocks enabled rules of the collection with their stored states
+func (s *AlertSuite) mockRules(rules ...alert.Rule) {
+	res := []*alert.Rule{}
+	for i := range rules {
+		rule := rules[i]
+		rule.State = s.states[rule.Id]
+		res = append(res, &rule)
+	}
+	s.repo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("alert.FindAllOptionsFunc"),
+		mock.AnythingOfType("alert.FindAllOptionsFunc")).
+		Return(res, nil).Once()
+}
+
+func (s *AlertSuite) mockUpdateState(id string) {
+	s.repo.On("UpdateState", mock.Anything, id, s.states[id].Version, mock.AnythingOfType("alert.RuleState")).
+		Run(func(args mock.Arguments) { s.states[id] = args.Get(3).(alert.RuleState) }).
+		Return(nil).Once()
+}
+
+func (s *AlertSuite) mockNotify() {
+	s.notifier.On("Notify", mock.Anything, mock.AnythingOfType("*alert.Notification")).
+		Run(func(args mock.Arguments) { s.notified = append(s.notified, args.Get(1).(*alert.Notification)) }).
+		Return(nil).Once()
+}
+
+// mockOrders mocks active orders of the token and collection offers of the collection
+func (s *AlertSuite) mockOrders(items []*order.OrderItem) {
+	s.orderItemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc")).
+		Return(items, nil).Once()
+	s.orderItemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc")).
+		Return(nil, nil).Once()
+}
+
+func (s *AlertSuite) TestEvaluateCollection() {
+	id := collection.CollectionId{ChainId: 1, Address: contractAddress}
+	rules := []alert.Rule{
+		{Id: "floor", Owner: alice, Type: alert.RuleTypeCollectionFloorBelow, ChainId: 1, ContractAddress: contractAddress, Threshold: 1, CooldownSeconds: 60, IsEnabled: true},
+		{Id: "trait", Owner: alice, Type: alert.RuleTypeTraitFloorChange, ChainId: 1, ContractAddress: contractAddress, TraitName: "hat", TraitValue: "red", CooldownSeconds: 60, IsEnabled: true},
+		{Id: "offer", Owner: alice, Type: alert.RuleTypeOfferReceived, ChainId: 1, ContractAddress: contractAddress, TokenId: "1", Threshold: 0.5, CooldownSeconds: 60, IsEnabled: true},
+	}
+	offers := []*order.OrderItem{
+		{Item: order.Item{Collection: contractAddress, TokenId: "1"}, OrderItemHash: "0x01", IsAsk: false, PriceInNative: 0.6},
+		{Item: order.Item{Collection: contractAddress, TokenId: "1"}, OrderItemHash: "0x02", IsAsk: false, PriceInNative: 0.4},
+	}
+	col := &collection.Collection{HasFloorPrice: true, FloorPriceInNative: 0.9, TraitFloorPrice: map[string]map[string]float64{"hat": {"red": 2}}}
+
+	// floor crossed and offer received, trait floor is the baseline
+	s.mockRules(rules...)
+	s.collectionRepo.On("FindOne", mock.Anything, id).Return(col, nil).Once()
+	s.mockOrders(offers)
+	s.mockUpdateState("floor")
+	s.mockUpdateState("trait")
+	s.mockUpdateState("offer")
+	s.mockNotify()
+	s.mockNotify()
+	s.Require().NoError(s.im.EvaluateCollection(s.ctx, id))
+	s.Equal(float64(2), s.states["trait"].Value)
+	s.Equal("0x01", s.states["offer"].Key)
+
+	// nothing changed, nothing notified again
+	s.mockRules(rules...)
+	s.collectionRepo.On("FindOne", mock.Anything, id).Return(col, nil).Once()
+	s.mockOrders(offers)
+	s.Require().NoError(s.im.EvaluateCollection(s.ctx, id))
+
+	col.TraitFloorPrice["hat"]["red"] = 1.5
+	s.mockRules(rules...)
+	s.collectionRepo.On("FindOne", mock.Anything, id).Return(col, nil).Once()
+	s.mockOrders(offers)
+	s.mockUpdateState("trait")
+	s.mockNotify()
+	s.Require().NoError(s.im.EvaluateCollection(s.ctx, id))
+	s.Require().Len(s.notified, 3)
+	s.Equal(alert.RuleTypeTraitFloorChange, s.notified[2].Type)
+	s.Equal(float64(2), s.notified[2].PreviousValue)
+	s.Equal(1.5, s.notified[2].Value)
+}
+
+func (s *AlertSuite) TestEvaluateCollectionOffersOnce() {
+	id := collection.CollectionId{ChainId: 1, Address: contractAddress}
+	rules := []alert.Rule{
+		{Id: "offer-1", Owner: alice, Type: alert.RuleTypeOfferReceived, ChainId: 1, ContractAddress: contractAddress, TokenId: "1", Threshold: 0.5, CooldownSeconds: 60, IsEnabled: true},
+		{Id: "offer-2", Owner: alice, Type: alert.RuleTypeOfferReceived, ChainId: 1, ContractAddress: contractAddress, TokenId: "2", Threshold: 0.5, CooldownSeconds: 60, IsEnabled: true},
+	}
+	collectionOffer := &order.OrderItem{Item: order.Item{Collection: contractAddress}, OrderItemHash: "0x03", IsAsk: false, PriceInNative: 0.7, Strategy: order.StrategyCollectionOffer}
+
+	s.mockRules(rules...)
+	// orders of each token
+	s.orderItemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc")).
+		Return(nil, nil).Twice()
+	// collection offers of the collection
+	s.orderItemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc")).
+		Return([]*order.OrderItem{collectionOffer}, nil).Once()
+	s.mockUpdateState("offer-1")
+	s.mockUpdateState("offer-2")
+	s.mockNotify()
+	s.mockNotify()
+	s.Require().NoError(s.im.EvaluateCollection(s.ctx, id))
+	s.Equal("0x03", s.states["offer-1"].Key)
+	s.Equal("0x03", s.states["offer-2"].Key)
+}
+
+func (s *AlertSuite) TestApplyDedup() {
+	rule := &alert.Rule{Id: "listed", Owner: alice, Type: alert.RuleTypeTokenListed, ChainId: 1, ContractAddress: contractAddress, TokenId: "1", IsEnabled: true}
+
+	// two evaluators read the same state, only the first one notifies
+	obs := alert.Observation{Exists: true, Value: 1, Key: "0x01"}
+	s.mockUpdateState("listed")
+	s.mockNotify()
+	s.Require().NoError(s.im.apply(s.ctx, rule, obs))
+	s.repo.On("UpdateState", mock.Anything, "listed", int64(0), mock.AnythingOfType("alert.RuleState")).Return(alert.ErrStateConflict).Once()
+	s.Require().NoError(s.im.apply(s.ctx, rule, obs))
+	s.Equal(int64(1), s.states["listed"].Version)
+}
+
+func (s *AlertSuite) TestUpdateCooldown() {
+	rule := alert.Rule{Id: "floor", Owner: alice, Type: alert.RuleTypeCollectionFloorBelow, ChainId: 1, ContractAddress: contractAddress, Threshold: 1, CooldownSeconds: 60, IsEnabled: true}
+	defaultCooldown := int64(alert.DefaultCooldown / time.Second)
+
+	// a rule without a cooldown would notify on every evaluation, it gets the default one
+	updated := rule
+	updated.CooldownSeconds = defaultCooldown
+	s.repo.On("FindOne", mock.Anything, "floor").Return(&rule, nil).Once()
+	s.repo.On("Update", mock.Anything, "floor", mock.MatchedBy(func(updater *alert.Updater) bool {
+		return *updater.CooldownSeconds == defaultCooldown
+	})).Return(nil).Once()
+	s.repo.On("FindOne", mock.Anything, "floor").Return(&updated, nil).Once()
+	zero := int64(0)
+	res, err := s.im.Update(s.ctx, alice, "floor", &alert.Updater{CooldownSeconds: &zero})
+	s.Require().NoError(err)
+	s.Equal(alert.DefaultCooldown, res.Cooldown())
+
+	// the rule isn't updated
+	s.repo.On("FindOne", mock.Anything, "floor").Return(&updated, nil).Once()
+	short := int64(1)
+	_, err = s.im.Update(s.ctx, alice, "floor", &alert.Updater{CooldownSeconds: &short})
+	s.ErrorIs(err, domain.ErrBadParamInput)
+}
+
+func TestValidate(t *testing.T) {
+	valid := alert.Rule{Type: alert.RuleTypeCollectionFloorBelow, ChainId: 1, ContractAddress: contractAddress, Threshold: 1}
+	require.NoError(t, validate(&valid))
+
+	for _, update := range []func(r *alert.Rule){
+		func(r *alert.Rule) { r.Type = "unknown" },
+		func(r *alert.Rule) { r.Threshold = 0 },
+		func(r *alert.Rule) { r.CooldownSeconds = 1 },
+		func(r *alert.Rule) { r.Type = alert.RuleTypeTokenListed },
+		func(r *alert.Rule) { r.Type = alert.RuleTypeTraitFloorChange; r.TraitName = "hat" },
+	} {
+		r := valid
+		update(&r)
+		require.ErrorIs(t, validate(&r), domain.ErrBadParamInput)
+	}
+}
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/validator"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/apikey"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+const (
+	defaultUsageDays = 7
+	maxUsageDays     = 90
+)
+
+type handler struct {
+	apikey apikey.UseCase
+}
+
+func New(e *echo.Echo, apikey apikey.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{apikey}
+
+	g := e.Group("/apikeys", authMiddleware.Auth(), authMiddleware.IsAdmin())
+
+	g.GET("", h.list)
+
+	g.POST("", h.issue)
+
+	g.GET("/:id", h.get)
+
+	g.POST("/:id/revoke", h.revoke)
+
+	g.PUT("/:id/limits", h.updateLimits)
+
+	g.GET("/:id/usage", h.usage)
+}
+
+// list godoc
+//
+//	@Summary		List api keys
+//	@Description	List api keys, admin only
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			owner		query		string	false	"owner address"
+//	@Param			isRevoked	query		bool	false	"filter by revocation"
+//	@Param			offset		query		int		false	"offset"
+//	@Param			limit		query		int		false	"limit"
+//	@Success		200			{object}	[]apikey.ApiKey
+//	@Failure		400
+//	@Failure		500
+//	@Router			/apikeys [get]
+func (h *handler) list(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Owner     *domain.Address `query:"owner"`
+		IsRevoked *bool           `query:"isRevoked"`
+		Offset    int32           `query:"offset"`
+		Limit     int32           `query:"limit"`
+	}
+
+	p := params{Limit: 100}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	opts := []apikey.FindAllOptionsFunc{apikey.WithPagination(p.Offset, p.Limit)}
+
+	if p.Owner != nil {
+		opts = append(opts, apikey.WithOwner(*p.Owner))
+	}
+
+	if p.IsRevoked != nil {
+		opts = append(opts, apikey.WithIsRevoked(*p.IsRevoked))
+	}
+
+	res, err := h.apikey.FindAll(ctx, opts...)
+	if err != nil {
+		ctx.WithField("err", err).Error("apikey.FindAll failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// issue godoc
+//
+//	@Summary		Issue api key
+//	@Description	Issue api key to an address, admin only. The raw key is only returned in this response.
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	body		http.issue.params	true	"params"
+//	@Success		201		{object}	apikey.IssuedKey
+//	@Failure		400
+//	@Failure		500
+//	@Router			/apikeys [post]
+func (h *handler) issue(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Owner  domain.Address `json:"owner"`
+		Name   string         `json:"name"`
+		Scopes []apikey.Scope `json:"scopes"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if !validator.IsValidAddress(string(p.Owner)) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid owner")
+	}
+
+	if len(p.Scopes) == 0 {
+		p.Scopes = []apikey.Scope{apikey.ScopeRead}
+	}
+
+	res, err := h.apikey.Issue(ctx, p.Owner, p.Name, p.Scopes)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"owner": p.Owner,
+		}).Error("apikey.Issue failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusCreated, res)
+}
+
+// get godoc
+//
+//	@Summary		Get api key
+//	@Description	Get api key, admin only
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"key id"
+//	@Success		200	{object}	apikey.ApiKey
+//	@Failure		404
+//	@Failure		500
+//	@Router			/apikeys/{id} [get]
+func (h *handler) get(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	res, err := h.apikey.FindOne(ctx, c.Param("id"))
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// revoke godoc
+//
+//	@Summary		Revoke api key
+//	@Description	Revoke api key, admin only
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id	path	string	true	"key id"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/apikeys/{id}/revoke [post]
+func (h *handler) revoke(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	if err := h.apikey.Revoke(ctx, c.Param("id")); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, nil)
+}
+
+// updateLimits godoc
+//
+//	@Summary		Update api key limits
+//	@Description	Update scopes, rate limit and daily quota of api key, admin only. Omitted fields are unchanged.
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"key id"
+//	@Param			params	body		apikey.Updater	true	"params"
+//	@Success		200		{object}	apikey.ApiKey
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/apikeys/{id}/limits [put]
+func (h *handler) updateLimits(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	updater := &apikey.Updater{}
+	if err := c.Bind(updater); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.apikey.UpdateLimits(ctx, c.Param("id"), updater)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// usage godoc
+//
+//	@Summary		Get api key usage
+//	@Description	Get daily request counts of api key, latest first, admin only
+//	@Tags			apikeys
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"key id"
+//	@Param			days	query		int		false	"number of days, default 7, max 90"
+//	@Success		200		{object}	[]apikey.Usage
+//	@Failure		400
+//	@Failure		500
+//	@Router			/apikeys/{id}/usage [get]
+func (h *handler) usage(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Id   string `param:"id"`
+		Days int    `query:"days"`
+	}
+
+	p := params{Days: defaultUsageDays}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if p.Days <= 0 || p.Days > maxUsageDays {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid days")
+	}
+
+	res, err := h.apikey.GetUsage(ctx, p.Id, p.Days)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
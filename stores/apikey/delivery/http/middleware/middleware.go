@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain/apikey"
+	"github.com/x-xyz/goapi/service/ratelimit"
+)
+
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+)
+
+type RateLimitMiddleware struct {
+	apikey  apikey.UseCase
+	limiter ratelimit.Limiter
+	// ipRateLimit is the max requests per minute of an ip without api key, 0 for unlimited
+	ipRateLimit int
+}
+
+func New(apikey apikey.UseCase, limiter ratelimit.Limiter, ipRateLimit int) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		apikey:      apikey,
+		limiter:     limiter,
+		ipRateLimit: ipRateLimit,
+	}
+}
+
+// RateLimit throttles requests by api key if `X-API-Key` presents, otherwise by ip.
+// Requests are let through if redis is unavailable, availability matters more than throttling.
+func (m *RateLimitMiddleware) RateLimit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Get("ctx").(ctx.Ctx)
+
+			raw := c.Request().Header.Get(apikey.Header)
+			if raw == "" {
+				return m.limitByIp(ctx, c, next)
+			}
+
+			key, err := m.apikey.Authenticate(ctx, raw)
+			if errors.Is(err, apikey.ErrInvalidKey) || errors.Is(err, apikey.ErrRevoked) {
+				return delivery.MakeJsonResp(c, http.StatusUnauthorized, err)
+			} else if err != nil {
+				ctx.WithField("err", err).Error("apikey.Authenticate failed")
+				return m.limitByIp(ctx, c, next)
+			}
+
+			if !key.HasScope(requiredScope(c)) {
+				return delivery.MakeJsonResp(c, http.StatusForbidden, apikey.ErrNoScope)
+			}
+
+			c.Set("apiKey", key)
+
+			if res, err := m.limiter.Allow(ctx, "key:"+key.Id, key.RateLimit, apikey.RateWindow); err != nil {
+				ctx.WithFields(log.Fields{
+					"err": err,
+					"id":  key.Id,
+				}).Warn("limiter.Allow failed")
+			} else if !limit(c, res) {
+				return tooManyRequests(c, res.RetryAfter)
+			}
+
+			count, err := m.apikey.IncrUsage(ctx, key.Id)
+			if err != nil {
+				ctx.WithFields(log.Fields{
+					"err": err,
+					"id":  key.Id,
+				}).Warn("apikey.IncrUsage failed")
+			} else if key.DailyQuota > 0 && count > int64(key.DailyQuota) {
+				now := time.Now().UTC()
+				tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+				return tooManyRequests(c, tomorrow.Sub(now))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func (m *RateLimitMiddleware) limitByIp(ctx ctx.Ctx, c echo.Context, next echo.HandlerFunc) error {
+	if m.ipRateLimit <= 0 {
+		return next(c)
+	}
+
+	ip := c.RealIP()
+	if res, err := m.limiter.Allow(ctx, "ip:"+ip, m.ipRateLimit, apikey.RateWindow); err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"ip":  ip,
+		}).Warn("limiter.Allow failed")
+	} else if !limit(c, res) {
+		return tooManyRequests(c, res.RetryAfter)
+	}
+
+	return next(c)
+}
+
+// limit sets rate limit headers and returns whether the request is allowed
+func limit(c echo.Context, res *ratelimit.Result) bool {
+	header := c.Response().Header()
+	header.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+	header.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+	return res.Allowed
+}
+
+func tooManyRequests(c echo.Context, retryAfter time.Duration) error {
+	// Retry-After is in seconds, round up so clients won't retry too early
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+	return delivery.MakeJsonResp(c, http.StatusTooManyRequests, "rate limit exceeded")
+}
+
+// requiredScope is read for safe methods and graphql which has no mutation, write otherwise
+func requiredScope(c echo.Context) apikey.Scope {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return apikey.ScopeRead
+	}
+
+	if c.Path() == "/graphql" {
+		return apikey.ScopeRead
+	}
+
+	return apikey.ScopeWrite
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain/apikey"
+	mApikey "github.com/x-xyz/goapi/domain/apikey/mocks"
+	"github.com/x-xyz/goapi/service/ratelimit"
+	mRatelimit "github.com/x-xyz/goapi/service/ratelimit/mocks"
+)
+
+var (
+	readKey    = &apikey.ApiKey{Id: "1", Scopes: []apikey.Scope{apikey.ScopeRead}, RateLimit: 2}
+	quotaKey   = &apikey.ApiKey{Id: "2", Scopes: []apikey.Scope{apikey.ScopeRead}, RateLimit: 10, DailyQuota: 1}
+	allowed    = &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1}
+	notAllowed = &ratelimit.Result{Limit: 2, RetryAfter: 1500 * time.Millisecond}
+)
+
+type RateLimitSuite struct {
+	suite.Suite
+	e       *echo.Echo
+	apikey  *mApikey.UseCase
+	limiter *mRatelimit.Limiter
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
+
+func (s *RateLimitSuite) SetupTest() {
+	s.apikey = &mApikey.UseCase{}
+	s.limiter = &mRatelimit.Limiter{}
+
+	m := New(s.apikey, s.limiter, 1)
+
+	s.e = echo.New()
+	s.e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("ctx", ctx.Background())
+			return next(c)
+		}
+	})
+	s.e.Use(m.RateLimit())
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	s.e.GET("/tokens", ok)
+	s.e.POST("/tokens", ok)
+	s.e.POST("/graphql", ok)
+}
+
+func (s *RateLimitSuite) TearDownTest() {
+	s.apikey.AssertExpectations(s.T())
+	s.limiter.AssertExpectations(s.T())
+}
+
+func (s *RateLimitSuite) do(method, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set(apikey.Header, key)
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *RateLimitSuite) TestIp() {
+	s.limiter.On("Allow", mock.Anything, "ip:192.0.2.1", 1, apikey.RateWindow).
+		Return(&ratelimit.Result{Allowed: true, Limit: 1}, nil).Once()
+	s.limiter.On("Allow", mock.Anything, "ip:192.0.2.1", 1, apikey.RateWindow).
+		Return(&ratelimit.Result{Limit: 1, RetryAfter: 1500 * time.Millisecond}, nil).Once()
+
+	rec := s.do(http.MethodGet, "/tokens", "")
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("1", rec.Header().Get(HeaderRateLimitLimit))
+	s.Equal("0", rec.Header().Get(HeaderRateLimitRemaining))
+
+	rec = s.do(http.MethodGet, "/tokens", "")
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.Equal("2", rec.Header().Get(echo.HeaderRetryAfter))
+}
+
+func (s *RateLimitSuite) TestKey() {
+	s.apikey.On("Authenticate", mock.Anything, "read").Return(readKey, nil).Times(3)
+	// key limit is separated from ip limit
+	s.limiter.On("Allow", mock.Anything, "key:1", 2, apikey.RateWindow).Return(allowed, nil).Twice()
+	s.limiter.On("Allow", mock.Anything, "key:1", 2, apikey.RateWindow).Return(notAllowed, nil).Once()
+	s.apikey.On("IncrUsage", mock.Anything, "1").Return(int64(1), nil).Once()
+	s.apikey.On("IncrUsage", mock.Anything, "1").Return(int64(2), nil).Once()
+
+	for i := 0; i < 2; i++ {
+		rec := s.do(http.MethodGet, "/tokens", "read")
+		s.Equal(http.StatusOK, rec.Code)
+		s.Equal("2", rec.Header().Get(HeaderRateLimitLimit))
+	}
+
+	rec := s.do(http.MethodGet, "/tokens", "read")
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.NotEmpty(rec.Header().Get(echo.HeaderRetryAfter))
+}
+
+func (s *RateLimitSuite) TestInvalidKey() {
+	s.apikey.On("Authenticate", mock.Anything, "unknown").Return(nil, apikey.ErrInvalidKey).Once()
+	s.apikey.On("Authenticate", mock.Anything, "revoked").Return(nil, apikey.ErrRevoked).Once()
+
+	s.Equal(http.StatusUnauthorized, s.do(http.MethodGet, "/tokens", "unknown").Code)
+	s.Equal(http.StatusUnauthorized, s.do(http.MethodGet, "/tokens", "revoked").Code)
+}
+
+func (s *RateLimitSuite) TestScope() {
+	s.apikey.On("Authenticate", mock.Anything, "read").Return(readKey, nil).Twice()
+	s.limiter.On("Allow", mock.Anything, "key:1", 2, apikey.RateWindow).Return(allowed, nil).Once()
+	s.apikey.On("IncrUsage", mock.Anything, "1").Return(int64(1), nil).Once()
+
+	s.Equal(http.StatusForbidden, s.do(http.MethodPost, "/tokens", "read").Code)
+	s.Equal(http.StatusOK, s.do(http.MethodPost, "/graphql", "read").Code)
+}
+
+func (s *RateLimitSuite) TestDailyQuota() {
+	s.apikey.On("Authenticate", mock.Anything, "quota").Return(quotaKey, nil).Twice()
+	s.limiter.On("Allow", mock.Anything, "key:2", 10, apikey.RateWindow).Return(allowed, nil).Twice()
+	s.apikey.On("IncrUsage", mock.Anything, "2").Return(int64(1), nil).Once()
+	s.apikey.On("IncrUsage", mock.Anything, "2").Return(int64(2), nil).Once()
+
+	s.Equal(http.StatusOK, s.do(http.MethodGet, "/tokens", "quota").Code)
+
+	rec := s.do(http.MethodGet, "/tokens", "quota")
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.NotEmpty(rec.Header().Get(echo.HeaderRetryAfter))
+}
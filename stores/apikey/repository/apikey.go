@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/apikey"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type impl struct {
+	query query.Mongo
+}
+
+func New(query query.Mongo) apikey.Repo {
+	return &impl{query}
+}
+
+func (im *impl) Insert(ctx ctx.Ctx, key *apikey.ApiKey) error {
+	key.Owner = key.Owner.ToLower()
+
+	err := im.query.Insert(ctx, domain.TableApiKeys, key)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  key.Id,
+		}).Error("failed to query.Insert")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) FindAll(ctx ctx.Ctx, options ...apikey.FindAllOptionsFunc) ([]*apikey.ApiKey, error) {
+	opts, err := apikey.GetFindAllOptions(options...)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+		}).Error("failed to apikey.GetFindAllOptions")
+		return nil, err
+	}
+
+	query := bson.M{}
+
+	if opts.Owner != nil {
+		query["owner"] = *opts.Owner
+	}
+
+	if opts.IsRevoked != nil {
+		query["isRevoked"] = *opts.IsRevoked
+	}
+
+	offset, limit := 0, 0
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+
+	res := []*apikey.ApiKey{}
+	err = im.query.Search(ctx, domain.TableApiKeys, offset, limit, "-createdAt", query, &res)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"query": query,
+		}).Error("failed to query.Search")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *impl) FindOne(ctx ctx.Ctx, id string) (*apikey.ApiKey, error) {
+	return im.findOne(ctx, bson.M{"id": id})
+}
+
+func (im *impl) FindOneByHash(ctx ctx.Ctx, hash string) (*apikey.ApiKey, error) {
+	return im.findOne(ctx, bson.M{"hash": hash})
+}
+
+func (im *impl) findOne(ctx ctx.Ctx, selector bson.M) (*apikey.ApiKey, error) {
+	res := apikey.ApiKey{}
+	err := im.query.FindOne(ctx, domain.TableApiKeys, selector, &res)
+	if errors.Is(err, query.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":      err,
+			"selector": selector,
+		}).Error("failed to query.FindOne")
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (im *impl) Update(ctx ctx.Ctx, id string, updater *apikey.Updater) error {
+	updateBson, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"updater": *updater,
+		}).Error("failed to mongoclient.MakeBsonM")
+		return err
+	}
+
+	err = im.query.Patch(ctx, domain.TableApiKeys, bson.M{"id": id}, updateBson)
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.Patch")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/apikey"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/service/cache"
+	"github.com/x-xyz/goapi/service/cache/provider/primitive"
+	"github.com/x-xyz/goapi/service/redis"
+)
+
+const (
+	keyPfx       = "xk_"
+	keyBytes     = 24
+	displayChars = 10
+
+	defaultRateLimit = 600
+	usageTtl         = 90 * 24 * time.Hour
+	usageDateLayout  = "20060102"
+)
+
+type ApiKeyUseCaseCfg struct {
+	Repo  apikey.Repo
+	Redis redis.Service
+
+	// DefaultRateLimit and DefaultDailyQuota are applied to new keys, DefaultRateLimit uses default if 0
+	DefaultRateLimit  int
+	DefaultDailyQuota int
+}
+
+type impl struct {
+	repo              apikey.Repo
+	redis             redis.Service
+	defaultRateLimit  int
+	defaultDailyQuota int
+	// keyed by hash, keeps hot keys off mongo, revocation takes effect on other instances after ttl
+	keyCache cache.Service
+	now      func() time.Time
+}
+
+func New(cfg *ApiKeyUseCaseCfg) apikey.UseCase {
+	im := &impl{
+		repo:              cfg.Repo,
+		redis:             cfg.Redis,
+		defaultRateLimit:  cfg.DefaultRateLimit,
+		defaultDailyQuota: cfg.DefaultDailyQuota,
+		keyCache: cache.New(cache.ServiceConfig{
+			Ttl:   10 * time.Second,
+			Pfx:   "apikey",
+			Cache: primitive.NewPrimitive("apikey", 256),
+		}),
+		now: time.Now,
+	}
+
+	if im.defaultRateLimit == 0 {
+		im.defaultRateLimit = defaultRateLimit
+	}
+
+	return im
+}
+
+func (im *impl) Issue(ctx ctx.Ctx, owner domain.Address, name string, scopes []apikey.Scope) (*apikey.IssuedKey, error) {
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			ctx.WithField("scope", scope).Warn("invalid scope")
+			return nil, domain.ErrBadParamInput
+		}
+	}
+
+	raw, err := newRawKey()
+	if err != nil {
+		ctx.WithField("err", err).Error("newRawKey failed")
+		return nil, err
+	}
+
+	key := &apikey.ApiKey{
+		Id:         uuid.NewString(),
+		Prefix:     raw[:displayChars],
+		Hash:       apikey.Hash(raw),
+		Owner:      owner.ToLower(),
+		Name:       name,
+		Scopes:     scopes,
+		RateLimit:  im.defaultRateLimit,
+		DailyQuota: im.defaultDailyQuota,
+		CreatedAt:  im.now(),
+	}
+
+	if err := im.repo.Insert(ctx, key); err != nil {
+		ctx.WithField("err", err).Error("repo.Insert failed")
+		return nil, err
+	}
+
+	return &apikey.IssuedKey{ApiKey: key, Key: raw}, nil
+}
+
+func (im *impl) FindAll(ctx ctx.Ctx, opts ...apikey.FindAllOptionsFunc) ([]*apikey.ApiKey, error) {
+	return im.repo.FindAll(ctx, opts...)
+}
+
+func (im *impl) FindOne(ctx ctx.Ctx, id string) (*apikey.ApiKey, error) {
+	return im.repo.FindOne(ctx, id)
+}
+
+func (im *impl) Revoke(ctx ctx.Ctx, id string) error {
+	key, err := im.repo.FindOne(ctx, id)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.FindOne failed")
+		return err
+	}
+
+	if key.IsRevoked {
+		return nil
+	}
+
+	isRevoked := true
+	now := im.now()
+	if err := im.repo.Update(ctx, id, &apikey.Updater{IsRevoked: &isRevoked, RevokedAt: &now}); err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.Update failed")
+		return err
+	}
+
+	im.evict(ctx, key)
+	return nil
+}
+
+func (im *impl) UpdateLimits(ctx ctx.Ctx, id string, updater *apikey.Updater) (*apikey.ApiKey, error) {
+	for _, scope := range updater.Scopes {
+		if !scope.IsValid() {
+			ctx.WithField("scope", scope).Warn("invalid scope")
+			return nil, domain.ErrBadParamInput
+		}
+	}
+
+	if (updater.RateLimit != nil && *updater.RateLimit <= 0) || (updater.DailyQuota != nil && *updater.DailyQuota < 0) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	// revocation is not a limit
+	updater.IsRevoked = nil
+	updater.RevokedAt = nil
+
+	if err := im.repo.Update(ctx, id, updater); err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.Update failed")
+		return nil, err
+	}
+
+	key, err := im.repo.FindOne(ctx, id)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.FindOne failed")
+		return nil, err
+	}
+
+	im.evict(ctx, key)
+	return key, nil
+}
+
+func (im *impl) Authenticate(ctx ctx.Ctx, raw string) (*apikey.ApiKey, error) {
+	hash := apikey.Hash(raw)
+
+	key := &apikey.ApiKey{}
+	if err := im.keyCache.GetByFunc(ctx, hash, key, func() (interface{}, error) {
+		return im.repo.FindOneByHash(ctx, hash)
+	}); err == domain.ErrNotFound {
+		return nil, apikey.ErrInvalidKey
+	} else if err != nil {
+		ctx.WithField("err", err).Error("keyCache.GetByFunc failed")
+		return nil, err
+	}
+
+	if key.IsRevoked {
+		return nil, apikey.ErrRevoked
+	}
+
+	return key, nil
+}
+
+func (im *impl) IncrUsage(ctx ctx.Ctx, id string) (int64, error) {
+	key := usageKey(id, im.now())
+
+	count, err := im.redis.Incr(ctx, key)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"key": key,
+		}).Error("redis.Incr failed")
+		return 0, err
+	}
+
+	if count == 1 {
+		if err := im.redis.Expire(ctx, key, usageTtl); err != nil {
+			ctx.WithFields(log.Fields{
+				"err": err,
+				"key": key,
+			}).Warn("redis.Expire failed")
+		}
+	}
+
+	return count, nil
+}
+
+func (im *impl) GetUsage(ctx ctx.Ctx, id string, days int) ([]apikey.Usage, error) {
+	now := im.now()
+
+	res := make([]apikey.Usage, days)
+	redisKeys := make([]string, days)
+	for i := 0; i < days; i++ {
+		date := now.AddDate(0, 0, -i)
+		res[i].Date = date.UTC().Format(usageDateLayout)
+		redisKeys[i] = usageKey(id, date)
+	}
+
+	vals, err := im.redis.MGet(ctx, redisKeys)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("redis.MGet failed")
+		return nil, err
+	}
+
+	for i, val := range vals {
+		if !val.Valid {
+			continue
+		}
+		count, err := strconv.ParseInt(string(val.Value), 10, 64)
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"err": err,
+				"key": redisKeys[i],
+			}).Error("strconv.ParseInt failed")
+			return nil, err
+		}
+		res[i].Count = count
+	}
+
+	return res, nil
+}
+
+func (im *impl) evict(ctx ctx.Ctx, key *apikey.ApiKey) {
+	if err := im.keyCache.Del(ctx, key.Hash); err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  key.Id,
+		}).Warn("keyCache.Del failed")
+	}
+}
+
+func usageKey(id string, t time.Time) string {
+	return keys.RedisKey(keys.PfxApiKeyUsage, id, t.UTC().Format(usageDateLayout))
+}
+
+func newRawKey() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPfx + hex.EncodeToString(b), nil
+}
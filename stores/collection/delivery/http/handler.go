@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -13,18 +14,23 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/like"
+	"github.com/x-xyz/goapi/domain/moderation"
 	"github.com/x-xyz/goapi/middleware"
 	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 )
 
 var met metrics.Service
 
+// defaultCursorLimit is the page size of cursor paging if limit is not given
+const defaultCursorLimit = 100
+
 type handler struct {
 	account        account.Usecase
 	collection     collection.Usecase
 	authMiddleware *authMiddleware.AuthMiddleware
 	like           like.CollectionLikeUsecase
 	tradingVolume  collection.TradingVolumeUseCase
+	moderation     moderation.UseCase
 }
 
 func New(
@@ -33,10 +39,11 @@ func New(
 	collection collection.Usecase,
 	authMiddleware *authMiddleware.AuthMiddleware,
 	like like.CollectionLikeUsecase,
-	tradingVolume collection.TradingVolumeUseCase) {
+	tradingVolume collection.TradingVolumeUseCase,
+	moderation moderation.UseCase) {
 	met = metrics.New("collection")
 
-	h := &handler{account, collection, authMiddleware, like, tradingVolume}
+	h := &handler{account, collection, authMiddleware, like, tradingVolume, moderation}
 
 	gs := e.Group("/collections")
 
@@ -72,11 +79,19 @@ func New(
 
 	g.PUT("/info", h.updateInfo, authMiddleware.Auth())
 
+	g.GET("/ownership", h.verifyOwnership, authMiddleware.Auth())
+
+	g.PUT("/royalty", h.updateRoyalty, authMiddleware.Auth())
+
+	g.PUT("/editable-accounts", h.updateEditableAccounts, authMiddleware.Auth())
+
 	g.PUT("/trait-floor", h.updateTraitFloor)
 
 	g.GET("/activities", h.getActivities)
 
 	g.GET("/globalofferstat", h.getGlobalOfferStat)
+
+	g.GET("/holders", h.getHolderStats, middleware.CacheHttp(1*time.Minute))
 }
 
 func (h *handler) getAll(c echo.Context) error {
@@ -95,7 +110,20 @@ func (h *handler) getAll(c echo.Context) error {
 		collection.WithSort(sortBy, sortDir),
 	}
 
-	if p.Offset != 0 || p.Limit != 0 {
+	if p.Cursor != nil {
+		// holding sorts are done in memory after the query, which can't be paged by cursor
+		if p.SortBy == collection.SearchSortOptionHoldingAsc || p.SortBy == collection.SearchSortOptionHoldingDesc {
+			return delivery.MakeJsonResp(c, http.StatusBadRequest, "cursor is not supported by holding sorts")
+		}
+		if p.IncludeUnregistered != nil && *p.IncludeUnregistered {
+			return delivery.MakeJsonResp(c, http.StatusBadRequest, "cursor is not supported with unregistered collections")
+		}
+		limit := p.Limit
+		if limit == 0 {
+			limit = defaultCursorLimit
+		}
+		opts = append(opts, collection.WithCursor(*p.Cursor), collection.WithPagination(0, limit))
+	} else if p.Offset != 0 || p.Limit != 0 {
 		opts = append(opts, collection.WithPagination(p.Offset, p.Limit))
 	} else {
 		// for backward compatible
@@ -134,6 +162,10 @@ func (h *handler) getAll(c echo.Context) error {
 		opts = append(opts, collection.WithAddresses(domain.YugaLabCollectionAddresses))
 	}
 
+	if p.IncludeSpam == nil || !*p.IncludeSpam {
+		opts = append(opts, collection.WithIsSpam(false))
+	}
+
 	var (
 		res             []*collection.CollectionWithHoldingCount
 		pagingRes       *collection.SearchResult
@@ -169,7 +201,15 @@ func (h *handler) getAll(c echo.Context) error {
 		res, err = h.collection.FindAllIncludingUnregistered(ctx, opts...)
 	} else {
 		pagingRes, err = h.collection.FindAll(ctx, opts...)
-		res = pagingRes.Items
+		if err == nil {
+			res = pagingRes.Items
+		}
+	}
+
+	if err == domain.ErrInvalidCursor {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
 	if p.Holder != nil {
@@ -182,13 +222,10 @@ func (h *handler) getAll(c echo.Context) error {
 		collection.SortCollectionWithHoldingCount(res, p.SortBy)
 	}
 
-	if err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	} else if p.IsPaging != nil && *p.IsPaging {
+	if (p.IsPaging != nil && *p.IsPaging) || p.Cursor != nil {
 		return delivery.MakeJsonResp(c, http.StatusOK, pagingRes)
-	} else {
-		return delivery.MakeJsonResp(c, http.StatusOK, res)
 	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
 }
 
 func (h *handler) getMintable(c echo.Context) error {
@@ -334,7 +371,9 @@ func (h *handler) register(c echo.Context) error {
 
 	p.Owner = address
 
-	if res, err := h.collection.Register(ctx, p.Registration); err != nil {
+	if res, err := h.collection.Register(ctx, p.Registration); errors.Is(err, collection.ErrNotCollectionOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusCreated, res)
@@ -386,6 +425,7 @@ func (h *handler) ban(c echo.Context) error {
 		ChainId   domain.ChainId `param:"chainId"`
 		Contract  domain.Address `param:"contract"`
 		Signature string         `json:"signature"`
+		Reason    string         `json:"reason"`
 	}
 
 	p := params{}
@@ -398,9 +438,13 @@ func (h *handler) ban(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
 	}
 
-	collectionId := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
+	target := moderation.Target{Type: moderation.TargetTypeCollection, ChainId: p.ChainId, ContractAddress: p.Contract}
+	if err := h.moderation.Ban(ctx, signer, target, p.Reason); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
 
-	if res, err := h.collection.Ban(ctx, collectionId, true); err != nil {
+	collectionId := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
+	if res, err := h.collection.FindOne(ctx, collectionId); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusCreated, res)
@@ -415,6 +459,7 @@ func (h *handler) unban(c echo.Context) error {
 		ChainId   domain.ChainId `param:"chainId"`
 		Contract  domain.Address `param:"contract"`
 		Signature string         `json:"signature"`
+		Reason    string         `json:"reason"`
 	}
 
 	p := params{}
@@ -427,8 +472,13 @@ func (h *handler) unban(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
 	}
 
+	target := moderation.Target{Type: moderation.TargetTypeCollection, ChainId: p.ChainId, ContractAddress: p.Contract}
+	if err := h.moderation.Unban(ctx, signer, target, p.Reason); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
 	collectionId := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
-	if res, err := h.collection.Ban(ctx, collectionId, false); err != nil {
+	if res, err := h.collection.FindOne(ctx, collectionId); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusCreated, res)
@@ -538,6 +588,101 @@ func (h *handler) updateInfo(c echo.Context) error {
 	return delivery.MakeJsonResp(c, http.StatusAccepted, "ok")
 }
 
+func (h *handler) verifyOwnership(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		ChainId  domain.ChainId `param:"chainId"`
+		Contract domain.Address `param:"contract"`
+	}
+
+	p := params{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	id := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
+
+	if res, err := h.collection.VerifyOwnership(ctx, id, address); errors.Is(err, collection.ErrNotCollectionOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	} else {
+		return delivery.MakeJsonResp(c, http.StatusOK, res)
+	}
+}
+
+func (h *handler) updateRoyalty(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		ChainId   domain.ChainId `param:"chainId"`
+		Contract  domain.Address `param:"contract"`
+		Signature string         `json:"signature"`
+		collection.RoyaltyPayload
+	}
+
+	p := params{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.account.ValidateSignature(ctx, address, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	id := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
+
+	if err := h.collection.UpdateRoyalty(ctx, id, address, p.RoyaltyPayload); errors.Is(err, collection.ErrNotCollectionOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if errors.Is(err, collection.ErrInvalidRoyalty) || errors.Is(err, domain.ErrInvalidAddress) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusAccepted, "ok")
+}
+
+func (h *handler) updateEditableAccounts(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		ChainId          domain.ChainId   `param:"chainId"`
+		Contract         domain.Address   `param:"contract"`
+		Signature        string           `json:"signature"`
+		EditableAccounts []domain.Address `json:"editableAccounts"`
+	}
+
+	p := params{}
+
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.account.ValidateSignature(ctx, address, p.Signature); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
+	}
+
+	id := collection.CollectionId{ChainId: p.ChainId, Address: p.Contract}
+
+	if err := h.collection.UpdateEditableAccounts(ctx, id, address, p.EditableAccounts); errors.Is(err, collection.ErrNotCollectionOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusAccepted, "ok")
+}
+
 func (h *handler) likeCollection(c echo.Context) error {
 	id := collection.CollectionId{}
 
@@ -643,6 +788,7 @@ func (h *handler) updateTraitFloor(c echo.Context) error {
 //	@Param			address	path		string		true	"collection address"												example(0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d)
 //	@Param			offset	query		int			false	"paging offset"														example(0)
 //	@Param			limit	query		int			false	"paging size"														example(100)
+//	@Param			cursor	query		string		false	"paging cursor, empty for the first page. offset is ignored if given"
 //	@Param			types	query		[]string	false	"activity types (sold is only used in legacy on-chain marketplace)"	enums(sale, list, createOffer, sold, cancelListing, cancelOffer)	collectionFormat(multi)	example(sale)
 //	@Success		200		{object}	collection.ActivityResult
 //	@Failure		400
@@ -656,6 +802,7 @@ func (h *handler) getActivities(c echo.Context) error {
 		Offset int                           `query:"offset"`
 		Limit  int                           `query:"limit"`
 		Types  []account.ActivityHistoryType `query:"types"`
+		Cursor *string                       `query:"cursor"`
 	}
 
 	p := &params{}
@@ -673,8 +820,14 @@ func (h *handler) getActivities(c echo.Context) error {
 		opts = append(opts, account.ActivityHistoryWithTypes(p.Types...))
 	}
 
+	if p.Cursor != nil {
+		opts = append(opts, account.ActivityHistoryWithCursor(*p.Cursor))
+	}
+
 	id := collection.CollectionId{ChainId: p.ChainId, Address: p.Address}
-	if res, err := h.collection.GetActivities(ctx, id, opts...); err != nil {
+	if res, err := h.collection.GetActivities(ctx, id, opts...); err == domain.ErrInvalidCursor {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
@@ -712,3 +865,34 @@ func (h *handler) getGlobalOfferStat(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
 	}
 }
+
+// getHolderStats
+//
+//	@Summary	Get holder distribution, concentration and churn of a collection
+//	@Tags		collections
+//	@Produce	json
+//	@Param		chainId	path		int		true	"chain id"				example(1)
+//	@Param		address	path		string	true	"collection address"	example(0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d)
+//	@Success	200		{object}	collection.HolderStats
+//	@Failure	400
+//	@Failure	404
+//	@Failure	500
+//	@Router		/collection/{chainId}/{address}/holders [get]
+func (h *handler) getHolderStats(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	p := struct {
+		collection.CollectionId
+	}{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if res, err := h.collection.GetHolderStats(ctx, p.CollectionId); errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	} else {
+		return delivery.MakeJsonResp(c, http.StatusOK, res)
+	}
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"regexp"
+
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/spam"
+	"github.com/x-xyz/goapi/service/keyset"
 	"github.com/x-xyz/goapi/service/query"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -41,6 +46,23 @@ func makeFindQuery(optFns ...collection.FindAllOptions) (bson.M, error) {
 		query["isInternal"] = *opts.IsInternal
 	}
 
+	if opts.IsVerified != nil {
+		query["isVerified"] = *opts.IsVerified
+	}
+
+	if opts.IsSpam != nil {
+		if *opts.IsSpam {
+			query["spamScore"] = bson.M{"$gte": spam.Threshold}
+		} else {
+			// collections never classified have no spam score
+			query["spamScore"] = bson.M{"$not": bson.M{"$gte": spam.Threshold}}
+		}
+	}
+
+	if opts.CollectionName != nil {
+		query["collectionName"] = bson.M{"$regex": "^" + regexp.QuoteMeta(*opts.CollectionName) + "$", "$options": "i"}
+	}
+
 	if opts.IsOwnerble != nil {
 		query["isOwnerble"] = *opts.IsOwnerble
 	}
@@ -111,8 +133,6 @@ func (im *collectionImpl) FindAll(c ctx.Ctx, optFns ...collection.FindAllOptions
 
 	limit := int(0)
 
-	sort := []string{"_id"}
-
 	query, err := makeFindQuery(optFns...)
 	if err != nil {
 		return res, err
@@ -126,29 +146,25 @@ func (im *collectionImpl) FindAll(c ctx.Ctx, optFns ...collection.FindAllOptions
 		limit = int(*opts.Limit)
 	}
 
-	if opts.SortBy != nil && opts.SortDir != nil {
-		sort = []string{}
-		sortBy := *opts.SortBy
-
-		// to keep empty data has lowest order by any way
-		switch sortBy {
-		case "floorPrice":
-			fallthrough
-		case "usdFloorPrice":
-			sort = append(sort, "-hasFloorPrice")
-		case "lastSoldAt":
-			sort = append(sort, "-hasBeenSold")
-		case "lastListedAt":
-			sort = append(sort, "-hasBeenListed")
-		}
-
-		if *opts.SortDir == domain.SortDirDesc {
-			sortBy = "-" + sortBy
-		}
-		sort = append(sort, sortBy)
+	if opts.SortBy != nil && opts.SortDir != nil && len(query) == 0 {
+		query[*opts.SortBy] = bson.M{"$exists": true}
+	}
 
-		if len(query) == 0 {
-			query[*opts.SortBy] = bson.M{"$exists": true}
+	sort := opts.SortFields()
+
+	if opts.Cursor != nil {
+		offset = 0
+		sort = keyset.Sorts(sort)
+		if *opts.Cursor != "" {
+			after, err := keyset.Query(*opts.Cursor, sort)
+			if err != nil {
+				c.WithFields(log.Fields{
+					"err":    err,
+					"cursor": *opts.Cursor,
+				}).Warn("keyset.Query failed")
+				return res, err
+			}
+			query = bson.M{"$and": bson.A{query, after}}
 		}
 	}
 
@@ -174,6 +190,31 @@ func (im *collectionImpl) Count(c ctx.Ctx, opts ...collection.FindAllOptions) (i
 	return res, nil
 }
 
+// NextCursor encodes the stored document instead of Collection so that missing sort fields are encoded as null
+func (im *collectionImpl) NextCursor(c ctx.Ctx, last collection.CollectionId, optFns ...collection.FindAllOptions) (string, error) {
+	opts, err := collection.GetFindAllOptions(optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("collection.GetFindAllOptions failed")
+		return "", err
+	}
+
+	qry, err := mongoclient.MakeBsonM(collection.Collection{ChainId: last.ChainId, Erc721Address: last.Address})
+	if err != nil {
+		c.WithField("err", err).Error("mongoclient.MakeBsonM failed")
+		return "", err
+	}
+
+	var doc bson.Raw
+	if err := im.q.FindOne(c, domain.TableCollections, qry, &doc); err == query.ErrNotFound {
+		return "", domain.ErrNotFound
+	} else if err != nil {
+		c.WithField("err", err).Error("q.FindOne failed")
+		return "", err
+	}
+
+	return keyset.Encode(keyset.Sorts(opts.SortFields()), doc)
+}
+
 func (im *collectionImpl) FindOne(c ctx.Ctx, id collection.CollectionId) (*collection.Collection, error) {
 	res := &collection.Collection{}
 
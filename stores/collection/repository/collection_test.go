@@ -10,6 +10,7 @@ import (
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/service/query"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type collectionSuite struct {
@@ -113,6 +114,11 @@ func (s *collectionSuite) TestFindAll() {
 		output, err := s.im.FindAll(ctx, c.queryOptions...)
 		s.Nil(err)
 
+		// don't care objectId
+		for _, item := range output {
+			item.ObjectId = primitive.NilObjectID
+		}
+
 		s.ElementsMatch(c.want, output)
 	}
 }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+type holderStatsRepo struct {
+	q query.Mongo
+}
+
+func NewHolderStatsRepo(q query.Mongo) collection.HolderStatsRepo {
+	return &holderStatsRepo{q: q}
+}
+
+func (r *holderStatsRepo) FindOne(ctx bCtx.Ctx, id collection.HolderStatsId) (*collection.HolderStats, error) {
+	s := &collection.HolderStats{}
+	err := r.q.FindOne(ctx, domain.TableCollectionHolderStats, id, s)
+	if err == query.ErrNotFound {
+		return nil, domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("q.FindOne failed")
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *holderStatsRepo) Upsert(ctx bCtx.Ctx, s collection.HolderStats) error {
+	if err := r.q.Upsert(ctx, domain.TableCollectionHolderStats, s.ToId(), s); err != nil {
+		ctx.WithFields(log.Fields{
+			"holderStats": s,
+			"err":         err,
+		}).Error("q.Upsert failed")
+		return err
+	}
+	return nil
+}
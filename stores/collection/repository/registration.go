@@ -56,3 +56,19 @@ func (im *registrationImpl) Patch(c ctx.Ctx, id collection.CollectionId, value c
 	}
 	return nil
 }
+
+func (im *registrationImpl) PatchSpamScore(c ctx.Ctx, id collection.CollectionId, score float64) error {
+	if err := im.q.Patch(c, domain.TableCollectionRegistrations, id, bson.M{"spamScore": score}); err != nil {
+		c.WithField("err", err).Error("q.Patch failed")
+		return err
+	}
+	return nil
+}
+
+func (im *registrationImpl) Remove(c ctx.Ctx, id collection.CollectionId) error {
+	if err := im.q.Remove(c, domain.TableCollectionRegistrations, id); err != nil {
+		c.WithField("err", err).Error("q.Remove failed")
+		return err
+	}
+	return nil
+}
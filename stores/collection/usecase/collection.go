@@ -23,6 +23,7 @@ import (
 	"github.com/x-xyz/goapi/domain/like"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/search"
 	"github.com/x-xyz/goapi/domain/token"
 	"github.com/x-xyz/goapi/service/chain/contract"
 	"github.com/x-xyz/goapi/service/pinata"
@@ -47,6 +48,11 @@ type CollectionUseCaseCfg struct {
 	LikeRepo              like.Repo
 	PromotedCollectionsUC collection_promotion.CollPromotionUsecase
 	TokenUC               token.Usecase
+	SearchIndexer         search.Indexer
+	// OwnershipContract verifies the collection owner, registration is rejected if nil
+	OwnershipContract contract.OwnershipContract
+	// HolderStatsRepo stores precomputed holder stats, holder stats are not available if nil
+	HolderStatsRepo collection.HolderStatsRepo
 }
 
 type impl struct {
@@ -67,6 +73,9 @@ type impl struct {
 	likeRepo              like.Repo
 	promotedCollectionsUC collection_promotion.CollPromotionUsecase
 	tokenUC               token.Usecase
+	searchIdx             search.Indexer
+	ownership             contract.OwnershipContract
+	holderStats           collection.HolderStatsRepo
 }
 
 func NewCollection(cfg *CollectionUseCaseCfg) collection.Usecase {
@@ -88,6 +97,9 @@ func NewCollection(cfg *CollectionUseCaseCfg) collection.Usecase {
 		likeRepo:              cfg.LikeRepo,
 		promotedCollectionsUC: cfg.PromotedCollectionsUC,
 		tokenUC:               cfg.TokenUC,
+		searchIdx:             cfg.SearchIndexer,
+		ownership:             cfg.OwnershipContract,
+		holderStats:           cfg.HolderStatsRepo,
 	}
 }
 
@@ -176,10 +188,14 @@ func (im *impl) FindAll(c ctx.Ctx, opts ...collection.FindAllOptions) (*collecti
 		col.IsRegistered = true
 	}
 
-	cnt, err := im.collection.Count(c, opts...)
-	if err != nil {
-		c.WithField("err", err).Error("FindAll failed")
-		return nil, err
+	// collections are counted on the first page only when paging by cursor
+	cnt := 0
+	if options.Cursor == nil || *options.Cursor == "" {
+		cnt, err = im.collection.Count(c, opts...)
+		if err != nil {
+			c.WithField("err", err).Error("FindAll failed")
+			return nil, err
+		}
 	}
 
 	res := &collection.SearchResult{
@@ -191,6 +207,14 @@ func (im *impl) FindAll(c ctx.Ctx, opts ...collection.FindAllOptions) (*collecti
 		res.Items = append(res.Items, &collection.CollectionWithHoldingCount{Collection: *i})
 	}
 
+	if options.Cursor != nil && options.Limit != nil && *options.Limit > 0 && len(items) == int(*options.Limit) {
+		res.NextCursor, err = im.collection.NextCursor(c, items[len(items)-1].ToId(), opts...)
+		if err != nil {
+			c.WithField("err", err).Error("collection.NextCursor failed")
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
@@ -455,6 +479,21 @@ func (im *impl) Register(c ctx.Ctx, value collection.Registration) (*collection.
 		}
 	}
 
+	id := collection.CollectionId{ChainId: value.ChainId, Address: value.Erc721Address}
+	if _, err := im.VerifyOwnership(c, id, value.Owner); err != nil {
+		return nil, err
+	}
+
+	// registration by the owner replaces the pending one created by contract discovery
+	value.Discovered = false
+	value.SpamScore = 0
+	if reg, err := im.registration.FindOne(c, id); err == nil && reg.Discovered && reg.State == collection.RegistrationStatePending {
+		if err := im.registration.Remove(c, id); err != nil {
+			c.WithField("err", err).Error("registration.Remove failed")
+			return nil, err
+		}
+	}
+
 	if len(value.LogoImage) > 0 {
 		opts := pinata.PinOptions{
 			Metadata: &pinata.PinataMetadata{
@@ -493,7 +532,7 @@ func (im *impl) Register(c ctx.Ctx, value collection.Registration) (*collection.
 		return nil, err
 	}
 
-	col, err := im.registration.FindOne(c, collection.CollectionId{ChainId: value.ChainId, Address: value.Erc721Address})
+	col, err := im.registration.FindOne(c, id)
 	if err != nil {
 		c.WithField("err", err).Error("collection.FindOne failed")
 		return nil, err
@@ -508,7 +547,8 @@ func (im *impl) Accept(c ctx.Ctx, id collection.CollectionId) (*collection.Colle
 		return nil, err
 	}
 
-	if !common.IsHexAddress(reg.FeeRecipient) {
+	// discovered contracts have no royalty and fee recipient until the owner sets them
+	if reg.Royalty > 0 && !common.IsHexAddress(reg.FeeRecipient) {
 		//	@todo	send email
 		//	@todo	remove collection
 		return nil, domain.ErrInvalidAddress
@@ -614,6 +654,8 @@ func (im *impl) Accept(c ctx.Ctx, id collection.CollectionId) (*collection.Colle
 		return nil, err
 	}
 
+	im.indexCollection(c, id)
+
 	//	@todo	send email
 
 	return im.FindOne(c, id)
@@ -652,6 +694,7 @@ func (im *impl) Ban(c ctx.Ctx, id collection.CollectionId, ban bool) (*collectio
 			return nil, err
 		}
 	}
+	im.indexCollection(c, id)
 	return col, nil
 }
 
@@ -806,6 +849,7 @@ func (im *impl) calculateOpenrarityScoreAndRank(ctx ctx.Ctx, id collection.Colle
 func (im *impl) RefreshStat(c ctx.Ctx, id collection.CollectionId) error {
 	supply := int64(0)
 	attrs := map[string]map[string]int64{}
+	attrRanges := map[string]collection.AttributeRange{}
 	var fpItems []*order.OrderItem
 	traitsColl := map[string]map[string][]domain.TokenId{}
 	traitsFp := map[string]map[string]float64{}
@@ -845,8 +889,33 @@ func (im *impl) RefreshStat(c ctx.Ctx, id collection.CollectionId) error {
 
 		supply += int64(len(items))
 
+		// backfill items indexed before attributes are normalized, once backfilled nothing is written here
+		backfills := []nftitem.PatchOp{}
+		for _, item := range items {
+			if !nftitem.IsNormalized(item.Attributes) {
+				item.Attributes = nftitem.NormalizeAttributes(item.Attributes)
+				backfills = append(backfills, nftitem.PatchOp{Id: *item.ToId(), Value: nftitem.PatchableNftItem{Attributes: item.Attributes}})
+			}
+		}
+		if err := im.nftitem.BulkPatch(c, backfills); err != nil {
+			c.WithFields(log.Fields{
+				"err":   err,
+				"count": len(backfills),
+			}).Warn("nftitem.BulkPatch failed")
+		}
+
 		for _, item := range items {
+
 			for _, attr := range item.Attributes {
+				if attr.ValueType == nftitem.AttributeValueTypeNumber {
+					r, ok := attrRanges[attr.TraitType]
+					if !ok {
+						r = collection.AttributeRange{Min: *attr.NumberValue, Max: *attr.NumberValue}
+					}
+					r.Add(*attr.NumberValue)
+					attrRanges[attr.TraitType] = r
+				}
+
 				// If an attribute has display type, then it it more like stats.
 				// No need to use such attribue as filter
 				if len(attr.DisplayType) > 0 {
@@ -985,6 +1054,7 @@ func (im *impl) RefreshStat(c ctx.Ctx, id collection.CollectionId) error {
 	updater := collection.UpdatePayload{
 		Supply:                    supply,
 		Attributes:                attrs,
+		AttributeRanges:           attrRanges,
 		NumOwners:                 numOwners,
 		HasFloorPrice:             &hasFloorPrice,
 		FloorPriceInNative:        &floorPriceInNative,
@@ -1264,6 +1334,7 @@ func (im *impl) UpdateInfo(c ctx.Ctx, id collection.CollectionId, info collectio
 		}).Error("collection.Update failed")
 		return err
 	}
+	im.indexCollection(c, id)
 	return nil
 }
 
@@ -1473,15 +1544,30 @@ func (im *impl) GetActivities(c ctx.Ctx, id collection.CollectionId, optFns ...a
 		return nil, err
 	}
 
-	count, err := im.activityHistoryRepo.CountActivities(c, activityOpts...)
+	shouldCount, err := account.ShouldCountActivityHistories(activityOpts...)
 	if err != nil {
-		c.WithFields(log.Fields{
-			"err":  err,
-			"opts": activityOpts,
-		}).Error("CountActivities failed")
+		c.WithField("err", err).Error("account.ShouldCountActivityHistories failed")
+		return nil, err
+	}
+
+	count := 0
+	if shouldCount {
+		count, err = im.activityHistoryRepo.CountActivities(c, activityOpts...)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err":  err,
+				"opts": activityOpts,
+			}).Error("CountActivities failed")
+			return nil, err
+		}
+	}
+	nextCursor, err := account.NextActivityHistoryCursor(items, activityOpts...)
+	if err != nil {
+		c.WithField("err", err).Error("account.NextActivityHistoryCursor failed")
 		return nil, err
 	}
-	return &collection.ActivityResult{Items: items, Count: count}, nil
+
+	return &collection.ActivityResult{Items: items, Count: count, NextCursor: nextCursor}, nil
 }
 
 func (im *impl) GetGlobalOfferStats(c ctx.Ctx, id collection.CollectionId) (*collection.GlobalOfferStatResult, error) {
@@ -1549,3 +1635,16 @@ func (im *impl) GetGlobalOfferStats(c ctx.Ctx, id collection.CollectionId) (*col
 		Rows: rows,
 	}, nil
 }
+
+// indexCollection refreshes the collection in search index, failure is ignored as index will be synced later
+func (im *impl) indexCollection(c ctx.Ctx, id collection.CollectionId) {
+	if im.searchIdx == nil {
+		return
+	}
+	if err := im.searchIdx.IndexCollection(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Warn("searchIdx.IndexCollection failed")
+	}
+}
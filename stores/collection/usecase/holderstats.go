@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	"github.com/x-xyz/goapi/domain/nftitem"
+)
+
+const (
+	numTopHolders = 10
+	// activities are replayed from the latest one in pages, history older than maxHolderHistory activities is ignored
+	holderHistoryPageSize = 1000
+	maxHolderHistory      = 100000
+	holderItemPageSize    = 1000
+)
+
+// churnPeriods are the periods of new and departed holders
+var churnPeriods = []struct {
+	period   collection.PeriodType
+	duration time.Duration
+}{
+	{collection.PeriodTypeDay, day},
+	{collection.PeriodTypeWeek, 7 * day},
+	{collection.PeriodTypeMonth, 30 * day},
+}
+
+// heldItem is quantity of a token held by the owner
+type heldItem struct {
+	tokenId  domain.TokenId
+	owner    domain.Address
+	quantity int64
+}
+
+func (im *impl) GetHolderStats(c ctx.Ctx, id collection.CollectionId) (*collection.HolderStats, error) {
+	if im.holderStats == nil {
+		return nil, domain.ErrNotFound
+	}
+	return im.holderStats.FindOne(c, collection.HolderStatsId{ChainId: id.ChainId, Address: id.Address.ToLower()})
+}
+
+func (im *impl) RefreshHolderStats(c ctx.Ctx, id collection.CollectionId) error {
+	if im.holderStats == nil {
+		return nil
+	}
+	id.Address = id.Address.ToLower()
+	logger := c.WithFields(log.Fields{
+		"chainId": id.ChainId,
+		"address": id.Address,
+	})
+
+	col, err := im.collection.FindOne(c, id)
+	if err != nil {
+		logger.WithField("err", err).Error("collection.FindOne failed")
+		return err
+	}
+
+	var held []heldItem
+	if col.TokenType == domain.TokenType1155 {
+		held, err = im.getHeld1155(c, id)
+	} else {
+		held, err = im.getHeld721(c, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	replay := newHolderReplay(held, now)
+	for cursor, n := "", 0; n < maxHolderHistory && !replay.done(); n += holderHistoryPageSize {
+		optFns := []account.FindActivityHistoryOptions{
+			account.ActivityHistoryWithCollection(id.ChainId, id.Address),
+			account.ActivityHistoryWithTypes(account.ActivityHistoryTypeTransfer, account.ActivityHistoryTypeMint),
+			account.ActivityHistoryWithSource(account.SourceX),
+			account.ActivityHistoryWithPagination(0, holderHistoryPageSize),
+			account.ActivityHistoryWithCursor(cursor),
+		}
+		activities, err := im.activityHistoryRepo.FindActivities(c, optFns...)
+		if err != nil {
+			logger.WithField("err", err).Error("activityHistoryRepo.FindActivities failed")
+			return err
+		}
+		for _, a := range activities {
+			replay.add(a)
+		}
+		if cursor, err = account.NextActivityHistoryCursor(activities, optFns...); err != nil {
+			logger.WithField("err", err).Error("account.NextActivityHistoryCursor failed")
+			return err
+		} else if cursor == "" {
+			break
+		}
+	}
+
+	stats := computeHolderStats(held)
+	stats.ChainId = id.ChainId
+	stats.Address = id.Address
+	stats.AvgHoldingDays, stats.Churn = replay.finish()
+	stats.UpdatedAt = now
+	return im.holderStats.Upsert(c, stats)
+}
+
+func (im *impl) getHeld721(c ctx.Ctx, id collection.CollectionId) ([]heldItem, error) {
+	held := []heldItem{}
+	for offset := int32(0); ; offset += holderItemPageSize {
+		items, err := im.nftitem.FindAll(c,
+			nftitem.WithChainId(id.ChainId),
+			nftitem.WithContractAddresses([]domain.Address{id.Address}),
+			nftitem.WithSort("_id", domain.SortDirAsc),
+			nftitem.WithPagination(offset, holderItemPageSize),
+		)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("nftitem.FindAll failed")
+			return nil, err
+		}
+		for _, item := range items {
+			// lazy minted items are not on chain yet
+			if item.LazyMintVoucherId != "" || !isHolder(item.Owner) {
+				continue
+			}
+			held = append(held, heldItem{tokenId: item.TokenId, owner: item.Owner.ToLower(), quantity: 1})
+		}
+		if len(items) < holderItemPageSize {
+			return held, nil
+		}
+	}
+}
+
+func (im *impl) getHeld1155(c ctx.Ctx, id collection.CollectionId) ([]heldItem, error) {
+	holdings, err := im.erc1155holding.FindAll(c, erc1155.WithHoldingAddress(id.Address))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("erc1155holding.FindAll failed")
+		return nil, err
+	}
+	held := []heldItem{}
+	for _, h := range holdings {
+		if h.ChainId != id.ChainId || h.Balance <= 0 || !isHolder(h.Owner) {
+			continue
+		}
+		held = append(held, heldItem{tokenId: h.TokenId, owner: h.Owner.ToLower(), quantity: h.Balance})
+	}
+	return held, nil
+}
+
+func isHolder(address domain.Address) bool {
+	return !address.IsEmpty() && !address.Equals(domain.EmptyAddress)
+}
+
+// computeHolderStats computes stats from current holdings, stats from transfer history are left empty
+func computeHolderStats(held []heldItem) collection.HolderStats {
+	counts := map[domain.Address]int64{}
+	var numItems int64
+	for _, h := range held {
+		counts[h.owner] += h.quantity
+		numItems += h.quantity
+	}
+
+	byBucket := map[collection.HolderBucket]*collection.HolderDistribution{}
+	distribution := []collection.HolderDistribution{}
+	for _, b := range collection.HolderBuckets {
+		distribution = append(distribution, collection.HolderDistribution{Bucket: b})
+	}
+	for i := range distribution {
+		byBucket[distribution[i].Bucket] = &distribution[i]
+	}
+
+	holders := []collection.TopHolder{}
+	for owner, n := range counts {
+		d := byBucket[collection.ToHolderBucket(n)]
+		d.Holders++
+		d.Items += n
+		holders = append(holders, collection.TopHolder{Address: owner, Items: n, Share: float64(n) / float64(numItems)})
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if holders[i].Items != holders[j].Items {
+			return holders[i].Items > holders[j].Items
+		}
+		return holders[i].Address < holders[j].Address
+	})
+
+	stats := collection.HolderStats{
+		NumHolders:    int64(len(holders)),
+		NumItems:      numItems,
+		Distribution:  distribution,
+		Concentration: gini(holders),
+	}
+	if len(holders) > numTopHolders {
+		holders = holders[:numTopHolders]
+	}
+	stats.TopHolders = holders
+	return stats
+}
+
+// gini returns the gini coefficient of items held, holders are in descending order of items
+func gini(holders []collection.TopHolder) float64 {
+	n := len(holders)
+	if n == 0 {
+		return 0
+	}
+	var sum, weighted float64
+	for i, h := range holders {
+		// rank in ascending order starting from 1
+		rank := float64(n - i)
+		sum += float64(h.Items)
+		weighted += rank * float64(h.Items)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 2*weighted/(float64(n)*sum) - float64(n+1)/float64(n)
+}
+
+type heldKey struct {
+	tokenId domain.TokenId
+	owner   domain.Address
+}
+
+type churnBoundary struct {
+	period collection.PeriodType
+	at     time.Time
+}
+
+// holderReplay reverts transfers from the latest one to find when held items were received,
+// and who held items at the start of churn periods
+type holderReplay struct {
+	now     time.Time
+	current map[domain.Address]int64
+	// counts of items held at the time of the last added activity
+	counts map[domain.Address]int64
+	// quantity of held items whose receiving transfer is not found yet
+	pending     map[heldKey]int64
+	holdingDays float64
+	received    int64
+	boundaries  []churnBoundary
+	churn       []collection.HolderChurn
+}
+
+func newHolderReplay(held []heldItem, now time.Time) *holderReplay {
+	r := &holderReplay{
+		now:     now,
+		current: map[domain.Address]int64{},
+		counts:  map[domain.Address]int64{},
+		pending: map[heldKey]int64{},
+		churn:   []collection.HolderChurn{},
+	}
+	for _, h := range held {
+		r.current[h.owner] += h.quantity
+		r.counts[h.owner] += h.quantity
+		r.pending[heldKey{h.tokenId, h.owner}] += h.quantity
+	}
+	for _, p := range churnPeriods {
+		r.boundaries = append(r.boundaries, churnBoundary{period: p.period, at: now.Add(-p.duration)})
+	}
+	return r
+}
+
+// add reverts the activity, activities must be added from the latest one
+func (r *holderReplay) add(a account.ActivityHistory) {
+	for len(r.boundaries) > 0 && a.Time.Before(r.boundaries[0].at) {
+		r.snapshot(r.boundaries[0].period)
+		r.boundaries = r.boundaries[1:]
+	}
+
+	quantity, err := strconv.ParseInt(a.Quantity, 10, 64)
+	if err != nil || quantity <= 0 {
+		quantity = 1
+	}
+	to, from := a.To.ToLower(), a.Account.ToLower()
+
+	key := heldKey{a.TokenId, to}
+	if pending := r.pending[key]; pending > 0 {
+		n := quantity
+		if pending < n {
+			n = pending
+		}
+		r.holdingDays += float64(n) * r.now.Sub(a.Time).Hours() / 24
+		r.received += n
+		if r.pending[key] -= n; r.pending[key] == 0 {
+			delete(r.pending, key)
+		}
+	}
+
+	if isHolder(to) {
+		r.counts[to] -= quantity
+	}
+	if isHolder(from) {
+		r.counts[from] += quantity
+	}
+}
+
+// done is true if older activities change nothing
+func (r *holderReplay) done() bool {
+	return len(r.pending) == 0 && len(r.boundaries) == 0
+}
+
+func (r *holderReplay) snapshot(period collection.PeriodType) {
+	churn := collection.HolderChurn{Period: period}
+	for holder, n := range r.current {
+		if n > 0 && r.counts[holder] <= 0 {
+			churn.New++
+		}
+	}
+	for holder, n := range r.counts {
+		if n > 0 && r.current[holder] <= 0 {
+			churn.Departed++
+		}
+	}
+	r.churn = append(r.churn, churn)
+}
+
+// finish returns average holding days and churns, periods not covered by added activities have no activity
+func (r *holderReplay) finish() (float64, []collection.HolderChurn) {
+	for _, b := range r.boundaries {
+		r.snapshot(b.period)
+	}
+	r.boundaries = nil
+
+	avg := float64(0)
+	if r.received > 0 {
+		avg = r.holdingDays / float64(r.received)
+	}
+	return avg, r.churn
+}
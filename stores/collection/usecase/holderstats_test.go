@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+)
+
+func holder(i int) domain.Address {
+	return domain.Address(fmt.Sprintf("0x%040x", i))
+}
+
+func TestComputeHolderStats(t *testing.T) {
+	req := require.New(t)
+
+	held := []heldItem{}
+	// holder 1 holds 21 items, holder 2 holds 3 items and holders 3 to 14 hold 1 item each
+	for i := 0; i < 21; i++ {
+		held = append(held, heldItem{tokenId: domain.TokenId(fmt.Sprint(i)), owner: holder(1), quantity: 1})
+	}
+	held = append(held, heldItem{tokenId: "100", owner: holder(2), quantity: 3})
+	for i := 3; i <= 14; i++ {
+		held = append(held, heldItem{tokenId: domain.TokenId(fmt.Sprint(100 + i)), owner: holder(i), quantity: 1})
+	}
+
+	stats := computeHolderStats(held)
+	req.Equal(int64(14), stats.NumHolders)
+	req.Equal(int64(36), stats.NumItems)
+	req.Equal([]collection.HolderDistribution{
+		{Bucket: collection.HolderBucketOne, Holders: 12, Items: 12},
+		{Bucket: collection.HolderBucketTwoToFive, Holders: 1, Items: 3},
+		{Bucket: collection.HolderBucketSixTo20, Holders: 0, Items: 0},
+		{Bucket: collection.HolderBucketOver20, Holders: 1, Items: 21},
+	}, stats.Distribution)
+	req.Len(stats.TopHolders, numTopHolders)
+	req.Equal(holder(1), stats.TopHolders[0].Address)
+	req.InDelta(21.0/36, stats.TopHolders[0].Share, 1e-9)
+	req.Equal(holder(2), stats.TopHolders[1].Address)
+	req.Greater(stats.Concentration, 0.5)
+
+	even := computeHolderStats([]heldItem{{tokenId: "1", owner: holder(1), quantity: 2}, {tokenId: "1", owner: holder(2), quantity: 2}})
+	req.InDelta(0, even.Concentration, 1e-9)
+
+	empty := computeHolderStats(nil)
+	req.Equal(int64(0), empty.NumHolders)
+	req.Equal(0.0, empty.Concentration)
+	req.Empty(empty.TopHolders)
+}
+
+func TestHolderReplay(t *testing.T) {
+	req := require.New(t)
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	transfer := func(tokenId string, from, to domain.Address, daysAgo float64) account.ActivityHistory {
+		return account.ActivityHistory{
+			Type:     account.ActivityHistoryTypeTransfer,
+			TokenId:  domain.TokenId(tokenId),
+			Account:  from,
+			To:       to,
+			Quantity: "1",
+			Time:     now.Add(-time.Duration(daysAgo * float64(day))),
+		}
+	}
+
+	replay := newHolderReplay([]heldItem{
+		{tokenId: "1", owner: holder(2), quantity: 1},
+		{tokenId: "2", owner: holder(3), quantity: 1},
+	}, now)
+	// from the latest one
+	activities := []account.ActivityHistory{
+		// holder 1 departs and holder 2 is new within a week
+		transfer("1", holder(1), holder(2), 2),
+		// holder 3 is new within a month
+		transfer("2", holder(4), holder(3), 10),
+		transfer("1", domain.EmptyAddress, holder(1), 40),
+		transfer("2", domain.EmptyAddress, holder(4), 40),
+	}
+	for _, a := range activities[:3] {
+		req.False(replay.done())
+		replay.add(a)
+	}
+	// all items are received and the month started
+	req.True(replay.done())
+	replay.add(activities[3])
+
+	avg, churn := replay.finish()
+	req.InDelta(6, avg, 1e-9)
+	req.Equal([]collection.HolderChurn{
+		{Period: collection.PeriodTypeDay, New: 0, Departed: 0},
+		{Period: collection.PeriodTypeWeek, New: 1, Departed: 1},
+		{Period: collection.PeriodTypeMonth, New: 2, Departed: 2},
+	}, churn)
+}
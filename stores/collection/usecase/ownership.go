@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/service/chain/contract"
+)
+
+// VerifyOwnership tries owner() of Ownable, DEFAULT_ADMIN_ROLE of AccessControl and then the deployer of the contract.
+// contracts not implementing Ownable or AccessControl revert, so call failures are treated as not owned
+func (im *impl) VerifyOwnership(c ctx.Ctx, id collection.CollectionId, account domain.Address) (*collection.OwnershipProof, error) {
+	if im.ownership == nil || account.IsEmpty() {
+		return nil, collection.ErrNotCollectionOwner
+	}
+
+	logger := c.WithFields(log.Fields{
+		"chainId": id.ChainId,
+		"address": id.Address,
+		"account": account,
+	})
+	proof := &collection.OwnershipProof{
+		ChainId: id.ChainId,
+		Address: id.Address.ToLower(),
+		Account: account.ToLower(),
+	}
+
+	if owner, err := im.ownership.Owner(c, int32(id.ChainId), id.Address.ToLowerStr()); err != nil {
+		logger.WithField("err", err).Info("ownership.Owner failed")
+	} else if account.Equals(domain.Address(owner)) {
+		proof.Method = collection.OwnershipMethodOwnable
+	}
+
+	if proof.Method == "" {
+		if ok, err := im.ownership.HasRole(c, int32(id.ChainId), id.Address.ToLowerStr(), contract.DefaultAdminRole, account.ToLowerStr()); err != nil {
+			logger.WithField("err", err).Info("ownership.HasRole failed")
+		} else if ok {
+			proof.Method = collection.OwnershipMethodAccessControl
+		}
+	}
+
+	if proof.Method == "" {
+		if deployer, err := im.ownership.Deployer(c, int32(id.ChainId), id.Address.ToLowerStr()); err != nil {
+			logger.WithField("err", err).Warn("ownership.Deployer failed")
+		} else if account.Equals(domain.Address(deployer)) {
+			proof.Method = collection.OwnershipMethodDeployer
+		}
+	}
+
+	if proof.Method == "" {
+		return nil, collection.ErrNotCollectionOwner
+	}
+	proof.VerifiedAt = time.Now()
+	return proof, nil
+}
+
+func (im *impl) UpdateRoyalty(c ctx.Ctx, id collection.CollectionId, account domain.Address, payload collection.RoyaltyPayload) error {
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := im.collection.FindOne(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("collection.FindOne failed")
+		return err
+	}
+
+	if _, err := im.VerifyOwnership(c, id, account); err != nil {
+		return err
+	}
+
+	patchable := collection.UpdatePayload{
+		Royalty:      &payload.Royalty,
+		FeeRecipient: payload.FeeRecipient.ToLowerStr(),
+	}
+	if err := im.collection.Update(c, id, patchable); err != nil {
+		c.WithFields(log.Fields{
+			"id":            id,
+			"updatePayload": patchable,
+			"err":           err,
+		}).Error("collection.Update failed")
+		return err
+	}
+	im.indexCollection(c, id)
+	return nil
+}
+
+func (im *impl) UpdateEditableAccounts(c ctx.Ctx, id collection.CollectionId, account domain.Address, accounts []domain.Address) error {
+	if _, err := im.collection.FindOne(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("collection.FindOne failed")
+		return err
+	}
+
+	if _, err := im.VerifyOwnership(c, id, account); err != nil {
+		return err
+	}
+
+	editableAccounts := []domain.Address{}
+	seen := map[domain.Address]struct{}{}
+	for _, a := range accounts {
+		a = a.ToLower()
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		editableAccounts = append(editableAccounts, a)
+	}
+
+	patchable := collection.UpdatePayload{EditableAccounts: editableAccounts}
+	if err := im.collection.Update(c, id, patchable); err != nil {
+		c.WithFields(log.Fields{
+			"id":            id,
+			"updatePayload": patchable,
+			"err":           err,
+		}).Error("collection.Update failed")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	"github.com/x-xyz/goapi/service/chain/contract"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+)
+
+var errReverted = errors.New("execution reverted")
+
+func mockOwnership(id collection.CollectionId, account domain.Address, owner string, ownerErr error, admin bool, roleErr error, deployer string) *mContract.OwnershipContract {
+	ownership := &mContract.OwnershipContract{}
+	ownership.On("Owner", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr()).Return(owner, ownerErr).Once()
+	if ownerErr == nil && account.Equals(domain.Address(owner)) {
+		return ownership
+	}
+	ownership.On("HasRole", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr(), contract.DefaultAdminRole, account.ToLowerStr()).Return(admin, roleErr).Once()
+	if roleErr == nil && admin {
+		return ownership
+	}
+	ownership.On("Deployer", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr()).Return(deployer, nil).Once()
+	return ownership
+}
+
+func TestVerifyOwnership(t *testing.T) {
+	req := require.New(t)
+	id := collection.CollectionId{ChainId: 1, Address: "0x3333333333333333333333333333333333333333"}
+	account := domain.Address("0x4444444444444444444444444444444444444444")
+	other := domain.Address("0x5555555555555555555555555555555555555555")
+
+	ownership := mockOwnership(id, account, "0x4444444444444444444444444444444444444444", nil, false, nil, "")
+	im := &impl{ownership: ownership}
+	proof, err := im.VerifyOwnership(bCtx.Background(), id, account)
+	req.NoError(err)
+	req.Equal(collection.OwnershipMethodOwnable, proof.Method)
+	req.Equal(account, proof.Account)
+	ownership.AssertExpectations(t)
+
+	// not Ownable
+	ownership = mockOwnership(id, account, "", errReverted, true, nil, "")
+	im = &impl{ownership: ownership}
+	proof, err = im.VerifyOwnership(bCtx.Background(), id, account)
+	req.NoError(err)
+	req.Equal(collection.OwnershipMethodAccessControl, proof.Method)
+	ownership.AssertExpectations(t)
+
+	// neither Ownable nor AccessControl
+	ownership = mockOwnership(id, account, "", errReverted, false, errReverted, "0x4444444444444444444444444444444444444444")
+	im = &impl{ownership: ownership}
+	proof, err = im.VerifyOwnership(bCtx.Background(), id, account)
+	req.NoError(err)
+	req.Equal(collection.OwnershipMethodDeployer, proof.Method)
+	ownership.AssertExpectations(t)
+
+	ownership = mockOwnership(id, other, "", errReverted, false, errReverted, "0x4444444444444444444444444444444444444444")
+	im = &impl{ownership: ownership}
+	_, err = im.VerifyOwnership(bCtx.Background(), id, other)
+	req.ErrorIs(err, collection.ErrNotCollectionOwner)
+	ownership.AssertExpectations(t)
+
+	im = &impl{}
+	_, err = im.VerifyOwnership(bCtx.Background(), id, account)
+	req.ErrorIs(err, collection.ErrNotCollectionOwner)
+}
+
+func TestUpdateRoyaltyAndEditableAccounts(t *testing.T) {
+	req := require.New(t)
+	id := collection.CollectionId{ChainId: 1, Address: "0x3333333333333333333333333333333333333333"}
+	account := domain.Address("0x4444444444444444444444444444444444444444")
+	other := domain.Address("0x5555555555555555555555555555555555555555")
+	repo := &mCollection.Repo{}
+	repo.On("FindOne", mock.Anything, id).Return(&collection.Collection{ChainId: id.ChainId, Erc721Address: id.Address}, nil)
+	updated := []collection.UpdatePayload{}
+	repo.On("Update", mock.Anything, id, mock.AnythingOfType("collection.UpdatePayload")).
+		Run(func(args mock.Arguments) { updated = append(updated, args.Get(2).(collection.UpdatePayload)) }).
+		Return(nil)
+	ownership := &mContract.OwnershipContract{}
+	ownership.On("Owner", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr()).Return(account.ToLowerStr(), nil)
+	ownership.On("HasRole", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr(), contract.DefaultAdminRole, other.ToLowerStr()).Return(false, nil).Twice()
+	ownership.On("Deployer", mock.Anything, int32(id.ChainId), id.Address.ToLowerStr()).Return("", nil).Twice()
+	im := &impl{collection: repo, ownership: ownership}
+
+	err := im.UpdateRoyalty(bCtx.Background(), id, other, collection.RoyaltyPayload{Royalty: 5, FeeRecipient: other})
+	req.ErrorIs(err, collection.ErrNotCollectionOwner)
+	err = im.UpdateRoyalty(bCtx.Background(), id, account, collection.RoyaltyPayload{Royalty: 101, FeeRecipient: account})
+	req.ErrorIs(err, collection.ErrInvalidRoyalty)
+	req.Empty(updated)
+
+	// zero royalty clears the royalty
+	req.NoError(im.UpdateRoyalty(bCtx.Background(), id, account, collection.RoyaltyPayload{}))
+	req.Len(updated, 1)
+	req.NotNil(updated[0].Royalty)
+	req.Equal(0.0, *updated[0].Royalty)
+
+	err = im.UpdateEditableAccounts(bCtx.Background(), id, other, []domain.Address{other})
+	req.ErrorIs(err, collection.ErrNotCollectionOwner)
+	req.NoError(im.UpdateEditableAccounts(bCtx.Background(), id, account, []domain.Address{"0xAAAA", "0xaaaa", other}))
+	req.Equal([]domain.Address{"0xaaaa", other}, updated[1].EditableAccounts)
+	req.NoError(im.UpdateEditableAccounts(bCtx.Background(), id, account, nil))
+	req.NotNil(updated[2].EditableAccounts)
+	req.Empty(updated[2].EditableAccounts)
+	repo.AssertNumberOfCalls(t, "FindOne", 5)
+	ownership.AssertExpectations(t)
+}
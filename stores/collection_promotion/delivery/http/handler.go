@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -10,18 +11,22 @@ import (
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/collection_promotion"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 )
 
 type handler struct {
 	collPromotion collection_promotion.CollPromotionUsecase
 	collection    collection.Usecase
+	listingReward collection_promotion.ListingRewardUseCase
 }
 
 func New(
 	e *echo.Echo,
 	collPromotion collection_promotion.CollPromotionUsecase,
-	collection collection.Usecase) {
-	h := &handler{collPromotion, collection}
+	collection collection.Usecase,
+	listingReward collection_promotion.ListingRewardUseCase,
+	authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{collPromotion, collection, listingReward}
 
 	gs := e.Group("/collection-promotions")
 
@@ -29,6 +34,10 @@ func New(
 	gs.GET("/activated", h.GetPromotedCollections)
 	gs.GET("/rewards", h.CalculateListingRewards)
 	gs.GET("/last-hour-reward-per-listing", h.GetLastHourAverageRewardPerListing)
+	gs.GET("/distributions", h.GetDistributions)
+	gs.GET("/distributions/:id", h.GetDistribution)
+	gs.GET("/distributions/:id/ledger", h.GetLedger)
+	gs.POST("/distributions", h.Distribute, authMiddleware.Auth(), authMiddleware.IsAdmin())
 	//gs.POST("", h.createCollPromotion)
 
 }
@@ -155,3 +164,95 @@ func (h *handler) GetLastHourAverageRewardPerListing(c echo.Context) error {
 	}
 	return delivery.MakeJsonResp(c, http.StatusOK, res)
 }
+
+func (h *handler) GetDistributions(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		PromotionId string `query:"promotionId"`
+		Offset      int32  `query:"offset"`
+		Limit       int32  `query:"limit"`
+	}
+
+	p := params{Limit: 100}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	opts := []collection_promotion.RewardDistributionFindAllOptionsFunc{
+		collection_promotion.RewardDistributionWithPagination(p.Offset, p.Limit),
+	}
+	if p.PromotionId != "" {
+		opts = append(opts, collection_promotion.RewardDistributionWithPromotionId(p.PromotionId))
+	}
+
+	res, err := h.listingReward.FindDistributions(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+func (h *handler) GetDistribution(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	res, err := h.listingReward.FindDistributions(ctx, collection_promotion.RewardDistributionWithId(c.Param("id")))
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	} else if len(res) == 0 {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, domain.ErrNotFound)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res[0])
+}
+
+func (h *handler) GetLedger(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Owner  domain.Address `query:"owner"`
+		Offset int32          `query:"offset"`
+		Limit  int32          `query:"limit"`
+	}
+
+	p := params{Limit: 100}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	opts := []collection_promotion.RewardLedgerFindAllOptionsFunc{
+		collection_promotion.RewardLedgerWithDistributionId(c.Param("id")),
+		collection_promotion.RewardLedgerWithPagination(p.Offset, p.Limit),
+	}
+	if p.Owner != "" {
+		opts = append(opts, collection_promotion.RewardLedgerWithOwner(p.Owner))
+	}
+
+	res, err := h.listingReward.FindLedger(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// Distribute (re)writes the ledger of a closed period, the scheduler does it for every period
+func (h *handler) Distribute(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type payload struct {
+		BeginEpoch int64 `json:"begin"`
+		EndEpoch   int64 `json:"end"`
+	}
+
+	p := payload{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.listingReward.Distribute(ctx, time.Unix(p.BeginEpoch, 0), time.Unix(p.EndEpoch, 0))
+	if errors.Is(err, domain.ErrBadParamInput) || errors.Is(err, collection_promotion.ErrDistributionNotClosed) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const ledgerBulkSize = 1000
+
+type rewardDistributionImpl struct {
+	q query.Mongo
+}
+
+func NewRewardDistribution(q query.Mongo) collection_promotion.RewardDistributionRepo {
+	return &rewardDistributionImpl{q}
+}
+
+func (im *rewardDistributionImpl) FindAll(c ctx.Ctx, optFns ...collection_promotion.RewardDistributionFindAllOptionsFunc) ([]collection_promotion.RewardDistribution, error) {
+	opts, err := collection_promotion.GetRewardDistributionFindAllOptions(optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("collection_promotion.GetRewardDistributionFindAllOptions failed")
+		return nil, err
+	}
+
+	var (
+		offset int    = 0
+		limit  int    = 0
+		sort   string = "-begin"
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+	if opts.SortBy != nil && opts.SortDir != nil {
+		sort = *opts.SortBy
+		if *opts.SortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+
+	qry, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		c.WithField("err", err).Error("mongoclient.MakeBsonM failed")
+		return nil, err
+	}
+
+	res := []collection_promotion.RewardDistribution{}
+	if err := im.q.Search(c, domain.TableRewardDistributions, offset, limit, sort, qry, &res); err != nil {
+		c.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *rewardDistributionImpl) Upsert(c ctx.Ctx, d *collection_promotion.RewardDistribution) error {
+	if err := im.q.Upsert(c, domain.TableRewardDistributions, bson.M{"id": d.Id}, d); err != nil {
+		c.WithField("err", err).Error("q.Upsert failed")
+		return err
+	}
+	return nil
+}
+
+func (im *rewardDistributionImpl) Remove(c ctx.Ctx, id string) error {
+	if err := im.q.Remove(c, domain.TableRewardDistributions, bson.M{"id": id}); err != nil && err != query.ErrNotFound {
+		c.WithField("err", err).Error("q.Remove failed")
+		return err
+	}
+	return nil
+}
+
+type rewardLedgerImpl struct {
+	q query.Mongo
+}
+
+func NewRewardLedger(q query.Mongo) collection_promotion.RewardLedgerRepo {
+	return &rewardLedgerImpl{q}
+}
+
+func (im *rewardLedgerImpl) FindAll(c ctx.Ctx, optFns ...collection_promotion.RewardLedgerFindAllOptionsFunc) ([]collection_promotion.RewardLedgerEntry, error) {
+	opts, err := collection_promotion.GetRewardLedgerFindAllOptions(optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("collection_promotion.GetRewardLedgerFindAllOptions failed")
+		return nil, err
+	}
+
+	var (
+		offset int    = 0
+		limit  int    = 0
+		sort   string = "owner"
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+	if opts.SortBy != nil && opts.SortDir != nil {
+		sort = *opts.SortBy
+		if *opts.SortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+
+	qry, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		c.WithField("err", err).Error("mongoclient.MakeBsonM failed")
+		return nil, err
+	}
+
+	res := []collection_promotion.RewardLedgerEntry{}
+	if err := im.q.Search(c, domain.TableRewardLedger, offset, limit, sort, qry, &res); err != nil {
+		c.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *rewardLedgerImpl) BulkUpsert(c ctx.Ctx, entries []collection_promotion.RewardLedgerEntry) error {
+	for begin := 0; begin < len(entries); begin += ledgerBulkSize {
+		end := begin + ledgerBulkSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		ops := make([]query.UpsertOp, 0, end-begin)
+		for _, e := range entries[begin:end] {
+			e.Owner = e.Owner.ToLower()
+			ops = append(ops, query.UpsertOp{
+				Selector: bson.M{
+					"distributionId": e.DistributionId,
+					"owner":          e.Owner,
+				},
+				Updater: e,
+			})
+		}
+
+		if _, _, err := im.q.BulkUpsert(c, domain.TableRewardLedger, ops); err != nil {
+			c.WithField("err", err).Error("q.BulkUpsert failed")
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *rewardLedgerImpl) RemoveAll(c ctx.Ctx, distributionId string) error {
+	// never wipe the whole table
+	if distributionId == "" {
+		return domain.ErrBadParamInput
+	}
+
+	if _, err := im.q.RemoveAll(c, domain.TableRewardLedger, bson.M{"distributionId": distributionId}); err != nil {
+		c.WithField("err", err).Error("q.RemoveAll failed")
+		return err
+	}
+	return nil
+}
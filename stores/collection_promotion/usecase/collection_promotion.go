@@ -120,6 +120,10 @@ func (im *collPromotionImpl) CalculateListingRewardsFlat(c ctx.Ctx, begin time.T
 			return nil, err
 		}
 		for _, lr := range lrs {
+			// only weighted for listing reward rounds
+			if lr.Count <= 0 && lr.SubCount <= 0 {
+				continue
+			}
 			if accountListings[lr.Owner] == nil {
 				accountListings[lr.Owner] = make(map[domain.Address]int)
 			}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/airdrop"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+)
+
+const defaultSnapshotInterval = time.Hour
+
+type ListingRewardUseCaseCfg struct {
+	CollPromotionUC   collection_promotion.CollPromotionUsecase
+	ListingRecordUC   airdrop.ListingRecordUseCase
+	ListingRecordRepo airdrop.ListingRecordRepo
+	DistributionRepo  collection_promotion.RewardDistributionRepo
+	LedgerRepo        collection_promotion.RewardLedgerRepo
+	// SnapshotInterval is the length of a snapshot slot, defaultSnapshotInterval if zero
+	SnapshotInterval time.Duration
+}
+
+type listingRewardImpl struct {
+	collPromotionUC   collection_promotion.CollPromotionUsecase
+	listingRecordUC   airdrop.ListingRecordUseCase
+	listingRecordRepo airdrop.ListingRecordRepo
+	distributionRepo  collection_promotion.RewardDistributionRepo
+	ledgerRepo        collection_promotion.RewardLedgerRepo
+	snapshotInterval  time.Duration
+	now               func() time.Time
+}
+
+func NewListingReward(cfg *ListingRewardUseCaseCfg) collection_promotion.ListingRewardUseCase {
+	interval := cfg.SnapshotInterval
+	if interval == 0 {
+		interval = defaultSnapshotInterval
+	}
+
+	return &listingRewardImpl{
+		collPromotionUC:   cfg.CollPromotionUC,
+		listingRecordUC:   cfg.ListingRecordUC,
+		listingRecordRepo: cfg.ListingRecordRepo,
+		distributionRepo:  cfg.DistributionRepo,
+		ledgerRepo:        cfg.LedgerRepo,
+		snapshotInterval:  interval,
+		now:               time.Now,
+	}
+}
+
+func (im *listingRewardImpl) Snapshot(c ctx.Ctx, ts time.Time) error {
+	_, collections, err := im.collPromotionUC.GetPromotedCollections(c, &ts)
+	if err == domain.ErrNotFound {
+		// no active promotion
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"ts":  ts,
+			"err": err,
+		}).Error("collPromotionUC.GetPromotedCollections failed")
+		return err
+	}
+
+	// a failed collection doesn't block the others, the first error is returned
+	var firstErr error
+	for _, collection := range collections {
+		if err := im.listingRecordUC.SnapshotCollectionListings(c, collection.ChainId, collection.Address, ts); err != nil {
+			c.WithFields(log.Fields{
+				"collection": collection,
+				"ts":         ts,
+				"err":        err,
+			}).Error("listingRecordUC.SnapshotCollectionListings failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (im *listingRewardImpl) Distribute(c ctx.Ctx, begin time.Time, end time.Time) (*collection_promotion.RewardDistribution, error) {
+	if !begin.Before(end) {
+		return nil, domain.ErrBadParamInput
+	}
+	if end.After(im.now()) {
+		return nil, collection_promotion.ErrDistributionNotClosed
+	}
+
+	promo, collections, err := im.collPromotionUC.GetPromotedCollections(c, &begin)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"begin": begin,
+			"err":   err,
+		}).Error("collPromotionUC.GetPromotedCollections failed")
+		return nil, err
+	}
+
+	totalReward, ok := new(big.Int).SetString(promo.RewardPerDistribution, 10)
+	if !ok {
+		return nil, domain.ErrInvalidNumberFormat
+	}
+
+	// weights of owners by snapshot time
+	snapshots := make(map[int64]map[domain.Address]decimal.Decimal)
+	listings := make(map[domain.Address]map[domain.Address]int)
+	for _, collection := range collections {
+		// unset multiplier counts as 1
+		multiplier := decimal.NewFromInt(int64(collection.Multiplier))
+		if collection.Multiplier <= 0 {
+			multiplier = decimal.NewFromInt(1)
+		}
+
+		lrs, err := im.listingRecordRepo.FindAll(c,
+			airdrop.ListingRecordWithChainId(collection.ChainId),
+			airdrop.ListingRecordWithContractAddress(collection.Address),
+			airdrop.ListingRecordWithSnapshotTime(begin, end),
+		)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"collection": collection,
+				"begin":      begin,
+				"end":        end,
+				"err":        err,
+			}).Error("listingRecordRepo.FindAll failed")
+			return nil, err
+		}
+
+		for _, lr := range lrs {
+			if lr.SnapshotTime == nil {
+				continue
+			}
+			count := lr.WeightedCount
+			weight := decimal.NewFromInt(int64(count))
+			if lr.Weight != nil {
+				weight = decimal.NewFromFloat(*lr.Weight)
+			} else {
+				// snapshotted before listings were weighted
+				count = lr.Count
+				weight = decimal.NewFromInt(int64(count))
+			}
+			if count <= 0 {
+				continue
+			}
+
+			owner := lr.Owner.ToLower()
+			ts := lr.SnapshotTime.Unix()
+			if snapshots[ts] == nil {
+				snapshots[ts] = make(map[domain.Address]decimal.Decimal)
+			}
+			snapshots[ts][owner] = snapshots[ts][owner].Add(weight.Mul(multiplier))
+			if listings[owner] == nil {
+				listings[owner] = make(map[domain.Address]int)
+			}
+			listings[owner][collection.Address.ToLower()] += count
+		}
+	}
+
+	slots := int(end.Sub(begin) / im.snapshotInterval)
+	if slots < 1 {
+		slots = 1
+	}
+	// extra snapshots, e.g. triggered manually, share the reward instead of adding to it
+	shares := slots
+	if len(snapshots) > shares {
+		shares = len(snapshots)
+	}
+	rewardPerSnapshot := decimal.NewFromBigInt(new(big.Int).Div(totalReward, big.NewInt(int64(shares))), 0)
+
+	rewards := make(map[domain.Address]*big.Int)
+	weights := make(map[domain.Address]decimal.Decimal)
+	snapshotTimes := []time.Time{}
+	for ts, ownerWeights := range snapshots {
+		snapshotTimes = append(snapshotTimes, time.Unix(ts, 0).UTC())
+
+		sum := decimal.Zero
+		for _, w := range ownerWeights {
+			sum = sum.Add(w)
+		}
+		if sum.IsZero() {
+			continue
+		}
+
+		for owner, w := range ownerWeights {
+			weights[owner] = weights[owner].Add(w)
+			reward := rewardPerSnapshot.Mul(w).Div(sum).Floor().BigInt()
+			if rewards[owner] == nil {
+				rewards[owner] = new(big.Int)
+			}
+			rewards[owner].Add(rewards[owner], reward)
+		}
+	}
+	sort.Slice(snapshotTimes, func(i, j int) bool {
+		return snapshotTimes[i].Before(snapshotTimes[j])
+	})
+
+	now := im.now()
+	id := collection_promotion.DistributionId(promo.Id, begin)
+	allocated := new(big.Int)
+	entries := make([]collection_promotion.RewardLedgerEntry, 0, len(rewards))
+	for owner, reward := range rewards {
+		allocated.Add(allocated, reward)
+		weight, _ := weights[owner].Float64()
+		entries = append(entries, collection_promotion.RewardLedgerEntry{
+			DistributionId: id,
+			PromotionId:    promo.Id,
+			Owner:          owner,
+			Amount:         reward.String(),
+			Weight:         weight,
+			Listings:       listings[owner],
+			CreatedAt:      now,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Owner < entries[j].Owner
+	})
+
+	distributedCollections := make([]collection_promotion.CollPromotion, len(collections))
+	for i, collection := range collections {
+		distributedCollections[i] = *collection
+	}
+
+	distribution := &collection_promotion.RewardDistribution{
+		Id:              id,
+		PromotionId:     promo.Id,
+		Begin:           begin,
+		End:             end,
+		SnapshotSlots:   slots,
+		SnapshotTimes:   snapshotTimes,
+		TotalReward:     totalReward.String(),
+		AllocatedReward: allocated.String(),
+		OwnerCount:      len(entries),
+		Collections:     distributedCollections,
+		CreatedAt:       now,
+	}
+
+	// the header of a redistributed period is removed before its ledger is rewritten and written back last, so a
+	// distribution with header always has the complete ledger
+	if err := im.distributionRepo.Remove(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("distributionRepo.Remove failed")
+		return nil, err
+	}
+	if err := im.ledgerRepo.RemoveAll(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("ledgerRepo.RemoveAll failed")
+		return nil, err
+	}
+	if len(entries) > 0 {
+		if err := im.ledgerRepo.BulkUpsert(c, entries); err != nil {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("ledgerRepo.BulkUpsert failed")
+			return nil, err
+		}
+	}
+	if err := im.distributionRepo.Upsert(c, distribution); err != nil {
+		c.WithFields(log.Fields{
+			"distribution": distribution,
+			"err":          err,
+		}).Error("distributionRepo.Upsert failed")
+		return nil, err
+	}
+
+	return distribution, nil
+}
+
+func (im *listingRewardImpl) FindDistributions(c ctx.Ctx, optFns ...collection_promotion.RewardDistributionFindAllOptionsFunc) ([]collection_promotion.RewardDistribution, error) {
+	distributions, err := im.distributionRepo.FindAll(c, optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("distributionRepo.FindAll failed")
+		return nil, err
+	}
+	return distributions, nil
+}
+
+func (im *listingRewardImpl) FindLedger(c ctx.Ctx, optFns ...collection_promotion.RewardLedgerFindAllOptionsFunc) ([]collection_promotion.RewardLedgerEntry, error) {
+	entries, err := im.ledgerRepo.FindAll(c, optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("ledgerRepo.FindAll failed")
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/airdrop"
+	mAirdrop "github.com/x-xyz/goapi/domain/airdrop/mocks"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	mCollPromotion "github.com/x-xyz/goapi/domain/collection_promotion/mocks"
+	"github.com/x-xyz/goapi/domain/promotion"
+)
+
+var (
+	promo       = &promotion.Promotion{Id: "promo", RewardPerDistribution: "2400"}
+	collections = []*collection_promotion.CollPromotion{
+		{ChainId: 1, Address: "0xa", Multiplier: 1},
+		{ChainId: 1, Address: "0xb", Multiplier: 2},
+	}
+)
+
+type ListingRewardSuite struct {
+	suite.Suite
+	ctx              ctx.Ctx
+	begin            time.Time
+	records          map[domain.Address][]airdrop.ListingRecord
+	collPromotionUC  *mCollPromotion.CollPromotionUsecase
+	recordRepo       *mAirdrop.ListingRecordRepo
+	distributionRepo *mCollPromotion.RewardDistributionRepo
+	ledgerRepo       *mCollPromotion.RewardLedgerRepo
+	im               *listingRewardImpl
+}
+
+func TestListingRewardSuite(t *testing.T) {
+	suite.Run(t, new(ListingRewardSuite))
+}
+
+func (s *ListingRewardSuite) SetupTest() {
+	s.ctx = ctx.Background()
+	s.begin = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	s.records = map[domain.Address][]airdrop.ListingRecord{}
+	s.collPromotionUC = &mCollPromotion.CollPromotionUsecase{}
+	s.recordRepo = &mAirdrop.ListingRecordRepo{}
+	s.distributionRepo = &mCollPromotion.RewardDistributionRepo{}
+	s.ledgerRepo = &mCollPromotion.RewardLedgerRepo{}
+	s.im = NewListingReward(&ListingRewardUseCaseCfg{
+		CollPromotionUC:   s.collPromotionUC,
+		ListingRecordRepo: s.recordRepo,
+		DistributionRepo:  s.distributionRepo,
+		LedgerRepo:        s.ledgerRepo,
+	}).(*listingRewardImpl)
+	s.im.now = func() time.Time { return s.begin.Add(48 * time.Hour) }
+}
+
+func (s *ListingRewardSuite) TearDownTest() {
+	s.collPromotionUC.AssertExpectations(s.T())
+	s.recordRepo.AssertExpectations(s.T())
+	s.distributionRepo.AssertExpectations(s.T())
+	s.ledgerRepo.AssertExpectations(s.T())
+}
+
+func (s *ListingRewardSuite) record(owner domain.Address, address domain.Address, hour int, count int, weight float64) {
+	ts := s.begin.Add(time.Duration(hour)*time.Hour + 17*time.Minute)
+	s.records[address] = append(s.records[address], airdrop.ListingRecord{
+		Owner:           owner,
+		ChainId:         1,
+		ContractAddress: address,
+		Count:           count,
+		Weight:          &weight,
+		WeightedCount:   count,
+		SnapshotTime:    &ts,
+	})
+}
+
+func (s *ListingRewardSuite) legacyRecord(owner domain.Address, address domain.Address, hour int, count int) {
+	ts := s.begin.Add(time.Duration(hour)*time.Hour + 17*time.Minute)
+	s.records[address] = append(s.records[address], airdrop.ListingRecord{
+		Owner:           owner,
+		ChainId:         1,
+		ContractAddress: address,
+		Count:           count,
+		SnapshotTime:    &ts,
+	})
+}
+
+// mockRecords mocks the records of the period found collection by collection
+func (s *ListingRewardSuite) mockRecords() {
+	for _, collection := range collections {
+		s.recordRepo.On("FindAll", mock.Anything,
+			mock.AnythingOfType("airdrop.ListingRecordFindAllOptionsFunc"),
+			mock.AnythingOfType("airdrop.ListingRecordFindAllOptionsFunc"),
+			mock.AnythingOfType("airdrop.ListingRecordFindAllOptionsFunc")).
+			Return(s.records[collection.Address], nil).Once()
+	}
+}
+
+func (s *ListingRewardSuite) TestDistribute() {
+	// 24 slots of 100
+	s.record("0x1", "0xa", 0, 2, 1.5)
+	s.record("0x2", "0xb", 0, 1, 0.75)
+	s.record("0x1", "0xa", 1, 1, 0.5)
+	// snapshotted before listings were weighted
+	s.legacyRecord("0x2", "0xa", 1, 1)
+
+	id := collection_promotion.DistributionId("promo", s.begin)
+	entries := []collection_promotion.RewardLedgerEntry{}
+	s.collPromotionUC.On("GetPromotedCollections", mock.Anything, &s.begin).Return(promo, collections, nil).Once()
+	s.mockRecords()
+	// the header is removed before the ledger is rewritten, and written back last
+	calls := []string{}
+	s.distributionRepo.On("Remove", mock.Anything, id).
+		Run(func(args mock.Arguments) { calls = append(calls, "Remove") }).
+		Return(nil).Once()
+	s.ledgerRepo.On("RemoveAll", mock.Anything, id).
+		Run(func(args mock.Arguments) { calls = append(calls, "RemoveAll") }).
+		Return(nil).Once()
+	s.ledgerRepo.On("BulkUpsert", mock.Anything, mock.AnythingOfType("[]collection_promotion.RewardLedgerEntry")).
+		Run(func(args mock.Arguments) {
+			calls = append(calls, "BulkUpsert")
+			entries = args.Get(1).([]collection_promotion.RewardLedgerEntry)
+		}).
+		Return(nil).Once()
+	s.distributionRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*collection_promotion.RewardDistribution")).
+		Run(func(args mock.Arguments) { calls = append(calls, "Upsert") }).
+		Return(nil).Once()
+
+	d, err := s.im.Distribute(s.ctx, s.begin, s.begin.Add(24*time.Hour))
+	s.NoError(err)
+	s.Equal([]string{"Remove", "RemoveAll", "BulkUpsert", "Upsert"}, calls)
+	s.Equal(id, d.Id)
+	s.Equal(24, d.SnapshotSlots)
+	s.Len(d.SnapshotTimes, 2)
+	s.Equal("2400", d.TotalReward)
+	s.Equal(2, d.OwnerCount)
+
+	// hour 0: 0x1 1.5, 0x2 0.75*2 -> 50/50
+	// hour 1: 0x1 0.5, 0x2 1 -> 33/66
+	s.Equal([]collection_promotion.RewardLedgerEntry{
+		{
+			DistributionId: d.Id,
+			PromotionId:    "promo",
+			Owner:          "0x1",
+			Amount:         "83",
+			Weight:         2,
+			Listings:       map[domain.Address]int{"0xa": 3},
+			CreatedAt:      d.CreatedAt,
+		},
+		{
+			DistributionId: d.Id,
+			PromotionId:    "promo",
+			Owner:          "0x2",
+			Amount:         "116",
+			Weight:         2.5,
+			Listings:       map[domain.Address]int{"0xa": 1, "0xb": 1},
+			CreatedAt:      d.CreatedAt,
+		},
+	}, entries)
+	s.Equal("199", d.AllocatedReward)
+}
+
+func (s *ListingRewardSuite) TestDistributeErrors() {
+	_, err := s.im.Distribute(s.ctx, s.begin, s.begin)
+	s.ErrorIs(err, domain.ErrBadParamInput)
+
+	_, err = s.im.Distribute(s.ctx, s.begin.Add(48*time.Hour), s.begin.Add(72*time.Hour))
+	s.ErrorIs(err, collection_promotion.ErrDistributionNotClosed)
+
+	s.collPromotionUC.On("GetPromotedCollections", mock.Anything, &s.begin).Return(nil, nil, domain.ErrNotFound).Once()
+	_, err = s.im.Distribute(s.ctx, s.begin, s.begin.Add(24*time.Hour))
+	s.ErrorIs(err, domain.ErrNotFound)
+
+	// the ledger is untouched if the header can't be removed
+	s.collPromotionUC.On("GetPromotedCollections", mock.Anything, &s.begin).Return(promo, collections, nil).Once()
+	s.mockRecords()
+	s.distributionRepo.On("Remove", mock.Anything, collection_promotion.DistributionId("promo", s.begin)).
+		Return(domain.ErrInternalServerError).Once()
+	_, err = s.im.Distribute(s.ctx, s.begin, s.begin.Add(24*time.Hour))
+	s.ErrorIs(err, domain.ErrInternalServerError)
+}
+
+func (s *ListingRewardSuite) TestNextSnapshotTime() {
+	now := s.begin.Add(90 * time.Minute)
+
+	fixed := NewRewardScheduler(&RewardSchedulerCfg{Mode: collection_promotion.SnapshotModeFixed})
+	s.Equal(s.begin.Add(2*time.Hour), fixed.nextSnapshotTime(now))
+	// exactly at the beginning of a slot
+	s.Equal(s.begin.Add(time.Hour), fixed.nextSnapshotTime(s.begin.Add(time.Hour)))
+
+	random := NewRewardScheduler(&RewardSchedulerCfg{})
+	for i := 0; i < 100; i++ {
+		at := random.nextSnapshotTime(now)
+		s.False(at.Before(now))
+		s.True(at.Before(s.begin.Add(3 * time.Hour)))
+	}
+}
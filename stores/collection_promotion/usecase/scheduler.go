@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"math/rand"
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+)
+
+const defaultDistributionPeriod = 24 * time.Hour
+
+type RewardSchedulerCfg struct {
+	ListingReward collection_promotion.ListingRewardUseCase
+	// Mode is SnapshotModeRandom if not set
+	Mode collection_promotion.SnapshotMode
+	// Interval is the length of a snapshot slot, defaultSnapshotInterval if zero. It should match SnapshotInterval of
+	// the ListingRewardUseCase.
+	Interval time.Duration
+	// Period is the length of a distribution, defaultDistributionPeriod if zero. It should be a multiple of Interval.
+	Period time.Duration
+}
+
+// RewardScheduler snapshots listings of promoted collections once in every interval, and distributes rewards of a
+// period once it's closed
+type RewardScheduler struct {
+	listingReward collection_promotion.ListingRewardUseCase
+	mode          collection_promotion.SnapshotMode
+	interval      time.Duration
+	period        time.Duration
+	stoppedCh     chan interface{}
+	now           func() time.Time
+	after         func(time.Duration) <-chan time.Time
+	// snapshotted is the beginning of the last snapshotted slot, only accessed by loop
+	snapshotted time.Time
+}
+
+func NewRewardScheduler(cfg *RewardSchedulerCfg) *RewardScheduler {
+	mode := cfg.Mode
+	if !mode.IsValid() {
+		mode = collection_promotion.SnapshotModeRandom
+	}
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultSnapshotInterval
+	}
+	period := cfg.Period
+	if period == 0 {
+		period = defaultDistributionPeriod
+	}
+
+	return &RewardScheduler{
+		listingReward: cfg.ListingReward,
+		mode:          mode,
+		interval:      interval,
+		period:        period,
+		stoppedCh:     make(chan interface{}),
+		now:           time.Now,
+		after:         time.After,
+	}
+}
+
+func (s *RewardScheduler) Start(ctx bCtx.Ctx) {
+	go s.loop(ctx)
+}
+
+func (s *RewardScheduler) Wait() {
+	<-s.stoppedCh
+}
+
+// nextSnapshotTime returns the snapshot time of the first slot after the last snapshotted one which isn't passed yet,
+// so every slot is snapshotted at most once
+func (s *RewardScheduler) nextSnapshotTime(now time.Time) time.Time {
+	slot := now.Truncate(s.interval)
+	if !s.snapshotted.Before(slot) {
+		slot = s.snapshotted.Add(s.interval)
+	}
+	for {
+		at := slot
+		if s.mode == collection_promotion.SnapshotModeRandom {
+			at = slot.Add(time.Duration(rand.Int63n(int64(s.interval))))
+		}
+		if !at.Before(now) {
+			return at
+		}
+		slot = slot.Add(s.interval)
+	}
+}
+
+func (s *RewardScheduler) loop(ctx bCtx.Ctx) {
+	// distributing a period again rewrites the same ledger, so it's fine to distribute the last period after restart
+	var distributed time.Time
+
+	for {
+		now := s.now()
+		at := s.nextSnapshotTime(now)
+		select {
+		case <-ctx.Done():
+			close(s.stoppedCh)
+			return
+		case <-s.after(at.Sub(now)):
+			s.snapshotted = at.Truncate(s.interval)
+			if err := s.listingReward.Snapshot(ctx, at); err != nil {
+				ctx.WithFields(log.Fields{
+					"at":  at,
+					"err": err,
+				}).Error("listingReward.Snapshot failed")
+			}
+
+			end := s.now().Truncate(s.period)
+			if !end.After(distributed) {
+				continue
+			}
+			begin := end.Add(-s.period)
+			if _, err := s.listingReward.Distribute(ctx, begin, end); err != nil && err != domain.ErrNotFound {
+				// retry after next snapshot
+				ctx.WithFields(log.Fields{
+					"begin": begin,
+					"end":   end,
+					"err":   err,
+				}).Error("listingReward.Distribute failed")
+				continue
+			}
+			distributed = end
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain/collection_promotion"
+	mCollPromotion "github.com/x-xyz/goapi/domain/collection_promotion/mocks"
+)
+
+type RewardSchedulerSuite struct {
+	suite.Suite
+	clock         time.Time
+	listingReward *mCollPromotion.ListingRewardUseCase
+	snapshots     []time.Time
+	distributions []time.Time
+}
+
+func TestRewardSchedulerSuite(t *testing.T) {
+	suite.Run(t, new(RewardSchedulerSuite))
+}
+
+func (s *RewardSchedulerSuite) SetupTest() {
+	s.clock = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	s.listingReward = &mCollPromotion.ListingRewardUseCase{}
+	s.snapshots = nil
+	s.distributions = nil
+}
+
+func (s *RewardSchedulerSuite) TearDownTest() {
+	s.listingReward.AssertExpectations(s.T())
+}
+
+// run runs the scheduler on a fake clock until it snapshotted count times
+func (s *RewardSchedulerSuite) run(mode collection_promotion.SnapshotMode, count int) {
+	c, cancel := ctx.WithCancel(ctx.Background())
+	defer cancel()
+
+	s.listingReward.On("Snapshot", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil).Run(func(args mock.Arguments) {
+		if s.snapshots = append(s.snapshots, args.Get(1).(time.Time)); len(s.snapshots) == count {
+			cancel()
+		}
+	})
+	s.listingReward.On("Distribute", mock.Anything, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(nil, nil).Maybe().Run(func(args mock.Arguments) {
+		s.distributions = append(s.distributions, args.Get(2).(time.Time))
+	})
+
+	scheduler := NewRewardScheduler(&RewardSchedulerCfg{ListingReward: s.listingReward, Mode: mode})
+	scheduler.now = func() time.Time { return s.clock }
+	scheduler.after = func(d time.Duration) <-chan time.Time {
+		if len(s.snapshots) >= count {
+			// never fires, so the loop stops
+			return nil
+		}
+		s.clock = s.clock.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- s.clock
+		return ch
+	}
+	scheduler.loop(c)
+	scheduler.Wait()
+}
+
+// assertOnePerSlot asserts snapshots are in consecutive slots from the first one
+func (s *RewardSchedulerSuite) assertOnePerSlot(count int, first time.Time) {
+	s.Len(s.snapshots, count)
+	for i, at := range s.snapshots {
+		s.Equal(first.Add(time.Duration(i)*time.Hour), at.Truncate(time.Hour), "snapshot %d at %s", i, at)
+	}
+}
+
+func (s *RewardSchedulerSuite) TestRandom() {
+	start := s.clock
+	s.run(collection_promotion.SnapshotModeRandom, 48)
+	s.assertOnePerSlot(48, start)
+	// the period closed before start is distributed too
+	s.Equal([]time.Time{start, start.Add(24 * time.Hour)}, s.distributions)
+}
+
+func (s *RewardSchedulerSuite) TestFixed() {
+	// the passed beginning of the current slot is skipped
+	s.clock = s.clock.Add(30 * time.Minute)
+	s.run(collection_promotion.SnapshotModeFixed, 24)
+	s.assertOnePerSlot(24, time.Date(2022, 10, 1, 1, 0, 0, 0, time.UTC))
+	for _, at := range s.snapshots {
+		s.Equal(at.Truncate(time.Hour), at)
+	}
+}
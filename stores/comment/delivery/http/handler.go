@@ -0,0 +1,376 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/comment"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+type handler struct {
+	comment comment.UseCase
+}
+
+// New registers comment routes on token threads and collection threads
+func New(e *echo.Echo, comment comment.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{comment}
+
+	for _, g := range []*echo.Group{
+		e.Group("/token/:chainId/:contract/:tokenId/comments"),
+		e.Group("/collection/:chainId/:contract/comments"),
+	} {
+		g.GET("", h.list, authMiddleware.OptionalAuth())
+
+		g.POST("", h.post, authMiddleware.Auth())
+
+		g.PUT("/:commentId", h.edit, authMiddleware.Auth())
+
+		g.DELETE("/:commentId", h.delete, authMiddleware.Auth())
+
+		g.POST("/:commentId/remove", h.remove, authMiddleware.Auth(), authMiddleware.IsModerator())
+
+		g.POST("/:commentId/reactions/:reaction", h.react, authMiddleware.Auth())
+
+		g.DELETE("/:commentId/reactions/:reaction", h.unreact, authMiddleware.Auth())
+	}
+}
+
+// bindThread binds the thread from path params only, so that the body can't point to another thread
+func bindThread(c echo.Context) (comment.Thread, error) {
+	thread := comment.Thread{}
+	err := (&echo.DefaultBinder{}).BindPathParams(c, &thread)
+	return thread, err
+}
+
+func errStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput),
+		errors.Is(err, comment.ErrInvalidBody),
+		errors.Is(err, comment.ErrInvalidParent),
+		errors.Is(err, comment.ErrInvalidReaction):
+		return http.StatusBadRequest
+	case errors.Is(err, comment.ErrNotAuthor),
+		errors.Is(err, comment.ErrBanned),
+		errors.Is(err, comment.ErrEditWindowPassed),
+		errors.Is(err, comment.ErrDeleteWindowPassed):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, comment.ErrReactionExists):
+		return http.StatusConflict
+	case errors.Is(err, comment.ErrRateLimited):
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+// list godoc
+//
+//	@Summary		List comments
+//	@Description	List top level comments of the thread newest first, or replies of parentId oldest first.
+//	@Description	Deleted and removed comments are listed with empty body only if they have replies.
+//	@Description	Holds is the number of tokens of the collection the author holds.
+//	@Tags			comments
+//	@Produce		json
+//	@Param			chainId		path		int		true	"chain id"
+//	@Param			contract	path		string	true	"contract address"
+//	@Param			tokenId		path		string	false	"token id, omitted on collection threads"
+//	@Param			parentId	query		string	false	"comment id to list replies of"
+//	@Param			offset		query		int		false	"offset"
+//	@Param			limit		query		int		false	"limit, 20 by default and at most 100"
+//	@Success		200			{object}	comment.SearchResult
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments [get]
+//	@Router			/collection/{chainId}/{contract}/comments [get]
+func (h *handler) list(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		ParentId string `query:"parentId"`
+		Offset   int32  `query:"offset"`
+		Limit    int32  `query:"limit"`
+	}
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	var viewer *domain.Address
+	if address, ok := c.Get("address").(domain.Address); ok {
+		viewer = &address
+	}
+
+	res, err := h.comment.Find(ctx, thread, p.ParentId, viewer, p.Offset, p.Limit)
+	if err != nil {
+		if status := errStatus(err); status != http.StatusInternalServerError {
+			return delivery.MakeJsonResp(c, status, err)
+		}
+		ctx.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("comment.Find failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// post godoc
+//
+//	@Summary		Post comment
+//	@Description	Post a comment, or a reply if parentId is set. Replies are one level deep.
+//	@Description	Posting is rate limited per account, 429 is returned when exceeded.
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			chainId		path		int					true	"chain id"
+//	@Param			contract	path		string				true	"contract address"
+//	@Param			tokenId		path		string				false	"token id, omitted on collection threads"
+//	@Param			params		body		http.post.params	true	"params"
+//	@Success		201			{object}	comment.CommentWithDetail
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		429
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments [post]
+//	@Router			/collection/{chainId}/{contract}/comments [post]
+func (h *handler) post(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		ParentId string `json:"parentId"`
+		Body     string `json:"body"`
+	}
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.comment.Post(ctx, address, thread, p.ParentId, p.Body)
+	if err != nil {
+		if status := errStatus(err); status != http.StatusInternalServerError {
+			return delivery.MakeJsonResp(c, status, err)
+		}
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"thread":  thread,
+			"address": address,
+		}).Error("comment.Post failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusCreated, res)
+}
+
+// edit godoc
+//
+//	@Summary		Edit comment
+//	@Description	Edit own comment within 15 minutes after posting
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			chainId		path		int					true	"chain id"
+//	@Param			contract	path		string				true	"contract address"
+//	@Param			tokenId		path		string				false	"token id, omitted on collection threads"
+//	@Param			commentId	path		string				true	"comment id"
+//	@Param			params		body		http.edit.params	true	"params"
+//	@Success		200			{object}	comment.Comment
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments/{commentId} [put]
+//	@Router			/collection/{chainId}/{contract}/comments/{commentId} [put]
+func (h *handler) edit(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	type params struct {
+		Body string `json:"body"`
+	}
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.comment.Edit(ctx, address, thread, c.Param("commentId"), p.Body)
+	if err != nil {
+		return delivery.MakeJsonResp(c, errStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// delete godoc
+//
+//	@Summary		Delete comment
+//	@Description	Delete own comment within 24 hours after posting
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			chainId		path	int		true	"chain id"
+//	@Param			contract	path	string	true	"contract address"
+//	@Param			tokenId		path	string	false	"token id, omitted on collection threads"
+//	@Param			commentId	path	string	true	"comment id"
+//	@Success		200
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments/{commentId} [delete]
+//	@Router			/collection/{chainId}/{contract}/comments/{commentId} [delete]
+func (h *handler) delete(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.comment.Delete(ctx, address, thread, c.Param("commentId")); err != nil {
+		return delivery.MakeJsonResp(c, errStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, nil)
+}
+
+// remove godoc
+//
+//	@Summary		Remove comment
+//	@Description	Remove comment by moderator, the removal is recorded in the moderation audit log
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			chainId		path	int					true	"chain id"
+//	@Param			contract	path	string				true	"contract address"
+//	@Param			tokenId		path	string				false	"token id, omitted on collection threads"
+//	@Param			commentId	path	string				true	"comment id"
+//	@Param			params		body	http.remove.params	true	"params"
+//	@Success		200
+//	@Failure		404
+//	@Failure		405
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments/{commentId}/remove [post]
+//	@Router			/collection/{chainId}/{contract}/comments/{commentId}/remove [post]
+func (h *handler) remove(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	moderator := c.Get("address").(domain.Address)
+
+	type params struct {
+		Reason string `json:"reason"`
+	}
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if err := h.comment.Remove(ctx, moderator, thread, c.Param("commentId"), p.Reason); err != nil {
+		return delivery.MakeJsonResp(c, errStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, nil)
+}
+
+// react godoc
+//
+//	@Summary		React to comment
+//	@Description	React to comment with like, love, laugh, fire or rocket
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			chainId		path		int		true	"chain id"
+//	@Param			contract	path		string	true	"contract address"
+//	@Param			tokenId		path		string	false	"token id, omitted on collection threads"
+//	@Param			commentId	path		string	true	"comment id"
+//	@Param			reaction	path		string	true	"reaction"
+//	@Success		200			{object}	comment.Comment
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		409
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments/{commentId}/reactions/{reaction} [post]
+//	@Router			/collection/{chainId}/{contract}/comments/{commentId}/reactions/{reaction} [post]
+func (h *handler) react(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.comment.React(ctx, address, thread, c.Param("commentId"), comment.Reaction(c.Param("reaction")))
+	if err != nil {
+		return delivery.MakeJsonResp(c, errStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// unreact godoc
+//
+//	@Summary		Undo reaction to comment
+//	@Tags			comments
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			chainId		path		int		true	"chain id"
+//	@Param			contract	path		string	true	"contract address"
+//	@Param			tokenId		path		string	false	"token id, omitted on collection threads"
+//	@Param			commentId	path		string	true	"comment id"
+//	@Param			reaction	path		string	true	"reaction"
+//	@Success		200			{object}	comment.Comment
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/token/{chainId}/{contract}/{tokenId}/comments/{commentId}/reactions/{reaction} [delete]
+//	@Router			/collection/{chainId}/{contract}/comments/{commentId}/reactions/{reaction} [delete]
+func (h *handler) unreact(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	thread, err := bindThread(c)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.comment.Unreact(ctx, address, thread, c.Param("commentId"), comment.Reaction(c.Param("reaction")))
+	if err != nil {
+		return delivery.MakeJsonResp(c, errStatus(err), err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
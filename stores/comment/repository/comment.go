@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/comment"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type commentImpl struct {
+	query query.Mongo
+}
+
+func NewCommentRepo(query query.Mongo) comment.Repo {
+	return &commentImpl{query}
+}
+
+func (im *commentImpl) Insert(ctx ctx.Ctx, value *comment.Comment) error {
+	value.Thread = value.Thread.Normalize()
+	value.Author = value.Author.ToLower()
+
+	err := im.query.Insert(ctx, domain.TableComments, value)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  value.Id,
+		}).Error("failed to query.Insert")
+		return err
+	}
+	return nil
+}
+
+func (im *commentImpl) FindOne(ctx ctx.Ctx, id string) (*comment.Comment, error) {
+	res := comment.Comment{}
+	err := im.query.FindOne(ctx, domain.TableComments, bson.M{"id": id}, &res)
+	if errors.Is(err, query.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.FindOne")
+		return nil, err
+	}
+	return &res, nil
+}
+
+func makeQuery(opts comment.FindAllOptions) bson.M {
+	query := bson.M{}
+
+	if opts.Thread != nil {
+		query["thread.chainId"] = opts.Thread.ChainId
+		query["thread.contractAddress"] = opts.Thread.ContractAddress
+		query["thread.tokenId"] = opts.Thread.TokenId
+	}
+
+	if opts.ParentId != nil {
+		query["parentId"] = *opts.ParentId
+	}
+
+	if opts.IsListed != nil && *opts.IsListed {
+		query["$or"] = []bson.M{
+			{"isDeleted": false, "isRemoved": false},
+			{"replyCount": bson.M{"$gt": 0}},
+		}
+	}
+
+	return query
+}
+
+func (im *commentImpl) FindAll(ctx ctx.Ctx, options ...comment.FindAllOptionsFunc) ([]*comment.Comment, error) {
+	opts, err := comment.GetFindAllOptions(options...)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+		}).Error("failed to comment.GetFindAllOptions")
+		return nil, err
+	}
+
+	offset, limit := 0, 0
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+
+	query := makeQuery(opts)
+
+	// replies read top down, threads newest first
+	sort := "-createdAt"
+	if opts.ParentId != nil && *opts.ParentId != "" {
+		sort = "createdAt"
+	}
+
+	res := []*comment.Comment{}
+	err = im.query.Search(ctx, domain.TableComments, offset, limit, sort, query, &res)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"query": query,
+		}).Error("failed to query.Search")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *commentImpl) Count(ctx ctx.Ctx, options ...comment.FindAllOptionsFunc) (int, error) {
+	opts, err := comment.GetFindAllOptions(options...)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+		}).Error("failed to comment.GetFindAllOptions")
+		return 0, err
+	}
+
+	query := makeQuery(opts)
+	count, err := im.query.Count(ctx, domain.TableComments, query)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"query": query,
+		}).Error("failed to query.Count")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (im *commentImpl) Update(ctx ctx.Ctx, id string, updater *comment.Updater) error {
+	updateBson, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"updater": *updater,
+		}).Error("failed to mongoclient.MakeBsonM")
+		return err
+	}
+
+	err = im.query.Patch(ctx, domain.TableComments, bson.M{"id": id}, updateBson)
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.Patch")
+		return err
+	}
+	return nil
+}
+
+func (im *commentImpl) IncreaseReplyCount(ctx ctx.Ctx, id string, count int) error {
+	res := comment.Comment{}
+	if err := im.query.Increment(ctx, domain.TableComments, bson.M{"id": id}, &res, "replyCount", count); err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to query.Increment")
+		return err
+	}
+	return nil
+}
+
+func (im *commentImpl) IncreaseReactionCount(ctx ctx.Ctx, id string, reaction comment.Reaction, count int) (*comment.Comment, error) {
+	res := comment.Comment{}
+	if err := im.query.Increment(ctx, domain.TableComments, bson.M{"id": id}, &res, "reactions."+string(reaction), count); err != nil {
+		ctx.WithFields(log.Fields{
+			"err":      err,
+			"id":       id,
+			"reaction": reaction,
+		}).Error("failed to query.Increment")
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/comment"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reactionImpl struct {
+	query query.Mongo
+}
+
+// EnsureReactionIndex creates the unique index of (commentId, account, reaction) which reaction repo relies on to dedup reactions
+func EnsureReactionIndex(ctx ctx.Ctx, q query.Mongo) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "commentId", Value: 1}, {Key: "account", Value: 1}, {Key: "reaction", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	return q.CreateIndex(ctx, domain.TableCommentReactions, index)
+}
+
+// NewReactionRepo requires the index created by EnsureReactionIndex to dedup reactions
+func NewReactionRepo(query query.Mongo) comment.ReactionRepo {
+	return &reactionImpl{query}
+}
+
+func (im *reactionImpl) Insert(ctx ctx.Ctx, record *comment.ReactionRecord) error {
+	record.Account = record.Account.ToLower()
+
+	err := im.query.Insert(ctx, domain.TableCommentReactions, record)
+	if errors.Is(err, query.ErrDuplicateKey) {
+		return comment.ErrReactionExists
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":    err,
+			"record": record,
+		}).Error("failed to query.Insert")
+		return err
+	}
+	return nil
+}
+
+func (im *reactionImpl) Delete(ctx ctx.Ctx, commentId string, account domain.Address, reaction comment.Reaction) error {
+	selector := bson.M{"commentId": commentId, "account": account.ToLower(), "reaction": reaction}
+	err := im.query.Remove(ctx, domain.TableCommentReactions, selector)
+	if errors.Is(err, query.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":      err,
+			"selector": selector,
+		}).Error("failed to query.Remove")
+		return err
+	}
+	return nil
+}
+
+func (im *reactionImpl) FindAll(ctx ctx.Ctx, account domain.Address, commentIds []string) ([]*comment.ReactionRecord, error) {
+	query := bson.M{"account": account.ToLower(), "commentId": bson.M{"$in": commentIds}}
+
+	res := []*comment.ReactionRecord{}
+	err := im.query.Search(ctx, domain.TableCommentReactions, 0, 0, "createdAt", query, &res)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":   err,
+			"query": query,
+		}).Error("failed to query.Search")
+		return nil, err
+	}
+	return res, nil
+}
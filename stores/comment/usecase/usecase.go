@@ -0,0 +1,552 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/comment"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	"github.com/x-xyz/goapi/domain/moderation"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/service/ratelimit"
+)
+
+const (
+	defaultPostRateLimit  = 5
+	defaultPostRateWindow = time.Minute
+	defaultLimit          = 20
+	maxLimit              = 100
+)
+
+type CommentUseCaseCfg struct {
+	Repo               comment.Repo
+	ReactionRepo       comment.ReactionRepo
+	AuditLogRepo       moderation.AuditLogRepo
+	AccountRepo        account.Repo
+	CollectionRepo     collection.Repo
+	NftitemRepo        nftitem.Repo
+	Erc1155HoldingRepo erc1155.HoldingRepo
+	// Limiter is optional, posting isn't rate limited without it
+	Limiter ratelimit.Limiter
+	// PostRateLimit comments can be posted by an account in PostRateWindow, 5 a minute if not set
+	PostRateLimit  int
+	PostRateWindow time.Duration
+}
+
+type impl struct {
+	repo           comment.Repo
+	reactionRepo   comment.ReactionRepo
+	auditLogRepo   moderation.AuditLogRepo
+	accountRepo    account.Repo
+	collectionRepo collection.Repo
+	nftitemRepo    nftitem.Repo
+	holdingRepo    erc1155.HoldingRepo
+	limiter        ratelimit.Limiter
+	postRateLimit  int
+	postRateWindow time.Duration
+	now            func() time.Time
+}
+
+func New(cfg *CommentUseCaseCfg) comment.UseCase {
+	im := &impl{
+		repo:           cfg.Repo,
+		reactionRepo:   cfg.ReactionRepo,
+		auditLogRepo:   cfg.AuditLogRepo,
+		accountRepo:    cfg.AccountRepo,
+		collectionRepo: cfg.CollectionRepo,
+		nftitemRepo:    cfg.NftitemRepo,
+		holdingRepo:    cfg.Erc1155HoldingRepo,
+		limiter:        cfg.Limiter,
+		postRateLimit:  cfg.PostRateLimit,
+		postRateWindow: cfg.PostRateWindow,
+		now:            time.Now,
+	}
+	if im.postRateLimit <= 0 {
+		im.postRateLimit = defaultPostRateLimit
+	}
+	if im.postRateWindow <= 0 {
+		im.postRateWindow = defaultPostRateWindow
+	}
+	return im
+}
+
+func (im *impl) Find(c ctx.Ctx, thread comment.Thread, parentId string, viewer *domain.Address, offset, limit int32) (*comment.SearchResult, error) {
+	thread = thread.Normalize()
+	if offset < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	} else if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if parentId != "" {
+		if _, err := im.findParent(c, thread, parentId); err != nil {
+			return nil, err
+		}
+	}
+
+	optFns := []comment.FindAllOptionsFunc{
+		comment.WithThread(thread),
+		comment.WithParentId(parentId),
+		comment.WithIsListed(true),
+	}
+	count, err := im.repo.Count(c, optFns...)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("repo.Count failed")
+		return nil, err
+	}
+	comments, err := im.repo.FindAll(c, append(optFns, comment.WithPagination(offset, limit))...)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("repo.FindAll failed")
+		return nil, err
+	}
+
+	items, err := im.toDetails(c, thread, comments, viewer)
+	if err != nil {
+		return nil, err
+	}
+	return &comment.SearchResult{Items: items, Count: count}, nil
+}
+
+func (im *impl) Post(c ctx.Ctx, author domain.Address, thread comment.Thread, parentId, body string) (*comment.CommentWithDetail, error) {
+	thread = thread.Normalize()
+	author = author.ToLower()
+	body, err := validateBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := im.checkThread(c, thread); err != nil {
+		return nil, err
+	}
+	var parent *comment.Comment
+	if parentId != "" {
+		if parent, err = im.findParent(c, thread, parentId); errors.Is(err, domain.ErrNotFound) {
+			return nil, comment.ErrInvalidParent
+		} else if err != nil {
+			return nil, err
+		} else if !parent.IsVisible() {
+			return nil, comment.ErrInvalidParent
+		}
+	}
+
+	if err := im.checkBanned(c, author); err != nil {
+		return nil, err
+	}
+	if err := im.checkRateLimit(c, author); err != nil {
+		return nil, err
+	}
+
+	res := &comment.Comment{
+		Id:        uuid.NewString(),
+		Thread:    thread,
+		ParentId:  parentId,
+		Author:    author,
+		Body:      body,
+		Reactions: map[comment.Reaction]int64{},
+		CreatedAt: im.now(),
+	}
+	if err := im.repo.Insert(c, res); err != nil {
+		c.WithField("err", err).Error("repo.Insert failed")
+		return nil, err
+	}
+	// the reply is posted already, a missed reply count isn't worth failing the request
+	if parent != nil {
+		if err := im.repo.IncreaseReplyCount(c, parent.Id, 1); err != nil {
+			c.WithFields(log.Fields{
+				"err": err,
+				"id":  parent.Id,
+			}).Warn("repo.IncreaseReplyCount failed")
+		}
+	}
+
+	items, err := im.toDetails(c, thread, []*comment.Comment{res}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+func (im *impl) Edit(c ctx.Ctx, author domain.Address, thread comment.Thread, id, body string) (*comment.Comment, error) {
+	body, err := validateBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	cmt, err := im.findOwned(c, author, thread, id)
+	if err != nil {
+		return nil, err
+	}
+	if im.now().Sub(cmt.CreatedAt) > comment.EditWindow {
+		return nil, comment.ErrEditWindowPassed
+	}
+
+	now := im.now()
+	if err := im.repo.Update(c, id, &comment.Updater{Body: &body, EditedAt: &now}); err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("repo.Update failed")
+		return nil, err
+	}
+
+	cmt.Body = body
+	cmt.EditedAt = &now
+	return cmt, nil
+}
+
+func (im *impl) Delete(c ctx.Ctx, author domain.Address, thread comment.Thread, id string) error {
+	cmt, err := im.findOwned(c, author, thread, id)
+	if err != nil {
+		return err
+	}
+	if im.now().Sub(cmt.CreatedAt) > comment.DeleteWindow {
+		return comment.ErrDeleteWindowPassed
+	}
+
+	isDeleted, body := true, ""
+	return im.hide(c, cmt, &comment.Updater{Body: &body, IsDeleted: &isDeleted})
+}
+
+func (im *impl) Remove(c ctx.Ctx, moderator domain.Address, thread comment.Thread, id, reason string) error {
+	cmt, err := im.findInThread(c, thread, id)
+	if err != nil {
+		return err
+	}
+	if cmt.IsRemoved {
+		return nil
+	}
+
+	isRemoved, body := true, ""
+	if err := im.hide(c, cmt, &comment.Updater{Body: &body, IsRemoved: &isRemoved}); err != nil {
+		return err
+	}
+
+	auditLog := &moderation.AuditLog{
+		Id:     uuid.NewString(),
+		Action: moderation.AuditActionRemoveComment,
+		Actor:  moderator.ToLower(),
+		Target: moderation.Target{
+			Type:            moderation.TargetTypeComment,
+			ChainId:         cmt.Thread.ChainId,
+			ContractAddress: cmt.Thread.ContractAddress,
+			TokenId:         cmt.Thread.TokenId,
+			CommentId:       cmt.Id,
+		},
+		Reason:    reason,
+		CreatedAt: im.now(),
+	}
+	if err := im.auditLogRepo.Insert(c, auditLog); err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"auditLog": auditLog,
+		}).Error("auditLogRepo.Insert failed")
+		return err
+	}
+	return nil
+}
+
+// hide deletes or removes the comment, a reply hidden for the first time no longer counts to its parent
+func (im *impl) hide(c ctx.Ctx, cmt *comment.Comment, updater *comment.Updater) error {
+	if err := im.repo.Update(c, cmt.Id, updater); err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  cmt.Id,
+		}).Error("repo.Update failed")
+		return err
+	}
+	// the comment is hidden already, a missed reply count isn't worth failing the request
+	if cmt.ParentId != "" && cmt.IsVisible() {
+		if err := im.repo.IncreaseReplyCount(c, cmt.ParentId, -1); err != nil {
+			c.WithFields(log.Fields{
+				"err": err,
+				"id":  cmt.ParentId,
+			}).Warn("repo.IncreaseReplyCount failed")
+		}
+	}
+	return nil
+}
+
+func (im *impl) React(c ctx.Ctx, address domain.Address, thread comment.Thread, id string, reaction comment.Reaction) (*comment.Comment, error) {
+	address = address.ToLower()
+	if !reaction.IsValid() {
+		return nil, comment.ErrInvalidReaction
+	}
+
+	cmt, err := im.findInThread(c, thread, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cmt.IsVisible() {
+		return nil, domain.ErrNotFound
+	}
+	if err := im.checkBanned(c, address); err != nil {
+		return nil, err
+	}
+
+	record := &comment.ReactionRecord{
+		CommentId: id,
+		Account:   address,
+		Reaction:  reaction,
+		CreatedAt: im.now(),
+	}
+	if err := im.reactionRepo.Insert(c, record); err != nil {
+		return nil, err
+	}
+	return im.increaseReactionCount(c, id, reaction, 1)
+}
+
+func (im *impl) Unreact(c ctx.Ctx, address domain.Address, thread comment.Thread, id string, reaction comment.Reaction) (*comment.Comment, error) {
+	if !reaction.IsValid() {
+		return nil, comment.ErrInvalidReaction
+	}
+
+	if _, err := im.findInThread(c, thread, id); err != nil {
+		return nil, err
+	}
+	if err := im.reactionRepo.Delete(c, id, address, reaction); err != nil {
+		return nil, err
+	}
+	return im.increaseReactionCount(c, id, reaction, -1)
+}
+
+func (im *impl) increaseReactionCount(c ctx.Ctx, id string, reaction comment.Reaction, count int) (*comment.Comment, error) {
+	res, err := im.repo.IncreaseReactionCount(c, id, reaction, count)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":      err,
+			"id":       id,
+			"reaction": reaction,
+		}).Error("repo.IncreaseReactionCount failed")
+		return nil, err
+	}
+	return res, nil
+}
+
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > comment.MaxBodyLength {
+		return "", comment.ErrInvalidBody
+	}
+	return body, nil
+}
+
+// findInThread returns domain.ErrNotFound if the comment belongs to another thread
+func (im *impl) findInThread(c ctx.Ctx, thread comment.Thread, id string) (*comment.Comment, error) {
+	cmt, err := im.repo.FindOne(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if cmt.Thread != thread.Normalize() {
+		return nil, domain.ErrNotFound
+	}
+	return cmt, nil
+}
+
+// findParent returns domain.ErrNotFound if the comment isn't a top level comment of the thread
+func (im *impl) findParent(c ctx.Ctx, thread comment.Thread, id string) (*comment.Comment, error) {
+	cmt, err := im.findInThread(c, thread, id)
+	if err != nil {
+		return nil, err
+	}
+	if cmt.ParentId != "" {
+		return nil, domain.ErrNotFound
+	}
+	return cmt, nil
+}
+
+// findOwned returns a visible comment of author
+func (im *impl) findOwned(c ctx.Ctx, author domain.Address, thread comment.Thread, id string) (*comment.Comment, error) {
+	cmt, err := im.findInThread(c, thread, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cmt.IsVisible() {
+		return nil, domain.ErrNotFound
+	}
+	if cmt.Author != author.ToLower() {
+		return nil, comment.ErrNotAuthor
+	}
+	return cmt, nil
+}
+
+func (im *impl) checkThread(c ctx.Ctx, thread comment.Thread) error {
+	if _, err := im.collectionRepo.FindOne(c, thread.ToCollectionId()); errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("collectionRepo.FindOne failed")
+		return err
+	}
+	if thread.TokenId == "" {
+		return nil
+	}
+	if _, err := im.nftitemRepo.FindOne(c, thread.ChainId, thread.ContractAddress, thread.TokenId); errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("nftitemRepo.FindOne failed")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) checkBanned(c ctx.Ctx, address domain.Address) error {
+	acc, err := im.accountRepo.Get(c, address)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("accountRepo.Get failed")
+		return err
+	}
+	if !acc.IsAppropriate {
+		return comment.ErrBanned
+	}
+	return nil
+}
+
+func (im *impl) checkRateLimit(c ctx.Ctx, author domain.Address) error {
+	if im.limiter == nil {
+		return nil
+	}
+	res, err := im.limiter.Allow(c, "comment:"+string(author), im.postRateLimit, im.postRateWindow)
+	if err != nil {
+		return err
+	}
+	if !res.Allowed {
+		return comment.ErrRateLimited
+	}
+	return nil
+}
+
+// toDetails hides bodies of deleted and removed comments, and attaches author accounts, holder badges and reactions of viewer
+func (im *impl) toDetails(c ctx.Ctx, thread comment.Thread, comments []*comment.Comment, viewer *domain.Address) ([]*comment.CommentWithDetail, error) {
+	res := make([]*comment.CommentWithDetail, 0, len(comments))
+	if len(comments) == 0 {
+		return res, nil
+	}
+
+	authors := []domain.Address{}
+	seen := map[domain.Address]bool{}
+	ids := make([]string, 0, len(comments))
+	for _, cmt := range comments {
+		if !cmt.IsVisible() {
+			cmt.Body = ""
+		}
+		if !seen[cmt.Author] {
+			seen[cmt.Author] = true
+			authors = append(authors, cmt.Author)
+		}
+		ids = append(ids, cmt.Id)
+	}
+
+	accounts, err := im.accountRepo.GetAccounts(c, authors)
+	if err != nil {
+		c.WithField("err", err).Error("accountRepo.GetAccounts failed")
+		return nil, err
+	}
+	accountMap := map[domain.Address]*account.SimpleAccount{}
+	for _, acc := range accounts {
+		accountMap[acc.Address.ToLower()] = acc.ToSimpleAccount()
+	}
+
+	tokenType, err := im.tokenType(c, thread)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := im.countHolds(c, thread, tokenType, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	myReactions := map[string][]comment.Reaction{}
+	if viewer != nil {
+		records, err := im.reactionRepo.FindAll(c, *viewer, ids)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err":    err,
+				"viewer": *viewer,
+			}).Error("reactionRepo.FindAll failed")
+			return nil, err
+		}
+		for _, record := range records {
+			myReactions[record.CommentId] = append(myReactions[record.CommentId], record.Reaction)
+		}
+	}
+
+	for _, cmt := range comments {
+		res = append(res, &comment.CommentWithDetail{
+			Comment:       cmt,
+			AuthorAccount: accountMap[cmt.Author],
+			Holds:         holds[cmt.Author],
+			MyReactions:   myReactions[cmt.Id],
+		})
+	}
+	return res, nil
+}
+
+func (im *impl) tokenType(c ctx.Ctx, thread comment.Thread) (domain.TokenType, error) {
+	col, err := im.collectionRepo.FindOne(c, thread.ToCollectionId())
+	if errors.Is(err, domain.ErrNotFound) {
+		// holds are 0 on threads of unknown collections
+		return 0, nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"thread": thread,
+		}).Error("collectionRepo.FindOne failed")
+		return 0, err
+	}
+	return col.TokenType, nil
+}
+
+// countHolds counts tokens of the collection held by each of owners, holder badges are of the collection on token
+// threads too
+func (im *impl) countHolds(c ctx.Ctx, thread comment.Thread, tokenType domain.TokenType, owners []domain.Address) (map[domain.Address]int64, error) {
+	switch tokenType {
+	case domain.TokenType1155:
+		res, err := im.holdingRepo.SumBalanceByOwners(c, thread.ChainId, thread.ContractAddress, owners)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err":    err,
+				"owners": owners,
+			}).Error("holdingRepo.SumBalanceByOwners failed")
+			return nil, err
+		}
+		return res, nil
+	case domain.TokenType721, domain.TokenTypePunk:
+		res, err := im.nftitemRepo.CountByOwners(c, thread.ChainId, thread.ContractAddress, owners)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err":    err,
+				"owners": owners,
+			}).Error("nftitemRepo.CountByOwners failed")
+			return nil, err
+		}
+		return res, nil
+	}
+	return map[domain.Address]int64{}, nil
+}
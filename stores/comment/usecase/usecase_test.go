@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	"github.com/x-xyz/goapi/domain/comment"
+	mComment "github.com/x-xyz/goapi/domain/comment/mocks"
+	"github.com/x-xyz/goapi/domain/moderation"
+	mModeration "github.com/x-xyz/goapi/domain/moderation/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/service/ratelimit"
+	mRatelimit "github.com/x-xyz/goapi/service/ratelimit/mocks"
+)
+
+var (
+	contractAddress = domain.Address("0x1111111111111111111111111111111111111111")
+	alice           = domain.Address("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	bob             = domain.Address("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	thread          = comment.Thread{ChainId: 1, ContractAddress: contractAddress, TokenId: "1"}
+)
+
+type CommentSuite struct {
+	suite.Suite
+	ctx            bCtx.Ctx
+	now            time.Time
+	repo           *mComment.Repo
+	reactionRepo   *mComment.ReactionRepo
+	auditLogRepo   *mModeration.AuditLogRepo
+	accountRepo    *mAccount.Repo
+	collectionRepo *mCollection.Repo
+	nftitemRepo    *mNftitem.Repo
+	limiter        *mRatelimit.Limiter
+	im             *impl
+}
+
+func TestCommentSuite(t *testing.T) {
+	suite.Run(t, new(CommentSuite))
+}
+
+func (s *CommentSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.now = time.Now()
+	s.repo = &mComment.Repo{}
+	s.reactionRepo = &mComment.ReactionRepo{}
+	s.auditLogRepo = &mModeration.AuditLogRepo{}
+	s.accountRepo = &mAccount.Repo{}
+	s.collectionRepo = &mCollection.Repo{}
+	s.nftitemRepo = &mNftitem.Repo{}
+	s.limiter = &mRatelimit.Limiter{}
+	s.im = New(&CommentUseCaseCfg{
+		Repo:           s.repo,
+		ReactionRepo:   s.reactionRepo,
+		AuditLogRepo:   s.auditLogRepo,
+		AccountRepo:    s.accountRepo,
+		CollectionRepo: s.collectionRepo,
+		NftitemRepo:    s.nftitemRepo,
+		Limiter:        s.limiter,
+		PostRateLimit:  3,
+	}).(*impl)
+	s.im.now = func() time.Time { return s.now }
+}
+
+func (s *CommentSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.reactionRepo.AssertExpectations(s.T())
+	s.auditLogRepo.AssertExpectations(s.T())
+	s.accountRepo.AssertExpectations(s.T())
+	s.collectionRepo.AssertExpectations(s.T())
+	s.nftitemRepo.AssertExpectations(s.T())
+	s.limiter.AssertExpectations(s.T())
+}
+
+// mockThread mocks the collection and the token of the thread
+func (s *CommentSuite) mockThread(thread comment.Thread) {
+	s.collectionRepo.On("FindOne", mock.Anything, thread.ToCollectionId()).
+		Return(&collection.Collection{TokenType: domain.TokenType721}, nil).Once()
+	if thread.TokenId != "" {
+		s.nftitemRepo.On("FindOne", mock.Anything, thread.ChainId, thread.ContractAddress, thread.TokenId).
+			Return(&nftitem.NftItem{}, nil).Once()
+	}
+}
+
+func (s *CommentSuite) mockAccount(address domain.Address, isBanned bool) {
+	s.accountRepo.On("Get", mock.Anything, address).
+		Return(&account.Account{Address: address, IsAppropriate: !isBanned}, nil).Once()
+}
+
+func (s *CommentSuite) mockAllow(author domain.Address, allowed bool) {
+	s.limiter.On("Allow", mock.Anything, "comment:"+string(author), 3, defaultPostRateWindow).
+		Return(&ratelimit.Result{Allowed: allowed, Limit: 3}, nil).Once()
+}
+
+// mockFindOne returns a copy of cmt so that updates of the usecase don't leak into the test
+func (s *CommentSuite) mockFindOne(cmt *comment.Comment) {
+	copied := *cmt
+	s.repo.On("FindOne", mock.Anything, cmt.Id).Return(&copied, nil).Once()
+}
+
+// mockInsert returns the inserted comment
+func (s *CommentSuite) mockInsert() *comment.Comment {
+	res := &comment.Comment{}
+	s.repo.On("Insert", mock.Anything, mock.AnythingOfType("*comment.Comment")).
+		Run(func(args mock.Arguments) { *res = *args.Get(1).(*comment.Comment) }).
+		Return(nil).Once()
+	return res
+}
+
+// mockDetails mocks the account and the holder badge of author
+func (s *CommentSuite) mockDetails(author domain.Address, holds int) {
+	s.accountRepo.On("GetAccounts", mock.Anything, []domain.Address{author}).
+		Return([]*account.Account{{Address: author, IsAppropriate: true}}, nil).Once()
+	s.collectionRepo.On("FindOne", mock.Anything, thread.ToCollectionId()).
+		Return(&collection.Collection{TokenType: domain.TokenType721}, nil).Once()
+	s.nftitemRepo.On("CountByOwners", mock.Anything, thread.ChainId, thread.ContractAddress, []domain.Address{author}).
+		Return(map[domain.Address]int64{author: int64(holds)}, nil).Once()
+}
+
+func (s *CommentSuite) newComment(id string, author domain.Address, parentId string) *comment.Comment {
+	return &comment.Comment{
+		Id:        id,
+		Thread:    thread,
+		ParentId:  parentId,
+		Author:    author,
+		Body:      "gm",
+		Reactions: map[comment.Reaction]int64{},
+		CreatedAt: s.now,
+	}
+}
+
+func (s *CommentSuite) TestPost() {
+	_, err := s.im.Post(s.ctx, alice, thread, "", "   ")
+	s.ErrorIs(err, comment.ErrInvalidBody)
+
+	s.mockThread(thread)
+	s.mockAccount(alice, false)
+	s.mockAllow(alice, true)
+	inserted := s.mockInsert()
+	s.mockDetails(alice, 3)
+	top, err := s.im.Post(s.ctx, alice, thread, "", " gm ")
+	s.NoError(err)
+	s.Equal("gm", top.Body)
+	s.Equal("gm", inserted.Body)
+	s.Equal(int64(3), top.Holds)
+
+	s.mockThread(thread)
+	s.mockFindOne(top.Comment)
+	s.mockAccount(bob, false)
+	s.mockAllow(bob, true)
+	inserted = s.mockInsert()
+	s.repo.On("IncreaseReplyCount", mock.Anything, top.Id, 1).Return(nil).Once()
+	s.mockDetails(bob, 0)
+	reply, err := s.im.Post(s.ctx, bob, thread, top.Id, "gm")
+	s.NoError(err)
+	s.Equal(top.Id, inserted.ParentId)
+	s.Equal(int64(0), reply.Holds)
+
+	// the reply is posted even if its parent isn't counted
+	s.mockThread(thread)
+	s.mockFindOne(top.Comment)
+	s.mockAccount(bob, false)
+	s.mockAllow(bob, true)
+	s.mockInsert()
+	s.repo.On("IncreaseReplyCount", mock.Anything, top.Id, 1).Return(errors.New("timeout")).Once()
+	s.mockDetails(bob, 0)
+	_, err = s.im.Post(s.ctx, bob, thread, top.Id, "gm")
+	s.NoError(err)
+}
+
+func (s *CommentSuite) TestFindHoldsOfAuthors() {
+	top := s.newComment("top", alice, "")
+	second := s.newComment("second", bob, "")
+	third := s.newComment("third", alice, "")
+
+	s.repo.On("Count", mock.Anything,
+		mock.AnythingOfType("comment.FindAllOptionsFunc"),
+		mock.AnythingOfType("comment.FindAllOptionsFunc"),
+		mock.AnythingOfType("comment.FindAllOptionsFunc")).
+		Return(3, nil).Once()
+	s.repo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("comment.FindAllOptionsFunc"),
+		mock.AnythingOfType("comment.FindAllOptionsFunc"),
+		mock.AnythingOfType("comment.FindAllOptionsFunc"),
+		mock.AnythingOfType("comment.FindAllOptionsFunc")).
+		Return([]*comment.Comment{top, second, third}, nil).Once()
+	s.accountRepo.On("GetAccounts", mock.Anything, []domain.Address{alice, bob}).Return([]*account.Account{}, nil).Once()
+	s.collectionRepo.On("FindOne", mock.Anything, thread.ToCollectionId()).
+		Return(&collection.Collection{TokenType: domain.TokenType721}, nil).Once()
+	// holds of all authors are counted at once
+	s.nftitemRepo.On("CountByOwners", mock.Anything, thread.ChainId, thread.ContractAddress, []domain.Address{alice, bob}).
+		Return(map[domain.Address]int64{alice: 2}, nil).Once()
+
+	res, err := s.im.Find(s.ctx, thread, "", nil, 0, 10)
+	s.Require().NoError(err)
+	s.Equal(3, res.Count)
+	s.Equal(int64(2), res.Items[0].Holds)
+	s.Equal(int64(0), res.Items[1].Holds)
+	s.Equal(int64(2), res.Items[2].Holds)
+}
+
+func (s *CommentSuite) TestPostInvalidParent() {
+	top := s.newComment("top", alice, "")
+	reply := s.newComment("reply", bob, top.Id)
+
+	// replies are one level deep
+	s.mockThread(thread)
+	s.mockFindOne(reply)
+	_, err := s.im.Post(s.ctx, bob, thread, reply.Id, "gm")
+	s.ErrorIs(err, comment.ErrInvalidParent)
+
+	// and stay in the thread
+	collectionThread := comment.Thread{ChainId: 1, ContractAddress: contractAddress}
+	s.mockThread(collectionThread)
+	s.mockFindOne(top)
+	_, err = s.im.Post(s.ctx, bob, collectionThread, top.Id, "gm")
+	s.ErrorIs(err, comment.ErrInvalidParent)
+
+	top.IsRemoved = true
+	s.mockThread(thread)
+	s.mockFindOne(top)
+	_, err = s.im.Post(s.ctx, bob, thread, top.Id, "gm")
+	s.ErrorIs(err, comment.ErrInvalidParent)
+}
+
+func (s *CommentSuite) TestPostRateLimited() {
+	s.mockThread(thread)
+	s.mockAccount(alice, false)
+	s.mockAllow(alice, false)
+	_, err := s.im.Post(s.ctx, alice, thread, "", "gm")
+	s.ErrorIs(err, comment.ErrRateLimited)
+}
+
+func (s *CommentSuite) TestPostBanned() {
+	// banned accounts are rejected before hitting the limiter
+	s.mockThread(thread)
+	s.mockAccount(bob, true)
+	_, err := s.im.Post(s.ctx, bob, thread, "", "gm")
+	s.ErrorIs(err, comment.ErrBanned)
+}
+
+func (s *CommentSuite) TestEdit() {
+	top := s.newComment("top", alice, "")
+
+	s.mockFindOne(top)
+	_, err := s.im.Edit(s.ctx, bob, thread, top.Id, "gn")
+	s.ErrorIs(err, comment.ErrNotAuthor)
+
+	s.mockFindOne(top)
+	s.repo.On("Update", mock.Anything, top.Id, mock.MatchedBy(func(updater *comment.Updater) bool {
+		return *updater.Body == "gn" && updater.EditedAt.Equal(s.now) && updater.IsDeleted == nil && updater.IsRemoved == nil
+	})).Return(nil).Once()
+	edited, err := s.im.Edit(s.ctx, alice, thread, top.Id, "gn")
+	s.NoError(err)
+	s.Equal("gn", edited.Body)
+	s.NotNil(edited.EditedAt)
+
+	s.now = s.now.Add(comment.EditWindow + time.Second)
+	s.mockFindOne(top)
+	_, err = s.im.Edit(s.ctx, alice, thread, top.Id, "gm")
+	s.ErrorIs(err, comment.ErrEditWindowPassed)
+}
+
+func (s *CommentSuite) TestDelete() {
+	top := s.newComment("top", alice, "")
+	reply := s.newComment("reply", bob, top.Id)
+
+	// deleting a reply no longer counts it to the parent
+	s.mockFindOne(reply)
+	s.repo.On("Update", mock.Anything, reply.Id, mock.MatchedBy(func(updater *comment.Updater) bool {
+		return *updater.Body == "" && *updater.IsDeleted && updater.IsRemoved == nil
+	})).Return(nil).Once()
+	s.repo.On("IncreaseReplyCount", mock.Anything, top.Id, -1).Return(nil).Once()
+	s.NoError(s.im.Delete(s.ctx, bob, thread, reply.Id))
+
+	// the reply is deleted even if its parent isn't counted
+	s.mockFindOne(reply)
+	s.repo.On("Update", mock.Anything, reply.Id, mock.AnythingOfType("*comment.Updater")).Return(nil).Once()
+	s.repo.On("IncreaseReplyCount", mock.Anything, top.Id, -1).Return(errors.New("timeout")).Once()
+	s.NoError(s.im.Delete(s.ctx, bob, thread, reply.Id))
+
+	reply.IsDeleted = true
+	s.mockFindOne(reply)
+	s.ErrorIs(s.im.Delete(s.ctx, bob, thread, reply.Id), domain.ErrNotFound)
+
+	s.now = s.now.Add(comment.DeleteWindow + time.Second)
+	s.mockFindOne(top)
+	s.ErrorIs(s.im.Delete(s.ctx, alice, thread, top.Id), comment.ErrDeleteWindowPassed)
+}
+
+func (s *CommentSuite) TestRemove() {
+	top := s.newComment("top", alice, "")
+
+	// moderators aren't bound by the delete window
+	s.now = s.now.Add(comment.DeleteWindow * 2)
+	s.mockFindOne(top)
+	s.repo.On("Update", mock.Anything, top.Id, mock.MatchedBy(func(updater *comment.Updater) bool {
+		return *updater.Body == "" && *updater.IsRemoved && updater.IsDeleted == nil
+	})).Return(nil).Once()
+	var auditLog *moderation.AuditLog
+	s.auditLogRepo.On("Insert", mock.Anything, mock.AnythingOfType("*moderation.AuditLog")).
+		Run(func(args mock.Arguments) { auditLog = args.Get(1).(*moderation.AuditLog) }).
+		Return(nil).Once()
+	s.NoError(s.im.Remove(s.ctx, bob, thread, top.Id, "spam"))
+	s.Equal(moderation.AuditActionRemoveComment, auditLog.Action)
+	s.Equal(bob, auditLog.Actor)
+	s.Equal(top.Id, auditLog.Target.CommentId)
+	s.Equal(thread.TokenId, auditLog.Target.TokenId)
+	s.Equal("spam", auditLog.Reason)
+
+	// removing twice isn't logged twice
+	top.IsRemoved = true
+	s.mockFindOne(top)
+	s.NoError(s.im.Remove(s.ctx, bob, thread, top.Id, "spam"))
+}
+
+func (s *CommentSuite) TestReact() {
+	top := s.newComment("top", alice, "")
+	isRecord := mock.MatchedBy(func(record *comment.ReactionRecord) bool {
+		return record.CommentId == top.Id && record.Account == bob && record.Reaction == comment.ReactionFire
+	})
+
+	_, err := s.im.React(s.ctx, bob, thread, top.Id, "boo")
+	s.ErrorIs(err, comment.ErrInvalidReaction)
+
+	s.mockFindOne(top)
+	s.mockAccount(bob, false)
+	s.reactionRepo.On("Insert", mock.Anything, isRecord).Return(nil).Once()
+	s.repo.On("IncreaseReactionCount", mock.Anything, top.Id, comment.ReactionFire, 1).
+		Return(&comment.Comment{Id: top.Id, Reactions: map[comment.Reaction]int64{comment.ReactionFire: 1}}, nil).Once()
+	res, err := s.im.React(s.ctx, bob, thread, top.Id, comment.ReactionFire)
+	s.NoError(err)
+	s.Equal(int64(1), res.Reactions[comment.ReactionFire])
+
+	// the count isn't increased on duplicated reactions
+	s.mockFindOne(top)
+	s.mockAccount(bob, false)
+	s.reactionRepo.On("Insert", mock.Anything, isRecord).Return(comment.ErrReactionExists).Once()
+	_, err = s.im.React(s.ctx, bob, thread, top.Id, comment.ReactionFire)
+	s.ErrorIs(err, comment.ErrReactionExists)
+}
+
+func (s *CommentSuite) TestUnreact() {
+	top := s.newComment("top", alice, "")
+
+	s.mockFindOne(top)
+	s.reactionRepo.On("Delete", mock.Anything, top.Id, bob, comment.ReactionFire).Return(nil).Once()
+	s.repo.On("IncreaseReactionCount", mock.Anything, top.Id, comment.ReactionFire, -1).
+		Return(&comment.Comment{Id: top.Id, Reactions: map[comment.Reaction]int64{comment.ReactionFire: 0}}, nil).Once()
+	res, err := s.im.Unreact(s.ctx, bob, thread, top.Id, comment.ReactionFire)
+	s.NoError(err)
+	s.Equal(int64(0), res.Reactions[comment.ReactionFire])
+
+	s.mockFindOne(top)
+	s.reactionRepo.On("Delete", mock.Anything, top.Id, bob, comment.ReactionFire).Return(domain.ErrNotFound).Once()
+	_, err = s.im.Unreact(s.ctx, bob, thread, top.Id, comment.ReactionFire)
+	s.ErrorIs(err, domain.ErrNotFound)
+}
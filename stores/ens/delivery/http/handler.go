@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/x-xyz/goapi/base/ctx"
@@ -10,6 +11,8 @@ import (
 	"github.com/x-xyz/goapi/service/ens"
 )
 
+const maxBatchAddresses = 100
+
 type handler struct {
 	ens ens.ENS
 }
@@ -24,6 +27,10 @@ func New(e *echo.Echo, ens ens.ENS) {
 	g.GET("/resolve/:name", h.Resolve)
 
 	g.GET("/reverse-resolve/:address", h.ReverseResolve)
+
+	g.GET("/reverse-resolve", h.ReverseResolveBatch)
+
+	g.GET("/profile/:name", h.GetProfile)
 }
 
 func (h *handler) Resolve(c echo.Context) error {
@@ -65,3 +72,56 @@ func (h *handler) ReverseResolve(c echo.Context) error {
 
 	return delivery.MakeJsonResp(c, http.StatusOK, name)
 }
+
+func (h *handler) ReverseResolveBatch(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type payload struct {
+		// comma separated addresses
+		Addresses string `query:"addresses" validate:"required"`
+	}
+
+	p := payload{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	addresses := []domain.Address{}
+	for _, address := range strings.Split(p.Addresses, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, domain.Address(address))
+		}
+	}
+	if len(addresses) == 0 || len(addresses) > maxBatchAddresses {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, domain.ErrBadParamInput)
+	}
+
+	names, err := h.ens.ReverseResolveBatch(ctx, addresses)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, names)
+}
+
+func (h *handler) GetProfile(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type payload struct {
+		Name string `param:"name" validate:"required"`
+	}
+
+	p := payload{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	profile, err := h.ens.GetProfile(ctx, p.Name)
+	if err == domain.ErrBadParamInput {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, profile)
+}
@@ -115,3 +115,33 @@ func (h *holdingImpl) CountUniqueOwner(c ctx.Ctx, chainId domain.ChainId, addres
 	}
 	return result[0].NumOwner, nil
 }
+
+func (h *holdingImpl) SumBalanceByOwners(c ctx.Ctx, chainId domain.ChainId, address domain.Address, owners []domain.Address) (map[domain.Address]int64, error) {
+	res := map[domain.Address]int64{}
+	if len(owners) == 0 {
+		return res, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"chainId": chainId, "address": address.ToLower(), "owner": bson.M{"$in": owners}, "balance": bson.M{"$gt": 0}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$owner", "balance": bson.M{"$sum": "$balance"}}}},
+	}
+	iter, close, err := h.q.Pipe(c, domain.TableERC1155Holdings, pipeline)
+	if err != nil {
+		c.WithField("err", err).Error("q.Pipe failed")
+		return nil, err
+	}
+	defer close()
+
+	var result []struct {
+		Owner   domain.Address `bson:"_id"`
+		Balance int64          `bson:"balance"`
+	}
+	if err := iter.All(c, &result); err != nil {
+		c.WithField("err", err).Error("iter.Cursor.All failed")
+		return nil, err
+	}
+	for _, r := range result {
+		res[r.Owner] = r.Balance
+	}
+	return res, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/erc721/contract"
+	"github.com/x-xyz/goapi/domain/lazymint"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
 	"github.com/x-xyz/goapi/domain/token"
@@ -26,6 +27,7 @@ type Erc721EventUseCaseCfg struct {
 	ActivityHistoryRepo       account.ActivityHistoryRepo
 	FolderUsecase             account.FolderUseCase
 	OrderUseCase              order.UseCase
+	VoucherRepo               lazymint.VoucherRepo
 }
 
 type erc721EventUseCase struct {
@@ -38,6 +40,7 @@ type erc721EventUseCase struct {
 	activityHistoryRepo       account.ActivityHistoryRepo
 	folderUsecase             account.FolderUseCase
 	orderUseCase              order.UseCase
+	voucherRepo               lazymint.VoucherRepo
 }
 
 func NewErc721EventUseCase(cfg *Erc721EventUseCaseCfg) contract.Erc721EventUseCase {
@@ -51,6 +54,7 @@ func NewErc721EventUseCase(cfg *Erc721EventUseCaseCfg) contract.Erc721EventUseCa
 		activityHistoryRepo:       cfg.ActivityHistoryRepo,
 		folderUsecase:             cfg.FolderUsecase,
 		orderUseCase:              cfg.OrderUseCase,
+		voucherRepo:               cfg.VoucherRepo,
 	}
 }
 func (u *erc721EventUseCase) Transfer(ctx bCtx.Ctx, chainId domain.ChainId, event *contract.TransferEvent, lMeta *domain.LogMeta) error {
@@ -63,19 +67,34 @@ func (u *erc721EventUseCase) Transfer(ctx bCtx.Ctx, chainId domain.ChainId, even
 	id := nftitem.Id{ChainId: chainId, ContractAddress: lMeta.ContractAddress, TokenId: event.TokenId}
 	token, err := u.nftitem.FindOne(ctx, chainId, lMeta.ContractAddress, event.TokenId)
 	if err == nil {
-		// nft exists
-		if event.To == token.Owner {
+		// nft exists, or it's listed by a mint voucher and minted now
+		lazyMinted := token.LazyMintVoucherId != ""
+		if event.To == token.Owner && !lazyMinted {
 			return nil
 		}
 		patchable := nftitem.PatchableNftItem{
 			Owner:             &event.To,
 			IndexerRetryCount: ptr.Int32(0),
 		}
+		if lazyMinted {
+			patchable.LazyMintVoucherId = ptr.String("")
+		}
 		if err := u.nftitem.Patch(ctx, *token.ToId(), patchable); err != nil {
 			ctx.WithField("err", err).Error("nftitem.Patch failed")
 			return err
 		}
 
+		if lazyMinted {
+			if err := u.markVoucherMinted(ctx, token.LazyMintVoucherId, lMeta); err != nil {
+				ctx.WithFields(log.Fields{
+					"id":        id,
+					"voucherId": token.LazyMintVoucherId,
+					"err":       err,
+				}).Error("markVoucherMinted failed")
+				return err
+			}
+		}
+
 		if err := u.orderUseCase.RefreshOrders(ctx, id); err != nil {
 			ctx.WithFields(log.Fields{
 				"err": err,
@@ -178,6 +197,22 @@ func (u *erc721EventUseCase) Transfer(ctx bCtx.Ctx, chainId domain.ChainId, even
 	return nil
 }
 
+func (u *erc721EventUseCase) markVoucherMinted(ctx bCtx.Ctx, voucherId string, lMeta *domain.LogMeta) error {
+	if u.voucherRepo == nil {
+		return nil
+	}
+	status := lazymint.VoucherStatusMinted
+	updater := lazymint.VoucherUpdater{
+		Status:     &status,
+		MintTxHash: &lMeta.TxHash,
+		MintedAt:   &lMeta.BlockTime,
+	}
+	if err := u.voucherRepo.Update(ctx, voucherId, updater); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
 func (u *erc721EventUseCase) moveNftToNewOwnerPublicFolder(ctx bCtx.Ctx, owner domain.Address, id nftitem.Id) error {
 	if err := u.folderNftRelationshipRepo.DeleteAllRelationsByNftitem(ctx, id); err != nil {
 		ctx.WithFields(log.Fields{
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -29,13 +30,14 @@ func New(
 	h := &handler{externalListing: externalListing, collection: collection, cacheDuration: cacheDuration}
 
 	g := e.Group("/external-listings")
+	g.GET("/floor/:chainId/:contract", h.getFloor, middleware.IsValidAddress("contract"), middleware.CacheHttp(1*time.Minute))
 	g.GET("/:account", h.getListings, middleware.IsValidAddress("account"))
 	g.POST("/:account/refresh", h.refreshListings, middleware.IsValidAddress("account"))
 }
 
 // getListings
 //
-//	@Description	Get opensea listings, should call refresh first
+//	@Description	Get listings on external marketplaces, should call refresh first
 //	@Tags			external-listings
 //	@Accept			json
 //	@Produce		json
@@ -57,7 +59,7 @@ func (h *handler) getListings(c echo.Context) error {
 
 // refreshListings
 //
-//	@Description	Refresh opensea listings
+//	@Description	Refresh listings from all external marketplaces
 //	@Tags			external-listings
 //	@Accept			json
 //	@Produce		json
@@ -80,50 +82,77 @@ func (h *handler) refreshListings(c echo.Context) error {
 		if diff.Seconds() < h.cacheDuration {
 			return delivery.MakeJsonResp(c, http.StatusOK, nil)
 		}
-		for _, oldListing := range oldListings {
-			id := external_listing.ExternalListingId{
-				Owner:   oldListing.Owner,
-				ChainId: oldListing.ChainId,
-			}
-			err = h.externalListing.DeleteListing(ctx, id)
-			if err != nil {
-				return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-			}
-		}
 	}
-	osListings, err := h.externalListing.FetchOpenseaListings(ctx, account, ethereumChainId)
+	// listings of a failed marketplace are kept until it succeeds
+	listingsBySource, err := h.externalListing.FetchListings(ctx, account, ethereumChainId)
 	if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 	var collAddresses []domain.Address
-	for _, osListing := range osListings {
-		collAddresses = append(collAddresses, osListing.ContractAddress)
-	}
-	var opts []collection.FindAllOptions
-	opts = append(opts, collection.WithChainId(1))
-	opts = append(opts, collection.WithAddresses(collAddresses))
-	collections, err := h.collection.FindAll(ctx, opts...)
-	if err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	}
-	var exListings []external_listing.ExternalListing
-	if collections.Count == 0 {
-		return delivery.MakeJsonResp(c, http.StatusOK, nil)
+	for _, listings := range listingsBySource {
+		for _, listing := range listings {
+			collAddresses = append(collAddresses, listing.ContractAddress)
+		}
 	}
 	collectionSet := make(map[domain.Address]void)
-	for _, coll := range collections.Items {
-		collectionSet[coll.Erc721Address] = void{}
-	}
-	for _, osListing := range osListings {
-		if _, ok := collectionSet[osListing.ContractAddress]; ok {
-			exListings = append(exListings, osListing)
+	if len(collAddresses) > 0 {
+		var opts []collection.FindAllOptions
+		opts = append(opts, collection.WithChainId(1))
+		opts = append(opts, collection.WithAddresses(collAddresses))
+		collections, err := h.collection.FindAll(ctx, opts...)
+		if err != nil {
+			return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+		}
+		for _, coll := range collections.Items {
+			collectionSet[coll.Erc721Address] = void{}
 		}
 	}
-	if len(exListings) > 0 {
-		err = h.externalListing.BulkUpsert(ctx, exListings)
-		if err != nil {
+	for source, listings := range listingsBySource {
+		exListings := []external_listing.ExternalListing{}
+		for _, listing := range listings {
+			if _, ok := collectionSet[listing.ContractAddress]; ok {
+				exListings = append(exListings, listing)
+			}
+		}
+		if err := h.externalListing.ReplaceListings(ctx, account, ethereumChainId, source, exListings); err != nil {
 			return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 		}
 	}
 	return delivery.MakeJsonResp(c, http.StatusOK, nil)
 }
+
+// getFloor
+//
+//	@Summary		Get cross-marketplace floor
+//	@Description	Get the floor of a collection across all external marketplaces, with the floor of each
+//	@Description	marketplace
+//	@Tags			external-listings
+//	@Produce		json
+//	@Param			chainId		path		int		true	"chain id"	example(1)
+//	@Param			contract	path		string	true	"collection address"
+//	@Success		200			{object}	external_listing.Floor
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/external-listings/floor/{chainId}/{contract} [get]
+func (h *handler) getFloor(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		ChainId  domain.ChainId `param:"chainId"`
+		Contract domain.Address `param:"contract"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	floor, err := h.externalListing.GetFloor(ctx, p.ChainId, p.Contract)
+	if errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+	return delivery.MakeJsonResp(c, http.StatusOK, floor)
+}
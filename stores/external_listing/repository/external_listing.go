@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"strconv"
 	"time"
 
 	bCtx "github.com/x-xyz/goapi/base/ctx"
@@ -50,6 +51,11 @@ func (r *externalListingRepo) FindAll(ctx bCtx.Ctx, optsFns ...external_listing.
 		}).Error("MakeBsonM failed")
 		return nil, err
 	}
+	if opts.ActiveAt != nil {
+		query["startTime"] = bson.M{"$lte": *opts.ActiveAt}
+		query["deadline"] = bson.M{"$gt": *opts.ActiveAt}
+		query["quantity"] = bson.M{"$gt": 0}
+	}
 	res := []external_listing.ExternalListing{}
 	if err := r.q.Search(ctx, domain.TableExternalListing, offset, limit, sort, query, &res); err != nil {
 		ctx.WithField("err", err).Error("q.Search failed")
@@ -66,12 +72,21 @@ func (r *externalListingRepo) BulkUpsert(ctx bCtx.Ctx, items []external_listing.
 	ops := []query.UpsertOp{}
 	nowTime := time.Now()
 	for _, it := range items {
+		// prices are display prices in the native token, kept as a number to sort in queries
+		priceInNative, err := strconv.ParseFloat(it.Price, 64)
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"externalListing": it,
+				"err":             err,
+			}).Warn("strconv.ParseFloat failed")
+		}
 		ops = append(ops, query.UpsertOp{
 			Selector: bson.M{
 				"owner":           it.Owner,
 				"chainId":         it.ChainId,
 				"contractAddress": it.ContractAddress,
 				"tokenId":         it.TokenId,
+				"source":          it.Source,
 			},
 			Updater: bson.M{
 				"owner":           it.Owner,
@@ -83,6 +98,7 @@ func (r *externalListingRepo) BulkUpsert(ctx bCtx.Ctx, items []external_listing.
 				"paymentToken":    it.PaymentToken,
 				"price":           it.Price,
 				"priceInUSD":      it.PriceInUsd,
+				"priceInNative":   priceInNative,
 				"startTime":       it.StartTime,
 				"deadline":        it.Deadline,
 				"source":          it.Source,
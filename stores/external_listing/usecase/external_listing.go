@@ -1,30 +1,23 @@
 package usecase
 
 import (
-	"fmt"
-	"github.com/shopspring/decimal"
-	"math/big"
-	"strconv"
-	"time"
+	"errors"
+	"sort"
+	"sync"
 
 	bCtx "github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/log"
-	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/external_listing"
-	"github.com/x-xyz/goapi/service/coingecko"
-	"github.com/x-xyz/goapi/service/opensea"
 )
 
 type impl struct {
-	openseaClient       opensea.Client
+	marketplaces        []external_listing.Marketplace
 	externalListingRepo external_listing.ExternalListingRepo
-	priceFormatter      pricefomatter.PriceFormatter
-	coingecko           coingecko.Client
 }
 
-func New(openseaClient opensea.Client, externalListingRepo external_listing.ExternalListingRepo, priceFormatter pricefomatter.PriceFormatter) external_listing.ExternalListingUseCase {
-	return &impl{openseaClient: openseaClient, externalListingRepo: externalListingRepo, priceFormatter: priceFormatter}
+func New(marketplaces []external_listing.Marketplace, externalListingRepo external_listing.ExternalListingRepo) external_listing.ExternalListingUseCase {
+	return &impl{marketplaces: marketplaces, externalListingRepo: externalListingRepo}
 }
 
 func (im *impl) GetListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) ([]external_listing.ExternalListing, error) {
@@ -32,152 +25,129 @@ func (im *impl) GetListings(ctx bCtx.Ctx, account domain.Address, chainId domain
 	return im.externalListingRepo.FindAll(ctx, opts...)
 }
 
-func (im *impl) FetchOpenseaListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) ([]external_listing.ExternalListing, error) {
-	var getAssetsNext string
-	var listingsCol []external_listing.ExternalListing
-	ethAddress := domain.Address("0x0000000000000000000000000000000000000000")
-	nowTime := time.Now()
-	for {
-		data, err := im.openseaClient.GetAssetByOwner(ctx, account, getAssetsNext)
-		if err != nil {
-			ctx.WithField("err", err).Error("GetAssetByOwner failed")
-			return nil, err
-		}
-		for _, asset := range data.Assets {
-			if len(asset.SellOrders) > 0 {
-				for _, sellOrder := range asset.SellOrders {
-					quantity, err := strconv.ParseInt(sellOrder.Quantity, 10, 64)
-					if err != nil {
-						ctx.WithField("err", err).Error("quantity ParseInt failed")
-						return nil, err
-					}
-					paymentToken := sellOrder.PaymentTokenContract.Address
-					if paymentToken != ethAddress {
-						continue
-					}
-					currentPrice, err := decimal.NewFromString(sellOrder.CurrentPrice)
-					if err != nil {
-						ctx.WithFields(log.Fields{
-							"err":          err,
-							"currentPrice": sellOrder.CurrentPrice,
-						}).Error("currentPrice NewFromString failed")
-						return nil, err
-					}
-					i := decimal.NewFromBigInt(big.NewInt(1), -sellOrder.PaymentTokenContract.Decimals)
-					price := currentPrice.Mul(i)
-					priceInUsd, priceInNative, err := im.priceFormatter.GetPricesFromDisplayPrice(ctx, chainId, paymentToken, price)
-					if err != nil {
-						ctx.WithFields(log.Fields{
-							"err":          err,
-							"currentPrice": sellOrder.CurrentPrice,
-						}).Error("priceFormatter GetPricesFromDisplayPrice failed")
-						return nil, err
-					}
-					startTime, err := time.Parse(time.RFC3339, sellOrder.StartTime)
-					if err != nil {
-						ctx.WithField("err", err).Error("startTime time parse failed")
-						return nil, err
-					}
-					deadline, err := time.Parse("2006-01-02T15:04:05", sellOrder.Deadline)
-					if err != nil {
-						ctx.WithField("err", err).Error("deadline time parse failed")
-						return nil, err
-					}
-					openseaListing := external_listing.ExternalListing{
-						Owner:           account,
-						ChainId:         chainId,
-						Minter:          sellOrder.Taker.Address,
-						ContractAddress: sellOrder.Metadata.Asset.Address,
-						TokenId:         sellOrder.Metadata.Asset.TokenId,
-						Quantity:        quantity,
-						PaymentToken:    paymentToken,
-						Price:           fmt.Sprintf("%f", priceInNative),
-						PriceInUsd:      fmt.Sprintf("%f", priceInUsd),
-						StartTime:       startTime,
-						Deadline:        deadline,
-						Source:          "opensea",
-						UpdatedTime:     nowTime,
-					}
-					listingsCol = append(listingsCol, openseaListing)
-				}
+func (im *impl) FetchListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId) (map[external_listing.Source][]external_listing.ExternalListing, error) {
+	type result struct {
+		source   external_listing.Source
+		listings []external_listing.ExternalListing
+		err      error
+	}
+
+	results := make([]result, len(im.marketplaces))
+	wg := sync.WaitGroup{}
+	for i, marketplace := range im.marketplaces {
+		wg.Add(1)
+		go func(i int, marketplace external_listing.Marketplace) {
+			defer wg.Done()
+			listings, err := marketplace.GetListings(ctx, account, chainId)
+			results[i] = result{source: marketplace.Source(), listings: listings, err: err}
+		}(i, marketplace)
+	}
+	wg.Wait()
+
+	res := map[external_listing.Source][]external_listing.ExternalListing{}
+	var firstErr error
+	for _, r := range results {
+		if r.err != nil {
+			ctx.WithFields(log.Fields{
+				"source":  r.source,
+				"account": account,
+				"err":     r.err,
+			}).Error("marketplace.GetListings failed")
+			if firstErr == nil {
+				firstErr = r.err
 			}
+			continue
+		}
+		res[r.source] = r.listings
+	}
+
+	// a failed marketplace doesn't block the others
+	if firstErr != nil && len(res) == 0 {
+		return nil, firstErr
+	}
+	return res, nil
+}
+
+func (im *impl) ReplaceListings(ctx bCtx.Ctx, account domain.Address, chainId domain.ChainId, source external_listing.Source, listings []external_listing.ExternalListing) error {
+	if err := im.externalListingRepo.RemoveAll(ctx, external_listing.WithExternalListingSource(account.ToLower(), chainId, source)); err != nil {
+		ctx.WithFields(log.Fields{
+			"account": account,
+			"source":  source,
+			"err":     err,
+		}).Error("externalListingRepo.RemoveAll failed")
+		return err
+	}
+	if len(listings) == 0 {
+		return nil
+	}
+	if err := im.externalListingRepo.BulkUpsert(ctx, listings); err != nil {
+		ctx.WithFields(log.Fields{
+			"account": account,
+			"source":  source,
+			"err":     err,
+		}).Error("externalListingRepo.BulkUpsert failed")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) GetFloor(ctx bCtx.Ctx, chainId domain.ChainId, collection domain.Address) (*external_listing.Floor, error) {
+	type result struct {
+		source external_listing.Source
+		floor  *external_listing.SourceFloor
+		err    error
+	}
 
-			var nativeTokenType, erc20TokenType = 0, 1
-			if len(asset.SeaportSellOrders) > 0 {
-				for _, sellOrder := range asset.SeaportSellOrders {
-					var skipFlag bool
-					for _, consideration := range sellOrder.ProtocolData.Parameters.Consideration {
-						if consideration.ContractAddress != ethAddress {
-							skipFlag = true
-							break
-						}
-					}
-					if len(sellOrder.ProtocolData.Parameters.Offer) > 1 || skipFlag {
-						continue
-					}
-					for _, offer := range sellOrder.ProtocolData.Parameters.Offer {
-						if offer.ItemType == nativeTokenType || offer.ItemType == erc20TokenType || offer.ContractAddress.ToLower() != asset.AssetContract.Address.ToLower() {
-							continue
-						}
-						endAmount, err := strconv.ParseInt(offer.EndAmount, 10, 64)
-						if err != nil {
-							ctx.WithField("err", err).Error("quantity ParseInt failed")
-							return nil, err
-						}
-						currentPrice, err := decimal.NewFromString(sellOrder.CurrentPrice)
-						if err != nil {
-							ctx.WithFields(log.Fields{
-								"err":          err,
-								"currentPrice": sellOrder.CurrentPrice,
-							}).Error("currentPrice ParseFloat failed")
-							return nil, err
-						}
-						i := decimal.NewFromBigInt(big.NewInt(1), -18)
-						price := currentPrice.Mul(i)
-						priceInUsd, priceInNative, err := im.priceFormatter.GetPricesFromDisplayPrice(ctx, chainId, ethAddress, price)
-						if err != nil {
-							ctx.WithFields(log.Fields{
-								"err":          err,
-								"currentPrice": sellOrder.CurrentPrice,
-							}).Error("priceFormatter GetPricesFromDisplayPrice failed")
-							return nil, err
-						}
-						startTime, err := time.Parse("2006-01-02T15:04:05", sellOrder.StartTime)
-						if err != nil {
-							ctx.WithField("err", err).Error("startTime time parse failed")
-							return nil, err
-						}
-						deadline, err := time.Parse("2006-01-02T15:04:05", sellOrder.Deadline)
-						if err != nil {
-							ctx.WithField("err", err).Error("deadline time parse failed")
-							return nil, err
-						}
-						openseaListing := external_listing.ExternalListing{
-							Owner:           account,
-							ChainId:         chainId,
-							Minter:          sellOrder.Taker.Address,
-							ContractAddress: asset.AssetContract.Address,
-							TokenId:         asset.TokenId,
-							Quantity:        endAmount,
-							PaymentToken:    ethAddress,
-							Price:           fmt.Sprintf("%f", priceInNative),
-							PriceInUsd:      fmt.Sprintf("%f", priceInUsd),
-							StartTime:       startTime,
-							Deadline:        deadline,
-							Source:          "opensea",
-							UpdatedTime:     nowTime,
-						}
-						listingsCol = append(listingsCol, openseaListing)
-					}
-				}
+	results := make([]result, len(im.marketplaces))
+	wg := sync.WaitGroup{}
+	for i, marketplace := range im.marketplaces {
+		wg.Add(1)
+		go func(i int, marketplace external_listing.Marketplace) {
+			defer wg.Done()
+			floor, err := marketplace.GetFloor(ctx, chainId, collection)
+			results[i] = result{source: marketplace.Source(), floor: floor, err: err}
+		}(i, marketplace)
+	}
+	wg.Wait()
+
+	res := &external_listing.Floor{
+		ChainId:    chainId,
+		Collection: collection.ToLower(),
+		Sources:    []external_listing.SourceFloor{},
+	}
+	var firstErr error
+	for _, r := range results {
+		if errors.Is(r.err, domain.ErrNotFound) {
+			continue
+		} else if r.err != nil {
+			ctx.WithFields(log.Fields{
+				"source":     r.source,
+				"collection": collection,
+				"err":        r.err,
+			}).Error("marketplace.GetFloor failed")
+			if firstErr == nil {
+				firstErr = r.err
 			}
+			continue
 		}
-		getAssetsNext = data.Next
-		if data.Next == "" {
-			break
+		res.Sources = append(res.Sources, *r.floor)
+	}
+	if len(res.Sources) == 0 {
+		if firstErr != nil {
+			return nil, firstErr
 		}
+		return nil, domain.ErrNotFound
 	}
-	return listingsCol, nil
+
+	sort.Slice(res.Sources, func(i, j int) bool {
+		return res.Sources[i].PriceInNative < res.Sources[j].PriceInNative
+	})
+	cheapest := res.Sources[0]
+	res.Source = cheapest.Source
+	res.TokenId = cheapest.TokenId
+	res.PriceInNative = cheapest.PriceInNative
+	res.PriceInUsd = cheapest.PriceInUsd
+	return res, nil
 }
 
 func (im *impl) BulkUpsert(ctx bCtx.Ctx, o []external_listing.ExternalListing) error {
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	mExternalListing "github.com/x-xyz/goapi/domain/external_listing/mocks"
+)
+
+func mockListings(account domain.Address, source external_listing.Source, listings []external_listing.ExternalListing, err error) *mExternalListing.Marketplace {
+	m := &mExternalListing.Marketplace{}
+	m.On("Source").Return(source).Once()
+	m.On("GetListings", mock.Anything, account, domain.ChainId(1)).Return(listings, err).Once()
+	return m
+}
+
+func mockFloor(collection domain.Address, source external_listing.Source, floor *external_listing.SourceFloor, err error) *mExternalListing.Marketplace {
+	m := &mExternalListing.Marketplace{}
+	m.On("Source").Return(source).Once()
+	m.On("GetFloor", mock.Anything, domain.ChainId(1), collection).Return(floor, err).Once()
+	return m
+}
+
+func assertMarketplaces(t *testing.T, marketplaces []external_listing.Marketplace) {
+	for _, m := range marketplaces {
+		m.(*mExternalListing.Marketplace).AssertExpectations(t)
+	}
+}
+
+func TestFetchListings(t *testing.T) {
+	req := require.New(t)
+	account := domain.Address("0x4444444444444444444444444444444444444444")
+	errFailed := errors.New("failed")
+
+	marketplaces := []external_listing.Marketplace{
+		mockListings(account, external_listing.SourceOpensea, []external_listing.ExternalListing{{TokenId: "1"}}, nil),
+		mockListings(account, external_listing.SourceX2Y2, nil, errFailed),
+		mockListings(account, external_listing.SourceLooksRare, []external_listing.ExternalListing{{TokenId: "2"}}, nil),
+	}
+	im := New(marketplaces, nil)
+
+	listings, err := im.FetchListings(ctx.Background(), account, 1)
+	req.NoError(err)
+	req.Equal(map[external_listing.Source][]external_listing.ExternalListing{
+		external_listing.SourceOpensea:   {{TokenId: "1"}},
+		external_listing.SourceLooksRare: {{TokenId: "2"}},
+	}, listings)
+	assertMarketplaces(t, marketplaces)
+
+	marketplaces = []external_listing.Marketplace{
+		mockListings(account, external_listing.SourceX2Y2, nil, errFailed),
+	}
+	im = New(marketplaces, nil)
+	_, err = im.FetchListings(ctx.Background(), account, 1)
+	req.ErrorIs(err, errFailed)
+	assertMarketplaces(t, marketplaces)
+}
+
+func TestReplaceListings(t *testing.T) {
+	req := require.New(t)
+	account := domain.Address("0x4444444444444444444444444444444444444444")
+
+	removedBy := func(source external_listing.Source) interface{} {
+		// only listings of the source are removed
+		return mock.MatchedBy(func(opt external_listing.RemoveAllOptionsFunc) bool {
+			o, err := external_listing.GetRemoveAllOptions(opt)
+			return err == nil && *o.Owner == account && *o.ChainId == 1 && *o.Source == source
+		})
+	}
+
+	repo := &mExternalListing.ExternalListingRepo{}
+	im := New(nil, repo)
+	listings := []external_listing.ExternalListing{{TokenId: "1", Source: external_listing.SourceX2Y2}}
+	repo.On("RemoveAll", mock.Anything, removedBy(external_listing.SourceX2Y2)).Return(nil).Once()
+	repo.On("BulkUpsert", mock.Anything, listings).Return(nil).Once()
+	req.NoError(im.ReplaceListings(ctx.Background(), account, 1, external_listing.SourceX2Y2, listings))
+
+	// delisted on the source
+	repo.On("RemoveAll", mock.Anything, removedBy(external_listing.SourceOpensea)).Return(nil).Once()
+	req.NoError(im.ReplaceListings(ctx.Background(), account, 1, external_listing.SourceOpensea, nil))
+	repo.AssertExpectations(t)
+}
+
+func TestGetFloor(t *testing.T) {
+	req := require.New(t)
+	collection := domain.Address("0x3333333333333333333333333333333333333333")
+	errFailed := errors.New("failed")
+
+	marketplaces := []external_listing.Marketplace{
+		mockFloor(collection, external_listing.SourceOpensea, &external_listing.SourceFloor{Source: external_listing.SourceOpensea, PriceInNative: 2, PriceInUsd: 2000}, nil),
+		mockFloor(collection, external_listing.SourceLooksRare, &external_listing.SourceFloor{Source: external_listing.SourceLooksRare, TokenId: "3", PriceInNative: 1.5, PriceInUsd: 1500}, nil),
+		// failed and unlisted marketplaces are left out
+		mockFloor(collection, external_listing.SourceX2Y2, nil, errFailed),
+		mockFloor(collection, external_listing.SourceX2Y2, nil, domain.ErrNotFound),
+	}
+	im := New(marketplaces, nil)
+
+	floor, err := im.GetFloor(ctx.Background(), 1, collection)
+	req.NoError(err)
+	req.Equal(external_listing.SourceLooksRare, floor.Source)
+	req.Equal(domain.TokenId("3"), floor.TokenId)
+	req.Equal(1.5, floor.PriceInNative)
+	req.Equal([]external_listing.SourceFloor{
+		{Source: external_listing.SourceLooksRare, TokenId: "3", PriceInNative: 1.5, PriceInUsd: 1500},
+		{Source: external_listing.SourceOpensea, PriceInNative: 2, PriceInUsd: 2000},
+	}, floor.Sources)
+	assertMarketplaces(t, marketplaces)
+
+	marketplaces = []external_listing.Marketplace{mockFloor(collection, external_listing.SourceOpensea, nil, domain.ErrNotFound)}
+	im = New(marketplaces, nil)
+	_, err = im.GetFloor(ctx.Background(), 1, collection)
+	req.ErrorIs(err, domain.ErrNotFound)
+	assertMarketplaces(t, marketplaces)
+
+	marketplaces = []external_listing.Marketplace{mockFloor(collection, external_listing.SourceX2Y2, nil, errFailed)}
+	im = New(marketplaces, nil)
+	_, err = im.GetFloor(ctx.Background(), 1, collection)
+	req.ErrorIs(err, errFailed)
+	assertMarketplaces(t, marketplaces)
+}
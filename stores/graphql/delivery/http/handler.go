@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/graphql"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+type handler struct {
+	graphql graphql.Usecase
+}
+
+func New(e *echo.Echo, graphql graphql.Usecase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{graphql: graphql}
+
+	g := e.Group("/graphql")
+
+	g.GET("", h.query, authMiddleware.OptionalAuth())
+
+	g.POST("", h.query, authMiddleware.OptionalAuth())
+}
+
+type getParams struct {
+	Query         string `query:"query"`
+	OperationName string `query:"operationName"`
+	// json encoded variables
+	Variables string `query:"variables"`
+}
+
+// query godoc
+//
+//	@Summary		GraphQL endpoint
+//	@Description	execute graphql query, schema can be retrieved by introspection
+//	@Tags			graphql
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			query			query	string	false	"query, for GET requests"
+//	@Param			operationName	query	string	false	"operation name, for GET requests"
+//	@Param			variables		query	string	false	"json encoded variables, for GET requests"
+//	@Param			request			body	graphql.Request	false	"request, for POST requests"
+//	@Success		200				{object}	object
+//	@Router			/graphql [get]
+//	@Router			/graphql [post]
+func (h *handler) query(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	req := graphql.Request{}
+
+	if c.Request().Method == http.MethodGet {
+		p := &getParams{}
+		if err := c.Bind(p); err != nil {
+			return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid params")
+		}
+		req.Query = p.Query
+		req.OperationName = p.OperationName
+		if p.Variables != "" {
+			if err := json.Unmarshal([]byte(p.Variables), &req.Variables); err != nil {
+				return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid variables")
+			}
+		}
+	} else if err := c.Bind(&req); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid params")
+	}
+
+	if req.Query == "" {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, "missing query")
+	}
+
+	var viewer *domain.Address
+	if address, ok := c.Get("address").(domain.Address); ok {
+		viewer = &address
+	}
+
+	// errors are part of graphql response
+	return c.JSON(http.StatusOK, h.graphql.Do(ctx, viewer, req))
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/token"
+)
+
+type loaders struct {
+	// keyed by lower case address, values are *account.Info
+	accounts *loader
+	// keyed by collection.ToCollectionKey, values are *collection.Collection
+	collections *loader
+	// keyed by token.ToTokenKey, values are *nftitem.NftItem
+	nftitems *loader
+	// keyed by token.ToTokenKey, values are active []*order.OrderItem of the token
+	orders *loader
+	// keyed by folder id, values are []*nftitem.NftItem of the folder in order
+	folderNftitems *loader
+}
+
+func (im *impl) newLoaders() *loaders {
+	return &loaders{
+		accounts:       newLoader(im.batchAccounts),
+		collections:    newLoader(im.batchCollections),
+		nftitems:       newLoader(im.batchNftitems),
+		orders:         newLoader(im.batchOrders),
+		folderNftitems: newLoader(im.batchFolderNftitems),
+	}
+}
+
+func (im *impl) batchAccounts(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+	addresses := make([]domain.Address, len(keys))
+	for i, key := range keys {
+		addresses[i] = domain.Address(key)
+	}
+
+	accounts, err := im.accountRepo.GetAccounts(c, addresses)
+	if err != nil {
+		c.WithField("err", err).Error("accountRepo.GetAccounts failed")
+		return nil, err
+	}
+
+	res := map[string]interface{}{}
+	for _, a := range accounts {
+		res[a.Address.ToLowerStr()] = a.ToInfo()
+	}
+
+	// addresses without profile are still valid accounts
+	for _, key := range keys {
+		if _, ok := res[key]; !ok {
+			res[key] = &account.Info{Address: domain.Address(key)}
+		}
+	}
+
+	return res, nil
+}
+
+func (im *impl) batchCollections(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+	addresses := map[domain.ChainId][]domain.Address{}
+	for _, key := range keys {
+		chainId, address, err := collection.FromCollectionKey(key)
+		if err != nil {
+			c.WithField("err", err).Error("collection.FromCollectionKey failed")
+			return nil, err
+		}
+		addresses[chainId] = append(addresses[chainId], address)
+	}
+
+	res := map[string]interface{}{}
+	for chainId, addrs := range addresses {
+		cols, err := im.collectionRepo.FindAll(c, collection.WithChainId(chainId), collection.WithAddresses(addrs))
+		if err != nil {
+			c.WithField("err", err).Error("collectionRepo.FindAll failed")
+			return nil, err
+		}
+		for _, col := range cols {
+			res[collection.ToCollectionKey(col.ChainId, col.Erc721Address)] = col
+		}
+	}
+
+	return res, nil
+}
+
+func (im *impl) batchNftitems(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+	ids, err := parseTokenKeys(keys)
+	if err != nil {
+		c.WithField("err", err).Error("parseTokenKeys failed")
+		return nil, err
+	}
+
+	items, err := im.nftitemRepo.FindAll(c, nftitem.WithNftitemIds(ids))
+	if err != nil {
+		c.WithField("err", err).Error("nftitemRepo.FindAll failed")
+		return nil, err
+	}
+
+	res := map[string]interface{}{}
+	for _, item := range items {
+		res[token.ToTokenKey(item.ChainId, item.ContractAddress, item.TokenId)] = item
+	}
+
+	return res, nil
+}
+
+func (im *impl) batchOrders(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+	ids, err := parseTokenKeys(keys)
+	if err != nil {
+		c.WithField("err", err).Error("parseTokenKeys failed")
+		return nil, err
+	}
+
+	now := time.Now()
+	orders, err := im.orderUC.FindAll(c,
+		order.WithNftItemIds(ids),
+		order.WithIsValid(true),
+		order.WithIsUsed(false),
+		order.WithStartTimeLT(now),
+		order.WithEndTimeGT(now),
+	)
+	if err != nil {
+		c.WithField("err", err).Error("orderUC.FindAll failed")
+		return nil, err
+	}
+
+	grouped := map[string][]*order.OrderItem{}
+	for _, o := range orders {
+		key := token.ToTokenKey(o.ChainId, o.Collection, o.TokenId)
+		grouped[key] = append(grouped[key], o)
+	}
+
+	res := map[string]interface{}{}
+	for _, key := range keys {
+		if grouped[key] == nil {
+			res[key] = []*order.OrderItem{}
+			continue
+		}
+		res[key] = grouped[key]
+	}
+
+	return res, nil
+}
+
+func (im *impl) batchFolderNftitems(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+	relations, err := im.relationRepo.GetAllRelations(c, account.WithFolderIds(keys))
+	if err != nil {
+		c.WithField("err", err).Error("relationRepo.GetAllRelations failed")
+		return nil, err
+	}
+
+	res := map[string]interface{}{}
+	for _, key := range keys {
+		res[key] = []*nftitem.NftItem{}
+	}
+	if len(relations) == 0 {
+		return res, nil
+	}
+
+	ids := make([]nftitem.Id, len(relations))
+	for i, relation := range relations {
+		ids[i] = nftitem.Id{ChainId: relation.ChainId, ContractAddress: relation.ContractAddress.ToLower(), TokenId: relation.TokenId}
+	}
+
+	items, err := im.nftitemRepo.FindAll(c, nftitem.WithNftitemIds(ids))
+	if err != nil {
+		c.WithField("err", err).Error("nftitemRepo.FindAll failed")
+		return nil, err
+	}
+
+	found := map[string]*nftitem.NftItem{}
+	for _, item := range items {
+		found[token.ToTokenKey(item.ChainId, item.ContractAddress, item.TokenId)] = item
+	}
+
+	// relations are sorted by index, items removed meanwhile are left out
+	for i, relation := range relations {
+		if item, ok := found[token.ToTokenKey(ids[i].ChainId, ids[i].ContractAddress, ids[i].TokenId)]; ok {
+			res[relation.FolderId] = append(res[relation.FolderId].([]*nftitem.NftItem), item)
+		}
+	}
+
+	return res, nil
+}
+
+func parseTokenKeys(keys []string) ([]nftitem.Id, error) {
+	ids := make([]nftitem.Id, len(keys))
+	for i, key := range keys {
+		chainId, contract, tokenId, err := token.FromTokenKey(key)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = nftitem.Id{ChainId: chainId, ContractAddress: contract, TokenId: tokenId}
+	}
+	return ids, nil
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var errOperationNotFound = errors.New("operation not found")
+
+// pageSizeArg is the argument of paginated fields, the cost of a paginated field is multiplied by it
+const pageSizeArg = "first"
+
+// measurer calculates the depth and cost of a validated query.
+// every field costs 1 and the cost of sub fields of a paginated field is multiplied by the page size,
+// introspection fields are free since they are served from memory.
+type measurer struct {
+	schema    *gql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func measure(schema *gql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (depth int, cost int, err error) {
+	m := &measurer{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.FragmentDefinition:
+			m.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		}
+	}
+
+	if operation == nil {
+		return 0, 0, errOperationNotFound
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	depth, cost = m.selectionSet(operation.SelectionSet, root, 0)
+	return depth, cost, nil
+}
+
+func (m *measurer) selectionSet(set *ast.SelectionSet, parent *gql.Object, depth int) (int, int) {
+	if set == nil || parent == nil {
+		return depth, 0
+	}
+
+	maxDepth, cost := depth, 0
+	for _, selection := range set.Selections {
+		var (
+			d int
+			c int
+		)
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = m.field(s, parent, depth)
+		case *ast.FragmentSpread:
+			fragment, ok := m.fragments[s.Name.Value]
+			if !ok {
+				continue
+			}
+			d, c = m.selectionSet(fragment.SelectionSet, m.object(fragment.TypeCondition, parent), depth)
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(s.SelectionSet, m.object(s.TypeCondition, parent), depth)
+		}
+
+		if d > maxDepth {
+			maxDepth = d
+		}
+		cost += c
+	}
+
+	return maxDepth, cost
+}
+
+func (m *measurer) field(field *ast.Field, parent *gql.Object, depth int) (int, int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return depth, 0
+	}
+
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return depth, 0
+	}
+
+	subDepth, subCost := m.selectionSet(field.SelectionSet, namedObject(def.Type), depth+1)
+	return subDepth, 1 + m.multiplier(field, def)*subCost
+}
+
+func (m *measurer) multiplier(field *ast.Field, def *gql.FieldDefinition) int {
+	var argDef *gql.Argument
+	for _, arg := range def.Args {
+		if arg.Name() == pageSizeArg {
+			argDef = arg
+		}
+	}
+
+	if argDef == nil {
+		return 1
+	}
+
+	first := 0
+	if v, ok := argDef.DefaultValue.(int); ok {
+		first = v
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != pageSizeArg {
+			continue
+		}
+		if v, ok := m.intValue(arg.Value); ok {
+			first = v
+		}
+	}
+
+	return pageSize(first)
+}
+
+func (m *measurer) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		i, err := strconv.Atoi(v.Value)
+		return i, err == nil
+	case *ast.Variable:
+		switch i := m.variables[v.Name.Value].(type) {
+		case int:
+			return i, true
+		case float64:
+			// numbers in json variables are decoded as float64
+			return int(i), true
+		}
+	}
+	return 0, false
+}
+
+func (m *measurer) object(cond *ast.Named, parent *gql.Object) *gql.Object {
+	if cond == nil {
+		return parent
+	}
+	obj, _ := m.schema.Type(cond.Name.Value).(*gql.Object)
+	return obj
+}
+
+// namedObject unwraps list and non null types, nil is returned for scalars
+func namedObject(t gql.Output) *gql.Object {
+	for {
+		switch v := t.(type) {
+		case *gql.List:
+			t = v.OfType
+		case *gql.NonNull:
+			t = v.OfType
+		case *gql.Object:
+			return v
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasure(t *testing.T) {
+	im := New(&GraphqlUseCaseCfg{}).(*impl)
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		depth     int
+		cost      int
+	}{
+		{
+			name:  "nested object",
+			query: `{ nftItem(chainId: 1, contractAddress: "0x1", tokenId: "1") { name owner { alias } } }`,
+			depth: 3,
+			cost:  4,
+		},
+		{
+			name:  "paginated",
+			query: `{ nftItems(first: 10) { items { name } nextCursor } }`,
+			depth: 3,
+			cost:  1 + 10*(2+1),
+		},
+		{
+			name:  "default page size",
+			query: `{ collections { items { name } } }`,
+			depth: 3,
+			cost:  1 + defaultPageSize*2,
+		},
+		{
+			name:  "page size is capped",
+			query: `{ collections(first: 1000) { items { name } } }`,
+			depth: 3,
+			cost:  1 + maxPageSize*2,
+		},
+		{
+			name:      "page size from variables",
+			query:     `query q($n: Int) { nftItems(first: $n) { items { name } } }`,
+			variables: map[string]interface{}{"n": float64(5)},
+			depth:     3,
+			cost:      1 + 5*2,
+		},
+		{
+			name:  "nested pagination",
+			query: `{ collections(first: 10) { items { nftItems(first: 10) { items { name } } } } }`,
+			depth: 5,
+			cost:  1 + 10*(1+1*(1+10*(1+1))),
+		},
+		{
+			name:  "fragments",
+			query: `{ account(address: "0x1") { ...f ... on Account { bio } } } fragment f on Account { alias folders(first: 1) { name } }`,
+			depth: 3,
+			cost:  1 + 1 + 2 + 1,
+		},
+		{
+			name:  "lists without cursor are limited",
+			query: `{ nftItem(chainId: 1, contractAddress: "0x1", tokenId: "1") { listings { price } offers(first: 5) { price } } }`,
+			depth: 3,
+			cost:  1 + (1 + defaultPageSize*1) + (1 + 5*1),
+		},
+		{
+			name:  "folder items are limited",
+			query: `{ folder(id: "f") { nftItems(first: 1000) { name } } }`,
+			depth: 3,
+			cost:  1 + 1 + maxPageSize*1,
+		},
+		{
+			name:  "introspection is free",
+			query: `{ __schema { types { name fields { name } } } }`,
+			depth: 0,
+			cost:  0,
+		},
+	}
+
+	for _, c := range cases {
+		doc, err := parser.Parse(parser.ParseParams{Source: c.query})
+		assert.NoError(t, err, c.name)
+
+		depth, cost, err := measure(&im.schema, doc, "", c.variables)
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.depth, depth, c.name)
+		assert.Equal(t, c.cost, cost, c.name)
+	}
+}
+
+func TestMeasureOperationNotFound(t *testing.T) {
+	im := New(&GraphqlUseCaseCfg{}).(*impl)
+
+	doc, err := parser.Parse(parser.ParseParams{Source: `query a { me { alias } }`})
+	assert.NoError(t, err)
+
+	_, _, err = measure(&im.schema, doc, "b", nil)
+	assert.Equal(t, errOperationNotFound, err)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/x-xyz/goapi/base/ctx"
+)
+
+// batchFunc fetches values of keys in one query, keys not found are left out of the result
+type batchFunc func(c ctx.Ctx, keys []string) (map[string]interface{}, error)
+
+type loadResult struct {
+	value interface{}
+	err   error
+}
+
+// loader batches the lookups of resolvers in the same level of a query.
+// graphql-go resolves the thunks returned by resolvers after visiting all fields of a level,
+// so keys are queued by Load and fetched in one batch when the first thunk is called.
+// results are cached for the lifetime of the loader, which is a single request.
+type loader struct {
+	mu      sync.Mutex
+	batch   batchFunc
+	pending []string
+	queued  map[string]bool
+	results map[string]*loadResult
+}
+
+func newLoader(batch batchFunc) *loader {
+	return &loader{
+		batch:   batch,
+		queued:  map[string]bool{},
+		results: map[string]*loadResult{},
+	}
+}
+
+func (l *loader) Load(c ctx.Ctx, key string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch(c)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		r := l.results[key]
+		return r.value, r.err
+	}
+}
+
+func (l *loader) dispatch(c ctx.Ctx) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) == 0 {
+		return
+	}
+
+	keys := l.pending
+	l.pending = nil
+	l.queued = map[string]bool{}
+
+	values, err := l.batch(c, keys)
+	for _, key := range keys {
+		r := &loadResult{err: err}
+		if v, ok := values[key]; ok && err == nil {
+			r.value = v
+		}
+		l.results[key] = r
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x-xyz/goapi/base/ctx"
+)
+
+func TestLoaderBatch(t *testing.T) {
+	batches := [][]string{}
+	l := newLoader(func(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+		batches = append(batches, keys)
+		res := map[string]interface{}{}
+		for _, key := range keys {
+			if key != "missing" {
+				res[key] = "value:" + key
+			}
+		}
+		return res, nil
+	})
+
+	c := ctx.Background()
+	a := l.Load(c, "a")
+	b := l.Load(c, "b")
+	a2 := l.Load(c, "a")
+	missing := l.Load(c, "missing")
+
+	v, err := b()
+	assert.NoError(t, err)
+	assert.Equal(t, "value:b", v)
+
+	v, err = a()
+	assert.NoError(t, err)
+	assert.Equal(t, "value:a", v)
+
+	v, err = a2()
+	assert.NoError(t, err)
+	assert.Equal(t, "value:a", v)
+
+	v, err = missing()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	assert.Equal(t, [][]string{{"a", "b", "missing"}}, batches)
+
+	// loaded keys are cached
+	v, err = l.Load(c, "a")()
+	assert.NoError(t, err)
+	assert.Equal(t, "value:a", v)
+	assert.Len(t, batches, 1)
+
+	v, err = l.Load(c, "c")()
+	assert.NoError(t, err)
+	assert.Equal(t, "value:c", v)
+	assert.Equal(t, [][]string{{"a", "b", "missing"}, {"c"}}, batches)
+}
+
+func TestLoaderError(t *testing.T) {
+	errBatch := errors.New("batch failed")
+	l := newLoader(func(c ctx.Ctx, keys []string) (map[string]interface{}, error) {
+		return nil, errBatch
+	})
+
+	c := ctx.Background()
+	a := l.Load(c, "a")
+	b := l.Load(c, "b")
+
+	_, err := a()
+	assert.Equal(t, errBatch, err)
+	_, err = b()
+	assert.Equal(t, errBatch, err)
+}
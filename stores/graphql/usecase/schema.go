@@ -0,0 +1,785 @@
+package usecase
+
+import (
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/graphql"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageSize returns the page size of paginated fields, used by both resolvers and cost calculation
+func pageSize(first int) int {
+	if first <= 0 {
+		return defaultPageSize
+	}
+	if first > maxPageSize {
+		return maxPageSize
+	}
+	return first
+}
+
+// types of the schema, source values of object types are
+//
+//	NftItem: *nftitem.NftItem
+//	Attribute: nftitem.Attribute
+//	Collection: *collection.Collection
+//	Account: *account.Info
+//	OrderItem: *order.OrderItem
+//	ActivityHistory: *account.ActivityHistory
+//	Folder: *account.Folder
+//	connections: *connection
+type types struct {
+	nftitem              *gql.Object
+	attribute            *gql.Object
+	collection           *gql.Object
+	account              *gql.Object
+	orderItem            *gql.Object
+	activityHistory      *gql.Object
+	folder               *gql.Object
+	nftitemConnection    *gql.Object
+	collectionConnection *gql.Object
+	activityConnection   *gql.Object
+}
+
+type connection struct {
+	items      interface{}
+	nextCursor string
+}
+
+func connectionType(name string, item *gql.Object) *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: name,
+		Fields: gql.Fields{
+			"items": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(item))),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*connection).items, nil
+				},
+			},
+			"nextCursor": &gql.Field{
+				Type:        gql.String,
+				Description: "cursor of the next page, null if it is the last page",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					if cursor := p.Source.(*connection).nextCursor; cursor != "" {
+						return cursor, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+}
+
+func paginationArgs(extra gql.FieldConfigArgument) gql.FieldConfigArgument {
+	args := gql.FieldConfigArgument{
+		pageSizeArg: &gql.ArgumentConfig{
+			Type:         gql.Int,
+			DefaultValue: defaultPageSize,
+			Description:  "page size, at most 100",
+		},
+		"after": &gql.ArgumentConfig{
+			Type:        gql.String,
+			Description: "nextCursor of the previous page",
+		},
+	}
+	for k, v := range extra {
+		args[k] = v
+	}
+	return args
+}
+
+// limitArgs are the arguments of list fields without cursor, which are cut to the page size and costed by it
+func limitArgs() gql.FieldConfigArgument {
+	return gql.FieldConfigArgument{
+		pageSizeArg: &gql.ArgumentConfig{
+			Type:         gql.Int,
+			DefaultValue: defaultPageSize,
+			Description:  "max number of items, at most 100",
+		},
+	}
+}
+
+func pagination(p gql.ResolveParams) (int, string) {
+	first, _ := p.Args[pageSizeArg].(int)
+	after, _ := p.Args["after"].(string)
+	return pageSize(first), after
+}
+
+func (im *impl) buildSchema() (gql.Schema, error) {
+	t := &types{}
+
+	t.attribute = gql.NewObject(gql.ObjectConfig{
+		Name: "Attribute",
+		Fields: gql.Fields{
+			"traitType":   attributeField(gql.String, func(a nftitem.Attribute) interface{} { return a.TraitType }),
+			"value":       attributeField(gql.String, func(a nftitem.Attribute) interface{} { return a.Value }),
+			"displayType": attributeField(gql.String, func(a nftitem.Attribute) interface{} { return a.DisplayType }),
+			"valueType":   attributeField(gql.String, func(a nftitem.Attribute) interface{} { return string(a.ValueType) }),
+			"numberValue": attributeField(gql.Float, func(a nftitem.Attribute) interface{} { return floatPtr(a.NumberValue) }),
+			"dateValue":   attributeField(gql.DateTime, func(a nftitem.Attribute) interface{} { return timePtr(a.DateValue) }),
+		},
+	})
+
+	t.account = gql.NewObject(gql.ObjectConfig{
+		Name:   "Account",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.accountFields(t) }),
+	})
+
+	t.nftitem = gql.NewObject(gql.ObjectConfig{
+		Name:   "NftItem",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.nftitemFields(t) }),
+	})
+
+	t.collection = gql.NewObject(gql.ObjectConfig{
+		Name:   "Collection",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.collectionFields(t) }),
+	})
+
+	t.orderItem = gql.NewObject(gql.ObjectConfig{
+		Name:   "OrderItem",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.orderItemFields(t) }),
+	})
+
+	t.activityHistory = gql.NewObject(gql.ObjectConfig{
+		Name:   "ActivityHistory",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.activityHistoryFields(t) }),
+	})
+
+	t.folder = gql.NewObject(gql.ObjectConfig{
+		Name:   "Folder",
+		Fields: gql.FieldsThunk(func() gql.Fields { return im.folderFields(t) }),
+	})
+
+	t.nftitemConnection = connectionType("NftItemConnection", t.nftitem)
+	t.collectionConnection = connectionType("CollectionConnection", t.collection)
+	t.activityConnection = connectionType("ActivityHistoryConnection", t.activityHistory)
+
+	return gql.NewSchema(gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{
+			Name:   "Query",
+			Fields: im.queryFields(t),
+		}),
+	})
+}
+
+func (im *impl) queryFields(t *types) gql.Fields {
+	return gql.Fields{
+		"nftItem": &gql.Field{
+			Type: t.nftitem,
+			Args: gql.FieldConfigArgument{
+				"chainId":         &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)},
+				"contractAddress": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				"tokenId":         &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, _ := fromParams(p)
+				id := nftitem.Id{
+					ChainId:         domain.ChainId(p.Args["chainId"].(int)),
+					ContractAddress: domain.Address(p.Args["contractAddress"].(string)).ToLower(),
+					TokenId:         domain.TokenId(p.Args["tokenId"].(string)),
+				}
+				res, err := im.tokenUC.FindOne(c, id)
+				if query.IsNotFound(err) {
+					return nil, nil
+				} else if err != nil {
+					c.WithField("err", err).Error("tokenUC.FindOne failed")
+					return nil, err
+				}
+				return &res.NftItem, nil
+			},
+		},
+		"nftItems": &gql.Field{
+			Type: gql.NewNonNull(t.nftitemConnection),
+			Args: paginationArgs(gql.FieldConfigArgument{
+				"chainId":     &gql.ArgumentConfig{Type: gql.Int},
+				"collections": &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.String)), Description: "works with chainId only"},
+				"belongsTo":   &gql.ArgumentConfig{Type: gql.String},
+				"sortBy":      &gql.ArgumentConfig{Type: gql.String, Description: "same as sortBy of rest api"},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				var opts []token.SearchOptionsFunc
+				if chainId, ok := p.Args["chainId"].(int); ok {
+					opts = append(opts, token.WithChainId(domain.ChainId(chainId)))
+					if collections, ok := p.Args["collections"].([]interface{}); ok && len(collections) > 0 {
+						opts = append(opts, token.WithCollections(toAddresses(collections)...))
+					}
+				}
+				if belongsTo, ok := p.Args["belongsTo"].(string); ok {
+					opts = append(opts, token.WithBelongsTo(domain.Address(belongsTo)))
+				}
+				return im.searchNftitems(p, opts...)
+			},
+		},
+		"collection": &gql.Field{
+			Type: t.collection,
+			Args: gql.FieldConfigArgument{
+				"chainId": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)},
+				"address": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, _ := fromParams(p)
+				id := collection.CollectionId{
+					ChainId: domain.ChainId(p.Args["chainId"].(int)),
+					Address: domain.Address(p.Args["address"].(string)).ToLower(),
+				}
+				res, err := im.collectionUC.FindOne(c, id)
+				if query.IsNotFound(err) {
+					return nil, nil
+				} else if err != nil {
+					c.WithField("err", err).Error("collectionUC.FindOne failed")
+					return nil, err
+				}
+				return res, nil
+			},
+		},
+		"collections": &gql.Field{
+			Type: gql.NewNonNull(t.collectionConnection),
+			Args: paginationArgs(gql.FieldConfigArgument{
+				"chainId": &gql.ArgumentConfig{Type: gql.Int},
+				"sortBy":  &gql.ArgumentConfig{Type: gql.String, Description: "same as sortBy of rest api"},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, _ := fromParams(p)
+				first, after := pagination(p)
+				sortByArg, _ := p.Args["sortBy"].(string)
+				sortBy, sortDir := collection.ParseSearchSortOption(collection.SearchSortOption(sortByArg))
+				opts := []collection.FindAllOptions{
+					collection.WithIsAppropriate(true),
+					collection.WithSort(sortBy, sortDir),
+					collection.WithCursor(after),
+					collection.WithPagination(0, int32(first)),
+				}
+				if chainId, ok := p.Args["chainId"].(int); ok {
+					opts = append(opts, collection.WithChainId(domain.ChainId(chainId)))
+				}
+				res, err := im.collectionUC.FindAll(c, opts...)
+				if err != nil {
+					c.WithField("err", err).Error("collectionUC.FindAll failed")
+					return nil, err
+				}
+				items := make([]*collection.Collection, len(res.Items))
+				for i, item := range res.Items {
+					items[i] = &item.Collection
+				}
+				return &connection{items: items, nextCursor: res.NextCursor}, nil
+			},
+		},
+		"account": &gql.Field{
+			Type: t.account,
+			Args: gql.FieldConfigArgument{
+				"address": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return im.getAccount(p, domain.Address(p.Args["address"].(string)))
+			},
+		},
+		"me": &gql.Field{
+			Type:        t.account,
+			Description: "the authenticated account",
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				_, r := fromParams(p)
+				if r.viewer == nil {
+					return nil, graphql.ErrUnauthorized
+				}
+				return im.getAccount(p, *r.viewer)
+			},
+		},
+		"folder": &gql.Field{
+			Type: t.folder,
+			Args: gql.FieldConfigArgument{
+				"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, r := fromParams(p)
+				folder, err := im.folderUC.GetFolder(c, p.Args["id"].(string))
+				if query.IsNotFound(err) {
+					return nil, nil
+				} else if err != nil {
+					c.WithField("err", err).Error("folderUC.GetFolder failed")
+					return nil, err
+				}
+				if folder.IsPrivate && !r.isViewer(folder.Owner) {
+					return nil, nil
+				}
+				return folder, nil
+			},
+		},
+	}
+}
+
+func (im *impl) nftitemFields(t *types) gql.Fields {
+	return gql.Fields{
+		"chainId":            nftitemField(gql.NewNonNull(gql.Int), func(n *nftitem.NftItem) interface{} { return int(n.ChainId) }),
+		"contractAddress":    nftitemField(gql.NewNonNull(gql.String), func(n *nftitem.NftItem) interface{} { return string(n.ContractAddress) }),
+		"tokenId":            nftitemField(gql.NewNonNull(gql.String), func(n *nftitem.NftItem) interface{} { return string(n.TokenId) }),
+		"tokenType":          nftitemField(gql.Int, func(n *nftitem.NftItem) interface{} { return int(n.TokenType) }),
+		"tokenUri":           nftitemField(gql.String, func(n *nftitem.NftItem) interface{} { return n.TokenUri }),
+		"name":               nftitemField(gql.String, func(n *nftitem.NftItem) interface{} { return n.Name }),
+		"imageUrl":           nftitemField(gql.String, func(n *nftitem.NftItem) interface{} { return n.ImageUrl }),
+		"hostedImageUrl":     nftitemField(gql.String, func(n *nftitem.NftItem) interface{} { return n.HostedImageUrl }),
+		"animationUrl":       nftitemField(gql.String, func(n *nftitem.NftItem) interface{} { return n.AnimationUrl }),
+		"supply":             nftitemField(gql.Int, func(n *nftitem.NftItem) interface{} { return int(n.Supply) }),
+		"price":              nftitemField(gql.Float, func(n *nftitem.NftItem) interface{} { return floatPtr(n.Price) }),
+		"priceInUsd":         nftitemField(gql.Float, func(n *nftitem.NftItem) interface{} { return floatPtr(n.PriceInUsd) }),
+		"lastSalePrice":      nftitemField(gql.Float, func(n *nftitem.NftItem) interface{} { return n.LastSalePrice }),
+		"lastSalePriceInUsd": nftitemField(gql.Float, func(n *nftitem.NftItem) interface{} { return n.LastSalePriceInUsd }),
+		"liked":              nftitemField(gql.Int, func(n *nftitem.NftItem) interface{} { return int(n.Liked) }),
+		"viewed":             nftitemField(gql.Int, func(n *nftitem.NftItem) interface{} { return int(n.Viewed) }),
+		"openrarityRank":     nftitemField(gql.Int, func(n *nftitem.NftItem) interface{} { return n.OpenrarityRank }),
+		"listedAt":           nftitemField(gql.DateTime, func(n *nftitem.NftItem) interface{} { return timePtr(n.ListedAt) }),
+		"soldAt":             nftitemField(gql.DateTime, func(n *nftitem.NftItem) interface{} { return timePtr(n.SoldAt) }),
+		"createdAt":          nftitemField(gql.DateTime, func(n *nftitem.NftItem) interface{} { return n.CreatedAt }),
+		"attributes": nftitemField(gql.NewNonNull(gql.NewList(gql.NewNonNull(t.attribute))), func(n *nftitem.NftItem) interface{} {
+			if n.Attributes == nil {
+				return nftitem.Attributes{}
+			}
+			return n.Attributes
+		}),
+		"owner": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*nftitem.NftItem).Owner), nil
+			},
+		},
+		"creator": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*nftitem.NftItem).Creator), nil
+			},
+		},
+		"collection": &gql.Field{
+			Type: t.collection,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, r := fromParams(p)
+				n := p.Source.(*nftitem.NftItem)
+				return r.loaders.collections.Load(c, collection.ToCollectionKey(n.ChainId, n.ContractAddress)), nil
+			},
+		},
+		"listings": &gql.Field{
+			Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(t.orderItem))),
+			Args: limitArgs(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadOrders(p, true), nil
+			},
+		},
+		"offers": &gql.Field{
+			Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(t.orderItem))),
+			Args: limitArgs(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadOrders(p, false), nil
+			},
+		},
+		"activities": &gql.Field{
+			Type: gql.NewNonNull(t.activityConnection),
+			Args: paginationArgs(nil),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, _ := fromParams(p)
+				n := p.Source.(*nftitem.NftItem)
+				id := nftitem.Id{ChainId: n.ChainId, ContractAddress: n.ContractAddress, TokenId: n.TokenId}
+				res, err := im.tokenUC.GetActivities(c, id, activityOptions(p)...)
+				if err != nil {
+					c.WithField("err", err).Error("tokenUC.GetActivities failed")
+					return nil, err
+				}
+				return activityConnection(res.Items, res.NextCursor), nil
+			},
+		},
+	}
+}
+
+func (im *impl) collectionFields(t *types) gql.Fields {
+	return gql.Fields{
+		"chainId":            collectionField(gql.NewNonNull(gql.Int), func(col *collection.Collection) interface{} { return int(col.ChainId) }),
+		"address":            collectionField(gql.NewNonNull(gql.String), func(col *collection.Collection) interface{} { return string(col.Erc721Address) }),
+		"tokenType":          collectionField(gql.Int, func(col *collection.Collection) interface{} { return int(col.TokenType) }),
+		"name":               collectionField(gql.String, func(col *collection.Collection) interface{} { return col.CollectionName }),
+		"description":        collectionField(gql.String, func(col *collection.Collection) interface{} { return col.Description }),
+		"categories":         collectionField(gql.NewList(gql.NewNonNull(gql.String)), func(col *collection.Collection) interface{} { return col.Categories }),
+		"logoImageHash":      collectionField(gql.String, func(col *collection.Collection) interface{} { return col.LogoImageHash }),
+		"logoImageUrl":       collectionField(gql.String, func(col *collection.Collection) interface{} { return col.LogoImageUrl }),
+		"coverImageUrl":      collectionField(gql.String, func(col *collection.Collection) interface{} { return col.CoverImageURL }),
+		"siteUrl":            collectionField(gql.String, func(col *collection.Collection) interface{} { return col.SiteUrl }),
+		"isVerified":         collectionField(gql.Boolean, func(col *collection.Collection) interface{} { return col.IsVerified }),
+		"royalty":            collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.Royalty }),
+		"supply":             collectionField(gql.Int, func(col *collection.Collection) interface{} { return int(col.Supply) }),
+		"numOwners":          collectionField(gql.Int, func(col *collection.Collection) interface{} { return int(col.NumOwners) }),
+		"totalVolume":        collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.TotalVolume }),
+		"floorPrice":         collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.FloorPriceInNative }),
+		"usdFloorPrice":      collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.FloorPriceInUsd }),
+		"liked":              collectionField(gql.Int, func(col *collection.Collection) interface{} { return int(col.Liked) }),
+		"lastListedAt":       collectionField(gql.DateTime, func(col *collection.Collection) interface{} { return col.LastListedAt }),
+		"lastSoldAt":         collectionField(gql.DateTime, func(col *collection.Collection) interface{} { return col.LastSoldAt }),
+		"highestSale":        collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.HighestSale }),
+		"floorPriceMovement": collectionField(gql.Float, func(col *collection.Collection) interface{} { return col.FloorPriceMovement }),
+		"owner": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*collection.Collection).Owner), nil
+			},
+		},
+		"nftItems": &gql.Field{
+			Type: gql.NewNonNull(t.nftitemConnection),
+			Args: paginationArgs(gql.FieldConfigArgument{
+				"sortBy": &gql.ArgumentConfig{Type: gql.String, Description: "same as sortBy of rest api"},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				col := p.Source.(*collection.Collection)
+				return im.searchNftitems(p, token.WithChainId(col.ChainId), token.WithCollections(col.Erc721Address))
+			},
+		},
+		"activities": &gql.Field{
+			Type: gql.NewNonNull(t.activityConnection),
+			Args: paginationArgs(nil),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, _ := fromParams(p)
+				res, err := im.collectionUC.GetActivities(c, p.Source.(*collection.Collection).ToId(), activityOptions(p)...)
+				if err != nil {
+					c.WithField("err", err).Error("collectionUC.GetActivities failed")
+					return nil, err
+				}
+				return activityConnection(res.Items, res.NextCursor), nil
+			},
+		},
+	}
+}
+
+func (im *impl) accountFields(t *types) gql.Fields {
+	return gql.Fields{
+		"address":    accountField(gql.NewNonNull(gql.String), func(a *account.Info) interface{} { return a.Address.ToLowerStr() }),
+		"alias":      accountField(gql.String, func(a *account.Info) interface{} { return a.Alias }),
+		"bio":        accountField(gql.String, func(a *account.Info) interface{} { return a.Bio }),
+		"imageHash":  accountField(gql.String, func(a *account.Info) interface{} { return a.ImageHash }),
+		"bannerHash": accountField(gql.String, func(a *account.Info) interface{} { return a.BannerHash }),
+		"website":    accountField(gql.String, func(a *account.Info) interface{} { return a.Website }),
+		"twitter":    accountField(gql.String, func(a *account.Info) interface{} { return a.Twitter }),
+		"instagram":  accountField(gql.String, func(a *account.Info) interface{} { return a.Instagram }),
+		"discord":    accountField(gql.String, func(a *account.Info) interface{} { return a.Discord }),
+		"nftItems": &gql.Field{
+			Type: gql.NewNonNull(t.nftitemConnection),
+			Args: paginationArgs(gql.FieldConfigArgument{
+				"sortBy": &gql.ArgumentConfig{Type: gql.String, Description: "same as sortBy of rest api"},
+			}),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return im.searchNftitems(p, token.WithBelongsTo(p.Source.(*account.Info).Address))
+			},
+		},
+		"folders": &gql.Field{
+			Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(t.folder))),
+			Description: "private folders are only visible to the owner",
+			Args:        limitArgs(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, r := fromParams(p)
+				address := p.Source.(*account.Info).Address
+				opts := []account.GetFoldersOptionsFunc{account.WithOwner(address)}
+				if !r.isViewer(address) {
+					opts = append(opts, account.WithPrivate(false))
+				}
+				folders, err := im.folderUC.GetFolders(c, opts...)
+				if err != nil {
+					c.WithField("err", err).Error("folderUC.GetFolders failed")
+					return nil, err
+				}
+				if first, _ := pagination(p); len(folders) > first {
+					folders = folders[:first]
+				}
+				return folders, nil
+			},
+		},
+		"activities": &gql.Field{
+			Type: gql.NewNonNull(t.activityConnection),
+			Args: paginationArgs(nil),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				// accountUC.GetActivities is not used since it looks up tokens of activities one by one
+				c, _ := fromParams(p)
+				opts := append(activityOptions(p), account.ActivityHistoryWithAccount(p.Source.(*account.Info).Address))
+				activities, err := im.activityRepo.FindActivities(c, opts...)
+				if err != nil {
+					c.WithField("err", err).Error("activityRepo.FindActivities failed")
+					return nil, err
+				}
+				nextCursor, err := account.NextActivityHistoryCursor(activities, opts...)
+				if err != nil {
+					c.WithField("err", err).Error("account.NextActivityHistoryCursor failed")
+					return nil, err
+				}
+				return activityConnection(activities, nextCursor), nil
+			},
+		},
+	}
+}
+
+func (im *impl) orderItemFields(t *types) gql.Fields {
+	return gql.Fields{
+		"chainId":       orderItemField(gql.NewNonNull(gql.Int), func(o *order.OrderItem) interface{} { return int(o.ChainId) }),
+		"orderHash":     orderItemField(gql.NewNonNull(gql.String), func(o *order.OrderItem) interface{} { return string(o.OrderHash) }),
+		"orderItemHash": orderItemField(gql.NewNonNull(gql.String), func(o *order.OrderItem) interface{} { return string(o.OrderItemHash) }),
+		"itemIdx":       orderItemField(gql.Int, func(o *order.OrderItem) interface{} { return o.ItemIdx }),
+		"isAsk":         orderItemField(gql.Boolean, func(o *order.OrderItem) interface{} { return o.IsAsk }),
+		"amount":        orderItemField(gql.String, func(o *order.OrderItem) interface{} { return o.Amount }),
+		"price":         orderItemField(gql.String, func(o *order.OrderItem) interface{} { return o.Price }),
+		"currency":      orderItemField(gql.String, func(o *order.OrderItem) interface{} { return string(o.Currency) }),
+		"priceInUsd":    orderItemField(gql.Float, func(o *order.OrderItem) interface{} { return o.PriceInUsd }),
+		"priceInNative": orderItemField(gql.Float, func(o *order.OrderItem) interface{} { return o.PriceInNative }),
+		"displayPrice":  orderItemField(gql.String, func(o *order.OrderItem) interface{} { return o.DisplayPrice }),
+		"strategy":      orderItemField(gql.String, func(o *order.OrderItem) interface{} { return string(o.Strategy) }),
+		"marketplace":   orderItemField(gql.String, func(o *order.OrderItem) interface{} { return o.Marketplace }),
+		"startTime":     orderItemField(gql.DateTime, func(o *order.OrderItem) interface{} { return o.StartTime }),
+		"endTime":       orderItemField(gql.DateTime, func(o *order.OrderItem) interface{} { return o.EndTime }),
+		"signer": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*order.OrderItem).Signer), nil
+			},
+		},
+		"nftItem": &gql.Field{
+			Type: t.nftitem,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				o := p.Source.(*order.OrderItem)
+				return loadNftitem(p, o.ChainId, o.Collection, o.TokenId), nil
+			},
+		},
+	}
+}
+
+func (im *impl) activityHistoryFields(t *types) gql.Fields {
+	return gql.Fields{
+		"chainId":       activityField(gql.NewNonNull(gql.Int), func(a *account.ActivityHistory) interface{} { return int(a.ChainId) }),
+		"type":          activityField(gql.NewNonNull(gql.String), func(a *account.ActivityHistory) interface{} { return string(a.Type) }),
+		"quantity":      activityField(gql.String, func(a *account.ActivityHistory) interface{} { return a.Quantity }),
+		"price":         activityField(gql.String, func(a *account.ActivityHistory) interface{} { return a.Price }),
+		"paymentToken":  activityField(gql.String, func(a *account.ActivityHistory) interface{} { return string(a.PaymentToken) }),
+		"priceInUsd":    activityField(gql.Float, func(a *account.ActivityHistory) interface{} { return a.PriceInUsd }),
+		"priceInNative": activityField(gql.Float, func(a *account.ActivityHistory) interface{} { return a.PriceInNative }),
+		"blockNumber":   activityField(gql.Float, func(a *account.ActivityHistory) interface{} { return float64(a.BlockNumber) }),
+		"txHash":        activityField(gql.String, func(a *account.ActivityHistory) interface{} { return string(a.TxHash) }),
+		"time":          activityField(gql.DateTime, func(a *account.ActivityHistory) interface{} { return a.Time }),
+		"source":        activityField(gql.String, func(a *account.ActivityHistory) interface{} { return string(a.Source) }),
+		"account": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*account.ActivityHistory).Account), nil
+			},
+		},
+		"to": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*account.ActivityHistory).To), nil
+			},
+		},
+		"nftItem": &gql.Field{
+			Type: t.nftitem,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				a := p.Source.(*account.ActivityHistory)
+				return loadNftitem(p, a.ChainId, a.ContractAddress, a.TokenId), nil
+			},
+		},
+	}
+}
+
+func (im *impl) folderFields(t *types) gql.Fields {
+	return gql.Fields{
+		"id":              folderField(gql.NewNonNull(gql.String), func(f *account.Folder) interface{} { return f.Id }),
+		"name":            folderField(gql.String, func(f *account.Folder) interface{} { return f.Name }),
+		"isPrivate":       folderField(gql.Boolean, func(f *account.Folder) interface{} { return f.IsPrivate }),
+		"isBuiltIn":       folderField(gql.Boolean, func(f *account.Folder) interface{} { return f.IsBuiltIn }),
+		"floorPriceInUsd": folderField(gql.Float, func(f *account.Folder) interface{} { return f.FloorPriceInUsd }),
+		"totalValueInUsd": folderField(gql.Float, func(f *account.Folder) interface{} { return f.TotalValueInUsd }),
+		"nftCount":        folderField(gql.Int, func(f *account.Folder) interface{} { return f.NftCount }),
+		"collectionCount": folderField(gql.Int, func(f *account.Folder) interface{} { return f.CollectionCount }),
+		"createdAt":       folderField(gql.DateTime, func(f *account.Folder) interface{} { return f.CreatedAt }),
+		"owner": &gql.Field{
+			Type: t.account,
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return loadAccount(p, p.Source.(*account.Folder).Owner), nil
+			},
+		},
+		"nftItems": &gql.Field{
+			Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(t.nftitem))),
+			Args: limitArgs(),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				c, r := fromParams(p)
+				first, _ := pagination(p)
+				thunk := r.loaders.folderNftitems.Load(c, p.Source.(*account.Folder).Id)
+				return func() (interface{}, error) {
+					v, err := thunk()
+					if err != nil {
+						return nil, err
+					}
+					items := v.([]*nftitem.NftItem)
+					if len(items) > first {
+						items = items[:first]
+					}
+					return items, nil
+				}, nil
+			},
+		},
+	}
+}
+
+func (im *impl) getAccount(p gql.ResolveParams, address domain.Address) (interface{}, error) {
+	c, _ := fromParams(p)
+	info, err := im.accountUC.Get(c, address.ToLower())
+	if query.IsNotFound(err) {
+		// addresses without profile are still valid accounts
+		return &account.Info{Address: address.ToLower()}, nil
+	} else if err != nil {
+		c.WithField("err", err).Error("accountUC.Get failed")
+		return nil, err
+	}
+	return info, nil
+}
+
+func (im *impl) searchNftitems(p gql.ResolveParams, opts ...token.SearchOptionsFunc) (interface{}, error) {
+	c, _ := fromParams(p)
+	first, after := pagination(p)
+	sortByArg, _ := p.Args["sortBy"].(string)
+	sortBy, sortDir, requiredOpts := token.ParseSearchSortOption(token.SearchSortOption(sortByArg))
+
+	opts = append(opts, token.WithSort(sortBy, sortDir), token.WithCursor(after), token.WithSize(first))
+	opts = append(opts, requiredOpts...)
+
+	res, err := im.tokenUC.SearchV2(c, opts...)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":    err,
+			"sortBy": sortByArg,
+		}).Error("tokenUC.SearchV2 failed")
+		return nil, err
+	}
+
+	items := make([]*nftitem.NftItem, len(res.Items))
+	for i, item := range res.Items {
+		items[i] = &item.NftItem
+	}
+	return &connection{items: items, nextCursor: res.NextCursor}, nil
+}
+
+func activityOptions(p gql.ResolveParams) []account.FindActivityHistoryOptions {
+	first, after := pagination(p)
+	return []account.FindActivityHistoryOptions{
+		account.ActivityHistoryWithCursor(after),
+		account.ActivityHistoryWithPagination(0, first),
+	}
+}
+
+func activityConnection(activities []account.ActivityHistory, nextCursor string) *connection {
+	items := make([]*account.ActivityHistory, len(activities))
+	for i := range activities {
+		items[i] = &activities[i]
+	}
+	return &connection{items: items, nextCursor: nextCursor}
+}
+
+func loadAccount(p gql.ResolveParams, address domain.Address) interface{} {
+	if address.IsEmpty() {
+		return nil
+	}
+	c, r := fromParams(p)
+	return r.loaders.accounts.Load(c, address.ToLowerStr())
+}
+
+func loadNftitem(p gql.ResolveParams, chainId domain.ChainId, contract domain.Address, tokenId domain.TokenId) interface{} {
+	c, r := fromParams(p)
+	return r.loaders.nftitems.Load(c, token.ToTokenKey(chainId, contract, tokenId))
+}
+
+func loadOrders(p gql.ResolveParams, isAsk bool) interface{} {
+	c, r := fromParams(p)
+	first, _ := pagination(p)
+	n := p.Source.(*nftitem.NftItem)
+	thunk := r.loaders.orders.Load(c, token.ToTokenKey(n.ChainId, n.ContractAddress, n.TokenId))
+	return func() (interface{}, error) {
+		v, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		res := []*order.OrderItem{}
+		for _, o := range v.([]*order.OrderItem) {
+			if o.IsAsk == isAsk && len(res) < first {
+				res = append(res, o)
+			}
+		}
+		return res, nil
+	}
+}
+
+func toAddresses(values []interface{}) []domain.Address {
+	res := make([]domain.Address, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			res = append(res, domain.Address(s).ToLower())
+		}
+	}
+	return res
+}
+
+func floatPtr(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func timePtr(v *time.Time) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// field helpers resolve scalar fields from typed sources
+
+func nftitemField(t gql.Output, get func(*nftitem.NftItem) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*nftitem.NftItem)), nil
+	}}
+}
+
+func attributeField(t gql.Output, get func(nftitem.Attribute) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(nftitem.Attribute)), nil
+	}}
+}
+
+func collectionField(t gql.Output, get func(*collection.Collection) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*collection.Collection)), nil
+	}}
+}
+
+func accountField(t gql.Output, get func(*account.Info) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*account.Info)), nil
+	}}
+}
+
+func orderItemField(t gql.Output, get func(*order.OrderItem) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*order.OrderItem)), nil
+	}}
+}
+
+func activityField(t gql.Output, get func(*account.ActivityHistory) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*account.ActivityHistory)), nil
+	}}
+}
+
+func folderField(t gql.Output, get func(*account.Folder) interface{}) *gql.Field {
+	return &gql.Field{Type: t, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*account.Folder)), nil
+	}}
+}
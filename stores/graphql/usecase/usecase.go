@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/graphql"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/token"
+)
+
+const (
+	defaultMaxDepth = 10
+	defaultMaxCost  = 5000
+)
+
+type GraphqlUseCaseCfg struct {
+	AccountUC    account.Usecase
+	FolderUC     account.FolderUseCase
+	CollectionUC collection.Usecase
+	TokenUC      token.Usecase
+	OrderUC      order.UseCase
+
+	// repos are used for batch lookups which are not provided by usecases
+	AccountRepo    account.Repo
+	CollectionRepo collection.Repo
+	NftitemRepo    nftitem.Repo
+	ActivityRepo   account.ActivityHistoryRepo
+	RelationRepo   account.FolderNftRelationshipRepo
+
+	// MaxDepth and MaxCost limit the complexity of a query, use defaults if 0
+	MaxDepth int
+	MaxCost  int
+}
+
+type impl struct {
+	accountUC      account.Usecase
+	folderUC       account.FolderUseCase
+	collectionUC   collection.Usecase
+	tokenUC        token.Usecase
+	orderUC        order.UseCase
+	accountRepo    account.Repo
+	collectionRepo collection.Repo
+	nftitemRepo    nftitem.Repo
+	activityRepo   account.ActivityHistoryRepo
+	relationRepo   account.FolderNftRelationshipRepo
+	maxDepth       int
+	maxCost        int
+	schema         gql.Schema
+}
+
+func New(cfg *GraphqlUseCaseCfg) graphql.Usecase {
+	im := &impl{
+		accountUC:      cfg.AccountUC,
+		folderUC:       cfg.FolderUC,
+		collectionUC:   cfg.CollectionUC,
+		tokenUC:        cfg.TokenUC,
+		orderUC:        cfg.OrderUC,
+		accountRepo:    cfg.AccountRepo,
+		collectionRepo: cfg.CollectionRepo,
+		nftitemRepo:    cfg.NftitemRepo,
+		activityRepo:   cfg.ActivityRepo,
+		relationRepo:   cfg.RelationRepo,
+		maxDepth:       cfg.MaxDepth,
+		maxCost:        cfg.MaxCost,
+	}
+
+	if im.maxDepth == 0 {
+		im.maxDepth = defaultMaxDepth
+	}
+
+	if im.maxCost == 0 {
+		im.maxCost = defaultMaxCost
+	}
+
+	schema, err := im.buildSchema()
+	if err != nil {
+		// schema is static, failed to build it is a bug
+		panic(err)
+	}
+	im.schema = schema
+
+	return im
+}
+
+func (im *impl) Do(c ctx.Ctx, viewer *domain.Address, req graphql.Request) *gql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if res := gql.ValidateDocument(&im.schema, doc, nil); !res.IsValid {
+		return &gql.Result{Errors: res.Errors}
+	}
+
+	depth, cost, err := measure(&im.schema, doc, req.OperationName, req.Variables)
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if depth > im.maxDepth {
+		c.WithFields(log.Fields{
+			"depth":     depth,
+			"operation": req.OperationName,
+		}).Warn("query exceeds max depth")
+		return &gql.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf("%w: %d > %d", graphql.ErrQueryTooDeep, depth, im.maxDepth))}
+	}
+
+	if cost > im.maxCost {
+		c.WithFields(log.Fields{
+			"cost":      cost,
+			"operation": req.OperationName,
+		}).Warn("query exceeds max cost")
+		return &gql.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf("%w: %d > %d", graphql.ErrQueryTooCostly, cost, im.maxCost))}
+	}
+
+	c = withRequest(c, &request{
+		viewer:  viewer,
+		loaders: im.newLoaders(),
+	})
+
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        im.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       c,
+	})
+}
+
+type requestKey struct{}
+
+// request holds the states of a graphql request which are shared by resolvers
+type request struct {
+	viewer  *domain.Address
+	loaders *loaders
+}
+
+func (r *request) isViewer(address domain.Address) bool {
+	return r.viewer != nil && r.viewer.Equals(address)
+}
+
+func withRequest(c ctx.Ctx, r *request) ctx.Ctx {
+	c.Context = context.WithValue(c.Context, requestKey{}, r)
+	return c
+}
+
+func fromParams(p gql.ResolveParams) (ctx.Ctx, *request) {
+	c := p.Context.(ctx.Ctx)
+	return c, c.Value(requestKey{}).(*request)
+}
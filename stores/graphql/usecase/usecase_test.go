@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/graphql"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/stores/graphql/usecase"
+)
+
+func TestQueryAccount(t *testing.T) {
+	mockAccountUC := &mAccount.Usecase{}
+	mockAccountUC.On("Get", mock.Anything, domain.Address("0xabc")).Return(&account.Info{Address: "0xabc", Alias: "alice"}, nil)
+	mockAccountUC.On("Get", mock.Anything, domain.Address("0xdef")).Return(nil, domain.ErrNotFound)
+
+	u := usecase.New(&usecase.GraphqlUseCaseCfg{AccountUC: mockAccountUC})
+
+	res := u.Do(ctx.Background(), nil, graphql.Request{
+		Query:     `query q($address: String!) { account(address: $address) { address alias } }`,
+		Variables: map[string]interface{}{"address": "0xABC"},
+	})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"account": map[string]interface{}{"address": "0xabc", "alias": "alice"},
+	}, res.Data)
+
+	// addresses without profile are still valid accounts
+	res = u.Do(ctx.Background(), nil, graphql.Request{Query: `{ account(address: "0xdef") { address alias } }`})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"account": map[string]interface{}{"address": "0xdef", "alias": ""},
+	}, res.Data)
+}
+
+func TestQueryMe(t *testing.T) {
+	mockAccountUC := &mAccount.Usecase{}
+	mockAccountUC.On("Get", mock.Anything, domain.Address("0xabc")).Return(&account.Info{Address: "0xabc", Alias: "alice"}, nil)
+
+	u := usecase.New(&usecase.GraphqlUseCaseCfg{AccountUC: mockAccountUC})
+	req := graphql.Request{Query: `{ me { alias } }`}
+
+	viewer := domain.Address("0xabc")
+	res := u.Do(ctx.Background(), &viewer, req)
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, map[string]interface{}{"me": map[string]interface{}{"alias": "alice"}}, res.Data)
+
+	res = u.Do(ctx.Background(), nil, req)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, graphql.ErrUnauthorized.Error(), res.Errors[0].Message)
+	}
+}
+
+func TestQueryFolderNftItems(t *testing.T) {
+	mockAccountUC := &mAccount.Usecase{}
+	mockFolderUC := &mAccount.FolderUseCase{}
+	mockRelationRepo := &mAccount.FolderNftRelationshipRepo{}
+	mockNftitemRepo := &mNftitem.Repo{}
+	defer mockFolderUC.AssertExpectations(t)
+	defer mockRelationRepo.AssertExpectations(t)
+	defer mockNftitemRepo.AssertExpectations(t)
+
+	mockAccountUC.On("Get", mock.Anything, domain.Address("0xabc")).Return(&account.Info{Address: "0xabc"}, nil)
+	mockFolderUC.On("GetFolders", mock.Anything, mock.AnythingOfType("account.GetFoldersOptionsFunc")).
+		Return([]*account.Folder{{Id: "a"}, {Id: "b"}, {Id: "c"}}, nil).Once()
+	// items of all folders are fetched at once
+	mockRelationRepo.On("GetAllRelations", mock.Anything, mock.MatchedBy(func(opt account.RelationsQueryOptionsFunc) bool {
+		o, err := account.ParseRelationsQueryOptionFunc(opt)
+		return err == nil && o.FolderIds != nil && len(*o.FolderIds) == 2
+	})).Return([]*account.FolderNftRelationship{
+		{FolderId: "a", ChainId: 1, ContractAddress: "0x1", TokenId: "2"},
+		{FolderId: "b", ChainId: 1, ContractAddress: "0x1", TokenId: "3"},
+		{FolderId: "a", ChainId: 1, ContractAddress: "0x1", TokenId: "1"},
+		{FolderId: "b", ChainId: 1, ContractAddress: "0x1", TokenId: "4"},
+	}, nil).Once()
+	mockNftitemRepo.On("FindAll", mock.Anything, mock.AnythingOfType("nftitem.FindAllOptionsFunc")).Return([]*nftitem.NftItem{
+		{ChainId: 1, ContractAddress: "0x1", TokenId: "1"},
+		{ChainId: 1, ContractAddress: "0x1", TokenId: "2"},
+		{ChainId: 1, ContractAddress: "0x1", TokenId: "4"},
+	}, nil).Once()
+
+	u := usecase.New(&usecase.GraphqlUseCaseCfg{
+		AccountUC:    mockAccountUC,
+		FolderUC:     mockFolderUC,
+		RelationRepo: mockRelationRepo,
+		NftitemRepo:  mockNftitemRepo,
+	})
+
+	viewer := domain.Address("0xabc")
+	res := u.Do(ctx.Background(), &viewer, graphql.Request{
+		Query: `{ account(address: "0xabc") { folders(first: 2) { id nftItems(first: 1) { tokenId } } } }`,
+	})
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"account": map[string]interface{}{
+			"folders": []interface{}{
+				map[string]interface{}{"id": "a", "nftItems": []interface{}{map[string]interface{}{"tokenId": "2"}}},
+				// removed items are left out
+				map[string]interface{}{"id": "b", "nftItems": []interface{}{map[string]interface{}{"tokenId": "4"}}},
+			},
+		},
+	}, res.Data)
+}
+
+func TestLimits(t *testing.T) {
+	u := usecase.New(&usecase.GraphqlUseCaseCfg{MaxDepth: 3, MaxCost: 50})
+
+	res := u.Do(ctx.Background(), nil, graphql.Request{Query: `{ collections { items { owner { alias } } } }`})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, graphql.ErrQueryTooDeep.Error())
+	}
+	assert.Nil(t, res.Data)
+
+	res = u.Do(ctx.Background(), nil, graphql.Request{Query: `{ collections(first: 30) { items { name } } }`})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, graphql.ErrQueryTooCostly.Error())
+	}
+	assert.Nil(t, res.Data)
+
+	res = u.Do(ctx.Background(), nil, graphql.Request{Query: `{ collections { unknown } }`})
+	assert.NotEmpty(t, res.Errors)
+	assert.Nil(t, res.Data)
+}
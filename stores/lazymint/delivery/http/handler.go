@@ -0,0 +1,162 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/lazymint"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+const maxLimit = 100
+
+type handler struct {
+	lazymint lazymint.UseCase
+}
+
+func New(e *echo.Echo, lazymint lazymint.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{lazymint}
+
+	e.POST("/lazy-mint/vouchers", h.create, authMiddleware.Auth())
+
+	e.GET("/lazy-mint/vouchers", h.list)
+
+	e.GET("/lazy-mint/vouchers/:id", h.get)
+}
+
+// create godoc
+//
+//	@Summary		Create a mint voucher
+//	@Description	Store an EIP-712 mint voucher signed by the creator, the token is listed as not minted yet until
+//	@Description	the voucher is redeemed on chain. The creator must be the signed in account.
+//	@Tags			lazy-mint
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			voucher	body		lazymint.Voucher	true	"signed voucher"
+//	@Success		201		{object}	lazymint.Voucher
+//	@Failure		400
+//	@Failure		403
+//	@Failure		409
+//	@Failure		500
+//	@Router			/lazy-mint/vouchers [post]
+func (h *handler) create(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	address := c.Get("address").(domain.Address)
+
+	voucher := lazymint.Voucher{}
+	if err := c.Bind(&voucher); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if !voucher.Creator.Equals(address) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, nil)
+	}
+
+	res, err := h.lazymint.Create(ctx, voucher)
+	if errors.Is(err, lazymint.ErrInvalidVoucher) ||
+		errors.Is(err, lazymint.ErrUnsupportedContract) ||
+		errors.Is(err, lazymint.ErrVoucherExpired) ||
+		errors.Is(err, domain.ErrInvalidCurrency) ||
+		errors.Is(err, domain.ErrInvalidSignature) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, lazymint.ErrVoucherExists) {
+		return delivery.MakeJsonResp(c, http.StatusConflict, err)
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"voucher": voucher,
+			"err":     err,
+		}).Error("lazymint.Create failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusCreated, res)
+}
+
+// list godoc
+//
+//	@Summary		List mint vouchers
+//	@Description	List mint vouchers, latest first
+//	@Tags			lazy-mint
+//	@Produce		json
+//	@Param			chainId		query		int		false	"chain id"
+//	@Param			contract	query		string	false	"contract address, used with chainId and tokenId"
+//	@Param			tokenId		query		string	false	"token id, used with chainId and contract"
+//	@Param			creator		query		string	false	"creator address"
+//	@Param			status		query		string	false	"active or minted"
+//	@Param			offset		query		int		false	"offset"
+//	@Param			limit		query		int		false	"limit"
+//	@Success		200			{array}		lazymint.Voucher
+//	@Failure		400
+//	@Failure		500
+//	@Router			/lazy-mint/vouchers [get]
+func (h *handler) list(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		ChainId  domain.ChainId          `query:"chainId"`
+		Contract domain.Address          `query:"contract"`
+		TokenId  domain.TokenId          `query:"tokenId"`
+		Creator  *domain.Address         `query:"creator"`
+		Status   *lazymint.VoucherStatus `query:"status"`
+		Offset   int32                   `query:"offset"`
+		Limit    int32                   `query:"limit"`
+	}
+
+	p := params{Limit: maxLimit}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	if p.Limit <= 0 || p.Limit > maxLimit {
+		p.Limit = maxLimit
+	}
+
+	opts := []lazymint.FindAllOptionsFunc{lazymint.WithPagination(p.Offset, p.Limit)}
+
+	if p.ChainId != 0 && p.Contract != "" && p.TokenId != "" {
+		opts = append(opts, lazymint.WithToken(nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}))
+	}
+
+	if p.Creator != nil {
+		opts = append(opts, lazymint.WithCreator(*p.Creator))
+	}
+
+	if p.Status != nil {
+		opts = append(opts, lazymint.WithStatus(*p.Status))
+	}
+
+	res, err := h.lazymint.FindAll(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// get godoc
+//
+//	@Summary		Get a mint voucher
+//	@Description	Get a mint voucher by id, the hex of the signed EIP-712 hash
+//	@Tags			lazy-mint
+//	@Produce		json
+//	@Param			id	path		string	true	"voucher id"
+//	@Success		200	{object}	lazymint.Voucher
+//	@Failure		404
+//	@Failure		500
+//	@Router			/lazy-mint/vouchers/{id} [get]
+func (h *handler) get(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	res, err := h.lazymint.FindOne(ctx, c.Param("id"))
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/lazymint"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type voucherRepoImpl struct {
+	q query.Mongo
+}
+
+func NewVoucherRepo(q query.Mongo) lazymint.VoucherRepo {
+	return &voucherRepoImpl{q}
+}
+
+func (r *voucherRepoImpl) FindAll(ctx bCtx.Ctx, optFns ...lazymint.FindAllOptionsFunc) ([]lazymint.Voucher, error) {
+	opts, err := lazymint.GetFindAllOptions(optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("lazymint.GetFindAllOptions failed")
+		return nil, err
+	}
+
+	selector, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return nil, err
+	}
+
+	var (
+		offset int = 0
+		limit  int = 0
+		sort       = "-createdAt"
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+	if opts.SortBy != nil && opts.SortDir != nil {
+		sort = *opts.SortBy
+		if *opts.SortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+
+	vouchers := []lazymint.Voucher{}
+	if err := r.q.Search(ctx, domain.TableMintVouchers, offset, limit, sort, selector, &vouchers); err != nil {
+		ctx.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+func (r *voucherRepoImpl) Insert(ctx bCtx.Ctx, voucher *lazymint.Voucher) error {
+	if err := r.q.Insert(ctx, domain.TableMintVouchers, voucher); err != nil {
+		ctx.WithFields(log.Fields{
+			"voucher": voucher,
+			"err":     err,
+		}).Error("q.Insert failed")
+		return err
+	}
+	return nil
+}
+
+func (r *voucherRepoImpl) Update(ctx bCtx.Ctx, id string, updater lazymint.VoucherUpdater) error {
+	update, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return err
+	}
+
+	if err := r.q.Patch(ctx, domain.TableMintVouchers, bson.M{"id": id}, update); err == query.ErrNotFound {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("q.Patch failed")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/ethereum"
+	"github.com/x-xyz/goapi/base/log"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter"
+	"github.com/x-xyz/goapi/base/ptr"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/lazymint"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/service/chain/contract"
+)
+
+type UseCaseCfg struct {
+	// Contracts are lazy mint contracts of chains, the verifying contract of vouchers
+	Contracts      map[domain.ChainId]domain.Address
+	VoucherRepo    lazymint.VoucherRepo
+	NftitemRepo    nftitem.Repo
+	PaytokenRepo   domain.PayTokenRepo
+	PriceFormatter pricefomatter.PriceFormatter
+	Erc1271        contract.Erc1271Contract
+}
+
+type impl struct {
+	contracts      map[domain.ChainId]domain.Address
+	voucherRepo    lazymint.VoucherRepo
+	nftitemRepo    nftitem.Repo
+	paytokenRepo   domain.PayTokenRepo
+	priceFormatter pricefomatter.PriceFormatter
+	erc1271        contract.Erc1271Contract
+	now            func() time.Time
+}
+
+func New(cfg *UseCaseCfg) lazymint.UseCase {
+	return &impl{
+		contracts:      cfg.Contracts,
+		voucherRepo:    cfg.VoucherRepo,
+		nftitemRepo:    cfg.NftitemRepo,
+		paytokenRepo:   cfg.PaytokenRepo,
+		priceFormatter: cfg.PriceFormatter,
+		erc1271:        cfg.Erc1271,
+		now:            time.Now,
+	}
+}
+
+func (im *impl) Create(c ctx.Ctx, voucher lazymint.Voucher) (*lazymint.Voucher, error) {
+	voucher.ContractAddress = voucher.ContractAddress.ToLower()
+	voucher.Creator = voucher.Creator.ToLower()
+	voucher.Currency = voucher.Currency.ToLower()
+
+	if contract, ok := im.contracts[voucher.ChainId]; !ok || contract.ToLower() != voucher.ContractAddress {
+		return nil, lazymint.ErrUnsupportedContract
+	}
+	if err := voucher.Validate(); err != nil {
+		return nil, err
+	}
+	now := im.now()
+	if !voucher.ExpiresAt().After(now) {
+		return nil, lazymint.ErrVoucherExpired
+	}
+	if _, err := im.paytokenRepo.FindOne(c, voucher.ChainId, voucher.Currency); err != nil {
+		return nil, domain.ErrInvalidCurrency
+	}
+
+	hash, err := voucher.Hash()
+	if err != nil {
+		c.WithFields(log.Fields{
+			"voucher": voucher,
+			"err":     err,
+		}).Error("voucher.Hash failed")
+		return nil, lazymint.ErrInvalidVoucher
+	}
+	if err := im.verifySignature(c, voucher, hash); err != nil {
+		return nil, err
+	}
+
+	// a token listed by an expired voucher can be listed again by its creator
+	id := voucher.ToNftItemId()
+	item, err := im.nftitemRepo.FindOne(c, id.ChainId, id.ContractAddress, id.TokenId)
+	if err == nil {
+		if err := im.checkReplaceable(c, item, voucher.Creator, now); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("nftitemRepo.FindOne failed")
+		return nil, err
+	}
+
+	price, _ := new(big.Int).SetString(voucher.Price, 10)
+	displayPrice, priceInUsd, _, err := im.priceFormatter.GetPrices(c, voucher.ChainId, voucher.Currency, price)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"voucher": voucher,
+			"err":     err,
+		}).Error("priceFormatter.GetPrices failed")
+		return nil, err
+	}
+
+	voucher.Id = hexutil.Encode(hash)
+	voucher.Status = lazymint.VoucherStatusActive
+	voucher.MintTxHash = ""
+	voucher.MintedAt = nil
+	voucher.CreatedAt = now
+	if err := im.voucherRepo.Insert(c, &voucher); err != nil {
+		c.WithFields(log.Fields{
+			"voucher": voucher,
+			"err":     err,
+		}).Error("voucherRepo.Insert failed")
+		return nil, err
+	}
+
+	expiresAt := voucher.ExpiresAt()
+	priceSource := nftitem.PriceSource(nftitem.PriceSourceListing)
+	if item != nil {
+		patchable := nftitem.PatchableNftItem{
+			TokenUri:          &voucher.TokenUri,
+			Price:             ptr.Float64(displayPrice.InexactFloat64()),
+			PaymentToken:      &voucher.Currency,
+			PriceInUsd:        &priceInUsd,
+			PriceSource:       &priceSource,
+			HasActiveListings: ptr.Bool(true),
+			HasOrder:          ptr.Bool(true),
+			ListedAt:          &now,
+			ListingEndsAt:     &expiresAt,
+			ListingOwners:     []domain.Address{voucher.Creator},
+			LazyMintVoucherId: &voucher.Id,
+		}
+		if err := im.nftitemRepo.Patch(c, id, patchable); err != nil {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("nftitemRepo.Patch failed")
+			return nil, err
+		}
+		return &voucher, nil
+	}
+
+	item = &nftitem.NftItem{
+		ChainId:           voucher.ChainId,
+		ContractAddress:   voucher.ContractAddress,
+		TokenId:           voucher.TokenId,
+		TokenType:         domain.TokenType721,
+		TokenUri:          voucher.TokenUri,
+		Creator:           voucher.Creator,
+		CreatedAt:         now,
+		IsAppropriate:     ptr.Bool(true),
+		ThumbnailPath:     "-",
+		ImagePath:         "-",
+		ImageUrl:          "https://storage.x.xyz/empty_token.jpg",
+		ContentType:       "image",
+		IndexerState:      nftitem.IndexerStateHasTokenURI,
+		Price:             ptr.Float64(displayPrice.InexactFloat64()),
+		PaymentToken:      &voucher.Currency,
+		PriceInUsd:        &priceInUsd,
+		PriceSource:       &priceSource,
+		HasActiveListings: true,
+		HasOrder:          true,
+		ListedAt:          &now,
+		ListingEndsAt:     &expiresAt,
+		ListingOwners:     []domain.Address{voucher.Creator},
+		LazyMintVoucherId: voucher.Id,
+	}
+	if err := im.nftitemRepo.Create(c, item); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("nftitemRepo.Create failed")
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+// checkReplaceable returns nil if the token is not minted and its voucher is expired, a token not minted has no owner
+func (im *impl) checkReplaceable(c ctx.Ctx, item *nftitem.NftItem, creator domain.Address, now time.Time) error {
+	if item.Owner != "" || !item.Creator.Equals(creator) {
+		return lazymint.ErrVoucherExists
+	}
+	if item.LazyMintVoucherId == "" {
+		// the expired voucher is cleared
+		return nil
+	}
+	current, err := im.FindOne(c, item.LazyMintVoucherId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if current.IsRedeemable(now) {
+		return lazymint.ErrVoucherExists
+	}
+	return nil
+}
+
+func (im *impl) verifySignature(c ctx.Ctx, voucher lazymint.Voucher, hash []byte) error {
+	valid, err := ethereum.ValidateHashSignature(hash, voucher.Signature, voucher.Creator.ToLowerStr())
+	if err == nil && valid {
+		return nil
+	}
+	c.WithFields(log.Fields{
+		"hash":  hexutil.Encode(hash),
+		"err":   err,
+		"valid": valid,
+	}).Warn("validating eoa signature failed")
+
+	if im.erc1271 != nil {
+		sig, _ := hexutil.Decode(voucher.Signature)
+		valid, err = im.erc1271.IsValidSignature(c, int32(voucher.ChainId), voucher.Creator.ToLowerStr(), common.BytesToHash(hash), sig)
+		if err == nil && valid {
+			return nil
+		}
+		c.WithFields(log.Fields{
+			"hash":  hexutil.Encode(hash),
+			"err":   err,
+			"valid": valid,
+		}).Warn("validating eip1271 signature failed")
+	}
+
+	return domain.ErrInvalidSignature
+}
+
+func (im *impl) FindAll(c ctx.Ctx, opts ...lazymint.FindAllOptionsFunc) ([]lazymint.Voucher, error) {
+	vouchers, err := im.voucherRepo.FindAll(c, opts...)
+	if err != nil {
+		c.WithField("err", err).Error("voucherRepo.FindAll failed")
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+func (im *impl) FindOne(c ctx.Ctx, id string) (*lazymint.Voucher, error) {
+	vouchers, err := im.voucherRepo.FindAll(c, lazymint.WithId(id), lazymint.WithPagination(0, 1))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("voucherRepo.FindAll failed")
+		return nil, err
+	}
+	if len(vouchers) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &vouchers[0], nil
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	mPriceFormatter "github.com/x-xyz/goapi/base/price_fomatter/mocks"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/lazymint"
+	mLazymint "github.com/x-xyz/goapi/domain/lazymint/mocks"
+	mDomain "github.com/x-xyz/goapi/domain/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+)
+
+var oneEther = big.NewInt(1000000000000000000)
+
+type VoucherSuite struct {
+	suite.Suite
+	ctx            bCtx.Ctx
+	key            *ecdsa.PrivateKey
+	creator        domain.Address
+	contract       domain.Address
+	voucherRepo    *mLazymint.VoucherRepo
+	nftitemRepo    *mNftitem.Repo
+	paytokenRepo   *mDomain.PayTokenRepo
+	priceFormatter *mPriceFormatter.PriceFormatter
+	im             *impl
+}
+
+func TestVoucherSuite(t *testing.T) {
+	suite.Run(t, new(VoucherSuite))
+}
+
+func (s *VoucherSuite) SetupTest() {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.ctx = bCtx.Background()
+	s.key = key
+	s.creator = domain.Address(crypto.PubkeyToAddress(key.PublicKey).Hex()).ToLower()
+	s.contract = "0x1111111111111111111111111111111111111111"
+	s.voucherRepo = &mLazymint.VoucherRepo{}
+	s.nftitemRepo = &mNftitem.Repo{}
+	s.paytokenRepo = &mDomain.PayTokenRepo{}
+	s.priceFormatter = &mPriceFormatter.PriceFormatter{}
+	s.im = New(&UseCaseCfg{
+		Contracts:      map[domain.ChainId]domain.Address{1: s.contract},
+		VoucherRepo:    s.voucherRepo,
+		NftitemRepo:    s.nftitemRepo,
+		PaytokenRepo:   s.paytokenRepo,
+		PriceFormatter: s.priceFormatter,
+	}).(*impl)
+}
+
+func (s *VoucherSuite) TearDownTest() {
+	s.voucherRepo.AssertExpectations(s.T())
+	s.nftitemRepo.AssertExpectations(s.T())
+	s.paytokenRepo.AssertExpectations(s.T())
+	s.priceFormatter.AssertExpectations(s.T())
+}
+
+func (s *VoucherSuite) mockPaytoken() *mock.Call {
+	return s.paytokenRepo.On("FindOne", mock.Anything, domain.ChainId(1), domain.EmptyAddress).Return(&domain.PayToken{}, nil).Once()
+}
+
+func (s *VoucherSuite) mockItem(item *nftitem.NftItem) {
+	call := s.nftitemRepo.On("FindOne", mock.Anything, domain.ChainId(1), s.contract, domain.TokenId("1"))
+	if item == nil {
+		call.Return(nil, domain.ErrNotFound).Once()
+		return
+	}
+	call.Return(item, nil).Once()
+}
+
+// mockListed mocks listing the token by a voucher
+func (s *VoucherSuite) mockListed() {
+	s.priceFormatter.On("GetPrices", mock.Anything, domain.ChainId(1), domain.EmptyAddress, oneEther).
+		Return(decimal.NewFromInt(1), float64(1000), float64(1), nil).Once()
+	s.voucherRepo.On("Insert", mock.Anything, mock.AnythingOfType("*lazymint.Voucher")).Return(nil).Once()
+}
+
+// mockPatched mocks patching the listed token, the patch is returned
+func (s *VoucherSuite) mockPatched() *nftitem.PatchableNftItem {
+	patched := &nftitem.PatchableNftItem{}
+	s.nftitemRepo.On("Patch", mock.Anything, nftitem.Id{ChainId: 1, ContractAddress: s.contract, TokenId: "1"}, mock.AnythingOfType("nftitem.PatchableNftItem")).
+		Run(func(args mock.Arguments) { *patched = args.Get(2).(nftitem.PatchableNftItem) }).
+		Return(nil).Once()
+	return patched
+}
+
+func (s *VoucherSuite) signed(v lazymint.Voucher) lazymint.Voucher {
+	hash, err := v.Hash()
+	s.Require().NoError(err)
+	sig, err := crypto.Sign(hash, s.key)
+	s.Require().NoError(err)
+	v.Signature = hexutil.Encode(sig)
+	return v
+}
+
+func (s *VoucherSuite) voucher() lazymint.Voucher {
+	return lazymint.Voucher{
+		ChainId:         1,
+		ContractAddress: s.contract,
+		TokenId:         "1",
+		Creator:         s.creator,
+		TokenUri:        "ipfs://token",
+		Royalty:         500,
+		Price:           "1000000000000000000",
+		Currency:        domain.EmptyAddress,
+		Expiry:          time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func (s *VoucherSuite) TestCreate() {
+	s.mockPaytoken()
+	s.mockItem(nil)
+	s.mockListed()
+	var item *nftitem.NftItem
+	s.nftitemRepo.On("Create", mock.Anything, mock.AnythingOfType("*nftitem.NftItem")).
+		Run(func(args mock.Arguments) { item = args.Get(1).(*nftitem.NftItem) }).
+		Return(nil).Once()
+
+	v, err := s.im.Create(s.ctx, s.signed(s.voucher()))
+	s.Require().NoError(err)
+	s.Equal(lazymint.VoucherStatusActive, v.Status)
+	s.NotEmpty(v.Id)
+
+	s.Require().NotNil(item)
+	s.Equal(v.Id, item.LazyMintVoucherId)
+	s.Empty(item.Owner)
+	s.Equal(s.creator, item.Creator)
+	s.True(item.HasActiveListings)
+	s.Equal(1.0, *item.Price)
+	s.Equal(1000.0, *item.PriceInUsd)
+	s.Equal(v.ExpiresAt(), *item.ListingEndsAt)
+
+	// the token is listed by a redeemable voucher
+	s.mockPaytoken()
+	s.mockItem(item)
+	s.voucherRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("lazymint.FindAllOptionsFunc"),
+		mock.AnythingOfType("lazymint.FindAllOptionsFunc")).
+		Return([]lazymint.Voucher{*v}, nil).Once()
+	_, err = s.im.Create(s.ctx, s.signed(s.voucher()))
+	s.ErrorIs(err, lazymint.ErrVoucherExists)
+}
+
+func (s *VoucherSuite) TestCreateInvalidSignature() {
+	s.mockPaytoken().Twice()
+
+	v := s.signed(s.voucher())
+	v.Price = "2000000000000000000"
+	_, err := s.im.Create(s.ctx, v)
+	s.ErrorIs(err, domain.ErrInvalidSignature)
+
+	v = s.voucher()
+	v.Signature = "0x1234"
+	_, err = s.im.Create(s.ctx, v)
+	s.ErrorIs(err, domain.ErrInvalidSignature)
+
+	v = s.voucher()
+	v.Signature = "not hex"
+	_, err = s.im.Create(s.ctx, v)
+	s.ErrorIs(err, lazymint.ErrInvalidVoucher)
+}
+
+func (s *VoucherSuite) TestCreateInvalid() {
+	v := s.voucher()
+	v.ContractAddress = "0x2222222222222222222222222222222222222222"
+	_, err := s.im.Create(s.ctx, s.signed(v))
+	s.ErrorIs(err, lazymint.ErrUnsupportedContract)
+
+	v = s.voucher()
+	v.Royalty = lazymint.MaxRoyalty + 1
+	_, err = s.im.Create(s.ctx, s.signed(v))
+	s.ErrorIs(err, lazymint.ErrInvalidVoucher)
+
+	v = s.voucher()
+	v.Expiry = time.Now().Add(-time.Minute).Unix()
+	_, err = s.im.Create(s.ctx, s.signed(v))
+	s.ErrorIs(err, lazymint.ErrVoucherExpired)
+
+	v = s.voucher()
+	v.Currency = "0x3333333333333333333333333333333333333333"
+	s.paytokenRepo.On("FindOne", mock.Anything, domain.ChainId(1), v.Currency).Return(nil, domain.ErrNotFound).Once()
+	_, err = s.im.Create(s.ctx, s.signed(v))
+	s.ErrorIs(err, domain.ErrInvalidCurrency)
+}
+
+func (s *VoucherSuite) TestCreateMintedToken() {
+	s.mockPaytoken()
+	s.mockItem(&nftitem.NftItem{Owner: s.creator, Creator: s.creator})
+	_, err := s.im.Create(s.ctx, s.signed(s.voucher()))
+	s.ErrorIs(err, lazymint.ErrVoucherExists)
+}
+
+func (s *VoucherSuite) TestCreateReplaceCleared() {
+	// the expired voucher is cleared by refreshing listings
+	s.mockPaytoken()
+	s.mockItem(&nftitem.NftItem{Creator: s.creator})
+	s.mockListed()
+	patched := s.mockPatched()
+
+	created, err := s.im.Create(s.ctx, s.signed(s.voucher()))
+	s.Require().NoError(err)
+	s.Equal(created.Id, *patched.LazyMintVoucherId)
+}
+
+func (s *VoucherSuite) TestCreateReplaceExpired() {
+	first := s.voucher()
+	first.Id = "0xfirst"
+	first.Status = lazymint.VoucherStatusActive
+
+	s.mockPaytoken()
+	s.mockItem(&nftitem.NftItem{Creator: s.creator, LazyMintVoucherId: first.Id})
+	s.voucherRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("lazymint.FindAllOptionsFunc"),
+		mock.AnythingOfType("lazymint.FindAllOptionsFunc")).
+		Return([]lazymint.Voucher{first}, nil).Once()
+	s.mockListed()
+	patched := s.mockPatched()
+
+	s.im.now = func() time.Time { return first.ExpiresAt().Add(time.Minute) }
+	v := s.voucher()
+	v.Expiry = first.Expiry + 3600
+	second, err := s.im.Create(s.ctx, s.signed(v))
+	s.Require().NoError(err)
+	s.NotEqual(first.Id, second.Id)
+	s.Equal(second.Id, *patched.LazyMintVoucherId)
+	s.Equal(second.ExpiresAt(), *patched.ListingEndsAt)
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/moderation"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+const maxLimit = 100
+
+type handler struct {
+	moderation moderation.UseCase
+}
+
+func New(e *echo.Echo, moderation moderation.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{moderation}
+
+	e.POST("/reports", h.report, authMiddleware.Auth())
+
+	g := e.Group("", authMiddleware.Auth(), authMiddleware.IsModerator())
+
+	g.GET("/reports", h.listReports)
+
+	g.POST("/reports/:id/triage", h.triage)
+
+	g.GET("/audit-logs", h.listAuditLogs)
+}
+
+// report godoc
+//
+//	@Summary		Report a token, collection or account
+//	@Description	Report a target to moderators, a reporter has at most one open report of a target
+//	@Tags			moderation
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	body		moderation.ReportParams	true	"params"
+//	@Success		201		{object}	moderation.Report
+//	@Failure		400
+//	@Failure		409
+//	@Failure		500
+//	@Router			/reports [post]
+func (h *handler) report(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	reporter := c.Get("address").(domain.Address)
+
+	p := moderation.ReportParams{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.moderation.Report(ctx, reporter, p)
+	if errors.Is(err, moderation.ErrInvalidTarget) || errors.Is(err, moderation.ErrInvalidCategory) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, moderation.ErrReportExists) {
+		return delivery.MakeJsonResp(c, http.StatusConflict, err)
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"reporter": reporter,
+			"params":   p,
+			"err":      err,
+		}).Error("moderation.Report failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusCreated, res)
+}
+
+// listReports godoc
+//
+//	@Summary		List reports
+//	@Description	List reports in the moderation queue, latest first, moderator only
+//	@Tags			moderation
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			status		query		string	false	"open, actioned or dismissed"
+//	@Param			category	query		string	false	"report category"
+//	@Param			targetType	query		string	false	"token, collection or account"
+//	@Param			offset		query		int		false	"offset"
+//	@Param			limit		query		int		false	"limit"
+//	@Success		200			{object}	http.listReports.response
+//	@Failure		400
+//	@Failure		500
+//	@Router			/reports [get]
+func (h *handler) listReports(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Status     *moderation.ReportStatus `query:"status"`
+		Category   *moderation.Category     `query:"category"`
+		TargetType *moderation.TargetType   `query:"targetType"`
+		Offset     int32                    `query:"offset"`
+		Limit      int32                    `query:"limit"`
+	}
+
+	p := params{Limit: maxLimit}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	if p.Limit <= 0 || p.Limit > maxLimit {
+		p.Limit = maxLimit
+	}
+
+	opts := []moderation.ReportFindAllOptionsFunc{moderation.ReportWithPagination(p.Offset, p.Limit)}
+
+	if p.Status != nil {
+		opts = append(opts, moderation.ReportWithStatus(*p.Status))
+	}
+
+	if p.Category != nil {
+		opts = append(opts, moderation.ReportWithCategory(*p.Category))
+	}
+
+	if p.TargetType != nil {
+		opts = append(opts, moderation.ReportWithTargetType(*p.TargetType))
+	}
+
+	type response struct {
+		Items []moderation.Report `json:"items"`
+		Count int                 `json:"count"`
+	}
+
+	items, count, err := h.moderation.FindReports(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, response{items, count})
+}
+
+// triage godoc
+//
+//	@Summary		Triage a report
+//	@Description	Action or dismiss an open report, moderator only. Actioning a report resolves all open reports of the
+//	@Description	same target, and bans the target if ban is set.
+//	@Tags			moderation
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"report id"
+//	@Param			params	body		moderation.TriageParams	true	"params"
+//	@Success		200		{object}	moderation.Report
+//	@Failure		400
+//	@Failure		404
+//	@Failure		409
+//	@Failure		500
+//	@Router			/reports/{id}/triage [post]
+func (h *handler) triage(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+	moderator := c.Get("address").(domain.Address)
+
+	p := moderation.TriageParams{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.moderation.Triage(ctx, moderator, c.Param("id"), p)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, moderation.ErrReportResolved) {
+		return delivery.MakeJsonResp(c, http.StatusConflict, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// listAuditLogs godoc
+//
+//	@Summary		List audit logs
+//	@Description	List ban and unban audit logs, latest first, moderator only
+//	@Tags			moderation
+//	@Security		ApiKeyAuth
+//	@Produce		json
+//	@Param			action	query		string	false	"ban or unban"
+//	@Param			actor	query		string	false	"actor address"
+//	@Param			offset	query		int		false	"offset"
+//	@Param			limit	query		int		false	"limit"
+//	@Success		200		{object}	[]moderation.AuditLog
+//	@Failure		400
+//	@Failure		500
+//	@Router			/audit-logs [get]
+func (h *handler) listAuditLogs(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		Action *moderation.AuditAction `query:"action"`
+		Actor  *domain.Address         `query:"actor"`
+		Offset int32                   `query:"offset"`
+		Limit  int32                   `query:"limit"`
+	}
+
+	p := params{Limit: maxLimit}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	if p.Limit <= 0 || p.Limit > maxLimit {
+		p.Limit = maxLimit
+	}
+
+	opts := []moderation.AuditLogFindAllOptionsFunc{moderation.AuditLogWithPagination(p.Offset, p.Limit)}
+
+	if p.Action != nil {
+		opts = append(opts, moderation.AuditLogWithAction(*p.Action))
+	}
+
+	if p.Actor != nil {
+		opts = append(opts, moderation.AuditLogWithActor(*p.Actor))
+	}
+
+	res, err := h.moderation.FindAuditLogs(ctx, opts...)
+	if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"errors"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/moderation"
+	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addTargetQuery matches the exact target, fields not belonging to the type are not stored
+func addTargetQuery(selector bson.M, target *moderation.Target) {
+	if target == nil {
+		return
+	}
+	selector["target.type"] = target.Type
+	switch target.Type {
+	case moderation.TargetTypeToken:
+		selector["target.chainId"] = target.ChainId
+		selector["target.contractAddress"] = target.ContractAddress
+		selector["target.tokenId"] = target.TokenId
+	case moderation.TargetTypeCollection:
+		selector["target.chainId"] = target.ChainId
+		selector["target.contractAddress"] = target.ContractAddress
+	case moderation.TargetTypeAccount:
+		selector["target.address"] = target.Address
+	case moderation.TargetTypeComment:
+		selector["target.commentId"] = target.CommentId
+	}
+}
+
+func sortOf(sortBy *string, sortDir *domain.SortDir) string {
+	sort := "-createdAt"
+	if sortBy != nil && sortDir != nil {
+		sort = *sortBy
+		if *sortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+	return sort
+}
+
+type reportRepoImpl struct {
+	q query.Mongo
+}
+
+// EnsureReportIndex creates the unique index of (reporter, target) on open reports, which backs one open report of a
+// target per reporter
+func EnsureReportIndex(ctx bCtx.Ctx, q query.Mongo) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "reporter", Value: 1},
+			{Key: "target.type", Value: 1},
+			{Key: "target.chainId", Value: 1},
+			{Key: "target.contractAddress", Value: 1},
+			{Key: "target.tokenId", Value: 1},
+			{Key: "target.address", Value: 1},
+			{Key: "target.commentId", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": moderation.ReportStatusOpen}),
+	}
+	return q.CreateIndex(ctx, domain.TableReports, index)
+}
+
+// NewReportRepo requires the index created by EnsureReportIndex, Insert returns moderation.ErrReportExists if the
+// reporter has an open report of the target
+func NewReportRepo(q query.Mongo) moderation.ReportRepo {
+	return &reportRepoImpl{q}
+}
+
+func (r *reportRepoImpl) selector(ctx bCtx.Ctx, optFns ...moderation.ReportFindAllOptionsFunc) (moderation.ReportFindAllOptions, bson.M, error) {
+	opts, err := moderation.GetReportFindAllOptions(optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("moderation.GetReportFindAllOptions failed")
+		return opts, nil, err
+	}
+
+	selector, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return opts, nil, err
+	}
+	addTargetQuery(selector, opts.Target)
+
+	return opts, selector, nil
+}
+
+func (r *reportRepoImpl) FindAll(ctx bCtx.Ctx, optFns ...moderation.ReportFindAllOptionsFunc) ([]moderation.Report, error) {
+	opts, selector, err := r.selector(ctx, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		offset int = 0
+		limit  int = 0
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+
+	reports := []moderation.Report{}
+	if err := r.q.Search(ctx, domain.TableReports, offset, limit, sortOf(opts.SortBy, opts.SortDir), selector, &reports); err != nil {
+		ctx.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *reportRepoImpl) Count(ctx bCtx.Ctx, optFns ...moderation.ReportFindAllOptionsFunc) (int, error) {
+	_, selector, err := r.selector(ctx, optFns...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := r.q.Count(ctx, domain.TableReports, selector)
+	if err != nil {
+		ctx.WithField("err", err).Error("q.Count failed")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *reportRepoImpl) Insert(ctx bCtx.Ctx, report *moderation.Report) error {
+	if err := r.q.Insert(ctx, domain.TableReports, report); errors.Is(err, query.ErrDuplicateKey) {
+		return moderation.ErrReportExists
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"report": report,
+			"err":    err,
+		}).Error("q.Insert failed")
+		return err
+	}
+	return nil
+}
+
+func (r *reportRepoImpl) Update(ctx bCtx.Ctx, updater moderation.ReportUpdater, optFns ...moderation.ReportFindAllOptionsFunc) error {
+	_, selector, err := r.selector(ctx, optFns...)
+	if err != nil {
+		return err
+	}
+	// never update the whole table
+	if len(selector) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	update, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return err
+	}
+
+	if err := r.q.Patch(ctx, domain.TableReports, selector, update, query.WithPatchMany(true)); err == query.ErrNotFound {
+		return domain.ErrNotFound
+	} else if err != nil {
+		ctx.WithField("err", err).Error("q.Patch failed")
+		return err
+	}
+	return nil
+}
+
+type auditLogRepoImpl struct {
+	q query.Mongo
+}
+
+func NewAuditLogRepo(q query.Mongo) moderation.AuditLogRepo {
+	return &auditLogRepoImpl{q}
+}
+
+func (r *auditLogRepoImpl) FindAll(ctx bCtx.Ctx, optFns ...moderation.AuditLogFindAllOptionsFunc) ([]moderation.AuditLog, error) {
+	opts, err := moderation.GetAuditLogFindAllOptions(optFns...)
+	if err != nil {
+		ctx.WithField("err", err).Error("moderation.GetAuditLogFindAllOptions failed")
+		return nil, err
+	}
+
+	selector, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		ctx.WithField("err", err).Error("MakeBsonM failed")
+		return nil, err
+	}
+	addTargetQuery(selector, opts.Target)
+
+	var (
+		offset int = 0
+		limit  int = 0
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+
+	logs := []moderation.AuditLog{}
+	if err := r.q.Search(ctx, domain.TableAuditLogs, offset, limit, sortOf(opts.SortBy, opts.SortDir), selector, &logs); err != nil {
+		ctx.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *auditLogRepoImpl) Insert(ctx bCtx.Ctx, auditLog *moderation.AuditLog) error {
+	if err := r.q.Insert(ctx, domain.TableAuditLogs, auditLog); err != nil {
+		ctx.WithFields(log.Fields{
+			"auditLog": auditLog,
+			"err":      err,
+		}).Error("q.Insert failed")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/moderation"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/token"
+)
+
+type UseCaseCfg struct {
+	ReportRepo   moderation.ReportRepo
+	AuditLogRepo moderation.AuditLogRepo
+	AccountUC    account.Usecase
+	CollectionUC collection.Usecase
+	TokenUC      token.Usecase
+}
+
+type impl struct {
+	reportRepo   moderation.ReportRepo
+	auditLogRepo moderation.AuditLogRepo
+	accountUC    account.Usecase
+	collectionUC collection.Usecase
+	tokenUC      token.Usecase
+	now          func() time.Time
+}
+
+func New(cfg *UseCaseCfg) moderation.UseCase {
+	return &impl{
+		reportRepo:   cfg.ReportRepo,
+		auditLogRepo: cfg.AuditLogRepo,
+		accountUC:    cfg.AccountUC,
+		collectionUC: cfg.CollectionUC,
+		tokenUC:      cfg.TokenUC,
+		now:          time.Now,
+	}
+}
+
+func (im *impl) Report(c ctx.Ctx, reporter domain.Address, params moderation.ReportParams) (*moderation.Report, error) {
+	if !params.Category.IsValid() {
+		return nil, moderation.ErrInvalidCategory
+	}
+	target, err := params.Target.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	reporter = reporter.ToLower()
+
+	report := &moderation.Report{
+		Id:        uuid.NewString(),
+		Target:    target,
+		Reporter:  reporter,
+		Category:  params.Category,
+		Comment:   params.Comment,
+		Status:    moderation.ReportStatusOpen,
+		CreatedAt: im.now(),
+	}
+	// a reporter has at most one open report of a target, which is enforced by the repo
+	if err := im.reportRepo.Insert(c, report); errors.Is(err, moderation.ErrReportExists) {
+		return nil, err
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"report": report,
+			"err":    err,
+		}).Error("reportRepo.Insert failed")
+		return nil, err
+	}
+	return report, nil
+}
+
+func (im *impl) FindReports(c ctx.Ctx, optFns ...moderation.ReportFindAllOptionsFunc) ([]moderation.Report, int, error) {
+	reports, err := im.reportRepo.FindAll(c, optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("reportRepo.FindAll failed")
+		return nil, 0, err
+	}
+
+	// pagination is ignored by count
+	count, err := im.reportRepo.Count(c, optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("reportRepo.Count failed")
+		return nil, 0, err
+	}
+	return reports, count, nil
+}
+
+func (im *impl) Triage(c ctx.Ctx, moderator domain.Address, id string, params moderation.TriageParams) (*moderation.Report, error) {
+	if params.Status != moderation.ReportStatusActioned && params.Status != moderation.ReportStatusDismissed {
+		return nil, domain.ErrBadParamInput
+	}
+	moderator = moderator.ToLower()
+
+	reports, err := im.reportRepo.FindAll(c, moderation.ReportWithId(id))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("reportRepo.FindAll failed")
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	report := reports[0]
+	if report.Status != moderation.ReportStatusOpen {
+		return nil, moderation.ErrReportResolved
+	}
+
+	if params.Status == moderation.ReportStatusActioned && params.Ban {
+		reason := string(report.Category)
+		if params.Resolution != "" {
+			reason = fmt.Sprintf("%s: %s", report.Category, params.Resolution)
+		}
+		if err := im.ban(c, moderation.AuditActionBan, moderator, report.Target, reason, report.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	now := im.now()
+	updater := moderation.ReportUpdater{
+		Status:     &params.Status,
+		ResolvedBy: &moderator,
+		ResolvedAt: &now,
+		Resolution: &params.Resolution,
+	}
+	optFns := []moderation.ReportFindAllOptionsFunc{
+		moderation.ReportWithStatus(moderation.ReportStatusOpen),
+	}
+	if params.Status == moderation.ReportStatusActioned {
+		// the target is dealt with, so are the other reports of it
+		optFns = append(optFns, moderation.ReportWithTarget(report.Target))
+	} else {
+		optFns = append(optFns, moderation.ReportWithId(report.Id))
+	}
+	if err := im.reportRepo.Update(c, updater, optFns...); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("reportRepo.Update failed")
+		return nil, err
+	}
+
+	report.Status = params.Status
+	report.ResolvedBy = &moderator
+	report.ResolvedAt = &now
+	report.Resolution = params.Resolution
+	return &report, nil
+}
+
+func (im *impl) Ban(c ctx.Ctx, actor domain.Address, target moderation.Target, reason string) error {
+	return im.ban(c, moderation.AuditActionBan, actor, target, reason, "")
+}
+
+func (im *impl) Unban(c ctx.Ctx, actor domain.Address, target moderation.Target, reason string) error {
+	return im.ban(c, moderation.AuditActionUnban, actor, target, reason, "")
+}
+
+// ban bans or unbans the target by action, the audit log is written only if the action succeeds
+func (im *impl) ban(c ctx.Ctx, action moderation.AuditAction, actor domain.Address, target moderation.Target, reason string, reportId string) error {
+	target, err := target.Normalize()
+	if err != nil {
+		return err
+	}
+	isBan := action == moderation.AuditActionBan
+
+	switch target.Type {
+	case moderation.TargetTypeToken:
+		id := nftitem.Id{ChainId: target.ChainId, ContractAddress: target.ContractAddress, TokenId: target.TokenId}
+		if isBan {
+			err = im.tokenUC.BanNftItem(c, id)
+		} else {
+			err = im.tokenUC.UnbanNftItem(c, id)
+		}
+	case moderation.TargetTypeCollection:
+		_, err = im.collectionUC.Ban(c, collection.CollectionId{ChainId: target.ChainId, Address: target.ContractAddress}, isBan)
+	case moderation.TargetTypeAccount:
+		if isBan {
+			err = im.accountUC.Ban(c, target.Address)
+		} else {
+			err = im.accountUC.Unban(c, target.Address)
+		}
+	case moderation.TargetTypeComment:
+		return moderation.ErrInvalidTarget
+	}
+	if err != nil {
+		c.WithFields(log.Fields{
+			"action": action,
+			"target": target,
+			"err":    err,
+		}).Error("ban failed")
+		return err
+	}
+
+	auditLog := &moderation.AuditLog{
+		Id:        uuid.NewString(),
+		Action:    action,
+		Actor:     actor.ToLower(),
+		Target:    target,
+		Reason:    reason,
+		ReportId:  reportId,
+		CreatedAt: im.now(),
+	}
+	if err := im.auditLogRepo.Insert(c, auditLog); err != nil {
+		c.WithFields(log.Fields{
+			"auditLog": auditLog,
+			"err":      err,
+		}).Error("auditLogRepo.Insert failed")
+		return err
+	}
+	return nil
+}
+
+func (im *impl) FindAuditLogs(c ctx.Ctx, optFns ...moderation.AuditLogFindAllOptionsFunc) ([]moderation.AuditLog, error) {
+	logs, err := im.auditLogRepo.FindAll(c, optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("auditLogRepo.FindAll failed")
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	"github.com/x-xyz/goapi/domain/moderation"
+	mModeration "github.com/x-xyz/goapi/domain/moderation/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mToken "github.com/x-xyz/goapi/domain/token/mocks"
+)
+
+type ModerationSuite struct {
+	suite.Suite
+	ctx          bCtx.Ctx
+	reportRepo   *mModeration.ReportRepo
+	auditLogRepo *mModeration.AuditLogRepo
+	accountUC    *mAccount.Usecase
+	collectionUC *mCollection.Usecase
+	tokenUC      *mToken.Usecase
+	im           moderation.UseCase
+}
+
+func TestModerationSuite(t *testing.T) {
+	suite.Run(t, new(ModerationSuite))
+}
+
+func (s *ModerationSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.reportRepo = &mModeration.ReportRepo{}
+	s.auditLogRepo = &mModeration.AuditLogRepo{}
+	s.accountUC = &mAccount.Usecase{}
+	s.collectionUC = &mCollection.Usecase{}
+	s.tokenUC = &mToken.Usecase{}
+	s.im = New(&UseCaseCfg{
+		ReportRepo:   s.reportRepo,
+		AuditLogRepo: s.auditLogRepo,
+		AccountUC:    s.accountUC,
+		CollectionUC: s.collectionUC,
+		TokenUC:      s.tokenUC,
+	})
+}
+
+func (s *ModerationSuite) TearDownTest() {
+	s.reportRepo.AssertExpectations(s.T())
+	s.auditLogRepo.AssertExpectations(s.T())
+	s.accountUC.AssertExpectations(s.T())
+	s.collectionUC.AssertExpectations(s.T())
+	s.tokenUC.AssertExpectations(s.T())
+}
+
+func (s *ModerationSuite) mockFindReport(reports ...moderation.Report) {
+	s.reportRepo.On("FindAll", mock.Anything, mock.AnythingOfType("moderation.ReportFindAllOptionsFunc")).
+		Return(reports, nil).Once()
+}
+
+func (s *ModerationSuite) mockUpdate(status moderation.ReportStatus) {
+	s.reportRepo.On("Update", mock.Anything,
+		mock.MatchedBy(func(u moderation.ReportUpdater) bool {
+			return *u.Status == status && *u.ResolvedBy == "0xmod"
+		}),
+		mock.AnythingOfType("moderation.ReportFindAllOptionsFunc"),
+		mock.AnythingOfType("moderation.ReportFindAllOptionsFunc")).
+		Return(nil).Once()
+}
+
+func (s *ModerationSuite) mockAuditLog(action moderation.AuditAction, target moderation.Target, reason string, reportId string) {
+	s.auditLogRepo.On("Insert", mock.Anything, mock.MatchedBy(func(l *moderation.AuditLog) bool {
+		return l.Action == action && l.Actor == "0xmod" && l.Target == target && l.Reason == reason && l.ReportId == reportId
+	})).Return(nil).Once()
+}
+
+func (s *ModerationSuite) TestReport() {
+	target := moderation.Target{Type: moderation.TargetTypeAccount, Address: "0xABC", ChainId: 1}
+	params := moderation.ReportParams{Target: target, Category: moderation.CategoryScam}
+
+	s.reportRepo.On("Insert", mock.Anything, mock.AnythingOfType("*moderation.Report")).Return(nil).Once()
+
+	report, err := s.im.Report(s.ctx, "0xReporter", params)
+	s.Nil(err)
+	s.Equal(moderation.ReportStatusOpen, report.Status)
+	s.Equal(moderation.Target{Type: moderation.TargetTypeAccount, Address: "0xabc"}, report.Target)
+	s.Equal(domain.Address("0xreporter"), report.Reporter)
+
+	// the open report of the reporter conflicts
+	s.reportRepo.On("Insert", mock.Anything, mock.AnythingOfType("*moderation.Report")).Return(moderation.ErrReportExists).Once()
+	_, err = s.im.Report(s.ctx, "0xreporter", params)
+	s.Equal(moderation.ErrReportExists, err)
+
+	_, err = s.im.Report(s.ctx, "0xreporter", moderation.ReportParams{Target: target, Category: "boring"})
+	s.Equal(moderation.ErrInvalidCategory, err)
+
+	_, err = s.im.Report(s.ctx, "0xreporter", moderation.ReportParams{Target: moderation.Target{Type: moderation.TargetTypeToken}, Category: moderation.CategorySpam})
+	s.Equal(moderation.ErrInvalidTarget, err)
+}
+
+func (s *ModerationSuite) TestTriageActioned() {
+	target := moderation.Target{Type: moderation.TargetTypeToken, ChainId: 1, ContractAddress: "0xc", TokenId: "1"}
+	r1 := moderation.Report{Id: "r1", Target: target, Reporter: "0x1", Category: moderation.CategoryCopyright, Status: moderation.ReportStatusOpen}
+
+	s.mockFindReport(r1)
+	s.tokenUC.On("BanNftItem", mock.Anything, nftitem.Id{ChainId: 1, ContractAddress: "0xc", TokenId: "1"}).Return(nil).Once()
+	s.mockAuditLog(moderation.AuditActionBan, target, "copyright: stolen art", r1.Id)
+	// other open reports of the target are actioned as well
+	s.mockUpdate(moderation.ReportStatusActioned)
+
+	res, err := s.im.Triage(s.ctx, "0xMod", r1.Id, moderation.TriageParams{Status: moderation.ReportStatusActioned, Ban: true, Resolution: "stolen art"})
+	s.Nil(err)
+	s.Equal(moderation.ReportStatusActioned, res.Status)
+	s.Equal(domain.Address("0xmod"), *res.ResolvedBy)
+	s.Equal("stolen art", res.Resolution)
+
+	r1.Status = moderation.ReportStatusActioned
+	s.mockFindReport(r1)
+	_, err = s.im.Triage(s.ctx, "0xmod", r1.Id, moderation.TriageParams{Status: moderation.ReportStatusDismissed})
+	s.Equal(moderation.ErrReportResolved, err)
+}
+
+func (s *ModerationSuite) TestTriageDismissed() {
+	target := moderation.Target{Type: moderation.TargetTypeCollection, ChainId: 1, ContractAddress: "0xc"}
+	r1 := moderation.Report{Id: "r1", Target: target, Reporter: "0x1", Category: moderation.CategorySpam, Status: moderation.ReportStatusOpen}
+
+	_, err := s.im.Triage(s.ctx, "0xmod", r1.Id, moderation.TriageParams{Status: moderation.ReportStatusOpen})
+	s.Equal(domain.ErrBadParamInput, err)
+
+	// a dismissed report bans nothing
+	s.mockFindReport(r1)
+	s.mockUpdate(moderation.ReportStatusDismissed)
+	_, err = s.im.Triage(s.ctx, "0xmod", r1.Id, moderation.TriageParams{Status: moderation.ReportStatusDismissed, Ban: true})
+	s.Nil(err)
+
+	s.mockFindReport()
+	_, err = s.im.Triage(s.ctx, "0xmod", "missing", moderation.TriageParams{Status: moderation.ReportStatusDismissed})
+	s.Equal(domain.ErrNotFound, err)
+}
+
+func (s *ModerationSuite) TestBanUnban() {
+	target := moderation.Target{Type: moderation.TargetTypeAccount, Address: "0xA"}
+	normalized := moderation.Target{Type: moderation.TargetTypeAccount, Address: "0xa"}
+
+	s.accountUC.On("Ban", mock.Anything, domain.Address("0xa")).Return(nil).Once()
+	s.mockAuditLog(moderation.AuditActionBan, normalized, "spam bot", "")
+	s.Nil(s.im.Ban(s.ctx, "0xmod", target, "spam bot"))
+
+	s.accountUC.On("Unban", mock.Anything, domain.Address("0xa")).Return(nil).Once()
+	s.mockAuditLog(moderation.AuditActionUnban, normalized, "appealed", "")
+	s.Nil(s.im.Unban(s.ctx, "0xmod", target, "appealed"))
+
+	collectionTarget := moderation.Target{Type: moderation.TargetTypeCollection, ChainId: 1, ContractAddress: "0xc"}
+	s.collectionUC.On("Ban", mock.Anything, collection.CollectionId{ChainId: 1, Address: "0xc"}, true).Return(nil, nil).Once()
+	s.mockAuditLog(moderation.AuditActionBan, collectionTarget, "scam", "")
+	s.Nil(s.im.Ban(s.ctx, "0xmod", collectionTarget, "scam"))
+
+	// nothing is audited if the target is invalid
+	s.Equal(moderation.ErrInvalidTarget, s.im.Ban(s.ctx, "0xmod", moderation.Target{Type: "unknown"}, ""))
+}
@@ -2,14 +2,27 @@ package usecase
 
 import (
 	"fmt"
-	"strings"
+	"time"
 
 	bctx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/erc1155"
 	erc721 "github.com/x-xyz/goapi/domain/erc721/contract"
 	"github.com/x-xyz/goapi/domain/nftdetector"
+	"github.com/x-xyz/goapi/domain/spam"
+	"github.com/x-xyz/goapi/service/cache/provider"
+	"github.com/x-xyz/goapi/service/cache/provider/primitive"
 	chainservice "github.com/x-xyz/goapi/service/chain/contract"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+const (
+	// seenCacheSize is the size in MB of the cache of detected contracts
+	seenCacheSize = 16
+	// detected contracts are skipped for seenTTL, their records are checked again afterwards
+	seenTTL = 24 * time.Hour
 )
 
 type NFTDetectorCfg struct {
@@ -17,28 +30,41 @@ type NFTDetectorCfg struct {
 	Erc721Service  chainservice.Erc721Contract
 	Erc1155        erc1155.Repo
 	Erc1155Service chainservice.Erc1155Contract
+	// MetadataService and Registration onboard new contracts as unreviewed registrations,
+	// contracts are only stored for later review if Registration is nil
+	MetadataService chainservice.NftMetadataContract
+	Registration    collection.RegistrationRepo
+	// Spam scores discovered contracts, they are left unscored until the next classification if it's nil
+	Spam spam.UseCase
 }
 
 type nftDetectorUseCase struct {
-	seen           map[string]struct{}
+	seen           provider.Provider
 	erc721         erc721.Repo
 	erc721Service  chainservice.Erc721Contract
 	erc1155        erc1155.Repo
 	erc1155Service chainservice.Erc1155Contract
+	metadata       chainservice.NftMetadataContract
+	registration   collection.RegistrationRepo
+	spam           spam.UseCase
 }
 
 func NewNFTDetectorUseCase(cfg *NFTDetectorCfg) nftdetector.UseCase {
 	return &nftDetectorUseCase{
-		seen:           map[string]struct{}{}, // NOTE: not goroutine safe
+		seen:           primitive.NewPrimitive("nftdetector", seenCacheSize),
 		erc721:         cfg.Erc721,
 		erc721Service:  cfg.Erc721Service,
 		erc1155:        cfg.Erc1155,
 		erc1155Service: cfg.Erc1155Service,
+		metadata:       cfg.MetadataService,
+		registration:   cfg.Registration,
+		spam:           cfg.Spam,
 	}
 }
 
 func (n *nftDetectorUseCase) DetectNFT(ctx bctx.Ctx, chainId domain.ChainId, address domain.Address, nftType nftdetector.NFTType) error {
-	if _, ok := n.seen[address.ToLowerStr()]; ok {
+	key := fmt.Sprintf("%d:%s", chainId, address.ToLowerStr())
+	if _, _, err := n.seen.Get(ctx, key); err == nil {
 		return nil
 	}
 
@@ -51,7 +77,9 @@ func (n *nftDetectorUseCase) DetectNFT(ctx bctx.Ctx, chainId domain.ChainId, add
 	}
 
 	if err == nil {
-		n.seen[address.ToLowerStr()] = struct{}{}
+		if err := n.seen.Set(ctx, key, []byte{1}, seenTTL); err != nil {
+			ctx.WithField("err", err).Warn("seen.Set failed")
+		}
 	}
 	return err
 }
@@ -75,7 +103,7 @@ func (n *nftDetectorUseCase) detectErc721(ctx bctx.Ctx, chainId domain.ChainId,
 	// check erc721
 	is721, err := n.erc721Service.Supports721Interface(ctx, int32(chainId), address.ToLowerStr())
 	if err != nil {
-		if n.isKnownEVMError(err) {
+		if chainservice.IsReverted(err) {
 			return nil
 		}
 		ctx.Error(fmt.Sprintf("detect erc721 = %s err=%s", address, err.Error()))
@@ -86,9 +114,17 @@ func (n *nftDetectorUseCase) detectErc721(ctx bctx.Ctx, chainId domain.ChainId,
 		return nil
 	}
 
+	// onboard before storing the contract, so that failed onboarding is retried on the next transfer
+	name, symbol := n.getMetadata(ctx, chainId, address)
+	if err := n.onboard(ctx, chainId, address, domain.TokenType721, name, symbol); err != nil {
+		return err
+	}
+
 	return n.erc721.Create(ctx, erc721.Contract{
 		ChainId: chainId,
 		Address: address.ToLower(),
+		Name:    name,
+		Symbol:  symbol,
 	})
 }
 
@@ -112,7 +148,7 @@ func (n *nftDetectorUseCase) detectErc1155(ctx bctx.Ctx, chainId domain.ChainId,
 	// check erc721
 	is1155, err := n.erc1155Service.Supports1155Interface(ctx, int32(chainId), address.ToLowerStr())
 	if err != nil {
-		if n.isKnownEVMError(err) {
+		if chainservice.IsReverted(err) {
 			return nil
 		}
 		ctx.Error(fmt.Sprintf("detect erc1155 = %s err=%s", address, err.Error()))
@@ -123,24 +159,87 @@ func (n *nftDetectorUseCase) detectErc1155(ctx bctx.Ctx, chainId domain.ChainId,
 		return nil
 	}
 
+	name, symbol := n.getMetadata(ctx, chainId, address)
+	if err := n.onboard(ctx, chainId, address, domain.TokenType1155, name, symbol); err != nil {
+		return err
+	}
+
 	return n.erc1155.Create(ctx, erc1155.Contract{
 		ChainId: chainId,
 		Address: address.ToLower(),
+		Name:    name,
+		Symbol:  symbol,
 	})
 }
 
-func (n *nftDetectorUseCase) isKnownEVMError(err error) bool {
-	if strings.Contains(err.Error(), "execution reverted") {
-		return true
+// getMetadata returns name and symbol of the contract, they are empty if the contract doesn't implement them
+func (n *nftDetectorUseCase) getMetadata(ctx bctx.Ctx, chainId domain.ChainId, address domain.Address) (string, string) {
+	if n.metadata == nil {
+		return "", ""
+	}
+	name, err := n.metadata.Name(ctx, int32(chainId), address.ToLowerStr())
+	if err != nil && !chainservice.IsReverted(err) {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Warn("metadata.Name failed")
+	}
+	symbol, err := n.metadata.Symbol(ctx, int32(chainId), address.ToLowerStr())
+	if err != nil && !chainservice.IsReverted(err) {
+		ctx.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Warn("metadata.Symbol failed")
+	}
+	return name, symbol
+}
+
+// onboard creates a pending registration for moderators to review,
+// the contract is tracked once the registration is accepted
+func (n *nftDetectorUseCase) onboard(ctx bctx.Ctx, chainId domain.ChainId, address domain.Address, tokenType domain.TokenType, name, symbol string) error {
+	if n.registration == nil {
+		return nil
 	}
-	if strings.Contains(err.Error(), "abi: attempting to unmarshall an empty string while arguments are expected") {
-		return true
+
+	id := collection.CollectionId{ChainId: chainId, Address: address.ToLower()}
+	if _, err := n.registration.FindOne(ctx, id); err == nil {
+		return nil
+	} else if !query.IsNotFound(err) {
+		return err
 	}
-	if strings.Contains(err.Error(), "invalid opcode: INVALID") {
-		return true
+
+	reg := collection.Registration{
+		ChainId:        chainId,
+		Erc721Address:  address.ToLower(),
+		TokenType:      tokenType,
+		CollectionName: name,
+		Symbol:         symbol,
+		State:          collection.RegistrationStatePending,
+		Discovered:     true,
+	}
+	if err := n.registration.Create(ctx, reg); err != nil {
+		ctx.WithFields(log.Fields{
+			"err":          err,
+			"registration": reg,
+		}).Error("registration.Create failed")
+		return err
 	}
-	if strings.Contains(err.Error(), "invalid jump destination") {
-		return true
+
+	logger := ctx.WithFields(log.Fields{
+		"chainId": chainId,
+		"address": address,
+		"name":    name,
+	})
+	if n.spam == nil {
+		logger.Info("discovered new contract")
+		return nil
+	}
+	// a failed classification is retried by the spam classifier
+	res, err := n.spam.Refresh(ctx, chainId, address)
+	if err != nil {
+		logger.WithField("err", err).Warn("spam.Refresh failed")
+		return nil
 	}
-	return false
+	logger.WithField("spamScore", res.Score).Info("discovered new contract")
+	return nil
 }
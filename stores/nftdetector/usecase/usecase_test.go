@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	bctx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	erc721 "github.com/x-xyz/goapi/domain/erc721/contract"
+	mErc721 "github.com/x-xyz/goapi/domain/erc721/contract/mocks"
+	"github.com/x-xyz/goapi/domain/nftdetector"
+	"github.com/x-xyz/goapi/domain/spam"
+	mSpam "github.com/x-xyz/goapi/domain/spam/mocks"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+func TestDetectNFTOnboardsNewContract(t *testing.T) {
+	req := require.New(t)
+	address := domain.Address("0x3333333333333333333333333333333333333333")
+
+	erc721Repo := &mErc721.Repo{}
+	erc721Repo.On("FindOne", mock.Anything,
+		mock.AnythingOfType("contract.FindOptions"),
+		mock.AnythingOfType("contract.FindOptions")).
+		Return(nil, domain.ErrNotFound).Once()
+	var created erc721.Contract
+	erc721Repo.On("Create", mock.Anything, mock.AnythingOfType("contract.Contract")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(erc721.Contract) }).
+		Return(nil).Once()
+	erc721Service := &mContract.Erc721Contract{}
+	erc721Service.On("Supports721Interface", mock.Anything, int32(1), address.ToLowerStr()).Return(true, nil).Once()
+	metadata := &mContract.NftMetadataContract{}
+	metadata.On("Name", mock.Anything, int32(1), address.ToLowerStr()).Return("Cool Cats", nil).Once()
+	metadata.On("Symbol", mock.Anything, int32(1), address.ToLowerStr()).Return("COOL", nil).Once()
+	registrationRepo := &mCollection.RegistrationRepo{}
+	registrationRepo.On("FindOne", mock.Anything, collection.CollectionId{ChainId: 1, Address: address}).Return(nil, query.ErrNotFound).Once()
+	var reg collection.Registration
+	registrationRepo.On("Create", mock.Anything, mock.AnythingOfType("collection.Registration")).
+		Run(func(args mock.Arguments) { reg = args.Get(1).(collection.Registration) }).
+		Return(nil).Once()
+	// the registration is scored after it's created
+	spamUC := &mSpam.UseCase{}
+	spamUC.On("Refresh", mock.Anything, domain.ChainId(1), address).
+		Return(&spam.Result{Score: 0.5, Signals: []spam.Signal{spam.SignalBaitName}}, nil).Once()
+	uc := NewNFTDetectorUseCase(&NFTDetectorCfg{
+		Erc721:          erc721Repo,
+		Erc721Service:   erc721Service,
+		MetadataService: metadata,
+		Registration:    registrationRepo,
+		Spam:            spamUC,
+	})
+
+	req.NoError(uc.DetectNFT(bctx.Background(), 1, address, nftdetector.Erc721Type))
+	req.Equal("Cool Cats", created.Name)
+	req.False(created.IsAppropriate)
+	req.True(reg.Discovered)
+	req.Equal(collection.RegistrationStatePending, reg.State)
+	req.Equal(domain.TokenType721, reg.TokenType)
+	req.Equal("COOL", reg.Symbol)
+
+	// seen contracts are skipped
+	req.NoError(uc.DetectNFT(bctx.Background(), 1, address, nftdetector.Erc721Type))
+
+	erc721Repo.AssertExpectations(t)
+	erc721Service.AssertExpectations(t)
+	metadata.AssertExpectations(t)
+	registrationRepo.AssertExpectations(t)
+	spamUC.AssertExpectations(t)
+}
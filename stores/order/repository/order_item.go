@@ -40,6 +40,18 @@ func (im *impl) makeQuery(options order.OrderItemFindAllOptions) (bson.M, error)
 		query["tokenID"] = options.NftitemId.TokenId
 	}
 
+	if options.NftitemIds != nil {
+		orExprs := bson.A{}
+		for _, id := range *options.NftitemIds {
+			orExprs = append(orExprs, bson.M{
+				"chainId":    id.ChainId,
+				"collection": id.ContractAddress.ToLower(),
+				"tokenID":    id.TokenId,
+			})
+		}
+		query["$or"] = orExprs
+	}
+
 	if options.IsValid != nil {
 		query["isValid"] = *options.IsValid
 	}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+)
+
+func (im *impl) QuoteFees(ctx ctx.Ctx, draft order.Order) (*order.FeeQuote, error) {
+	draft.LowerCase()
+
+	exchangeCfg, ok := im.exchangeCfgs[draft.ChainId]
+	if !ok {
+		return nil, domain.ErrInvalidChainId
+	}
+	if exchangeCfg.Fee.Unset {
+		return nil, order.ErrFeeNotConfigured
+	}
+	if len(draft.Items) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if _, err := im.paytokenRepo.FindOne(ctx, draft.ChainId, draft.Currency); err != nil {
+		return nil, domain.ErrInvalidCurrency
+	}
+	minPercentageToAsk := int64(0)
+	if draft.MinPercentageToAsk != "" {
+		v, err := strconv.ParseInt(draft.MinPercentageToAsk, 10, 64)
+		if err != nil || v < 0 || v > order.BpsBase {
+			return nil, domain.ErrInvalidNumberFormat
+		}
+		minPercentageToAsk = v
+	}
+
+	res := &order.FeeQuote{
+		ChainId:  draft.ChainId,
+		IsAsk:    draft.IsAsk,
+		Strategy: order.StrategyUnknown,
+		Currency: draft.Currency,
+		Items:    []order.ItemQuote{},
+		Warnings: []order.QuoteWarning{},
+	}
+	if strategy, ok := exchangeCfg.Strategies[draft.Strategy]; !ok {
+		res.Warnings = append(res.Warnings, order.QuoteWarningUnsupportedStrategy)
+	} else {
+		res.Strategy = strategy
+		if !strategy.IsSideAllowed(draft.IsAsk) {
+			res.Warnings = append(res.Warnings, order.QuoteWarningStrategyForbidsSide)
+		}
+	}
+
+	for idx, item := range draft.Items {
+		price, ok := new(big.Int).SetString(item.Price, 10)
+		if !ok || price.Sign() <= 0 {
+			return nil, domain.ErrInvalidNumberFormat
+		}
+
+		quote := order.ItemQuote{
+			ItemIdx:    idx,
+			Collection: item.Collection,
+			TokenId:    item.TokenId,
+			Price:      price.String(),
+			Fees:       []order.FeeLine{},
+			Warnings:   []order.QuoteWarning{},
+		}
+
+		fees := im.protocolFees(exchangeCfg.Fee, order.ToFeeDistType(draft.FeeDistType), price)
+		royaltyFees, err := im.royaltyFees(ctx, draft.ChainId, item, price)
+		if err != nil {
+			quote.Warnings = append(quote.Warnings, order.QuoteWarningRoyaltyUnavailable)
+		}
+		fees = append(fees, royaltyFees...)
+
+		net := new(big.Int).Set(price)
+		for _, fee := range fees {
+			amount, _ := new(big.Int).SetString(fee.Amount, 10)
+			net.Sub(net, amount)
+			if fee.DisplayAmount, fee.AmountInUsd, err = im.formatAmount(ctx, draft.ChainId, draft.Currency, amount); err != nil {
+				return nil, err
+			}
+			quote.Fees = append(quote.Fees, fee)
+		}
+		if net.Sign() < 0 {
+			net.SetInt64(0)
+		}
+		quote.NetProceeds = net.String()
+		if quote.DisplayNetProceeds, quote.NetProceedsInUsd, err = im.formatAmount(ctx, draft.ChainId, draft.Currency, net); err != nil {
+			return nil, err
+		}
+
+		// the exchange reverts if the seller receives less than minPercentageToAsk of the price
+		minNet := new(big.Int).Mul(price, big.NewInt(minPercentageToAsk))
+		if new(big.Int).Mul(net, big.NewInt(order.BpsBase)).Cmp(minNet) < 0 {
+			quote.Warnings = append(quote.Warnings, order.QuoteWarningBelowMinPercentageToAsk)
+		}
+
+		res.Items = append(res.Items, quote)
+	}
+
+	return res, nil
+}
+
+// protocolFees splits the protocol fee into the share burnt or donated and the rest to the fee recipient
+func (im *impl) protocolFees(cfg order.FeeCfg, distType order.FeeDistType, price *big.Int) []order.FeeLine {
+	fees := []order.FeeLine{}
+	protocolFee := bps(price, cfg.ProtocolFeeBps)
+	if protocolFee.Sign() <= 0 {
+		return fees
+	}
+
+	distFee := bps(protocolFee, cfg.FeeDistShareBps)
+	if distFee.Sign() > 0 {
+		line := order.FeeLine{Type: order.FeeTypeBurn, Recipient: order.BurnAddress, Amount: distFee.String()}
+		if distType == order.FeeDistTypeDonate {
+			line = order.FeeLine{Type: order.FeeTypeDonate, Recipient: cfg.DonationRecipient, Amount: distFee.String()}
+		}
+		fees = append(fees, line)
+	}
+
+	if rest := new(big.Int).Sub(protocolFee, distFee); rest.Sign() > 0 {
+		fees = append(fees, order.FeeLine{Type: order.FeeTypeProtocol, Recipient: cfg.Recipient, Amount: rest.String()})
+	}
+	return fees
+}
+
+// royaltyFees returns royalty of the item, collection offers have no token id and royalty isn't resolvable
+func (im *impl) royaltyFees(ctx ctx.Ctx, chainId domain.ChainId, item order.Item, price *big.Int) ([]order.FeeLine, error) {
+	fees := []order.FeeLine{}
+	if im.royalty == nil || item.TokenId == "" {
+		return fees, domain.ErrNotFound
+	}
+
+	id := nftitem.Id{ChainId: chainId, ContractAddress: item.Collection, TokenId: item.TokenId}
+	quote, err := im.royalty.Quote(ctx, id, price)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Warn("royalty.Quote failed")
+		return fees, err
+	}
+	for _, payout := range quote.Recipients {
+		fees = append(fees, order.FeeLine{Type: order.FeeTypeRoyalty, Recipient: payout.Address, Amount: payout.Amount})
+	}
+	return fees, nil
+}
+
+func (im *impl) formatAmount(ctx ctx.Ctx, chainId domain.ChainId, currency domain.Address, amount *big.Int) (string, float64, error) {
+	displayAmount, amountInUsd, _, err := im.priceFormatter.GetPrices(ctx, chainId, currency, amount)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"chainId":  chainId,
+			"currency": currency,
+			"amount":   amount,
+			"err":      err,
+		}).Error("failed to priceFormatter.GetPrices")
+		return "", 0, err
+	}
+	return displayAmount.String(), amountInUsd, nil
+}
+
+func bps(value *big.Int, bps int64) *big.Int {
+	res := new(big.Int).Mul(value, big.NewInt(bps))
+	return res.Div(res, big.NewInt(order.BpsBase))
+}
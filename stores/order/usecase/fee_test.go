@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/base/ctx"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter/mocks"
+	"github.com/x-xyz/goapi/domain"
+	mDomain "github.com/x-xyz/goapi/domain/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/royalty"
+	mRoyalty "github.com/x-xyz/goapi/domain/royalty/mocks"
+)
+
+func TestQuoteFees(t *testing.T) {
+	req := require.New(t)
+	_ctx := ctx.Background()
+	chainId := domain.ChainId(1)
+	weth := domain.Address("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	fixedPrice := domain.Address("0xa7ca695b37854181f09c1c39a0cdcffc8db7a667")
+	collectionOffer := domain.Address("0x2e9e733cb0394aace1226e34313f12b0764be65a")
+	feeRecipient := domain.Address("0x1111111111111111111111111111111111111111")
+	royaltyRecipient := domain.Address("0x2222222222222222222222222222222222222222")
+
+	paytokenRepo := &mDomain.PayTokenRepo{}
+	paytokenRepo.On("FindOne", mock.Anything, chainId, weth).Return(&domain.PayToken{}, nil)
+	priceFormatter := &pricefomatter.PriceFormatter{}
+	priceFormatter.On("GetPrices", mock.Anything, chainId, weth, mock.Anything).Return(decimal.Zero, float64(0), float64(0), nil)
+	royaltyUC := &mRoyalty.UseCase{}
+	royaltyUC.On("Quote", mock.Anything, nftitem.Id{ChainId: chainId, ContractAddress: "0x3333333333333333333333333333333333333333", TokenId: "1"}, big.NewInt(10000)).
+		Return(&royalty.Quote{
+			Source:     royalty.SourceErc2981,
+			Price:      "10000",
+			Recipients: []royalty.Payout{{Address: royaltyRecipient, Amount: "500"}},
+		}, nil).Twice()
+
+	im := &impl{
+		exchangeCfgs: map[domain.ChainId]order.ExchangeCfg{
+			chainId: {
+				Strategies: map[domain.Address]order.Strategy{
+					fixedPrice:      order.StrategyFixedPrice,
+					collectionOffer: order.StrategyCollectionOffer,
+				},
+				Fee: order.FeeCfg{ProtocolFeeBps: 200, FeeDistShareBps: 5000, Recipient: feeRecipient},
+			},
+		},
+		paytokenRepo:   paytokenRepo,
+		priceFormatter: priceFormatter,
+		royalty:        royaltyUC,
+	}
+
+	draft := order.Order{
+		ChainId:            chainId,
+		IsAsk:              true,
+		Strategy:           fixedPrice,
+		Currency:           weth,
+		MinPercentageToAsk: "9000",
+		Items:              []order.Item{{Collection: "0x3333333333333333333333333333333333333333", TokenId: "1", Amount: "1", Price: "10000"}},
+	}
+
+	quote, err := im.QuoteFees(_ctx, draft)
+	req.NoError(err)
+	req.Equal(order.StrategyFixedPrice, quote.Strategy)
+	req.Empty(quote.Warnings)
+	req.Len(quote.Items, 1)
+	req.Equal([]order.FeeLine{
+		{Type: order.FeeTypeBurn, Recipient: order.BurnAddress, Amount: "100", DisplayAmount: "0"},
+		{Type: order.FeeTypeProtocol, Recipient: feeRecipient, Amount: "100", DisplayAmount: "0"},
+		{Type: order.FeeTypeRoyalty, Recipient: royaltyRecipient, Amount: "500", DisplayAmount: "0"},
+	}, quote.Items[0].Fees)
+	req.Equal("9300", quote.Items[0].NetProceeds)
+	req.Empty(quote.Items[0].Warnings)
+
+	// net proceeds below minPercentageToAsk
+	draft.MinPercentageToAsk = "9500"
+	quote, err = im.QuoteFees(_ctx, draft)
+	req.NoError(err)
+	req.Equal([]order.QuoteWarning{order.QuoteWarningBelowMinPercentageToAsk}, quote.Items[0].Warnings)
+
+	// collection offers are bids only and have no royalty without token id
+	draft.MinPercentageToAsk = ""
+	draft.Strategy = collectionOffer
+	draft.Items[0].TokenId = ""
+	quote, err = im.QuoteFees(_ctx, draft)
+	req.NoError(err)
+	req.Equal([]order.QuoteWarning{order.QuoteWarningStrategyForbidsSide}, quote.Warnings)
+	req.Equal("9800", quote.Items[0].NetProceeds)
+	req.Equal([]order.QuoteWarning{order.QuoteWarningRoyaltyUnavailable}, quote.Items[0].Warnings)
+
+	// invalid input
+	_, err = im.QuoteFees(_ctx, order.Order{ChainId: 5})
+	req.ErrorIs(err, domain.ErrInvalidChainId)
+	draft.Items[0].Price = "0"
+	_, err = im.QuoteFees(_ctx, draft)
+	req.ErrorIs(err, domain.ErrInvalidNumberFormat)
+
+	// quotes are disabled if the protocol fee isn't configured
+	cfg := im.exchangeCfgs[chainId]
+	cfg.Fee = order.FeeCfg{Unset: true}
+	im.exchangeCfgs[chainId] = cfg
+	_, err = im.QuoteFees(_ctx, draft)
+	req.ErrorIs(err, order.ErrFeeNotConfigured)
+
+	royaltyUC.AssertExpectations(t)
+}
@@ -18,9 +18,13 @@ import (
 	"github.com/x-xyz/goapi/base/ptr"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/alert"
+	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/erc1155"
+	"github.com/x-xyz/goapi/domain/external_listing"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/royalty"
 	"github.com/x-xyz/goapi/domain/token"
 	"github.com/x-xyz/goapi/service/chain/contract"
 )
@@ -38,6 +42,9 @@ type OrderUseCaseCfg struct {
 	TokenUC             token.Usecase
 	Erc1271             contract.Erc1271Contract
 	ActivityHistoryRepo account.ActivityHistoryRepo
+	RoyaltyUC           royalty.UseCase
+	ExternalListingRepo external_listing.ExternalListingRepo
+	AlertUC             alert.UseCase
 }
 
 type impl struct {
@@ -53,6 +60,9 @@ type impl struct {
 	tokenUC             token.Usecase
 	erc1271             contract.Erc1271Contract
 	activityHistoryRepo account.ActivityHistoryRepo
+	royalty             royalty.UseCase
+	externalListingRepo external_listing.ExternalListingRepo
+	alert               alert.UseCase
 }
 
 func New(cfg *OrderUseCaseCfg) order.UseCase {
@@ -69,6 +79,9 @@ func New(cfg *OrderUseCaseCfg) order.UseCase {
 		tokenUC:             cfg.TokenUC,
 		erc1271:             cfg.Erc1271,
 		activityHistoryRepo: cfg.ActivityHistoryRepo,
+		royalty:             cfg.RoyaltyUC,
+		externalListingRepo: cfg.ExternalListingRepo,
+		alert:               cfg.AlertUC,
 	}
 }
 
@@ -93,6 +106,20 @@ func (im *impl) GetOrder(ctx ctx.Ctx, id order.OrderId) (*order.Order, error) {
 		return nil, err
 	}
 
+	// items are served without royalty if it's not resolvable, e.g. collection offers
+	if im.royalty != nil {
+		for i, item := range order.Items {
+			price, ok := new(big.Int).SetString(item.Price, 10)
+			if !ok {
+				continue
+			}
+			nftitemId := nftitem.Id{ChainId: order.ChainId, ContractAddress: item.Collection, TokenId: item.TokenId}
+			if quote, err := im.royalty.Quote(ctx, nftitemId, price); err == nil {
+				order.Items[i].Royalty = quote
+			}
+		}
+	}
+
 	return order, nil
 }
 
@@ -142,9 +169,46 @@ func (im *impl) MakeOrder(ctx ctx.Ctx, od order.Order) error {
 		im.removeRelatedOrders(ctx, od)
 		return err
 	}
+	im.evaluateAlerts(ctx, od)
 	return nil
 }
 
+// evaluateAlerts is best effort, a failed evaluation is retried by the tracker's PriceUpdater
+func (im *impl) evaluateAlerts(ctx ctx.Ctx, od order.Order) {
+	if im.alert == nil {
+		return
+	}
+
+	// a collection offer is an offer of every token in the collection
+	isCollectionOffer := im.exchangeCfgs[od.ChainId].Strategies[od.Strategy] == order.StrategyCollectionOffer
+	evaluated := map[nftitem.Id]bool{}
+	for _, item := range od.Items {
+		id := nftitem.Id{ChainId: od.ChainId, ContractAddress: item.Collection.ToLower(), TokenId: item.TokenId}
+		if isCollectionOffer {
+			id.TokenId = ""
+		}
+		if evaluated[id] {
+			continue
+		}
+		evaluated[id] = true
+
+		if isCollectionOffer {
+			collectionId := collection.CollectionId{ChainId: id.ChainId, Address: id.ContractAddress}
+			if err := im.alert.EvaluateCollection(ctx, collectionId); err != nil {
+				ctx.WithFields(log.Fields{
+					"id":  collectionId,
+					"err": err,
+				}).Warn("alert.EvaluateCollection failed")
+			}
+		} else if err := im.alert.EvaluateToken(ctx, id); err != nil {
+			ctx.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Warn("alert.EvaluateToken failed")
+		}
+	}
+}
+
 func (im *impl) removeRelatedOrders(ctx ctx.Ctx, od order.Order) {
 	if err := im.orderItemRepo.RemoveAll(ctx, order.WithChainId(od.ChainId), order.WithOrderHash(od.OrderHash)); err != nil {
 		ctx.WithFields(log.Fields{
@@ -16,6 +16,8 @@ import (
 	"github.com/x-xyz/goapi/base/ptr"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
+	mAlert "github.com/x-xyz/goapi/domain/alert/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
 	mErc1155 "github.com/x-xyz/goapi/domain/erc1155/mocks"
 	mDomain "github.com/x-xyz/goapi/domain/mocks"
 	"github.com/x-xyz/goapi/domain/nftitem"
@@ -420,6 +422,49 @@ func TestHashOrderItem(t *testing.T) {
 	}
 }
 
+func TestEvaluateAlerts(t *testing.T) {
+	chainId := domain.ChainId(1)
+	fixedPrice := domain.Address("0x1111111111111111111111111111111111111111")
+	collectionOffer := domain.Address("0x2222222222222222222222222222222222222222")
+	contract := domain.Address("0x3333333333333333333333333333333333333333")
+
+	alertUC := &mAlert.UseCase{}
+	im := &impl{
+		exchangeCfgs: map[domain.ChainId]order.ExchangeCfg{
+			chainId: {
+				Strategies: map[domain.Address]order.Strategy{
+					fixedPrice:      order.StrategyFixedPrice,
+					collectionOffer: order.StrategyCollectionOffer,
+				},
+			},
+		},
+		alert: alertUC,
+	}
+
+	// each token is evaluated once, failures don't stop the others
+	alertUC.On("EvaluateToken", mock.Anything, nftitem.Id{ChainId: chainId, ContractAddress: contract, TokenId: "1"}).Return(domain.ErrNotFound).Once()
+	alertUC.On("EvaluateToken", mock.Anything, nftitem.Id{ChainId: chainId, ContractAddress: contract, TokenId: "2"}).Return(nil).Once()
+	im.evaluateAlerts(ctx.Background(), order.Order{
+		ChainId:  chainId,
+		Strategy: fixedPrice,
+		Items: []order.Item{
+			{Collection: contract, TokenId: "1"},
+			{Collection: contract, TokenId: "2"},
+			{Collection: contract, TokenId: "1"},
+		},
+	})
+
+	// collection offers are offers of every token of the collection
+	alertUC.On("EvaluateCollection", mock.Anything, collection.CollectionId{ChainId: chainId, Address: contract}).Return(nil).Once()
+	im.evaluateAlerts(ctx.Background(), order.Order{
+		ChainId:  chainId,
+		Strategy: collectionOffer,
+		Items:    []order.Item{{Collection: contract, TokenId: "0"}},
+	})
+
+	alertUC.AssertExpectations(t)
+}
+
 type testSuite struct {
 	suite.Suite
 
@@ -469,6 +514,9 @@ func (s *testSuite) SetupSuite() {
 		nil,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
 	}).(*impl)
 }
 
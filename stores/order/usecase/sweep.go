@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/order"
+)
+
+// maxSweepCandidates is the max number of native asks considered, and of external listings per page
+const maxSweepCandidates = 1000
+
+// maxExternalSweepPages bounds the pages of external listings filtered by attributes
+const maxExternalSweepPages = 5
+
+// sweepKey identifies a fillable item, an erc721 token is a single item whoever lists it while listings of an erc1155
+// token by different sellers are different items
+type sweepKey struct {
+	collection domain.Address
+	tokenId    domain.TokenId
+	seller     domain.Address
+}
+
+func toSweepKey(item order.SweepItem, isErc1155 bool) sweepKey {
+	key := sweepKey{collection: item.Collection.ToLower(), tokenId: item.TokenId}
+	if isErc1155 {
+		key.seller = item.Seller.ToLower()
+	}
+	return key
+}
+
+func (im *impl) QuoteSweep(ctx ctx.Ctx, params order.SweepParams) (*order.SweepQuote, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	params.Collection = params.Collection.ToLower()
+	params.Buyer = params.Buyer.ToLower()
+
+	budget := decimal.Zero
+	if params.Budget != "" {
+		budget, _ = decimal.NewFromString(params.Budget)
+	}
+
+	now := time.Now()
+	candidates, err := im.nativeSweepCandidates(ctx, params, now)
+	if err != nil {
+		return nil, err
+	}
+	externals, err := im.externalSweepCandidates(ctx, params, now)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, externals...)
+
+	isErc1155 := false
+	if len(candidates) > 0 {
+		if isErc1155, err = im.isErc1155Collection(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+	limit := params.Count
+	if limit == 0 {
+		// budget only sweeps are capped too
+		limit = order.MaxSweepCount
+	}
+
+	// native asks go first if prices are equal
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].PriceInNative < candidates[j].PriceInNative
+	})
+
+	res := &order.SweepQuote{
+		ChainId:    params.ChainId,
+		Collection: params.Collection,
+		Items:      []order.SweepItem{},
+	}
+	total := decimal.Zero
+	chosen := map[sweepKey]bool{}
+	// candidates are sorted by price, the first valid ask of an item is the cheapest
+	for _, candidate := range candidates {
+		if len(res.Items) >= limit {
+			break
+		}
+		if params.Buyer != "" && candidate.Seller == params.Buyer {
+			continue
+		}
+		key := toSweepKey(candidate, isErc1155)
+		if chosen[key] {
+			continue
+		}
+		price := decimal.NewFromFloat(candidate.PriceInNative)
+		// candidates are sorted by price, none of the rest fits the budget
+		if params.Budget != "" && total.Add(price).GreaterThan(budget) {
+			break
+		}
+		chosen[key] = true
+		total = total.Add(price)
+		res.TotalInUsd += candidate.PriceInUsd
+		res.Items = append(res.Items, candidate)
+	}
+	res.TotalInNative = total.String()
+
+	return res, nil
+}
+
+func (im *impl) nativeSweepCandidates(ctx ctx.Ctx, params order.SweepParams, now time.Time) ([]order.SweepItem, error) {
+	res := []order.SweepItem{}
+	opts := []order.OrderItemFindAllOptionsFunc{
+		order.WithChainId(params.ChainId),
+		order.WithContractAddress(params.Collection),
+		order.WithIsAsk(true),
+		order.WithIsValid(true),
+		order.WithIsUsed(false),
+		order.WithStrategy(order.StrategyFixedPrice),
+		order.WithStartTimeLT(now),
+		order.WithEndTimeGT(now),
+		order.WithSort("priceInNative"),
+		order.WithPagination(0, maxSweepCandidates),
+	}
+	// filter by traits before capping candidates, so matched asks aren't cut by cheaper asks not matched
+	if len(params.Attributes) > 0 {
+		ids, err := im.listedIdsByAttributes(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return res, nil
+		}
+		opts = append(opts, order.WithNftItemIds(ids))
+	}
+
+	orderItems, err := im.orderItemRepo.FindAll(ctx, opts...)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"params": params,
+			"err":    err,
+		}).Error("orderItemRepo.FindAll failed")
+		return nil, err
+	}
+
+	for _, orderItem := range orderItems {
+		price, ok := new(big.Int).SetString(orderItem.Price, 10)
+		if !ok {
+			continue
+		}
+		// stored prices in native may be outdated, the sweep is priced by the latest rates
+		displayPrice, priceInUsd, priceInNative, err := im.priceFormatter.GetPrices(ctx, params.ChainId, orderItem.Currency, price)
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"orderItemHash": orderItem.OrderItemHash,
+				"err":           err,
+			}).Warn("priceFormatter.GetPrices failed")
+			continue
+		}
+		res = append(res, order.SweepItem{
+			Source:        order.SweepSourceNative,
+			ChainId:       orderItem.ChainId,
+			Collection:    orderItem.Collection,
+			TokenId:       orderItem.TokenId,
+			Seller:        orderItem.Signer,
+			Currency:      orderItem.Currency,
+			DisplayPrice:  displayPrice.String(),
+			PriceInNative: priceInNative,
+			PriceInUsd:    priceInUsd,
+			OrderItem:     orderItem,
+		})
+	}
+	return res, nil
+}
+
+func (im *impl) externalSweepCandidates(ctx ctx.Ctx, params order.SweepParams, now time.Time) ([]order.SweepItem, error) {
+	res := []order.SweepItem{}
+	if im.externalListingRepo == nil {
+		return res, nil
+	}
+
+	opts := []external_listing.FindAllOptionsFunc{
+		external_listing.WithChainId(params.ChainId),
+		external_listing.WithContractAddress(params.Collection),
+		external_listing.WithActiveAt(now),
+		external_listing.WithSort("priceInNative", domain.SortDirAsc),
+	}
+	// listings not matching attributes are dropped after the query, so keep paging until enough are matched
+	for page := 0; page < maxExternalSweepPages; page++ {
+		listings, err := im.externalListingRepo.FindAll(ctx,
+			append(opts, external_listing.WithPagination(int32(page*maxSweepCandidates), maxSweepCandidates))...)
+		if err != nil {
+			ctx.WithFields(log.Fields{
+				"params": params,
+				"err":    err,
+			}).Error("externalListingRepo.FindAll failed")
+			return nil, err
+		}
+
+		items := []order.SweepItem{}
+		for i := range listings {
+			listing := listings[i]
+			// external prices are stored in the native token
+			priceInUsd, priceInNative, err := im.priceFormatter.GetPricesFromDisplayPriceString(ctx, params.ChainId, domain.EmptyAddress, listing.Price)
+			if err != nil {
+				ctx.WithFields(log.Fields{
+					"listing": listing,
+					"err":     err,
+				}).Warn("priceFormatter.GetPricesFromDisplayPriceString failed")
+				continue
+			}
+			items = append(items, order.SweepItem{
+				Source:          order.SweepSourceExternal,
+				ChainId:         listing.ChainId,
+				Collection:      listing.ContractAddress.ToLower(),
+				TokenId:         listing.TokenId,
+				Seller:          listing.Owner.ToLower(),
+				Currency:        listing.PaymentToken.ToLower(),
+				DisplayPrice:    listing.Price,
+				PriceInNative:   priceInNative,
+				PriceInUsd:      priceInUsd,
+				ExternalListing: &listing,
+			})
+		}
+
+		if len(params.Attributes) > 0 {
+			if items, err = im.filterSweepByAttributes(ctx, params, items); err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, items...)
+
+		if len(params.Attributes) == 0 || len(listings) < maxSweepCandidates || len(res) >= maxSweepCandidates {
+			break
+		}
+	}
+	return res, nil
+}
+
+// isErc1155Collection tells the token type of the collection by any of its tokens
+func (im *impl) isErc1155Collection(ctx ctx.Ctx, params order.SweepParams) (bool, error) {
+	items, err := im.nftitemRepo.FindAll(ctx,
+		nftitem.WithChainId(params.ChainId),
+		nftitem.WithContractAddresses([]domain.Address{params.Collection}),
+		nftitem.WithPagination(0, 1),
+	)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"params": params,
+			"err":    err,
+		}).Error("nftitemRepo.FindAll failed")
+		return false, err
+	}
+	return len(items) > 0 && items[0].TokenType == domain.TokenType1155, nil
+}
+
+// listedIdsByAttributes returns tokens with native listings matching all attribute filters
+func (im *impl) listedIdsByAttributes(ctx ctx.Ctx, params order.SweepParams) ([]nftitem.Id, error) {
+	items, err := im.nftitemRepo.FindAll(ctx,
+		nftitem.WithChainId(params.ChainId),
+		nftitem.WithContractAddresses([]domain.Address{params.Collection}),
+		nftitem.WithBuyNow(),
+		nftitem.WithAttributeFilters(params.Attributes),
+	)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"params": params,
+			"err":    err,
+		}).Error("nftitemRepo.FindAll failed")
+		return nil, err
+	}
+
+	ids := make([]nftitem.Id, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, *item.ToId())
+	}
+	return ids, nil
+}
+
+// filterSweepByAttributes keeps external candidates whose token matches all attribute filters
+func (im *impl) filterSweepByAttributes(ctx ctx.Ctx, params order.SweepParams, candidates []order.SweepItem) ([]order.SweepItem, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	ids := []nftitem.Id{}
+	seen := map[domain.TokenId]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate.TokenId] {
+			continue
+		}
+		seen[candidate.TokenId] = true
+		ids = append(ids, nftitem.Id{ChainId: params.ChainId, ContractAddress: params.Collection, TokenId: candidate.TokenId})
+	}
+
+	items, err := im.nftitemRepo.FindAll(ctx,
+		nftitem.WithChainId(params.ChainId),
+		nftitem.WithContractAddresses([]domain.Address{params.Collection}),
+		nftitem.WithNftitemIds(ids),
+		nftitem.WithAttributeFilters(params.Attributes),
+	)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"params": params,
+			"err":    err,
+		}).Error("nftitemRepo.FindAll failed")
+		return nil, err
+	}
+
+	matched := map[domain.TokenId]bool{}
+	for _, item := range items {
+		matched[item.TokenId] = true
+	}
+	res := []order.SweepItem{}
+	for _, candidate := range candidates {
+		if matched[candidate.TokenId] {
+			res = append(res, candidate)
+		}
+	}
+	return res, nil
+}
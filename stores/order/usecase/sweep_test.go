@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/x-xyz/goapi/base/ctx"
+	pricefomatter "github.com/x-xyz/goapi/base/price_fomatter/mocks"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/external_listing"
+	mExternalListing "github.com/x-xyz/goapi/domain/external_listing/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/domain/order"
+	mOrder "github.com/x-xyz/goapi/domain/order/mocks"
+)
+
+func TestQuoteSweep(t *testing.T) {
+	req := require.New(t)
+	_ctx := ctx.Background()
+	chainId := domain.ChainId(1)
+	collection := domain.Address("0x3333333333333333333333333333333333333333")
+	weth := domain.Address("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	eth := domain.Address("0x0000000000000000000000000000000000000000")
+	buyer := domain.Address("0x4444444444444444444444444444444444444444")
+	seller := domain.Address("0x5555555555555555555555555555555555555555")
+	now := time.Now()
+
+	askOf := func(tokenId domain.TokenId, price int64, signer domain.Address) *order.OrderItem {
+		return &order.OrderItem{
+			ChainId:  chainId,
+			Item:     order.Item{Collection: collection, TokenId: tokenId, Amount: "1", Price: big.NewInt(price).String()},
+			Signer:   signer,
+			Currency: weth,
+		}
+	}
+	orderItemRepo := &mOrder.OrderItemRepo{}
+	orderItemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*order.OrderItem{
+		askOf("1", 1, seller),
+		askOf("2", 3, seller),
+		askOf("3", 2, buyer),
+	}, nil)
+
+	priceFormatter := &pricefomatter.PriceFormatter{}
+	priceFormatter.On("GetPrices", mock.Anything, chainId, weth, mock.Anything).Return(
+		func(_ ctx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) decimal.Decimal {
+			return decimal.NewFromBigInt(value, 0)
+		},
+		func(_ ctx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) float64 {
+			return float64(value.Int64()) * 1000
+		},
+		func(_ ctx.Ctx, _ domain.ChainId, _ domain.Address, value *big.Int) float64 {
+			return float64(value.Int64())
+		},
+		nil,
+	)
+	priceFormatter.On("GetPricesFromDisplayPriceString", mock.Anything, chainId, eth, "1.5").Return(float64(1500), 1.5, nil)
+	priceFormatter.On("GetPricesFromDisplayPriceString", mock.Anything, chainId, eth, "0.5").Return(float64(500), 0.5, nil)
+
+	externalListingRepo := &mExternalListing.ExternalListingRepo{}
+	externalListingRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc")).
+		Return([]external_listing.ExternalListing{
+			// the erc721 token listed natively by another seller for more
+			{Owner: buyer, ChainId: chainId, ContractAddress: collection, TokenId: "2", Quantity: 1, PaymentToken: eth, Price: "0.5", StartTime: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+			{Owner: seller, ChainId: chainId, ContractAddress: collection, TokenId: "4", Quantity: 1, PaymentToken: eth, Price: "1.5", StartTime: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+		}, nil).Twice()
+
+	nftitemRepo := &mNftitem.Repo{}
+	nftitemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return([]*nftitem.NftItem{{ChainId: chainId, ContractAddress: collection, TokenId: "1", TokenType: domain.TokenType721}}, nil).Twice()
+
+	im := &impl{
+		orderItemRepo:       orderItemRepo,
+		nftitemRepo:         nftitemRepo,
+		priceFormatter:      priceFormatter,
+		externalListingRepo: externalListingRepo,
+	}
+
+	quote, err := im.QuoteSweep(_ctx, order.SweepParams{ChainId: chainId, Collection: collection, Count: 3})
+	req.NoError(err)
+	req.Len(quote.Items, 3)
+	// the cheapest ask of token 2 is chosen and the other seller's ask is dropped
+	req.Equal(domain.TokenId("2"), quote.Items[0].TokenId)
+	req.Equal(order.SweepSourceExternal, quote.Items[0].Source)
+	req.NotNil(quote.Items[0].ExternalListing)
+	req.Equal(domain.TokenId("1"), quote.Items[1].TokenId)
+	req.Equal(order.SweepSourceNative, quote.Items[1].Source)
+	req.Equal(domain.TokenId("4"), quote.Items[2].TokenId)
+	req.Equal("3", quote.TotalInNative)
+	req.Equal(float64(3000), quote.TotalInUsd)
+
+	// asks of the buyer are skipped without hiding the other seller's ask of the token, the budget stops at the first
+	// item not fitting
+	quote, err = im.QuoteSweep(_ctx, order.SweepParams{ChainId: chainId, Collection: collection, Budget: "3", Buyer: buyer})
+	req.NoError(err)
+	req.Len(quote.Items, 2)
+	req.Equal(domain.TokenId("1"), quote.Items[0].TokenId)
+	req.Equal(domain.TokenId("4"), quote.Items[1].TokenId)
+	req.Equal("2.5", quote.TotalInNative)
+
+	_, err = im.QuoteSweep(_ctx, order.SweepParams{ChainId: chainId, Collection: collection})
+	req.ErrorIs(err, domain.ErrBadParamInput)
+	_, err = im.QuoteSweep(_ctx, order.SweepParams{ChainId: chainId, Collection: collection, Budget: "-1"})
+	req.ErrorIs(err, domain.ErrInvalidNumberFormat)
+	externalListingRepo.AssertExpectations(t)
+	nftitemRepo.AssertExpectations(t)
+}
+
+func TestQuoteSweepBudgetOnlyCapped(t *testing.T) {
+	req := require.New(t)
+	chainId := domain.ChainId(1)
+	collection := domain.Address("0x3333333333333333333333333333333333333333")
+	weth := domain.Address("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+
+	asks := []*order.OrderItem{}
+	for i := 0; i < order.MaxSweepCount+10; i++ {
+		asks = append(asks, &order.OrderItem{
+			ChainId:  chainId,
+			Item:     order.Item{Collection: collection, TokenId: domain.TokenId(strconv.Itoa(i)), Amount: "1", Price: "1"},
+			Signer:   "0x5555555555555555555555555555555555555555",
+			Currency: weth,
+		})
+	}
+	orderItemRepo := &mOrder.OrderItemRepo{}
+	orderItemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(asks, nil).Once()
+
+	nftitemRepo := &mNftitem.Repo{}
+	nftitemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*nftitem.NftItem{}, nil).Once()
+
+	priceFormatter := &pricefomatter.PriceFormatter{}
+	priceFormatter.On("GetPrices", mock.Anything, chainId, weth, mock.Anything).Return(decimal.NewFromInt(1), float64(1000), float64(1), nil)
+
+	im := &impl{
+		orderItemRepo:  orderItemRepo,
+		nftitemRepo:    nftitemRepo,
+		priceFormatter: priceFormatter,
+	}
+
+	quote, err := im.QuoteSweep(ctx.Background(), order.SweepParams{ChainId: chainId, Collection: collection, Budget: "1000"})
+	req.NoError(err)
+	req.Len(quote.Items, order.MaxSweepCount)
+	req.Equal("100", quote.TotalInNative)
+	orderItemRepo.AssertExpectations(t)
+	nftitemRepo.AssertExpectations(t)
+}
+
+func TestQuoteSweepAttributes(t *testing.T) {
+	req := require.New(t)
+	_ctx := ctx.Background()
+	chainId := domain.ChainId(1)
+	collection := domain.Address("0x3333333333333333333333333333333333333333")
+	eth := domain.Address("0x0000000000000000000000000000000000000000")
+	seller := domain.Address("0x5555555555555555555555555555555555555555")
+	other := domain.Address("0x6666666666666666666666666666666666666666")
+	now := time.Now()
+
+	// only token 2 has the trait, matched by the nftitem query instead of filtering capped asks
+	nftitemRepo := &mNftitem.Repo{}
+	// the token type lookup goes first, trailing mock.Anything would match its missing argument
+	nftitemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*nftitem.NftItem{
+		{ChainId: chainId, ContractAddress: collection, TokenId: "1", TokenType: domain.TokenType1155},
+	}, nil).Once()
+	nftitemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*nftitem.NftItem{
+		{ChainId: chainId, ContractAddress: collection, TokenId: "2"},
+	}, nil)
+
+	orderItemRepo := &mOrder.OrderItemRepo{}
+	orderItemRepo.On("FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*order.OrderItem{
+		{ChainId: chainId, Item: order.Item{Collection: collection, TokenId: "2", Amount: "1", Price: "1"}, Signer: seller, Currency: eth},
+	}, nil).Once()
+
+	priceFormatter := &pricefomatter.PriceFormatter{}
+	priceFormatter.On("GetPrices", mock.Anything, chainId, eth, mock.Anything).Return(decimal.NewFromInt(1), float64(1000), float64(1), nil)
+	priceFormatter.On("GetPricesFromDisplayPriceString", mock.Anything, chainId, eth, "1.5").Return(float64(1500), 1.5, nil)
+
+	externalListingRepo := &mExternalListing.ExternalListingRepo{}
+	externalListingRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc"),
+		mock.AnythingOfType("external_listing.FindAllOptionsFunc")).
+		Return([]external_listing.ExternalListing{
+			// erc1155 listed by another seller is another item
+			{Owner: other, ChainId: chainId, ContractAddress: collection, TokenId: "2", Quantity: 1, PaymentToken: eth, Price: "1.5", StartTime: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+			// the same item is listed natively for less
+			{Owner: seller, ChainId: chainId, ContractAddress: collection, TokenId: "2", Quantity: 1, PaymentToken: eth, Price: "1.5", StartTime: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+			{Owner: seller, ChainId: chainId, ContractAddress: collection, TokenId: "4", Quantity: 1, PaymentToken: eth, Price: "1.5", StartTime: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+		}, nil).Once()
+
+	im := &impl{
+		orderItemRepo:       orderItemRepo,
+		nftitemRepo:         nftitemRepo,
+		priceFormatter:      priceFormatter,
+		externalListingRepo: externalListingRepo,
+	}
+
+	quote, err := im.QuoteSweep(_ctx, order.SweepParams{
+		ChainId:    chainId,
+		Collection: collection,
+		Count:      10,
+		Attributes: []nftitem.AttributeFilter{{Name: "Fur", Values: []string{"Gold"}}},
+	})
+	req.NoError(err)
+	req.Len(quote.Items, 2)
+	req.Equal(order.SweepSourceNative, quote.Items[0].Source)
+	req.Equal(seller, quote.Items[0].Seller)
+	req.Equal(order.SweepSourceExternal, quote.Items[1].Source)
+	req.Equal(other, quote.Items[1].Seller)
+	req.Equal("2.5", quote.TotalInNative)
+	orderItemRepo.AssertExpectations(t)
+	externalListingRepo.AssertExpectations(t)
+	nftitemRepo.AssertExpectations(t)
+}
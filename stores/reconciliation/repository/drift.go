@@ -0,0 +1,28 @@
+package repository
+
+import (
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/reconciliation"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+type driftRepoImpl struct {
+	q query.Mongo
+}
+
+func NewDriftRepo(q query.Mongo) reconciliation.DriftRepo {
+	return &driftRepoImpl{q}
+}
+
+func (r *driftRepoImpl) Insert(ctx bCtx.Ctx, drift reconciliation.Drift) error {
+	if err := r.q.Insert(ctx, domain.TableReconciliationDrifts, drift); err != nil {
+		ctx.WithFields(log.Fields{
+			"drift": drift,
+			"err":   err,
+		}).Error("q.Insert failed")
+		return err
+	}
+	return nil
+}
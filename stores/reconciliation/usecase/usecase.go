@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/reconciliation"
+	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/service/chain/contract"
+)
+
+// batchSize is the number of calls sent in a json-rpc batch
+const batchSize = 100
+
+type UseCaseCfg struct {
+	NftitemRepo        nftitem.Repo
+	Erc1155HoldingRepo erc1155.HoldingRepo
+	DriftRepo          reconciliation.DriftRepo
+	// TrackerStateRepo has the block processed by the tracker of the collection, the chain is read at this block
+	TrackerStateRepo domain.TrackerStateRepo
+	Erc721Service    contract.Erc721OwnerContract
+	Erc1155Service   contract.Erc1155BalanceContract
+	// TokenUseCase refreshes listing and offer states of tokens whose owner is fixed, it's skipped if nil
+	TokenUseCase token.Usecase
+}
+
+type impl struct {
+	nftitem        nftitem.Repo
+	holding        erc1155.HoldingRepo
+	drift          reconciliation.DriftRepo
+	trackerState   domain.TrackerStateRepo
+	erc721Service  contract.Erc721OwnerContract
+	erc1155Service contract.Erc1155BalanceContract
+	token          token.Usecase
+}
+
+func New(cfg *UseCaseCfg) reconciliation.UseCase {
+	return &impl{
+		nftitem:        cfg.NftitemRepo,
+		holding:        cfg.Erc1155HoldingRepo,
+		drift:          cfg.DriftRepo,
+		trackerState:   cfg.TrackerStateRepo,
+		erc721Service:  cfg.Erc721Service,
+		erc1155Service: cfg.Erc1155Service,
+		token:          cfg.TokenUseCase,
+	}
+}
+
+func (im *impl) Reconcile(c ctx.Ctx, chainId domain.ChainId, address domain.Address, tokenType domain.TokenType, sampleSize int) (*reconciliation.Report, error) {
+	report := &reconciliation.Report{ChainId: chainId, ContractAddress: address.ToLower()}
+	switch tokenType {
+	case domain.TokenType721:
+		return report, im.reconcileOwners(c, report, sampleSize)
+	case domain.TokenType1155:
+		return report, im.reconcileBalances(c, report, sampleSize)
+	default:
+		return report, nil
+	}
+}
+
+// reconcileOwners checks owners of a random window of sampleSize tokens, or of all tokens page by page
+func (im *impl) reconcileOwners(c ctx.Ctx, report *reconciliation.Report, sampleSize int) error {
+	logger := c.WithFields(log.Fields{
+		"chainId": report.ChainId,
+		"address": report.ContractAddress,
+	})
+	optFns := []nftitem.FindAllOptionsFunc{
+		nftitem.WithChainId(report.ChainId),
+		nftitem.WithContractAddresses([]domain.Address{report.ContractAddress}),
+		nftitem.WithSort("_id", domain.SortDirAsc),
+	}
+
+	offset, end := 0, -1
+	if sampleSize > 0 {
+		count, err := im.nftitem.Count(c, optFns...)
+		if err != nil {
+			logger.WithField("err", err).Error("nftitem.Count failed")
+			return err
+		}
+		if count > sampleSize {
+			offset = rand.Intn(count - sampleSize + 1)
+		}
+		end = offset + sampleSize
+	}
+
+	for ; end < 0 || offset < end; offset += batchSize {
+		limit := batchSize
+		if end >= 0 && end-offset < limit {
+			limit = end - offset
+		}
+		// the state is read before the items, so that the items have all events up to the block of the state
+		state, err := im.settledState(c, report)
+		if err != nil {
+			return err
+		}
+		items, err := im.nftitem.FindAll(c, append(optFns, nftitem.WithPagination(int32(offset), int32(limit)))...)
+		if err != nil {
+			logger.WithField("err", err).Error("nftitem.FindAll failed")
+			return err
+		}
+		if state == nil {
+			report.Skipped += len(items)
+		} else if err := im.reconcileItems(c, report, state, items); err != nil {
+			return err
+		}
+		if len(items) < limit {
+			break
+		}
+	}
+	return nil
+}
+
+func (im *impl) reconcileItems(c ctx.Ctx, report *reconciliation.Report, state *domain.TrackerState, items []*nftitem.NftItem) error {
+	checked := []*nftitem.NftItem{}
+	tokenIds := []*big.Int{}
+	for _, item := range items {
+		// lazy minted tokens don't exist on chain yet
+		if item.LazyMintVoucherId != "" {
+			continue
+		}
+		tokenId, ok := new(big.Int).SetString(item.TokenId.String(), 10)
+		if !ok {
+			continue
+		}
+		checked = append(checked, item)
+		tokenIds = append(tokenIds, tokenId)
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	owners, err := im.erc721Service.OwnersOf(c, int32(report.ChainId), report.ContractAddress.ToLowerStr(), blockOf(state), tokenIds)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"chainId": report.ChainId,
+			"address": report.ContractAddress,
+			"err":     err,
+		}).Error("erc721Service.OwnersOf failed")
+		return err
+	}
+	// owners are set by the stored transfers, the items are stale if the tracker moved while they're read
+	if moved, err := im.trackerMoved(c, state); err != nil {
+		return err
+	} else if moved {
+		report.Skipped += len(checked)
+		return nil
+	}
+
+	for i, item := range checked {
+		// ownerOf reverts for burned or not yet minted tokens, there's nothing to compare with
+		if owners[i] == "" {
+			continue
+		}
+		report.Checked++
+		actual := domain.Address(owners[i]).ToLower()
+		if actual.Equals(item.Owner) {
+			continue
+		}
+		report.Drifted++
+
+		drift := newDrift(item.ToId(), reconciliation.FieldOwner, item.Owner.ToLowerStr(), actual.ToLowerStr())
+		drift.Fixed = im.fixOwner(c, *item.ToId(), actual)
+		if drift.Fixed {
+			report.Fixed++
+		}
+		if err := im.drift.Insert(c, drift); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *impl) fixOwner(c ctx.Ctx, id nftitem.Id, owner domain.Address) bool {
+	logger := c.WithFields(log.Fields{
+		"id":    id,
+		"owner": owner,
+	})
+	if err := im.nftitem.Patch(c, id, nftitem.PatchableNftItem{Owner: &owner}); err != nil {
+		logger.WithField("err", err).Error("nftitem.Patch failed")
+		return false
+	}
+	if im.token == nil {
+		return true
+	}
+	// listings and offers of the previous owner become invalid
+	if err := im.token.RefreshListingAndOfferState(c, id); err != nil {
+		logger.WithField("err", err).Warn("token.RefreshListingAndOfferState failed")
+	}
+	return true
+}
+
+// reconcileBalances checks sampleSize random holdings, or all holdings of the collection. holders missing
+// from the holdings can't be found by balanceOf, they're left to event processing
+func (im *impl) reconcileBalances(c ctx.Ctx, report *reconciliation.Report, sampleSize int) error {
+	state, err := im.settledState(c, report)
+	if err != nil {
+		return err
+	}
+	all, err := im.holding.FindAll(c, erc1155.WithHoldingAddress(report.ContractAddress))
+	if err != nil {
+		c.WithFields(log.Fields{
+			"chainId": report.ChainId,
+			"address": report.ContractAddress,
+			"err":     err,
+		}).Error("holding.FindAll failed")
+		return err
+	}
+
+	holdings := []*erc1155.Holding{}
+	for _, h := range all {
+		if h.ChainId == report.ChainId {
+			holdings = append(holdings, h)
+		}
+	}
+	if sampleSize > 0 && len(holdings) > sampleSize {
+		rand.Shuffle(len(holdings), func(i, j int) { holdings[i], holdings[j] = holdings[j], holdings[i] })
+		holdings = holdings[:sampleSize]
+	}
+	if state == nil {
+		report.Skipped += len(holdings)
+		return nil
+	}
+	// the holdings are compared with the chain at the block of the state, they're stale if the tracker moved
+	// while they're read. fixes are increments so that transfers stored after the snapshot are kept
+	if moved, err := im.trackerMoved(c, state); err != nil {
+		return err
+	} else if moved {
+		report.Skipped += len(holdings)
+		return nil
+	}
+
+	for start := 0; start < len(holdings); start += batchSize {
+		end := start + batchSize
+		if end > len(holdings) {
+			end = len(holdings)
+		}
+		if err := im.reconcileHoldings(c, report, state, holdings[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *impl) reconcileHoldings(c ctx.Ctx, report *reconciliation.Report, state *domain.TrackerState, holdings []*erc1155.Holding) error {
+	checked := []*erc1155.Holding{}
+	owners := []string{}
+	tokenIds := []*big.Int{}
+	for _, h := range holdings {
+		tokenId, ok := new(big.Int).SetString(h.TokenId.String(), 10)
+		if !ok {
+			continue
+		}
+		checked = append(checked, h)
+		owners = append(owners, h.Owner.ToLowerStr())
+		tokenIds = append(tokenIds, tokenId)
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	balances, err := im.erc1155Service.BalancesOf(c, int32(report.ChainId), report.ContractAddress.ToLowerStr(), blockOf(state), owners, tokenIds)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"chainId": report.ChainId,
+			"address": report.ContractAddress,
+			"err":     err,
+		}).Error("erc1155Service.BalancesOf failed")
+		return err
+	}
+
+	for i, h := range checked {
+		report.Checked++
+		if !balances[i].IsInt64() || balances[i].Int64() == h.Balance {
+			continue
+		}
+		report.Drifted++
+
+		actual := balances[i].Int64()
+		id := nftitem.Id{ChainId: h.ChainId, ContractAddress: h.Address, TokenId: h.TokenId}
+		drift := newDrift(&id, reconciliation.FieldBalance, big.NewInt(h.Balance).String(), balances[i].String())
+		drift.Holder = h.Owner.ToLower()
+		drift.Fixed = im.fixBalance(c, h, actual)
+		if drift.Fixed {
+			report.Fixed++
+		}
+		if err := im.drift.Insert(c, drift); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *impl) fixBalance(c ctx.Ctx, h *erc1155.Holding, balance int64) bool {
+	id := erc1155.HoldingId{ChainId: h.ChainId, Address: h.Address, TokenId: h.TokenId, Owner: h.Owner}
+	logger := c.WithFields(log.Fields{
+		"id":      id,
+		"balance": balance,
+	})
+	holding, err := im.holding.Increment(c, id, balance-h.Balance)
+	if err != nil {
+		logger.WithField("err", err).Error("holding.Increment failed")
+		return false
+	}
+	if holding.Balance == 0 {
+		if err := im.holding.Delete(c, id); err != nil {
+			logger.WithField("err", err).Error("holding.Delete failed")
+			return false
+		}
+	}
+	return true
+}
+
+// settledState returns the state of the tracker of the collection if it's between blocks, i.e. all events
+// before the block of the state are stored and none of the block. it's nil if the collection isn't tracked
+// or a block is being processed, the chain can't be compared with the stored values then
+func (im *impl) settledState(c ctx.Ctx, report *reconciliation.Report) (*domain.TrackerState, error) {
+	id := &domain.TrackerStateId{ChainId: report.ChainId, ContractAddress: report.ContractAddress, Tag: domain.DefaultTag}
+	state, err := im.trackerState.Get(c, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("trackerState.Get failed")
+		return nil, err
+	}
+	if state.LastLogIndexProcessed >= 0 || state.LastBlockProcessed == 0 {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// trackerMoved tells if the tracker processed events since the state is read
+func (im *impl) trackerMoved(c ctx.Ctx, state *domain.TrackerState) (bool, error) {
+	current, err := im.trackerState.Get(c, state.ToId())
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  state.ToId(),
+			"err": err,
+		}).Error("trackerState.Get failed")
+		return false, err
+	}
+	return current.LastBlockProcessed != state.LastBlockProcessed || current.LastLogIndexProcessed != state.LastLogIndexProcessed, nil
+}
+
+// blockOf returns the last block fully processed by the tracker
+func blockOf(state *domain.TrackerState) *big.Int {
+	return new(big.Int).SetUint64(state.LastBlockProcessed - 1)
+}
+
+func newDrift(id *nftitem.Id, field reconciliation.Field, stored, actual string) reconciliation.Drift {
+	return reconciliation.Drift{
+		ChainId:         id.ChainId,
+		ContractAddress: id.ContractAddress.ToLower(),
+		TokenId:         id.TokenId,
+		Field:           field,
+		Stored:          stored,
+		Actual:          actual,
+		DetectedAt:      time.Now(),
+	}
+}
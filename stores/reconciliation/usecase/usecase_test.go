@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	mErc1155 "github.com/x-xyz/goapi/domain/erc1155/mocks"
+	mDomain "github.com/x-xyz/goapi/domain/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/domain/reconciliation"
+	mReconciliation "github.com/x-xyz/goapi/domain/reconciliation/mocks"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+)
+
+var (
+	contractAddress = domain.Address("0x1111111111111111111111111111111111111111")
+	alice           = domain.Address("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	bob             = domain.Address("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	trackerStateId  = &domain.TrackerStateId{ChainId: 1, ContractAddress: contractAddress, Tag: domain.DefaultTag}
+)
+
+func settled(block uint64) *domain.TrackerState {
+	return &domain.TrackerState{ChainId: 1, ContractAddress: contractAddress, Tag: domain.DefaultTag, LastBlockProcessed: block, LastLogIndexProcessed: -1}
+}
+
+type ReconciliationSuite struct {
+	suite.Suite
+	ctx              bCtx.Ctx
+	nftitemRepo      *mNftitem.Repo
+	holdingRepo      *mErc1155.HoldingRepo
+	driftRepo        *mReconciliation.DriftRepo
+	trackerStateRepo *mDomain.TrackerStateRepo
+	erc721           *mContract.Erc721OwnerContract
+	erc1155          *mContract.Erc1155BalanceContract
+	im               reconciliation.UseCase
+	items            []*nftitem.NftItem
+	// owners are owners of tokens on chain, tokens without owners are burned
+	owners map[string]string
+	drifts []reconciliation.Drift
+}
+
+func TestReconciliationSuite(t *testing.T) {
+	suite.Run(t, new(ReconciliationSuite))
+}
+
+func (s *ReconciliationSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.nftitemRepo = &mNftitem.Repo{}
+	s.holdingRepo = &mErc1155.HoldingRepo{}
+	s.driftRepo = &mReconciliation.DriftRepo{}
+	s.trackerStateRepo = &mDomain.TrackerStateRepo{}
+	s.erc721 = &mContract.Erc721OwnerContract{}
+	s.erc1155 = &mContract.Erc1155BalanceContract{}
+	s.im = New(&UseCaseCfg{
+		NftitemRepo:        s.nftitemRepo,
+		Erc1155HoldingRepo: s.holdingRepo,
+		DriftRepo:          s.driftRepo,
+		TrackerStateRepo:   s.trackerStateRepo,
+		Erc721Service:      s.erc721,
+		Erc1155Service:     s.erc1155,
+	})
+
+	s.items = []*nftitem.NftItem{}
+	s.owners = map[string]string{}
+	for i := 0; i < batchSize+10; i++ {
+		tokenId := fmt.Sprint(i)
+		s.items = append(s.items, &nftitem.NftItem{
+			ChainId:         1,
+			ContractAddress: contractAddress,
+			TokenId:         domain.TokenId(tokenId),
+			Owner:           alice,
+		})
+		s.owners[tokenId] = alice.ToLowerStr()
+	}
+	// a transfer missed by the tracker
+	s.owners["3"] = "0xBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"
+	s.owners[fmt.Sprint(batchSize+5)] = bob.ToLowerStr()
+	// burned
+	delete(s.owners, "7")
+
+	s.drifts = []reconciliation.Drift{}
+}
+
+func (s *ReconciliationSuite) TearDownTest() {
+	s.nftitemRepo.AssertExpectations(s.T())
+	s.holdingRepo.AssertExpectations(s.T())
+	s.driftRepo.AssertExpectations(s.T())
+	s.trackerStateRepo.AssertExpectations(s.T())
+	s.erc721.AssertExpectations(s.T())
+	s.erc1155.AssertExpectations(s.T())
+}
+
+func (s *ReconciliationSuite) mockState(state *domain.TrackerState) *mock.Call {
+	return s.trackerStateRepo.On("Get", mock.Anything, trackerStateId).Return(state, nil).Once()
+}
+
+// mockItems mocks pages of items, the page is sliced by the pagination option
+func (s *ReconciliationSuite) mockItems() *mock.Call {
+	return s.nftitemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return(func(c bCtx.Ctx, optFns ...nftitem.FindAllOptionsFunc) []*nftitem.NftItem {
+			opts, err := nftitem.GetFindAllOptions(optFns...)
+			s.Require().NoError(err)
+			offset, limit := int(*opts.Offset), int(*opts.Limit)
+			if offset >= len(s.items) {
+				return nil
+			}
+			if offset+limit > len(s.items) {
+				return s.items[offset:]
+			}
+			return s.items[offset : offset+limit]
+		}, nil)
+}
+
+// mockOwners mocks a batch of ownerOf calls at the block
+func (s *ReconciliationSuite) mockOwners(block int64) *mock.Call {
+	return s.erc721.On("OwnersOf", mock.Anything, int32(1), contractAddress.ToLowerStr(), big.NewInt(block), mock.AnythingOfType("[]*big.Int")).
+		Return(func(ctx bCtx.Ctx, chainId int32, addr string, blk *big.Int, tokenIds []*big.Int) []string {
+			owners := make([]string, len(tokenIds))
+			for i, tokenId := range tokenIds {
+				owners[i] = s.owners[tokenId.String()]
+			}
+			return owners
+		}, nil)
+}
+
+func (s *ReconciliationSuite) mockPatch(tokenId domain.TokenId, owner domain.Address) {
+	s.nftitemRepo.On("Patch", mock.Anything, nftitem.Id{ChainId: 1, ContractAddress: contractAddress, TokenId: tokenId}, mock.MatchedBy(func(v nftitem.PatchableNftItem) bool {
+		return *v.Owner == owner
+	})).Return(nil).Once()
+}
+
+func (s *ReconciliationSuite) mockDrifts() *mock.Call {
+	return s.driftRepo.On("Insert", mock.Anything, mock.AnythingOfType("reconciliation.Drift")).
+		Run(func(args mock.Arguments) { s.drifts = append(s.drifts, args.Get(1).(reconciliation.Drift)) }).
+		Return(nil)
+}
+
+func (s *ReconciliationSuite) TestReconcileOwners() {
+	// the chain is read at the last block processed by the tracker
+	s.mockState(settled(100)).Times(4)
+	s.mockItems().Twice()
+	s.mockOwners(99).Twice()
+	s.mockPatch("3", bob)
+	s.mockPatch(domain.TokenId(fmt.Sprint(batchSize+5)), bob)
+	s.mockDrifts().Twice()
+
+	report, err := s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType721, 0)
+	s.Require().NoError(err)
+	s.Equal(batchSize+9, report.Checked)
+	s.Equal(2, report.Drifted)
+	s.Equal(2, report.Fixed)
+	s.Equal(reconciliation.FieldOwner, s.drifts[0].Field)
+	s.Equal(alice.ToLowerStr(), s.drifts[0].Stored)
+	s.Equal(bob.ToLowerStr(), s.drifts[0].Actual)
+	s.True(s.drifts[0].Fixed)
+}
+
+func (s *ReconciliationSuite) TestReconcileOwnersSample() {
+	// a sample is a window in one batch
+	s.nftitemRepo.On("Count", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return(len(s.items), nil).Once()
+	s.mockState(settled(100)).Twice()
+	s.mockItems().Once()
+	s.mockOwners(99).Once()
+	// the window may have the missed transfers
+	s.nftitemRepo.On("Patch", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.mockDrifts().Maybe()
+
+	report, err := s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType721, 10)
+	s.Require().NoError(err)
+	s.LessOrEqual(report.Checked, 10)
+	s.GreaterOrEqual(report.Checked, 9)
+	s.Equal(report.Drifted, len(s.drifts))
+}
+
+func (s *ReconciliationSuite) TestReconcileOwnersTrackerMoved() {
+	// owners aren't compared while the tracker is in the middle of a block, or if it moves during the batch
+	busy := settled(101)
+	busy.LastLogIndexProcessed = 3
+	s.mockState(busy)
+	s.mockState(settled(101))
+	s.mockState(settled(102))
+	s.mockItems().Twice()
+	s.mockOwners(100).Once()
+
+	report, err := s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType721, 0)
+	s.Require().NoError(err)
+	s.Equal(batchSize+10, report.Skipped)
+	s.Equal(0, report.Checked)
+}
+
+func (s *ReconciliationSuite) mockHoldings(holdings []*erc1155.Holding) {
+	s.holdingRepo.On("FindAll", mock.Anything, mock.AnythingOfType("erc1155.FindAllOptionsFunc")).Return(holdings, nil).Once()
+}
+
+func (s *ReconciliationSuite) TestReconcileBalances() {
+	s.mockState(settled(100)).Twice()
+	s.mockHoldings([]*erc1155.Holding{
+		{ChainId: 1, Address: contractAddress, TokenId: "1", Owner: alice, Balance: 5},
+		{ChainId: 1, Address: contractAddress, TokenId: "1", Owner: bob, Balance: 2},
+		{ChainId: 1, Address: contractAddress, TokenId: "2", Owner: alice, Balance: 1},
+		// holdings of other chains are skipped
+		{ChainId: 137, Address: contractAddress, TokenId: "1", Owner: bob, Balance: 9},
+	})
+	s.erc1155.On("BalancesOf", mock.Anything, int32(1), contractAddress.ToLowerStr(), big.NewInt(99),
+		[]string{alice.ToLowerStr(), bob.ToLowerStr(), alice.ToLowerStr()},
+		[]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(2)}).
+		Return([]*big.Int{big.NewInt(3), big.NewInt(2), big.NewInt(0)}, nil).Once()
+	s.holdingRepo.On("Increment", mock.Anything, erc1155.HoldingId{ChainId: 1, Address: contractAddress, TokenId: "1", Owner: alice}, int64(-2)).
+		Return(&erc1155.Holding{ChainId: 1, Address: contractAddress, TokenId: "1", Owner: alice, Balance: 3}, nil).Once()
+	// the emptied holding is incremented to 0 and deleted
+	emptied := erc1155.HoldingId{ChainId: 1, Address: contractAddress, TokenId: "2", Owner: alice}
+	s.holdingRepo.On("Increment", mock.Anything, emptied, int64(-1)).
+		Return(&erc1155.Holding{ChainId: 1, Address: contractAddress, TokenId: "2", Owner: alice, Balance: 0}, nil).Once()
+	s.holdingRepo.On("Delete", mock.Anything, emptied).Return(nil).Once()
+	s.mockDrifts().Twice()
+
+	report, err := s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType1155, 0)
+	s.Require().NoError(err)
+	s.Equal(&reconciliation.Report{ChainId: 1, ContractAddress: contractAddress, Checked: 3, Drifted: 2, Fixed: 2}, report)
+	s.Equal(reconciliation.Drift{
+		ChainId:         1,
+		ContractAddress: contractAddress,
+		TokenId:         "1",
+		Field:           reconciliation.FieldBalance,
+		Holder:          alice,
+		Stored:          "5",
+		Actual:          "3",
+		Fixed:           true,
+		DetectedAt:      s.drifts[0].DetectedAt,
+	}, s.drifts[0])
+}
+
+func (s *ReconciliationSuite) TestReconcileBalancesTrackerMoved() {
+	holdings := []*erc1155.Holding{
+		{ChainId: 1, Address: contractAddress, TokenId: "1", Owner: alice, Balance: 5},
+	}
+
+	// a transfer is stored while the holdings are read
+	s.mockState(settled(100))
+	s.mockHoldings(holdings)
+	s.mockState(settled(101))
+	report, err := s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType1155, 0)
+	s.Require().NoError(err)
+	s.Equal(&reconciliation.Report{ChainId: 1, ContractAddress: contractAddress, Skipped: 1}, report)
+
+	// collections without trackers are skipped
+	s.trackerStateRepo.On("Get", mock.Anything, trackerStateId).Return(nil, domain.ErrNotFound).Once()
+	s.mockHoldings(holdings)
+	report, err = s.im.Reconcile(s.ctx, 1, contractAddress, domain.TokenType1155, 0)
+	s.Require().NoError(err)
+	s.Equal(1, report.Skipped)
+}
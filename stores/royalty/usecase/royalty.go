@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/royalty"
+	"github.com/x-xyz/goapi/service/cache"
+	compoundcache "github.com/x-xyz/goapi/service/cache/compoundCache"
+	"github.com/x-xyz/goapi/service/cache/provider"
+	"github.com/x-xyz/goapi/service/cache/provider/primitive"
+	redisCache "github.com/x-xyz/goapi/service/cache/provider/redis"
+	"github.com/x-xyz/goapi/service/chain/contract"
+	"github.com/x-xyz/goapi/service/redis"
+)
+
+type UseCaseCfg struct {
+	Erc2981       contract.Erc2981Contract
+	RoyaltyEngine contract.RoyaltyEngineContract
+	// RoyaltyEngines are royalty engine addresses of chains, the engine is skipped on chains without it
+	RoyaltyEngines map[domain.ChainId]domain.Address
+	CollectionRepo collection.Repo
+	// Redis is optional, resolved royalties are cached in memory only if nil
+	Redis redis.Service
+}
+
+type impl struct {
+	erc2981        contract.Erc2981Contract
+	royaltyEngine  contract.RoyaltyEngineContract
+	royaltyEngines map[domain.ChainId]domain.Address
+	collectionRepo collection.Repo
+	cache          cache.Service
+	// shortCache keeps royalties from the royalty engine and collection settings, which are overridden by owners any
+	// time and are cached per token so they can't be invalidated by collection
+	shortCache cache.Service
+}
+
+func New(cfg *UseCaseCfg) royalty.UseCase {
+	local := primitive.NewPrimitive("royalty", 1024)
+	return &impl{
+		erc2981:        cfg.Erc2981,
+		royaltyEngine:  cfg.RoyaltyEngine,
+		royaltyEngines: cfg.RoyaltyEngines,
+		collectionRepo: cfg.CollectionRepo,
+		cache:          newCache(local, cfg.Redis, "royalty", 24*time.Hour),
+		shortCache:     newCache(local, cfg.Redis, "royalty-short", 10*time.Minute),
+	}
+}
+
+func newCache(local provider.Provider, r redis.Service, pfx string, redisTtl time.Duration) cache.Service {
+	caches := []cache.Service{
+		cache.New(cache.ServiceConfig{
+			Ttl:   time.Minute,
+			Pfx:   pfx,
+			Cache: local,
+		}),
+	}
+	if r != nil {
+		caches = append(caches, cache.New(cache.ServiceConfig{
+			Ttl:   redisTtl,
+			Pfx:   pfx,
+			Cache: redisCache.NewRedis(r),
+		}))
+	}
+	return compoundcache.NewCompoundCache(caches)
+}
+
+func (im *impl) Resolve(c ctx.Ctx, id nftitem.Id) (*royalty.Royalty, error) {
+	tokenId, ok := new(big.Int).SetString(id.TokenId.String(), 10)
+	if !ok {
+		return nil, domain.ErrBadParamInput
+	}
+	id.ContractAddress = id.ContractAddress.ToLower()
+
+	key := keys.RedisKey(fmt.Sprint(id.ChainId), id.ContractAddress.ToLowerStr(), id.TokenId.String())
+	for _, cached := range []cache.Service{im.cache, im.shortCache} {
+		res := &royalty.Royalty{}
+		if err := cached.Get(c, key, res); err == nil {
+			return res, nil
+		} else if err != cache.ErrNotFound {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Warn("cache.Get failed")
+		}
+	}
+
+	res, err := im.resolve(c, id, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	// erc2981 is implemented by the token contract and rarely changes
+	target := im.shortCache
+	if res.Source == royalty.SourceErc2981 {
+		target = im.cache
+	}
+	if err := target.Set(c, key, res); err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Warn("cache.Set failed")
+	}
+	return res, nil
+}
+
+func (im *impl) Quote(c ctx.Ctx, id nftitem.Id, price *big.Int) (*royalty.Quote, error) {
+	if price == nil || price.Sign() < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	r, err := im.Resolve(c, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Quote(price), nil
+}
+
+func (im *impl) resolve(c ctx.Ctx, id nftitem.Id, tokenId *big.Int) (*royalty.Royalty, error) {
+	res := &royalty.Royalty{
+		ChainId:         id.ChainId,
+		ContractAddress: id.ContractAddress,
+		TokenId:         id.TokenId,
+		Source:          royalty.SourceNone,
+		Recipients:      []royalty.Recipient{},
+	}
+
+	// most contracts don't implement erc2981 and revert, which falls back to the next source. rpc failures are
+	// returned instead, so the fallback isn't cached
+	if im.erc2981 != nil {
+		receiver, amount, err := im.erc2981.RoyaltyInfo(c, int32(id.ChainId), id.ContractAddress.ToLowerStr(), tokenId, royalty.RatePrecision)
+		if err != nil && !contract.IsReverted(err) {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("erc2981.RoyaltyInfo failed")
+			return nil, err
+		}
+		if err == nil && common.HexToAddress(receiver) != (common.Address{}) && amount.Sign() > 0 {
+			res.Source = royalty.SourceErc2981
+			res.Recipients = append(res.Recipients, royalty.Recipient{
+				Address: domain.Address(receiver).ToLower(),
+				Rate:    amount.String(),
+			})
+			return res, nil
+		}
+	}
+
+	if engine, ok := im.royaltyEngines[id.ChainId]; ok && im.royaltyEngine != nil {
+		recipients, amounts, err := im.royaltyEngine.GetRoyalty(c, int32(id.ChainId), engine.ToLowerStr(), id.ContractAddress.ToLowerStr(), tokenId, royalty.RatePrecision)
+		if err != nil && !contract.IsReverted(err) {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("royaltyEngine.GetRoyalty failed")
+			return nil, err
+		}
+		for i, recipient := range recipients {
+			if i >= len(amounts) || amounts[i].Sign() <= 0 {
+				continue
+			}
+			res.Recipients = append(res.Recipients, royalty.Recipient{
+				Address: domain.Address(recipient).ToLower(),
+				Rate:    amounts[i].String(),
+			})
+		}
+		if len(res.Recipients) > 0 {
+			res.Source = royalty.SourceRoyaltyEngine
+			return res, nil
+		}
+	}
+
+	coll, err := im.collectionRepo.FindOne(c, collection.CollectionId{ChainId: id.ChainId, Address: id.ContractAddress})
+	if errors.Is(err, domain.ErrNotFound) {
+		return res, nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("collectionRepo.FindOne failed")
+		return nil, err
+	}
+	// collection royalty is in percent
+	if coll.Royalty > 0 && coll.FeeRecipient != "" {
+		rate := decimal.NewFromFloat(coll.Royalty).Div(decimal.NewFromInt(100)).Mul(decimal.NewFromBigInt(royalty.RatePrecision, 0))
+		res.Source = royalty.SourceCollection
+		res.Recipients = append(res.Recipients, royalty.Recipient{
+			Address: domain.Address(coll.FeeRecipient).ToLower(),
+			Rate:    rate.Floor().String(),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/royalty"
+	"github.com/x-xyz/goapi/service/cache"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+)
+
+var (
+	errReverted = errors.New("execution reverted")
+	errRpc      = errors.New("429 Too Many Requests")
+)
+
+const (
+	erc2981Contract    = domain.Address("0x0000000000000000000000000000000000002981")
+	engineContract     = domain.Address("0x00000000000000000000000000000000000e0e0e")
+	collectionContract = domain.Address("0x0000000000000000000000000000000000000c0c")
+	engine             = domain.Address("0x0000000000000000000000000000000000e0e0e0")
+)
+
+type RoyaltySuite struct {
+	suite.Suite
+	ctx            bCtx.Ctx
+	erc2981        *mContract.Erc2981Contract
+	royaltyEngine  *mContract.RoyaltyEngineContract
+	collectionRepo *mCollection.Repo
+	im             royalty.UseCase
+}
+
+func TestRoyaltySuite(t *testing.T) {
+	suite.Run(t, new(RoyaltySuite))
+}
+
+func (s *RoyaltySuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.erc2981 = &mContract.Erc2981Contract{}
+	s.royaltyEngine = &mContract.RoyaltyEngineContract{}
+	s.collectionRepo = &mCollection.Repo{}
+	s.im = New(&UseCaseCfg{
+		Erc2981:        s.erc2981,
+		RoyaltyEngine:  s.royaltyEngine,
+		RoyaltyEngines: map[domain.ChainId]domain.Address{1: engine},
+		CollectionRepo: s.collectionRepo,
+	})
+}
+
+func (s *RoyaltySuite) TearDownTest() {
+	s.erc2981.AssertExpectations(s.T())
+	s.royaltyEngine.AssertExpectations(s.T())
+	s.collectionRepo.AssertExpectations(s.T())
+}
+
+func (s *RoyaltySuite) mockErc2981(contract domain.Address, err error) *mock.Call {
+	call := s.erc2981.On("RoyaltyInfo", mock.Anything, int32(1), contract.ToLowerStr(), big.NewInt(1), royalty.RatePrecision)
+	if err != nil {
+		return call.Return("", nil, err).Once()
+	}
+	// 5%
+	return call.Return("0x000000000000000000000000000000000000AAAA", new(big.Int).Div(royalty.RatePrecision, big.NewInt(20)), nil).Once()
+}
+
+func (s *RoyaltySuite) mockEngine(contract domain.Address, recipients ...string) {
+	amounts := []*big.Int{}
+	for range recipients {
+		// 1% each
+		amounts = append(amounts, new(big.Int).Div(royalty.RatePrecision, big.NewInt(100)))
+	}
+	s.royaltyEngine.On("GetRoyalty", mock.Anything, int32(1), engine.ToLowerStr(), contract.ToLowerStr(), big.NewInt(1), royalty.RatePrecision).
+		Return(recipients, amounts, nil).Once()
+}
+
+func (s *RoyaltySuite) mockCollection(contract domain.Address, coll *collection.Collection) {
+	call := s.collectionRepo.On("FindOne", mock.Anything, collection.CollectionId{ChainId: 1, Address: contract})
+	if coll == nil {
+		call.Return(nil, domain.ErrNotFound).Once()
+		return
+	}
+	call.Return(coll, nil).Once()
+}
+
+func id(contract domain.Address) nftitem.Id {
+	return nftitem.Id{ChainId: 1, ContractAddress: contract, TokenId: "1"}
+}
+
+func (s *RoyaltySuite) TestErc2981() {
+	s.mockErc2981(erc2981Contract, nil)
+
+	q, err := s.im.Quote(s.ctx, id(erc2981Contract), big.NewInt(1000))
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceErc2981, q.Source)
+	s.Equal([]royalty.Payout{{Address: "0x000000000000000000000000000000000000aaaa", Amount: "50"}}, q.Recipients)
+	s.Equal("950", q.NetProceeds)
+}
+
+func (s *RoyaltySuite) TestRoyaltyEngine() {
+	s.mockErc2981(engineContract, errReverted)
+	s.mockEngine(engineContract, "0x000000000000000000000000000000000000BBBB", "0x000000000000000000000000000000000000CCCC")
+
+	q, err := s.im.Quote(s.ctx, id(engineContract), big.NewInt(1000))
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceRoyaltyEngine, q.Source)
+	s.Equal([]royalty.Payout{
+		{Address: "0x000000000000000000000000000000000000bbbb", Amount: "10"},
+		{Address: "0x000000000000000000000000000000000000cccc", Amount: "10"},
+	}, q.Recipients)
+	s.Equal("20", q.TotalRoyalty)
+}
+
+func (s *RoyaltySuite) TestCollection() {
+	s.mockErc2981(collectionContract, errReverted)
+	s.mockEngine(collectionContract)
+	s.mockCollection(collectionContract, &collection.Collection{Royalty: 2.5, FeeRecipient: "0x000000000000000000000000000000000000DDDD"})
+
+	q, err := s.im.Quote(s.ctx, id(collectionContract), big.NewInt(1000))
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceCollection, q.Source)
+	s.Equal([]royalty.Payout{{Address: "0x000000000000000000000000000000000000dddd", Amount: "25"}}, q.Recipients)
+	s.Equal("975", q.NetProceeds)
+}
+
+func (s *RoyaltySuite) TestNone() {
+	contract := domain.Address("0x0000000000000000000000000000000000000001")
+	s.mockErc2981(contract, errReverted)
+	s.mockEngine(contract)
+	s.mockCollection(contract, nil)
+
+	q, err := s.im.Quote(s.ctx, id(contract), big.NewInt(1000))
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceNone, q.Source)
+	s.Empty(q.Recipients)
+	s.Equal("1000", q.NetProceeds)
+}
+
+func (s *RoyaltySuite) TestCached() {
+	// resolved only once
+	s.mockErc2981(erc2981Contract, nil)
+
+	tokenId := id(erc2981Contract)
+	_, err := s.im.Resolve(s.ctx, tokenId)
+	s.Require().NoError(err)
+	_, err = s.im.Quote(s.ctx, tokenId, big.NewInt(2000))
+	s.Require().NoError(err)
+}
+
+func (s *RoyaltySuite) TestCollectionCachedShortly() {
+	// resolved only once
+	s.mockErc2981(collectionContract, errReverted)
+	s.mockEngine(collectionContract)
+	s.mockCollection(collectionContract, &collection.Collection{Royalty: 2.5, FeeRecipient: "0x000000000000000000000000000000000000DDDD"})
+
+	tokenId := id(collectionContract)
+	_, err := s.im.Resolve(s.ctx, tokenId)
+	s.Require().NoError(err)
+	r, err := s.im.Resolve(s.ctx, tokenId)
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceCollection, r.Source)
+
+	// owners update collection settings any time, so they aren't kept for long
+	im := s.im.(*impl)
+	key := keys.RedisKey("1", collectionContract.ToLowerStr(), "1")
+	s.ErrorIs(im.cache.Get(s.ctx, key, &royalty.Royalty{}), cache.ErrNotFound)
+	s.NoError(im.shortCache.Get(s.ctx, key, &royalty.Royalty{}))
+}
+
+func (s *RoyaltySuite) TestRpcFailureNotCached() {
+	tokenId := id(erc2981Contract)
+	s.mockErc2981(erc2981Contract, errRpc)
+	_, err := s.im.Resolve(s.ctx, tokenId)
+	s.ErrorIs(err, errRpc)
+
+	s.mockErc2981(erc2981Contract, nil)
+	r, err := s.im.Resolve(s.ctx, tokenId)
+	s.Require().NoError(err)
+	s.Equal(royalty.SourceErc2981, r.Source)
+}
+
+func (s *RoyaltySuite) TestInvalidTokenId() {
+	_, err := s.im.Resolve(s.ctx, nftitem.Id{ChainId: 1, ContractAddress: "0x1", TokenId: "abc"})
+	s.ErrorIs(err, domain.ErrBadParamInput)
+}
@@ -28,75 +28,56 @@ func New(e *echo.Echo, search search.Usecase) {
 	g.GET("/tokens", h.searchTokens)
 }
 
-func (h *handler) searchAll(c echo.Context) error {
-	type params struct {
-		Keyword     string           `query:"keyword"`
-		Filter      []string         `query:"filter"`
-		Collections []domain.Address `query:"collections"`
-	}
-
-	p := &params{}
-
-	if err := c.Bind(p); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid params")
-	}
-
-	ctx := c.Get("ctx").(ctx.Ctx)
-
-	if res, err := h.search.Search(ctx, p.Keyword, p.Filter, p.Collections); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	} else {
-		return delivery.MakeJsonResp(c, http.StatusOK, res)
-	}
+type params struct {
+	Keyword     string           `query:"keyword"`
+	Filter      []string         `query:"filter"`
+	Collections []domain.Address `query:"collections"`
+	ChainId     *domain.ChainId  `query:"chainId"`
+	Category    *string          `query:"category"`
+	Offset      int              `query:"offset"`
+	Limit       int              `query:"limit"`
 }
 
-func (h *handler) searchAccounts(c echo.Context) error {
-	type params struct {
-		Keyword string `query:"keyword"`
+func (p *params) toOptions() []search.SearchOptionsFunc {
+	opts := []search.SearchOptionsFunc{
+		search.WithFilter(p.Filter),
+		search.WithCollections(p.Collections),
 	}
 
-	p := &params{}
-
-	if err := c.Bind(p); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid params")
+	if p.ChainId != nil {
+		opts = append(opts, search.WithChainId(*p.ChainId))
 	}
 
-	ctx := c.Get("ctx").(ctx.Ctx)
-
-	if res, err := h.search.SearchAccounts(ctx, p.Keyword); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	} else {
-		return delivery.MakeJsonResp(c, http.StatusOK, res)
+	if p.Category != nil {
+		opts = append(opts, search.WithCategory(*p.Category))
 	}
-}
 
-func (h *handler) searchCollections(c echo.Context) error {
-	type params struct {
-		Keyword     string           `query:"keyword"`
-		Collections []domain.Address `query:"collections"`
+	if p.Offset != 0 || p.Limit != 0 {
+		opts = append(opts, search.WithPagination(p.Offset, p.Limit))
 	}
 
-	p := &params{}
+	return opts
+}
 
-	if err := c.Bind(p); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusBadRequest, "invalid params")
-	}
+func (h *handler) searchAll(c echo.Context) error {
+	return h.doSearch(c, h.search.Search)
+}
 
-	ctx := c.Get("ctx").(ctx.Ctx)
+func (h *handler) searchAccounts(c echo.Context) error {
+	return h.doSearch(c, h.search.SearchAccounts)
+}
 
-	if res, err := h.search.SearchCollections(ctx, p.Keyword, p.Collections); err != nil {
-		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
-	} else {
-		return delivery.MakeJsonResp(c, http.StatusOK, res)
-	}
+func (h *handler) searchCollections(c echo.Context) error {
+	return h.doSearch(c, h.search.SearchCollections)
 }
 
 func (h *handler) searchTokens(c echo.Context) error {
-	type params struct {
-		Keyword     string           `query:"keyword"`
-		Collections []domain.Address `query:"collections"`
-	}
+	return h.doSearch(c, h.search.SearchTokens)
+}
+
+type searchFunc func(c ctx.Ctx, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error)
 
+func (h *handler) doSearch(c echo.Context, fn searchFunc) error {
 	p := &params{}
 
 	if err := c.Bind(p); err != nil {
@@ -105,7 +86,9 @@ func (h *handler) searchTokens(c echo.Context) error {
 
 	ctx := c.Get("ctx").(ctx.Ctx)
 
-	if res, err := h.search.SearchTokens(ctx, p.Keyword, p.Collections); err != nil {
+	if res, err := fn(ctx, p.Keyword, p.toOptions()...); err == domain.ErrBadParamInput {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err.Error())
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
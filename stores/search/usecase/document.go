@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/search"
+	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/service/fulltext"
+)
+
+const zeroAddress = domain.Address("0x0000000000000000000000000000000000000000")
+
+// field weights
+const (
+	weightName        = 3
+	weightAlias       = 3
+	weightEns         = 3
+	weightAddress     = 1
+	weightCollection  = 1
+	weightTrait       = 1
+	weightDescription = 0.5
+)
+
+// collectionInfo is the part of collection used to rank its tokens
+type collectionInfo struct {
+	Name       string
+	IsVerified bool
+	Boost      float64
+}
+
+func toDocId(kind, key string) string {
+	return kind + "/" + key
+}
+
+func fromDocId(id string) (string, string) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+func accountDocId(address domain.Address) string {
+	return toDocId(search.Account, address.ToLowerStr())
+}
+
+func collectionDocId(chainId domain.ChainId, address domain.Address) string {
+	return toDocId(search.Collection, collection.ToCollectionKey(chainId, address))
+}
+
+func tokenDocId(chainId domain.ChainId, contract domain.Address, tokenId domain.TokenId) string {
+	return toDocId(search.Token, token.ToTokenKey(chainId, contract, tokenId))
+}
+
+func isSearchableAccount(a *account.Account) bool {
+	return a.IsAppropriate
+}
+
+func isSearchableCollection(col *collection.Collection) bool {
+	return col.IsAppropriate && col.Status
+}
+
+func isSearchableToken(item *nftitem.NftItem) bool {
+	return (item.IsAppropriate == nil || *item.IsAppropriate) && !item.Owner.Equals(zeroAddress)
+}
+
+// collectionBoost favours verified collections and collections with higher trading volume
+func collectionBoost(col *collection.Collection) float64 {
+	boost := math.Log10(1+col.TotalVolume) / 4
+	if col.IsVerified {
+		boost += 1
+	}
+	return boost
+}
+
+func toCollectionInfo(col *collection.Collection) *collectionInfo {
+	return &collectionInfo{
+		Name:       col.CollectionName,
+		IsVerified: col.IsVerified,
+		Boost:      collectionBoost(col),
+	}
+}
+
+func accountDocument(a *account.Account, ensName string) fulltext.Document {
+	return fulltext.Document{
+		Id:   accountDocId(a.Address),
+		Kind: search.Account,
+		Fields: []fulltext.Field{
+			{Name: "alias", Text: a.Alias, Weight: weightAlias},
+			{Name: "ens", Text: ensName, Weight: weightEns},
+			{Name: "address", Text: a.Address.ToLowerStr(), Weight: weightAddress},
+			{Name: "bio", Text: a.Bio, Weight: weightDescription},
+		},
+		Facets: map[string][]string{},
+	}
+}
+
+func collectionDocument(col *collection.Collection) fulltext.Document {
+	return fulltext.Document{
+		Id:   collectionDocId(col.ChainId, col.Erc721Address),
+		Kind: search.Collection,
+		Fields: []fulltext.Field{
+			{Name: "name", Text: col.CollectionName, Weight: weightName},
+			{Name: "address", Text: col.Erc721Address.ToLowerStr(), Weight: weightAddress},
+			{Name: "description", Text: col.Description, Weight: weightDescription},
+		},
+		Boost: collectionBoost(col),
+		Facets: map[string][]string{
+			search.FacetChainId:    {strconv.Itoa(int(col.ChainId))},
+			search.FacetCollection: {col.Erc721Address.ToLowerStr()},
+			search.FacetCategory:   col.Categories,
+			search.FacetVerified:   {strconv.FormatBool(col.IsVerified)},
+		},
+	}
+}
+
+func tokenDocument(item *nftitem.NftItem, col *collectionInfo) fulltext.Document {
+	traits := make([]string, 0, len(item.Attributes))
+	for _, attr := range item.Attributes {
+		traits = append(traits, attr.Value)
+	}
+
+	doc := fulltext.Document{
+		Id:   tokenDocId(item.ChainId, item.ContractAddress, item.TokenId),
+		Kind: search.Token,
+		Fields: []fulltext.Field{
+			{Name: "name", Text: item.Name, Weight: weightName},
+			{Name: "traits", Text: strings.Join(traits, " "), Weight: weightTrait},
+		},
+		Facets: map[string][]string{
+			search.FacetChainId:    {strconv.Itoa(int(item.ChainId))},
+			search.FacetCollection: {item.ContractAddress.ToLowerStr()},
+			search.FacetVerified:   {"false"},
+		},
+	}
+
+	if col != nil {
+		doc.Fields = append(doc.Fields, fulltext.Field{Name: "collection", Text: col.Name, Weight: weightCollection})
+		doc.Boost = col.Boost
+		doc.Facets[search.FacetVerified] = []string{strconv.FormatBool(col.IsVerified)}
+	}
+
+	return doc
+}
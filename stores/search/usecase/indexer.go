@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/service/fulltext"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	syncBatch = 1000
+	// syncOverlap is subtracted from last sync time to tolerate clock skew between writers
+	syncOverlap = 10 * time.Second
+)
+
+var tokenProjection = bson.M{
+	"_id":             1,
+	"chainId":         1,
+	"contractAddress": 1,
+	"tokenID":         1,
+	"name":            1,
+	"owner":           1,
+	"attributes":      1,
+	"isAppropriate":   1,
+}
+
+type accountDoc struct {
+	ObjectId        primitive.ObjectID `bson:"_id"`
+	account.Account `bson:",inline"`
+}
+
+func (im *impl) IndexAccount(c ctx.Ctx, address domain.Address) error {
+	return im.indexAccount(c, address, true)
+}
+
+func (im *impl) indexAccount(c ctx.Ctx, address domain.Address, resolveEns bool) error {
+	acc, err := im.account.Get(c, address)
+	if err == domain.ErrNotFound {
+		im.getIndex().Remove(accountDocId(address))
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Error("account.Get failed")
+		return err
+	}
+
+	im.upsertAccount(c, im.getIndex(), acc, resolveEns)
+	return nil
+}
+
+func (im *impl) IndexCollection(c ctx.Ctx, id collection.CollectionId) error {
+	col, err := im.collection.FindOne(c, id)
+	if err == domain.ErrNotFound {
+		im.getIndex().Remove(collectionDocId(id.ChainId, id.Address))
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("collection.FindOne failed")
+		return err
+	}
+
+	im.mu.Lock()
+	im.collections[collection.ToCollectionKey(col.ChainId, col.Erc721Address)] = toCollectionInfo(col)
+	im.mu.Unlock()
+
+	im.upsertCollection(im.getIndex(), col)
+	return nil
+}
+
+func (im *impl) IndexToken(c ctx.Ctx, id nftitem.Id) error {
+	item, err := im.nftitem.FindOne(c, id.ChainId, id.ContractAddress, id.TokenId)
+	if err == domain.ErrNotFound {
+		im.getIndex().Remove(tokenDocId(id.ChainId, id.ContractAddress, id.TokenId))
+		return nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("nftitem.FindOne failed")
+		return err
+	}
+
+	im.upsertToken(im.getIndex(), item, im.getCollectionInfo(item.ChainId, item.ContractAddress))
+	return nil
+}
+
+func (im *impl) Sync(c ctx.Ctx) error {
+	im.syncMu.Lock()
+	defer im.syncMu.Unlock()
+
+	now := time.Now()
+	if im.lastRebuild.IsZero() || now.Sub(im.lastRebuild) >= im.rebuildInterval {
+		return im.rebuild(c, now)
+	}
+	return im.syncSince(c, now)
+}
+
+// rebuild builds a new index from scratch and swaps it with the serving one
+func (im *impl) rebuild(c ctx.Ctx, now time.Time) error {
+	index := fulltext.New()
+
+	collections, err := im.syncCollections(c, index)
+	if err != nil {
+		return err
+	}
+
+	if err := im.scanAccounts(c, bson.M{}, func(acc *account.Account) {
+		im.upsertAccount(c, index, acc, false)
+	}); err != nil {
+		return err
+	}
+
+	lastTokenObjectId, err := im.scanTokens(c, bson.M{}, func(item *nftitem.NftItem) {
+		im.upsertToken(index, item, collections[collection.ToCollectionKey(item.ChainId, item.ContractAddress)])
+	})
+	if err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	im.index = index
+	im.collections = collections
+	im.mu.Unlock()
+
+	im.lastRebuild = now
+	im.lastSync = now
+	im.lastTokenObjectId = lastTokenObjectId
+
+	c.WithFields(log.Fields{
+		"size":    index.Size(),
+		"elapsed": time.Since(now).String(),
+	}).Info("search index rebuilt")
+
+	return nil
+}
+
+// syncSince indexes collections, and accounts and tokens changed or created after last sync
+func (im *impl) syncSince(c ctx.Ctx, now time.Time) error {
+	index := im.getIndex()
+	since := im.lastSync.Add(-syncOverlap)
+
+	collections, err := im.syncCollections(c, index)
+	if err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	im.collections = collections
+	im.mu.Unlock()
+
+	if err := im.scanAccounts(c, bson.M{"updatedAt": bson.M{"$gte": since}}, func(acc *account.Account) {
+		im.upsertAccount(c, index, acc, true)
+	}); err != nil {
+		return err
+	}
+
+	tokenQuery := bson.M{"$or": bson.A{
+		bson.M{"_id": bson.M{"$gt": im.lastTokenObjectId}},
+		bson.M{"updatedAt": bson.M{"$gte": since}},
+	}}
+	lastTokenObjectId, err := im.scanTokens(c, tokenQuery, func(item *nftitem.NftItem) {
+		im.upsertToken(index, item, collections[collection.ToCollectionKey(item.ChainId, item.ContractAddress)])
+	})
+	if err != nil {
+		return err
+	}
+
+	im.lastSync = now
+	if bytes.Compare(lastTokenObjectId[:], im.lastTokenObjectId[:]) > 0 {
+		im.lastTokenObjectId = lastTokenObjectId
+	}
+
+	return nil
+}
+
+// syncCollections indexes all collections and returns ranking info of searchable ones
+func (im *impl) syncCollections(c ctx.Ctx, index fulltext.Index) (map[string]*collectionInfo, error) {
+	cols, err := im.collection.FindAll(c)
+	if err != nil {
+		c.WithField("err", err).Error("collection.FindAll failed")
+		return nil, err
+	}
+
+	res := map[string]*collectionInfo{}
+	for _, col := range cols {
+		if isSearchableCollection(col) {
+			res[collection.ToCollectionKey(col.ChainId, col.Erc721Address)] = toCollectionInfo(col)
+		}
+		im.upsertCollection(index, col)
+	}
+
+	return res, nil
+}
+
+// scanAccounts iterates accounts matching query in batches ordered by _id
+func (im *impl) scanAccounts(c ctx.Ctx, query bson.M, fn func(*account.Account)) error {
+	lastId := primitive.NilObjectID
+	for {
+		accs := []*accountDoc{}
+		q := bson.M{"$and": bson.A{query, bson.M{"_id": bson.M{"$gt": lastId}}}}
+		if err := im.q.Search(c, domain.TableAccounts, 0, syncBatch, "_id", q, &accs); err != nil {
+			c.WithFields(log.Fields{
+				"err":   err,
+				"query": q,
+			}).Error("q.Search failed")
+			return err
+		}
+
+		for _, acc := range accs {
+			fn(&acc.Account)
+		}
+
+		if len(accs) < syncBatch {
+			return nil
+		}
+		lastId = accs[len(accs)-1].ObjectId
+	}
+}
+
+// scanTokens iterates tokens matching query in batches ordered by _id, and returns the last _id
+func (im *impl) scanTokens(c ctx.Ctx, query bson.M, fn func(*nftitem.NftItem)) (primitive.ObjectID, error) {
+	lastId := primitive.NilObjectID
+	for {
+		items := []*nftitem.NftItem{}
+		q := bson.M{"$and": bson.A{query, bson.M{"_id": bson.M{"$gt": lastId}}}}
+		if err := im.q.SearchNProject(c, domain.TableNFTItems, 0, syncBatch, "_id", q, tokenProjection, &items); err != nil {
+			c.WithFields(log.Fields{
+				"err":   err,
+				"query": q,
+			}).Error("q.SearchNProject failed")
+			return lastId, err
+		}
+
+		for _, item := range items {
+			fn(item)
+		}
+
+		if len(items) > 0 {
+			lastId = items[len(items)-1].ObjectId
+		}
+
+		if len(items) < syncBatch {
+			return lastId, nil
+		}
+	}
+}
+
+func (im *impl) upsertAccount(c ctx.Ctx, index fulltext.Index, acc *account.Account, resolveEns bool) {
+	address := acc.Address.ToLower()
+	if !isSearchableAccount(acc) {
+		index.Remove(accountDocId(address))
+		return
+	}
+
+	name, ok := im.getEnsName(address)
+	if !ok && resolveEns && im.ens != nil {
+		if resolved, err := im.ens.ReverseResolve(c, address); err != nil {
+			c.WithFields(log.Fields{
+				"err":     err,
+				"address": address,
+			}).Warn("ens.ReverseResolve failed")
+		} else {
+			name = resolved
+			im.setEnsName(address, name)
+		}
+	}
+
+	index.Upsert(accountDocument(acc, name))
+}
+
+func (im *impl) upsertCollection(index fulltext.Index, col *collection.Collection) {
+	if !isSearchableCollection(col) {
+		index.Remove(collectionDocId(col.ChainId, col.Erc721Address))
+		return
+	}
+	index.Upsert(collectionDocument(col))
+}
+
+func (im *impl) upsertToken(index fulltext.Index, item *nftitem.NftItem, col *collectionInfo) {
+	if !isSearchableToken(item) {
+		index.Remove(tokenDocId(item.ChainId, item.ContractAddress, item.TokenId))
+		return
+	}
+	index.Upsert(tokenDocument(item, col))
+}
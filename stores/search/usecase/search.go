@@ -1,247 +1,401 @@
 package usecase
 
 import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/search"
+	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/service/ens"
+	"github.com/x-xyz/goapi/service/fulltext"
 	"github.com/x-xyz/goapi/service/query"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// default page sizes when limit is not given
 const (
 	maxAccounts    = 3
 	maxCollections = 3
 	maxTokens      = 10
 )
 
+const defaultRebuildInterval = 6 * time.Hour
+
+type SearchUseCaseCfg struct {
+	Mongo          query.Mongo
+	AccountRepo    account.Repo
+	CollectionRepo collection.Repo
+	NftitemRepo    nftitem.Repo
+	// ENS is optional, account ens names are not indexed if nil
+	ENS ens.ENS
+	// RebuildInterval is the interval of rebuilding the whole index in Sync, defaultRebuildInterval if zero
+	RebuildInterval time.Duration
+}
+
 type impl struct {
-	q query.Mongo
+	q               query.Mongo
+	account         account.Repo
+	collection      collection.Repo
+	nftitem         nftitem.Repo
+	ens             ens.ENS
+	rebuildInterval time.Duration
+
+	mu          sync.RWMutex
+	index       fulltext.Index
+	collections map[string]*collectionInfo
+	ensNames    map[domain.Address]string
+
+	// sync states, guarded by syncMu
+	syncMu            sync.Mutex
+	lastRebuild       time.Time
+	lastSync          time.Time
+	lastTokenObjectId primitive.ObjectID
 }
 
-func New(q query.Mongo) search.Usecase {
-	return &impl{q: q}
+func New(cfg *SearchUseCaseCfg) search.Usecase {
+	rebuildInterval := cfg.RebuildInterval
+	if rebuildInterval == 0 {
+		rebuildInterval = defaultRebuildInterval
+	}
+
+	return &impl{
+		q:               cfg.Mongo,
+		account:         cfg.AccountRepo,
+		collection:      cfg.CollectionRepo,
+		nftitem:         cfg.NftitemRepo,
+		ens:             cfg.ENS,
+		rebuildInterval: rebuildInterval,
+		index:           fulltext.New(),
+		collections:     map[string]*collectionInfo{},
+		ensNames:        map[domain.Address]string{},
+	}
 }
 
-func (im *impl) Search(c ctx.Ctx, keyword string, filter []string, collections []domain.Address) (*search.Result, error) {
-	res := &search.Result{}
-	for _, target := range filter {
-		switch target {
-		case search.Account:
-			if accRes, err := im.SearchAccounts(c, keyword); err != nil {
-				c.WithField("err", err).Error("SearchAccounts failed")
-				return nil, err
-			} else {
-				res.Accounts = accRes.Accounts
-			}
-		case search.Collection:
-			if colRes, err := im.SearchCollections(c, keyword, collections); err != nil {
-				c.WithField("err", err).Error("SearchCollectinos failed")
-				return nil, err
-			} else {
-				res.Collections = colRes.Collections
-			}
-		case search.Token:
-			if tknRes, err := im.SearchTokens(c, keyword, collections); err != nil {
-				c.WithField("err", err).Error("SearchTokens failed")
-				return nil, err
-			} else {
-				res.Tokens = tknRes.Tokens
-			}
-		default:
-			continue
-		}
+func (im *impl) Search(c ctx.Ctx, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error) {
+	options, err := search.GetSearchOptions(opts...)
+	if err != nil {
+		return nil, err
 	}
-	if len(filter) == 0 {
-		if accRes, err := im.SearchAccounts(c, keyword); err != nil {
-			c.WithField("err", err).Error("SearchAccounts failed")
+
+	kinds := options.Filter
+	if len(kinds) == 0 {
+		kinds = []string{search.Account, search.Collection, search.Token}
+	}
+
+	res := &search.Result{Facets: map[string]map[string]int{}}
+	for _, kind := range kinds {
+		kindRes, err := im.searchKind(c, kind, keyword, options)
+		if err == domain.ErrBadParamInput {
+			// unknown kind
+			continue
+		} else if err != nil {
+			c.WithFields(log.Fields{
+				"err":  err,
+				"kind": kind,
+			}).Error("searchKind failed")
 			return nil, err
-		} else {
-			res.Accounts = accRes.Accounts
 		}
 
-		if colRes, err := im.SearchCollections(c, keyword, collections); err != nil {
-			c.WithField("err", err).Error("SearchCollectinos failed")
-			return nil, err
-		} else {
-			res.Collections = colRes.Collections
+		switch kind {
+		case search.Account:
+			res.Accounts = kindRes.Accounts
+		case search.Collection:
+			res.Collections = kindRes.Collections
+		case search.Token:
+			res.Tokens = kindRes.Tokens
 		}
 
-		if tknRes, err := im.SearchTokens(c, keyword, collections); err != nil {
-			c.WithField("err", err).Error("SearchTokens failed")
-			return nil, err
-		} else {
-			res.Tokens = tknRes.Tokens
+		res.Count += kindRes.Count
+		for name, counts := range kindRes.Facets {
+			if _, ok := res.Facets[name]; !ok {
+				res.Facets[name] = map[string]int{}
+			}
+			for v, cnt := range counts {
+				res.Facets[name][v] += cnt
+			}
 		}
 	}
 
 	return res, nil
 }
 
-func (im *impl) SearchAccounts(c ctx.Ctx, keyword string) (*search.Result, error) {
-	res := &search.Result{}
-	accs := []*account.Account{}
-
-	searchStage := bson.D{{"$search", bson.M{
-		"compound": bson.M{
-			"must": bson.A{
-				bson.M{"equals": bson.M{"path": "isAppropriate", "value": true}},
-			},
-			"should": bson.A{
-				bson.M{"text": bson.M{
-					"path":  []string{"address", "alias", "bio"},
-					"query": keyword,
-					"fuzzy": bson.M{"maxEdits": 1},
-				}},
-			},
-		},
-	}}}
-	limitStage := bson.D{{"$limit", maxAccounts}}
-	visibilityStage := bson.D{{"$project", bson.D{{"address", 1}, {"alias", 1}, {"imageHash", 1}, {"_id", 0}}}}
-	iter, close, err := im.q.Pipe(c, domain.TableAccounts, mongo.Pipeline{searchStage, limitStage, visibilityStage})
+func (im *impl) SearchAccounts(c ctx.Ctx, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error) {
+	return im.searchOne(c, search.Account, keyword, opts...)
+}
+
+func (im *impl) SearchCollections(c ctx.Ctx, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error) {
+	return im.searchOne(c, search.Collection, keyword, opts...)
+}
+
+func (im *impl) SearchTokens(c ctx.Ctx, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error) {
+	return im.searchOne(c, search.Token, keyword, opts...)
+}
+
+func (im *impl) searchOne(c ctx.Ctx, kind, keyword string, opts ...search.SearchOptionsFunc) (*search.Result, error) {
+	options, err := search.GetSearchOptions(opts...)
 	if err != nil {
-		c.WithField("err", err).Error("q.Pipe failed")
 		return nil, err
 	}
-	defer close()
-	if err := iter.All(c, &accs); err != nil {
-		c.WithField("err", err).Error("iter.Cursor.All failed")
+
+	res, err := im.searchKind(c, kind, keyword, options)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":  err,
+			"kind": kind,
+		}).Error("searchKind failed")
 		return nil, err
 	}
 
-	for _, acc := range accs {
-		res.Accounts = append(res.Accounts, acc.ToSimpleAccount())
+	return res, nil
+}
+
+func (im *impl) searchKind(c ctx.Ctx, kind, keyword string, options search.SearchOptions) (*search.Result, error) {
+	var (
+		limit  int
+		facets []string
+	)
+
+	switch kind {
+	case search.Account:
+		limit = maxAccounts
+		facets = []string{search.FacetKind}
+		im.resolveEnsKeyword(c, keyword)
+	case search.Collection:
+		limit = maxCollections
+		facets = []string{search.FacetKind, search.FacetChainId, search.FacetCategory, search.FacetVerified}
+	case search.Token:
+		limit = maxTokens
+		facets = []string{search.FacetKind, search.FacetChainId, search.FacetCollection, search.FacetVerified}
+	default:
+		return nil, domain.ErrBadParamInput
+	}
+
+	if options.Limit != nil {
+		limit = *options.Limit
+	}
+
+	fopts := []fulltext.SearchOptions{
+		fulltext.WithKinds(kind),
+		fulltext.WithPagination(options.Offset, limit),
+		fulltext.WithFacets(facets...),
+	}
+
+	if kind != search.Account {
+		if options.ChainId != nil {
+			fopts = append(fopts, fulltext.WithFilter(search.FacetChainId, strconv.Itoa(int(*options.ChainId))))
+		}
+		for _, col := range options.Collections {
+			fopts = append(fopts, fulltext.WithFilter(search.FacetCollection, col.ToLowerStr()))
+		}
+	}
+
+	if kind == search.Collection && options.Category != nil {
+		fopts = append(fopts, fulltext.WithFilter(search.FacetCategory, *options.Category))
+	}
+
+	hits, err := im.getIndex().Search(keyword, fopts...)
+	if err != nil {
+		c.WithField("err", err).Error("index.Search failed")
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		_, key := fromDocId(hit.Id)
+		keys = append(keys, key)
+	}
+
+	res := &search.Result{Count: hits.Total, Facets: hits.Facets}
+
+	switch kind {
+	case search.Account:
+		res.Accounts, err = im.getAccounts(c, keys)
+	case search.Collection:
+		res.Collections, err = im.getCollections(c, keys)
+	case search.Token:
+		res.Tokens, err = im.getTokens(c, keys)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return res, nil
 }
 
-func (im *impl) SearchCollections(c ctx.Ctx, keyword string, collections []domain.Address) (*search.Result, error) {
-	res := &search.Result{}
-	var searchStage bson.D
-	mustCond := bson.A{
-		bson.M{"equals": bson.M{"path": "isAppropriate", "value": true}},
-		bson.M{"equals": bson.M{"path": "status", "value": true}},
-	}
-	shouldCond := bson.A{
-		bson.M{"text": bson.M{
-			"path":  []string{"collectionName", "erc721Address", "description"},
-			"query": keyword,
-			"fuzzy": bson.M{"maxEdits": 1},
-		}},
-	}
-	filterCond := bson.A{
-		bson.M{"text": bson.M{
-			"path":  []string{"erc721Address"},
-			"query": collections,
-		}},
-	}
-	if len(collections) == 0 {
-		searchStage = bson.D{{"$search", bson.M{
-			"compound": bson.M{
-				"must":   mustCond,
-				"should": shouldCond,
-			},
-		}}}
-	} else {
-		searchStage = bson.D{{"$search", bson.M{
-			"compound": bson.M{
-				"must":   mustCond,
-				"should": shouldCond,
-				"filter": filterCond,
-			},
-		}}}
-	}
-	limitStage := bson.D{{"$limit", maxCollections}}
-	visibilityStage := bson.D{{"$project", bson.D{
-		{"chainId", 1}, {"erc721Address", 1}, {"owner", 1},
-		{"collectionName", 1}, {"description", 1}, {"categories", 1}, {"logoImageHash", 1}, {"logoImageUrl", 1},
-		{"coverImageHash", 1}, {"coverImageUrl", 1}, {"siteUrl", 1}, {"discord", 1}, {"twitterHandle", 1}, {"instagramHandle", 1},
-		{"mediumHandle", 1}, {"telegram", 1}, {"isVerified", 1}, {"royalty", 1}, {"feeRecipient", 1}, {"isRegistered", 1},
-		{"supply", 1}, {"attributes", 1}, {"numOwners", 1}, {"totalVolume", 1}, {"floorPrice", 1}, {"usdFloorPrice", 1},
-		{"hasFloorPrice", 1}, {"highestSale", 1}, {"highestSaleInUsd", 1}, {"lastSoldAt", 1}, {"hasBeenSold", 1},
-		{"lastListedAt", 1}, {"hasBeenListed", 1}, {"viewCount", 1}, {"liked", 1},
-		{"score", bson.M{"$meta": "searchScore"}}}}}
-	iter, close, err := im.q.Pipe(c, domain.TableCollections, mongo.Pipeline{searchStage, limitStage, visibilityStage})
+// getAccounts returns accounts in the order of keys
+func (im *impl) getAccounts(c ctx.Ctx, keys []string) ([]*account.SimpleAccount, error) {
+	res := []*account.SimpleAccount{}
+	if len(keys) == 0 {
+		return res, nil
+	}
+
+	addresses := make([]domain.Address, 0, len(keys))
+	for _, k := range keys {
+		addresses = append(addresses, domain.Address(k))
+	}
+
+	accs, err := im.account.GetAccounts(c, addresses)
 	if err != nil {
-		c.WithField("err", err).Error("q.Pipe failed")
+		c.WithField("err", err).Error("account.GetAccounts failed")
 		return nil, err
 	}
-	defer close()
-	if err := iter.All(c, &res.Collections); err != nil {
-		c.WithField("err", err).Error("iter.Cursor.All failed")
-		return nil, err
+
+	m := map[string]*account.Account{}
+	for _, acc := range accs {
+		m[acc.Address.ToLowerStr()] = acc
+	}
+
+	for _, k := range keys {
+		if acc, ok := m[k]; ok {
+			res = append(res, acc.ToSimpleAccount())
+		}
 	}
 
 	return res, nil
 }
 
-func (im *impl) SearchTokens(c ctx.Ctx, keyword string, collections []domain.Address) (*search.Result, error) {
-	res := &search.Result{}
-	tkns := []*nftitem.NftItem{}
-
-	var searchStage bson.D
-	const zeroAddress = "0x0000000000000000000000000000000000000000"
-
-	mustCond := bson.M{"equals": bson.M{"path": "isAppropriate", "value": true}}
-	mustNotCond := bson.M{"text": bson.M{"path": "owner", "query": zeroAddress}}
-	shouldCond := bson.A{
-		bson.M{"text": bson.M{
-			"path":  []string{"name", "contractAddress"},
-			"query": keyword,
-			"fuzzy": bson.M{"maxEdits": 1},
-		}},
-	}
-
-	filterCond := bson.A{
-		bson.M{"text": bson.M{
-			"path":  []string{"contractAddress"},
-			"query": collections,
-		}},
-	}
-
-	if len(collections) == 0 {
-		searchStage = bson.D{{"$search", bson.M{
-			"compound": bson.M{
-				"must":    mustCond,
-				"mustNot": mustNotCond,
-				"should":  shouldCond,
-			},
-		}}}
-	} else {
-		searchStage = bson.D{{"$search", bson.M{
-			"compound": bson.M{
-				"must":    mustCond,
-				"mustNot": mustNotCond,
-				"should":  shouldCond,
-				"filter":  filterCond,
-			},
-		}}}
-	}
-
-	limitStage := bson.D{{"$limit", maxTokens}}
-	visibilityStage := bson.D{{"$project", bson.D{
-		{"chainId", 1}, {"contractAddress", 1}, {"tokenID", 1}, {"name", 1}, {"tokenURI", 1}, {"thumbnailPath", 1},
-		{"imagePath", 1}, {"imageURL", 1}, {"hostedImageURL", 1}, {"hostedTokenURI", 1}, {"_id", 0}, {"score", bson.M{"$meta": "searchScore"}},
-	},
-	}}
-	iter, close, err := im.q.Pipe(c, domain.TableNFTItems, mongo.Pipeline{searchStage, limitStage, visibilityStage})
+// getCollections returns collections in the order of keys
+func (im *impl) getCollections(c ctx.Ctx, keys []string) ([]*collection.Collection, error) {
+	res := []*collection.Collection{}
+	if len(keys) == 0 {
+		return res, nil
+	}
+
+	addresses := []domain.Address{}
+	for _, k := range keys {
+		_, address, err := collection.FromCollectionKey(k)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err": err,
+				"key": k,
+			}).Error("collection.FromCollectionKey failed")
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	cols, err := im.collection.FindAll(c, collection.WithAddresses(addresses))
 	if err != nil {
-		c.WithField("err", err).Error("q.Pipe failed")
+		c.WithField("err", err).Error("collection.FindAll failed")
 		return nil, err
 	}
 
-	defer close()
+	m := map[string]*collection.Collection{}
+	for _, col := range cols {
+		col.IsRegistered = true
+		m[collection.ToCollectionKey(col.ChainId, col.Erc721Address)] = col
+	}
+
+	for _, k := range keys {
+		if col, ok := m[k]; ok {
+			res = append(res, col)
+		}
+	}
+
+	return res, nil
+}
+
+// getTokens returns tokens in the order of keys
+func (im *impl) getTokens(c ctx.Ctx, keys []string) ([]*nftitem.SimpleNftItem, error) {
+	res := []*nftitem.SimpleNftItem{}
+	if len(keys) == 0 {
+		return res, nil
+	}
+
+	ids := []nftitem.Id{}
+	for _, k := range keys {
+		chainId, address, tokenId, err := token.FromTokenKey(k)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"err": err,
+				"key": k,
+			}).Error("token.FromTokenKey failed")
+			return nil, err
+		}
+		ids = append(ids, nftitem.Id{ChainId: chainId, ContractAddress: address, TokenId: tokenId})
+	}
 
-	if err := iter.All(c, &tkns); err != nil {
-		c.WithField("err", err).Error("iter.Cursor.All failed")
+	items, err := im.nftitem.FindAll(c, nftitem.WithNftitemIds(ids))
+	if err != nil {
+		c.WithField("err", err).Error("nftitem.FindAll failed")
 		return nil, err
 	}
 
-	for _, tkn := range tkns {
-		res.Tokens = append(res.Tokens, tkn.ToSimpleNftItem())
+	m := map[string]*nftitem.NftItem{}
+	for _, item := range items {
+		m[token.ToTokenKey(item.ChainId, item.ContractAddress, item.TokenId)] = item
 	}
+
+	for _, k := range keys {
+		if item, ok := m[k]; ok {
+			res = append(res, item.ToSimpleNftItem())
+		}
+	}
+
 	return res, nil
 }
+
+// resolveEnsKeyword indexes the owner of ens name in keyword, so that it is searchable before the next sync
+func (im *impl) resolveEnsKeyword(c ctx.Ctx, keyword string) {
+	name := strings.ToLower(strings.TrimSpace(keyword))
+	if im.ens == nil || !strings.HasSuffix(name, ".eth") {
+		return
+	}
+
+	address, err := im.ens.Resolve(c, name)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"err":  err,
+			"name": name,
+		}).Warn("ens.Resolve failed")
+		return
+	} else if address.IsEmpty() {
+		return
+	}
+
+	address = address.ToLower()
+	if known, ok := im.getEnsName(address); ok && known == name {
+		return
+	}
+
+	im.setEnsName(address, name)
+	if err := im.indexAccount(c, address, false); err != nil && err != domain.ErrNotFound {
+		c.WithFields(log.Fields{
+			"err":     err,
+			"address": address,
+		}).Warn("indexAccount failed")
+	}
+}
+
+func (im *impl) getIndex() fulltext.Index {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	return im.index
+}
+
+func (im *impl) getEnsName(address domain.Address) (string, bool) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	name, ok := im.ensNames[address]
+	return name, ok
+}
+
+func (im *impl) setEnsName(address domain.Address, name string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.ensNames[address] = name
+}
+
+func (im *impl) getCollectionInfo(chainId domain.ChainId, address domain.Address) *collectionInfo {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	return im.collections[collection.ToCollectionKey(chainId, address)]
+}
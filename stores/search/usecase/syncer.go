@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"time"
+
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain/search"
+)
+
+const defaultSyncInterval = time.Minute
+
+type IndexSyncerCfg struct {
+	Search search.Usecase
+	// Interval between syncs, defaultSyncInterval if zero
+	Interval time.Duration
+}
+
+// IndexSyncer keeps the embedded search index up to date by calling Search.Sync periodically
+type IndexSyncer struct {
+	search    search.Usecase
+	interval  time.Duration
+	stoppedCh chan interface{}
+}
+
+func NewIndexSyncer(cfg *IndexSyncerCfg) *IndexSyncer {
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultSyncInterval
+	}
+
+	return &IndexSyncer{
+		search:    cfg.Search,
+		interval:  interval,
+		stoppedCh: make(chan interface{}),
+	}
+}
+
+func (s *IndexSyncer) Start(ctx bCtx.Ctx) {
+	go s.loop(ctx)
+}
+
+func (s *IndexSyncer) Wait() {
+	<-s.stoppedCh
+}
+
+func (s *IndexSyncer) loop(ctx bCtx.Ctx) {
+	nextTick := time.Second * 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(s.stoppedCh)
+			return
+		case <-time.After(nextTick):
+			// search keeps serving the current index on failure, retry in next tick
+			if err := s.search.Sync(ctx); err != nil {
+				ctx.WithField("err", err).Error("search.Sync failed")
+			}
+			nextTick = s.interval
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/delivery"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/spam"
+	"github.com/x-xyz/goapi/middleware"
+	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
+)
+
+type handler struct {
+	spam spam.UseCase
+}
+
+func New(e *echo.Echo, spam spam.UseCase, authMiddleware *authMiddleware.AuthMiddleware) {
+	h := &handler{spam}
+
+	e.PUT("/collection/:chainId/:contract/spam-override", h.setOverride, middleware.IsValidAddress("contract"), authMiddleware.Auth(), authMiddleware.IsModerator())
+}
+
+// setOverride godoc
+//
+//	@Summary		Override the spam classification of a collection
+//	@Description	Mark a collection and its tokens as spam or not spam regardless of the classifier, or let the
+//	@Description	classifier decide again with none, moderator only
+//	@Tags			collections
+//	@Security		ApiKeyAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			chainId		path		int					true	"chain id"
+//	@Param			contract	path		string				true	"collection address"
+//	@Param			params		body		http.setOverride.params	true	"params"
+//	@Success		200			{object}	spam.Result
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/collection/{chainId}/{contract}/spam-override [put]
+func (h *handler) setOverride(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	type params struct {
+		ChainId  domain.ChainId `param:"chainId" json:"-"`
+		Contract domain.Address `param:"contract" json:"-"`
+		// Override is none, spam or notSpam
+		Override spam.Override `json:"override"`
+	}
+
+	p := params{}
+	if err := c.Bind(&p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.spam.SetOverride(ctx, p.ChainId, p.Contract, p.Override)
+	if errors.Is(err, spam.ErrInvalidOverride) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, domain.ErrNotFound) {
+		return delivery.MakeJsonResp(c, http.StatusNotFound, err)
+	} else if err != nil {
+		ctx.WithFields(log.Fields{
+			"params": p,
+			"err":    err,
+		}).Error("spam.SetOverride failed")
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/x-xyz/goapi/base/imagehash"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/spam"
+)
+
+const (
+	// a transaction sending tokens to at least airdropMinRecipients accounts is a mass airdrop
+	airdropMinRecipients = 20
+	// images are copies if their difference hashes differ in at most copycatImageDistance bits
+	copycatImageDistance = 6
+)
+
+// urlShorteners hide the real destination of links
+var urlShorteners = map[string]struct{}{
+	"bit.ly":      {},
+	"tinyurl.com": {},
+	"t.co":        {},
+	"goo.gl":      {},
+	"is.gd":       {},
+	"cutt.ly":     {},
+	"rebrand.ly":  {},
+	"shorturl.at": {},
+	"ow.ly":       {},
+	"rb.gy":       {},
+}
+
+// baitHostKeywords are common in domains of phishing sites luring holders of airdropped tokens
+var baitHostKeywords = []string{"claim", "airdrop", "reward", "voucher", "giveaway", "bonus", "free-mint", "freemint"}
+
+// baitNamePatterns match links and claim phrases in names of airdropped scam contracts luring holders to phishing
+// sites. a domain alone is not enough since many legit collections are named after their sites
+var baitNamePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`https?://`),
+	regexp.MustCompile(`\bwww\.`),
+	regexp.MustCompile(`\b(visit|go to|claim (at|on))\s+[a-z0-9-]+(\.[a-z0-9-]+)*\.[a-z]{2,}\b`),
+	regexp.MustCompile(`\bclaim (your|free|now)\b`),
+}
+
+// airdropSignals finds transactions sending tokens to many accounts, and whether any of the recipients
+// has ever transferred a token afterwards. holders of spam tokens leave them in their wallets
+func airdropSignals(activities []account.ActivityHistory) []spam.Signal {
+	recipientsByTx := map[domain.TxHash]map[domain.Address]struct{}{}
+	for _, a := range activities {
+		to := a.To.ToLower()
+		if to.IsEmpty() || to == domain.EmptyAddress {
+			continue
+		}
+		if _, ok := recipientsByTx[a.TxHash]; !ok {
+			recipientsByTx[a.TxHash] = map[domain.Address]struct{}{}
+		}
+		recipientsByTx[a.TxHash][to] = struct{}{}
+	}
+
+	recipients := map[domain.Address]struct{}{}
+	for _, rs := range recipientsByTx {
+		if len(rs) < airdropMinRecipients {
+			continue
+		}
+		for r := range rs {
+			recipients[r] = struct{}{}
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	signals := []spam.Signal{spam.SignalMassAirdrop}
+	for _, a := range activities {
+		if a.Type != account.ActivityHistoryTypeTransfer {
+			continue
+		}
+		if _, ok := recipients[a.Account.ToLower()]; ok {
+			return signals
+		}
+	}
+	return append(signals, spam.SignalNoHolderTransfers)
+}
+
+// isCopycatName checks if the name equals to a verified collection other than the contract itself
+func isCopycatName(name string, address domain.Address, verified []*collection.Collection) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	for _, v := range verified {
+		if !v.Erc721Address.Equals(address) && strings.EqualFold(strings.TrimSpace(v.CollectionName), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBaitName checks if the name links to a site or promises a claim
+func isBaitName(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range baitNamePatterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCopycatImage checks if the image hash is close to any of the hashes of verified collection logos
+func isCopycatImage(hash uint64, verifiedHashes []uint64) bool {
+	for _, h := range verifiedHashes {
+		if imagehash.Distance(hash, h) <= copycatImageDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// isSuspiciousUrl checks if the url is served from a raw ip address, a url shortener or a bait domain.
+// ipfs, arweave and data uris are never suspicious
+func isSuspiciousUrl(rawUrl string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if _, ok := urlShorteners[strings.TrimPrefix(host, "www.")]; ok {
+		return true
+	}
+	for _, keyword := range baitHostKeywords {
+		if strings.Contains(host, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSuspiciousItem(item *nftitem.NftItem) bool {
+	return isSuspiciousUrl(item.TokenUri) || isSuspiciousUrl(item.ImageUrl) || isSuspiciousUrl(item.AnimationUrl)
+}
+
+// itemSignals replaces the url signal of the collection, which is found from sampled items, with the one of the item
+func itemSignals(collectionSignals []spam.Signal, item *nftitem.NftItem) []spam.Signal {
+	signals := []spam.Signal{}
+	for _, s := range collectionSignals {
+		if s != spam.SignalSuspiciousUrl {
+			signals = append(signals, s)
+		}
+	}
+	if isSuspiciousItem(item) {
+		signals = append(signals, spam.SignalSuspiciousUrl)
+	}
+	return signals
+}
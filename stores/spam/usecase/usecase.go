@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/imagehash"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	"github.com/x-xyz/goapi/domain/collection"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/spam"
+	"github.com/x-xyz/goapi/service/chain/contract"
+	"github.com/x-xyz/goapi/service/query"
+)
+
+const (
+	// number of items checked for urls and transferability
+	itemSampleSize = 20
+	// number of latest transfers checked for airdrops
+	activitySampleSize = 1000
+	verifiedLimit      = 1000
+	itemPageSize       = 100
+)
+
+type UseCaseCfg struct {
+	CollectionRepo      collection.Repo
+	RegistrationRepo    collection.RegistrationRepo
+	NftitemRepo         nftitem.Repo
+	ActivityHistoryRepo account.ActivityHistoryRepo
+	WebResourceUC       domain.WebResourceUseCase
+	// Erc721Service checks if tokens can be transferred, the check is skipped if it's nil
+	Erc721Service contract.Erc721TransferContract
+}
+
+type impl struct {
+	collection      collection.Repo
+	registration    collection.RegistrationRepo
+	nftitem         nftitem.Repo
+	activityHistory account.ActivityHistoryRepo
+	webResource     domain.WebResourceUseCase
+	erc721Service   contract.Erc721TransferContract
+
+	// difference hashes of images by url, verified logos are hashed once
+	imageHashesMu sync.Mutex
+	imageHashes   map[string]uint64
+}
+
+func New(cfg *UseCaseCfg) spam.UseCase {
+	return &impl{
+		collection:      cfg.CollectionRepo,
+		registration:    cfg.RegistrationRepo,
+		nftitem:         cfg.NftitemRepo,
+		activityHistory: cfg.ActivityHistoryRepo,
+		webResource:     cfg.WebResourceUC,
+		erc721Service:   cfg.Erc721Service,
+		imageHashes:     map[string]uint64{},
+	}
+}
+
+func (im *impl) Classify(c ctx.Ctx, chainId domain.ChainId, address domain.Address) (*spam.Result, error) {
+	id := collection.CollectionId{ChainId: chainId, Address: address.ToLower()}
+	col, err := im.findCollection(c, id)
+	if err != nil {
+		return nil, err
+	}
+	return im.classify(c, id, col)
+}
+
+// classify evaluates the signals of the contract, col is nil if the contract isn't registered
+func (im *impl) classify(c ctx.Ctx, id collection.CollectionId, col *collection.Collection) (*spam.Result, error) {
+	logger := c.WithFields(log.Fields{
+		"chainId": id.ChainId,
+		"address": id.Address,
+	})
+
+	name, imageUrl, err := im.getProfile(c, id, col)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := im.nftitem.FindAll(c,
+		nftitem.WithChainId(id.ChainId),
+		nftitem.WithContractAddresses([]domain.Address{id.Address}),
+		nftitem.WithPagination(0, itemSampleSize),
+	)
+	if err != nil {
+		logger.WithField("err", err).Error("nftitem.FindAll failed")
+		return nil, err
+	}
+
+	activities, err := im.activityHistory.FindActivities(c,
+		account.ActivityHistoryWithCollection(id.ChainId, id.Address),
+		account.ActivityHistoryWithTypes(account.ActivityHistoryTypeTransfer, account.ActivityHistoryTypeMint),
+		account.ActivityHistoryWithPagination(0, activitySampleSize),
+	)
+	if err != nil {
+		logger.WithField("err", err).Error("activityHistory.FindActivities failed")
+		return nil, err
+	}
+
+	verified, err := im.collection.FindAll(c,
+		collection.WithChainId(id.ChainId),
+		collection.WithIsVerified(true),
+		collection.WithPagination(0, verifiedLimit),
+	)
+	if err != nil {
+		logger.WithField("err", err).Error("collection.FindAll failed")
+		return nil, err
+	}
+
+	signals := airdropSignals(activities)
+	if signals == nil {
+		signals = []spam.Signal{}
+	}
+
+	if isCopycatName(name, id.Address, verified) {
+		signals = append(signals, spam.SignalCopycatName)
+	}
+
+	if isBaitName(name) {
+		signals = append(signals, spam.SignalBaitName)
+	}
+
+	if imageUrl == "" && len(items) > 0 {
+		imageUrl = items[0].HostedImageUrl
+	}
+	if im.isCopycatImage(c, imageUrl, id.Address, verified) {
+		signals = append(signals, spam.SignalCopycatImage)
+	}
+
+	for _, item := range items {
+		if isSuspiciousItem(item) {
+			signals = append(signals, spam.SignalSuspiciousUrl)
+			break
+		}
+	}
+
+	if blocked, err := im.isTransferBlocked(c, items); err != nil {
+		logger.WithField("err", err).Warn("isTransferBlocked failed")
+	} else if blocked {
+		signals = append(signals, spam.SignalTransferBlocked)
+	}
+
+	return &spam.Result{Score: scoreOf(col, signals), Signals: signals}, nil
+}
+
+func (im *impl) Refresh(c ctx.Ctx, chainId domain.ChainId, address domain.Address) (*spam.Result, error) {
+	id := collection.CollectionId{ChainId: chainId, Address: address.ToLower()}
+	logger := c.WithFields(log.Fields{
+		"chainId": chainId,
+		"address": address,
+	})
+
+	col, err := im.findCollection(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := im.classify(c, id, col)
+	if err != nil {
+		return nil, err
+	}
+
+	if col != nil {
+		payload := collection.UpdatePayload{SpamScore: &res.Score, SpamSignals: res.Signals}
+		if err := im.collection.Update(c, id, payload); err != nil {
+			logger.WithField("err", err).Error("collection.Update failed")
+			return nil, err
+		}
+	} else if im.registration != nil {
+		if err := im.registration.PatchSpamScore(c, id, res.Score); err != nil && !query.IsNotFound(err) {
+			logger.WithField("err", err).Error("registration.PatchSpamScore failed")
+			return nil, err
+		}
+	}
+
+	if err := im.scoreItems(c, id, col, res.Signals); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (im *impl) SetOverride(c ctx.Ctx, chainId domain.ChainId, address domain.Address, override spam.Override) (*spam.Result, error) {
+	id := collection.CollectionId{ChainId: chainId, Address: address.ToLower()}
+	logger := c.WithFields(log.Fields{
+		"chainId":  chainId,
+		"address":  address,
+		"override": override,
+	})
+
+	if !override.IsValid() {
+		return nil, spam.ErrInvalidOverride
+	}
+
+	col, err := im.collection.FindOne(c, id)
+	if err != nil {
+		logger.WithField("err", err).Error("collection.FindOne failed")
+		return nil, err
+	}
+	col.SpamOverride = override
+
+	signals := col.SpamSignals
+	if signals == nil {
+		signals = []spam.Signal{}
+	}
+	res := &spam.Result{Score: scoreOf(col, signals), Signals: signals}
+	payload := collection.UpdatePayload{SpamScore: &res.Score, SpamOverride: override}
+	if err := im.collection.Update(c, id, payload); err != nil {
+		logger.WithField("err", err).Error("collection.Update failed")
+		return nil, err
+	}
+
+	if err := im.scoreItems(c, id, col, signals); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// findCollection returns nil if the contract isn't registered
+func (im *impl) findCollection(c ctx.Ctx, id collection.CollectionId) (*collection.Collection, error) {
+	col, err := im.collection.FindOne(c, id)
+	if query.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("collection.FindOne failed")
+		return nil, err
+	}
+	return col, nil
+}
+
+// scoreItems stores spam scores of all tokens of the contract, tokens of the same score are updated at once
+func (im *impl) scoreItems(c ctx.Ctx, id collection.CollectionId, col *collection.Collection, signals []spam.Signal) error {
+	logger := c.WithFields(log.Fields{
+		"chainId": id.ChainId,
+		"address": id.Address,
+	})
+
+	tokenIdsByScore := map[float64][]domain.TokenId{}
+	for offset := int32(0); ; offset += itemPageSize {
+		items, err := im.nftitem.FindAll(c,
+			nftitem.WithChainId(id.ChainId),
+			nftitem.WithContractAddresses([]domain.Address{id.Address}),
+			nftitem.WithSort("_id", domain.SortDirAsc),
+			nftitem.WithPagination(offset, itemPageSize),
+		)
+		if err != nil {
+			logger.WithField("err", err).Error("nftitem.FindAll failed")
+			return err
+		}
+
+		for _, item := range items {
+			score := scoreOf(col, itemSignals(signals, item))
+			if score != item.SpamScore {
+				tokenIdsByScore[score] = append(tokenIdsByScore[score], item.TokenId)
+			}
+		}
+
+		if len(items) < itemPageSize {
+			break
+		}
+	}
+
+	for score, tokenIds := range tokenIdsByScore {
+		score := score
+		if err := im.nftitem.PatchTokens(c, id.ChainId, id.Address, tokenIds, nftitem.PatchableNftItem{SpamScore: &score}); err != nil {
+			logger.WithFields(log.Fields{
+				"score": score,
+				"err":   err,
+			}).Error("nftitem.PatchTokens failed")
+			return err
+		}
+	}
+	return nil
+}
+
+// getProfile returns name and logo of the collection, or of the pending registration if the contract isn't registered
+func (im *impl) getProfile(c ctx.Ctx, id collection.CollectionId, col *collection.Collection) (string, string, error) {
+	if col != nil {
+		return col.CollectionName, col.LogoImageUrl, nil
+	}
+
+	if im.registration == nil {
+		return "", "", nil
+	}
+	reg, err := im.registration.FindOne(c, id)
+	if err == nil {
+		return reg.CollectionName, reg.LogoImageUrl, nil
+	} else if !query.IsNotFound(err) {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("registration.FindOne failed")
+		return "", "", err
+	}
+	return "", "", nil
+}
+
+func (im *impl) isCopycatImage(c ctx.Ctx, imageUrl string, address domain.Address, verified []*collection.Collection) bool {
+	if im.webResource == nil || imageUrl == "" {
+		return false
+	}
+
+	hash, ok := im.getImageHash(c, imageUrl)
+	if !ok {
+		return false
+	}
+
+	verifiedHashes := []uint64{}
+	for _, v := range verified {
+		if v.Erc721Address.Equals(address) || v.LogoImageUrl == "" {
+			continue
+		}
+		if h, ok := im.getImageHash(c, v.LogoImageUrl); ok {
+			verifiedHashes = append(verifiedHashes, h)
+		}
+	}
+	return isCopycatImage(hash, verifiedHashes)
+}
+
+// getImageHash fetches and hashes the image, failures are not cached so they're retried in the next classification
+func (im *impl) getImageHash(c ctx.Ctx, imageUrl string) (uint64, bool) {
+	im.imageHashesMu.Lock()
+	hash, ok := im.imageHashes[imageUrl]
+	im.imageHashesMu.Unlock()
+	if ok {
+		return hash, true
+	}
+
+	data, err := im.webResource.Get(c, imageUrl)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"url": imageUrl,
+			"err": err,
+		}).Warn("webResource.Get failed")
+		return 0, false
+	}
+	hash, err = imagehash.DHashBytes(data)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"url": imageUrl,
+			"err": err,
+		}).Info("imagehash.DHashBytes failed")
+		return 0, false
+	}
+
+	im.imageHashesMu.Lock()
+	im.imageHashes[imageUrl] = hash
+	im.imageHashesMu.Unlock()
+	return hash, true
+}
+
+// scoreOf scores signals of the contract, registered collections may be exempted or overridden
+func scoreOf(col *collection.Collection, signals []spam.Signal) float64 {
+	if col == nil {
+		return spam.Score(signals)
+	}
+	return spam.ScoreOf(signals, col.IsVerified, col.SpamOverride)
+}
+
+// isTransferBlocked simulates a transfer of an erc721 token by its owner. erc1155 contracts are not checked
+func (im *impl) isTransferBlocked(c ctx.Ctx, items []*nftitem.NftItem) (bool, error) {
+	if im.erc721Service == nil {
+		return false, nil
+	}
+
+	for _, item := range items {
+		if item.TokenType != domain.TokenType721 || item.Owner.IsEmpty() || item.Owner.Equals(domain.EmptyAddress) {
+			continue
+		}
+		tokenId, ok := new(big.Int).SetString(item.TokenId.String(), 10)
+		if !ok {
+			continue
+		}
+		ok, err := im.erc721Service.CanTransfer(c, int32(item.ChainId), item.ContractAddress.ToLowerStr(), item.Owner.ToLowerStr(), tokenId)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	bCtx "github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/account"
+	mAccount "github.com/x-xyz/goapi/domain/account/mocks"
+	"github.com/x-xyz/goapi/domain/collection"
+	mCollection "github.com/x-xyz/goapi/domain/collection/mocks"
+	mDomain "github.com/x-xyz/goapi/domain/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/domain/spam"
+	mContract "github.com/x-xyz/goapi/service/chain/contract/mocks"
+)
+
+var (
+	spamAddress     = domain.Address("0x3333333333333333333333333333333333333333")
+	verifiedAddress = domain.Address("0x4444444444444444444444444444444444444444")
+	airdropper      = domain.Address("0x5555555555555555555555555555555555555555")
+)
+
+type SpamSuite struct {
+	suite.Suite
+	ctx                 bCtx.Ctx
+	collectionRepo      *mCollection.Repo
+	registrationRepo    *mCollection.RegistrationRepo
+	nftitemRepo         *mNftitem.Repo
+	activityHistoryRepo *mAccount.ActivityHistoryRepo
+	webResource         *mDomain.WebResourceUseCase
+	erc721              *mContract.Erc721TransferContract
+}
+
+func TestSpamSuite(t *testing.T) {
+	suite.Run(t, new(SpamSuite))
+}
+
+func (s *SpamSuite) SetupTest() {
+	s.ctx = bCtx.Background()
+	s.collectionRepo = &mCollection.Repo{}
+	s.registrationRepo = &mCollection.RegistrationRepo{}
+	s.nftitemRepo = &mNftitem.Repo{}
+	s.activityHistoryRepo = &mAccount.ActivityHistoryRepo{}
+	s.webResource = &mDomain.WebResourceUseCase{}
+	s.erc721 = &mContract.Erc721TransferContract{}
+}
+
+func (s *SpamSuite) TearDownTest() {
+	s.collectionRepo.AssertExpectations(s.T())
+	s.registrationRepo.AssertExpectations(s.T())
+	s.nftitemRepo.AssertExpectations(s.T())
+	s.activityHistoryRepo.AssertExpectations(s.T())
+	s.webResource.AssertExpectations(s.T())
+	s.erc721.AssertExpectations(s.T())
+}
+
+func (s *SpamSuite) mockCollection(address domain.Address, col *collection.Collection) {
+	call := s.collectionRepo.On("FindOne", mock.Anything, collection.CollectionId{ChainId: 1, Address: address})
+	if col == nil {
+		call.Return(nil, domain.ErrNotFound).Once()
+		return
+	}
+	call.Return(col, nil).Once()
+}
+
+// mockSignals mocks the sampled items, latest transfers and verified collections of a classification
+func (s *SpamSuite) mockSignals(items []*nftitem.NftItem, activities []account.ActivityHistory, verified []*collection.Collection) {
+	s.nftitemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return(items, nil).Once()
+	s.activityHistoryRepo.On("FindActivities", mock.Anything,
+		mock.AnythingOfType("account.FindActivityHistoryOptions"),
+		mock.AnythingOfType("account.FindActivityHistoryOptions"),
+		mock.AnythingOfType("account.FindActivityHistoryOptions")).
+		Return(activities, nil).Once()
+	s.collectionRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("collection.FindAllOptions"),
+		mock.AnythingOfType("collection.FindAllOptions"),
+		mock.AnythingOfType("collection.FindAllOptions")).
+		Return(verified, nil).Once()
+}
+
+// mockItemPage mocks a page of items to be scored
+func (s *SpamSuite) mockItemPage(items []*nftitem.NftItem) {
+	s.nftitemRepo.On("FindAll", mock.Anything,
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc"),
+		mock.AnythingOfType("nftitem.FindAllOptionsFunc")).
+		Return(items, nil).Once()
+}
+
+func (s *SpamSuite) mockPatchTokens(tokenIds []domain.TokenId, score float64) {
+	s.nftitemRepo.On("PatchTokens", mock.Anything, domain.ChainId(1), spamAddress, tokenIds, mock.MatchedBy(func(v nftitem.PatchableNftItem) bool {
+		return *v.SpamScore == score
+	})).Return(nil).Once()
+}
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / w), uint8(y * 255 / h), 128, 255})
+		}
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func massAirdrop(recipients int) []account.ActivityHistory {
+	activities := []account.ActivityHistory{}
+	for i := 0; i < recipients; i++ {
+		activities = append(activities, account.ActivityHistory{
+			Type:    account.ActivityHistoryTypeTransfer,
+			Account: airdropper,
+			To:      domain.Address(fmt.Sprintf("0x%040x", i+1)),
+			TxHash:  "0xairdrop",
+		})
+	}
+	return activities
+}
+
+func TestAirdropSignals(t *testing.T) {
+	req := require.New(t)
+
+	req.Nil(airdropSignals(massAirdrop(airdropMinRecipients - 1)))
+
+	activities := massAirdrop(airdropMinRecipients)
+	req.Equal([]spam.Signal{spam.SignalMassAirdrop, spam.SignalNoHolderTransfers}, airdropSignals(activities))
+
+	// a recipient moves the token
+	activities = append(activities, account.ActivityHistory{
+		Type:    account.ActivityHistoryTypeTransfer,
+		Account: domain.Address(fmt.Sprintf("0x%040x", 1)),
+		To:      airdropper,
+		TxHash:  "0xmove",
+	})
+	req.Equal([]spam.Signal{spam.SignalMassAirdrop}, airdropSignals(activities))
+}
+
+func TestIsSuspiciousUrl(t *testing.T) {
+	req := require.New(t)
+
+	req.True(isSuspiciousUrl("http://192.168.1.1/metadata/1"))
+	req.True(isSuspiciousUrl("https://bit.ly/3abcde"))
+	req.True(isSuspiciousUrl("https://claim-apes.xyz/1.json"))
+	req.False(isSuspiciousUrl("https://api.coolcatsnft.com/cat/1"))
+	req.False(isSuspiciousUrl("ipfs://QmXoypizjW3WknFiJnKLwHCnL72vedxjQkDDP1mXWo6uco/1"))
+	req.False(isSuspiciousUrl("data:application/json;base64,e30="))
+	req.False(isSuspiciousUrl(""))
+}
+
+func TestIsBaitName(t *testing.T) {
+	req := require.New(t)
+
+	req.True(isBaitName("Claim your reward at apes.xyz"))
+	req.True(isBaitName("Visit bayc-airdrop.com"))
+	req.True(isBaitName("Claim at www.apes-drop.io"))
+	req.True(isBaitName("https://t.me/apes"))
+	req.True(isBaitName("CLAIM FREE MINT"))
+	req.False(isBaitName("Cool Cats"))
+	req.False(isBaitName("Reward Pass"))
+	req.False(isBaitName("Visitors of Imma Degen"))
+	req.False(isBaitName("Airdrop Hunters"))
+	req.False(isBaitName("apes.xyz"))
+	req.False(isBaitName("Claim Stakes"))
+	req.False(isBaitName(""))
+}
+
+func (s *SpamSuite) TestClassifyAndRefresh() {
+	logo := pngImage(s.T(), 90, 80)
+
+	bayc := &collection.Collection{ChainId: 1, Erc721Address: verifiedAddress, CollectionName: "Bored Ape Yacht Club", LogoImageUrl: "https://cdn/bayc.png", IsVerified: true}
+	copycat := &collection.Collection{ChainId: 1, Erc721Address: spamAddress, CollectionName: "bored ape yacht club", LogoImageUrl: "https://cdn/copy.png"}
+	items := []*nftitem.NftItem{}
+	for i := 0; i < itemPageSize+1; i++ {
+		items = append(items, &nftitem.NftItem{
+			ChainId:         1,
+			ContractAddress: spamAddress,
+			TokenId:         domain.TokenId(fmt.Sprint(i)),
+			TokenType:       domain.TokenType721,
+			Owner:           airdropper,
+			TokenUri:        "https://api.example.com/" + fmt.Sprint(i),
+		})
+	}
+	items[0].TokenUri = "http://10.0.0.1/0"
+
+	s.mockCollection(spamAddress, copycat)
+	s.mockSignals(items[:itemSampleSize], massAirdrop(airdropMinRecipients), []*collection.Collection{bayc})
+	s.webResource.On("Get", mock.Anything, "https://cdn/copy.png").Return(pngImage(s.T(), 180, 160), nil).Once()
+	s.webResource.On("Get", mock.Anything, "https://cdn/bayc.png").Return(logo, nil).Once()
+	s.erc721.On("CanTransfer", mock.Anything, int32(1), spamAddress.ToLowerStr(), airdropper.ToLowerStr(), big.NewInt(0)).Return(false, nil).Once()
+	uc := New(&UseCaseCfg{
+		CollectionRepo:      s.collectionRepo,
+		NftitemRepo:         s.nftitemRepo,
+		ActivityHistoryRepo: s.activityHistoryRepo,
+		WebResourceUC:       s.webResource,
+		Erc721Service:       s.erc721,
+	})
+
+	res, err := uc.Classify(s.ctx, 1, spamAddress)
+	s.Require().NoError(err)
+	s.ElementsMatch([]spam.Signal{
+		spam.SignalMassAirdrop,
+		spam.SignalNoHolderTransfers,
+		spam.SignalCopycatName,
+		spam.SignalCopycatImage,
+		spam.SignalSuspiciousUrl,
+		spam.SignalTransferBlocked,
+	}, res.Signals)
+	s.Equal(1.0, res.Score)
+
+	// the verified collection is neither a copy of itself nor airdropped
+	s.mockCollection(verifiedAddress, bayc)
+	s.mockSignals(nil, nil, []*collection.Collection{bayc})
+	s.webResource.On("Get", mock.Anything, "https://cdn/bayc.png").Return(logo, nil).Once()
+	uc = New(&UseCaseCfg{
+		CollectionRepo:      s.collectionRepo,
+		NftitemRepo:         s.nftitemRepo,
+		ActivityHistoryRepo: s.activityHistoryRepo,
+		WebResourceUC:       s.webResource,
+	})
+	res, err = uc.Classify(s.ctx, 1, verifiedAddress)
+	s.Require().NoError(err)
+	s.Empty(res.Signals)
+	s.Equal(0.0, res.Score)
+
+	// refresh stores scores to the collection and all pages of items
+	s.mockCollection(spamAddress, copycat)
+	s.mockSignals(items[:itemSampleSize], massAirdrop(airdropMinRecipients), []*collection.Collection{bayc})
+	var updated collection.UpdatePayload
+	s.collectionRepo.On("Update", mock.Anything, collection.CollectionId{ChainId: 1, Address: spamAddress}, mock.AnythingOfType("collection.UpdatePayload")).
+		Run(func(args mock.Arguments) { updated = args.Get(2).(collection.UpdatePayload) }).
+		Return(nil).Once()
+	s.mockItemPage(items[:itemPageSize])
+	s.mockItemPage(items[itemPageSize:])
+	// one update of all pages per score
+	var patched []domain.TokenId
+	var patchedScore float64
+	s.nftitemRepo.On("PatchTokens", mock.Anything, domain.ChainId(1), spamAddress, mock.AnythingOfType("[]domain.TokenId"), mock.AnythingOfType("nftitem.PatchableNftItem")).
+		Run(func(args mock.Arguments) {
+			patched = args.Get(3).([]domain.TokenId)
+			patchedScore = *args.Get(4).(nftitem.PatchableNftItem).SpamScore
+		}).
+		Return(nil).Once()
+	uc = New(&UseCaseCfg{
+		CollectionRepo:      s.collectionRepo,
+		NftitemRepo:         s.nftitemRepo,
+		ActivityHistoryRepo: s.activityHistoryRepo,
+	})
+	res, err = uc.Refresh(s.ctx, 1, spamAddress)
+	s.Require().NoError(err)
+	s.InDelta(1.0, res.Score, 1e-9)
+	s.InDelta(1.0, *updated.SpamScore, 1e-9)
+	s.Equal(res.Signals, updated.SpamSignals)
+	// copycat name and airdrop signals score all items as spam without the url of item 0
+	s.Len(patched, itemPageSize+1)
+	s.InDelta(1.0, patchedScore, 1e-9)
+}
+
+func (s *SpamSuite) TestVerifiedAndOverride() {
+	col := &collection.Collection{ChainId: 1, Erc721Address: spamAddress, CollectionName: "Claim your reward at apes.xyz", IsVerified: true}
+	items := []*nftitem.NftItem{
+		{ChainId: 1, ContractAddress: spamAddress, TokenId: "1", TokenUri: "https://bit.ly/3abcde", SpamScore: 0.8},
+		{ChainId: 1, ContractAddress: spamAddress, TokenId: "2"},
+	}
+	id := collection.CollectionId{ChainId: 1, Address: spamAddress}
+	uc := New(&UseCaseCfg{
+		CollectionRepo:      s.collectionRepo,
+		NftitemRepo:         s.nftitemRepo,
+		ActivityHistoryRepo: s.activityHistoryRepo,
+	})
+
+	// verified collections keep their signals but are never spam
+	s.mockCollection(spamAddress, col)
+	s.mockSignals(items, massAirdrop(airdropMinRecipients), []*collection.Collection{col})
+	s.collectionRepo.On("Update", mock.Anything, id, mock.MatchedBy(func(v collection.UpdatePayload) bool {
+		return *v.SpamScore == 0
+	})).Return(nil).Once()
+	s.mockItemPage(items)
+	s.mockPatchTokens([]domain.TokenId{"1"}, 0)
+	res, err := uc.Refresh(s.ctx, 1, spamAddress)
+	s.Require().NoError(err)
+	s.Contains(res.Signals, spam.SignalBaitName)
+	s.Equal(0.0, res.Score)
+	col.SpamSignals = res.Signals
+
+	// moderators override the exemption with the stored signals
+	s.mockCollection(spamAddress, col)
+	s.collectionRepo.On("Update", mock.Anything, id, mock.MatchedBy(func(v collection.UpdatePayload) bool {
+		return *v.SpamScore == 1 && v.SpamOverride == spam.OverrideSpam
+	})).Return(nil).Once()
+	s.mockItemPage(items)
+	s.mockPatchTokens([]domain.TokenId{"1", "2"}, 1)
+	res, err = uc.SetOverride(s.ctx, 1, spamAddress, spam.OverrideSpam)
+	s.Require().NoError(err)
+	s.Equal(1.0, res.Score)
+
+	_, err = uc.SetOverride(s.ctx, 1, spamAddress, "maybe")
+	s.ErrorIs(err, spam.ErrInvalidOverride)
+	s.mockCollection(verifiedAddress, nil)
+	_, err = uc.SetOverride(s.ctx, 1, verifiedAddress, spam.OverrideNone)
+	s.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (s *SpamSuite) TestRefreshDiscoveredContract() {
+	reg := &collection.Registration{ChainId: 1, Erc721Address: spamAddress, CollectionName: "Claim your reward at apes.xyz", Discovered: true}
+	id := collection.CollectionId{ChainId: 1, Address: spamAddress}
+	s.mockCollection(spamAddress, nil)
+	s.registrationRepo.On("FindOne", mock.Anything, id).Return(reg, nil).Once()
+	s.mockSignals(nil, nil, nil)
+	s.registrationRepo.On("PatchSpamScore", mock.Anything, id, spam.Weights[spam.SignalBaitName]).Return(nil).Once()
+	s.mockItemPage(nil)
+	uc := New(&UseCaseCfg{
+		CollectionRepo:      s.collectionRepo,
+		RegistrationRepo:    s.registrationRepo,
+		NftitemRepo:         s.nftitemRepo,
+		ActivityHistoryRepo: s.activityHistoryRepo,
+	})
+
+	res, err := uc.Refresh(s.ctx, 1, spamAddress)
+	s.Require().NoError(err)
+	s.Equal([]spam.Signal{spam.SignalBaitName}, res.Signals)
+	// a bait name alone doesn't hide the contract
+	s.False(spam.IsSpam(spam.Weights[spam.SignalBaitName]))
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -13,9 +14,11 @@ import (
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/account"
 	"github.com/x-xyz/goapi/domain/like"
+	"github.com/x-xyz/goapi/domain/moderation"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
 	"github.com/x-xyz/goapi/domain/token"
+	"github.com/x-xyz/goapi/domain/unlockable"
 	"github.com/x-xyz/goapi/service/hyype"
 	authMiddleware "github.com/x-xyz/goapi/stores/auth/delivery/http/middleware"
 )
@@ -27,6 +30,7 @@ type handler struct {
 	folder      account.FolderUseCase
 	order       order.UseCase
 	hyypeClient hyype.Client
+	moderation  moderation.UseCase
 }
 
 func New(
@@ -38,8 +42,9 @@ func New(
 	order order.UseCase,
 	authMiddleware *authMiddleware.AuthMiddleware,
 	hyypeClient hyype.Client,
+	moderation moderation.UseCase,
 ) {
-	h := &handler{token, like, account, folder, order, hyypeClient}
+	h := &handler{token, like, account, folder, order, hyypeClient, moderation}
 
 	gs := e.Group("/tokens", authMiddleware.OptionalAuth())
 
@@ -51,11 +56,17 @@ func New(
 
 	gs.POST("/mark-private", h.markTokensPrivate, authMiddleware.Auth())
 
+	gs.POST("/unlockable-content/rotate-keys", h.rotateUnlockableKeys, authMiddleware.Auth(), authMiddleware.IsAdmin())
+
 	gs.GET("/order/:chainId/:orderHash", h.getOrder)
 
 	// NOTE: not sure need to auth or not
 	gs.POST("/make-order", h.makeOrder)
 
+	gs.POST("/quote-order", h.quoteOrder)
+
+	gs.POST("/sweep-quote", h.quoteSweep)
+
 	g := e.Group("/token/:chainId/:contract/:tokenId")
 
 	g.GET("", h.get, authMiddleware.OptionalAuth())
@@ -82,6 +93,8 @@ func New(
 
 	g.POST("/unlockable-content/reveal", h.getUnlockableContent, authMiddleware.Auth())
 
+	g.GET("/unlockable-content/access-logs", h.getUnlockableAccessLogs, authMiddleware.Auth())
+
 	g.POST("/refresh-metadata", h.refreshMetadata)
 
 	g.GET("/score", h.getOpenrarityScore)
@@ -163,6 +176,8 @@ func (h *handler) Search(c echo.Context) error {
 
 	// usage
 	// attrFilters={"name":"address","values":["0x35bcf180358e74d09dfe6c96f6ddc74262be506e","b"]}&attrFilters={"name":"recipient","values":["0x35bcf180358e74d09dfe6c96f6ddc74262be506e","d"]}
+	// range of number and date traits
+	// attrFilters={"name":"Level","min":10,"max":20}&attrFilters={"name":"Birthday","after":"2022-01-01T00:00:00Z"}
 	if len(p.AttrFilters) > 0 {
 		attrs := []nftitem.AttributeFilter{}
 		for _, af := range p.AttrFilters {
@@ -203,13 +218,17 @@ func (h *handler) Search(c echo.Context) error {
 //	@Produce		json
 //	@Param			offset			query		int			false	"paging offset"
 //	@Param			limit			query		int			false	"paging size"						example(100)
+//	@Param			cursor			query		string		false	"paging cursor, empty for the first page. offset and limit are ignored if given"
+//	@Param			size			query		int			false	"cursor paging size, default 10"
 //	@Param			sortBy			query		string		false	"NFT sorting rule"					Enums(price_low_to_high, price_high_to_low, offer_price_low_to_high, offer_price_high_to_low)	example(price_low_to_high)
 //	@Param			saleStatus		query		[]string	false	"Filter with specific order type"	enums(buynow, hasoffer)																			example(buynow)	collectionFormat(multi)
 //	@Param			chainId			query		int			false	"chain id. e.g: `1` for ethereum"	example(1)
 //	@Param			collections		query		string		false	"NFT collection contract address"	example(0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d)
 //	@Param			includeOrders	query		bool		false	"determining if order information should be included in the response."
+//	@Param			includeSpam		query		bool		false	"include tokens classified as spam, hidden by default"
 //	@Param			belongsTo		query		string		false	"NFT belongs to owner address"	example(0xed2ab4948bA6A909a7751DEc4F34f303eB8c7236)
 //	@Param			offerOwners		query		string		false	"Get NFT with offer owner"		example(0x020ca66c30bec2c4fe3861a94e4db4a498a35872)
+//	@Param			attrFilters		query		[]string	false	"trait filters in JSON, matching values or range of number and date traits"	example({"name":"Level","min":10,"max":20})	collectionFormat(multi)
 //	@Success		200				{object}	token.SearchResult
 //	@Failure		400
 //	@Failure		404
@@ -341,12 +360,18 @@ func (h *handler) SearchV2(c echo.Context) error {
 		opts = append(opts, token.WithIncludeInactiveOrders(*p.IncludeInactiveOrders))
 	}
 
+	if p.IncludeSpam == nil || !*p.IncludeSpam {
+		opts = append(opts, token.WithIsSpam(false))
+	}
+
 	if len(p.OfferOwners) > 0 {
 		opts = append(opts, token.WithOfferOwners(p.OfferOwners...))
 	}
 
 	// usage
 	// attrFilters={"name":"address","values":["0x35bcf180358e74d09dfe6c96f6ddc74262be506e","b"]}&attrFilters={"name":"recipient","values":["0x35bcf180358e74d09dfe6c96f6ddc74262be506e","d"]}
+	// range of number and date traits
+	// attrFilters={"name":"Level","min":10,"max":20}&attrFilters={"name":"Birthday","after":"2022-01-01T00:00:00Z"}
 	if len(p.AttrFilters) > 0 {
 		attrs := []nftitem.AttributeFilter{}
 		for _, af := range p.AttrFilters {
@@ -379,7 +404,9 @@ func (h *handler) SearchV2(c echo.Context) error {
 
 	res, err := h.token.SearchV2(ctx, opts...)
 
-	if err != nil {
+	if err == domain.ErrInvalidCursor {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
@@ -441,10 +468,9 @@ func (h *handler) get(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	}
 
-	if _, err := h.token.GetUnlockableContent(ctx, id); err == domain.ErrNotFound {
-		res.HasUnlockable = ptr.Bool(false)
-	} else {
-		res.HasUnlockable = ptr.Bool(true)
+	// the token is still served if unlockable content isn't available
+	if hasUnlockable, err := h.token.HasUnlockableContent(ctx, id); err == nil {
+		res.HasUnlockable = ptr.Bool(hasUnlockable)
 	}
 
 	if !user.IsEmpty() {
@@ -470,6 +496,7 @@ func (h *handler) get(c echo.Context) error {
 //	@Param			tokenId		path		string	true	"token id"			example(6969)
 //	@Param			offset		query		int		false	"paging offset"
 //	@Param			limit		query		int		false	"paging size"	example(100)
+//	@Param			cursor		query		string	false	"paging cursor, empty for the first page. offset is ignored if given"
 //	@Success		200			{object}	token.ActivityResult
 //	@Failure		400
 //	@Failure		404
@@ -484,6 +511,7 @@ func (h *handler) getActivities(c echo.Context) error {
 		TokenId  domain.TokenId `param:"tokenId"`
 		Offset   int            `query:"offset"`
 		Limit    int            `query:"limit"`
+		Cursor   *string        `query:"cursor"`
 	}
 
 	p := params{}
@@ -498,7 +526,17 @@ func (h *handler) getActivities(c echo.Context) error {
 		p.Limit = 5
 	}
 
-	if res, err := h.token.GetActivities(ctx, id, p.Offset, p.Limit); err != nil {
+	opts := []account.FindActivityHistoryOptions{
+		account.ActivityHistoryWithPagination(p.Offset, p.Limit),
+	}
+
+	if p.Cursor != nil {
+		opts = append(opts, account.ActivityHistoryWithCursor(*p.Cursor))
+	}
+
+	if res, err := h.token.GetActivities(ctx, id, opts...); err == domain.ErrInvalidCursor {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
@@ -661,6 +699,7 @@ func (h *handler) ban(c echo.Context) error {
 		Contract  domain.Address `param:"contract"`
 		TokenId   domain.TokenId `param:"tokenId"`
 		Signature string         `json:"signature"`
+		Reason    string         `json:"reason"`
 	}
 
 	p := &params{}
@@ -677,9 +716,9 @@ func (h *handler) ban(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
 	}
 
-	id := nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
+	target := moderation.Target{Type: moderation.TargetTypeToken, ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
 
-	if err := h.token.BanNftItem(ctx, id); err != nil {
+	if err := h.moderation.Ban(ctx, address, target, p.Reason); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusAccepted, nil)
@@ -692,6 +731,7 @@ func (h *handler) unban(c echo.Context) error {
 		Contract  domain.Address `param:"contract"`
 		TokenId   domain.TokenId `param:"tokenId"`
 		Signature string         `json:"signature"`
+		Reason    string         `json:"reason"`
 	}
 
 	p := &params{}
@@ -708,9 +748,9 @@ func (h *handler) unban(c echo.Context) error {
 		return delivery.MakeJsonResp(c, http.StatusMethodNotAllowed, err)
 	}
 
-	id := nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
+	target := moderation.Target{Type: moderation.TargetTypeToken, ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
 
-	if err := h.token.UnbanNftItem(ctx, id); err != nil {
+	if err := h.moderation.Unban(ctx, address, target, p.Reason); err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusAccepted, nil)
@@ -763,13 +803,63 @@ func (h *handler) getUnlockableContent(c echo.Context) error {
 
 	id := nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
 
-	if res, err := h.token.GetUnlockableContent(ctx, id); err != nil {
+	if res, err := h.token.RevealUnlockableContent(ctx, id, address); errors.Is(err, unlockable.ErrNotOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if errors.Is(err, unlockable.ErrEncryptionDisabled) {
+		return delivery.MakeJsonResp(c, http.StatusServiceUnavailable, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	} else {
+		return delivery.MakeJsonResp(c, http.StatusOK, res)
+	}
+}
+
+func (h *handler) getUnlockableAccessLogs(c echo.Context) error {
+	type params struct {
+		ChainId  domain.ChainId `param:"chainId"`
+		Contract domain.Address `param:"contract"`
+		TokenId  domain.TokenId `param:"tokenId"`
+		Offset   int32          `query:"offset"`
+		Limit    int32          `query:"limit"`
+	}
+
+	p := &params{Limit: unlockable.MaxAccessLogLimit}
+
+	if err := c.Bind(p); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	if p.Limit <= 0 || p.Limit > unlockable.MaxAccessLogLimit {
+		p.Limit = unlockable.MaxAccessLogLimit
+	}
+
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	address := c.Get("address").(domain.Address)
+
+	id := nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
+
+	if res, err := h.token.GetUnlockableAccessLogs(ctx, id, address, unlockable.AccessLogWithPagination(p.Offset, p.Limit)); errors.Is(err, unlockable.ErrNotCreator) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusOK, res)
 	}
 }
 
+func (h *handler) rotateUnlockableKeys(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	if count, err := h.token.RotateUnlockableKeys(ctx); errors.Is(err, unlockable.ErrEncryptionDisabled) {
+		return delivery.MakeJsonResp(c, http.StatusServiceUnavailable, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	} else {
+		return delivery.MakeJsonResp(c, http.StatusOK, count)
+	}
+}
+
 func (h *handler) addUnlockableContent(c echo.Context) error {
 	type params struct {
 		ChainId   domain.ChainId `param:"chainId"`
@@ -795,7 +885,11 @@ func (h *handler) addUnlockableContent(c echo.Context) error {
 
 	id := nftitem.Id{ChainId: p.ChainId, ContractAddress: p.Contract, TokenId: p.TokenId}
 
-	if err := h.token.AddUnlockableContent(ctx, id, p.Content); err != nil {
+	if err := h.token.AddUnlockableContent(ctx, id, address, p.Content); errors.Is(err, unlockable.ErrNotOwner) {
+		return delivery.MakeJsonResp(c, http.StatusForbidden, err)
+	} else if errors.Is(err, unlockable.ErrEncryptionDisabled) {
+		return delivery.MakeJsonResp(c, http.StatusServiceUnavailable, err)
+	} else if err != nil {
 		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
 	} else {
 		return delivery.MakeJsonResp(c, http.StatusCreated, nil)
@@ -883,6 +977,82 @@ func (h *handler) makeOrder(c echo.Context) error {
 	return delivery.MakeJsonResp(c, http.StatusOK, 1)
 }
 
+// quoteOrder godoc
+//
+//	@Summary		Quote fees of a draft order
+//	@Description	Returns the fee lines of each item of an unsigned order, including protocol fee, the burnt or donated
+//	@Description	share by feeDistType and royalties, and the net proceeds of the seller in payment token and USD.
+//	@Description	Warnings are returned if net proceeds fall below minPercentageToAsk or the strategy forbids the side.
+//	@Tags			tokens
+//	@Accept			json
+//	@Produce		json
+//	@Param			order	body		order.Order	true	"draft order, signature is not required"
+//	@Success		200		{object}	order.FeeQuote
+//	@Failure		400
+//	@Failure		500
+//	@Failure		503
+//	@Router			/tokens/quote-order [post]
+func (h *handler) quoteOrder(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	payload := struct {
+		Order order.Order `json:"order"`
+	}{}
+
+	if err := c.Bind(&payload); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+
+	res, err := h.order.QuoteFees(ctx, payload.Order)
+	if errors.Is(err, domain.ErrInvalidChainId) ||
+		errors.Is(err, domain.ErrInvalidCurrency) ||
+		errors.Is(err, domain.ErrInvalidNumberFormat) ||
+		errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if errors.Is(err, order.ErrFeeNotConfigured) {
+		return delivery.MakeJsonResp(c, http.StatusServiceUnavailable, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
+// quoteSweep godoc
+//
+//	@Summary		Quote a sweep of a collection
+//	@Description	Returns the cheapest fillable items of a collection across native asks and external listings,
+//	@Description	up to count items or the budget in native token, optionally filtered by attributes.
+//	@Description	Prices are converted to native token by the latest rates, the signer's own listings are skipped.
+//	@Tags			tokens
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	body		order.SweepParams	true	"collection, count or budget and attribute filters"
+//	@Success		200		{object}	order.SweepQuote
+//	@Failure		400
+//	@Failure		500
+//	@Router			/tokens/sweep-quote [post]
+func (h *handler) quoteSweep(c echo.Context) error {
+	ctx := c.Get("ctx").(ctx.Ctx)
+
+	params := order.SweepParams{}
+	if err := c.Bind(&params); err != nil {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	}
+	if address, ok := c.Get("address").(domain.Address); ok {
+		params.Buyer = address
+	}
+
+	res, err := h.order.QuoteSweep(ctx, params)
+	if errors.Is(err, domain.ErrInvalidNumberFormat) || errors.Is(err, domain.ErrBadParamInput) {
+		return delivery.MakeJsonResp(c, http.StatusBadRequest, err)
+	} else if err != nil {
+		return delivery.MakeJsonResp(c, http.StatusInternalServerError, err)
+	}
+
+	return delivery.MakeJsonResp(c, http.StatusOK, res)
+}
+
 // getOrder godoc
 //
 //	@Description	Get order information by order hash
@@ -11,17 +11,42 @@ import (
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/keys"
 	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/spam"
 	"github.com/x-xyz/goapi/service/cache"
 	compoundcache "github.com/x-xyz/goapi/service/cache/compoundCache"
 	"github.com/x-xyz/goapi/service/cache/provider/primitive"
 	redisCache "github.com/x-xyz/goapi/service/cache/provider/redis"
+	"github.com/x-xyz/goapi/service/keyset"
 	"github.com/x-xyz/goapi/service/query"
 	"github.com/x-xyz/goapi/service/redis"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const zeroAddress = "0x0000000000000000000000000000000000000000"
 
+func makeNumberRangeQuery(min, max *float64) bson.M {
+	res := bson.M{}
+	if min != nil {
+		res["$gte"] = *min
+	}
+	if max != nil {
+		res["$lte"] = *max
+	}
+	return res
+}
+
+func makeDateRangeQuery(after, before *time.Time) bson.M {
+	res := bson.M{}
+	if after != nil {
+		res["$gte"] = *after
+	}
+	if before != nil {
+		res["$lte"] = *before
+	}
+	return res
+}
+
 func makeFindQuery(opts nftitem.FindAllOptions) (query bson.M) {
 	query = bson.M{}
 	orQueries := bson.A{}
@@ -179,6 +204,14 @@ func makeFindQuery(opts nftitem.FindAllOptions) (query bson.M) {
 		query["hasOrder"] = *opts.HasOrder
 	}
 
+	if opts.IsSpam != nil {
+		if *opts.IsSpam {
+			query["spamScore"] = bson.M{"$gte": spam.Threshold}
+		} else {
+			query["spamScore"] = bson.M{"$not": bson.M{"$gte": spam.Threshold}}
+		}
+	}
+
 	// example:
 	// {
 	//    "contractAddress":"0xd03e287a677b015a649ef9fbd7267554fa4dd2d8",
@@ -227,6 +260,31 @@ func makeFindQuery(opts nftitem.FindAllOptions) (query bson.M) {
 					},
 				})
 			}
+			if attr.HasNumberRange() {
+				orExprs = append(orExprs, bson.M{
+					"attributes": bson.M{
+						"$elemMatch": bson.M{
+							"trait_type":  attr.Name,
+							"valueType":   nftitem.AttributeValueTypeNumber,
+							"numberValue": makeNumberRangeQuery(attr.Min, attr.Max),
+						},
+					},
+				})
+			}
+			if attr.HasDateRange() {
+				orExprs = append(orExprs, bson.M{
+					"attributes": bson.M{
+						"$elemMatch": bson.M{
+							"trait_type": attr.Name,
+							"valueType":  nftitem.AttributeValueTypeDate,
+							"dateValue":  makeDateRangeQuery(attr.After, attr.Before),
+						},
+					},
+				})
+			}
+			if len(orExprs) == 0 {
+				continue
+			}
 			andExprs = append(andExprs, bson.M{
 				"$or": orExprs,
 			})
@@ -316,10 +374,6 @@ func (im *nftitemImpl) FindAll(c ctx.Ctx, optFns ...nftitem.FindAllOptionsFunc)
 
 	limit := int(0)
 
-	// default sort by -createdAt
-	// but there are some items without `createdAt` in dev db
-	sort := []string{"-_id"}
-
 	if opts.Offset != nil {
 		offset = int(*opts.Offset)
 	}
@@ -330,24 +384,22 @@ func (im *nftitemImpl) FindAll(c ctx.Ctx, optFns ...nftitem.FindAllOptionsFunc)
 
 	query := makeFindQuery(opts)
 
-	if opts.SortBy != nil && opts.SortDir != nil {
-		sort = []string{}
-		sortBy := *opts.SortBy
-		switch sortBy {
-		case "listedAt":
-			fallthrough
-		case "priceInUSD":
-			sort = append(sort, "-hasActiveListings")
-		}
-
-		if *opts.SortDir == domain.SortDirDesc {
-			sortBy = "-" + sortBy
+	sort := opts.SortFields()
+
+	if opts.Cursor != nil {
+		offset = 0
+		sort = keyset.Sorts(sort)
+		if *opts.Cursor != "" {
+			after, err := keyset.Query(*opts.Cursor, sort)
+			if err != nil {
+				c.WithFields(log.Fields{
+					"err":    err,
+					"cursor": *opts.Cursor,
+				}).Warn("keyset.Query failed")
+				return nil, err
+			}
+			query = bson.M{"$and": bson.A{query, after}}
 		}
-		sort = append(sort, sortBy, "-_id")
-	}
-
-	if opts.Sorts != nil {
-		sort = *opts.Sorts
 	}
 
 	res := []*nftitem.NftItem{}
@@ -381,6 +433,62 @@ func (im *nftitemImpl) Count(c ctx.Ctx, optFns ...nftitem.FindAllOptionsFunc) (i
 	}
 }
 
+func (im *nftitemImpl) CountByOwners(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, owners []domain.Address) (map[domain.Address]int64, error) {
+	res := map[domain.Address]int64{}
+	if len(owners) == 0 {
+		return res, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"chainId": chainId, "contractAddress": contract.ToLower(), "owner": bson.M{"$in": owners}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$owner", "count": bson.M{"$sum": 1}}}},
+	}
+	iter, close, err := im.q.Pipe(c, domain.TableNFTItems, pipeline)
+	if err != nil {
+		c.WithField("err", err).Error("q.Pipe failed")
+		return nil, err
+	}
+	defer close()
+
+	var result []struct {
+		Owner domain.Address `bson:"_id"`
+		Count int64          `bson:"count"`
+	}
+	if err := iter.All(c, &result); err != nil {
+		c.WithField("err", err).Error("iter.All failed")
+		return nil, err
+	}
+	for _, r := range result {
+		res[r.Owner] = r.Count
+	}
+	return res, nil
+}
+
+// NextCursor encodes the stored document instead of NftItem so that missing sort fields are encoded as null
+func (im *nftitemImpl) NextCursor(c ctx.Ctx, last nftitem.Id, optFns ...nftitem.FindAllOptionsFunc) (string, error) {
+	opts, err := nftitem.GetFindAllOptions(optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("nftitem.GetFindAllOptions failed")
+		return "", err
+	}
+
+	var doc bson.Raw
+	if err := im.q.FindOne(c, domain.TableNFTItems, bson.M{
+		"chainId":         last.ChainId,
+		"contractAddress": last.ContractAddress,
+		"tokenID":         last.TokenId,
+	}, &doc); errors.Is(err, query.ErrNotFound) {
+		return "", domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"err":  err,
+			"last": last,
+		}).Error("q.FindOne failed")
+		return "", err
+	}
+
+	return keyset.Encode(keyset.Sorts(opts.SortFields()), doc)
+}
+
 func (im *nftitemImpl) FindOne(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, tokenId domain.TokenId) (*nftitem.NftItem, error) {
 	key := keys.RedisKey(strconv.Itoa(int(chainId)), string(contract), string(tokenId))
 
@@ -454,6 +562,79 @@ func (im *nftitemImpl) Patch(c ctx.Ctx, id nftitem.Id, value nftitem.PatchableNf
 	return nil
 }
 
+func (im *nftitemImpl) BulkPatch(c ctx.Ctx, patches []nftitem.PatchOp) error {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	ops := make([]query.UpsertOp, 0, len(patches))
+	for _, p := range patches {
+		val, err := mongoclient.MakeBsonM(p.Value)
+		if err != nil {
+			c.WithField("err", err).Error("mongoclient.MakeBsonM for value failed")
+			return err
+		}
+		ops = append(ops, query.UpsertOp{
+			Selector: bson.M{
+				"chainId":         p.Id.ChainId,
+				"contractAddress": p.Id.ContractAddress,
+				"tokenID":         p.Id.TokenId,
+			},
+			Updater: val,
+		})
+	}
+
+	if _, _, err := im.q.BulkPatch(c, domain.TableNFTItems, ops); err != nil {
+		c.WithField("err", err).Error("q.BulkPatch failed")
+		return err
+	}
+
+	for _, p := range patches {
+		key := keys.RedisKey(strconv.Itoa(int(p.Id.ChainId)), string(p.Id.ContractAddress), string(p.Id.TokenId))
+		if err := im.nftitemCache.Del(c, key); err != nil {
+			c.WithFields(log.Fields{
+				"err": err,
+				"id":  p.Id,
+			}).Error("nftitemCache.Del failed")
+		}
+	}
+
+	return nil
+}
+
+func (im *nftitemImpl) PatchTokens(c ctx.Ctx, chainId domain.ChainId, contract domain.Address, tokenIds []domain.TokenId, value nftitem.PatchableNftItem) error {
+	if len(tokenIds) == 0 {
+		return nil
+	}
+
+	val, err := mongoclient.MakeBsonM(value)
+	if err != nil {
+		c.WithField("err", err).Error("mongoclient.MakeBsonM for value failed")
+		return err
+	}
+	selector := bson.M{
+		"chainId":         chainId,
+		"contractAddress": contract.ToLower(),
+		"tokenID":         bson.M{"$in": tokenIds},
+	}
+	if err := im.q.Patch(c, domain.TableNFTItems, selector, val, query.WithPatchMany(true)); err != nil && err != query.ErrNotFound {
+		c.WithField("err", err).Error("q.Patch failed")
+		return err
+	}
+
+	for _, tokenId := range tokenIds {
+		key := keys.RedisKey(strconv.Itoa(int(chainId)), contract.ToLowerStr(), string(tokenId))
+		if err := im.nftitemCache.Del(c, key); err != nil {
+			c.WithFields(log.Fields{
+				"err":     err,
+				"tokenId": tokenId,
+			}).Error("nftitemCache.Del failed")
+		}
+	}
+
+	return nil
+}
+
 func (im *nftitemImpl) IncreaseViewCount(c ctx.Ctx, id nftitem.Id, count int) (int32, error) {
 	res := &nftitem.NftItem{}
 
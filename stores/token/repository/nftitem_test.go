@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/x-xyz/goapi/base/ctx"
 	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/ptr"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/erc1155"
 	"github.com/x-xyz/goapi/domain/nftitem"
@@ -44,6 +45,9 @@ func (s *nftitemSuite) TestFindAll() {
 	mockOwner := "0x501fea3b37837cde179d1c38595ea6d590becf2e"
 	pastTime := time.Now().Add(-1 * time.Hour)
 	futureTime := time.Now().Add(1 * time.Hour)
+	oldBirthday := time.Unix(1500000000, 0).UTC()
+	midBirthday := time.Unix(1600000000, 0).UTC()
+	newBirthday := time.Unix(1700000000, 0).UTC()
 
 	cases := []struct {
 		name string
@@ -348,6 +352,72 @@ func (s *nftitemSuite) TestFindAll() {
 				},
 			},
 		},
+		{
+			name: "find by number trait range",
+			opts: []nftitem.FindAllOptionsFunc{
+				nftitem.WithAttributeFilters([]nftitem.AttributeFilter{
+					{Name: "Level", Min: ptr.Float64(10), Max: ptr.Float64(20)},
+				}),
+			},
+			data: []*nftitem.NftItem{
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "1",
+					Attributes:      nftitem.Attributes{{TraitType: "Level", Value: "10", DisplayType: nftitem.DisplayTypeNumber, ValueType: nftitem.AttributeValueTypeNumber, NumberValue: ptr.Float64(10)}},
+				},
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "2",
+					Attributes:      nftitem.Attributes{{TraitType: "Level", Value: "21", DisplayType: nftitem.DisplayTypeNumber, ValueType: nftitem.AttributeValueTypeNumber, NumberValue: ptr.Float64(21)}},
+				},
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "3",
+					Attributes:      nftitem.Attributes{{TraitType: "Rank", Value: "15", DisplayType: nftitem.DisplayTypeNumber, ValueType: nftitem.AttributeValueTypeNumber, NumberValue: ptr.Float64(15)}},
+				},
+			},
+			want: []*nftitem.NftItem{
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "1",
+					Attributes:      nftitem.Attributes{{TraitType: "Level", Value: "10", DisplayType: nftitem.DisplayTypeNumber, ValueType: nftitem.AttributeValueTypeNumber, NumberValue: ptr.Float64(10)}},
+				},
+			},
+		},
+		{
+			name: "find by date trait range",
+			opts: []nftitem.FindAllOptionsFunc{
+				nftitem.WithAttributeFilters([]nftitem.AttributeFilter{
+					{Name: "Birthday", After: &midBirthday, Before: &newBirthday},
+				}),
+			},
+			data: []*nftitem.NftItem{
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "1",
+					Attributes:      nftitem.Attributes{{TraitType: "Birthday", Value: "1500000000", DisplayType: nftitem.DisplayTypeDate, ValueType: nftitem.AttributeValueTypeDate, DateValue: &oldBirthday}},
+				},
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "2",
+					Attributes:      nftitem.Attributes{{TraitType: "Birthday", Value: "1700000000", DisplayType: nftitem.DisplayTypeDate, ValueType: nftitem.AttributeValueTypeDate, DateValue: &newBirthday}},
+				},
+			},
+			want: []*nftitem.NftItem{
+				{
+					ChainId:         1,
+					ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+					TokenId:         "2",
+					Attributes:      nftitem.Attributes{{TraitType: "Birthday", Value: "1700000000", DisplayType: nftitem.DisplayTypeDate, ValueType: nftitem.AttributeValueTypeDate, DateValue: &newBirthday}},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -386,6 +456,31 @@ func (s *nftitemSuite) TestFindAll() {
 	}
 }
 
+func (s *nftitemSuite) TestBulkPatch() {
+	ctx := ctx.Background()
+	s.query.RemoveAll(ctx, domain.TableNFTItems, bson.M{})
+
+	id := nftitem.Id{ChainId: 1, ContractAddress: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d", TokenId: "1"}
+	s.Require().NoError(s.query.Insert(ctx, domain.TableNFTItems, &nftitem.NftItem{
+		ChainId:         id.ChainId,
+		ContractAddress: id.ContractAddress,
+		TokenId:         id.TokenId,
+		Attributes:      nftitem.Attributes{{TraitType: "Level", Value: "10"}},
+	}))
+
+	attrs := nftitem.NormalizeAttributes(nftitem.Attributes{{TraitType: "Level", Value: "10"}})
+	s.Require().NoError(s.im.BulkPatch(ctx, []nftitem.PatchOp{
+		{Id: id, Value: nftitem.PatchableNftItem{Attributes: attrs}},
+		{Id: nftitem.Id{ChainId: 1, ContractAddress: id.ContractAddress, TokenId: "2"}, Value: nftitem.PatchableNftItem{Attributes: attrs}},
+	}))
+
+	res, err := s.im.FindAll(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(res, 1)
+	s.Equal(attrs, res[0].Attributes)
+	s.NoError(s.im.BulkPatch(ctx, nil))
+}
+
 type nftitemTestSuite struct {
 	suite.Suite
 	dbName      string
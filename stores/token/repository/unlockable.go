@@ -2,9 +2,12 @@ package repository
 
 import (
 	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/database/mongoclient"
+	"github.com/x-xyz/goapi/base/log"
 	"github.com/x-xyz/goapi/domain"
 	"github.com/x-xyz/goapi/domain/unlockable"
 	"github.com/x-xyz/goapi/service/query"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type unlockableImpl struct {
@@ -36,3 +39,105 @@ func (im *unlockableImpl) Create(c ctx.Ctx, val unlockable.Unlockable) error {
 
 	return nil
 }
+
+func (im *unlockableImpl) FindAllNotSealedBy(c ctx.Ctx, keyId string, limit int) ([]unlockable.Unlockable, error) {
+	res := []unlockable.Unlockable{}
+
+	if err := im.q.Search(c, domain.TableUnlockableContents, 0, limit, "", bson.M{"keyId": bson.M{"$ne": keyId}}, &res); err != nil {
+		c.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (im *unlockableImpl) FindAllPlaintext(c ctx.Ctx, limit int) ([]unlockable.Unlockable, error) {
+	res := []unlockable.Unlockable{}
+
+	if err := im.q.Search(c, domain.TableUnlockableContents, 0, limit, "", bson.M{"keyId": bson.M{"$in": bson.A{nil, ""}}}, &res); err != nil {
+		c.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (im *unlockableImpl) Update(c ctx.Ctx, id unlockable.UnlockableId, updater unlockable.Updater) error {
+	update, err := mongoclient.MakeBsonM(updater)
+	if err != nil {
+		c.WithField("err", err).Error("MakeBsonM failed")
+		return err
+	}
+
+	if err := im.q.Patch(c, domain.TableUnlockableContents, id, update); err == query.ErrNotFound {
+		return domain.ErrNotFound
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("q.Patch failed")
+		return err
+	}
+
+	return nil
+}
+
+type accessLogImpl struct {
+	q query.Mongo
+}
+
+func NewUnlockableAccessLog(q query.Mongo) unlockable.AccessLogRepo {
+	return &accessLogImpl{q}
+}
+
+func (im *accessLogImpl) FindAll(c ctx.Ctx, optFns ...unlockable.AccessLogFindAllOptionsFunc) ([]unlockable.AccessLog, error) {
+	opts, err := unlockable.GetAccessLogFindAllOptions(optFns...)
+	if err != nil {
+		c.WithField("err", err).Error("unlockable.GetAccessLogFindAllOptions failed")
+		return nil, err
+	}
+
+	selector, err := mongoclient.MakeBsonM(opts)
+	if err != nil {
+		c.WithField("err", err).Error("MakeBsonM failed")
+		return nil, err
+	}
+
+	var (
+		offset int    = 0
+		limit  int    = 0
+		sort   string = "-createdAt"
+	)
+	if opts.Offset != nil {
+		offset = int(*opts.Offset)
+	}
+	if opts.Limit != nil {
+		limit = int(*opts.Limit)
+	}
+	if opts.SortBy != nil && opts.SortDir != nil {
+		sort = *opts.SortBy
+		if *opts.SortDir == domain.SortDirDesc {
+			sort = "-" + sort
+		}
+	}
+
+	res := []unlockable.AccessLog{}
+	if err := im.q.Search(c, domain.TableUnlockableAccessLogs, offset, limit, sort, selector, &res); err != nil {
+		c.WithField("err", err).Error("q.Search failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (im *accessLogImpl) Insert(c ctx.Ctx, accessLog *unlockable.AccessLog) error {
+	if err := im.q.Insert(c, domain.TableUnlockableAccessLogs, accessLog); err != nil {
+		c.WithFields(log.Fields{
+			"accessLog": accessLog,
+			"err":       err,
+		}).Error("q.Insert failed")
+		return err
+	}
+
+	return nil
+}
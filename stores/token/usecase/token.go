@@ -3,6 +3,7 @@ package usecase
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"sort"
 	"time"
 
@@ -14,15 +15,16 @@ import (
 	"github.com/x-xyz/goapi/domain/collection"
 	"github.com/x-xyz/goapi/domain/erc1155"
 	"github.com/x-xyz/goapi/domain/file"
-	"github.com/x-xyz/goapi/domain/keys"
+	"github.com/x-xyz/goapi/domain/lazymint"
 	"github.com/x-xyz/goapi/domain/like"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	"github.com/x-xyz/goapi/domain/order"
+	"github.com/x-xyz/goapi/domain/royalty"
+	"github.com/x-xyz/goapi/domain/search"
 	"github.com/x-xyz/goapi/domain/token"
 	"github.com/x-xyz/goapi/domain/unlockable"
-	"github.com/x-xyz/goapi/service/paging"
+	"github.com/x-xyz/goapi/service/envelope"
 	"github.com/x-xyz/goapi/service/pinata"
-	"github.com/x-xyz/goapi/service/redis"
 )
 
 type TokenUseCaseCfg struct {
@@ -30,28 +32,38 @@ type TokenUseCaseCfg struct {
 	NftitemRepo        nftitem.Repo
 	CollectionRepo     collection.Repo
 	UnlockableRepo     unlockable.Repo
+	UnlockableKeyring  *envelope.Keyring
+	UnlockableLogRepo  unlockable.AccessLogRepo
 	FileUC             file.Usecase
 	IpfsUri            string
 	ActivityRepo       account.ActivityHistoryRepo
 	FolderRelationRepo account.FolderNftRelationshipRepo
 	Erc1155HoldingRepo erc1155.HoldingRepo
 	OrderItemRepo      order.OrderItemRepo
-	Redis              redis.Service
+	SearchIndexer      search.Indexer
+	RoyaltyUC          royalty.UseCase
+	VoucherRepo        lazymint.VoucherRepo
 }
 
+// defaultCursorSize is the page size of cursor paging if size is not given
+const defaultCursorSize = 10
+
 type impl struct {
 	like               like.Repo
 	nftitem            nftitem.Repo
 	collection         collection.Repo
 	unlockable         unlockable.Repo
+	unlockableKeyring  *envelope.Keyring
+	unlockableLog      unlockable.AccessLogRepo
 	file               file.Usecase
 	ipfsUri            string
 	activity           account.ActivityHistoryRepo
 	folderRelationRepo account.FolderNftRelationshipRepo
 	erc1155Holding     erc1155.HoldingRepo
 	orderItemRepo      order.OrderItemRepo
-
-	searchV2Paging paging.Service
+	searchIdx          search.Indexer
+	royalty            royalty.UseCase
+	voucher            lazymint.VoucherRepo
 }
 
 func New(cfg *TokenUseCaseCfg) token.Usecase {
@@ -60,23 +72,17 @@ func New(cfg *TokenUseCaseCfg) token.Usecase {
 		nftitem:            cfg.NftitemRepo,
 		collection:         cfg.CollectionRepo,
 		unlockable:         cfg.UnlockableRepo,
+		unlockableKeyring:  cfg.UnlockableKeyring,
+		unlockableLog:      cfg.UnlockableLogRepo,
 		file:               cfg.FileUC,
 		ipfsUri:            cfg.IpfsUri,
 		activity:           cfg.ActivityRepo,
 		folderRelationRepo: cfg.FolderRelationRepo,
 		erc1155Holding:     cfg.Erc1155HoldingRepo,
 		orderItemRepo:      cfg.OrderItemRepo,
-	}
-
-	if cfg.Redis != nil {
-		im.searchV2Paging = paging.New(&paging.PagingConfig{
-			RedisCache:    cfg.Redis,
-			KeyPfx:        keys.PfxSearchV2Paging,
-			RenewDuration: 10 * time.Second,
-			CacheDuration: 10 * time.Minute,
-			Getter:        im.searchV2Getter,
-			ShardSize:     100,
-		})
+		searchIdx:          cfg.SearchIndexer,
+		royalty:            cfg.RoyaltyUC,
+		voucher:            cfg.VoucherRepo,
 	}
 
 	return im
@@ -104,7 +110,7 @@ func getNftitemIdsIntersection(l1, l2 []nftitem.Id) []nftitem.Id {
 	return res
 }
 
-func (im *impl) searchV2(c ctx.Ctx, opts *token.SearchOptions, withCount bool) (*token.SearchResult, error) {
+func (im *impl) searchV2(c ctx.Ctx, opts *token.SearchOptions) (*token.SearchResult, error) {
 	findOpts := []nftitem.FindAllOptionsFunc{
 		nftitem.WithIndexerStates(nftitem.ReadyToServeIndexerStates),
 	}
@@ -121,6 +127,14 @@ func (im *impl) searchV2(c ctx.Ctx, opts *token.SearchOptions, withCount bool) (
 		findOpts = append(findOpts, nftitem.WithPagination(*opts.Offset, *opts.Limit))
 	}
 
+	size := defaultCursorSize
+	if opts.Cursor != nil {
+		if opts.Size != nil {
+			size = *opts.Size
+		}
+		findOpts = append(findOpts, nftitem.WithCursor(*opts.Cursor), nftitem.WithPagination(0, int32(size)))
+	}
+
 	if opts.ChainId != nil {
 		findOpts = append(findOpts, nftitem.WithChainId(*opts.ChainId))
 	}
@@ -252,6 +266,10 @@ func (im *impl) searchV2(c ctx.Ctx, opts *token.SearchOptions, withCount bool) (
 		findOpts = append(findOpts, nftitem.WithHasOrder(*opts.HasOrder))
 	}
 
+	if opts.IsSpam != nil {
+		findOpts = append(findOpts, nftitem.WithIsSpam(*opts.IsSpam))
+	}
+
 	folderIds := []nftitem.Id{}
 	if opts.FolderId != nil {
 		relations, err := im.folderRelationRepo.GetAllRelations(c, account.WithFolderId(*opts.FolderId))
@@ -357,43 +375,32 @@ func (im *impl) searchV2(c ctx.Ctx, opts *token.SearchOptions, withCount bool) (
 		details = append(details, &detail)
 	}
 
+	// items are counted on the first page only when paging by cursor
 	totalCnt := 0
-	if withCount {
-		cnt, err := im.nftitem.Count(c, findOpts...)
+	if opts.Cursor == nil || *opts.Cursor == "" {
+		totalCnt, err = im.nftitem.Count(c, findOpts...)
 		if err != nil {
 			c.WithFields(log.Fields{
 				"err": err,
 			}).Error("failed to nftitem.Count")
 			return nil, err
 		}
-		totalCnt = cnt
 	}
 
-	return &token.SearchResult{
-		Items: details,
-		Count: totalCnt,
-	}, nil
-}
-
-func (im *impl) searchV2Getter(c ctx.Ctx, key string) (interface{}, error) {
-	opts, err := token.ParseKeyToOptions(key)
-	if err != nil {
-		c.WithFields(log.Fields{
-			"err": err,
-			"key": key,
-		}).Error("failed to token.ParseKeyToOptions")
-		return nil, err
-	}
-
-	res, err := im.searchV2(c, opts, false)
-	if err != nil {
-		c.WithFields(log.Fields{
-			"err": err,
-		}).Error("failed to im.searchV2")
-		return nil, err
+	nextCursor := ""
+	if opts.Cursor != nil && len(items) == size {
+		nextCursor, err = im.nftitem.NextCursor(c, *items[len(items)-1].ToId(), findOpts...)
+		if err != nil {
+			c.WithField("err", err).Error("nftitem.NextCursor failed")
+			return nil, err
+		}
 	}
 
-	return res.Items, nil
+	return &token.SearchResult{
+		Items:      details,
+		Count:      totalCnt,
+		NextCursor: nextCursor,
+	}, nil
 }
 
 func (im *impl) SearchV2(c ctx.Ctx, optFns ...token.SearchOptionsFunc) (*token.SearchResult, error) {
@@ -403,35 +410,7 @@ func (im *impl) SearchV2(c ctx.Ctx, optFns ...token.SearchOptionsFunc) (*token.S
 		return nil, err
 	}
 
-	key := token.OptionsToKey(opts)
-
-	if im.searchV2Paging != nil && opts.Cursor != nil {
-		// if cursor exists, use paging service
-		cursor := *opts.Cursor
-		// size default set to 10
-		size := 10
-		if opts.Size != nil {
-			size = *opts.Size
-		}
-		details := []*token.TokenWithDetail{}
-
-		nextCursor, cnt, err := im.searchV2Paging.Get(c, key, cursor, size, &details)
-		if err != nil {
-			c.WithFields(log.Fields{
-				"err":  err,
-				"opts": opts,
-			}).Error("failed to searchV2Paging.Get")
-			return nil, err
-		}
-
-		res := &token.SearchResult{Count: cnt, NextCursor: nextCursor, Items: details}
-
-		return res, nil
-	} else {
-		// if searchV2Paging == nil, fall back to query db
-		// if cursor not exists, bypass caching
-		return im.searchV2(c, &opts, true)
-	}
+	return im.searchV2(c, &opts)
 }
 
 // getOffersByNftitemId returns offers, error
@@ -533,7 +512,33 @@ func (im *impl) FindOne(c ctx.Ctx, id nftitem.Id) (*token.TokenWithDetail, error
 		activeListing = listings[0]
 	}
 
-	return &token.TokenWithDetail{NftItem: *item, Listings: listings, Offers: offers, ActiveListing: activeListing}, nil
+	res := &token.TokenWithDetail{NftItem: *item, Listings: listings, Offers: offers, ActiveListing: activeListing}
+	im.quoteRoyalty(c, res)
+	return res, nil
+}
+
+// quoteRoyalty injects the royalty and quotes listings and offers with it, the token is served without royalty if
+// it's not resolvable
+func (im *impl) quoteRoyalty(c ctx.Ctx, t *token.TokenWithDetail) {
+	if im.royalty == nil {
+		return
+	}
+	r, err := im.royalty.Resolve(c, *t.ToId())
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  t.ToId(),
+			"err": err,
+		}).Warn("royalty.Resolve failed")
+		return
+	}
+	t.Royalty = r
+
+	orderItems := append(append([]*order.OrderItem{}, t.Listings...), t.Offers...)
+	for _, od := range orderItems {
+		if price, ok := new(big.Int).SetString(od.Price, 10); ok {
+			od.Royalty = r.Quote(price)
+		}
+	}
 }
 
 func (im *impl) getContractWhitelist(c ctx.Ctx, opts token.SearchOptions) ([]domain.Address, error) {
@@ -580,38 +585,12 @@ func (im *impl) getContractWhitelist(c ctx.Ctx, opts token.SearchOptions) ([]dom
 	return contractWhitelist, nil
 }
 
-func (im *impl) AddUnlockableContent(c ctx.Ctx, id nftitem.Id, content string) error {
-	v := unlockable.Unlockable{
-		ChainId:         id.ChainId,
-		ContractAddress: id.ContractAddress,
-		TokenId:         id.TokenId,
-		Content:         content,
-	}
-
-	if err := im.unlockable.Create(c, v); err != nil {
-		c.WithField("err", err).WithField("id", id).Error("unlockable.Create failed")
-		return err
-	}
-
-	return nil
-}
-
-func (im *impl) GetUnlockableContent(c ctx.Ctx, id nftitem.Id) (string, error) {
-	uid := unlockable.UnlockableId{ChainId: id.ChainId, ContractAddress: id.ContractAddress.ToLower(), TokenId: id.TokenId}
-
-	if res, err := im.unlockable.FindOne(c, uid); err != nil {
-		c.WithField("err", err).Error("unlockable.FindOne failed")
-		return "", err
-	} else {
-		return res.Content, nil
-	}
-}
-
 func (im *impl) BanNftItem(c ctx.Ctx, id nftitem.Id) error {
 	if err := im.nftitem.Patch(c, id, nftitem.PatchableNftItem{IsAppropriate: ptr.Bool(false)}); err != nil {
 		c.WithField("err", err).WithField("id", id).Error("nftitem.Patch failed")
 		return err
 	}
+	im.indexToken(c, id)
 	return nil
 }
 
@@ -620,6 +599,7 @@ func (im *impl) UnbanNftItem(c ctx.Ctx, id nftitem.Id) error {
 		c.WithField("err", err).WithField("id", id).Error("nftitem.Patch failed")
 		return err
 	}
+	im.indexToken(c, id)
 	return nil
 }
 
@@ -799,15 +779,18 @@ func (im *impl) ClearHighestBid(c ctx.Ctx, id nftitem.Id) error {
 	panic(domain.ErrDeprecated)
 }
 
-func (im *impl) GetActivities(c ctx.Ctx, id nftitem.Id, offset, limit int) (*token.ActivityResult, error) {
+func (im *impl) GetActivities(c ctx.Ctx, id nftitem.Id, optFns ...account.FindActivityHistoryOptions) (*token.ActivityResult, error) {
 	res := token.ActivityResult{}
 
-	items, err := im.activity.FindActivities(
-		c,
-		account.ActivityHistoryWithToken(id.ChainId, id.ContractAddress, id.TokenId),
-		account.ActivityHistoryWithPagination(offset, limit),
-		account.ActivityHistoryWithSource(account.SourceX),
+	opts := append(
+		[]account.FindActivityHistoryOptions{
+			account.ActivityHistoryWithToken(id.ChainId, id.ContractAddress, id.TokenId),
+			account.ActivityHistoryWithSource(account.SourceX),
+		},
+		optFns...,
 	)
+
+	items, err := im.activity.FindActivities(c, opts...)
 	if err != nil {
 		c.WithField("err", err).WithField("id", id).Error("activity.FindTokenActivities failed")
 		return nil, err
@@ -815,18 +798,25 @@ func (im *impl) GetActivities(c ctx.Ctx, id nftitem.Id, offset, limit int) (*tok
 
 	res.Items = items
 
-	cnt, err := im.activity.CountActivities(
-		c,
-		account.ActivityHistoryWithToken(id.ChainId, id.ContractAddress, id.TokenId),
-		account.ActivityHistoryWithPagination(offset, limit),
-		account.ActivityHistoryWithSource(account.SourceX),
-	)
+	shouldCount, err := account.ShouldCountActivityHistories(opts...)
 	if err != nil {
-		c.WithField("err", err).WithField("id", id).Error("activity.CountTokenActivities failed")
+		c.WithField("err", err).WithField("id", id).Error("account.ShouldCountActivityHistories failed")
 		return nil, err
 	}
 
-	res.Count = cnt
+	if shouldCount {
+		res.Count, err = im.activity.CountActivities(c, opts...)
+		if err != nil {
+			c.WithField("err", err).WithField("id", id).Error("activity.CountTokenActivities failed")
+			return nil, err
+		}
+	}
+
+	res.NextCursor, err = account.NextActivityHistoryCursor(items, opts...)
+	if err != nil {
+		c.WithField("err", err).WithField("id", id).Error("account.NextActivityHistoryCursor failed")
+		return nil, err
+	}
 
 	return &res, nil
 }
@@ -946,6 +936,15 @@ func (im *impl) RefreshIndexerState(c ctx.Ctx, id nftitem.Id) error {
 }
 
 func (im *impl) RefreshListingAndOfferState(ctx ctx.Ctx, id nftitem.Id) error {
+	item, err := im.nftitem.FindOne(ctx, id.ChainId, id.ContractAddress, id.TokenId)
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Error("failed to nftitem.FindOne")
+		return err
+	}
+
 	now := time.Now()
 	orderItems, err := im.orderItemRepo.FindAll(
 		ctx,
@@ -1049,6 +1048,27 @@ func (im *impl) RefreshListingAndOfferState(ctx ctx.Ctx, id nftitem.Id) error {
 		HasOrder:              &hasOrder,
 	}
 
+	// a token not minted yet is listed by its mint voucher instead of orders
+	if item.LazyMintVoucherId != "" {
+		redeemable, err := im.refreshVoucher(ctx, item.LazyMintVoucherId, now)
+		if err != nil {
+			return err
+		}
+		if redeemable {
+			patchable.HasActiveListings = nil
+			patchable.ListingEndsAt = nil
+			patchable.ListingOwners = nil
+			patchable.Price = nil
+			patchable.PaymentToken = nil
+			patchable.PriceInUsd = nil
+			patchable.PriceSource = nil
+			patchable.HasOrder = nil
+		} else {
+			// delisted, the creator can list it by a new voucher
+			patchable.LazyMintVoucherId = ptr.String("")
+		}
+	}
+
 	err = im.nftitem.Patch(ctx, id, patchable)
 	if err != nil {
 		ctx.WithFields(log.Fields{
@@ -1062,6 +1082,43 @@ func (im *impl) RefreshListingAndOfferState(ctx ctx.Ctx, id nftitem.Id) error {
 	return nil
 }
 
+// refreshVoucher marks the voucher expired if it's expired, returns true if the voucher is still redeemable
+func (im *impl) refreshVoucher(ctx ctx.Ctx, voucherId string, now time.Time) (bool, error) {
+	if im.voucher == nil {
+		return true, nil
+	}
+
+	vouchers, err := im.voucher.FindAll(ctx, lazymint.WithId(voucherId), lazymint.WithPagination(0, 1))
+	if err != nil {
+		ctx.WithFields(log.Fields{
+			"err":       err,
+			"voucherId": voucherId,
+		}).Error("failed to voucher.FindAll")
+		return false, err
+	}
+	if len(vouchers) == 0 {
+		return false, nil
+	}
+
+	v := vouchers[0]
+	if v.IsRedeemable(now) {
+		return true, nil
+	}
+	if v.Status != lazymint.VoucherStatusActive {
+		return false, nil
+	}
+
+	status := lazymint.VoucherStatusExpired
+	if err := im.voucher.Update(ctx, voucherId, lazymint.VoucherUpdater{Status: &status}); err != nil {
+		ctx.WithFields(log.Fields{
+			"err":       err,
+			"voucherId": voucherId,
+		}).Error("failed to voucher.Update")
+		return false, err
+	}
+	return false, nil
+}
+
 func (im *impl) GetOpenRararityScore(ctx ctx.Ctx, id nftitem.Id) (float64, error) {
 	col, err := im.collection.FindOne(ctx, collection.CollectionId{
 		ChainId: id.ChainId,
@@ -1126,3 +1183,16 @@ func (im *impl) GetOpenRararityScore(ctx ctx.Ctx, id nftitem.Id) (float64, error
 
 	return score / collectionEntropy, nil
 }
+
+// indexToken refreshes the token in search index, failure is ignored as index will be synced later
+func (im *impl) indexToken(c ctx.Ctx, id nftitem.Id) {
+	if im.searchIdx == nil {
+		return
+	}
+	if err := im.searchIdx.IndexToken(c, id); err != nil {
+		c.WithFields(log.Fields{
+			"err": err,
+			"id":  id,
+		}).Warn("searchIdx.IndexToken failed")
+	}
+}
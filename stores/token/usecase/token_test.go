@@ -10,6 +10,8 @@ import (
 	"github.com/x-xyz/goapi/base/database/mongoclient"
 	"github.com/x-xyz/goapi/base/ptr"
 	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/lazymint"
+	mLazymint "github.com/x-xyz/goapi/domain/lazymint/mocks"
 	"github.com/x-xyz/goapi/domain/nftitem"
 	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
 	"github.com/x-xyz/goapi/domain/order"
@@ -23,6 +25,7 @@ type tokenSuite struct {
 	query         query.Mongo
 	orderItemRepo *mOrder.OrderItemRepo
 	nftitemRepo   *mNftitem.Repo
+	voucherRepo   *mLazymint.VoucherRepo
 	im            *impl
 }
 
@@ -37,15 +40,18 @@ func (s *tokenSuite) SetupSuite() {
 	s.query = q
 	s.orderItemRepo = &mOrder.OrderItemRepo{}
 	s.nftitemRepo = &mNftitem.Repo{}
+	s.voucherRepo = &mLazymint.VoucherRepo{}
 	s.im = New(&TokenUseCaseCfg{
 		NftitemRepo:   s.nftitemRepo,
 		OrderItemRepo: s.orderItemRepo,
+		VoucherRepo:   s.voucherRepo,
 	}).(*impl)
 }
 
 func (s *tokenSuite) TearDownTest() {
 	s.orderItemRepo.AssertExpectations(s.T())
 	s.nftitemRepo.AssertExpectations(s.T())
+	s.voucherRepo.AssertExpectations(s.T())
 }
 
 func TestSuite(t *testing.T) {
@@ -244,6 +250,57 @@ func (s *tokenSuite) TestRefreshListingAndOfferState() {
 		}),
 	).Return(nil).Once()
 
+	s.nftitemRepo.On("FindOne", mock.Anything, mockNftitem.ChainId, mockNftitem.ContractAddress, mockNftitem.TokenId).Return(&mockNftitem, nil).Once()
+
 	err := s.im.RefreshListingAndOfferState(ctx.Background(), *mockNftitem.ToId())
 	s.Nil(err)
 }
+
+func (s *tokenSuite) TestRefreshListingAndOfferStateExpiredVoucher() {
+	item := nftitem.NftItem{
+		ChainId:           1,
+		ContractAddress:   "0x1234",
+		TokenId:           "1",
+		Creator:           "0x5566",
+		LazyMintVoucherId: "0xvoucher",
+	}
+	id := *item.ToId()
+
+	s.nftitemRepo.On("FindOne", mock.Anything, id.ChainId, id.ContractAddress, id.TokenId).Return(&item, nil).Once()
+	s.orderItemRepo.On("FindAll",
+		mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+	).Return([]*order.OrderItem{}, nil).Once()
+	s.orderItemRepo.On("FindAll",
+		mock.Anything,
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+		mock.AnythingOfType("order.OrderItemFindAllOptionsFunc"),
+	).Return([]*order.OrderItem{}, nil).Once()
+
+	s.voucherRepo.On("FindAll", mock.Anything, mock.AnythingOfType("lazymint.FindAllOptionsFunc"), mock.AnythingOfType("lazymint.FindAllOptionsFunc")).
+		Return([]lazymint.Voucher{{Id: "0xvoucher", Status: lazymint.VoucherStatusActive, Expiry: time.Now().Add(-time.Minute).Unix()}}, nil).Once()
+	expired := lazymint.VoucherStatusExpired
+	s.voucherRepo.On("Update", mock.Anything, "0xvoucher", lazymint.VoucherUpdater{Status: &expired}).Return(nil).Once()
+
+	s.nftitemRepo.On("Patch",
+		mock.Anything,
+		id,
+		mock.MatchedBy(func(input nftitem.PatchableNftItem) bool {
+			return input.LazyMintVoucherId != nil && *input.LazyMintVoucherId == "" &&
+				input.HasActiveListings != nil && !*input.HasActiveListings &&
+				input.Price != nil && *input.Price == 0
+		}),
+	).Return(nil).Once()
+
+	err := s.im.RefreshListingAndOfferState(ctx.Background(), id)
+	s.Nil(err)
+}
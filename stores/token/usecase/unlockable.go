@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/base/log"
+	"github.com/x-xyz/goapi/base/ptr"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	"github.com/x-xyz/goapi/domain/unlockable"
+	"github.com/x-xyz/goapi/service/envelope"
+)
+
+const rotateBatchSize = 100
+
+func toUnlockableId(id nftitem.Id) unlockable.UnlockableId {
+	return unlockable.UnlockableId{ChainId: id.ChainId, ContractAddress: id.ContractAddress.ToLower(), TokenId: id.TokenId}
+}
+
+// isOwner checks the current owner of erc721 or the balance of erc1155 in db
+func (im *impl) isOwner(c ctx.Ctx, id nftitem.Id, address domain.Address) (bool, error) {
+	item, err := im.nftitem.FindOne(c, id.ChainId, id.ContractAddress.ToLower(), id.TokenId)
+	if err != nil {
+		c.WithFields(log.Fields{
+			"id":  id,
+			"err": err,
+		}).Error("nftitem.FindOne failed")
+		return false, err
+	}
+
+	if item.TokenType != domain.TokenType1155 {
+		return item.Owner.Equals(address), nil
+	}
+
+	holding, err := im.erc1155Holding.FindOne(c, erc1155.HoldingId{
+		ChainId: id.ChainId,
+		Address: id.ContractAddress.ToLower(),
+		TokenId: id.TokenId,
+		Owner:   address.ToLower(),
+	})
+	if err == domain.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		c.WithFields(log.Fields{
+			"id":      id,
+			"address": address,
+			"err":     err,
+		}).Error("erc1155Holding.FindOne failed")
+		return false, err
+	}
+	return holding.Balance > 0, nil
+}
+
+func (im *impl) AddUnlockableContent(c ctx.Ctx, id nftitem.Id, creator domain.Address, content string) error {
+	if im.unlockableKeyring == nil {
+		return unlockable.ErrEncryptionDisabled
+	}
+
+	if ok, err := im.isOwner(c, id, creator); err != nil {
+		return err
+	} else if !ok {
+		return unlockable.ErrNotOwner
+	}
+
+	uid := toUnlockableId(id)
+	sealed, err := im.unlockableKeyring.Seal([]byte(content), uid.Aad())
+	if err != nil {
+		c.WithField("err", err).Error("unlockableKeyring.Seal failed")
+		return err
+	}
+
+	v := unlockable.Unlockable{
+		ChainId:         uid.ChainId,
+		ContractAddress: uid.ContractAddress,
+		TokenId:         uid.TokenId,
+		Creator:         creator.ToLower(),
+		KeyId:           sealed.KeyId,
+		WrappedKey:      sealed.WrappedKey,
+		Ciphertext:      sealed.Ciphertext,
+		CreatedAt:       time.Now(),
+	}
+
+	if err := im.unlockable.Create(c, v); err != nil {
+		c.WithField("err", err).WithField("id", id).Error("unlockable.Create failed")
+		return err
+	}
+
+	return nil
+}
+
+func (im *impl) HasUnlockableContent(c ctx.Ctx, id nftitem.Id) (bool, error) {
+	if _, err := im.unlockable.FindOne(c, toUnlockableId(id)); err == domain.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		c.WithField("err", err).Error("unlockable.FindOne failed")
+		return false, err
+	}
+	return true, nil
+}
+
+func (im *impl) RevealUnlockableContent(c ctx.Ctx, id nftitem.Id, viewer domain.Address) (string, error) {
+	uid := toUnlockableId(id)
+	res, err := im.unlockable.FindOne(c, uid)
+	if err != nil {
+		c.WithField("err", err).Error("unlockable.FindOne failed")
+		return "", err
+	}
+
+	if ok, err := im.isOwner(c, id, viewer); err != nil {
+		return "", err
+	} else if !ok {
+		return "", unlockable.ErrNotOwner
+	}
+
+	content := res.Content
+	if res.IsEncrypted() {
+		if im.unlockableKeyring == nil {
+			return "", unlockable.ErrEncryptionDisabled
+		}
+		plaintext, err := im.unlockableKeyring.Open(&envelope.Sealed{
+			KeyId:      res.KeyId,
+			WrappedKey: res.WrappedKey,
+			Ciphertext: res.Ciphertext,
+		}, uid.Aad())
+		if err != nil {
+			c.WithFields(log.Fields{
+				"id":    id,
+				"keyId": res.KeyId,
+				"err":   err,
+			}).Error("unlockableKeyring.Open failed")
+			return "", err
+		}
+		content = string(plaintext)
+	}
+
+	// the content is only returned if the reveal is logged
+	accessLog := &unlockable.AccessLog{
+		ChainId:         uid.ChainId,
+		ContractAddress: uid.ContractAddress,
+		TokenId:         uid.TokenId,
+		Viewer:          viewer.ToLower(),
+		CreatedAt:       time.Now(),
+	}
+	if err := im.unlockableLog.Insert(c, accessLog); err != nil {
+		c.WithField("err", err).Error("unlockableLog.Insert failed")
+		return "", err
+	}
+
+	return content, nil
+}
+
+func (im *impl) GetUnlockableAccessLogs(c ctx.Ctx, id nftitem.Id, requester domain.Address, optFns ...unlockable.AccessLogFindAllOptionsFunc) ([]unlockable.AccessLog, error) {
+	uid := toUnlockableId(id)
+	res, err := im.unlockable.FindOne(c, uid)
+	if err != nil {
+		c.WithField("err", err).Error("unlockable.FindOne failed")
+		return nil, err
+	}
+
+	creator := res.Creator
+	if creator == "" {
+		// added before creators were recorded
+		item, err := im.nftitem.FindOne(c, id.ChainId, id.ContractAddress.ToLower(), id.TokenId)
+		if err != nil {
+			c.WithFields(log.Fields{
+				"id":  id,
+				"err": err,
+			}).Error("nftitem.FindOne failed")
+			return nil, err
+		}
+		creator = item.Creator
+	}
+	if creator == "" || !creator.Equals(requester) {
+		return nil, unlockable.ErrNotCreator
+	}
+
+	logs, err := im.unlockableLog.FindAll(c, append(optFns, unlockable.AccessLogWithToken(uid))...)
+	if err != nil {
+		c.WithField("err", err).Error("unlockableLog.FindAll failed")
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (im *impl) RotateUnlockableKeys(c ctx.Ctx) (int, error) {
+	if im.unlockableKeyring == nil {
+		return 0, unlockable.ErrEncryptionDisabled
+	}
+
+	keyId := im.unlockableKeyring.CurrentKeyId()
+	return im.sealUnlockables(c, func() ([]unlockable.Unlockable, error) {
+		return im.unlockable.FindAllNotSealedBy(c, keyId, rotateBatchSize)
+	})
+}
+
+func (im *impl) EncryptPlaintextUnlockables(c ctx.Ctx) (int, error) {
+	if im.unlockableKeyring == nil {
+		return 0, unlockable.ErrEncryptionDisabled
+	}
+
+	return im.sealUnlockables(c, func() ([]unlockable.Unlockable, error) {
+		return im.unlockable.FindAllPlaintext(c, rotateBatchSize)
+	})
+}
+
+// sealUnlockables seals batches of contents by the current master key until no content is found
+func (im *impl) sealUnlockables(c ctx.Ctx, find func() ([]unlockable.Unlockable, error)) (int, error) {
+	count := 0
+	for {
+		contents, err := find()
+		if err != nil {
+			c.WithField("err", err).Error("find unlockable contents failed")
+			return count, err
+		}
+		if len(contents) == 0 {
+			return count, nil
+		}
+
+		for _, content := range contents {
+			uid := content.ToId()
+
+			var sealed *envelope.Sealed
+			if content.IsEncrypted() {
+				sealed, err = im.unlockableKeyring.Rewrap(&envelope.Sealed{
+					KeyId:      content.KeyId,
+					WrappedKey: content.WrappedKey,
+					Ciphertext: content.Ciphertext,
+				}, uid.Aad())
+			} else {
+				// plaintext added before encryption
+				sealed, err = im.unlockableKeyring.Seal([]byte(content.Content), uid.Aad())
+			}
+			if err != nil {
+				// a content failing once fails every batch, stop instead of looping on it
+				c.WithFields(log.Fields{
+					"id":    uid,
+					"keyId": content.KeyId,
+					"err":   err,
+				}).Error("seal unlockable content failed")
+				return count, err
+			}
+
+			if err := im.unlockable.Update(c, uid, unlockable.Updater{
+				Content:    ptr.String(""),
+				KeyId:      &sealed.KeyId,
+				WrappedKey: sealed.WrappedKey,
+				Ciphertext: sealed.Ciphertext,
+			}); err != nil {
+				c.WithFields(log.Fields{
+					"id":  uid,
+					"err": err,
+				}).Error("unlockable.Update failed")
+				return count, err
+			}
+			count++
+		}
+	}
+}
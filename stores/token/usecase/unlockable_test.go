@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/x-xyz/goapi/base/ctx"
+	"github.com/x-xyz/goapi/domain"
+	"github.com/x-xyz/goapi/domain/erc1155"
+	mErc1155 "github.com/x-xyz/goapi/domain/erc1155/mocks"
+	"github.com/x-xyz/goapi/domain/nftitem"
+	mNftitem "github.com/x-xyz/goapi/domain/nftitem/mocks"
+	"github.com/x-xyz/goapi/domain/unlockable"
+	mUnlockable "github.com/x-xyz/goapi/domain/unlockable/mocks"
+	"github.com/x-xyz/goapi/service/envelope"
+)
+
+type unlockableSuite struct {
+	suite.Suite
+
+	ctx            ctx.Ctx
+	nftitemRepo    *mNftitem.Repo
+	holdingRepo    *mErc1155.HoldingRepo
+	unlockableRepo *mUnlockable.Repo
+	accessLogRepo  *mUnlockable.AccessLogRepo
+	im             *impl
+}
+
+func TestUnlockableSuite(t *testing.T) {
+	suite.Run(t, new(unlockableSuite))
+}
+
+var (
+	erc721Id  = nftitem.Id{ChainId: 1, ContractAddress: "0xAAA", TokenId: "1"}
+	erc1155Id = nftitem.Id{ChainId: 1, ContractAddress: "0xbbb", TokenId: "2"}
+)
+
+func keyring(current string, ids ...string) *envelope.Keyring {
+	keys := map[string][]byte{}
+	for _, id := range ids {
+		// the same id is always the same key
+		keys[id] = bytes.Repeat([]byte(id[len(id)-1:]), 32)
+	}
+	k, err := envelope.New(keys, current)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// sealed returns the content of the token sealed by the current key of k
+func sealed(id nftitem.Id, content string, k *envelope.Keyring) unlockable.Unlockable {
+	uid := toUnlockableId(id)
+	res, err := k.Seal([]byte(content), uid.Aad())
+	if err != nil {
+		panic(err)
+	}
+	return unlockable.Unlockable{
+		ChainId:         uid.ChainId,
+		ContractAddress: uid.ContractAddress,
+		TokenId:         uid.TokenId,
+		KeyId:           res.KeyId,
+		WrappedKey:      res.WrappedKey,
+		Ciphertext:      res.Ciphertext,
+	}
+}
+
+// update applies updater to v as the repo does
+func update(v unlockable.Unlockable, updater unlockable.Updater) unlockable.Unlockable {
+	v.Content = *updater.Content
+	v.KeyId = *updater.KeyId
+	v.WrappedKey = updater.WrappedKey
+	v.Ciphertext = updater.Ciphertext
+	return v
+}
+
+func (s *unlockableSuite) SetupTest() {
+	s.ctx = ctx.Background()
+	s.nftitemRepo = &mNftitem.Repo{}
+	s.holdingRepo = &mErc1155.HoldingRepo{}
+	s.unlockableRepo = &mUnlockable.Repo{}
+	s.accessLogRepo = &mUnlockable.AccessLogRepo{}
+	s.im = New(&TokenUseCaseCfg{
+		NftitemRepo:        s.nftitemRepo,
+		Erc1155HoldingRepo: s.holdingRepo,
+		UnlockableRepo:     s.unlockableRepo,
+		UnlockableKeyring:  keyring("k1", "k1"),
+		UnlockableLogRepo:  s.accessLogRepo,
+	}).(*impl)
+}
+
+func (s *unlockableSuite) TearDownTest() {
+	s.nftitemRepo.AssertExpectations(s.T())
+	s.holdingRepo.AssertExpectations(s.T())
+	s.unlockableRepo.AssertExpectations(s.T())
+	s.accessLogRepo.AssertExpectations(s.T())
+}
+
+func (s *unlockableSuite) mockErc721() {
+	s.nftitemRepo.On("FindOne", mock.Anything, erc721Id.ChainId, domain.Address("0xaaa"), erc721Id.TokenId).
+		Return(&nftitem.NftItem{TokenType: domain.TokenType721, Owner: "0xowner", Creator: "0xminter"}, nil)
+}
+
+func (s *unlockableSuite) mockErc1155(owner domain.Address, balance int64) {
+	s.nftitemRepo.On("FindOne", mock.Anything, erc1155Id.ChainId, erc1155Id.ContractAddress, erc1155Id.TokenId).
+		Return(&nftitem.NftItem{TokenType: domain.TokenType1155}, nil)
+	holder := erc1155.HoldingId{ChainId: 1, Address: "0xbbb", TokenId: "2", Owner: owner}
+	if balance < 0 {
+		s.holdingRepo.On("FindOne", mock.Anything, holder).Return(nil, domain.ErrNotFound).Once()
+		return
+	}
+	s.holdingRepo.On("FindOne", mock.Anything, holder).Return(&erc1155.Holding{Balance: balance}, nil).Once()
+}
+
+func (s *unlockableSuite) mockFindOne(id nftitem.Id, v unlockable.Unlockable) *mock.Call {
+	return s.unlockableRepo.On("FindOne", mock.Anything, toUnlockableId(id)).Return(&v, nil).Once()
+}
+
+func (s *unlockableSuite) mockAccessLog(viewer domain.Address) {
+	s.accessLogRepo.On("Insert", mock.Anything, mock.MatchedBy(func(l *unlockable.AccessLog) bool {
+		return l.Viewer == viewer
+	})).Return(nil).Once()
+}
+
+// mockUpdates mocks n updates of contents, the updated contents are returned
+func (s *unlockableSuite) mockUpdates(n int) map[unlockable.UnlockableId]unlockable.Updater {
+	updated := map[unlockable.UnlockableId]unlockable.Updater{}
+	s.unlockableRepo.On("Update", mock.Anything, mock.AnythingOfType("unlockable.UnlockableId"), mock.AnythingOfType("unlockable.Updater")).
+		Run(func(args mock.Arguments) {
+			updated[args.Get(1).(unlockable.UnlockableId)] = args.Get(2).(unlockable.Updater)
+		}).
+		Return(nil).Times(n)
+	return updated
+}
+
+func (s *unlockableSuite) TestErc721() {
+	s.mockErc721()
+	s.Equal(unlockable.ErrNotOwner, s.im.AddUnlockableContent(s.ctx, erc721Id, "0xother", "secret"))
+
+	var stored unlockable.Unlockable
+	s.unlockableRepo.On("Create", mock.Anything, mock.AnythingOfType("unlockable.Unlockable")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(unlockable.Unlockable) }).
+		Return(nil).Once()
+	s.Nil(s.im.AddUnlockableContent(s.ctx, erc721Id, "0xOWNER", "secret"))
+
+	s.Equal("", stored.Content)
+	s.Equal("k1", stored.KeyId)
+	s.Equal(domain.Address("0xowner"), stored.Creator)
+	s.False(bytes.Contains(stored.Ciphertext, []byte("secret")))
+
+	s.mockFindOne(erc721Id, stored).Times(5)
+	has, err := s.im.HasUnlockableContent(s.ctx, erc721Id)
+	s.Nil(err)
+	s.True(has)
+
+	// nothing is logged if the content isn't revealed
+	_, err = s.im.RevealUnlockableContent(s.ctx, erc721Id, "0xother")
+	s.Equal(unlockable.ErrNotOwner, err)
+
+	s.mockAccessLog("0xowner")
+	content, err := s.im.RevealUnlockableContent(s.ctx, erc721Id, "0xowner")
+	s.Nil(err)
+	s.Equal("secret", content)
+
+	s.accessLogRepo.On("FindAll", mock.Anything, mock.AnythingOfType("unlockable.AccessLogFindAllOptionsFunc")).
+		Return([]unlockable.AccessLog{{Viewer: "0xowner"}}, nil).Once()
+	logs, err := s.im.GetUnlockableAccessLogs(s.ctx, erc721Id, "0xowner")
+	s.Nil(err)
+	s.Len(logs, 1)
+	_, err = s.im.GetUnlockableAccessLogs(s.ctx, erc721Id, "0xother")
+	s.Equal(unlockable.ErrNotCreator, err)
+}
+
+func (s *unlockableSuite) TestErc1155() {
+	stored := sealed(erc1155Id, "secret", s.im.unlockableKeyring)
+	s.mockFindOne(erc1155Id, stored).Times(3)
+
+	s.mockErc1155("0xholder", 3)
+	s.mockAccessLog("0xholder")
+	content, err := s.im.RevealUnlockableContent(s.ctx, erc1155Id, "0xholder")
+	s.Nil(err)
+	s.Equal("secret", content)
+
+	s.mockErc1155("0xsold", 0)
+	_, err = s.im.RevealUnlockableContent(s.ctx, erc1155Id, "0xsold")
+	s.Equal(unlockable.ErrNotOwner, err)
+
+	s.mockErc1155("0xstranger", -1)
+	_, err = s.im.RevealUnlockableContent(s.ctx, erc1155Id, "0xstranger")
+	s.Equal(unlockable.ErrNotOwner, err)
+}
+
+func (s *unlockableSuite) TestRotate() {
+	current := sealed(erc721Id, "sealed", s.im.unlockableKeyring)
+	// added before encryption
+	legacy := unlockable.Unlockable{ChainId: 1, ContractAddress: "0xbbb", TokenId: "2", Content: "plain"}
+
+	s.im.unlockableKeyring = keyring("k2", "k1", "k2")
+	s.unlockableRepo.On("FindAllNotSealedBy", mock.Anything, "k2", rotateBatchSize).
+		Return([]unlockable.Unlockable{current, legacy}, nil).Once()
+	s.unlockableRepo.On("FindAllNotSealedBy", mock.Anything, "k2", rotateBatchSize).
+		Return(nil, nil).Twice()
+	updated := s.mockUpdates(2)
+
+	count, err := s.im.RotateUnlockableKeys(s.ctx)
+	s.Nil(err)
+	s.Equal(2, count)
+	s.Len(updated, 2)
+	for _, updater := range updated {
+		s.Equal("k2", *updater.KeyId)
+		s.Equal("", *updater.Content)
+	}
+
+	// the retired key is not needed anymore
+	s.im.unlockableKeyring = keyring("k2", "k2")
+	s.mockFindOne(erc721Id, update(current, updated[current.ToId()]))
+	s.mockErc721()
+	s.mockAccessLog("0xowner")
+	content, err := s.im.RevealUnlockableContent(s.ctx, erc721Id, "0xowner")
+	s.Nil(err)
+	s.Equal("sealed", content)
+
+	count, err = s.im.RotateUnlockableKeys(s.ctx)
+	s.Nil(err)
+	s.Equal(0, count)
+}
+
+func (s *unlockableSuite) TestLegacy() {
+	// added before encryption and creators were recorded
+	legacy := unlockable.Unlockable{ChainId: 1, ContractAddress: "0xaaa", TokenId: "1", Content: "plain"}
+	s.mockFindOne(erc721Id, legacy).Twice()
+	s.mockErc721()
+	s.accessLogRepo.On("FindAll", mock.Anything, mock.AnythingOfType("unlockable.AccessLogFindAllOptionsFunc")).
+		Return(nil, nil).Once()
+
+	logs, err := s.im.GetUnlockableAccessLogs(s.ctx, erc721Id, "0xminter")
+	s.Nil(err)
+	s.Empty(logs)
+	_, err = s.im.GetUnlockableAccessLogs(s.ctx, erc721Id, "0xowner")
+	s.Equal(unlockable.ErrNotCreator, err)
+
+	s.unlockableRepo.On("FindAllPlaintext", mock.Anything, rotateBatchSize).
+		Return([]unlockable.Unlockable{legacy}, nil).Once()
+	s.unlockableRepo.On("FindAllPlaintext", mock.Anything, rotateBatchSize).
+		Return(nil, nil).Twice()
+	updated := s.mockUpdates(1)
+
+	count, err := s.im.EncryptPlaintextUnlockables(s.ctx)
+	s.Nil(err)
+	s.Equal(1, count)
+	s.Equal("k1", *updated[legacy.ToId()].KeyId)
+	s.Equal("", *updated[legacy.ToId()].Content)
+
+	s.mockFindOne(erc721Id, update(legacy, updated[legacy.ToId()]))
+	s.mockAccessLog("0xowner")
+	content, err := s.im.RevealUnlockableContent(s.ctx, erc721Id, "0xowner")
+	s.Nil(err)
+	s.Equal("plain", content)
+
+	count, err = s.im.EncryptPlaintextUnlockables(s.ctx)
+	s.Nil(err)
+	s.Equal(0, count)
+}
+
+func (s *unlockableSuite) TestEncryptionDisabled() {
+	s.mockFindOne(erc721Id, sealed(erc721Id, "sealed", s.im.unlockableKeyring))
+	s.mockErc721()
+	legacy := unlockable.Unlockable{ChainId: 1, ContractAddress: "0xbbb", TokenId: "2", Content: "plain"}
+	s.mockFindOne(erc1155Id, legacy)
+	s.mockErc1155("0xholder", 1)
+	s.mockAccessLog("0xholder")
+
+	s.im.unlockableKeyring = nil
+	s.Equal(unlockable.ErrEncryptionDisabled, s.im.AddUnlockableContent(s.ctx, erc721Id, "0xowner", "secret"))
+	_, err := s.im.RevealUnlockableContent(s.ctx, erc721Id, "0xowner")
+	s.Equal(unlockable.ErrEncryptionDisabled, err)
+	_, err = s.im.RotateUnlockableKeys(s.ctx)
+	s.Equal(unlockable.ErrEncryptionDisabled, err)
+
+	// plaintext contents are still revealable
+	content, err := s.im.RevealUnlockableContent(s.ctx, erc1155Id, "0xholder")
+	s.Nil(err)
+	s.Equal("plain", content)
+}